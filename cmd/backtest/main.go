@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"contract_playground/internal/trading"
+)
+
+// paramFlags collects repeated -param key=value strategy parameters
+type paramFlags map[string]interface{}
+
+func (p paramFlags) String() string {
+	return fmt.Sprint(map[string]interface{}(p))
+}
+
+func (p paramFlags) Set(value string) error {
+	key, raw, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	p[key] = parseParam(raw)
+	return nil
+}
+
+// gridFlags collects repeated -grid key=v1,v2,... candidate values
+type gridFlags map[string][]interface{}
+
+func (g gridFlags) String() string {
+	return fmt.Sprint(map[string][]interface{}(g))
+}
+
+func (g gridFlags) Set(value string) error {
+	key, raw, ok := strings.Cut(value, "=")
+	if !ok || key == "" || raw == "" {
+		return fmt.Errorf("expected key=v1,v2,..., got %q", value)
+	}
+	for _, candidate := range strings.Split(raw, ",") {
+		g[key] = append(g[key], parseParam(candidate))
+	}
+	return nil
+}
+
+// dataFlags collects repeated -data [SYMBOL=]file kline files
+type dataFlags []string
+
+func (d *dataFlags) String() string {
+	return strings.Join(*d, ",")
+}
+
+func (d *dataFlags) Set(value string) error {
+	*d = append(*d, value)
+	return nil
+}
+
+func parseParam(raw string) interface{} {
+	if number, err := strconv.ParseFloat(raw, 64); err == nil {
+		return number
+	}
+	if enabled, err := strconv.ParseBool(raw); err == nil {
+		return enabled
+	}
+	return raw
+}
+
+func readCandles(path string) (*trading.CandleSeries, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open kline data: %w", err)
+	}
+	defer file.Close()
+
+	series, err := trading.ReadCandlesCSV(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return series, nil
+}
+
+// Backtests a strategy over klines in Binance's CSV layout (e.g. the monthly 1m files from
+// data.binance.vision). The vectorized mode precomputes indicators over the whole file and
+// is available for the simple_moving_average and rsi strategies.
+//
+// Several -data files and -grid parameter candidates run every (symbol, parameter set)
+// combination across a worker pool and print the aggregated report instead.
+func main() {
+	params := paramFlags{}
+	grid := gridFlags{}
+	var data dataFlags
+	symbol := flag.String("symbol", "BTCUSDT", "symbol the data belongs to")
+	strategy := flag.String("strategy", "simple_moving_average", "strategy type, as in trading.strategy.type")
+	mode := flag.String("mode", trading.BacktestModeVectorized, "vectorized or candle (per-candle strategy calls)")
+	interval := flag.Duration("interval", time.Minute, "candle interval")
+	balance := flag.Float64("balance", 10000, "initial balance in USDT")
+	positionPercent := flag.Float64("position-percent", 10, "percent of the balance put into each entry")
+	fee := flag.Float64("fee", 0.0004, "fee per fill as a fraction of the notional")
+	atrPeriod := flag.Int("atr-period", 14, "ATR period given to strategies in candle mode")
+	slippage := flag.Float64("slippage-bps", 0, "random adverse slippage per fill, up to this many basis points")
+	seed := flag.Int64("seed", 1, "seed for the slippage draws; equal seeds reproduce a run")
+	workers := flag.Int("workers", 0, "concurrent backtests when running several (default: number of CPUs)")
+	showTrades := flag.Bool("trades", false, "include every trade in the output")
+	flag.Var(params, "param", "strategy parameter key=value (repeatable)")
+	flag.Var(grid, "grid", "strategy parameter candidates key=v1,v2,... (repeatable)")
+	flag.Var(&data, "data", "kline CSV file, as [SYMBOL=]file (repeatable; the symbol defaults to -symbol)")
+	flag.Parse()
+
+	if len(data) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	series := make(map[string]*trading.CandleSeries, len(data))
+	for _, value := range data {
+		name, path, ok := strings.Cut(value, "=")
+		if !ok {
+			name, path = *symbol, value
+		}
+		if _, exists := series[name]; exists {
+			log.Fatalf("Kline data for %s given twice; use SYMBOL=file", name)
+		}
+		candles, err := readCandles(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		series[name] = candles
+	}
+
+	cfg := trading.BacktestConfig{
+		Symbol:          *symbol,
+		StrategyType:    *strategy,
+		Parameters:      params,
+		Mode:            *mode,
+		Interval:        *interval,
+		InitialBalance:  *balance,
+		PositionPercent: *positionPercent,
+		FeeRate:         *fee,
+		SlippageBps:     *slippage,
+		Seed:            *seed,
+		ATRPeriod:       *atrPeriod,
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	if len(series) > 1 || len(grid) > 0 {
+		runner := &trading.BacktestRunner{
+			Workers: *workers,
+			Progress: func(completed, total int, job *trading.BacktestJobResult) {
+				status := "ok"
+				if job.Error != "" {
+					status = job.Error
+				}
+				fmt.Fprintf(os.Stderr, "[%d/%d] %s set %d: %s\n", completed, total, job.Symbol, job.ParameterSet, status)
+			},
+		}
+		report, err := runner.Run(context.Background(), cfg, series, trading.ParameterGrid(params, grid))
+		if err != nil {
+			log.Fatalf("Backtest failed: %v", err)
+		}
+		if !*showTrades {
+			for _, job := range report.Jobs {
+				if job.Result != nil {
+					job.Result.Trades = nil
+				}
+			}
+		}
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Failed to write report: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "%d backtests in %s\n", len(report.Jobs), report.Elapsed.Round(time.Millisecond))
+		return
+	}
+
+	for name, candles := range series {
+		cfg.Symbol = name
+		result, err := trading.Backtest(context.Background(), cfg, candles)
+		if err != nil {
+			log.Fatalf("Backtest failed: %v", err)
+		}
+
+		trades := len(result.Trades)
+		if !*showTrades {
+			result.Trades = nil
+		}
+		if err := encoder.Encode(result); err != nil {
+			log.Fatalf("Failed to write result: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "%d candles, %d trades in %s (%.0f candles/s)\n",
+			result.Candles, trades, result.Elapsed.Round(time.Millisecond), result.CandlesPerSecond)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/migrations"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Applies and rolls back the versioned schema migrations of migrations/ against the
+// database of the loaded configuration, recording them in schema_migrations.
+//
+//	migrate status
+//	migrate up [-to N]
+//	migrate down [-steps N]
+//	migrate baseline -version N   (existing database created before migrations were tracked)
+//	migrate force -version N      (after fixing a failed migration by hand)
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	profile := fs.String("profile", os.Getenv(config.ProfileEnv), "config profile merged over config/config.yaml, e.g. dev, testnet or prod")
+	var target, steps, version *int
+	switch os.Args[1] {
+	case "status":
+	case "up":
+		target = fs.Int("to", 0, "apply up to and including this version (0 for all)")
+	case "down":
+		steps = fs.Int("steps", 1, "number of migrations to roll back")
+	case "baseline", "force":
+		version = fs.Int("version", 0, "last migration version already in the schema")
+	default:
+		usage()
+	}
+	fs.Parse(os.Args[2:])
+	if version != nil && *version <= 0 {
+		log.Fatal("-version is required")
+	}
+
+	cfg, err := config.LoadProfile(*profile)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	db, err := database.InitMySQL(cfg.Database.MySQL)
+	if err != nil {
+		log.Fatalf("Failed to connect to MySQL: %v", err)
+	}
+	list, err := database.LoadMigrations(migrations.Files)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	migrator := database.NewMigrator(db, list, logger)
+
+	switch os.Args[1] {
+	case "status":
+		err = printStatus(migrator)
+	case "up":
+		var count int
+		count, err = migrator.Up(*target)
+		fmt.Printf("Applied %d migrations\n", count)
+	case "down":
+		var count int
+		count, err = migrator.Down(*steps)
+		fmt.Printf("Rolled back %d migrations\n", count)
+	case "baseline":
+		err = migrator.Baseline(*version)
+	case "force":
+		err = migrator.Force(*version)
+	}
+	if err != nil {
+		log.Fatalf("Migration %s failed: %v", os.Args[1], err)
+	}
+}
+
+func printStatus(migrator *database.Migrator) error {
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT\tROLLBACK")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Dirty {
+			state = "DIRTY"
+		} else if status.Applied {
+			state = "applied"
+		}
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		rollback := "no"
+		if status.Reversible {
+			rollback = "yes"
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt, rollback)
+	}
+	return w.Flush()
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate <command> [flags]")
+	fmt.Fprintln(os.Stderr, strings.Join([]string{
+		"  status                  list migrations and whether they are applied",
+		"  up [-to N]              apply pending migrations",
+		"  down [-steps N]         roll back the latest migrations",
+		"  baseline -version N     record migrations up to N as applied on an existing schema",
+		"  force -version N        set the applied version after fixing a failed migration",
+	}, "\n"))
+	os.Exit(2)
+}
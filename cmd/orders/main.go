@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Controls the trader through its HTTP API: manual orders, position closes, engine status
+// and pausing symbols.
+// The bearer token is read from API_AUTH_TOKEN.
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var (
+		method string
+		path   string
+		body   interface{}
+	)
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	apiURL := fs.String("api", "http://127.0.0.1:8080", "trader API base URL")
+
+	switch os.Args[1] {
+	case "place":
+		symbol := fs.String("symbol", "", "symbol, e.g. BTCUSDT")
+		side := fs.String("side", "BUY", "BUY or SELL (reduce-only)")
+		orderType := fs.String("type", "MARKET", "MARKET or LIMIT")
+		quantity := fs.Float64("qty", 0, "order quantity")
+		price := fs.Float64("price", 0, "limit price")
+		fs.Parse(os.Args[2:])
+		method, path = http.MethodPost, "/api/v1/orders"
+		body = map[string]interface{}{
+			"symbol":   *symbol,
+			"side":     *side,
+			"type":     *orderType,
+			"quantity": *quantity,
+			"price":    *price,
+		}
+	case "modify":
+		id := fs.Uint("id", 0, "manual order id")
+		quantity := fs.Float64("qty", 0, "new quantity")
+		price := fs.Float64("price", 0, "new limit price")
+		fs.Parse(os.Args[2:])
+		method, path = http.MethodPut, fmt.Sprintf("/api/v1/orders/%d", *id)
+		body = map[string]float64{"quantity": *quantity, "price": *price}
+	case "cancel":
+		id := fs.Uint("id", 0, "manual order id")
+		fs.Parse(os.Args[2:])
+		method, path = http.MethodDelete, fmt.Sprintf("/api/v1/orders/%d", *id)
+	case "list":
+		symbol := fs.String("symbol", "", "only orders of this symbol")
+		fs.Parse(os.Args[2:])
+		method, path = http.MethodGet, "/api/v1/orders?symbol="+url.QueryEscape(*symbol)
+	case "positions":
+		fs.Parse(os.Args[2:])
+		method, path = http.MethodGet, "/api/v1/positions"
+	case "close":
+		id := fs.Uint("id", 0, "position id")
+		orderType := fs.String("type", "MARKET", "MARKET or LIMIT")
+		price := fs.Float64("price", 0, "limit price")
+		fs.Parse(os.Args[2:])
+		method, path = http.MethodPost, fmt.Sprintf("/api/v1/positions/%d/close", *id)
+		body = map[string]interface{}{"type": *orderType, "price": *price}
+	case "close-symbol":
+		symbol := fs.String("symbol", "", "symbol whose positions to close at market")
+		fs.Parse(os.Args[2:])
+		method, path = http.MethodPost, "/api/v1/positions/close?symbol="+url.QueryEscape(*symbol)
+	case "flatten":
+		fs.Parse(os.Args[2:])
+		method, path = http.MethodPost, "/api/v1/flatten"
+	case "status":
+		fs.Parse(os.Args[2:])
+		method, path = http.MethodGet, "/api/v1/status"
+	case "shadow":
+		fs.Parse(os.Args[2:])
+		method, path = http.MethodGet, "/api/v1/shadow"
+	case "pause":
+		symbol := fs.String("symbol", "", "symbol whose new entries to pause")
+		reason := fs.String("reason", "", "why the symbol is paused")
+		fs.Parse(os.Args[2:])
+		method, path = http.MethodPost, "/api/v1/symbols/"+url.PathEscape(*symbol)+"/pause"
+		body = map[string]string{"reason": *reason}
+	case "resume":
+		symbol := fs.String("symbol", "", "symbol to resume")
+		fs.Parse(os.Args[2:])
+		method, path = http.MethodPost, "/api/v1/symbols/"+url.PathEscape(*symbol)+"/resume"
+	case "resume-strategy":
+		fs.Parse(os.Args[2:])
+		method, path = http.MethodPost, "/api/v1/strategy/resume"
+	default:
+		usage()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			log.Fatalf("Failed to encode request: %v", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(*apiURL, "/")+path, reader)
+	if err != nil {
+		log.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := os.Getenv("API_AUTH_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var out bytes.Buffer
+	data, _ := io.ReadAll(resp.Body)
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		out.Write(data)
+	}
+	fmt.Println(out.String())
+
+	if resp.StatusCode >= 300 {
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: orders place|modify|cancel|list|positions|close|close-symbol|flatten|status|shadow|pause|resume|resume-strategy [flags]")
+	os.Exit(2)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"contract_playground/internal/secrets"
+)
+
+// Encrypts BINANCE_API_KEY/BINANCE_SECRET_KEY from the environment into a secrets file
+// for exchange.credentials_source: file. Keys are read from the environment so they do
+// not end up in shell history.
+func main() {
+	out := flag.String("out", "config/secrets.enc", "path of the encrypted secrets file to write")
+	flag.Parse()
+
+	creds, err := secrets.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read credentials: %v", err)
+	}
+
+	passphrase, err := secrets.Passphrase("New passphrase: ")
+	if err != nil {
+		log.Fatalf("Failed to read passphrase: %v", err)
+	}
+	if os.Getenv(secrets.EnvPassphrase) == "" {
+		confirm, err := secrets.Passphrase("Repeat passphrase: ")
+		if err != nil {
+			log.Fatalf("Failed to read passphrase: %v", err)
+		}
+		if string(confirm) != string(passphrase) {
+			log.Fatal("Passphrases do not match")
+		}
+	}
+
+	plaintext, err := json.Marshal(creds)
+	if err != nil {
+		log.Fatalf("Failed to encode credentials: %v", err)
+	}
+	data, err := secrets.Encrypt(plaintext, passphrase)
+	if err != nil {
+		log.Fatalf("Failed to encrypt credentials: %v", err)
+	}
+
+	if err := os.WriteFile(*out, data, 0600); err != nil {
+		log.Fatalf("Failed to write secrets file: %v", err)
+	}
+	fmt.Printf("Encrypted credentials written to %s\n", *out)
+}
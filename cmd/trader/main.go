@@ -0,0 +1,604 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"contract_playground/internal/api"
+	"contract_playground/internal/auditlog"
+	"contract_playground/internal/commentary"
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/feeds"
+	"contract_playground/internal/notify"
+	"contract_playground/internal/snapshot"
+	"contract_playground/internal/spreads"
+	"contract_playground/internal/tax"
+	"contract_playground/internal/trading"
+	"contract_playground/migrations"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	profile := flag.String("profile", os.Getenv(config.ProfileEnv), "config profile merged over config/config.yaml, e.g. dev, testnet or prod")
+	dumpConfig := flag.Bool("dump-config", false, "print the effective configuration with secrets redacted and exit")
+	plan := flag.Bool("plan", false, "evaluate strategies once on current data, print the orders that would be placed and exit")
+	taxYear := flag.Int("tax-year", 0, "write a Form 8949 style CSV of the given calendar year's disposals and income and exit")
+	taxOut := flag.String("tax-out", "", "file for the tax CSV (default stdout)")
+	record := flag.String("record", "", "record every exchange response and stream event of the session to this file")
+	replay := flag.String("replay", "", "run the engine against a session recorded with -record instead of the exchange")
+	replaySpeed := flag.Float64("replay-speed", 1, "replay stream events this many times faster than recorded (0 = no delays)")
+	snapshotExport := flag.String("snapshot-export", "", "write open positions, orders, strategy state and risk counters to this snapshot file and exit")
+	snapshotRestore := flag.String("snapshot-restore", "", "load a snapshot written with -snapshot-export into the database and exit")
+	auditVerify := flag.Bool("audit-verify", false, "check the hash chain and encryption of the order audit log and exit")
+	flag.Parse()
+
+	cfg, err := config.LoadProfile(*profile)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if *dumpConfig {
+		if err := config.Dump(os.Stdout); err != nil {
+			log.Fatalf("Failed to dump configuration: %v", err)
+		}
+		return
+	}
+
+	logger, err := newLogger(cfg.Logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	if *plan {
+		if err := runPlan(cfg, logger); err != nil {
+			logger.Fatalf("Plan failed: %v", err)
+		}
+		return
+	}
+
+	if *taxYear > 0 {
+		if err := runTaxExport(cfg, *taxYear, *taxOut); err != nil {
+			logger.Fatalf("Tax export failed: %v", err)
+		}
+		return
+	}
+
+	if *auditVerify {
+		if err := runAuditVerify(cfg, logger); err != nil {
+			logger.Fatalf("Order audit log verification failed: %v", err)
+		}
+		return
+	}
+
+	if *snapshotExport != "" {
+		if err := runSnapshotExport(cfg, *snapshotExport, logger); err != nil {
+			logger.Fatalf("Snapshot export failed: %v", err)
+		}
+		return
+	}
+
+	if *snapshotRestore != "" {
+		if err := runSnapshotRestore(cfg, *snapshotRestore, logger); err != nil {
+			logger.Fatalf("Snapshot restore failed: %v", err)
+		}
+		return
+	}
+
+	if cfg.Profile != "" {
+		logger.Infof("Using config profile %s", cfg.Profile)
+	}
+	if *record != "" {
+		cfg.Exchange.RecordFile = *record
+	}
+
+	if len(cfg.Instances) > 0 {
+		if *replay != "" || *record != "" {
+			logger.Fatal("-record and -replay are not supported with instances; set exchange.record_file of an instance instead")
+		}
+		if err := runInstances(cfg, logger); err != nil {
+			logger.Fatalf("Trading bots exited with error: %v", err)
+		}
+		return
+	}
+
+	if err := run(cfg, logger, *replay, *replaySpeed); err != nil {
+		logger.Fatalf("Trading bot exited with error: %v", err)
+	}
+}
+
+// run starts the trading engine and blocks until a shutdown signal is received or, when
+// replaying a recorded session, until the recording has been played back
+func run(cfg *config.Config, logger *logrus.Logger, replayFile string, replaySpeed float64) error {
+	b, err := newBot(cfg, logger, replayFile, replaySpeed)
+	if err != nil {
+		return err
+	}
+	defer b.close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := b.start(ctx); err != nil {
+		return err
+	}
+
+	if cfg.API.Enabled {
+		if err := api.NewServer(cfg.API, b.engine, b.spreads, b.database, logger).Start(ctx); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		logger.Info("Shutdown signal received")
+	case <-b.replayDone:
+		logger.Info("Replay complete, shutting down")
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	return b.engine.Stop(shutdownCtx)
+}
+
+// bot is a trading engine with the connections and services it runs with
+type bot struct {
+	engine     *trading.Engine
+	spreads    *spreads.Monitor // nil when the spread monitor is disabled
+	database   *database.Monitor
+	relay      *notify.Relay   // nil without the notification outbox
+	replayDone <-chan struct{} // nil unless replaying a recorded session
+	close      func()
+}
+
+// newBot connects the databases and the exchange of cfg and builds its engine
+func newBot(cfg *config.Config, logger *logrus.Logger, replayFile string, replaySpeed float64) (_ *bot, err error) {
+	db, err := database.InitMySQL(cfg.Database.MySQL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.ApplyMigrations(db, cfg.Database.Migrations, migrations.Files, logger); err != nil {
+		return nil, err
+	}
+	dbMonitor, err := database.NewMonitor(db, cfg.Database.Monitoring, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	rdb, err := database.InitRedis(cfg.Database.Redis)
+	if err != nil {
+		return nil, err
+	}
+	b := &bot{database: dbMonitor, close: func() { rdb.Close() }}
+	defer func() {
+		if err != nil {
+			b.close()
+		}
+	}()
+
+	var exchangeClient exchange.Client
+	if replayFile != "" {
+		replay, err := exchange.NewReplayClient(replayFile, replaySpeed, logger)
+		if err != nil {
+			return nil, err
+		}
+		exchangeClient = exchange.NewGuardedClient(replay, cfg.Exchange, logger)
+		b.replayDone = replay.Done()
+	} else if exchangeClient, err = exchange.NewClient(cfg.Exchange, logger); err != nil {
+		return nil, err
+	}
+
+	var spotClient exchange.SpotClient
+	if (cfg.Trading.FundingArbitrage.Enabled || cfg.Exchange.SpotEnabled) && replayFile == "" {
+		if spotClient, err = exchange.NewBinanceSpotClient(cfg.Exchange, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	var commentator *commentary.Generator
+	if cfg.Commentary.Enabled {
+		commentator = commentary.NewGenerator(cfg.Commentary, logger)
+	}
+
+	var feedService *feeds.Service
+	if cfg.Feeds.Enabled {
+		feedService = feeds.NewService(cfg.Feeds, cfg.Trading.Symbols, database.NewMySQLRepository(db), logger)
+	}
+
+	var optionsVenue exchange.OptionsVenue
+	if cfg.Trading.Options.Enabled {
+		if optionsVenue, err = exchange.NewOptionsVenue(cfg.Trading.Options.Venue, time.Duration(cfg.Trading.Options.TimeoutSeconds)*time.Second); err != nil {
+			return nil, err
+		}
+	}
+
+	var orderAudit *auditlog.Log
+	if cfg.AuditLog.Enabled {
+		if orderAudit, err = auditlog.New(database.NewMySQLRepository(db), cfg.AuditLog.EncryptionKey); err != nil {
+			return nil, err
+		}
+	}
+
+	// With the outbox, notifications are stored and delivered with retries by the relay
+	var notifier notify.Notifier
+	if cfg.Notifications.Outbox.Enabled {
+		notifier, b.relay = notify.NewOutbox(cfg.Notifications, database.NewMySQLRepository(db), logger)
+	} else {
+		notifier = notify.New(cfg.Notifications, logger)
+	}
+	if cfg.Instance != "" {
+		notifier = notify.WithInstance(notifier, cfg.Instance)
+	}
+
+	if cfg.Spreads.Enabled {
+		b.spreads, err = spreads.NewMonitor(cfg.Spreads, cfg.Trading.Symbols, exchangeClient, database.NewMySQLRepository(db), notifier, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	b.engine = trading.NewEngine(&trading.EngineConfig{
+		DB:             db,
+		Redis:          rdb,
+		ExchangeClient: exchangeClient,
+		Config:         cfg.Trading,
+		Logger:         logger,
+		Commentary:     commentator,
+		Notifier:       notifier,
+		Feeds:          feedService,
+		Options:        optionsVenue,
+		SpotClient:     spotClient,
+		OrderAudit:     orderAudit,
+		EventOutbox:    b.relay != nil && len(cfg.Notifications.EventWebhookURLs) > 0,
+		Instance:       cfg.Instance,
+	})
+	return b, nil
+}
+
+// start starts the engine and its background services
+func (b *bot) start(ctx context.Context) error {
+	if err := b.engine.Start(ctx); err != nil {
+		return err
+	}
+
+	b.database.Start(ctx)
+	if b.spreads != nil {
+		b.spreads.Start(ctx)
+	}
+	if b.relay != nil {
+		b.relay.Start(ctx)
+	}
+	return nil
+}
+
+// instanceHook labels every log entry of a bot with its instance name
+type instanceHook struct {
+	instance string
+}
+
+func (h instanceHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h instanceHook) Fire(entry *logrus.Entry) error {
+	entry.Data["instance"] = h.instance
+	return nil
+}
+
+// runInstances runs every bot listed in instances in this process, each with its own
+// configuration, database and exchange account, and blocks until a shutdown signal is
+// received. A bot that fails to start stops the bots started before it.
+func runInstances(cfg *config.Config, logger *logrus.Logger) error {
+	configs := make([]*config.Config, 0, len(cfg.Instances))
+	for _, name := range cfg.Instances {
+		instanceCfg, err := config.LoadInstance(cfg.Profile, name)
+		if err != nil {
+			return fmt.Errorf("failed to load configuration of instance %s: %w", name, err)
+		}
+		configs = append(configs, instanceCfg)
+	}
+	if err := config.ValidateInstances(configs); err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var bots []*bot
+	defer func() {
+		for _, b := range bots {
+			b.close()
+		}
+	}()
+	var instances []*api.Instance
+	for _, instanceCfg := range configs {
+		instanceLogger, err := newLogger(cfg.Logger)
+		if err != nil {
+			return err
+		}
+		instanceLogger.AddHook(instanceHook{instance: instanceCfg.Instance})
+
+		b, err := newBot(instanceCfg, instanceLogger, "", 0)
+		if err == nil {
+			if err = b.start(ctx); err != nil {
+				b.close()
+			}
+		}
+		if err != nil {
+			stopBots(bots)
+			return fmt.Errorf("failed to start instance %s: %w", instanceCfg.Instance, err)
+		}
+		bots = append(bots, b)
+		instances = append(instances, &api.Instance{Name: instanceCfg.Instance, Engine: b.engine, Spreads: b.spreads, Database: b.database})
+		logger.Infof("Started instance %s", instanceCfg.Instance)
+	}
+
+	if cfg.API.Enabled {
+		if err := api.NewMultiServer(cfg.API, instances, logger).Start(ctx); err != nil {
+			stopBots(bots)
+			return err
+		}
+	}
+
+	<-ctx.Done()
+	logger.Info("Shutdown signal received")
+	return stopBots(bots)
+}
+
+// stopBots stops the engines in parallel within one shutdown deadline
+func stopBots(bots []*bot) error {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(bots))
+	for i, b := range bots {
+		wg.Add(1)
+		go func(i int, b *bot) {
+			defer wg.Done()
+			errs[i] = b.engine.Stop(shutdownCtx)
+		}(i, b)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// runPlan connects read-only, evaluates the strategy once and prints the resulting orders
+func runPlan(cfg *config.Config, logger *logrus.Logger) error {
+	// Keep the report readable; only warnings and errors are logged during planning
+	if logger.GetLevel() > logrus.WarnLevel {
+		logger.SetLevel(logrus.WarnLevel)
+	}
+
+	db, err := database.InitMySQL(cfg.Database.MySQL)
+	if err != nil {
+		return err
+	}
+
+	exchangeClient, err := exchange.NewClient(cfg.Exchange, logger)
+	if err != nil {
+		return err
+	}
+
+	engine := trading.NewEngine(&trading.EngineConfig{
+		DB:             db,
+		ExchangeClient: exchangeClient,
+		Config:         cfg.Trading,
+		Logger:         logger,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	entries, err := engine.Plan(ctx)
+	if err != nil {
+		return err
+	}
+
+	printPlan(os.Stdout, engine, entries)
+	return nil
+}
+
+// runTaxExport writes the year's disposals, matched FIFO from all recorded fills, and its
+// funding and other income from the synced income history as CSV
+func runTaxExport(cfg *config.Config, year int, path string) error {
+	db, err := database.InitMySQL(cfg.Database.MySQL)
+	if err != nil {
+		return err
+	}
+	repository := database.NewMySQLRepository(db)
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	trades, err := repository.GetTradesBefore(end)
+	if err != nil {
+		return fmt.Errorf("failed to get trades: %w", err)
+	}
+	incomes, err := repository.GetIncomes(start, end)
+	if err != nil {
+		return fmt.Errorf("failed to get income history: %w", err)
+	}
+
+	out := io.Writer(os.Stdout)
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		defer file.Close()
+		out = file
+	}
+	return tax.WriteCSV(out, tax.BuildRows(trades, incomes, year))
+}
+
+// runAuditVerify walks the order audit log and reports whether it is intact
+func runAuditVerify(cfg *config.Config, logger *logrus.Logger) error {
+	if cfg.AuditLog.EncryptionKey == "" {
+		return fmt.Errorf("audit_log.encryption_key is required to verify the log")
+	}
+
+	db, err := database.InitMySQL(cfg.Database.MySQL)
+	if err != nil {
+		return err
+	}
+	orderAudit, err := auditlog.New(database.NewMySQLRepository(db), cfg.AuditLog.EncryptionKey)
+	if err != nil {
+		return err
+	}
+
+	checked, err := orderAudit.Verify()
+	if err != nil {
+		return fmt.Errorf("%w (%d entries verified before the failure)", err, checked)
+	}
+	logger.Infof("Order audit log is intact: %d entries verified", checked)
+	return nil
+}
+
+// runSnapshotExport writes the bot state to a snapshot file for migrating to another host
+func runSnapshotExport(cfg *config.Config, path string, logger *logrus.Logger) error {
+	db, err := database.InitMySQL(cfg.Database.MySQL)
+	if err != nil {
+		return err
+	}
+
+	configHash, err := config.Hash()
+	if err != nil {
+		return fmt.Errorf("failed to hash configuration: %w", err)
+	}
+	snap, err := snapshot.Export(db, configHash)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+	if err := snapshot.Write(file, snap); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	logger.Infof("Wrote snapshot %s: %s", path, snap.Summary())
+	return nil
+}
+
+// runSnapshotRestore loads a snapshot into the configured database; the bot must not be
+// running against it
+func runSnapshotRestore(cfg *config.Config, path string, logger *logrus.Logger) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+	snap, err := snapshot.Read(file)
+	if err != nil {
+		return err
+	}
+
+	configHash, err := config.Hash()
+	if err != nil {
+		return fmt.Errorf("failed to hash configuration: %w", err)
+	}
+	if snap.ConfigHash != configHash {
+		logger.Warnf("Snapshot was taken with a different configuration on %s; check symbols and strategy before starting", snap.Host)
+	}
+
+	db, err := database.InitMySQL(cfg.Database.MySQL)
+	if err != nil {
+		return err
+	}
+	if err := database.ApplyMigrations(db, cfg.Database.Migrations, migrations.Files, logger); err != nil {
+		return err
+	}
+	if err := snapshot.Restore(db, snap); err != nil {
+		return err
+	}
+
+	logger.Infof("Restored snapshot taken on %s at %s: %s", snap.Host, snap.CreatedAt.Format(time.RFC3339), snap.Summary())
+	return nil
+}
+
+// printPlan writes the plan report as a table
+func printPlan(out io.Writer, engine *trading.Engine, entries []*trading.PlanEntry) {
+	fmt.Fprintf(out, "Strategy: %s\n", engine.StrategyName())
+	if engine.PaperTrading() {
+		fmt.Fprintln(out, "Paper trading is ENABLED - the running bot would not send these orders")
+	}
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SYMBOL\tPRICE\tACTION\tORDER\tRISK\tREASON")
+
+	orders := 0
+	for _, entry := range entries {
+		order := "-"
+		risk := "-"
+		reason := entry.Reason
+
+		if entry.Order != nil {
+			order = fmt.Sprintf("%s %s %.6f", entry.Order.Side, entry.Order.Type, entry.Order.Quantity)
+			if entry.RiskApproved {
+				risk = "approved"
+				orders++
+			} else {
+				risk = "rejected"
+			}
+		}
+
+		if entry.Error != "" {
+			reason = "error: " + entry.Error
+		}
+
+		fmt.Fprintf(w, "%s\t%.6f\t%s\t%s\t%s\t%s\n", entry.Symbol, entry.Price, entry.Action, order, risk, reason)
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\n%d order(s) would be placed now\n", orders)
+}
+
+// newLogger builds the application logger from configuration
+func newLogger(cfg config.LoggerConfig) (*logrus.Logger, error) {
+	logger := logrus.New()
+
+	level, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log level %q: %w", cfg.Level, err)
+	}
+	logger.SetLevel(level)
+
+	if cfg.Format == "json" {
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	}
+
+	if cfg.Output == "file" {
+		if err := os.MkdirAll("logs", 0755); err != nil {
+			return nil, fmt.Errorf("failed to create log directory: %w", err)
+		}
+		file, err := os.OpenFile(filepath.Join("logs", "trader.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		logger.SetOutput(file)
+	}
+
+	return logger, nil
+}
@@ -0,0 +1,112 @@
+// Package analytics computes performance statistics over daily return series
+package analytics
+
+import "math"
+
+// DaysPerYear annualizes daily statistics; crypto futures trade every day
+const DaysPerYear = 365
+
+// Benchmark compares a strategy's daily returns on a symbol with buying and holding it
+// over the same days
+type Benchmark struct {
+	Symbol            string  `json:"symbol"`
+	Days              int     `json:"days"`
+	StrategyReturn    float64 `json:"strategy_return"`    // Compounded over the period
+	BenchmarkReturn   float64 `json:"benchmark_return"`   // Buy-and-hold, compounded over the period
+	Alpha             float64 `json:"alpha"`              // Annualized Jensen's alpha against buy-and-hold
+	Beta              float64 `json:"beta"`               // Sensitivity of strategy returns to buy-and-hold returns
+	StrategyDrawdown  float64 `json:"strategy_drawdown"`  // Largest peak-to-trough decline, as a fraction
+	BenchmarkDrawdown float64 `json:"benchmark_drawdown"` // Largest buy-and-hold decline, as a fraction
+	RelativeDrawdown  float64 `json:"relative_drawdown"`  // Largest decline of strategy equity divided by buy-and-hold equity
+}
+
+// Compare builds the benchmark of aligned daily returns; both series must cover the same
+// days in the same order. The longer series is truncated to the shorter one.
+func Compare(symbol string, strategy, benchmark []float64) *Benchmark {
+	n := len(strategy)
+	if len(benchmark) < n {
+		n = len(benchmark)
+	}
+	strategy, benchmark = strategy[:n], benchmark[:n]
+
+	result := &Benchmark{
+		Symbol:            symbol,
+		Days:              n,
+		StrategyReturn:    CompoundReturn(strategy),
+		BenchmarkReturn:   CompoundReturn(benchmark),
+		StrategyDrawdown:  MaxDrawdown(strategy),
+		BenchmarkDrawdown: MaxDrawdown(benchmark),
+	}
+	if n < 2 {
+		return result
+	}
+
+	strategyMean, benchmarkMean := mean(strategy), mean(benchmark)
+	var covariance, variance float64
+	for i := 0; i < n; i++ {
+		covariance += (strategy[i] - strategyMean) * (benchmark[i] - benchmarkMean)
+		variance += (benchmark[i] - benchmarkMean) * (benchmark[i] - benchmarkMean)
+	}
+	if variance > 0 {
+		result.Beta = covariance / variance
+	}
+	result.Alpha = (strategyMean - result.Beta*benchmarkMean) * DaysPerYear
+
+	// Relative equity is strategy equity measured in units of the held asset
+	relative := make([]float64, n)
+	for i := 0; i < n; i++ {
+		relative[i] = (1+strategy[i])/(1+benchmark[i]) - 1
+	}
+	result.RelativeDrawdown = MaxDrawdown(relative)
+	return result
+}
+
+// DailyReturns converts consecutive closing prices into simple returns
+func DailyReturns(closes []float64) []float64 {
+	if len(closes) < 2 {
+		return nil
+	}
+	returns := make([]float64, 0, len(closes)-1)
+	for i := 1; i < len(closes); i++ {
+		if closes[i-1] <= 0 {
+			returns = append(returns, 0)
+			continue
+		}
+		returns = append(returns, closes[i]/closes[i-1]-1)
+	}
+	return returns
+}
+
+// CompoundReturn returns the total return of a series of periodic returns
+func CompoundReturn(returns []float64) float64 {
+	equity := 1.0
+	for _, r := range returns {
+		equity *= 1 + r
+	}
+	return equity - 1
+}
+
+// MaxDrawdown returns the largest peak-to-trough decline of the equity curve built from
+// periodic returns, as a positive fraction
+func MaxDrawdown(returns []float64) float64 {
+	equity, peak, drawdown := 1.0, 1.0, 0.0
+	for _, r := range returns {
+		equity *= 1 + r
+		peak = math.Max(peak, equity)
+		if peak > 0 {
+			drawdown = math.Max(drawdown, (peak-equity)/peak)
+		}
+	}
+	return drawdown
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// handleADL returns the latest auto-deleveraging quantile of every position side
+// (GET /api/v1/adl)
+func (s *Server) handleADL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.engine.ADLQuantiles())
+}
+
+// handleADLHistory returns the recorded quantile changes, oldest first
+// (GET /api/v1/adl/history?symbol=BTCUSDT&hours=24)
+func (s *Server) handleADLHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	hours := 24.0
+	if value := query.Get("hours"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "hours must be a positive number")
+			return
+		}
+		hours = parsed
+	}
+
+	history, err := s.engine.ADLHistory(strings.ToUpper(query.Get("symbol")), time.Now().Add(-time.Duration(hours*float64(time.Hour))))
+	if err != nil {
+		s.logger.Errorf("Failed to load ADL history: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load ADL history")
+		return
+	}
+	writeJSON(w, http.StatusOK, history)
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"contract_playground/internal/auditlog"
+	"contract_playground/internal/config"
+)
+
+// caller is the authenticated identity of a request
+type caller struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type callerKey struct{}
+
+var roleRanks = map[string]int{
+	config.APIRoleViewer:   1,
+	config.APIRoleOperator: 2,
+	config.APIRoleAdmin:    3,
+}
+
+// credentialsConfigured reports whether the API requires authentication
+func (s *Server) credentialsConfigured() bool {
+	return s.config.AuthToken != "" || len(s.config.Keys) > 0 || s.config.JWT.Secret != ""
+}
+
+// authenticate identifies the caller of every request, attributes its order actions to the
+// caller and audits every call that is not a read. Without configured credentials requests
+// are anonymous and only reads are allowed.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		actor := "anonymous"
+		if s.credentialsConfigured() {
+			identity, err := s.identify(r)
+			if err != nil {
+				writeError(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+			ctx = context.WithValue(ctx, callerKey{}, identity)
+			actor = identity.Name
+		}
+		ctx = auditlog.WithSource(ctx, auditlog.TriggerManual, "api:"+actor)
+		r = r.WithContext(ctx)
+
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		s.engine.RecordAudit("api", r.Method+" "+r.URL.Path, "", actor, map[string]interface{}{
+			"query":       r.URL.RawQuery,
+			"remote_addr": r.RemoteAddr,
+			"status":      recorder.status,
+		})
+	})
+}
+
+// identify resolves the bearer credential to a caller: a configured key, the legacy auth
+// token (admin) or a signed JWT
+func (s *Server) identify(r *http.Request) (*caller, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return nil, errors.New("missing bearer token")
+	}
+
+	for _, key := range s.config.Keys {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(key.Key)) == 1 {
+			return &caller{Name: key.Name, Role: key.Role}, nil
+		}
+	}
+	if s.config.AuthToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AuthToken)) == 1 {
+		return &caller{Name: "auth_token", Role: config.APIRoleAdmin}, nil
+	}
+	if s.config.JWT.Secret != "" && strings.Count(token, ".") == 2 {
+		return verifyJWT(token, s.config.JWT, time.Now())
+	}
+	return nil, errors.New("unknown bearer token")
+}
+
+// requireRole refuses the request unless the caller has at least the role
+func (s *Server) requireRole(w http.ResponseWriter, r *http.Request, role string) bool {
+	identity, _ := r.Context().Value(callerKey{}).(*caller)
+	if identity == nil {
+		writeError(w, http.StatusForbidden, "this action requires API credentials (api.auth_token, api.keys or api.jwt)")
+		return false
+	}
+	if roleRanks[identity.Role] < roleRanks[role] {
+		writeError(w, http.StatusForbidden, "this action requires the "+role+" role")
+		return false
+	}
+	return true
+}
+
+// jwtClaims are the claims read from API tokens
+type jwtClaims struct {
+	Subject   string `json:"sub"`
+	Role      string `json:"role"`
+	Issuer    string `json:"iss"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf"`
+}
+
+// verifyJWT checks an HS256 token's signature, expiry and issuer
+func verifyJWT(token string, cfg config.JWTConfig, now time.Time) (*caller, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return nil, errors.New("unsupported token algorithm")
+	}
+
+	mac := hmac.New(sha256.New, []byte(cfg.Secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, errors.New("invalid token signature")
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, errors.New("malformed token claims")
+	}
+	if claims.ExpiresAt == 0 || now.Unix() >= claims.ExpiresAt {
+		return nil, errors.New("token expired")
+	}
+	if claims.NotBefore != 0 && now.Unix() < claims.NotBefore {
+		return nil, errors.New("token not yet valid")
+	}
+	if cfg.Issuer != "" && claims.Issuer != cfg.Issuer {
+		return nil, errors.New("unexpected token issuer")
+	}
+	if claims.Subject == "" || roleRanks[claims.Role] == 0 {
+		return nil, errors.New("token lacks subject or role")
+	}
+	return &caller{Name: claims.Subject, Role: claims.Role}, nil
+}
+
+func decodeSegment(segment string, out interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// statusRecorder captures the response status for the audit record
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"contract_playground/internal/events"
+)
+
+// Events buffered per stream before a slow client starts losing them
+const eventStreamBuffer = 256
+
+// handleEventStream streams order, position and risk events as newline-delimited JSON
+// until the client disconnects. The types query parameter selects event types.
+func (s *Server) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	var types []string
+	if value := r.URL.Query().Get("types"); value != "" {
+		for _, t := range strings.Split(value, ",") {
+			switch t = strings.TrimSpace(t); t {
+			case events.TypeOrder, events.TypePosition, events.TypeRisk:
+				types = append(types, t)
+			default:
+				writeError(w, http.StatusBadRequest, "unknown event type "+t)
+				return
+			}
+		}
+	}
+
+	stream, unsubscribe := s.engine.Events().Subscribe(eventStreamBuffer, types...)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-stream:
+			if err := encoder.Encode(event); err != nil {
+				s.logger.Debugf("Event stream client went away: %v", err)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// handleExecution reports slippage, maker ratio and fill latency of recent fills by symbol,
+// UTC hour and order type (GET /api/v1/execution?hours=168)
+func (s *Server) handleExecution(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	hours := 168.0
+	if value := r.URL.Query().Get("hours"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "hours must be a positive number")
+			return
+		}
+		hours = parsed
+	}
+
+	report, err := s.engine.ExecutionReport(time.Now().Add(-time.Duration(hours * float64(time.Hour))))
+	if err != nil {
+		s.logger.Errorf("Failed to build execution report: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to build execution report")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
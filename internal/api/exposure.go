@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+)
+
+// handleExposure reports the combined perpetual and spot exposure per base asset
+// (GET /api/v1/exposure)
+func (s *Server) handleExposure(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	exposure, err := s.engine.CombinedExposure(r.Context())
+	if err != nil {
+		s.logger.Errorf("Failed to build combined exposure: %v", err)
+		writeError(w, http.StatusBadGateway, "failed to build combined exposure")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, exposure)
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+
+	"contract_playground/internal/trading"
+)
+
+// handleHealthz reports liveness: the engine runs and its loops and streams are alive
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeHealth(w, s.engine.Liveness())
+}
+
+// handleReadyz reports readiness: liveness plus database, Redis and exchange connectivity
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeHealth(w, s.engine.Readiness(r.Context()))
+}
+
+func writeHealth(w http.ResponseWriter, report *trading.HealthReport) {
+	status := http.StatusOK
+	if !report.OK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// handleReturnHeatmap reports average trade return and win rate by entry hour and weekday
+// per symbol (GET /api/v1/analytics/heatmap?days=90&symbol=BTCUSDT&tz=Asia/Shanghai)
+func (s *Server) handleReturnHeatmap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	days := 90.0
+	if value := query.Get("days"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "days must be a positive number")
+			return
+		}
+		days = parsed
+	}
+	if _, err := time.LoadLocation(query.Get("tz")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid tz: "+err.Error())
+		return
+	}
+
+	since := time.Now().Add(-time.Duration(days * float64(24*time.Hour)))
+	report, err := s.engine.ReturnHeatmap(since, query.Get("tz"), strings.ToUpper(query.Get("symbol")))
+	if err != nil {
+		s.logger.Errorf("Failed to build return heatmap: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to build return heatmap")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
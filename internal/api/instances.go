@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/spreads"
+	"contract_playground/internal/trading"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Instance is one bot of a multi-instance process
+type Instance struct {
+	Name     string
+	Engine   *trading.Engine
+	Spreads  *spreads.Monitor  // nil when the spread monitor is disabled
+	Database *database.Monitor // nil when database metrics are not collected
+}
+
+// MultiServer serves the API of every bot of a multi-instance process on one listener. Each
+// bot keeps the routes of a single bot under /instances/{name}/, so /api/v1/status of bot
+// acct1 is /instances/acct1/api/v1/status; credentials and roles are shared.
+type MultiServer struct {
+	servers map[string]*Server
+	names   []string
+	logger  *logrus.Logger
+	server  *http.Server
+}
+
+// NewMultiServer creates the API server of a multi-instance process
+func NewMultiServer(cfg config.APIConfig, instances []*Instance, logger *logrus.Logger) *MultiServer {
+	m := &MultiServer{servers: make(map[string]*Server, len(instances)), logger: logger}
+
+	root := http.NewServeMux()
+	for _, instance := range instances {
+		s := &Server{
+			config:   cfg,
+			engine:   instance.Engine,
+			spreads:  instance.Spreads,
+			database: instance.Database,
+			logger:   logger,
+		}
+		m.servers[instance.Name] = s
+		m.names = append(m.names, instance.Name)
+		prefix := "/instances/" + instance.Name
+		root.Handle(prefix+"/", http.StripPrefix(prefix, s.handler()))
+	}
+
+	// Probes of the whole process; each bot keeps its own under its prefix
+	root.HandleFunc("/healthz", m.handleHealthz)
+	root.HandleFunc("/readyz", m.handleReadyz)
+	if len(instances) > 0 {
+		root.Handle("/api/v1/instances", m.servers[instances[0].Name].authenticate(http.HandlerFunc(m.handleInstances)))
+	}
+
+	m.server = &http.Server{
+		Addr:              cfg.ListenAddr,
+		Handler:           root,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return m
+}
+
+// Start serves the API until ctx is cancelled
+func (m *MultiServer) Start(ctx context.Context) error {
+	serve(ctx, m.server, m.logger)
+	return nil
+}
+
+// handleInstances lists the bots with their status (GET /api/v1/instances)
+func (m *MultiServer) handleInstances(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	statuses := make([]*trading.EngineStatus, 0, len(m.names))
+	for _, name := range m.names {
+		statuses = append(statuses, m.servers[name].engine.Status())
+	}
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+// handleHealthz reports the process live while every bot is live
+func (m *MultiServer) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	m.writeHealth(w, func(engine *trading.Engine) *trading.HealthReport {
+		return engine.Liveness()
+	})
+}
+
+// handleReadyz reports the process ready while every bot is ready
+func (m *MultiServer) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	m.writeHealth(w, func(engine *trading.Engine) *trading.HealthReport {
+		return engine.Readiness(r.Context())
+	})
+}
+
+func (m *MultiServer) writeHealth(w http.ResponseWriter, check func(*trading.Engine) *trading.HealthReport) {
+	ok := true
+	reports := make(map[string]*trading.HealthReport, len(m.names))
+	for _, name := range m.names {
+		report := check(m.servers[name].engine)
+		reports[name] = report
+		ok = ok && report.OK
+	}
+
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, map[string]interface{}{"ok": ok, "instances": reports})
+}
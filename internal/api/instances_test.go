@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/trading"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// offlineDB is a database handle whose queries fail fast; the status of an engine without
+// today's figures still names its instance and symbols
+func offlineDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "trader:pw@tcp(127.0.0.1:1)/none", SkipInitializeWithVersion: true}),
+		&gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestMultiServerRoutesInstances(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	db := offlineDB(t)
+
+	var instances []*Instance
+	for name, symbol := range map[string]string{"alpha": "BTCUSDT", "beta": "ETHUSDT"} {
+		cfg := config.TradingConfig{Symbols: []string{symbol}, EnablePaperTrading: true}
+		cfg.Strategy.Type = "simple_moving_average"
+		engine := trading.NewEngine(&trading.EngineConfig{DB: db, Config: cfg, Logger: logger, Instance: name})
+		instances = append(instances, &Instance{Name: name, Engine: engine})
+	}
+	const token = "multi-instance-admin-token"
+	server := httptest.NewServer(NewMultiServer(config.APIConfig{AuthToken: token}, instances, logger).server.Handler)
+	defer server.Close()
+
+	get := func(path string, authorized bool) *http.Response {
+		request, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if authorized {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { response.Body.Close() })
+		return response
+	}
+
+	response := get("/instances/beta/api/v1/status", true)
+	var status trading.EngineStatus
+	if err := json.NewDecoder(response.Body).Decode(&status); err != nil || response.StatusCode != http.StatusOK {
+		t.Fatalf("beta status: %d, %v", response.StatusCode, err)
+	}
+	if status.Instance != "beta" || len(status.Symbols) != 1 || status.Symbols[0] != "ETHUSDT" {
+		t.Errorf("beta status = %s %v, want the beta engine trading ETHUSDT", status.Instance, status.Symbols)
+	}
+
+	if response := get("/instances/alpha/api/v1/status", false); response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("alpha status without a token: %d, want 401", response.StatusCode)
+	}
+	if response := get("/api/v1/instances", false); response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("instance list without a token: %d, want 401", response.StatusCode)
+	}
+	if response := get("/instances/gamma/api/v1/status", true); response.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown instance: %d, want 404", response.StatusCode)
+	}
+
+	response = get("/api/v1/instances", true)
+	var statuses []*trading.EngineStatus
+	if err := json.NewDecoder(response.Body).Decode(&statuses); err != nil || response.StatusCode != http.StatusOK {
+		t.Fatalf("instance list: %d, %v", response.StatusCode, err)
+	}
+	if len(statuses) != 2 {
+		t.Errorf("listed %d instances, want 2", len(statuses))
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"contract_playground/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// journalView is a journal entry with its JSON snapshots embedded as objects
+type journalView struct {
+	*models.TradeJournal
+	Features      json.RawMessage `json:"features,omitempty"`
+	Indicators    json.RawMessage `json:"indicators,omitempty"`
+	StrategyState json.RawMessage `json:"strategy_state,omitempty"`
+	Klines        json.RawMessage `json:"klines,omitempty"`
+}
+
+func newJournalView(entry *models.TradeJournal) *journalView {
+	raw := func(value string) json.RawMessage {
+		if value == "" {
+			return nil
+		}
+		return json.RawMessage(value)
+	}
+	return &journalView{
+		TradeJournal:  entry,
+		Features:      raw(entry.Features),
+		Indicators:    raw(entry.Indicators),
+		StrategyState: raw(entry.StrategyState),
+		Klines:        raw(entry.Klines),
+	}
+}
+
+// handleJournal lists journal entries without their strategy state and klines
+// (GET /api/v1/journal?symbol=&position_id=&limit=)
+func (s *Server) handleJournal(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	limit := 50
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > 500 {
+			writeError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		limit = parsed
+	}
+	var positionID uint64
+	if value := query.Get("position_id"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "position_id must be a positive integer")
+			return
+		}
+		positionID = parsed
+	}
+
+	entries, err := s.engine.JournalEntries(strings.ToUpper(query.Get("symbol")), uint(positionID), limit)
+	if err != nil {
+		s.logger.Errorf("Failed to load journal: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load journal")
+		return
+	}
+
+	views := make([]*journalView, 0, len(entries))
+	for _, entry := range entries {
+		views = append(views, newJournalView(entry))
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+// handleJournalEntry returns one complete journal entry (GET /api/v1/journal/{id})
+func (s *Server) handleJournalEntry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, "/api/v1/journal/"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "journal entry not found")
+		return
+	}
+
+	entry, err := s.engine.JournalEntry(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		writeError(w, http.StatusNotFound, "journal entry not found")
+		return
+	}
+	if err != nil {
+		s.logger.Errorf("Failed to load journal entry %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "failed to load journal entry")
+		return
+	}
+	writeJSON(w, http.StatusOK, newJournalView(entry))
+}
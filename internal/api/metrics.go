@@ -0,0 +1,21 @@
+package api
+
+import (
+	"net/http"
+)
+
+// handleDatabaseMetrics reports connection pool usage and per-query latency histograms
+// (GET /api/v1/metrics/database)
+func (s *Server) handleDatabaseMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if s.database == nil {
+		writeError(w, http.StatusServiceUnavailable, "database metrics are not collected")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.database.Metrics())
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/trading"
+)
+
+// Largest request body accepted by the order endpoints
+const maxOrderBodyBytes = 64 << 10
+
+// handleOrders lists manual orders (GET) or places one (POST)
+func (s *Server) handleOrders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && !s.requireRole(w, r, config.APIRoleOperator) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		limit := 50
+		if value := r.URL.Query().Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 || parsed > 500 {
+				writeError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+				return
+			}
+			limit = parsed
+		}
+
+		orders, err := s.engine.ManualOrders(strings.ToUpper(r.URL.Query().Get("symbol")), limit)
+		if err != nil {
+			s.logger.Errorf("Failed to load manual orders: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to load manual orders")
+			return
+		}
+		writeJSON(w, http.StatusOK, orders)
+
+	case http.MethodPost:
+		var req trading.ManualOrderRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxOrderBodyBytes)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid order: "+err.Error())
+			return
+		}
+
+		order, err := s.engine.PlaceManualOrder(r.Context(), &req)
+		if err != nil {
+			s.logger.Warnf("Manual order rejected: %v", err)
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, order)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleOrder modifies (PUT) or cancels (DELETE) the manual order /api/v1/orders/{id}
+func (s *Server) handleOrder(w http.ResponseWriter, r *http.Request) {
+	if !s.requireRole(w, r, config.APIRoleOperator) {
+		return
+	}
+
+	id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, "/api/v1/orders/"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "order not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var body struct {
+			Quantity float64 `json:"quantity"`
+			Price    float64 `json:"price"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxOrderBodyBytes)).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid order: "+err.Error())
+			return
+		}
+
+		order, err := s.engine.ModifyManualOrder(r.Context(), uint(id), body.Quantity, body.Price)
+		if err != nil {
+			s.logger.Warnf("Manual order %d modification failed: %v", id, err)
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, order)
+
+	case http.MethodDelete:
+		if err := s.engine.CancelManualOrder(r.Context(), uint(id)); err != nil {
+			s.logger.Warnf("Manual order %d cancel failed: %v", id, err)
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "canceled"})
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
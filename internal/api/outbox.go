@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"contract_playground/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// outboxView is an outbox message with its JSON payload embedded as an object
+type outboxView struct {
+	*models.OutboxMessage
+	Payload json.RawMessage `json:"payload"`
+}
+
+// handleOutbox lists outbox messages, newest first (GET /api/v1/outbox?status=&limit=)
+func (s *Server) handleOutbox(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	status := strings.ToUpper(query.Get("status"))
+	switch status {
+	case "", models.OutboxPending, models.OutboxDelivered, models.OutboxDead:
+	default:
+		writeError(w, http.StatusBadRequest, "status must be PENDING, DELIVERED or DEAD")
+		return
+	}
+	limit := 50
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > 500 {
+			writeError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		limit = parsed
+	}
+
+	messages, err := s.engine.OutboxMessages(status, limit)
+	if err != nil {
+		s.logger.Errorf("Failed to load outbox: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load outbox")
+		return
+	}
+	views := make([]*outboxView, 0, len(messages))
+	for _, message := range messages {
+		views = append(views, &outboxView{OutboxMessage: message, Payload: json.RawMessage(message.Payload)})
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+// handleOutboxRequeue retries a dead message (POST /api/v1/outbox/{id}/requeue)
+func (s *Server) handleOutboxRequeue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	rest, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/outbox/"), "/requeue")
+	id, err := strconv.ParseUint(rest, 10, 64)
+	if !ok || err != nil {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	err = s.engine.RequeueOutboxMessage(uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		writeError(w, http.StatusNotFound, "no dead outbox message with this id")
+		return
+	}
+	if err != nil {
+		s.logger.Errorf("Failed to requeue outbox message %d: %v", id, err)
+		writeError(w, http.StatusInternalServerError, "failed to requeue outbox message")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"id": id, "status": models.OutboxPending})
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"contract_playground/internal/config"
+)
+
+// handlePositions lists open positions (GET /api/v1/positions), closes one
+// (POST /api/v1/positions/{id}/close) or closes every position of a symbol
+// (POST /api/v1/positions/close?symbol=BTCUSDT)
+func (s *Server) handlePositions(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/positions"), "/")
+
+	if path == "" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		positions, err := s.engine.OpenPositions()
+		if err != nil {
+			s.logger.Errorf("Failed to load positions: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to load positions")
+			return
+		}
+		writeJSON(w, http.StatusOK, positions)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !s.requireRole(w, r, config.APIRoleOperator) {
+		return
+	}
+
+	if path == "close" {
+		symbol := r.URL.Query().Get("symbol")
+		if symbol == "" {
+			writeError(w, http.StatusBadRequest, "symbol is required")
+			return
+		}
+		results, err := s.engine.CloseSymbol(r.Context(), symbol)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, results)
+		return
+	}
+
+	idText, action, _ := strings.Cut(path, "/")
+	id, err := strconv.ParseUint(idText, 10, 64)
+	if err != nil || action != "close" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	var body struct {
+		Type  string  `json:"type"` // MARKET (default) or LIMIT
+		Price float64 `json:"price"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxOrderBodyBytes)).Decode(&body); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+		return
+	}
+
+	result, err := s.engine.ClosePosition(r.Context(), uint(id), body.Type, body.Price)
+	if err != nil {
+		s.logger.Warnf("Closing position %d failed: %v", id, err)
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleFlatten closes every open position and cancels pending manual entries
+func (s *Server) handleFlatten(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !s.requireRole(w, r, config.APIRoleOperator) {
+		return
+	}
+
+	results, err := s.engine.FlattenAccount(r.Context())
+	if err != nil {
+		s.logger.Errorf("Flatten failed: %v", err)
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
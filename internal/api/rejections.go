@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// handleRejections summarizes the orders the exchange rejected by class and symbol, with the
+// newest rejections (GET /api/v1/rejections?hours=24&limit=20)
+func (s *Server) handleRejections(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	hours := 24.0
+	if value := query.Get("hours"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "hours must be a positive number")
+			return
+		}
+		hours = parsed
+	}
+	limit := 20
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || parsed > 500 {
+			writeError(w, http.StatusBadRequest, "limit must be between 0 and 500")
+			return
+		}
+		limit = parsed
+	}
+
+	summary, err := s.engine.Rejections(time.Now().Add(-time.Duration(hours*float64(time.Hour))), limit)
+	if err != nil {
+		s.logger.Errorf("Failed to load order rejections: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load order rejections")
+		return
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
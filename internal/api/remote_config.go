@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/models"
+	"contract_playground/internal/trading"
+)
+
+// Largest request body accepted by the remote config endpoints
+const maxConfigBodyBytes = 64 << 10
+
+// handleRiskLimits returns (GET) or changes (PUT, admin) the remotely edited risk limits
+func (s *Server) handleRiskLimits(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		limits, err := s.engine.RemoteRiskLimits()
+		if err != nil {
+			s.writeConfigError(w, "load risk limits", err)
+			return
+		}
+		writeJSON(w, http.StatusOK, limits)
+
+	case http.MethodPut:
+		if !s.requireRole(w, r, config.APIRoleAdmin) {
+			return
+		}
+		var update trading.RiskLimitsUpdate
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxConfigBodyBytes)).Decode(&update); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid risk limits: "+err.Error())
+			return
+		}
+
+		limits, err := s.engine.UpdateRiskLimits(r.Context(), &update)
+		if err != nil {
+			s.writeConfigError(w, "update risk limits", err)
+			return
+		}
+		writeJSON(w, http.StatusOK, limits)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleStrategyConfig returns (GET) or changes (PUT, admin) the remotely edited strategy
+// parameters
+func (s *Server) handleStrategyConfig(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		params, err := s.engine.RemoteStrategyParameters()
+		if err != nil {
+			s.writeConfigError(w, "load strategy parameters", err)
+			return
+		}
+		writeJSON(w, http.StatusOK, params)
+
+	case http.MethodPut:
+		if !s.requireRole(w, r, config.APIRoleAdmin) {
+			return
+		}
+		var update trading.StrategyParametersUpdate
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxConfigBodyBytes)).Decode(&update); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid strategy parameters: "+err.Error())
+			return
+		}
+
+		params, err := s.engine.UpdateStrategyParameters(r.Context(), &update)
+		if err != nil {
+			s.writeConfigError(w, "update strategy parameters", err)
+			return
+		}
+		writeJSON(w, http.StatusOK, params)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleConfigChanges returns the history of remote config changes, newest first
+func (s *Server) handleConfigChanges(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	entity := query.Get("entity")
+	if entity != "" && entity != models.ConfigEntityRiskLimits && entity != models.ConfigEntityStrategy {
+		writeError(w, http.StatusBadRequest, "entity must be risk_limits or strategy")
+		return
+	}
+	limit := 50
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > 500 {
+			writeError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		limit = parsed
+	}
+
+	changes, err := s.engine.ConfigChanges(entity, limit)
+	if err != nil {
+		s.writeConfigError(w, "load config changes", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, changes)
+}
+
+// writeConfigError maps remote config errors to status codes
+func (s *Server) writeConfigError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, trading.ErrRemoteConfigDisabled):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, trading.ErrInvalidRemoteConfig):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, database.ErrVersionConflict):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		s.logger.Errorf("Failed to %s: %v", action, err)
+		writeError(w, http.StatusInternalServerError, "failed to "+action)
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// handleRiskMetrics returns one risk metric row per day, newest first
+// (GET /api/v1/risk/metrics?days=30)
+func (s *Server) handleRiskMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	days := 30
+	if value := r.URL.Query().Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "days must be a positive integer")
+			return
+		}
+		days = parsed
+	}
+
+	metrics, err := s.engine.RiskMetricHistory(days)
+	if err != nil {
+		s.logger.Errorf("Failed to load risk metrics: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load risk metrics")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, metrics)
+}
+
+// handleRiskMetricPoints returns the intraday risk metric series for charts, oldest first
+// (GET /api/v1/risk/metrics/intraday?hours=24)
+func (s *Server) handleRiskMetricPoints(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	hours := 24.0
+	if value := r.URL.Query().Get("hours"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "hours must be a positive number")
+			return
+		}
+		hours = parsed
+	}
+
+	points, err := s.engine.RiskMetricPoints(time.Now().Add(-time.Duration(hours * float64(time.Hour))))
+	if err != nil {
+		s.logger.Errorf("Failed to load risk metric points: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load risk metric points")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, points)
+}
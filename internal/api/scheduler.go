@@ -0,0 +1,16 @@
+package api
+
+import (
+	"net/http"
+)
+
+// handleScheduler reports the run history and next run of every scheduled job
+// (GET /api/v1/scheduler)
+func (s *Server) handleScheduler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.engine.SchedulerStatus())
+}
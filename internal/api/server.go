@@ -0,0 +1,232 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/spreads"
+	"contract_playground/internal/trading"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Server exposes engine state over HTTP for dashboards and tooling
+type Server struct {
+	config   config.APIConfig
+	engine   *trading.Engine
+	spreads  *spreads.Monitor  // nil when the spread monitor is disabled
+	database *database.Monitor // nil when database metrics are not collected
+	logger   *logrus.Logger
+	server   *http.Server
+}
+
+// NewServer creates a new API server
+func NewServer(cfg config.APIConfig, engine *trading.Engine, spreadMonitor *spreads.Monitor, dbMonitor *database.Monitor, logger *logrus.Logger) *Server {
+	s := &Server{
+		config:   cfg,
+		engine:   engine,
+		spreads:  spreadMonitor,
+		database: dbMonitor,
+		logger:   logger,
+	}
+	s.server = &http.Server{
+		Addr:              cfg.ListenAddr,
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s
+}
+
+// handler routes the API of the engine
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/status", s.handleStatus)
+	mux.HandleFunc("/api/v1/symbols/", s.handleSymbol)
+	mux.HandleFunc("/api/v1/strategy/resume", s.handleStrategyResume)
+	mux.HandleFunc("/api/v1/correlations", s.handleCorrelations)
+	mux.HandleFunc("/api/v1/correlations/pairs", s.handleCorrelationPairs)
+	mux.HandleFunc("/api/v1/spreads", s.handleSpreads)
+	mux.HandleFunc("/api/v1/spreads/history", s.handleSpreadHistory)
+	mux.HandleFunc("/api/v1/events/stream", s.handleEventStream)
+	mux.HandleFunc("/api/v1/orders", s.handleOrders)
+	mux.HandleFunc("/api/v1/orders/", s.handleOrder)
+	mux.HandleFunc("/api/v1/rejections", s.handleRejections)
+	mux.HandleFunc("/api/v1/positions", s.handlePositions)
+	mux.HandleFunc("/api/v1/positions/", s.handlePositions)
+	mux.HandleFunc("/api/v1/flatten", s.handleFlatten)
+	mux.HandleFunc("/api/v1/journal", s.handleJournal)
+	mux.HandleFunc("/api/v1/journal/", s.handleJournalEntry)
+	mux.HandleFunc("/api/v1/execution", s.handleExecution)
+	mux.HandleFunc("/api/v1/analytics/heatmap", s.handleReturnHeatmap)
+	mux.HandleFunc("/api/v1/exposure", s.handleExposure)
+	mux.HandleFunc("/api/v1/risk/metrics", s.handleRiskMetrics)
+	mux.HandleFunc("/api/v1/risk/metrics/intraday", s.handleRiskMetricPoints)
+	mux.HandleFunc("/api/v1/adl", s.handleADL)
+	mux.HandleFunc("/api/v1/adl/history", s.handleADLHistory)
+	mux.HandleFunc("/api/v1/shadow", s.handleShadow)
+	mux.HandleFunc("/api/v1/scheduler", s.handleScheduler)
+	mux.HandleFunc("/api/v1/metrics/database", s.handleDatabaseMetrics)
+	mux.HandleFunc("/api/v1/outbox", s.handleOutbox)
+	mux.HandleFunc("/api/v1/outbox/", s.handleOutboxRequeue)
+	mux.HandleFunc("/api/v1/wallets", s.handleWallets)
+	mux.HandleFunc("/api/v1/wallets/", s.handleWallet)
+	mux.HandleFunc("/api/v1/config/risk-limits", s.handleRiskLimits)
+	mux.HandleFunc("/api/v1/config/strategy", s.handleStrategyConfig)
+	mux.HandleFunc("/api/v1/config/changes", s.handleConfigChanges)
+
+	// Health probes come from orchestration without the bearer token
+	root := http.NewServeMux()
+	root.HandleFunc("/healthz", s.handleHealthz)
+	root.HandleFunc("/readyz", s.handleReadyz)
+	root.Handle("/", s.authenticate(mux))
+	return root
+}
+
+// Start serves the API until ctx is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	serve(ctx, s.server, s.logger)
+	return nil
+}
+
+// serve runs server until ctx is cancelled
+func serve(ctx context.Context, server *http.Server, logger *logrus.Logger) {
+	errC := make(chan error, 1)
+	go func() {
+		logger.Infof("API server listening on %s", server.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errC <- err
+		}
+	}()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				logger.Errorf("Failed to shut down API server: %v", err)
+			}
+		case err := <-errC:
+			logger.Errorf("API server stopped: %v", err)
+		}
+	}()
+}
+
+// handleCorrelations returns the latest rolling correlation matrix
+func (s *Server) handleCorrelations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	matrix := s.engine.Correlations()
+	if matrix == nil {
+		writeError(w, http.StatusServiceUnavailable, "correlation matrix not available yet")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, matrix)
+}
+
+// handleCorrelationPairs returns symbol pairs above a minimum absolute correlation
+func (s *Server) handleCorrelationPairs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	min := 0.8
+	if value := r.URL.Query().Get("min"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			writeError(w, http.StatusBadRequest, "min must be a number between 0 and 1")
+			return
+		}
+		min = parsed
+	}
+
+	matrix := s.engine.Correlations()
+	if matrix == nil {
+		writeError(w, http.StatusServiceUnavailable, "correlation matrix not available yet")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"min":        min,
+		"updated_at": matrix.UpdatedAt,
+		"pairs":      matrix.Pairs(min),
+	})
+}
+
+// handleSpreads returns the latest cross-exchange spread of every symbol and venue
+func (s *Server) handleSpreads(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if s.spreads == nil {
+		writeError(w, http.StatusServiceUnavailable, "spread monitor is disabled")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.spreads.Latest())
+}
+
+// handleSpreadHistory returns the stored spread series of a symbol
+func (s *Server) handleSpreadHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if s.spreads == nil {
+		writeError(w, http.StatusServiceUnavailable, "spread monitor is disabled")
+		return
+	}
+
+	query := r.URL.Query()
+	symbol := query.Get("symbol")
+	if symbol == "" {
+		writeError(w, http.StatusBadRequest, "symbol is required")
+		return
+	}
+
+	hours := 24.0
+	if value := query.Get("hours"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "hours must be a positive number")
+			return
+		}
+		hours = parsed
+	}
+
+	samples, err := s.spreads.History(symbol, query.Get("venue"), time.Now().Add(-time.Duration(hours*float64(time.Hour))))
+	if err != nil {
+		s.logger.Errorf("Failed to load spread history: %v", err)
+		writeError(w, http.StatusInternalServerError, "failed to load spread history")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, samples)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logrus.Errorf("Failed to encode API response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
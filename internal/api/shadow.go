@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+)
+
+// handleShadow compares the shadow test's candidate strategy with the control copy of the
+// live strategy (GET /api/v1/shadow)
+func (s *Server) handleShadow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	report, err := s.engine.ShadowReport()
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"contract_playground/internal/config"
+)
+
+// handleStatus returns the engine state including paused symbols
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.engine.Status())
+}
+
+// handleSymbol pauses (POST /api/v1/symbols/{symbol}/pause) or resumes
+// (POST /api/v1/symbols/{symbol}/resume) new entries for a symbol
+func (s *Server) handleSymbol(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !s.requireRole(w, r, config.APIRoleOperator) {
+		return
+	}
+
+	symbol, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/symbols/"), "/"), "/")
+	switch action {
+	case "pause":
+		var body struct {
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxOrderBodyBytes)).Decode(&body); err != nil && err != io.EOF {
+			writeError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+			return
+		}
+		if err := s.engine.PauseSymbol(symbol, body.Reason); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+	case "resume":
+		if err := s.engine.ResumeSymbol(symbol); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.engine.Status())
+}
+
+// handleStrategyResume lifts a strategy guard pause (POST /api/v1/strategy/resume)
+func (s *Server) handleStrategyResume(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !s.requireRole(w, r, config.APIRoleOperator) {
+		return
+	}
+
+	if err := s.engine.ResumeStrategy(); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s.engine.Status())
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/trading"
+)
+
+// walletTransferRequest moves capital into (positive amount) or out of a strategy wallet
+type walletTransferRequest struct {
+	Amount float64 `json:"amount"`
+	Note   string  `json:"note"`
+}
+
+// handleWallets returns the virtual wallet of every strategy (GET /api/v1/wallets)
+func (s *Server) handleWallets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	wallets, err := s.engine.Wallets()
+	if err != nil {
+		s.writeWalletError(w, "load wallets", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, wallets)
+}
+
+// handleWallet serves the equity curve and the transfers of one wallet:
+//
+//	GET  /api/v1/wallets/{strategy}/equity?hours=168
+//	GET  /api/v1/wallets/{strategy}/transfers?limit=50
+//	POST /api/v1/wallets/{strategy}/transfers (admin)
+//
+// Strategy names contain spaces, so the name is path-escaped.
+func (s *Server) handleWallet(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v1/wallets/")
+	escaped, resource, ok := strings.Cut(rest, "/")
+	strategy, err := url.PathUnescape(escaped)
+	if !ok || err != nil || strategy == "" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	switch {
+	case resource == "equity" && r.Method == http.MethodGet:
+		s.handleWalletEquity(w, r, strategy)
+	case resource == "transfers" && r.Method == http.MethodGet:
+		s.handleWalletTransfers(w, r, strategy)
+	case resource == "transfers" && r.Method == http.MethodPost:
+		s.handleWalletTransfer(w, r, strategy)
+	case resource == "equity" || resource == "transfers":
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) handleWalletEquity(w http.ResponseWriter, r *http.Request, strategy string) {
+	hours := 168.0
+	if value := r.URL.Query().Get("hours"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "hours must be a positive number")
+			return
+		}
+		hours = parsed
+	}
+
+	snapshots, err := s.engine.WalletEquity(strategy, time.Now().Add(-time.Duration(hours*float64(time.Hour))))
+	if err != nil {
+		s.writeWalletError(w, "load wallet equity", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshots)
+}
+
+func (s *Server) handleWalletTransfers(w http.ResponseWriter, r *http.Request, strategy string) {
+	limit := 50
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > 500 {
+			writeError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		limit = parsed
+	}
+
+	transfers, err := s.engine.WalletTransfers(strategy, limit)
+	if err != nil {
+		s.writeWalletError(w, "load wallet transfers", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, transfers)
+}
+
+func (s *Server) handleWalletTransfer(w http.ResponseWriter, r *http.Request, strategy string) {
+	if !s.requireRole(w, r, config.APIRoleAdmin) {
+		return
+	}
+	var req walletTransferRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxConfigBodyBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid transfer: "+err.Error())
+		return
+	}
+	if req.Amount == 0 || math.IsNaN(req.Amount) || math.IsInf(req.Amount, 0) {
+		writeError(w, http.StatusBadRequest, "amount must be a non-zero number")
+		return
+	}
+
+	wallet, err := s.engine.TransferWallet(r.Context(), strategy, req.Amount, req.Note)
+	if err != nil {
+		s.writeWalletError(w, "transfer to wallet", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, wallet)
+}
+
+func (s *Server) writeWalletError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, trading.ErrWalletsDisabled):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, trading.ErrInsufficientWalletFunds):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		s.logger.Errorf("Failed to %s: %v", action, err)
+		writeError(w, http.StatusInternalServerError, "failed to "+action)
+	}
+}
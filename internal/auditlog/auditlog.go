@@ -0,0 +1,232 @@
+package auditlog
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"contract_playground/internal/models"
+)
+
+// Order actions recorded in the log
+const (
+	ActionSubmit = "SUBMIT"
+	ActionModify = "MODIFY"
+	ActionCancel = "CANCEL"
+)
+
+// Markets an order action is sent to
+const (
+	MarketFutures = "futures"
+	MarketSpot    = "spot"
+)
+
+// Store persists log entries; entries are only ever appended
+type Store interface {
+	CreateOrderAuditEntry(entry *models.OrderAuditEntry) error
+	GetLastOrderAuditEntry() (*models.OrderAuditEntry, error)
+	GetOrderAuditEntries(afterSeq uint64, limit int) ([]*models.OrderAuditEntry, error)
+}
+
+// Entry describes an order action before it is sealed into the log
+type Entry struct {
+	Action          string
+	Market          string
+	Symbol          string
+	ExchangeOrderID string
+	ClientOrderID   string
+	Source          Source
+	Request         interface{} // Order request as sent
+	Response        interface{} // Exchange response, nil on failure
+	Err             error
+}
+
+// payload is the encrypted part of an entry
+type payload struct {
+	Request  interface{} `json:"request"`
+	Response interface{} `json:"response,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+// Log appends hash-chained entries with encrypted payloads. Each hash covers the previous
+// hash and the entry's fields, so editing, removing or reordering entries breaks the chain.
+type Log struct {
+	store Store
+	aead  cipher.AEAD
+
+	mu       sync.Mutex
+	seq      uint64
+	lastHash string
+}
+
+// New opens the log and continues the chain from its last entry. key is 32 bytes, hex encoded.
+func New(store Store, key string) (*Log, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := store.GetLastOrderAuditEntry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order audit log head: %w", err)
+	}
+
+	log := &Log{store: store, aead: aead}
+	if last != nil {
+		log.seq = last.Seq
+		log.lastHash = last.Hash
+	}
+	return log, nil
+}
+
+func newAEAD(key string) (cipher.AEAD, error) {
+	raw, err := hex.DecodeString(key)
+	if err != nil || len(raw) != 32 {
+		return nil, fmt.Errorf("order audit log key must be 32 bytes hex encoded")
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Append seals the entry and adds it to the chain
+func (l *Log) Append(entry *Entry) error {
+	body := payload{Request: entry.Request, Response: entry.Response}
+	if entry.Err != nil {
+		body.Error = entry.Err.Error()
+	}
+	plaintext, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode order audit payload: %w", err)
+	}
+	sealed, err := l.seal(plaintext)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	record := &models.OrderAuditEntry{
+		Seq:             l.seq + 1,
+		Action:          entry.Action,
+		Market:          entry.Market,
+		Symbol:          entry.Symbol,
+		ExchangeOrderID: entry.ExchangeOrderID,
+		ClientOrderID:   entry.ClientOrderID,
+		TriggeredBy:     entry.Source.Trigger,
+		Actor:           entry.Source.Actor,
+		Success:         entry.Err == nil,
+		Payload:         sealed,
+		PrevHash:        l.lastHash,
+		Timestamp:       time.Now().UnixMilli(),
+	}
+	record.Hash = Hash(record)
+
+	if err := l.store.CreateOrderAuditEntry(record); err != nil {
+		return fmt.Errorf("failed to append order audit entry: %w", err)
+	}
+	l.seq = record.Seq
+	l.lastHash = record.Hash
+	return nil
+}
+
+func (l *Log) seal(plaintext []byte) (string, error) {
+	nonce := make([]byte, l.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(l.aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// Decrypt returns the JSON request, response and error of an entry
+func (l *Log) Decrypt(entry *models.OrderAuditEntry) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(entry.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode payload of entry %d: %w", entry.Seq, err)
+	}
+	size := l.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, fmt.Errorf("payload of entry %d is truncated", entry.Seq)
+	}
+	plaintext, err := l.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt payload of entry %d: %w", entry.Seq, err)
+	}
+	return plaintext, nil
+}
+
+// Hash returns the chain hash of an entry from its previous hash and recorded fields
+func Hash(entry *models.OrderAuditEntry) string {
+	fields := []string{
+		entry.PrevHash,
+		strconv.FormatUint(entry.Seq, 10),
+		strconv.FormatInt(entry.Timestamp, 10),
+		entry.Action,
+		entry.Market,
+		entry.Symbol,
+		entry.ExchangeOrderID,
+		entry.ClientOrderID,
+		entry.TriggeredBy,
+		entry.Actor,
+		strconv.FormatBool(entry.Success),
+		entry.Payload,
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify walks the whole chain, checking sequence numbers, links, hashes and that every
+// payload decrypts, and returns the number of entries checked
+func (l *Log) Verify() (int, error) {
+	const pageSize = 1000
+
+	var prev *models.OrderAuditEntry
+	checked := 0
+	for {
+		afterSeq := uint64(0)
+		if prev != nil {
+			afterSeq = prev.Seq
+		}
+		entries, err := l.store.GetOrderAuditEntries(afterSeq, pageSize)
+		if err != nil {
+			return checked, fmt.Errorf("failed to read order audit log: %w", err)
+		}
+
+		for _, entry := range entries {
+			expectedSeq, expectedPrev := uint64(1), ""
+			if prev != nil {
+				expectedSeq, expectedPrev = prev.Seq+1, prev.Hash
+			}
+			if entry.Seq != expectedSeq {
+				return checked, fmt.Errorf("entry %d follows %d: entries are missing", entry.Seq, expectedSeq-1)
+			}
+			if entry.PrevHash != expectedPrev {
+				return checked, fmt.Errorf("entry %d does not link to the previous entry", entry.Seq)
+			}
+			if Hash(entry) != entry.Hash {
+				return checked, fmt.Errorf("entry %d was modified: hash mismatch", entry.Seq)
+			}
+			if _, err := l.Decrypt(entry); err != nil {
+				return checked, err
+			}
+			prev = entry
+			checked++
+		}
+
+		if len(entries) < pageSize {
+			return checked, nil
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package auditlog
+
+import "context"
+
+// What triggered an order action
+const (
+	TriggerStrategy = "strategy" // Strategy signal, grid or funding arbitrage
+	TriggerManual   = "manual"   // Control API or CLI call
+	TriggerRisk     = "risk"     // Risk protection such as delisting or shutdown closes
+	TriggerSystem   = "system"   // Engine housekeeping such as recovery and position sync
+)
+
+// Source is who or what triggered an order action
+type Source struct {
+	Trigger string `json:"trigger"`
+	Actor   string `json:"actor"`
+}
+
+type sourceKey struct{}
+
+// WithSource returns a context whose order actions are attributed to trigger and actor
+func WithSource(ctx context.Context, trigger, actor string) context.Context {
+	return context.WithValue(ctx, sourceKey{}, Source{Trigger: trigger, Actor: actor})
+}
+
+// SourceFrom returns the source set with WithSource, or the engine as a system action
+func SourceFrom(ctx context.Context) Source {
+	if source, ok := ctx.Value(sourceKey{}).(Source); ok {
+		return source
+	}
+	return Source{Trigger: TriggerSystem, Actor: "engine"}
+}
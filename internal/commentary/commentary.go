@@ -0,0 +1,169 @@
+package commentary
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"contract_playground/internal/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+const systemPrompt = "You are a trading desk analyst. Given the context of a closed futures trade, " +
+	"write a short, plain-language post-trade review: why the trade was likely entered and exited, " +
+	"what the indicators suggested, and one lesson for the future. Do not give financial advice. " +
+	"Answer in at most 5 sentences."
+
+// TradeContext holds everything known about a closed trade
+type TradeContext struct {
+	Symbol       string
+	PositionSide string
+	Strategy     string
+	Size         float64
+	EntryPrice   float64
+	ExitPrice    float64
+	PnL          float64
+	OpenTime     time.Time
+	CloseTime    time.Time
+	EntryReason  string
+	ExitReason   string
+	Indicators   map[string]float64
+
+	// Headlines published while the position was open, if a news source is available
+	News []string
+}
+
+// Generator produces human-readable explanations of closed trades using an
+// OpenAI-compatible chat completions API
+type Generator struct {
+	config     config.CommentaryConfig
+	httpClient *http.Client
+	logger     *logrus.Logger
+}
+
+// NewGenerator creates a new commentary generator
+func NewGenerator(cfg config.CommentaryConfig, logger *logrus.Logger) *Generator {
+	return &Generator{
+		config:     cfg,
+		httpClient: &http.Client{Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second},
+		logger:     logger,
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// Explain sends the trade context to the LLM and returns its explanation
+func (g *Generator) Explain(ctx context.Context, trade *TradeContext) (string, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: g.config.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: BuildPrompt(trade)},
+		},
+		MaxTokens:   g.config.MaxTokens,
+		Temperature: 0.2,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode commentary request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create commentary request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.config.APIKey)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("commentary request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("LLM API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var chat chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chat); err != nil {
+		return "", fmt.Errorf("failed to decode commentary response: %w", err)
+	}
+
+	if len(chat.Choices) == 0 || strings.TrimSpace(chat.Choices[0].Message.Content) == "" {
+		return "", fmt.Errorf("LLM API returned no commentary")
+	}
+
+	return strings.TrimSpace(chat.Choices[0].Message.Content), nil
+}
+
+// BuildPrompt renders the trade context as the user message sent to the LLM
+func BuildPrompt(trade *TradeContext) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Symbol: %s (%s)\n", trade.Symbol, trade.PositionSide)
+	fmt.Fprintf(&b, "Strategy: %s\n", trade.Strategy)
+	fmt.Fprintf(&b, "Size: %.6f\n", trade.Size)
+	fmt.Fprintf(&b, "Entry: %.6f at %s\n", trade.EntryPrice, trade.OpenTime.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Exit: %.6f at %s\n", trade.ExitPrice, trade.CloseTime.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Holding time: %s\n", trade.CloseTime.Sub(trade.OpenTime).Round(time.Second))
+	fmt.Fprintf(&b, "Realized PnL: %.4f USDT", trade.PnL)
+	if trade.EntryPrice > 0 {
+		fmt.Fprintf(&b, " (%.2f%%)", (trade.ExitPrice-trade.EntryPrice)/trade.EntryPrice*100)
+	}
+	b.WriteString("\n")
+
+	if trade.EntryReason != "" {
+		fmt.Fprintf(&b, "Entry signal: %s\n", trade.EntryReason)
+	}
+	if trade.ExitReason != "" {
+		fmt.Fprintf(&b, "Exit signal: %s\n", trade.ExitReason)
+	}
+
+	if len(trade.Indicators) > 0 {
+		names := make([]string, 0, len(trade.Indicators))
+		for name := range trade.Indicators {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		b.WriteString("Indicators at exit:\n")
+		for _, name := range names {
+			fmt.Fprintf(&b, "- %s: %.4f\n", name, trade.Indicators[name])
+		}
+	}
+
+	if len(trade.News) > 0 {
+		b.WriteString("News during the trade:\n")
+		for _, headline := range trade.News {
+			fmt.Fprintf(&b, "- %s\n", headline)
+		}
+	}
+
+	return b.String()
+}
@@ -2,17 +2,28 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config represents the application configuration
 type Config struct {
-	Exchange ExchangeConfig `mapstructure:"exchange"`
-	Trading  TradingConfig  `mapstructure:"trading"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Logger   LoggerConfig   `mapstructure:"logger"`
+	Profile       string             `mapstructure:"-"`         // Profile merged over the base file, empty for none
+	Instance      string             `mapstructure:"-"`         // Bot instance of a multi-instance process, empty for a single bot
+	Instances     []string           `mapstructure:"instances"` // Bot instances run by this process; empty runs the base configuration as one bot
+	Exchange      ExchangeConfig     `mapstructure:"exchange"`
+	Trading       TradingConfig      `mapstructure:"trading"`
+	Database      DatabaseConfig     `mapstructure:"database"`
+	Logger        LoggerConfig       `mapstructure:"logger"`
+	Commentary    CommentaryConfig   `mapstructure:"commentary"`
+	Notifications NotificationConfig `mapstructure:"notifications"`
+	Feeds         FeedsConfig        `mapstructure:"feeds"`
+	Spreads       SpreadsConfig      `mapstructure:"spreads"`
+	API           APIConfig          `mapstructure:"api"`
+	AuditLog      AuditLogConfig     `mapstructure:"audit_log"`
 }
 
 // ExchangeConfig holds exchange-specific configuration
@@ -22,21 +33,608 @@ type ExchangeConfig struct {
 	SecretKey string `mapstructure:"secret_key"`
 	Testnet   bool   `mapstructure:"testnet"`
 	BaseURL   string `mapstructure:"base_url"`
+	Market    string `mapstructure:"market"` // usdm (USDT/USDC-margined) or coinm (coin-margined)
+	SpotEnabled bool `mapstructure:"spot_enabled"` // Connect the spot wallet for hedging and the combined exposure view
+	RecvWindowMs            int64 `mapstructure:"recv_window_ms"`             // Validity window of signed requests (Binance max 60000)
+	TimeSyncIntervalSeconds int   `mapstructure:"time_sync_interval_seconds"` // How often to resync the server-time offset (0 = startup only)
+	CredentialsSource string      `mapstructure:"credentials_source"` // config, env, file or vault
+	SecretsFile       string      `mapstructure:"secrets_file"`       // Encrypted secrets file for the file source
+	Vault             VaultConfig `mapstructure:"vault"`
+	RequestTimeoutMs  int                  `mapstructure:"request_timeout_ms"` // Deadline of every REST call (0 = none)
+	CircuitBreaker    CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+	Chaos             ChaosConfig          `mapstructure:"chaos"`
+	RecordFile        string               `mapstructure:"record_file"` // Record every exchange response and stream event here for replay ("" = off)
+}
+
+// CircuitBreakerConfig stops calling the exchange after repeated failures and probes for recovery
+type CircuitBreakerConfig struct {
+	FailureThreshold int `mapstructure:"failure_threshold"` // Consecutive failures that open the breaker (0 = disabled)
+	OpenSeconds      int `mapstructure:"open_seconds"`      // How long calls fail fast before a probe is allowed
+}
+
+// ChaosConfig injects latency, failures, partial fills and stream drops into exchange calls
+// to exercise retries, reconciliation and risk handling. Only allowed on the testnet or in
+// paper trading.
+type ChaosConfig struct {
+	Enabled               bool    `mapstructure:"enabled"`
+	Seed                  int64   `mapstructure:"seed"`                    // Random seed for reproducible runs (0 = time based)
+	LatencyMs             int     `mapstructure:"latency_ms"`              // Delay added to every REST call
+	LatencyJitterMs       int     `mapstructure:"latency_jitter_ms"`       // Extra random delay of up to this much
+	ErrorRate             float64 `mapstructure:"error_rate"`              // Share of REST calls failed before reaching the exchange
+	LostResponseRate      float64 `mapstructure:"lost_response_rate"`      // Share of order placements/cancels that succeed but report an error
+	PartialFillRate       float64 `mapstructure:"partial_fill_rate"`       // Share of filled market orders reported as partially filled
+	DisconnectMeanSeconds int     `mapstructure:"disconnect_mean_seconds"` // Average time between forced stream drops (0 = never)
+}
+
+// VaultConfig locates the exchange credentials in HashiCorp Vault; the token is read from VAULT_TOKEN
+type VaultConfig struct {
+	Address        string `mapstructure:"address"` // Defaults to VAULT_ADDR
+	Path           string `mapstructure:"path"`    // e.g. secret/data/trader/binance (KV v2)
+	Namespace      string `mapstructure:"namespace"`
+	APIKeyField    string `mapstructure:"api_key_field"`
+	SecretKeyField string `mapstructure:"secret_key_field"`
 }
 
 // TradingConfig holds trading strategy and risk management configuration
 type TradingConfig struct {
 	Symbols              []string  `mapstructure:"symbols"`
+	Blacklist            []string  `mapstructure:"blacklist"` // Symbols that never get new entries
 	MaxPositionSize      float64   `mapstructure:"max_position_size"`
 	StopLossPercent      float64   `mapstructure:"stop_loss_percent"`
 	TakeProfitPercent    float64   `mapstructure:"take_profit_percent"`
 	MaxDailyLoss         float64   `mapstructure:"max_daily_loss"`
+	DayTimezone          string    `mapstructure:"day_timezone"` // IANA zone whose midnight resets daily limits, daily PnL and reports
 	TradingInterval      int       `mapstructure:"trading_interval_seconds"`
+	KlineInterval        string    `mapstructure:"kline_interval"`  // Candle interval fed to strategies, e.g. 1m, 15m, 1h
+	EvaluationMode       string    `mapstructure:"evaluation_mode"` // interval (every trading_interval_seconds) or candle_close
+	CandleCloseDelayMs   int       `mapstructure:"candle_close_delay_ms"` // Wait after a candle boundary before the timer fallback evaluates
+	AutoPauseErrors      int       `mapstructure:"auto_pause_errors"` // Consecutive processing errors that pause a symbol's entries (0 = never)
+	MaxConcurrentSymbols int       `mapstructure:"max_concurrent_symbols"` // Symbols processed in parallel per cycle (0 = all)
+	SymbolTimeoutSeconds int       `mapstructure:"symbol_timeout_seconds"` // Deadline for processing one symbol (0 = none)
 	MinOrderValue        float64   `mapstructure:"min_order_value"`
 	MaxLeverage          int       `mapstructure:"max_leverage"`
+	MarginType           string    `mapstructure:"margin_type"`
+	StopWorkingType      string    `mapstructure:"stop_working_type"` // Price for stop/take-profit checks: MARK_PRICE, CONTRACT_PRICE
+	PnLWorkingType       string    `mapstructure:"pnl_working_type"`  // Price for unrealized PnL: MARK_PRICE, CONTRACT_PRICE
 	RiskPerTrade         float64   `mapstructure:"risk_per_trade_percent"`
+	ATRPeriod            int       `mapstructure:"atr_period"` // Candles in the ATR exposed to strategies and risk sizing
 	EnablePaperTrading   bool      `mapstructure:"enable_paper_trading"`
 	Strategy             StrategyConfig `mapstructure:"strategy"`
+	PnLAlerts            PnLAlertConfig `mapstructure:"pnl_alerts"`
+	Correlation          CorrelationConfig `mapstructure:"correlation"`
+	Exposure             ExposureConfig    `mapstructure:"exposure"`
+	Positioning          PositioningConfig `mapstructure:"positioning"`
+	Options              OptionsDataConfig `mapstructure:"options"`
+	OrderFlow            OrderFlowConfig   `mapstructure:"order_flow"`
+	Liquidations         LiquidationConfig `mapstructure:"liquidations"`
+	Drift                DriftConfig       `mapstructure:"drift"`
+	Sessions             SessionConfig     `mapstructure:"sessions"`
+	Cooldown             CooldownConfig    `mapstructure:"cooldown"`
+	Slippage             SlippageConfig    `mapstructure:"slippage"`
+	TakeProfitLadder     TakeProfitLadderConfig `mapstructure:"take_profit_ladder"`
+	Pyramiding           PyramidingConfig  `mapstructure:"pyramiding"`
+	FundingArbitrage     FundingArbitrageConfig `mapstructure:"funding_arbitrage"`
+	Recovery             RecoveryConfig    `mapstructure:"recovery"`
+	PositionSync         PositionSyncConfig `mapstructure:"position_sync"`
+	Health               HealthConfig      `mapstructure:"health"`
+	StaleData            StaleDataConfig   `mapstructure:"stale_data"`
+	AutoCancel           AutoCancelConfig  `mapstructure:"auto_cancel"`
+	Sizing               SizingConfig      `mapstructure:"sizing"`
+	VolTarget            VolTargetConfig   `mapstructure:"vol_target"`
+	IncomeSync           IncomeSyncConfig  `mapstructure:"income_sync"`
+	LeverageRegime       LeverageRegimeConfig `mapstructure:"leverage_regime"`
+	Delisting            DelistingConfig   `mapstructure:"delisting"`
+	Warmup               WarmupConfig      `mapstructure:"warmup"`
+	Journal              JournalConfig     `mapstructure:"journal"`
+	DailyReport          DailyReportConfig `mapstructure:"daily_report"`
+	Scheduler            SchedulerConfig   `mapstructure:"scheduler"`
+	RemoteConfig         RemoteConfig      `mapstructure:"remote_config"`
+	Retention            RetentionConfig   `mapstructure:"retention"`
+	StrategyGuard        StrategyGuardConfig `mapstructure:"strategy_guard"`
+	Shadow               ShadowConfig      `mapstructure:"shadow"`
+	DrawdownScaling      DrawdownScalingConfig `mapstructure:"drawdown_scaling"`
+	ProfitLock           ProfitLockConfig  `mapstructure:"profit_lock"`
+	RiskMetrics          RiskMetricsConfig `mapstructure:"risk_metrics"`
+	Wallets              WalletsConfig     `mapstructure:"wallets"`
+	Rejections           RejectionsConfig  `mapstructure:"rejections"`
+	MarginMonitor        MarginMonitorConfig `mapstructure:"margin_monitor"`
+	ADL                  ADLConfig         `mapstructure:"adl"`
+}
+
+// Position sizing modes
+const (
+	SizingModeRisk             = "risk"              // Risk RiskPerTrade percent of live equity to the stop
+	SizingModeStrategy         = "strategy"          // Keep quantities chosen by the strategy, size the rest by risk
+	SizingModeFixedNotional    = "fixed_notional"    // Same notional on every entry
+	SizingModeFixedFractional  = "fixed_fractional"  // Notional as a percent of live equity
+	SizingModeVolatilityTarget = "volatility_target" // One ATR move equals a percent of equity
+	SizingModeKelly            = "kelly"             // Fractional Kelly from the strategy's recent trades
+)
+
+// SizingConfig selects how entry quantities are computed. Strategies maps a strategy type
+// to overrides of the fields it sets.
+type SizingConfig struct {
+	Mode                    string  `mapstructure:"mode"`
+	Notional                float64 `mapstructure:"notional"`                  // fixed_notional: USDT per entry
+	FractionPercent         float64 `mapstructure:"fraction_percent"`          // fixed_fractional: percent of equity per entry
+	TargetVolatilityPercent float64 `mapstructure:"target_volatility_percent"` // volatility_target: percent of equity per ATR
+	KellyFraction           float64 `mapstructure:"kelly_fraction"`            // kelly: multiplier of the full Kelly fraction
+	KellyLookback           int     `mapstructure:"kelly_lookback"`            // kelly: closed trades used for win rate and payoff
+	KellyMinTrades          int     `mapstructure:"kelly_min_trades"`          // kelly: below this, size by risk instead
+	KellyMaxPercent         float64 `mapstructure:"kelly_max_percent"`         // kelly: cap on the percent of equity risked
+
+	Strategies map[string]SizingConfig `mapstructure:"strategies"`
+}
+
+// StrategyGuardConfig pauses the strategy's entries when its rolling performance over the
+// last closed trades breaches a threshold. Strategies maps a strategy type to overrides.
+type StrategyGuardConfig struct {
+	Enabled         bool    `mapstructure:"enabled"`
+	IntervalMinutes int     `mapstructure:"interval_minutes"`
+	Lookback        int     `mapstructure:"lookback"`       // Closed trades in the rolling window
+	MinTrades       int     `mapstructure:"min_trades"`     // Below this the window is not judged
+	MinExpectancy   float64 `mapstructure:"min_expectancy"` // Mean realized PnL per trade in USDT
+	MaxDrawdown     float64 `mapstructure:"max_drawdown"`   // Peak-to-trough of cumulative PnL in USDT (0 = off)
+
+	Strategies map[string]StrategyGuardConfig `mapstructure:"strategies"`
+}
+
+// For returns the guard of a strategy type: the base settings with its overrides applied.
+// Enabled and the interval are engine-wide.
+func (c StrategyGuardConfig) For(strategyType string) StrategyGuardConfig {
+	resolved := c
+	resolved.Strategies = nil
+
+	override, ok := c.Strategies[strategyType]
+	if !ok {
+		return resolved
+	}
+	if override.Lookback > 0 {
+		resolved.Lookback = override.Lookback
+	}
+	if override.MinTrades > 0 {
+		resolved.MinTrades = override.MinTrades
+	}
+	if override.MinExpectancy != 0 {
+		resolved.MinExpectancy = override.MinExpectancy
+	}
+	if override.MaxDrawdown > 0 {
+		resolved.MaxDrawdown = override.MaxDrawdown
+	}
+	return resolved
+}
+
+// RiskMetricsConfig controls the risk metric snapshot. risk_metrics keeps one row per day
+// with its latest values; Intraday also appends every snapshot to risk_metric_points.
+type RiskMetricsConfig struct {
+	IntervalMinutes int  `mapstructure:"interval_minutes"`
+	Intraday        bool `mapstructure:"intraday"`
+	IntradayDays    int  `mapstructure:"intraday_days"` // Days of intraday points kept (0 = forever)
+}
+
+// WalletsConfig splits the exchange account into virtual wallets, one per strategy name as
+// recorded on positions (manual orders use "manual"). An entry must fit the free capital of
+// its strategy's wallet, so strategies cannot spend each other's budget.
+type WalletsConfig struct {
+	Enabled         bool               `mapstructure:"enabled"`
+	Allocations     []WalletAllocation `mapstructure:"allocations"`      // Opening deposits, recorded once per wallet
+	SnapshotMinutes int                `mapstructure:"snapshot_minutes"` // Equity curve point interval
+	RetentionDays   int                `mapstructure:"retention_days"`   // Days of equity curve points kept (0 = forever)
+}
+
+// WalletAllocation is the opening deposit of a strategy's wallet
+type WalletAllocation struct {
+	Strategy string  `mapstructure:"strategy"`
+	Amount   float64 `mapstructure:"amount"` // USDT
+}
+
+// Margin monitor stage actions, from least to most severe; each stage also applies the
+// effects of the less severe ones
+const (
+	MarginActionWarn        = "warn"         // Notify only
+	MarginActionStopEntries = "stop_entries" // Take no new entries
+	MarginActionReduce      = "reduce"       // Close the largest losing position, one per cooldown
+	MarginActionFlatten     = "flatten"      // Close every position
+)
+
+// MarginActions lists the margin monitor stage actions in order of severity
+var MarginActions = []string{MarginActionWarn, MarginActionStopEntries, MarginActionReduce, MarginActionFlatten}
+
+// MarginMonitorConfig polls the account margin ratio, the maintenance margin over the margin
+// balance at which the exchange liquidates at 100%, and applies the action of the highest
+// stage it has reached
+type MarginMonitorConfig struct {
+	Enabled         bool                `mapstructure:"enabled"`
+	IntervalSeconds int                 `mapstructure:"interval_seconds"`
+	CooldownSeconds int                 `mapstructure:"cooldown_seconds"` // Wait between closes so the ratio reflects the last one
+	Stages          []MarginStageConfig `mapstructure:"stages"`
+}
+
+// MarginStageConfig applies Action once the margin ratio reaches RatioPercent
+type MarginStageConfig struct {
+	RatioPercent float64 `mapstructure:"ratio_percent"`
+	Action       string  `mapstructure:"action"`
+}
+
+// ADLConfig polls the auto-deleveraging quantile of every position side and alerts when one
+// reaches AlertQuantile, so it can be reduced before the exchange deleverages it
+type ADLConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	IntervalSeconds int  `mapstructure:"interval_seconds"` // The exchange refreshes quantiles every 30 seconds
+	AlertQuantile   int  `mapstructure:"alert_quantile"`   // 1-4; 4 is the highest bucket
+	RetentionDays   int  `mapstructure:"retention_days"`   // Days of recorded changes kept (0 = forever)
+}
+
+// RejectionsConfig controls how orders refused by the exchange are recorded and fixed.
+// Remediation resizes orders below the minimum notional, re-rounds quantities and prices to
+// the symbol's step and tick size and drops the reduce-only flag in hedge mode, where the
+// position side already makes the order closing.
+type RejectionsConfig struct {
+	Record           bool    `mapstructure:"record"`             // Save rejected orders with their class as REJECTED rows
+	Remediate        bool    `mapstructure:"remediate"`          // Retry fixable rejections automatically
+	MaxRetries       int     `mapstructure:"max_retries"`        // Fixed retries per order
+	HourlyBudget     int     `mapstructure:"hourly_budget"`      // Fixed retries across all orders per rolling hour
+	MaxResizePercent float64 `mapstructure:"max_resize_percent"` // Largest quantity increase to reach the minimum notional
+}
+
+// Profit lock actions once the daily target is reached
+const (
+	ProfitLockActionStop   = "stop"   // Take no new entries until the next day
+	ProfitLockActionReduce = "reduce" // Multiply new position sizes by ReduceScale
+)
+
+// ProfitLockConfig is the upside counterpart of max_daily_loss: once the day's net realized
+// PnL reaches Target, new entries stop or shrink until the next midnight. Exits and open
+// positions are unaffected.
+type ProfitLockConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Target      float64 `mapstructure:"target"` // Net realized PnL in USDT that locks the day
+	Action      string  `mapstructure:"action"`
+	ReduceScale float64 `mapstructure:"reduce_scale"` // Size multiplier of the reduce action
+	Timezone    string  `mapstructure:"timezone"`     // IANA zone whose midnight starts a new day; empty follows day_timezone
+}
+
+// DrawdownScalingConfig reduces every new position size while account equity is below its
+// high-water mark and restores it as equity recovers. Schedule maps drawdowns to size
+// multipliers; between points the multiplier is interpolated linearly from 1 at no drawdown.
+type DrawdownScalingConfig struct {
+	Enabled        bool                `mapstructure:"enabled"`
+	LookbackDays   int                 `mapstructure:"lookback_days"` // Window of the high-water mark (0 = all snapshots)
+	RefreshMinutes int                 `mapstructure:"refresh_minutes"`
+	Schedule       []DrawdownStepConfig `mapstructure:"schedule"`
+}
+
+// DrawdownStepConfig multiplies sizes by Scale once equity is DrawdownPercent below the mark
+type DrawdownStepConfig struct {
+	DrawdownPercent float64 `mapstructure:"drawdown_percent"`
+	Scale           float64 `mapstructure:"scale"`
+}
+
+// VolTargetConfig scales every new position size so that the portfolio's realized volatility,
+// estimated from account equity snapshots, moves toward a target
+type VolTargetConfig struct {
+	Enabled        bool    `mapstructure:"enabled"`
+	TargetPercent  float64 `mapstructure:"target_percent"` // Annualized volatility aimed for
+	WindowHours    int     `mapstructure:"window_hours"`
+	BucketMinutes  int     `mapstructure:"bucket_minutes"` // Equity sampling period for returns
+	RefreshMinutes int     `mapstructure:"refresh_minutes"`
+	MinSamples     int     `mapstructure:"min_samples"` // Returns needed before sizes are scaled
+	MinScale       float64 `mapstructure:"min_scale"`
+	MaxScale       float64 `mapstructure:"max_scale"`
+}
+
+// For returns the sizing of a strategy type: the base settings with its overrides applied
+func (c SizingConfig) For(strategyType string) SizingConfig {
+	resolved := c
+	resolved.Strategies = nil
+
+	override, ok := c.Strategies[strategyType]
+	if !ok {
+		return resolved
+	}
+	if override.Mode != "" {
+		resolved.Mode = override.Mode
+	}
+	if override.Notional > 0 {
+		resolved.Notional = override.Notional
+	}
+	if override.FractionPercent > 0 {
+		resolved.FractionPercent = override.FractionPercent
+	}
+	if override.TargetVolatilityPercent > 0 {
+		resolved.TargetVolatilityPercent = override.TargetVolatilityPercent
+	}
+	if override.KellyFraction > 0 {
+		resolved.KellyFraction = override.KellyFraction
+	}
+	if override.KellyLookback > 0 {
+		resolved.KellyLookback = override.KellyLookback
+	}
+	if override.KellyMinTrades > 0 {
+		resolved.KellyMinTrades = override.KellyMinTrades
+	}
+	if override.KellyMaxPercent > 0 {
+		resolved.KellyMaxPercent = override.KellyMaxPercent
+	}
+	return resolved
+}
+
+// StaleDataConfig blocks entries and alerts when a symbol's market data stops updating
+type StaleDataConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	MaxAgeSeconds int  `mapstructure:"max_age_seconds"` // Age of the last kline refresh above which data is stale
+}
+
+// AutoCancelConfig keeps the exchange's cancel-all countdown running for each symbol, so
+// resting orders are canceled by the exchange when the process stops refreshing it
+type AutoCancelConfig struct {
+	Enabled          bool           `mapstructure:"enabled"`
+	CountdownSeconds int            `mapstructure:"countdown_seconds"` // Time without a refresh before orders are canceled
+	HeartbeatSeconds int            `mapstructure:"heartbeat_seconds"` // Refresh interval
+	Symbols          map[string]int `mapstructure:"symbols"`           // Countdown per symbol overriding countdown_seconds; 0 disables it
+}
+
+// CountdownFor returns the countdown of a symbol in seconds, 0 when disabled for it
+func (c AutoCancelConfig) CountdownFor(symbol string) int {
+	// Map keys are lower-cased when the config is loaded
+	if seconds, ok := c.Symbols[strings.ToLower(symbol)]; ok {
+		return seconds
+	}
+	return c.CountdownSeconds
+}
+
+// WarmupConfig preloads candles on start so strategies decide with complete indicators
+type WarmupConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	BufferCandles int  `mapstructure:"buffer_candles"` // Candles fetched beyond the strategy's longest period
+}
+
+// JournalConfig snapshots what the strategy saw on every entry and exit for post-trade review
+type JournalConfig struct {
+	Enabled     bool `mapstructure:"enabled"`
+	KlineWindow int  `mapstructure:"kline_window"` // Most recent candles stored with each entry
+}
+
+// DailyReportConfig sends a summary of the previous UTC day, optionally benchmarking each
+// symbol's strategy returns against buying and holding it
+type DailyReportConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	Hour          int  `mapstructure:"hour"`           // UTC hour the report on the last complete day is sent
+	BenchmarkDays int  `mapstructure:"benchmark_days"` // Days compared with buy-and-hold; 0 disables the benchmark
+}
+
+// Periodic jobs run by the scheduler; trading.scheduler.jobs overrides their schedules
+const (
+	JobIncomeSync      = "income_sync"      // Income history sync and reconciliation
+	JobSymbolRefresh   = "symbol_refresh"   // Contract specs reload and delisting checks
+	JobDriftCheck      = "drift_check"      // Exchange settings drift detection
+	JobDailyReport     = "daily_report"     // Previous day's report
+	JobRiskMetrics     = "risk_metrics"     // Risk metric snapshot
+	JobAccountSnapshot = "account_snapshot" // Account balance snapshot
+	JobRemoteConfig    = "remote_config"    // Poll for risk limit and strategy parameter changes
+	JobStrategyGuard   = "strategy_guard"   // Rolling strategy performance check
+	JobDrawdownScaling = "drawdown_scaling" // High-water mark and drawdown size scale refresh
+	JobWalletSnapshot  = "wallet_snapshot"  // Strategy wallet equity curve point
+	JobMarginMonitor   = "margin_monitor"   // Account margin ratio check and staged de-risking
+	JobADLMonitor      = "adl_monitor"      // Auto-deleveraging quantile check
+)
+
+// SchedulerJobs lists the jobs whose schedules can be overridden
+var SchedulerJobs = []string{JobIncomeSync, JobSymbolRefresh, JobDriftCheck, JobDailyReport, JobRiskMetrics, JobAccountSnapshot, JobRemoteConfig, JobStrategyGuard, JobDrawdownScaling, JobWalletSnapshot, JobMarginMonitor, JobADLMonitor}
+
+// SchedulerConfig holds the periodic job scheduler. Jobs maps a job name to a cron
+// expression (UTC) or "@every <duration>"; jobs not listed keep the interval of their own
+// section.
+type SchedulerConfig struct {
+	JitterSeconds int               `mapstructure:"jitter_seconds"` // Random delay added to each run
+	Jobs          map[string]string `mapstructure:"jobs"`
+}
+
+// RemoteConfig keeps risk limits and strategy parameters in the trading_configs and
+// strategies tables so they can be edited through the API. The rows are seeded from this
+// file on first start; afterwards the database wins and every change is versioned.
+type RemoteConfig struct {
+	Enabled        bool   `mapstructure:"enabled"`
+	RiskLimitsName string `mapstructure:"risk_limits_name"` // trading_configs row holding the risk limits
+	StrategyName   string `mapstructure:"strategy_name"`    // strategies row holding the strategy parameters
+	PollSeconds    int    `mapstructure:"poll_seconds"`     // How often changes made elsewhere are picked up
+}
+
+// RetentionConfig bounds the market data the engine keeps in memory and moves tick
+// persistence off the market data loop to a batching writer
+type RetentionConfig struct {
+	KlineWindow            int  `mapstructure:"kline_window"`             // Klines kept per symbol; at least what the strategy needs
+	MaxLiquidationEvents   int  `mapstructure:"max_liquidation_events"`   // Liquidations kept per symbol within the window
+	PersistMarketData      bool `mapstructure:"persist_market_data"`      // Store price ticks in market_data
+	PersistIntervalSeconds int  `mapstructure:"persist_interval_seconds"` // Minimum spacing of stored ticks per symbol, 0 stores every update
+	WriterQueueSize        int  `mapstructure:"writer_queue_size"`        // Ticks buffered for the database writer
+	WriterBatchSize        int  `mapstructure:"writer_batch_size"`        // Ticks inserted per statement
+	WriterFlushMillis      int  `mapstructure:"writer_flush_ms"`          // Longest a tick waits for its batch
+	EnqueueTimeoutMillis   int  `mapstructure:"enqueue_timeout_ms"`       // How long a full queue blocks the market data loop before the tick is dropped
+}
+
+// DelistingConfig watches exchange info for traded symbols leaving TRADING status or getting
+// a settlement date, blocks their entries and closes their positions before the deadline
+type DelistingConfig struct {
+	Enabled          bool `mapstructure:"enabled"`
+	IntervalMinutes  int  `mapstructure:"interval_minutes"`
+	CloseBeforeHours int  `mapstructure:"close_before_hours"` // Close positions this long before settlement
+}
+
+// LeverageRegimeConfig adjusts each symbol's leverage to its volatility regime: the
+// percentile of the current realized volatility among its values over the lookback.
+// Low volatility uses MaxLeverage, high volatility MinLeverage and normal their midpoint.
+type LeverageRegimeConfig struct {
+	Enabled         bool    `mapstructure:"enabled"`
+	IntervalMinutes int     `mapstructure:"interval_minutes"`
+	KlineInterval   string  `mapstructure:"kline_interval"`   // Candles the volatility is measured on
+	Window          int     `mapstructure:"window"`           // Returns per realized volatility value
+	Lookback        int     `mapstructure:"lookback"`         // Volatility values the percentile is ranked against
+	LowPercentile   float64 `mapstructure:"low_percentile"`   // At or below: low volatility regime
+	HighPercentile  float64 `mapstructure:"high_percentile"`  // At or above: high volatility regime
+	MinLeverage     int     `mapstructure:"min_leverage"`
+	MaxLeverage     int     `mapstructure:"max_leverage"` // At most trading.max_leverage
+}
+
+// HealthConfig sets when the health endpoints consider a stream stale
+type HealthConfig struct {
+	StreamMaxAgeSeconds int `mapstructure:"stream_max_age_seconds"` // Max age of the last mark price stream event
+}
+
+// RecoveryConfig controls the startup reconciliation of DB state with the exchange
+type RecoveryConfig struct {
+	Enabled      bool `mapstructure:"enabled"`
+	AdoptUnknown bool `mapstructure:"adopt_unknown"` // Record unknown long positions of traded symbols instead of only alerting
+}
+
+// PositionSyncConfig controls the periodic correction of DB positions from the exchange
+type PositionSyncConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	IntervalSeconds int  `mapstructure:"interval_seconds"`
+}
+
+// FundingArbitrageConfig holds the delta-neutral funding capture (perp short + spot long).
+// Its symbols must not be traded by the strategy: in one-way mode the legs would net out.
+type FundingArbitrageConfig struct {
+	Enabled              bool     `mapstructure:"enabled"`
+	Symbols              []string `mapstructure:"symbols"`                // Perpetual symbols; the spot leg uses the same symbol
+	EntryAnnualizedRate  float64  `mapstructure:"entry_annualized_rate"`  // Open when annualized funding reaches this percent
+	ExitAnnualizedRate   float64  `mapstructure:"exit_annualized_rate"`   // Unwind when annualized funding decays below this percent
+	NotionalUSDT         float64  `mapstructure:"notional_usdt"`          // Size of each leg
+	FundingIntervalHours float64  `mapstructure:"funding_interval_hours"` // Hours between funding payments
+	MaxBasisPercent      float64  `mapstructure:"max_basis_percent"`      // Skip entries when perp and spot differ by more (0 disables)
+	Leverage             int      `mapstructure:"leverage"`               // Leverage of the perp leg
+	CheckIntervalMinutes int      `mapstructure:"check_interval_minutes"`
+}
+
+// PyramidingConfig holds the rules for scaling into an open position
+type PyramidingConfig struct {
+	Enabled        bool    `mapstructure:"enabled"`
+	MaxAddOns      int     `mapstructure:"max_add_ons"`     // Adds allowed after the initial entry
+	SpacingPercent float64 `mapstructure:"spacing_percent"` // Minimum favorable move from the last fill before adding
+	SizeDecay      float64 `mapstructure:"size_decay"`      // Each add is the signal size times decay^n (0-1]
+}
+
+// TakeProfitLadderConfig holds the default partial take-profit ladder for new positions.
+// Strategies may override it per signal.
+type TakeProfitLadderConfig struct {
+	Enabled                 bool                    `mapstructure:"enabled"`
+	Levels                  []TakeProfitLevelConfig `mapstructure:"levels"`
+	TrailingCallbackPercent float64                 `mapstructure:"trailing_callback_percent"` // Runner trailing stop (0 = none)
+}
+
+// TakeProfitLevelConfig closes a fraction of the initial size once price moves by Percent
+type TakeProfitLevelConfig struct {
+	Percent  float64 `mapstructure:"percent"`
+	Fraction float64 `mapstructure:"fraction"`
+}
+
+// SlippageConfig holds the pre-trade slippage guard for market entries
+type SlippageConfig struct {
+	Enabled        bool    `mapstructure:"enabled"`
+	MaxSlippageBps float64 `mapstructure:"max_slippage_bps"` // Expected fill vs signal price
+	MaxSpreadBps   float64 `mapstructure:"max_spread_bps"`   // Bid/ask spread; 0 disables the spread check
+	Action         string  `mapstructure:"action"`           // reject or limit (IOC limit order at the slippage bound)
+	BookDepth      int     `mapstructure:"book_depth"`       // Order book levels used to estimate the fill
+}
+
+// CooldownConfig holds per-symbol anti-churn limits
+type CooldownConfig struct {
+	MaxConsecutiveLosses int `mapstructure:"max_consecutive_losses"` // Losing exits in a row before the symbol pauses (0 disables)
+	CooldownMinutes      int `mapstructure:"cooldown_minutes"`       // Pause length after the loss streak
+	MinReentryMinutes    int `mapstructure:"min_reentry_minutes"`    // Minimum time between entries on a symbol (0 disables)
+}
+
+// SessionConfig holds trading hours and blackout windows. Outside a session or inside a
+// blackout the engine stops opening new positions but keeps managing exits.
+type SessionConfig struct {
+	Enabled                bool             `mapstructure:"enabled"`
+	Timezone               string           `mapstructure:"timezone"`                 // IANA zone for trading hours and daily blackouts
+	TradingHours           []string         `mapstructure:"trading_hours"`            // Daily "HH:MM-HH:MM" windows; empty means all day
+	PauseWeekends          bool             `mapstructure:"pause_weekends"`           // No new positions on Saturday and Sunday
+	DailyBlackouts         []string         `mapstructure:"daily_blackouts"`          // Recurring daily "HH:MM-HH:MM" windows
+	Blackouts              []BlackoutWindow `mapstructure:"blackouts"`                // One-off windows, e.g. around CPI or FOMC releases
+	FundingBlackoutMinutes int              `mapstructure:"funding_blackout_minutes"` // Minutes before and after each funding timestamp (0 disables)
+}
+
+// BlackoutWindow is a one-off period without new entries
+type BlackoutWindow struct {
+	Name  string `mapstructure:"name"`
+	Start string `mapstructure:"start"` // RFC3339
+	End   string `mapstructure:"end"`   // RFC3339
+}
+
+// DriftConfig holds exchange settings drift detection configuration
+type DriftConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	IntervalMinutes int  `mapstructure:"interval_minutes"`
+	AutoReconcile   bool `mapstructure:"auto_reconcile"` // Restore configured values instead of only alerting
+}
+
+// IncomeSyncConfig controls the periodic copy of the futures income history and its
+// reconciliation against PnL recorded on closed positions
+type IncomeSyncConfig struct {
+	Enabled         bool    `mapstructure:"enabled"`
+	IntervalMinutes int     `mapstructure:"interval_minutes"`
+	BackfillDays    int     `mapstructure:"backfill_days"` // History fetched when the income table is empty
+	Tolerance       float64 `mapstructure:"tolerance"`     // Largest difference in USDT that is not flagged
+}
+
+// OrderFlowConfig controls trade-flow metrics from the aggregate trades stream
+type OrderFlowConfig struct {
+	Enabled            bool    `mapstructure:"enabled"`
+	WindowSeconds      int     `mapstructure:"window_seconds"`       // Rolling window of the metrics given to strategies
+	LargeTradeNotional float64 `mapstructure:"large_trade_notional"` // USDT value from which a trade counts as large
+	Persist            bool    `mapstructure:"persist"`              // Store one sample per symbol and minute for backtesting
+}
+
+// LiquidationConfig controls liquidation feed monitoring
+type LiquidationConfig struct {
+	Enabled       bool    `mapstructure:"enabled"`
+	WindowSeconds int     `mapstructure:"window_seconds"` // Rolling window of the liquidation volume
+	AlarmNotional float64 `mapstructure:"alarm_notional"` // USDT liquidated within the window that blocks entries, 0 disables
+}
+
+// PositioningConfig holds open interest and long/short ratio collection settings
+type PositioningConfig struct {
+	Enabled         bool   `mapstructure:"enabled"`
+	IntervalSeconds int    `mapstructure:"interval_seconds"`
+	Period          string `mapstructure:"period"` // Long/short ratio period: 5m, 15m, 30m, 1h, 2h, 4h, 6h, 12h, 1d
+}
+
+// OptionsDataConfig holds implied volatility and skew collection from an options venue
+type OptionsDataConfig struct {
+	Enabled              bool    `mapstructure:"enabled"`
+	Venue                string  `mapstructure:"venue"` // deribit
+	IntervalSeconds      int     `mapstructure:"interval_seconds"`
+	TenorDays            int     `mapstructure:"tenor_days"`             // Expiry the volatility is read from
+	SkewMoneynessPercent float64 `mapstructure:"skew_moneyness_percent"` // Distance of the OTM put and call strikes from the underlying
+	TimeoutSeconds       int     `mapstructure:"timeout_seconds"`
+}
+
+// CorrelationConfig holds rolling correlation matrix and correlated exposure settings
+type CorrelationConfig struct {
+	Enabled               bool    `mapstructure:"enabled"`
+	WindowHours           int     `mapstructure:"window_hours"`
+	BucketMinutes         int     `mapstructure:"bucket_minutes"`
+	RefreshMinutes        int     `mapstructure:"refresh_minutes"`
+	Limit                 float64 `mapstructure:"limit"`                   // Correlation at which symbols count as one exposure
+	MaxCorrelatedExposure float64 `mapstructure:"max_correlated_exposure"` // USDT; 0 disables the check
+}
+
+// ExposureConfig limits the portfolio built from live positions, in USDT notional; 0 disables
+// a limit
+type ExposureConfig struct {
+	MaxNetDelta      float64            `mapstructure:"max_net_delta"`      // Long minus short notional, in either direction
+	MaxGrossExposure float64            `mapstructure:"max_gross_exposure"` // Long plus short notional
+	MaxQuoteExposure map[string]float64 `mapstructure:"max_quote_exposure"` // Gross notional per quote asset, e.g. USDT, USDC
+}
+
+// PnLAlertConfig holds unrealized PnL alert bands, in percent of position entry value
+type PnLAlertConfig struct {
+	Enabled    bool      `mapstructure:"enabled"`
+	Bands      []float64 `mapstructure:"bands"`
+	Hysteresis float64   `mapstructure:"hysteresis_percent"` // Distance PnL must retreat before a band re-arms
 }
 
 // StrategyConfig holds trading strategy parameters
@@ -44,12 +642,129 @@ type StrategyConfig struct {
 	Type                string                 `mapstructure:"type"`
 	Parameters          map[string]interface{} `mapstructure:"parameters"`
 	EnableSignalFilters bool                   `mapstructure:"enable_signal_filters"`
+	Sandbox             SandboxConfig          `mapstructure:"sandbox"`
+	State               StrategyStateConfig    `mapstructure:"state"`
+	Filters             SignalFilterConfig     `mapstructure:"filters"` // Applied when EnableSignalFilters is set
+}
+
+// ShadowConfig runs a candidate strategy beside the live one on the same market data. The
+// candidate and a control copy of the live strategy each trade a simulated book, so their
+// signals and outcomes can be compared before the candidate goes live.
+type ShadowConfig struct {
+	Enabled         bool                   `mapstructure:"enabled"`
+	Name            string                 `mapstructure:"name"`             // Label of the candidate in signals and reports
+	Type            string                 `mapstructure:"type"`             // Candidate strategy type; empty runs the live type
+	Parameters      map[string]interface{} `mapstructure:"parameters"`       // Candidate parameters, over the live ones when the type is the same
+	InitialBalance  float64                `mapstructure:"initial_balance"`  // Simulated USDT per symbol and variant
+	PositionPercent float64                `mapstructure:"position_percent"` // Percent of the simulated balance per entry
+	FeeRate         float64                `mapstructure:"fee_rate"`         // Fee per simulated fill as a fraction of the notional
+	RecordSignals   bool                   `mapstructure:"record_signals"`   // Store every non-HOLD signal in shadow_signals
+}
+
+// Candidate returns the strategy the shadow runs: its own type and parameters, with the live
+// parameters underneath when it runs the live type
+func (c ShadowConfig) Candidate(live StrategyConfig) StrategyConfig {
+	candidate := StrategyConfig{Type: c.Type, Parameters: make(map[string]interface{})}
+	if candidate.Type == "" || candidate.Type == live.Type {
+		candidate.Type = live.Type
+		for key, value := range live.Parameters {
+			candidate.Parameters[key] = value
+		}
+	}
+	for key, value := range c.Parameters {
+		candidate.Parameters[key] = value
+	}
+	return candidate
+}
+
+// SignalFilterConfig configures the filters an entry signal passes between the strategy and
+// the risk manager. Zero values disable a filter. Strategies maps a strategy type to
+// overrides of the fields it sets.
+type SignalFilterConfig struct {
+	MinQuoteVolume24h float64  `mapstructure:"min_quote_volume_24h"` // USDT traded over 24h
+	MinCandleVolume   float64  `mapstructure:"min_candle_volume"`    // USDT traded in the last closed candle
+	MaxSpreadBps      float64  `mapstructure:"max_spread_bps"`       // Bid/ask spread from the order book
+	TrendInterval     string   `mapstructure:"trend_interval"`       // Higher timeframe whose trend entries must follow, e.g. 4h
+	TrendPeriod       int      `mapstructure:"trend_period"`         // SMA period on the higher timeframe
+	AllowedHours      []string `mapstructure:"allowed_hours"`        // Daily "HH:MM-HH:MM" windows; empty allows all day
+	Timezone          string   `mapstructure:"timezone"`             // IANA zone of allowed_hours
+	CooldownMinutes   int      `mapstructure:"cooldown_minutes"`     // Minimum time between accepted signals per symbol
+
+	Strategies map[string]SignalFilterConfig `mapstructure:"strategies"`
+}
+
+// For returns the filters of a strategy type: the base settings with its overrides applied
+func (c SignalFilterConfig) For(strategyType string) SignalFilterConfig {
+	resolved := c
+	resolved.Strategies = nil
+
+	override, ok := c.Strategies[strategyType]
+	if !ok {
+		return resolved
+	}
+	if override.MinQuoteVolume24h > 0 {
+		resolved.MinQuoteVolume24h = override.MinQuoteVolume24h
+	}
+	if override.MinCandleVolume > 0 {
+		resolved.MinCandleVolume = override.MinCandleVolume
+	}
+	if override.MaxSpreadBps > 0 {
+		resolved.MaxSpreadBps = override.MaxSpreadBps
+	}
+	if override.TrendInterval != "" {
+		resolved.TrendInterval = override.TrendInterval
+	}
+	if override.TrendPeriod > 0 {
+		resolved.TrendPeriod = override.TrendPeriod
+	}
+	if len(override.AllowedHours) > 0 {
+		resolved.AllowedHours = override.AllowedHours
+	}
+	if override.Timezone != "" {
+		resolved.Timezone = override.Timezone
+	}
+	if override.CooldownMinutes > 0 {
+		resolved.CooldownMinutes = override.CooldownMinutes
+	}
+	return resolved
+}
+
+// StrategyStateConfig persists in-memory strategy state so a restart does not change behavior
+type StrategyStateConfig struct {
+	Enabled             bool `mapstructure:"enabled"`
+	SaveIntervalSeconds int  `mapstructure:"save_interval_seconds"`
+	MaxAgeMinutes       int  `mapstructure:"max_age_minutes"` // Older saved state is ignored on start, 0 never expires
+}
+
+// SandboxConfig holds resource limits for strategy evaluation
+type SandboxConfig struct {
+	Enabled                bool `mapstructure:"enabled"`
+	TimeoutMs              int  `mapstructure:"timeout_ms"`               // Per-evaluation timeout
+	MaxConsecutiveTimeouts int  `mapstructure:"max_consecutive_timeouts"` // Timeouts/panics before quarantine
+	QuarantineSeconds      int  `mapstructure:"quarantine_seconds"`
+	MemoryLimitMB          int  `mapstructure:"memory_limit_mb"`   // External processes only
+	CPULimitSeconds        int  `mapstructure:"cpu_limit_seconds"` // External processes only
 }
 
 // DatabaseConfig holds database connection configuration
 type DatabaseConfig struct {
-	MySQL MySQLConfig `mapstructure:"mysql"`
-	Redis RedisConfig `mapstructure:"redis"`
+	MySQL      MySQLConfig              `mapstructure:"mysql"`
+	Redis      RedisConfig              `mapstructure:"redis"`
+	Migrations MigrationsConfig         `mapstructure:"migrations"`
+	Monitoring DatabaseMonitoringConfig `mapstructure:"monitoring"`
+}
+
+// DatabaseMonitoringConfig controls query latency metrics, slow query logging and the
+// connection pool soft cap
+type DatabaseMonitoringConfig struct {
+	SlowQueryMs        int     `mapstructure:"slow_query_ms"`         // Log statements at least this slow; 0 disables
+	PoolSoftCapPercent float64 `mapstructure:"pool_soft_cap_percent"` // Warn when this share of max_open_conns is in use
+	SampleSeconds      int     `mapstructure:"sample_seconds"`        // Pool sampling interval
+}
+
+// MigrationsConfig controls how schema migrations are applied at startup
+type MigrationsConfig struct {
+	AutoApply bool `mapstructure:"auto_apply"` // Apply pending migrations on start instead of refusing to start
 }
 
 // MySQLConfig holds MySQL-specific configuration
@@ -75,23 +790,154 @@ type LoggerConfig struct {
 	Output string `mapstructure:"output"`
 }
 
-// Load reads and parses the configuration from file and environment variables
+// CommentaryConfig holds the optional LLM trade commentary configuration
+type CommentaryConfig struct {
+	Enabled        bool   `mapstructure:"enabled"`
+	Endpoint       string `mapstructure:"endpoint"` // OpenAI-compatible chat completions URL
+	APIKey         string `mapstructure:"api_key"`
+	Model          string `mapstructure:"model"`
+	MaxTokens      int    `mapstructure:"max_tokens"`
+	TimeoutSeconds int    `mapstructure:"timeout_seconds"`
+}
+
+// NotificationConfig holds notification delivery configuration
+type NotificationConfig struct {
+	WebhookURLs      []string     `mapstructure:"webhook_urls"`
+	EventWebhookURLs []string     `mapstructure:"event_webhook_urls"` // Receive position opened/closed events through the outbox
+	TimeoutSeconds   int          `mapstructure:"timeout_seconds"`
+	Outbox           OutboxConfig `mapstructure:"outbox"`
+}
+
+// OutboxConfig controls asynchronous notification delivery through the outbox table
+type OutboxConfig struct {
+	Enabled          bool `mapstructure:"enabled"`
+	PollSeconds      int  `mapstructure:"poll_seconds"`
+	BatchSize        int  `mapstructure:"batch_size"`
+	MaxAttempts      int  `mapstructure:"max_attempts"`       // Marked dead after this many failed deliveries
+	RetryBaseSeconds int  `mapstructure:"retry_base_seconds"` // First retry delay, doubled on every further failure
+	RetryMaxSeconds  int  `mapstructure:"retry_max_seconds"`  // Longest retry delay
+	RetentionDays    int  `mapstructure:"retention_days"`     // Delivered messages are deleted after this many days
+}
+
+// FeedsConfig holds news/sentiment feed configuration
+type FeedsConfig struct {
+	Enabled             bool     `mapstructure:"enabled"`
+	PollIntervalSeconds int      `mapstructure:"poll_interval_seconds"`
+	WindowHours         int      `mapstructure:"window_hours"`
+	HalfLifeHours       float64  `mapstructure:"half_life_hours"`
+	MinBuySentiment     float64  `mapstructure:"min_buy_sentiment"` // Buys are skipped below this score
+	MinItems            int      `mapstructure:"min_items"`         // Items required before the buy filter applies
+	CryptoPanicToken    string   `mapstructure:"cryptopanic_token"`
+	RSSURLs             []string `mapstructure:"rss_urls"`
+}
+
+// SpreadsConfig holds the cross-exchange spread monitor configuration
+type SpreadsConfig struct {
+	Enabled         bool     `mapstructure:"enabled"`
+	Venues          []string `mapstructure:"venues"` // Other exchanges compared against Binance: bybit, okx
+	IntervalSeconds int      `mapstructure:"interval_seconds"`
+	AlertBps        float64  `mapstructure:"alert_bps"` // Absolute spread that raises an alert
+	ResetBps        float64  `mapstructure:"reset_bps"` // Spread the alert re-arms below
+	TimeoutSeconds  int      `mapstructure:"timeout_seconds"`
+}
+
+// APIConfig holds the HTTP API server configuration. Once any credential is configured
+// every request must present one; without credentials the API is read-only.
+type APIConfig struct {
+	Enabled    bool           `mapstructure:"enabled"`
+	ListenAddr string         `mapstructure:"listen_addr"`
+	AuthToken  string         `mapstructure:"auth_token"` // Bearer token with the admin role
+	Keys       []APIKeyConfig `mapstructure:"keys"`
+	JWT        JWTConfig      `mapstructure:"jwt"`
+}
+
+// Roles of API callers; each role includes the permissions of the ones before it
+const (
+	APIRoleViewer   = "viewer"   // Read status, positions, orders and reports
+	APIRoleOperator = "operator" // Pause and resume symbols, place, modify and cancel orders, close positions
+	APIRoleAdmin    = "admin"    // Change configuration
+)
+
+// APIKeyConfig is a named bearer key with a role
+type APIKeyConfig struct {
+	Name string `mapstructure:"name"`
+	Key  string `mapstructure:"key"`
+	Role string `mapstructure:"role"`
+}
+
+// JWTConfig accepts HS256 bearer tokens whose "sub" claim names the caller and "role" claim
+// holds its role
+type JWTConfig struct {
+	Secret string `mapstructure:"secret"` // Empty disables JWT authentication
+	Issuer string `mapstructure:"issuer"` // Required "iss" claim when set
+}
+
+// AuditLogConfig holds the append-only, hash-chained log of every order action
+type AuditLogConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	EncryptionKey string `mapstructure:"encryption_key"` // 32 bytes hex encoded; encrypts order requests and results
+}
+
+// Strategy evaluation modes for the trading loop
+const (
+	EvaluationModeInterval    = "interval"
+	EvaluationModeCandleClose = "candle_close"
+)
+
+// KlineIntervals maps the supported candle intervals to their durations
+var KlineIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"3m":  3 * time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"2h":  2 * time.Hour,
+	"4h":  4 * time.Hour,
+	"6h":  6 * time.Hour,
+	"8h":  8 * time.Hour,
+	"12h": 12 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// Load reads and parses the configuration from file and environment variables, using the
+// profile named by TRADER_PROFILE if set
 func Load() (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("./config")
-	viper.AddConfigPath(".")
+	return LoadProfile(os.Getenv(ProfileEnv))
+}
+
+// LoadProfile loads config/config.yaml, merges config/profiles/<profile>.yaml over it when a
+// profile is given, and applies TRADER_* environment variables on top
+func LoadProfile(profile string) (*Config, error) {
+	return load(viper.GetViper(), "TRADER", profile, "")
+}
+
+// LoadInstance loads the configuration of one bot instance: the base file and profile as in
+// LoadProfile, config/instances/<instance>.yaml merged over them and TRADER_<INSTANCE>_*
+// environment variables on top. Its settings are independent of the other instances.
+func LoadInstance(profile, instance string) (*Config, error) {
+	if !instanceName.MatchString(instance) {
+		return nil, fmt.Errorf("invalid instance name %q", instance)
+	}
+	return load(viper.New(), "TRADER_"+strings.ToUpper(instance), profile, instance)
+}
+
+func load(v *viper.Viper, envPrefix, profile, instance string) (*Config, error) {
+	v.SetConfigName("config")
+	v.SetConfigType("yaml")
+	v.AddConfigPath("./config")
+	v.AddConfigPath(".")
 
 	// Set environment variable prefix
-	viper.SetEnvPrefix("TRADER")
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	viper.AutomaticEnv()
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
 
 	// Set default values
-	setDefaults()
+	setDefaults(v)
 
 	// Read configuration file
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
 			// Config file not found; use defaults and environment variables
 		} else {
@@ -99,11 +945,35 @@ func Load() (*Config, error) {
 		}
 	}
 
+	dir := configDir(v)
+	if profile != "" {
+		if err := mergeProfile(v, dir, profile); err != nil {
+			return nil, err
+		}
+	}
+	if instance != "" {
+		if err := mergeInstance(v, dir, instance); err != nil {
+			return nil, err
+		}
+	}
+
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
+	config.Profile = profile
+	if instance != "" {
+		// The instance list belongs to the process, not to its instances
+		config.Instance = instance
+		config.Instances = nil
+	}
+
+	// Replace the exchange credentials when they come from a secrets source
+	if err := resolveCredentials(&config.Exchange); err != nil {
+		return nil, fmt.Errorf("failed to load exchange credentials: %w", err)
+	}
+
 	// Validate configuration
 	if err := validateConfig(&config); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
@@ -113,76 +983,297 @@ func Load() (*Config, error) {
 }
 
 // setDefaults sets default configuration values
-func setDefaults() {
+func setDefaults(v *viper.Viper) {
 	// Exchange defaults
-	viper.SetDefault("exchange.name", "binance")
-	viper.SetDefault("exchange.testnet", true)
-	viper.SetDefault("exchange.base_url", "")
+	v.SetDefault("exchange.name", "binance")
+	v.SetDefault("exchange.testnet", true)
+	v.SetDefault("exchange.base_url", "")
+	v.SetDefault("exchange.market", "usdm")
+	v.SetDefault("exchange.spot_enabled", false)
+	v.SetDefault("exchange.recv_window_ms", 5000)
+	v.SetDefault("exchange.time_sync_interval_seconds", 300)
+	v.SetDefault("exchange.request_timeout_ms", 10000)
+	v.SetDefault("exchange.circuit_breaker.failure_threshold", 5)
+	v.SetDefault("exchange.circuit_breaker.open_seconds", 60)
+	v.SetDefault("exchange.record_file", "")
+	v.SetDefault("exchange.chaos.enabled", false)
+	v.SetDefault("exchange.chaos.seed", 0)
+	v.SetDefault("exchange.chaos.latency_ms", 0)
+	v.SetDefault("exchange.chaos.latency_jitter_ms", 0)
+	v.SetDefault("exchange.chaos.error_rate", 0.0)
+	v.SetDefault("exchange.chaos.lost_response_rate", 0.0)
+	v.SetDefault("exchange.chaos.partial_fill_rate", 0.0)
+	v.SetDefault("exchange.chaos.disconnect_mean_seconds", 0)
+	v.SetDefault("exchange.credentials_source", "config")
+	v.SetDefault("exchange.secrets_file", "config/secrets.enc")
+	v.SetDefault("exchange.vault.api_key_field", "api_key")
+	v.SetDefault("exchange.vault.secret_key_field", "secret_key")
 
 	// Trading defaults
-	viper.SetDefault("trading.symbols", []string{"BTCUSDT", "ETHUSDT"})
-	viper.SetDefault("trading.max_position_size", 1000.0)
-	viper.SetDefault("trading.stop_loss_percent", 2.0)
-	viper.SetDefault("trading.take_profit_percent", 5.0)
-	viper.SetDefault("trading.max_daily_loss", 500.0)
-	viper.SetDefault("trading.trading_interval_seconds", 60)
-	viper.SetDefault("trading.min_order_value", 10.0)
-	viper.SetDefault("trading.max_leverage", 5)
-	viper.SetDefault("trading.risk_per_trade_percent", 1.0)
-	viper.SetDefault("trading.enable_paper_trading", true)
-	viper.SetDefault("trading.strategy.type", "simple_moving_average")
-	viper.SetDefault("trading.strategy.enable_signal_filters", true)
+	v.SetDefault("trading.symbols", []string{"BTCUSDT", "ETHUSDT"})
+	v.SetDefault("trading.max_position_size", 1000.0)
+	v.SetDefault("trading.stop_loss_percent", 2.0)
+	v.SetDefault("trading.take_profit_percent", 5.0)
+	v.SetDefault("trading.max_daily_loss", 500.0)
+	v.SetDefault("trading.day_timezone", "UTC")
+	v.SetDefault("trading.trading_interval_seconds", 60)
+	v.SetDefault("trading.kline_interval", "1m")
+	v.SetDefault("trading.evaluation_mode", "interval")
+	v.SetDefault("trading.candle_close_delay_ms", 3000)
+	v.SetDefault("trading.auto_pause_errors", 5)
+	v.SetDefault("trading.max_concurrent_symbols", 4)
+	v.SetDefault("trading.symbol_timeout_seconds", 30)
+	v.SetDefault("trading.recovery.enabled", true)
+	v.SetDefault("trading.recovery.adopt_unknown", false)
+	v.SetDefault("trading.position_sync.enabled", true)
+	v.SetDefault("trading.position_sync.interval_seconds", 60)
+	v.SetDefault("trading.health.stream_max_age_seconds", 60)
+	v.SetDefault("trading.stale_data.enabled", true)
+	v.SetDefault("trading.sizing.mode", SizingModeRisk)
+	v.SetDefault("trading.sizing.kelly_fraction", 0.5)
+	v.SetDefault("trading.sizing.kelly_lookback", 100)
+	v.SetDefault("trading.sizing.kelly_min_trades", 30)
+	v.SetDefault("trading.sizing.kelly_max_percent", 5.0)
+	v.SetDefault("trading.vol_target.enabled", false)
+	v.SetDefault("trading.vol_target.target_percent", 20.0)
+	v.SetDefault("trading.vol_target.window_hours", 168)
+	v.SetDefault("trading.vol_target.bucket_minutes", 60)
+	v.SetDefault("trading.vol_target.refresh_minutes", 15)
+	v.SetDefault("trading.vol_target.min_samples", 24)
+	v.SetDefault("trading.vol_target.min_scale", 0.25)
+	v.SetDefault("trading.vol_target.max_scale", 1.5)
+	v.SetDefault("trading.stale_data.max_age_seconds", 180)
+	v.SetDefault("trading.auto_cancel.enabled", false)
+	v.SetDefault("trading.auto_cancel.countdown_seconds", 120)
+	v.SetDefault("trading.auto_cancel.heartbeat_seconds", 30)
+	v.SetDefault("trading.min_order_value", 10.0)
+	v.SetDefault("trading.max_leverage", 5)
+	v.SetDefault("trading.margin_type", "CROSSED")
+	v.SetDefault("trading.stop_working_type", "MARK_PRICE")
+	v.SetDefault("trading.pnl_working_type", "MARK_PRICE")
+	v.SetDefault("trading.risk_per_trade_percent", 1.0)
+	v.SetDefault("trading.atr_period", 14)
+	v.SetDefault("trading.enable_paper_trading", true)
+	v.SetDefault("trading.strategy.type", "simple_moving_average")
+	v.SetDefault("trading.strategy.enable_signal_filters", true)
+	v.SetDefault("trading.strategy.filters.trend_period", 50)
+	v.SetDefault("trading.strategy.filters.timezone", "UTC")
+	v.SetDefault("trading.strategy.sandbox.enabled", true)
+	v.SetDefault("trading.strategy.sandbox.timeout_ms", 5000)
+	v.SetDefault("trading.strategy.sandbox.max_consecutive_timeouts", 3)
+	v.SetDefault("trading.strategy.sandbox.quarantine_seconds", 900)
+	v.SetDefault("trading.strategy.sandbox.memory_limit_mb", 512)
+	v.SetDefault("trading.strategy.sandbox.cpu_limit_seconds", 0)
+	v.SetDefault("trading.strategy.state.enabled", true)
+	v.SetDefault("trading.strategy.state.save_interval_seconds", 300)
+	v.SetDefault("trading.strategy.state.max_age_minutes", 60)
+	v.SetDefault("trading.correlation.enabled", true)
+	v.SetDefault("trading.correlation.window_hours", 168)
+	v.SetDefault("trading.correlation.bucket_minutes", 60)
+	v.SetDefault("trading.correlation.refresh_minutes", 15)
+	v.SetDefault("trading.correlation.limit", 0.8)
+	v.SetDefault("trading.correlation.max_correlated_exposure", 0.0)
+	v.SetDefault("trading.exposure.max_net_delta", 0.0)
+	v.SetDefault("trading.exposure.max_gross_exposure", 0.0)
+	v.SetDefault("trading.positioning.enabled", true)
+	v.SetDefault("trading.positioning.interval_seconds", 300)
+	v.SetDefault("trading.positioning.period", "5m")
+	v.SetDefault("trading.options.enabled", false)
+	v.SetDefault("trading.options.venue", "deribit")
+	v.SetDefault("trading.options.interval_seconds", 300)
+	v.SetDefault("trading.options.tenor_days", 30)
+	v.SetDefault("trading.options.skew_moneyness_percent", 10.0)
+	v.SetDefault("trading.options.timeout_seconds", 10)
+	v.SetDefault("trading.drift.enabled", true)
+	v.SetDefault("trading.drift.interval_minutes", 10)
+	v.SetDefault("trading.drift.auto_reconcile", true)
+
+	v.SetDefault("trading.order_flow.enabled", false)
+	v.SetDefault("trading.order_flow.window_seconds", 60)
+	v.SetDefault("trading.order_flow.large_trade_notional", 100000)
+	v.SetDefault("trading.order_flow.persist", true)
+
+	v.SetDefault("trading.liquidations.enabled", false)
+	v.SetDefault("trading.liquidations.window_seconds", 300)
+	v.SetDefault("trading.liquidations.alarm_notional", 0)
+
+	v.SetDefault("trading.warmup.enabled", true)
+	v.SetDefault("trading.warmup.buffer_candles", 20)
+	v.SetDefault("trading.journal.enabled", true)
+	v.SetDefault("trading.journal.kline_window", 100)
+	v.SetDefault("trading.daily_report.enabled", false)
+	v.SetDefault("trading.daily_report.hour", 0)
+	v.SetDefault("trading.daily_report.benchmark_days", 30)
+	v.SetDefault("trading.scheduler.jitter_seconds", 10)
+	v.SetDefault("trading.remote_config.enabled", false)
+	v.SetDefault("trading.remote_config.risk_limits_name", "default")
+	v.SetDefault("trading.remote_config.strategy_name", "default")
+	v.SetDefault("trading.remote_config.poll_seconds", 30)
+	v.SetDefault("trading.retention.kline_window", 0)
+	v.SetDefault("trading.retention.max_liquidation_events", 10000)
+	v.SetDefault("trading.retention.persist_market_data", true)
+	v.SetDefault("trading.retention.persist_interval_seconds", 0)
+	v.SetDefault("trading.retention.writer_queue_size", 1024)
+	v.SetDefault("trading.retention.writer_batch_size", 100)
+	v.SetDefault("trading.retention.writer_flush_ms", 1000)
+	v.SetDefault("trading.retention.enqueue_timeout_ms", 100)
+	v.SetDefault("trading.risk_metrics.interval_minutes", 5)
+	v.SetDefault("trading.risk_metrics.intraday", false)
+	v.SetDefault("trading.risk_metrics.intraday_days", 7)
+	v.SetDefault("trading.wallets.enabled", false)
+	v.SetDefault("trading.wallets.snapshot_minutes", 15)
+	v.SetDefault("trading.wallets.retention_days", 90)
+	v.SetDefault("trading.margin_monitor.enabled", false)
+	v.SetDefault("trading.margin_monitor.interval_seconds", 10)
+	v.SetDefault("trading.margin_monitor.cooldown_seconds", 60)
+	v.SetDefault("trading.adl.enabled", false)
+	v.SetDefault("trading.adl.interval_seconds", 30)
+	v.SetDefault("trading.adl.alert_quantile", 4)
+	v.SetDefault("trading.adl.retention_days", 30)
+	v.SetDefault("trading.rejections.record", true)
+	v.SetDefault("trading.rejections.remediate", false)
+	v.SetDefault("trading.rejections.max_retries", 2)
+	v.SetDefault("trading.rejections.hourly_budget", 20)
+	v.SetDefault("trading.rejections.max_resize_percent", 25)
+	v.SetDefault("trading.profit_lock.enabled", false)
+	v.SetDefault("trading.profit_lock.action", ProfitLockActionStop)
+	v.SetDefault("trading.profit_lock.reduce_scale", 0.5)
+	v.SetDefault("trading.drawdown_scaling.enabled", false)
+	v.SetDefault("trading.drawdown_scaling.lookback_days", 0)
+	v.SetDefault("trading.drawdown_scaling.refresh_minutes", 5)
+	v.SetDefault("trading.shadow.enabled", false)
+	v.SetDefault("trading.shadow.name", "candidate")
+	v.SetDefault("trading.shadow.initial_balance", 10000.0)
+	v.SetDefault("trading.shadow.position_percent", 10.0)
+	v.SetDefault("trading.shadow.fee_rate", 0.0004)
+	v.SetDefault("trading.shadow.record_signals", true)
+	v.SetDefault("trading.strategy_guard.enabled", false)
+	v.SetDefault("trading.strategy_guard.interval_minutes", 15)
+	v.SetDefault("trading.strategy_guard.lookback", 30)
+	v.SetDefault("trading.strategy_guard.min_trades", 10)
+	v.SetDefault("trading.strategy_guard.min_expectancy", 0)
+	v.SetDefault("trading.strategy_guard.max_drawdown", 0)
+
+	v.SetDefault("trading.delisting.enabled", true)
+	v.SetDefault("trading.delisting.interval_minutes", 10)
+	v.SetDefault("trading.delisting.close_before_hours", 24)
+
+	v.SetDefault("trading.leverage_regime.enabled", false)
+	v.SetDefault("trading.leverage_regime.interval_minutes", 15)
+	v.SetDefault("trading.leverage_regime.kline_interval", "1h")
+	v.SetDefault("trading.leverage_regime.window", 24)
+	v.SetDefault("trading.leverage_regime.lookback", 720)
+	v.SetDefault("trading.leverage_regime.low_percentile", 30)
+	v.SetDefault("trading.leverage_regime.high_percentile", 70)
+	v.SetDefault("trading.leverage_regime.min_leverage", 1)
+	v.SetDefault("trading.leverage_regime.max_leverage", 5)
+
+	v.SetDefault("trading.income_sync.enabled", true)
+	v.SetDefault("trading.income_sync.interval_minutes", 60)
+	v.SetDefault("trading.income_sync.backfill_days", 30)
+	v.SetDefault("trading.income_sync.tolerance", 0.5)
+	v.SetDefault("trading.sessions.enabled", false)
+	v.SetDefault("trading.sessions.timezone", "UTC")
+	v.SetDefault("trading.sessions.pause_weekends", false)
+	v.SetDefault("trading.sessions.funding_blackout_minutes", 0)
+	v.SetDefault("trading.cooldown.max_consecutive_losses", 3)
+	v.SetDefault("trading.cooldown.cooldown_minutes", 120)
+	v.SetDefault("trading.cooldown.min_reentry_minutes", 0)
+	v.SetDefault("trading.slippage.enabled", true)
+	v.SetDefault("trading.slippage.max_slippage_bps", 20.0)
+	v.SetDefault("trading.slippage.max_spread_bps", 10.0)
+	v.SetDefault("trading.slippage.action", "reject")
+	v.SetDefault("trading.slippage.book_depth", 20)
+	v.SetDefault("trading.take_profit_ladder.enabled", false)
+	v.SetDefault("trading.take_profit_ladder.trailing_callback_percent", 0.0)
+	v.SetDefault("trading.pyramiding.enabled", false)
+	v.SetDefault("trading.pyramiding.max_add_ons", 2)
+	v.SetDefault("trading.pyramiding.spacing_percent", 1.0)
+	v.SetDefault("trading.pyramiding.size_decay", 0.5)
+	v.SetDefault("trading.funding_arbitrage.enabled", false)
+	v.SetDefault("trading.funding_arbitrage.entry_annualized_rate", 20.0)
+	v.SetDefault("trading.funding_arbitrage.exit_annualized_rate", 5.0)
+	v.SetDefault("trading.funding_arbitrage.notional_usdt", 500.0)
+	v.SetDefault("trading.funding_arbitrage.funding_interval_hours", 8.0)
+	v.SetDefault("trading.funding_arbitrage.max_basis_percent", 0.5)
+	v.SetDefault("trading.funding_arbitrage.leverage", 1)
+	v.SetDefault("trading.funding_arbitrage.check_interval_minutes", 5)
+	v.SetDefault("trading.pnl_alerts.enabled", false)
+	v.SetDefault("trading.pnl_alerts.bands", []float64{-5.0, -3.0, 10.0})
+	v.SetDefault("trading.pnl_alerts.hysteresis_percent", 0.5)
 
 	// Database defaults
-	viper.SetDefault("database.mysql.max_open_conns", 25)
-	viper.SetDefault("database.mysql.max_idle_conns", 5)
-	viper.SetDefault("database.mysql.conn_max_lifetime_minutes", 30)
-	viper.SetDefault("database.redis.db", 0)
-	viper.SetDefault("database.redis.pool_size", 10)
+	v.SetDefault("database.mysql.max_open_conns", 25)
+	v.SetDefault("database.mysql.max_idle_conns", 5)
+	v.SetDefault("database.mysql.conn_max_lifetime_minutes", 30)
+	v.SetDefault("database.migrations.auto_apply", true)
+	v.SetDefault("database.monitoring.slow_query_ms", 200)
+	v.SetDefault("database.monitoring.pool_soft_cap_percent", 80)
+	v.SetDefault("database.monitoring.sample_seconds", 15)
+	v.SetDefault("database.redis.db", 0)
+	v.SetDefault("database.redis.pool_size", 10)
 
 	// Logger defaults
-	viper.SetDefault("logger.level", "info")
-	viper.SetDefault("logger.format", "json")
-	viper.SetDefault("logger.output", "stdout")
+	v.SetDefault("logger.level", "info")
+	v.SetDefault("logger.format", "json")
+	v.SetDefault("logger.output", "stdout")
+
+	// Notification defaults
+	v.SetDefault("notifications.timeout_seconds", 10)
+	v.SetDefault("notifications.outbox.enabled", true)
+	v.SetDefault("notifications.outbox.poll_seconds", 2)
+	v.SetDefault("notifications.outbox.batch_size", 50)
+	v.SetDefault("notifications.outbox.max_attempts", 10)
+	v.SetDefault("notifications.outbox.retry_base_seconds", 5)
+	v.SetDefault("notifications.outbox.retry_max_seconds", 900)
+	v.SetDefault("notifications.outbox.retention_days", 7)
+
+	// Feed defaults
+	v.SetDefault("feeds.enabled", false)
+	v.SetDefault("feeds.poll_interval_seconds", 300)
+	v.SetDefault("feeds.window_hours", 24)
+	v.SetDefault("feeds.half_life_hours", 6.0)
+	v.SetDefault("feeds.min_buy_sentiment", -0.5)
+	v.SetDefault("feeds.min_items", 3)
+
+	// Spread monitor defaults
+	v.SetDefault("spreads.enabled", false)
+	v.SetDefault("spreads.interval_seconds", 10)
+	v.SetDefault("spreads.alert_bps", 30.0)
+	v.SetDefault("spreads.reset_bps", 10.0)
+	v.SetDefault("spreads.timeout_seconds", 5)
+
+	// API defaults
+	v.SetDefault("api.enabled", false)
+	v.SetDefault("api.listen_addr", "127.0.0.1:8080")
+	v.SetDefault("audit_log.enabled", false)
+
+	// Commentary defaults
+	v.SetDefault("commentary.enabled", false)
+	v.SetDefault("commentary.endpoint", "https://api.openai.com/v1/chat/completions")
+	v.SetDefault("commentary.max_tokens", 300)
+	v.SetDefault("commentary.timeout_seconds", 30)
 }
 
-// validateConfig validates the configuration values
-func validateConfig(config *Config) error {
-	// Validate exchange configuration
-	if config.Exchange.APIKey == "" {
-		return fmt.Errorf("exchange API key is required")
-	}
-	if config.Exchange.SecretKey == "" {
-		return fmt.Errorf("exchange secret key is required")
+// ParseClockRange parses a daily "HH:MM-HH:MM" window into offsets from midnight.
+// The end may be earlier than the start for windows that cross midnight.
+func ParseClockRange(window string) (time.Duration, time.Duration, error) {
+	parts := strings.Split(window, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid time window %q, expected HH:MM-HH:MM", window)
 	}
 
-	// Validate trading configuration
-	if len(config.Trading.Symbols) == 0 {
-		return fmt.Errorf("at least one trading symbol is required")
-	}
-	if config.Trading.MaxPositionSize <= 0 {
-		return fmt.Errorf("max position size must be positive")
-	}
-	if config.Trading.StopLossPercent <= 0 || config.Trading.StopLossPercent > 50 {
-		return fmt.Errorf("stop loss percent must be between 0 and 50")
-	}
-	if config.Trading.TakeProfitPercent <= 0 || config.Trading.TakeProfitPercent > 100 {
-		return fmt.Errorf("take profit percent must be between 0 and 100")
-	}
-	if config.Trading.MaxLeverage < 1 || config.Trading.MaxLeverage > 125 {
-		return fmt.Errorf("max leverage must be between 1 and 125")
-	}
-	if config.Trading.RiskPerTrade < 0.1 || config.Trading.RiskPerTrade > 10 {
-		return fmt.Errorf("risk per trade percent must be between 0.1 and 10")
+	var offsets [2]time.Duration
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid time window %q: %w", window, err)
+		}
+		offsets[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
 	}
 
-	// Validate database configuration
-	if config.Database.MySQL.DSN == "" {
-		return fmt.Errorf("MySQL DSN is required")
-	}
-	if config.Database.Redis.Addr == "" {
-		return fmt.Errorf("Redis address is required")
+	if offsets[0] == offsets[1] {
+		return 0, 0, fmt.Errorf("time window %q is empty", window)
 	}
-
-	return nil
+	return offsets[0], offsets[1], nil
 }
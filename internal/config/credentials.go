@@ -0,0 +1,45 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"contract_playground/internal/secrets"
+)
+
+// resolveCredentials loads the API key and secret from the configured credentials source
+func resolveCredentials(cfg *ExchangeConfig) error {
+	var (
+		creds *secrets.Credentials
+		err   error
+	)
+
+	switch cfg.CredentialsSource {
+	case "", secrets.SourceConfig:
+		return nil
+	case secrets.SourceEnv:
+		creds, err = secrets.FromEnv()
+	case secrets.SourceFile:
+		creds, err = secrets.FromFile(cfg.SecretsFile)
+	case secrets.SourceVault:
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		creds, err = secrets.FromVault(ctx, secrets.VaultOptions{
+			Address:        cfg.Vault.Address,
+			Path:           cfg.Vault.Path,
+			Namespace:      cfg.Vault.Namespace,
+			APIKeyField:    cfg.Vault.APIKeyField,
+			SecretKeyField: cfg.Vault.SecretKeyField,
+		})
+	default:
+		return fmt.Errorf("unknown credentials source %q (expected config, env, file or vault)", cfg.CredentialsSource)
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg.APIKey = creds.APIKey
+	cfg.SecretKey = creds.SecretKey
+	return nil
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateInstances(t *testing.T) {
+	instance := func(name, dsn, apiKey string, testnet bool) *Config {
+		config := &Config{Instance: name}
+		config.Database.MySQL.DSN = dsn
+		config.Exchange.Name = "binance"
+		config.Exchange.Testnet = testnet
+		config.Exchange.APIKey = apiKey
+		return config
+	}
+
+	tests := []struct {
+		name      string
+		instances []*Config
+		problems  []string // Keys of the expected problems
+	}{
+		{"isolated", []*Config{instance("a", "db_a", "key_a", false), instance("b", "db_b", "key_b", false)}, nil},
+		{"shared database", []*Config{instance("a", "db", "key_a", false), instance("b", "db", "key_b", false)}, []string{"b.database.mysql.dsn"}},
+		{"shared account", []*Config{instance("a", "db_a", "key", false), instance("b", "db_b", "key", false)}, []string{"b.exchange.api_key"}},
+		// The same key on testnet and mainnet belongs to different accounts
+		{"testnet and mainnet", []*Config{instance("a", "db_a", "key", true), instance("b", "db_b", "key", false)}, nil},
+		{"both shared", []*Config{instance("a", "db", "key", false), instance("b", "db", "key", false)}, []string{"b.database.mysql.dsn", "b.exchange.api_key"}},
+	}
+	for _, tt := range tests {
+		err := ValidateInstances(tt.instances)
+		if len(tt.problems) == 0 {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		verr, ok := err.(*ValidationError)
+		if !ok || len(verr.Problems) != len(tt.problems) {
+			t.Errorf("%s: error = %v, want problems with %v", tt.name, err, tt.problems)
+			continue
+		}
+		for i, key := range tt.problems {
+			if !strings.HasPrefix(verr.Problems[i], key+":") {
+				t.Errorf("%s: problem %q, want one for %s", tt.name, verr.Problems[i], key)
+			}
+		}
+	}
+}
+
+// writeConfigTree lays out config/ with the repository's base file and the given profile and
+// instance files, and makes it the working directory of the test
+func writeConfigTree(t *testing.T, files map[string]string) {
+	base, err := os.ReadFile(filepath.Join("..", "..", "config", "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	files["config.yaml"] = string(base)
+	for name, content := range files {
+		path := filepath.Join(dir, "config", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestLoadInstance(t *testing.T) {
+	writeConfigTree(t, map[string]string{
+		"profiles/dev.yaml": "instances: [\"alpha\", \"beta\"]\ntrading:\n  symbols: [\"BTCUSDT\", \"ETHUSDT\"]\n  max_leverage: 7\n",
+		"instances/alpha.yaml": "trading:\n  symbols: [\"SOLUSDT\"]\n" +
+			"database:\n  mysql:\n    dsn: \"trader:pw@tcp(localhost:3306)/trading_bot_alpha\"\n",
+	})
+	t.Setenv("TRADER_ALPHA_EXCHANGE_API_KEY", "alpha-key")
+	t.Setenv("TRADER_EXCHANGE_API_KEY", "process-key") // The process prefix does not reach instances
+
+	config, err := LoadInstance("dev", "alpha")
+	if err != nil {
+		t.Fatalf("LoadInstance: %v", err)
+	}
+	if config.Instance != "alpha" || config.Profile != "dev" {
+		t.Errorf("instance %q profile %q, want alpha and dev", config.Instance, config.Profile)
+	}
+	if len(config.Instances) != 0 {
+		t.Errorf("instances = %v, want none on an instance", config.Instances)
+	}
+	// The instance file wins over the profile, which wins over the base file
+	if len(config.Trading.Symbols) != 1 || config.Trading.Symbols[0] != "SOLUSDT" {
+		t.Errorf("symbols = %v, want the instance's SOLUSDT", config.Trading.Symbols)
+	}
+	if config.Trading.MaxLeverage != 7 {
+		t.Errorf("max leverage = %d, want the profile's 7", config.Trading.MaxLeverage)
+	}
+	if !strings.HasSuffix(config.Database.MySQL.DSN, "/trading_bot_alpha") {
+		t.Errorf("dsn = %q, want the instance's database", config.Database.MySQL.DSN)
+	}
+	if config.Exchange.APIKey != "alpha-key" {
+		t.Errorf("api key = %q, want it from TRADER_ALPHA_EXCHANGE_API_KEY", config.Exchange.APIKey)
+	}
+
+	if _, err := LoadInstance("dev", "beta"); err == nil {
+		t.Error("loaded an instance without its file")
+	}
+	if _, err := LoadInstance("dev", "../alpha"); err == nil {
+		t.Error("loaded an instance with an invalid name")
+	}
+}
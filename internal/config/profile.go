@@ -0,0 +1,154 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ProfileEnv names the environment variable selecting the profile when no flag is given
+const ProfileEnv = "TRADER_PROFILE"
+
+var profileName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Instance names appear in API paths, log fields and environment variable names
+var instanceName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Words of setting names whose values are replaced in the config dump
+var secretWords = map[string]bool{
+	"key":      true,
+	"secret":   true,
+	"password": true,
+	"token":    true,
+	"dsn":      true,
+	"webhook":  true,
+}
+
+const redacted = "[REDACTED]"
+
+// configDir returns the directory of the base config file
+func configDir(v *viper.Viper) string {
+	if used := v.ConfigFileUsed(); used != "" {
+		return filepath.Dir(used)
+	}
+	return "config"
+}
+
+// mergeProfile merges profiles/<profile>.yaml next to the base config over the base values
+func mergeProfile(v *viper.Viper, dir, profile string) error {
+	if !profileName.MatchString(profile) {
+		return fmt.Errorf("invalid profile name %q", profile)
+	}
+	return mergeFile(v, filepath.Join(dir, "profiles", profile+".yaml"), "profile", profile)
+}
+
+// mergeInstance merges instances/<instance>.yaml next to the base config over the base and
+// profile values
+func mergeInstance(v *viper.Viper, dir, instance string) error {
+	return mergeFile(v, filepath.Join(dir, "instances", instance+".yaml"), "instance", instance)
+}
+
+func mergeFile(v *viper.Viper, path, kind, name string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("%s %q not found: %w", kind, name, err)
+	}
+
+	v.SetConfigFile(path)
+	if err := v.MergeInConfig(); err != nil {
+		return fmt.Errorf("error reading %s %s: %w", kind, path, err)
+	}
+	return nil
+}
+
+// Dump writes the effective configuration loaded by Load as YAML, with credentials, tokens,
+// DSNs and webhook URLs redacted
+func Dump(w io.Writer) error {
+	settings := redactSettings(viper.AllSettings())
+
+	dump := viper.New()
+	dump.SetConfigType("yaml")
+	if err := dump.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("failed to build config dump: %w", err)
+	}
+	return dump.WriteConfigTo(w)
+}
+
+// Hash returns the SHA-256 of the configuration dump, identifying the effective settings
+// without exposing secrets
+func Hash() (string, error) {
+	var buf bytes.Buffer
+	if err := Dump(&buf); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func redactSettings(settings map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			out[key] = redactSettings(v)
+		case []interface{}:
+			out[key] = redactList(v)
+		default:
+			if IsSecretKey(key) && !isEmpty(v) {
+				out[key] = redacted
+			} else {
+				out[key] = v
+			}
+		}
+	}
+	return out
+}
+
+// redactList redacts the settings of list items such as api.keys
+func redactList(items []interface{}) []interface{} {
+	out := make([]interface{}, len(items))
+	for i, item := range items {
+		if settings, ok := item.(map[string]interface{}); ok {
+			out[i] = redactSettings(settings)
+		} else {
+			out[i] = item
+		}
+	}
+	return out
+}
+
+// IsSecretKey reports whether a setting name holds a secret, such as an API key or password
+func IsSecretKey(key string) bool {
+	words := strings.Split(strings.ToLower(key), "_")
+	// Names of secret fields and files, not secrets themselves
+	if last := words[len(words)-1]; last == "field" || last == "file" {
+		return false
+	}
+	for _, word := range words {
+		if secretWords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Kinds of strategy parameter values
+const (
+	paramNumber = "number"
+	paramString = "string"
+	paramBool   = "bool"
+	paramAny    = "any"
+)
+
+// Parameters every strategy accepts
+var commonStrategyParameters = map[string]string{
+	"stop_working_type": paramString,
+}
+
+// strategyParameters is the parameter schema of each strategy type
+var strategyParameters = map[string]map[string]string{
+	"simple_moving_average": {
+		"short_period":   paramNumber,
+		"long_period":    paramNumber,
+		"min_confidence": paramNumber,
+	},
+	"rsi": {
+		"period":         paramNumber,
+		"oversold":       paramNumber,
+		"overbought":     paramNumber,
+		"min_confidence": paramNumber,
+	},
+	"ai": {
+		"endpoint":       paramString,
+		"api_key":        paramString,
+		"timeout_ms":     paramNumber,
+		"min_confidence": paramNumber,
+		"rsi_period":     paramNumber,
+		"volume_period":  paramNumber,
+		"return_window":  paramNumber,
+		"model_path":     paramString,
+		"onnx_library":   paramString,
+		"input_name":     paramString,
+		"output_name":    paramString,
+	},
+	"grid": {
+		"grid_size":      paramNumber,
+		"num_grids":      paramNumber,
+		"order_usdt":     paramNumber,
+		"mode":           paramString,
+		"min_confidence": paramNumber,
+	},
+	"dca": {
+		"base_order_usdt":   paramNumber,
+		"safety_order_usdt": paramNumber,
+		"max_safety_orders": paramNumber,
+		"price_deviation":   paramNumber,
+		"step_scale":        paramNumber,
+		"volume_scale":      paramNumber,
+		"take_profit":       paramNumber,
+		"stop_loss":         paramNumber,
+	},
+	"breakout": {
+		"order_usdt":        paramNumber,
+		"channel_period":    paramNumber,
+		"exit_period":       paramNumber,
+		"volume_period":     paramNumber,
+		"volume_multiplier": paramNumber,
+		"atr_period":        paramNumber,
+		"stop_atr":          paramNumber,
+		"trail_atr":         paramNumber,
+		"take_profit_atr":   paramNumber,
+	},
+	"vwap_reversion": {
+		"order_usdt":  paramNumber,
+		"anchor":      paramString,
+		"band_std":    paramNumber,
+		"exit_std":    paramNumber,
+		"stop_std":    paramNumber,
+		"min_samples": paramNumber,
+	},
+	"ichimoku": {
+		"order_usdt":      paramNumber,
+		"tenkan_period":   paramNumber,
+		"kijun_period":    paramNumber,
+		"senkou_b_period": paramNumber,
+		"displacement":    paramNumber,
+		"require_chikou":  paramBool,
+	},
+	"stochastic": {
+		"order_usdt":        paramNumber,
+		"k_period":          paramNumber,
+		"smooth_k":          paramNumber,
+		"d_period":          paramNumber,
+		"oversold":          paramNumber,
+		"overbought":        paramNumber,
+		"williams_period":   paramNumber,
+		"williams_buy":      paramNumber,
+		"williams_lookback": paramNumber,
+		"rsi_period":        paramNumber,
+		"rsi_max":           paramNumber,
+		"stop_loss":         paramNumber,
+	},
+	"external": {
+		"command": paramString,
+		"args":    paramAny, // List or space-separated string
+		"dir":     paramString,
+		"name":    paramString,
+	},
+	"plugin": {
+		"path": paramString,
+	},
+	"python": {
+		"script":     paramString,
+		"python":     paramString,
+		"timeout_ms": paramNumber,
+		"dir":        paramString,
+		"name":       paramString,
+	},
+}
+
+// Strategies loaded from outside the repository define their own parameters, so unknown
+// ones are passed on rather than rejected
+var openStrategyParameters = map[string]bool{"external": true, "plugin": true, "python": true}
+
+// Parameters a strategy type cannot run without
+var requiredStrategyParameters = map[string][]string{
+	"external": {"command"},
+	"plugin":   {"path"},
+	"python":   {"script"},
+}
+
+// StrategyTypes returns the supported strategy types in alphabetical order
+func StrategyTypes() []string {
+	types := make([]string, 0, len(strategyParameters))
+	for strategyType := range strategyParameters {
+		types = append(types, strategyType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// validateStrategy checks the strategy type and that every parameter is known to it and has
+// the expected kind, then the strategy's own parameter constraints. key is the section the
+// type and parameters are set in.
+func validateStrategy(p *problems, key string, cfg StrategyConfig) {
+	schema, ok := strategyParameters[cfg.Type]
+	if !ok {
+		p.addf(key+".type", "unknown strategy %q, expected one of %v", cfg.Type, StrategyTypes())
+		return
+	}
+
+	names := make([]string, 0, len(cfg.Parameters))
+	for name := range cfg.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		paramKey := key + ".parameters." + name
+		kind, ok := schema[name]
+		if !ok {
+			kind, ok = commonStrategyParameters[name]
+		}
+		if !ok {
+			if !openStrategyParameters[cfg.Type] {
+				p.addf(paramKey, "not a parameter of strategy %s", cfg.Type)
+			}
+			continue
+		}
+		if actual := paramKind(cfg.Parameters[name]); kind != paramAny && actual != kind {
+			p.addf(paramKey, "must be a %s, got %s %v", kind, actual, cfg.Parameters[name])
+		}
+	}
+
+	for _, name := range requiredStrategyParameters[cfg.Type] {
+		if value, _ := cfg.Parameters[name].(string); value == "" {
+			p.addf(key+".parameters."+name, "is required for strategy %s", cfg.Type)
+		}
+	}
+
+	number := func(name string) (float64, bool) {
+		value, ok := cfg.Parameters[name]
+		if !ok || paramKind(value) != paramNumber {
+			return 0, false
+		}
+		return toFloat(value), true
+	}
+	ordered := func(low, high string) {
+		lowValue, lowOK := number(low)
+		highValue, highOK := number(high)
+		if lowOK && highOK && lowValue >= highValue {
+			p.addf(key+".parameters."+low, "must be below %s (%v >= %v)", high, lowValue, highValue)
+		}
+	}
+	switch cfg.Type {
+	case "simple_moving_average":
+		ordered("short_period", "long_period")
+	case "rsi", "stochastic":
+		ordered("oversold", "overbought")
+	case "ichimoku":
+		ordered("tenkan_period", "kijun_period")
+		ordered("kijun_period", "senkou_b_period")
+	}
+
+	if workingType, ok := cfg.Parameters["stop_working_type"].(string); ok && !validWorkingType(workingType) {
+		p.addf(key+".parameters.stop_working_type", "must be MARK_PRICE or CONTRACT_PRICE, got %q", workingType)
+	}
+}
+
+// paramKind returns the kind of a parameter value as decoded from YAML or the environment
+func paramKind(value interface{}) string {
+	v := reflect.ValueOf(value)
+	switch {
+	case v.CanInt() || v.CanUint() || v.CanFloat():
+		return paramNumber
+	case v.Kind() == reflect.String:
+		return paramString
+	case v.Kind() == reflect.Bool:
+		return paramBool
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// toFloat converts a value of kind paramNumber
+func toFloat(value interface{}) float64 {
+	v := reflect.ValueOf(value)
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	case v.CanFloat():
+		return v.Float()
+	}
+	return 0
+}
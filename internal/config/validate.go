@@ -0,0 +1,911 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"contract_playground/internal/scheduler"
+)
+
+// ValidationError lists every problem found in a configuration, each prefixed with the key
+// of the offending setting
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d problem(s):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// problems collects validation failures so that all of them are reported at once
+type problems []string
+
+func (p *problems) addf(key, format string, args ...interface{}) {
+	*p = append(*p, key+": "+fmt.Sprintf(format, args...))
+}
+
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: p}
+}
+
+func validWorkingType(workingType string) bool {
+	return workingType == "MARK_PRICE" || workingType == "CONTRACT_PRICE"
+}
+
+func validSchedulerJob(job string) bool {
+	for _, known := range SchedulerJobs {
+		if job == known {
+			return true
+		}
+	}
+	return false
+}
+
+// validateConfig checks every setting and returns a *ValidationError listing all problems
+func validateConfig(config *Config) error {
+	var p problems
+
+	validateInstances(&p, config.Instances)
+	validateExchange(&p, config.Exchange, len(config.Instances) == 0)
+	validateChaos(&p, config)
+	validateTrading(&p, config.Trading)
+	validateStrategy(&p, "trading.strategy", config.Trading.Strategy)
+	if shadow := config.Trading.Shadow; shadow.Enabled {
+		validateShadow(&p, shadow, config.Trading.Strategy)
+	}
+	validateServices(&p, config)
+
+	return p.err()
+}
+
+func validateInstances(p *problems, instances []string) {
+	seen := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		if !instanceName.MatchString(instance) {
+			p.addf("instances", "%q must use only lowercase letters, digits and underscores", instance)
+		}
+		if seen[instance] {
+			p.addf("instances", "%q is listed twice", instance)
+		}
+		seen[instance] = true
+	}
+}
+
+// ValidateInstances checks that the instances of one process are isolated from each other:
+// each needs its own database, so their records cannot mix, and its own exchange account
+func ValidateInstances(instances []*Config) error {
+	var p problems
+	databases := make(map[string]string, len(instances))
+	accounts := make(map[string]string, len(instances))
+	for _, instance := range instances {
+		if other, ok := databases[instance.Database.MySQL.DSN]; ok {
+			p.addf(instance.Instance+".database.mysql.dsn", "is the database of instance %s; every instance needs its own", other)
+		}
+		databases[instance.Database.MySQL.DSN] = instance.Instance
+
+		account := fmt.Sprintf("%s/%t/%s", instance.Exchange.Name, instance.Exchange.Testnet, instance.Exchange.APIKey)
+		if other, ok := accounts[account]; ok {
+			p.addf(instance.Instance+".exchange.api_key", "is the account of instance %s; every instance needs its own", other)
+		}
+		accounts[account] = instance.Instance
+	}
+	return p.err()
+}
+
+// validateExchange checks the exchange settings; credentials are only required of a
+// configuration that trades, not of the base of a multi-instance process
+func validateExchange(p *problems, exchange ExchangeConfig, trades bool) {
+	if trades && exchange.APIKey == "" {
+		p.addf("exchange.api_key", "is required")
+	}
+	if trades && exchange.SecretKey == "" {
+		p.addf("exchange.secret_key", "is required")
+	}
+	if exchange.Market != "usdm" && exchange.Market != "coinm" {
+		p.addf("exchange.market", "must be usdm or coinm, got %q", exchange.Market)
+	}
+	if exchange.RecvWindowMs < 0 || exchange.RecvWindowMs > 60000 {
+		p.addf("exchange.recv_window_ms", "must be between 0 and 60000, got %d", exchange.RecvWindowMs)
+	}
+	if exchange.TimeSyncIntervalSeconds < 0 {
+		p.addf("exchange.time_sync_interval_seconds", "must not be negative, got %d", exchange.TimeSyncIntervalSeconds)
+	}
+	if exchange.RequestTimeoutMs < 0 {
+		p.addf("exchange.request_timeout_ms", "must not be negative, got %d", exchange.RequestTimeoutMs)
+	}
+	if exchange.CircuitBreaker.FailureThreshold < 0 {
+		p.addf("exchange.circuit_breaker.failure_threshold", "must not be negative, got %d", exchange.CircuitBreaker.FailureThreshold)
+	}
+	if exchange.CircuitBreaker.FailureThreshold > 0 && exchange.CircuitBreaker.OpenSeconds <= 0 {
+		p.addf("exchange.circuit_breaker.open_seconds", "must be positive while the breaker is enabled, got %d", exchange.CircuitBreaker.OpenSeconds)
+	}
+}
+
+func validateChaos(p *problems, config *Config) {
+	chaos := config.Exchange.Chaos
+	if !chaos.Enabled {
+		return
+	}
+	if !config.Exchange.Testnet && !config.Trading.EnablePaperTrading {
+		p.addf("exchange.chaos.enabled", "chaos injection is only allowed on the testnet or in paper trading")
+	}
+	if chaos.LatencyMs < 0 || chaos.LatencyJitterMs < 0 {
+		p.addf("exchange.chaos.latency_ms", "latency and jitter must not be negative, got %d and %d", chaos.LatencyMs, chaos.LatencyJitterMs)
+	}
+	rates := []struct {
+		key  string
+		rate float64
+	}{
+		{"error_rate", chaos.ErrorRate},
+		{"lost_response_rate", chaos.LostResponseRate},
+		{"partial_fill_rate", chaos.PartialFillRate},
+	}
+	for _, r := range rates {
+		if r.rate < 0 || r.rate > 1 {
+			p.addf("exchange.chaos."+r.key, "must be between 0 and 1, got %v", r.rate)
+		}
+	}
+	if chaos.DisconnectMeanSeconds < 0 {
+		p.addf("exchange.chaos.disconnect_mean_seconds", "must not be negative, got %d", chaos.DisconnectMeanSeconds)
+	}
+}
+
+func validateTrading(p *problems, trading TradingConfig) {
+	if len(trading.Symbols) == 0 {
+		p.addf("trading.symbols", "at least one symbol is required")
+	}
+	if trading.MaxPositionSize <= 0 {
+		p.addf("trading.max_position_size", "must be positive, got %v", trading.MaxPositionSize)
+	}
+	if trading.StopLossPercent <= 0 || trading.StopLossPercent > 50 {
+		p.addf("trading.stop_loss_percent", "must be between 0 and 50, got %v", trading.StopLossPercent)
+	}
+	if trading.TakeProfitPercent <= 0 || trading.TakeProfitPercent > 100 {
+		p.addf("trading.take_profit_percent", "must be between 0 and 100, got %v", trading.TakeProfitPercent)
+	}
+	if trading.StopLossPercent > 0 && trading.TakeProfitPercent > 0 && trading.StopLossPercent >= trading.TakeProfitPercent {
+		p.addf("trading.stop_loss_percent", "must be below take_profit_percent (%v >= %v)", trading.StopLossPercent, trading.TakeProfitPercent)
+	}
+	if trading.MaxLeverage < 1 || trading.MaxLeverage > 125 {
+		p.addf("trading.max_leverage", "must be between 1 and 125, got %d", trading.MaxLeverage)
+	}
+	if trading.RiskPerTrade < 0.1 || trading.RiskPerTrade > 10 {
+		p.addf("trading.risk_per_trade_percent", "must be between 0.1 and 10, got %v", trading.RiskPerTrade)
+	}
+	if trading.TradingInterval <= 0 {
+		p.addf("trading.trading_interval_seconds", "must be positive, got %d", trading.TradingInterval)
+	}
+	if _, err := time.LoadLocation(trading.DayTimezone); err != nil {
+		p.addf("trading.day_timezone", "invalid timezone %q: %v", trading.DayTimezone, err)
+	}
+
+	klineInterval, klineOK := KlineIntervals[trading.KlineInterval]
+	if !klineOK {
+		p.addf("trading.kline_interval", "unsupported interval %q", trading.KlineInterval)
+	}
+	switch trading.EvaluationMode {
+	case EvaluationModeInterval:
+		// Evaluating less often than once per candle skips candles
+		if klineOK && time.Duration(trading.TradingInterval)*time.Second > klineInterval {
+			p.addf("trading.trading_interval_seconds", "must not exceed the %s kline interval, got %ds", trading.KlineInterval, trading.TradingInterval)
+		}
+	case EvaluationModeCandleClose:
+		if klineOK && time.Duration(trading.CandleCloseDelayMs)*time.Millisecond >= klineInterval {
+			p.addf("trading.candle_close_delay_ms", "must be shorter than the %s kline interval, got %d", trading.KlineInterval, trading.CandleCloseDelayMs)
+		}
+	default:
+		p.addf("trading.evaluation_mode", "must be %s or %s, got %q", EvaluationModeInterval, EvaluationModeCandleClose, trading.EvaluationMode)
+	}
+	if trading.CandleCloseDelayMs < 0 {
+		p.addf("trading.candle_close_delay_ms", "must not be negative, got %d", trading.CandleCloseDelayMs)
+	}
+	if trading.AutoPauseErrors < 0 {
+		p.addf("trading.auto_pause_errors", "must not be negative, got %d", trading.AutoPauseErrors)
+	}
+	if trading.MaxConcurrentSymbols < 0 {
+		p.addf("trading.max_concurrent_symbols", "must not be negative, got %d", trading.MaxConcurrentSymbols)
+	}
+	if trading.SymbolTimeoutSeconds < 0 {
+		p.addf("trading.symbol_timeout_seconds", "must not be negative, got %d", trading.SymbolTimeoutSeconds)
+	}
+	if trading.ATRPeriod < 1 || trading.ATRPeriod > 98 {
+		p.addf("trading.atr_period", "must be between 1 and 98, got %d", trading.ATRPeriod)
+	}
+	if trading.MarginType != "CROSSED" && trading.MarginType != "ISOLATED" {
+		p.addf("trading.margin_type", "must be CROSSED or ISOLATED, got %q", trading.MarginType)
+	}
+	if !validWorkingType(trading.StopWorkingType) {
+		p.addf("trading.stop_working_type", "must be MARK_PRICE or CONTRACT_PRICE, got %q", trading.StopWorkingType)
+	}
+	if !validWorkingType(trading.PnLWorkingType) {
+		p.addf("trading.pnl_working_type", "must be MARK_PRICE or CONTRACT_PRICE, got %q", trading.PnLWorkingType)
+	}
+	if trading.Health.StreamMaxAgeSeconds <= 0 {
+		p.addf("trading.health.stream_max_age_seconds", "must be positive, got %d", trading.Health.StreamMaxAgeSeconds)
+	}
+
+	validateSignalFilters(p, "trading.strategy.filters", trading.Strategy.Filters)
+	for strategyType := range trading.Strategy.Filters.Strategies {
+		validateSignalFilters(p, "trading.strategy.filters.strategies."+strategyType, trading.Strategy.Filters.For(strategyType))
+	}
+	validateSizing(p, "trading.sizing", trading.Sizing)
+	for strategyType := range trading.Sizing.Strategies {
+		validateSizing(p, "trading.sizing.strategies."+strategyType, trading.Sizing.For(strategyType))
+	}
+	if guard := trading.StrategyGuard; guard.Enabled {
+		if guard.IntervalMinutes <= 0 {
+			p.addf("trading.strategy_guard.interval_minutes", "must be positive, got %d", guard.IntervalMinutes)
+		}
+		validateStrategyGuard(p, "trading.strategy_guard", guard)
+		for strategyType := range guard.Strategies {
+			validateStrategyGuard(p, "trading.strategy_guard.strategies."+strategyType, guard.For(strategyType))
+		}
+	}
+	if vt := trading.VolTarget; vt.Enabled {
+		if vt.TargetPercent <= 0 {
+			p.addf("trading.vol_target.target_percent", "must be positive, got %v", vt.TargetPercent)
+		}
+		if vt.WindowHours <= 0 || vt.BucketMinutes <= 0 || vt.RefreshMinutes <= 0 {
+			p.addf("trading.vol_target.window_hours", "window, bucket and refresh interval must be positive, got %d, %d and %d", vt.WindowHours, vt.BucketMinutes, vt.RefreshMinutes)
+		}
+		if vt.MinSamples < 2 {
+			p.addf("trading.vol_target.min_samples", "must be at least 2, got %d", vt.MinSamples)
+		}
+		if vt.MinScale <= 0 || vt.MaxScale < vt.MinScale {
+			p.addf("trading.vol_target.min_scale", "must be positive and not above max_scale, got %v and %v", vt.MinScale, vt.MaxScale)
+		}
+	}
+
+	if ds := trading.DrawdownScaling; ds.Enabled {
+		if ds.RefreshMinutes <= 0 {
+			p.addf("trading.drawdown_scaling.refresh_minutes", "must be positive, got %d", ds.RefreshMinutes)
+		}
+		if ds.LookbackDays < 0 {
+			p.addf("trading.drawdown_scaling.lookback_days", "must not be negative, got %d", ds.LookbackDays)
+		}
+		if len(ds.Schedule) == 0 {
+			p.addf("trading.drawdown_scaling.schedule", "at least one step is required when drawdown scaling is enabled")
+		}
+		// Deeper drawdowns must not size up again
+		previous := DrawdownStepConfig{Scale: 1}
+		for i, step := range ds.Schedule {
+			key := fmt.Sprintf("trading.drawdown_scaling.schedule[%d]", i)
+			if step.DrawdownPercent <= previous.DrawdownPercent || step.DrawdownPercent >= 100 {
+				p.addf(key, "drawdown_percent must increase and stay below 100, got %v after %v", step.DrawdownPercent, previous.DrawdownPercent)
+			}
+			if step.Scale <= 0 || step.Scale > previous.Scale {
+				p.addf(key, "scale must be positive and not above the previous step's %v, got %v", previous.Scale, step.Scale)
+			}
+			previous = step
+		}
+	}
+
+	if trading.RiskMetrics.IntervalMinutes <= 0 {
+		p.addf("trading.risk_metrics.interval_minutes", "must be positive, got %d", trading.RiskMetrics.IntervalMinutes)
+	}
+	if trading.RiskMetrics.IntradayDays < 0 {
+		p.addf("trading.risk_metrics.intraday_days", "must not be negative, got %d", trading.RiskMetrics.IntradayDays)
+	}
+
+	if pl := trading.ProfitLock; pl.Enabled {
+		if pl.Target <= 0 {
+			p.addf("trading.profit_lock.target", "must be positive, got %v", pl.Target)
+		}
+		switch pl.Action {
+		case ProfitLockActionStop:
+		case ProfitLockActionReduce:
+			if pl.ReduceScale <= 0 || pl.ReduceScale >= 1 {
+				p.addf("trading.profit_lock.reduce_scale", "must be between 0 and 1, got %v", pl.ReduceScale)
+			}
+		default:
+			p.addf("trading.profit_lock.action", "must be %s or %s, got %q", ProfitLockActionStop, ProfitLockActionReduce, pl.Action)
+		}
+		if pl.Timezone != "" {
+			if _, err := time.LoadLocation(pl.Timezone); err != nil {
+				p.addf("trading.profit_lock.timezone", "invalid timezone %q: %v", pl.Timezone, err)
+			}
+		}
+	}
+
+	if wallets := trading.Wallets; wallets.Enabled {
+		if wallets.SnapshotMinutes <= 0 {
+			p.addf("trading.wallets.snapshot_minutes", "must be positive, got %d", wallets.SnapshotMinutes)
+		}
+		if wallets.RetentionDays < 0 {
+			p.addf("trading.wallets.retention_days", "must not be negative, got %d", wallets.RetentionDays)
+		}
+		seen := make(map[string]bool, len(wallets.Allocations))
+		for i, allocation := range wallets.Allocations {
+			key := fmt.Sprintf("trading.wallets.allocations[%d]", i)
+			if allocation.Strategy == "" {
+				p.addf(key+".strategy", "is required")
+			} else if seen[allocation.Strategy] {
+				p.addf(key+".strategy", "%q is allocated twice", allocation.Strategy)
+			}
+			seen[allocation.Strategy] = true
+			if allocation.Amount <= 0 {
+				p.addf(key+".amount", "must be positive, got %v", allocation.Amount)
+			}
+		}
+	}
+
+	if mm := trading.MarginMonitor; mm.Enabled {
+		if mm.IntervalSeconds <= 0 {
+			p.addf("trading.margin_monitor.interval_seconds", "must be positive, got %d", mm.IntervalSeconds)
+		}
+		if mm.CooldownSeconds < 0 {
+			p.addf("trading.margin_monitor.cooldown_seconds", "must not be negative, got %d", mm.CooldownSeconds)
+		}
+		if len(mm.Stages) == 0 {
+			p.addf("trading.margin_monitor.stages", "at least one stage is required when the margin monitor is enabled")
+		}
+		// Higher ratios must not respond more mildly
+		previous, severity := 0.0, -1
+		for i, stage := range mm.Stages {
+			key := fmt.Sprintf("trading.margin_monitor.stages[%d]", i)
+			if stage.RatioPercent <= previous || stage.RatioPercent >= 100 {
+				p.addf(key, "ratio_percent must increase and stay below 100, got %v after %v", stage.RatioPercent, previous)
+			}
+			level := slices.Index(MarginActions, stage.Action)
+			if level < 0 {
+				p.addf(key, "action must be one of %s, got %q", strings.Join(MarginActions, ", "), stage.Action)
+			} else if level < severity {
+				p.addf(key, "action %q is milder than the previous stage's", stage.Action)
+			}
+			previous, severity = stage.RatioPercent, max(severity, level)
+		}
+	}
+
+	if adl := trading.ADL; adl.Enabled {
+		if adl.IntervalSeconds <= 0 {
+			p.addf("trading.adl.interval_seconds", "must be positive, got %d", adl.IntervalSeconds)
+		}
+		if adl.AlertQuantile < 1 || adl.AlertQuantile > 4 {
+			p.addf("trading.adl.alert_quantile", "must be between 1 and 4, got %d", adl.AlertQuantile)
+		}
+		if adl.RetentionDays < 0 {
+			p.addf("trading.adl.retention_days", "must not be negative, got %d", adl.RetentionDays)
+		}
+	}
+
+	if rejections := trading.Rejections; rejections.Remediate {
+		if rejections.MaxRetries <= 0 {
+			p.addf("trading.rejections.max_retries", "must be positive, got %d", rejections.MaxRetries)
+		}
+		if rejections.HourlyBudget <= 0 {
+			p.addf("trading.rejections.hourly_budget", "must be positive, got %d", rejections.HourlyBudget)
+		}
+		if rejections.MaxResizePercent < 0 {
+			p.addf("trading.rejections.max_resize_percent", "must not be negative, got %v", rejections.MaxResizePercent)
+		}
+	}
+
+	validateTradingFeatures(p, trading)
+	validateRiskControls(p, trading)
+}
+
+func validateSignalFilters(p *problems, key string, c SignalFilterConfig) {
+	if c.MinQuoteVolume24h < 0 || c.MinCandleVolume < 0 {
+		p.addf(key+".min_quote_volume_24h", "volume minimums must not be negative, got %v and %v", c.MinQuoteVolume24h, c.MinCandleVolume)
+	}
+	if c.MaxSpreadBps < 0 {
+		p.addf(key+".max_spread_bps", "must not be negative, got %v", c.MaxSpreadBps)
+	}
+	if c.TrendInterval != "" {
+		if _, ok := KlineIntervals[c.TrendInterval]; !ok {
+			p.addf(key+".trend_interval", "unknown kline interval %q", c.TrendInterval)
+		}
+		if c.TrendPeriod < 2 {
+			p.addf(key+".trend_period", "must be at least 2, got %d", c.TrendPeriod)
+		}
+	}
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		p.addf(key+".timezone", "invalid timezone %q: %v", c.Timezone, err)
+	}
+	for _, window := range c.AllowedHours {
+		if _, _, err := ParseClockRange(window); err != nil {
+			p.addf(key+".allowed_hours", "%v", err)
+		}
+	}
+	if c.CooldownMinutes < 0 {
+		p.addf(key+".cooldown_minutes", "must not be negative, got %d", c.CooldownMinutes)
+	}
+}
+
+// validateShadow checks the candidate strategy with the parameters it will run with and the
+// simulated books
+func validateShadow(p *problems, shadow ShadowConfig, live StrategyConfig) {
+	validateStrategy(p, "trading.shadow", shadow.Candidate(live))
+	if shadow.InitialBalance <= 0 {
+		p.addf("trading.shadow.initial_balance", "must be positive, got %v", shadow.InitialBalance)
+	}
+	if shadow.PositionPercent <= 0 || shadow.PositionPercent > 100 {
+		p.addf("trading.shadow.position_percent", "must be in (0, 100], got %v", shadow.PositionPercent)
+	}
+	if shadow.FeeRate < 0 {
+		p.addf("trading.shadow.fee_rate", "must not be negative, got %v", shadow.FeeRate)
+	}
+}
+
+func validateStrategyGuard(p *problems, key string, c StrategyGuardConfig) {
+	if c.MinTrades < 2 || c.Lookback < c.MinTrades {
+		p.addf(key+".min_trades", "must be at least 2 and not above lookback (%d), got %d", c.Lookback, c.MinTrades)
+	}
+	if c.MaxDrawdown < 0 {
+		p.addf(key+".max_drawdown", "must not be negative, got %v", c.MaxDrawdown)
+	}
+}
+
+func validateSizing(p *problems, key string, c SizingConfig) {
+	switch c.Mode {
+	case SizingModeRisk, SizingModeStrategy:
+	case SizingModeFixedNotional:
+		if c.Notional <= 0 {
+			p.addf(key+".notional", "fixed_notional sizing requires a positive notional, got %v", c.Notional)
+		}
+	case SizingModeFixedFractional:
+		if c.FractionPercent <= 0 || c.FractionPercent > 100 {
+			p.addf(key+".fraction_percent", "must be between 0 and 100 for fixed_fractional sizing, got %v", c.FractionPercent)
+		}
+	case SizingModeVolatilityTarget:
+		if c.TargetVolatilityPercent <= 0 || c.TargetVolatilityPercent > 100 {
+			p.addf(key+".target_volatility_percent", "must be between 0 and 100 for volatility_target sizing, got %v", c.TargetVolatilityPercent)
+		}
+	case SizingModeKelly:
+		if c.KellyFraction <= 0 || c.KellyFraction > 1 {
+			p.addf(key+".kelly_fraction", "must be between 0 and 1, got %v", c.KellyFraction)
+		}
+		if c.KellyLookback < c.KellyMinTrades || c.KellyMinTrades < 1 {
+			p.addf(key+".kelly_lookback", "must satisfy kelly_lookback >= kelly_min_trades >= 1, got %d and %d", c.KellyLookback, c.KellyMinTrades)
+		}
+		if c.KellyMaxPercent <= 0 || c.KellyMaxPercent > 100 {
+			p.addf(key+".kelly_max_percent", "must be between 0 and 100, got %v", c.KellyMaxPercent)
+		}
+	default:
+		p.addf(key+".mode", "unknown sizing mode %q", c.Mode)
+	}
+}
+
+// validateTradingFeatures checks the optional data collection and automation features
+func validateTradingFeatures(p *problems, trading TradingConfig) {
+	if trading.StaleData.Enabled && trading.StaleData.MaxAgeSeconds <= trading.TradingInterval {
+		p.addf("trading.stale_data.max_age_seconds", "must exceed trading_interval_seconds (%d), got %d", trading.TradingInterval, trading.StaleData.MaxAgeSeconds)
+	}
+	if ac := trading.AutoCancel; ac.Enabled {
+		if ac.HeartbeatSeconds <= 0 {
+			p.addf("trading.auto_cancel.heartbeat_seconds", "must be positive, got %d", ac.HeartbeatSeconds)
+		}
+		// A countdown shorter than two refreshes cancels orders after a single slow request
+		if ac.CountdownSeconds < 2*ac.HeartbeatSeconds {
+			p.addf("trading.auto_cancel.countdown_seconds", "must be at least twice heartbeat_seconds (%d), got %d", ac.HeartbeatSeconds, ac.CountdownSeconds)
+		}
+		for symbol, seconds := range ac.Symbols {
+			if seconds != 0 && seconds < 2*ac.HeartbeatSeconds {
+				p.addf("trading.auto_cancel.symbols."+symbol, "must be 0 or at least twice heartbeat_seconds (%d), got %d", ac.HeartbeatSeconds, seconds)
+			}
+		}
+	}
+	if trading.Drift.Enabled && trading.Drift.IntervalMinutes <= 0 {
+		p.addf("trading.drift.interval_minutes", "must be positive, got %d", trading.Drift.IntervalMinutes)
+	}
+	if orderFlow := trading.OrderFlow; orderFlow.Enabled {
+		if orderFlow.WindowSeconds <= 0 {
+			p.addf("trading.order_flow.window_seconds", "must be positive, got %d", orderFlow.WindowSeconds)
+		}
+		if orderFlow.LargeTradeNotional <= 0 {
+			p.addf("trading.order_flow.large_trade_notional", "must be positive, got %v", orderFlow.LargeTradeNotional)
+		}
+	}
+	if liquidations := trading.Liquidations; liquidations.Enabled {
+		if liquidations.WindowSeconds <= 0 {
+			p.addf("trading.liquidations.window_seconds", "must be positive, got %d", liquidations.WindowSeconds)
+		}
+		if liquidations.AlarmNotional < 0 {
+			p.addf("trading.liquidations.alarm_notional", "must not be negative, got %v", liquidations.AlarmNotional)
+		}
+	}
+	if trading.Warmup.Enabled && trading.Warmup.BufferCandles < 0 {
+		p.addf("trading.warmup.buffer_candles", "must not be negative, got %d", trading.Warmup.BufferCandles)
+	}
+	if trading.Journal.Enabled && (trading.Journal.KlineWindow < 0 || trading.Journal.KlineWindow > 1500) {
+		p.addf("trading.journal.kline_window", "must be between 0 and 1500, got %d", trading.Journal.KlineWindow)
+	}
+	if report := trading.DailyReport; report.Enabled {
+		if report.Hour < 0 || report.Hour > 23 {
+			p.addf("trading.daily_report.hour", "must be between 0 and 23, got %d", report.Hour)
+		}
+		if report.BenchmarkDays < 0 || report.BenchmarkDays > 365 {
+			p.addf("trading.daily_report.benchmark_days", "must be between 0 and 365, got %d", report.BenchmarkDays)
+		}
+	}
+	if trading.Scheduler.JitterSeconds < 0 {
+		p.addf("trading.scheduler.jitter_seconds", "must not be negative, got %d", trading.Scheduler.JitterSeconds)
+	}
+	for job, spec := range trading.Scheduler.Jobs {
+		if !validSchedulerJob(job) {
+			p.addf("trading.scheduler.jobs."+job, "unknown job, expected one of %s", strings.Join(SchedulerJobs, ", "))
+		} else if _, err := scheduler.Parse(spec); err != nil {
+			p.addf("trading.scheduler.jobs."+job, "%v", err)
+		}
+	}
+	if remote := trading.RemoteConfig; remote.Enabled {
+		if remote.RiskLimitsName == "" {
+			p.addf("trading.remote_config.risk_limits_name", "is required")
+		}
+		if remote.StrategyName == "" {
+			p.addf("trading.remote_config.strategy_name", "is required")
+		}
+		if remote.PollSeconds <= 0 {
+			p.addf("trading.remote_config.poll_seconds", "must be positive, got %d", remote.PollSeconds)
+		}
+	}
+	retention := trading.Retention
+	if retention.KlineWindow < 0 || retention.KlineWindow > 10000 {
+		p.addf("trading.retention.kline_window", "must be between 0 and 10000, got %d", retention.KlineWindow)
+	}
+	if retention.MaxLiquidationEvents <= 0 {
+		p.addf("trading.retention.max_liquidation_events", "must be positive, got %d", retention.MaxLiquidationEvents)
+	}
+	if retention.PersistMarketData {
+		if retention.PersistIntervalSeconds < 0 {
+			p.addf("trading.retention.persist_interval_seconds", "must not be negative, got %d", retention.PersistIntervalSeconds)
+		}
+		if retention.WriterQueueSize <= 0 {
+			p.addf("trading.retention.writer_queue_size", "must be positive, got %d", retention.WriterQueueSize)
+		}
+		if retention.WriterBatchSize <= 0 || retention.WriterBatchSize > retention.WriterQueueSize {
+			p.addf("trading.retention.writer_batch_size", "must be between 1 and writer_queue_size (%d), got %d", retention.WriterQueueSize, retention.WriterBatchSize)
+		}
+		if retention.WriterFlushMillis <= 0 {
+			p.addf("trading.retention.writer_flush_ms", "must be positive, got %d", retention.WriterFlushMillis)
+		}
+		if retention.EnqueueTimeoutMillis < 0 {
+			p.addf("trading.retention.enqueue_timeout_ms", "must not be negative, got %d", retention.EnqueueTimeoutMillis)
+		}
+	}
+	if delisting := trading.Delisting; delisting.Enabled {
+		if delisting.IntervalMinutes <= 0 {
+			p.addf("trading.delisting.interval_minutes", "must be positive, got %d", delisting.IntervalMinutes)
+		}
+		if delisting.CloseBeforeHours < 0 {
+			p.addf("trading.delisting.close_before_hours", "must not be negative, got %d", delisting.CloseBeforeHours)
+		}
+	}
+	if regime := trading.LeverageRegime; regime.Enabled {
+		if regime.IntervalMinutes <= 0 {
+			p.addf("trading.leverage_regime.interval_minutes", "must be positive, got %d", regime.IntervalMinutes)
+		}
+		if _, ok := KlineIntervals[regime.KlineInterval]; !ok {
+			p.addf("trading.leverage_regime.kline_interval", "unsupported interval %q", regime.KlineInterval)
+		}
+		if regime.Window < 2 || regime.Lookback < 1 || regime.Window+regime.Lookback >= 1500 {
+			p.addf("trading.leverage_regime.window", "window must be at least 2, lookback at least 1 and together below 1500 candles, got %d and %d", regime.Window, regime.Lookback)
+		}
+		if regime.LowPercentile < 0 || regime.LowPercentile >= regime.HighPercentile || regime.HighPercentile > 100 {
+			p.addf("trading.leverage_regime.low_percentile", "percentiles must satisfy 0 <= low < high <= 100, got %v and %v", regime.LowPercentile, regime.HighPercentile)
+		}
+		if regime.MinLeverage < 1 || regime.MinLeverage > regime.MaxLeverage || regime.MaxLeverage > trading.MaxLeverage {
+			p.addf("trading.leverage_regime.min_leverage", "bounds must satisfy 1 <= min <= max <= trading.max_leverage (%d), got %d and %d", trading.MaxLeverage, regime.MinLeverage, regime.MaxLeverage)
+		}
+	}
+
+	if trading.PositionSync.Enabled && trading.PositionSync.IntervalSeconds <= 0 {
+		p.addf("trading.position_sync.interval_seconds", "must be positive, got %d", trading.PositionSync.IntervalSeconds)
+	}
+
+	if incomeSync := trading.IncomeSync; incomeSync.Enabled {
+		if incomeSync.IntervalMinutes <= 0 {
+			p.addf("trading.income_sync.interval_minutes", "must be positive, got %d", incomeSync.IntervalMinutes)
+		}
+		if incomeSync.BackfillDays <= 0 {
+			p.addf("trading.income_sync.backfill_days", "must be positive, got %d", incomeSync.BackfillDays)
+		}
+		if incomeSync.Tolerance < 0 {
+			p.addf("trading.income_sync.tolerance", "must not be negative, got %v", incomeSync.Tolerance)
+		}
+	}
+	if state := trading.Strategy.State; state.Enabled {
+		if state.SaveIntervalSeconds <= 0 {
+			p.addf("trading.strategy.state.save_interval_seconds", "must be positive, got %d", state.SaveIntervalSeconds)
+		}
+		if state.MaxAgeMinutes < 0 {
+			p.addf("trading.strategy.state.max_age_minutes", "must not be negative, got %d", state.MaxAgeMinutes)
+		}
+	}
+	if sandbox := trading.Strategy.Sandbox; sandbox.Enabled {
+		if sandbox.TimeoutMs <= 0 {
+			p.addf("trading.strategy.sandbox.timeout_ms", "must be positive, got %d", sandbox.TimeoutMs)
+		}
+		if sandbox.MaxConsecutiveTimeouts < 0 || sandbox.QuarantineSeconds < 0 {
+			p.addf("trading.strategy.sandbox.quarantine_seconds", "quarantine settings must not be negative, got %d and %d", sandbox.MaxConsecutiveTimeouts, sandbox.QuarantineSeconds)
+		}
+		if sandbox.MemoryLimitMB < 0 || sandbox.CPULimitSeconds < 0 {
+			p.addf("trading.strategy.sandbox.memory_limit_mb", "process limits must not be negative, got %d and %d", sandbox.MemoryLimitMB, sandbox.CPULimitSeconds)
+		}
+	}
+	if corr := trading.Correlation; corr.Enabled {
+		if corr.WindowHours <= 0 || corr.BucketMinutes <= 0 || corr.RefreshMinutes <= 0 {
+			p.addf("trading.correlation.window_hours", "window, bucket and refresh interval must be positive, got %d, %d and %d", corr.WindowHours, corr.BucketMinutes, corr.RefreshMinutes)
+		}
+		if corr.Limit <= 0 || corr.Limit > 1 {
+			p.addf("trading.correlation.limit", "must be between 0 and 1, got %v", corr.Limit)
+		}
+		if corr.MaxCorrelatedExposure < 0 {
+			p.addf("trading.correlation.max_correlated_exposure", "must not be negative, got %v", corr.MaxCorrelatedExposure)
+		}
+	}
+	if exposure := trading.Exposure; exposure.MaxNetDelta < 0 || exposure.MaxGrossExposure < 0 {
+		p.addf("trading.exposure.max_net_delta", "limits must not be negative, got %v and %v", exposure.MaxNetDelta, exposure.MaxGrossExposure)
+	}
+	for asset, limit := range trading.Exposure.MaxQuoteExposure {
+		if limit < 0 {
+			p.addf("trading.exposure.max_quote_exposure."+asset, "must not be negative, got %v", limit)
+		}
+	}
+	if pos := trading.Positioning; pos.Enabled {
+		if pos.IntervalSeconds < 60 {
+			p.addf("trading.positioning.interval_seconds", "must be at least 60, got %d", pos.IntervalSeconds)
+		}
+		switch pos.Period {
+		case "5m", "15m", "30m", "1h", "2h", "4h", "6h", "12h", "1d":
+		default:
+			p.addf("trading.positioning.period", "invalid long/short ratio period %q", pos.Period)
+		}
+	}
+	if options := trading.Options; options.Enabled {
+		if options.Venue != "deribit" {
+			p.addf("trading.options.venue", "must be deribit, got %q", options.Venue)
+		}
+		if options.IntervalSeconds < 60 {
+			p.addf("trading.options.interval_seconds", "must be at least 60, got %d", options.IntervalSeconds)
+		}
+		if options.TenorDays <= 0 {
+			p.addf("trading.options.tenor_days", "must be positive, got %d", options.TenorDays)
+		}
+		if options.SkewMoneynessPercent <= 0 || options.SkewMoneynessPercent >= 100 {
+			p.addf("trading.options.skew_moneyness_percent", "must be between 0 and 100, got %v", options.SkewMoneynessPercent)
+		}
+		if options.TimeoutSeconds <= 0 {
+			p.addf("trading.options.timeout_seconds", "must be positive, got %d", options.TimeoutSeconds)
+		}
+	}
+	if sessions := trading.Sessions; sessions.Enabled {
+		if _, err := time.LoadLocation(sessions.Timezone); err != nil {
+			p.addf("trading.sessions.timezone", "invalid timezone %q: %v", sessions.Timezone, err)
+		}
+		for _, window := range sessions.TradingHours {
+			if _, _, err := ParseClockRange(window); err != nil {
+				p.addf("trading.sessions.trading_hours", "%v", err)
+			}
+		}
+		for _, window := range sessions.DailyBlackouts {
+			if _, _, err := ParseClockRange(window); err != nil {
+				p.addf("trading.sessions.daily_blackouts", "%v", err)
+			}
+		}
+		for _, blackout := range sessions.Blackouts {
+			start, startErr := time.Parse(time.RFC3339, blackout.Start)
+			if startErr != nil {
+				p.addf("trading.sessions.blackouts", "invalid start for blackout %q: %v", blackout.Name, startErr)
+			}
+			end, endErr := time.Parse(time.RFC3339, blackout.End)
+			if endErr != nil {
+				p.addf("trading.sessions.blackouts", "invalid end for blackout %q: %v", blackout.Name, endErr)
+			}
+			if startErr == nil && endErr == nil && !end.After(start) {
+				p.addf("trading.sessions.blackouts", "blackout %q must end after it starts", blackout.Name)
+			}
+		}
+		if sessions.FundingBlackoutMinutes < 0 {
+			p.addf("trading.sessions.funding_blackout_minutes", "must not be negative, got %d", sessions.FundingBlackoutMinutes)
+		}
+	}
+	if arb := trading.FundingArbitrage; arb.Enabled {
+		if len(arb.Symbols) == 0 {
+			p.addf("trading.funding_arbitrage.symbols", "at least one symbol is required")
+		}
+		for _, symbol := range arb.Symbols {
+			for _, traded := range trading.Symbols {
+				if symbol == traded {
+					p.addf("trading.funding_arbitrage.symbols", "%s is also traded by the strategy", symbol)
+				}
+			}
+		}
+		if arb.ExitAnnualizedRate >= arb.EntryAnnualizedRate {
+			p.addf("trading.funding_arbitrage.exit_annualized_rate", "must be below entry_annualized_rate (%v >= %v)", arb.ExitAnnualizedRate, arb.EntryAnnualizedRate)
+		}
+		if arb.NotionalUSDT <= 0 || arb.FundingIntervalHours <= 0 || arb.CheckIntervalMinutes <= 0 {
+			p.addf("trading.funding_arbitrage.notional_usdt", "notional, funding interval and check interval must be positive, got %v, %v and %d", arb.NotionalUSDT, arb.FundingIntervalHours, arb.CheckIntervalMinutes)
+		}
+		if arb.Leverage < 1 || arb.Leverage > 125 {
+			p.addf("trading.funding_arbitrage.leverage", "must be between 1 and 125, got %d", arb.Leverage)
+		}
+	}
+	if alerts := trading.PnLAlerts; alerts.Enabled {
+		if len(alerts.Bands) == 0 {
+			p.addf("trading.pnl_alerts.bands", "at least one band is required when PnL alerts are enabled")
+		}
+		for _, band := range alerts.Bands {
+			if band == 0 {
+				p.addf("trading.pnl_alerts.bands", "bands must be non-zero")
+				break
+			}
+		}
+		if alerts.Hysteresis < 0 {
+			p.addf("trading.pnl_alerts.hysteresis_percent", "must not be negative, got %v", alerts.Hysteresis)
+		}
+	}
+}
+
+// validateRiskControls checks the per-position and per-symbol risk settings
+func validateRiskControls(p *problems, trading TradingConfig) {
+	if cooldown := trading.Cooldown; cooldown.MaxConsecutiveLosses < 0 || cooldown.CooldownMinutes < 0 || cooldown.MinReentryMinutes < 0 {
+		p.addf("trading.cooldown", "settings must not be negative, got %d, %d and %d", cooldown.MaxConsecutiveLosses, cooldown.CooldownMinutes, cooldown.MinReentryMinutes)
+	}
+	if slippage := trading.Slippage; slippage.Enabled {
+		if slippage.MaxSlippageBps <= 0 {
+			p.addf("trading.slippage.max_slippage_bps", "must be positive, got %v", slippage.MaxSlippageBps)
+		}
+		if slippage.MaxSpreadBps < 0 {
+			p.addf("trading.slippage.max_spread_bps", "must not be negative, got %v", slippage.MaxSpreadBps)
+		}
+		if slippage.Action != "reject" && slippage.Action != "limit" {
+			p.addf("trading.slippage.action", "must be reject or limit, got %q", slippage.Action)
+		}
+		switch slippage.BookDepth {
+		case 5, 10, 20, 50, 100, 500, 1000:
+		default:
+			p.addf("trading.slippage.book_depth", "must be one of 5, 10, 20, 50, 100, 500, 1000, got %d", slippage.BookDepth)
+		}
+	}
+	if ladder := trading.TakeProfitLadder; ladder.Enabled {
+		if len(ladder.Levels) == 0 {
+			p.addf("trading.take_profit_ladder.levels", "at least one level is required when the ladder is enabled")
+		}
+		total := 0.0
+		for i, level := range ladder.Levels {
+			if level.Percent <= 0 || level.Fraction <= 0 {
+				p.addf(fmt.Sprintf("trading.take_profit_ladder.levels[%d]", i), "percent and fraction must be positive, got %v and %v", level.Percent, level.Fraction)
+			}
+			total += level.Fraction
+		}
+		if total > 1 {
+			p.addf("trading.take_profit_ladder.levels", "fractions must not add up to more than 1, got %v", total)
+		}
+		if cb := ladder.TrailingCallbackPercent; cb != 0 && (cb < 0.1 || cb > 5) {
+			p.addf("trading.take_profit_ladder.trailing_callback_percent", "must be between 0.1 and 5, got %v", cb)
+		}
+	}
+	if pyramiding := trading.Pyramiding; pyramiding.Enabled {
+		if pyramiding.MaxAddOns <= 0 {
+			p.addf("trading.pyramiding.max_add_ons", "must be positive, got %d", pyramiding.MaxAddOns)
+		}
+		if pyramiding.SpacingPercent < 0 {
+			p.addf("trading.pyramiding.spacing_percent", "must not be negative, got %v", pyramiding.SpacingPercent)
+		}
+		if pyramiding.SizeDecay <= 0 || pyramiding.SizeDecay > 1 {
+			p.addf("trading.pyramiding.size_decay", "must be between 0 and 1, got %v", pyramiding.SizeDecay)
+		}
+	}
+}
+
+// validateServices checks the database and the auxiliary services
+func validateServices(p *problems, config *Config) {
+	if config.Database.MySQL.DSN == "" {
+		p.addf("database.mysql.dsn", "is required")
+	}
+	if config.Database.Redis.Addr == "" {
+		p.addf("database.redis.addr", "is required")
+	}
+	if monitoring := config.Database.Monitoring; monitoring.SlowQueryMs < 0 {
+		p.addf("database.monitoring.slow_query_ms", "must not be negative, got %d", monitoring.SlowQueryMs)
+	}
+	if percent := config.Database.Monitoring.PoolSoftCapPercent; percent <= 0 || percent > 100 {
+		p.addf("database.monitoring.pool_soft_cap_percent", "must be between 0 and 100, got %v", percent)
+	}
+	if config.Database.Monitoring.SampleSeconds <= 0 {
+		p.addf("database.monitoring.sample_seconds", "must be positive, got %d", config.Database.Monitoring.SampleSeconds)
+	}
+
+	if outbox := config.Notifications.Outbox; outbox.Enabled {
+		if outbox.PollSeconds <= 0 {
+			p.addf("notifications.outbox.poll_seconds", "must be positive, got %d", outbox.PollSeconds)
+		}
+		if outbox.BatchSize <= 0 {
+			p.addf("notifications.outbox.batch_size", "must be positive, got %d", outbox.BatchSize)
+		}
+		if outbox.MaxAttempts <= 0 {
+			p.addf("notifications.outbox.max_attempts", "must be positive, got %d", outbox.MaxAttempts)
+		}
+		if outbox.RetryBaseSeconds <= 0 || outbox.RetryMaxSeconds < outbox.RetryBaseSeconds {
+			p.addf("notifications.outbox", "retry_base_seconds must be positive and at most retry_max_seconds, got %d and %d",
+				outbox.RetryBaseSeconds, outbox.RetryMaxSeconds)
+		}
+		if outbox.RetentionDays < 0 {
+			p.addf("notifications.outbox.retention_days", "must not be negative, got %d", outbox.RetentionDays)
+		}
+	} else if len(config.Notifications.EventWebhookURLs) > 0 {
+		p.addf("notifications.event_webhook_urls", "requires notifications.outbox.enabled")
+	}
+
+	if feeds := config.Feeds; feeds.Enabled {
+		if feeds.CryptoPanicToken == "" && len(feeds.RSSURLs) == 0 {
+			p.addf("feeds", "at least one source (cryptopanic_token or rss_urls) is required when feeds are enabled")
+		}
+		if feeds.PollIntervalSeconds <= 0 {
+			p.addf("feeds.poll_interval_seconds", "must be positive, got %d", feeds.PollIntervalSeconds)
+		}
+		if feeds.WindowHours <= 0 {
+			p.addf("feeds.window_hours", "must be positive, got %d", feeds.WindowHours)
+		}
+		if feeds.MinBuySentiment < -1 || feeds.MinBuySentiment > 1 {
+			p.addf("feeds.min_buy_sentiment", "must be between -1 and 1, got %v", feeds.MinBuySentiment)
+		}
+	}
+
+	if spreads := config.Spreads; spreads.Enabled {
+		if len(spreads.Venues) == 0 {
+			p.addf("spreads.venues", "at least one venue is required when the spread monitor is enabled")
+		}
+		if spreads.IntervalSeconds <= 0 {
+			p.addf("spreads.interval_seconds", "must be positive, got %d", spreads.IntervalSeconds)
+		}
+		if spreads.AlertBps <= 0 {
+			p.addf("spreads.alert_bps", "must be positive, got %v", spreads.AlertBps)
+		}
+		if spreads.ResetBps < 0 || spreads.ResetBps >= spreads.AlertBps {
+			p.addf("spreads.reset_bps", "must be between 0 and alert_bps (%v), got %v", spreads.AlertBps, spreads.ResetBps)
+		}
+	}
+
+	if config.API.Enabled && config.API.ListenAddr == "" {
+		p.addf("api.listen_addr", "is required when the API is enabled")
+	}
+	// The loader does not expand ${...}; a placeholder left in place would be a publicly known admin token
+	if token := config.API.AuthToken; token != "" && (strings.Contains(token, "${") || len(token) < 16) {
+		p.addf("api.auth_token", "must be at least 16 characters and not an unexpanded ${...} placeholder")
+	}
+	names := make(map[string]bool, len(config.API.Keys))
+	for i, key := range config.API.Keys {
+		prefix := fmt.Sprintf("api.keys[%d]", i)
+		if key.Name == "" {
+			p.addf(prefix+".name", "is required")
+		} else if names[key.Name] {
+			p.addf(prefix+".name", "duplicate key name %q", key.Name)
+		}
+		names[key.Name] = true
+		if len(key.Key) < 16 {
+			p.addf(prefix+".key", "must be at least 16 characters")
+		}
+		if key.Role != APIRoleViewer && key.Role != APIRoleOperator && key.Role != APIRoleAdmin {
+			p.addf(prefix+".role", "must be viewer, operator or admin, got %q", key.Role)
+		}
+	}
+	if secret := config.API.JWT.Secret; secret != "" && len(secret) < 32 {
+		p.addf("api.jwt.secret", "must be at least 32 characters")
+	}
+
+	if config.AuditLog.Enabled {
+		if key, err := hex.DecodeString(config.AuditLog.EncryptionKey); err != nil || len(key) != 32 {
+			p.addf("audit_log.encryption_key", "must be 32 bytes hex encoded (64 characters)")
+		}
+	}
+
+	if commentary := config.Commentary; commentary.Enabled {
+		if commentary.Endpoint == "" {
+			p.addf("commentary.endpoint", "is required when commentary is enabled")
+		}
+		if commentary.Model == "" {
+			p.addf("commentary.model", "is required when commentary is enabled")
+		}
+		if commentary.TimeoutSeconds <= 0 {
+			p.addf("commentary.timeout_seconds", "must be positive, got %d", commentary.TimeoutSeconds)
+		}
+	}
+}
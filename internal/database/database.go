@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -64,39 +67,22 @@ func InitRedis(cfg config.RedisConfig) (*redis.Client, error) {
 	return rdb, nil
 }
 
-// AutoMigrate runs database migrations
-func AutoMigrate(db *gorm.DB) error {
-	models := []interface{}{
-		&models.TradingConfig{},
-		&models.Order{},
-		&models.Position{},
-		&models.Trade{},
-		&models.Account{},
-		&models.Balance{},
-		&models.Symbol{},
-		&models.MarketData{},
-		&models.Strategy{},
-		&models.RiskMetric{},
-	}
-
-	for _, model := range models {
-		if err := db.AutoMigrate(model); err != nil {
-			return fmt.Errorf("failed to migrate %T: %w", model, err)
-		}
-	}
-
-	return nil
-}
-
 // Repository interface for database operations
 type Repository interface {
+	// WithTx runs fn with a repository whose writes commit together when fn returns nil and
+	// are rolled back when it returns an error or panics. Calls nest as savepoints.
+	WithTx(fn func(tx Repository) error) error
+
 	// Order operations
 	CreateOrder(order *models.Order) error
 	UpdateOrder(order *models.Order) error
 	GetOrder(id uint) (*models.Order, error)
 	GetOrderByExchangeID(exchangeOrderID string) (*models.Order, error)
+	GetOrderByClientID(clientOrderID string) (*models.Order, error)
 	GetOpenOrders(symbol string) ([]*models.Order, error)
 	GetOrderHistory(symbol string, limit int) ([]*models.Order, error)
+	GetPositionOrders(positionID uint) ([]*models.Order, error)
+	GetRejectedOrders(since time.Time, limit int) ([]*models.Order, error)
 
 	// Position operations
 	CreatePosition(position *models.Position) error
@@ -104,15 +90,23 @@ type Repository interface {
 	GetPosition(symbol, side string) (*models.Position, error)
 	GetAllPositions() ([]*models.Position, error)
 	ClosePosition(id uint, closePrice float64, closedPnL float64) error
+	UpdatePositionNotes(id uint, notes string) error
+	UpdatePositionCosts(id uint, commission, fundingFee, netPnL float64) error
+	UpdatePositionPrices(id uint, markPrice, lastPrice, unrealizedPnL, percentage float64) error
 
 	// Trade operations
 	CreateTrade(trade *models.Trade) error
 	GetTradeHistory(symbol string, limit int) ([]*models.Trade, error)
 	GetTradesByOrder(orderID uint) ([]*models.Trade, error)
+	GetRealizedTrades(strategy string, limit int) ([]*models.Trade, error)
+	GetTradesBefore(end time.Time) ([]*models.Trade, error)
+	GetTradesSince(since time.Time) ([]*models.Trade, error)
 
 	// Account operations
 	UpdateAccount(account *models.Account) error
 	GetLatestAccount() (*models.Account, error)
+	GetPeakEquity(since time.Time) (float64, error)
+	GetAccountsSince(since time.Time) ([]*models.Account, error)
 	UpdateBalance(balance *models.Balance) error
 	GetBalances(accountID uint) ([]*models.Balance, error)
 
@@ -123,26 +117,118 @@ type Repository interface {
 
 	// Market data operations
 	SaveMarketData(data *models.MarketData) error
+	SaveMarketDataBatch(data []*models.MarketData) error
 	GetLatestMarketData(symbol string) (*models.MarketData, error)
+	GetMarketDataSince(symbol string, since int64) ([]*models.MarketData, error)
+	SavePositioningData(data *models.PositioningData) error
+	GetLatestPositioningData(symbol string) (*models.PositioningData, error)
+	SaveOptionsVolatility(sample *models.OptionsVolatilitySample) error
+	SaveOrderFlowSamples(samples []*models.OrderFlowSample) error
+	GetOrderFlowSamples(symbol string, start, end int64) ([]*models.OrderFlowSample, error)
+	SaveLiquidation(liquidation *models.Liquidation) error
+	GetLiquidations(symbol string, start, end time.Time) ([]*models.Liquidation, error)
 
 	// Strategy operations
 	CreateStrategy(strategy *models.Strategy) error
 	UpdateStrategy(strategy *models.Strategy) error
 	GetStrategy(name string) (*models.Strategy, error)
 	GetActiveStrategies() ([]*models.Strategy, error)
+	SaveStrategyVersion(strategy *models.Strategy, change *models.ConfigChange) error
 
 	// Risk metrics operations
 	SaveRiskMetric(metric *models.RiskMetric) error
 	GetRiskMetrics(days int) ([]*models.RiskMetric, error)
 	GetLatestRiskMetric() (*models.RiskMetric, error)
+	SaveRiskMetricPoint(point *models.RiskMetricPoint) error
+	GetRiskMetricPoints(since time.Time) ([]*models.RiskMetricPoint, error)
+	DeleteRiskMetricPointsBefore(before time.Time) (int64, error)
 
 	// Trading config operations
 	CreateTradingConfig(config *models.TradingConfig) error
 	UpdateTradingConfig(config *models.TradingConfig) error
 	GetTradingConfig(name string) (*models.TradingConfig, error)
 	GetActiveTradingConfigs() ([]*models.TradingConfig, error)
+	SaveTradingConfigVersion(config *models.TradingConfig, change *models.ConfigChange) error
+	GetConfigChanges(entity, name string, limit int) ([]*models.ConfigChange, error)
+
+	// Sentiment operations
+	SaveSentimentItem(item *models.SentimentItem) error
+	GetSentimentItems(symbol string, since time.Time) ([]*models.SentimentItem, error)
+
+	// Audit operations
+	CreateAuditEvent(event *models.AuditEvent) error
+	GetAuditEvents(category string, limit int) ([]*models.AuditEvent, error)
+	CreateOrderAuditEntry(entry *models.OrderAuditEntry) error
+	GetLastOrderAuditEntry() (*models.OrderAuditEntry, error)
+	GetOrderAuditEntries(afterSeq uint64, limit int) ([]*models.OrderAuditEntry, error)
+
+	// Cooldown operations
+	SaveSymbolCooldown(cooldown *models.SymbolCooldown) error
+	GetSymbolCooldowns() ([]*models.SymbolCooldown, error)
+
+	// Grid operations
+	SaveGridState(state *models.GridState) error
+	GetGridStates(symbol string) ([]*models.GridState, error)
+	DeleteGridStates(symbol string) error
+
+	// Strategy state operations
+	SaveStrategyState(state *models.StrategyState) error
+	GetStrategyState(strategy, symbol string) (*models.StrategyState, error)
+
+	// Strategy pause operations
+	SaveStrategyPause(pause *models.StrategyPause) error
+	GetStrategyPause(strategy string) (*models.StrategyPause, error)
+
+	// Shadow test operations
+	CreateShadowSignal(signal *models.ShadowSignal) error
+
+	// Journal operations
+	CreateJournalEntry(entry *models.TradeJournal) error
+	GetJournalEntries(symbol string, positionID uint, limit int) ([]*models.TradeJournal, error)
+	GetJournalEntry(id uint) (*models.TradeJournal, error)
+
+	// Outbox operations
+	CreateOutboxMessage(message *models.OutboxMessage) error
+	GetDueOutboxMessages(now time.Time, limit int) ([]*models.OutboxMessage, error)
+	UpdateOutboxMessage(message *models.OutboxMessage) error
+	GetOutboxMessages(status string, limit int) ([]*models.OutboxMessage, error)
+	RequeueOutboxMessage(id uint) error
+	DeleteDeliveredOutboxMessagesBefore(before time.Time) (int64, error)
+
+	// Wallet operations
+	CreateWalletTransfer(transfer *models.WalletTransfer) error
+	GetWalletTransfers(strategy string, limit int) ([]*models.WalletTransfer, error)
+	SumClosedNetPnL(strategy string, since time.Time) (float64, error)
+	SaveWalletSnapshot(snapshot *models.WalletSnapshot) error
+	GetWalletSnapshots(strategy string, since time.Time) ([]*models.WalletSnapshot, error)
+	DeleteWalletSnapshotsBefore(before time.Time) (int64, error)
+
+	// ADL quantile operations
+	SaveADLQuantiles(quantiles []*models.ADLQuantile) error
+	GetADLQuantiles(symbol string, since time.Time) ([]*models.ADLQuantile, error)
+	DeleteADLQuantilesBefore(before time.Time) (int64, error)
+
+	// Funding arbitrage operations
+	CreateFundingArbPosition(position *models.FundingArbPosition) error
+	UpdateFundingArbPosition(position *models.FundingArbPosition) error
+	GetOpenFundingArbPosition(symbol string) (*models.FundingArbPosition, error)
+
+	// Spread operations
+	SaveSpreadSample(sample *models.SpreadSample) error
+	GetSpreadSamples(symbol, venue string, since time.Time) ([]*models.SpreadSample, error)
+
+	// Income operations
+	SaveIncomes(incomes []*models.Income) error
+	GetLatestIncomeTime() (time.Time, error)
+	SumIncome(symbol, incomeType string, start, end time.Time) (float64, error)
+	GetClosedPositions(start, end time.Time) ([]*models.Position, error)
+	GetIncomes(start, end time.Time) ([]*models.Income, error)
 }
 
+// ErrVersionConflict is returned when a versioned row was changed by someone else since it
+// was read
+var ErrVersionConflict = errors.New("version conflict: the row was changed concurrently")
+
 // MySQLRepository implements Repository interface
 type MySQLRepository struct {
 	db *gorm.DB
@@ -153,13 +239,25 @@ func NewMySQLRepository(db *gorm.DB) Repository {
 	return &MySQLRepository{db: db}
 }
 
+func (r *MySQLRepository) WithTx(fn func(tx Repository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&MySQLRepository{db: tx})
+	})
+}
+
 // Order operations
 func (r *MySQLRepository) CreateOrder(order *models.Order) error {
+	order.Version = max(order.Version, 1)
 	return r.db.Create(order).Error
 }
 
+// UpdateOrder saves every column of order and bumps its version. It fails with
+// ErrVersionConflict when the order was changed since it was read.
 func (r *MySQLRepository) UpdateOrder(order *models.Order) error {
-	return r.db.Save(order).Error
+	if order.ID == 0 {
+		return r.CreateOrder(order)
+	}
+	return r.updateVersioned(order, &order.Version)
 }
 
 func (r *MySQLRepository) GetOrder(id uint) (*models.Order, error) {
@@ -203,13 +301,52 @@ func (r *MySQLRepository) GetOrderHistory(symbol string, limit int) ([]*models.O
 	return orders, err
 }
 
+func (r *MySQLRepository) GetPositionOrders(positionID uint) ([]*models.Order, error) {
+	var orders []*models.Order
+	err := r.db.Where("position_id = ?", positionID).Order("id ASC").Find(&orders).Error
+	return orders, err
+}
+
+// GetRejectedOrders returns orders rejected by the exchange since the given time, newest
+// first; a limit of 0 returns all
+func (r *MySQLRepository) GetRejectedOrders(since time.Time, limit int) ([]*models.Order, error) {
+	var orders []*models.Order
+	query := r.db.Where("status = ? AND created_at >= ?", "REJECTED", since).Order("created_at DESC, id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&orders).Error
+	return orders, err
+}
+
 // Position operations
 func (r *MySQLRepository) CreatePosition(position *models.Position) error {
+	position.Version = max(position.Version, 1)
 	return r.db.Create(position).Error
 }
 
+// UpdatePosition saves every column of position and bumps its version. It fails with
+// ErrVersionConflict when the position was changed since it was read.
 func (r *MySQLRepository) UpdatePosition(position *models.Position) error {
-	return r.db.Save(position).Error
+	if position.ID == 0 {
+		return r.CreatePosition(position)
+	}
+	return r.updateVersioned(position, &position.Version)
+}
+
+// updateVersioned writes row only if its stored version is still *version, which it bumps.
+// The version is left unchanged when the write fails.
+func (r *MySQLRepository) updateVersioned(row interface{}, version *uint) error {
+	*version++
+	result := r.db.Model(row).Where("version = ?", *version-1).Select("*").Updates(row)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		*version--
+		return result.Error
+	}
+	return nil
 }
 
 func (r *MySQLRepository) GetPosition(symbol, side string) (*models.Position, error) {
@@ -227,12 +364,46 @@ func (r *MySQLRepository) GetAllPositions() ([]*models.Position, error) {
 	return positions, err
 }
 
+// ClosePosition closes an open position. It fails with ErrVersionConflict when the position
+// is no longer open, so a close and its PnL are recorded only once.
 func (r *MySQLRepository) ClosePosition(id uint, closePrice float64, closedPnL float64) error {
 	now := time.Now()
-	return r.db.Model(&models.Position{}).Where("id = ?", id).Updates(map[string]interface{}{
+	result := r.db.Model(&models.Position{}).Where("id = ? AND status = ?", id, "OPEN").Updates(map[string]interface{}{
 		"status":     "CLOSED",
 		"close_time": &now,
 		"closed_pnl": closedPnL,
+		"version":    gorm.Expr("version + 1"),
+	})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return result.Error
+}
+
+func (r *MySQLRepository) UpdatePositionNotes(id uint, notes string) error {
+	return r.db.Model(&models.Position{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"notes":   notes,
+		"version": gorm.Expr("version + 1"),
+	}).Error
+}
+
+func (r *MySQLRepository) UpdatePositionCosts(id uint, commission, fundingFee, netPnL float64) error {
+	return r.db.Model(&models.Position{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"commission":  commission,
+		"funding_fee": fundingFee,
+		"net_pnl":     netPnL,
+		"version":     gorm.Expr("version + 1"),
+	}).Error
+}
+
+// UpdatePositionPrices marks an open position to market. Prices are refreshed constantly and
+// a stale write is corrected by the next one, so the version is not bumped.
+func (r *MySQLRepository) UpdatePositionPrices(id uint, markPrice, lastPrice, unrealizedPnL, percentage float64) error {
+	return r.db.Model(&models.Position{}).Where("id = ? AND status = ?", id, "OPEN").Updates(map[string]interface{}{
+		"mark_price":     markPrice,
+		"last_price":     lastPrice,
+		"unrealized_pnl": unrealizedPnL,
+		"percentage":     percentage,
 	}).Error
 }
 
@@ -260,6 +431,29 @@ func (r *MySQLRepository) GetTradesByOrder(orderID uint) ([]*models.Trade, error
 	return trades, err
 }
 
+// GetRealizedTrades returns the most recent fills of a strategy that realized PnL
+func (r *MySQLRepository) GetRealizedTrades(strategy string, limit int) ([]*models.Trade, error) {
+	var trades []*models.Trade
+	query := r.db.Where("strategy = ? AND realized_pnl <> 0", strategy)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Order("trade_time DESC").Find(&trades).Error
+	return trades, err
+}
+
+func (r *MySQLRepository) GetTradesBefore(end time.Time) ([]*models.Trade, error) {
+	var trades []*models.Trade
+	err := r.db.Where("trade_time < ?", end).Order("trade_time ASC, id ASC").Find(&trades).Error
+	return trades, err
+}
+
+func (r *MySQLRepository) GetTradesSince(since time.Time) ([]*models.Trade, error) {
+	var trades []*models.Trade
+	err := r.db.Preload("Order").Where("trade_time >= ?", since).Order("trade_time ASC, id ASC").Find(&trades).Error
+	return trades, err
+}
+
 // Account operations
 func (r *MySQLRepository) UpdateAccount(account *models.Account) error {
 	return r.db.Save(account).Error
@@ -274,6 +468,21 @@ func (r *MySQLRepository) GetLatestAccount() (*models.Account, error) {
 	return &account, nil
 }
 
+// GetPeakEquity returns the highest margin balance of the snapshots since the given time, 0
+// without snapshots
+func (r *MySQLRepository) GetPeakEquity(since time.Time) (float64, error) {
+	var peak sql.NullFloat64
+	err := r.db.Model(&models.Account{}).Where("created_at >= ?", since).
+		Select("MAX(total_margin_balance)").Scan(&peak).Error
+	return peak.Float64, err
+}
+
+func (r *MySQLRepository) GetAccountsSince(since time.Time) ([]*models.Account, error) {
+	var accounts []*models.Account
+	err := r.db.Where("created_at >= ?", since).Order("created_at ASC").Find(&accounts).Error
+	return accounts, err
+}
+
 func (r *MySQLRepository) UpdateBalance(balance *models.Balance) error {
 	return r.db.Save(balance).Error
 }
@@ -309,6 +518,13 @@ func (r *MySQLRepository) SaveMarketData(data *models.MarketData) error {
 	return r.db.Create(data).Error
 }
 
+func (r *MySQLRepository) SaveMarketDataBatch(data []*models.MarketData) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return r.db.Create(&data).Error
+}
+
 func (r *MySQLRepository) GetLatestMarketData(symbol string) (*models.MarketData, error) {
 	var data models.MarketData
 	err := r.db.Where("symbol = ?", symbol).Order("timestamp DESC").First(&data).Error
@@ -318,6 +534,55 @@ func (r *MySQLRepository) GetLatestMarketData(symbol string) (*models.MarketData
 	return &data, nil
 }
 
+func (r *MySQLRepository) GetMarketDataSince(symbol string, since int64) ([]*models.MarketData, error) {
+	var data []*models.MarketData
+	err := r.db.Where("symbol = ? AND timestamp >= ?", symbol, since).Order("timestamp ASC").Find(&data).Error
+	return data, err
+}
+
+func (r *MySQLRepository) SavePositioningData(data *models.PositioningData) error {
+	return r.db.Create(data).Error
+}
+
+func (r *MySQLRepository) SaveOptionsVolatility(sample *models.OptionsVolatilitySample) error {
+	return r.db.Create(sample).Error
+}
+
+func (r *MySQLRepository) GetLatestPositioningData(symbol string) (*models.PositioningData, error) {
+	var data models.PositioningData
+	err := r.db.Where("symbol = ?", symbol).Order("timestamp DESC").First(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (r *MySQLRepository) SaveOrderFlowSamples(samples []*models.OrderFlowSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	// A restart within a minute produces a second sample for it; the first one is kept
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&samples).Error
+}
+
+func (r *MySQLRepository) GetOrderFlowSamples(symbol string, start, end int64) ([]*models.OrderFlowSample, error) {
+	var samples []*models.OrderFlowSample
+	err := r.db.Where("symbol = ? AND timestamp >= ? AND timestamp < ?", symbol, start, end).
+		Order("timestamp ASC").Find(&samples).Error
+	return samples, err
+}
+
+func (r *MySQLRepository) SaveLiquidation(liquidation *models.Liquidation) error {
+	return r.db.Create(liquidation).Error
+}
+
+func (r *MySQLRepository) GetLiquidations(symbol string, start, end time.Time) ([]*models.Liquidation, error) {
+	var liquidations []*models.Liquidation
+	err := r.db.Where("symbol = ? AND time >= ? AND time < ?", symbol, start, end).
+		Order("time ASC").Find(&liquidations).Error
+	return liquidations, err
+}
+
 // Strategy operations
 func (r *MySQLRepository) CreateStrategy(strategy *models.Strategy) error {
 	return r.db.Create(strategy).Error
@@ -343,8 +608,21 @@ func (r *MySQLRepository) GetActiveStrategies() ([]*models.Strategy, error) {
 }
 
 // Risk metrics operations
+// SaveRiskMetric upserts the row of the metric's date with its latest values
 func (r *MySQLRepository) SaveRiskMetric(metric *models.RiskMetric) error {
-	return r.db.Create(metric).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.RiskMetric
+		err := tx.Where("date = ?", metric.Date).Order("id DESC").First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(metric).Error
+		}
+		if err != nil {
+			return err
+		}
+		metric.ID = existing.ID
+		metric.CreatedAt = existing.CreatedAt
+		return tx.Save(metric).Error
+	})
 }
 
 func (r *MySQLRepository) GetRiskMetrics(days int) ([]*models.RiskMetric, error) {
@@ -356,13 +634,28 @@ func (r *MySQLRepository) GetRiskMetrics(days int) ([]*models.RiskMetric, error)
 
 func (r *MySQLRepository) GetLatestRiskMetric() (*models.RiskMetric, error) {
 	var metric models.RiskMetric
-	err := r.db.Order("date DESC").First(&metric).Error
+	err := r.db.Order("date DESC, id DESC").First(&metric).Error
 	if err != nil {
 		return nil, err
 	}
 	return &metric, nil
 }
 
+func (r *MySQLRepository) SaveRiskMetricPoint(point *models.RiskMetricPoint) error {
+	return r.db.Create(point).Error
+}
+
+func (r *MySQLRepository) GetRiskMetricPoints(since time.Time) ([]*models.RiskMetricPoint, error) {
+	var points []*models.RiskMetricPoint
+	err := r.db.Where("time >= ?", since).Order("time ASC").Find(&points).Error
+	return points, err
+}
+
+func (r *MySQLRepository) DeleteRiskMetricPointsBefore(before time.Time) (int64, error) {
+	result := r.db.Where("time < ?", before).Delete(&models.RiskMetricPoint{})
+	return result.RowsAffected, result.Error
+}
+
 // Trading config operations
 func (r *MySQLRepository) CreateTradingConfig(config *models.TradingConfig) error {
 	return r.db.Create(config).Error
@@ -386,3 +679,402 @@ func (r *MySQLRepository) GetActiveTradingConfigs() ([]*models.TradingConfig, er
 	err := r.db.Where("is_active = ?", true).Find(&configs).Error
 	return configs, err
 }
+
+// SaveTradingConfigVersion stores config at its new version and records the change. It
+// fails with ErrVersionConflict unless the stored row is still at the previous version.
+func (r *MySQLRepository) SaveTradingConfigVersion(config *models.TradingConfig, change *models.ConfigChange) error {
+	return r.saveVersion(config, config.Version, change)
+}
+
+// SaveStrategyVersion stores strategy at its new version and records the change. It fails
+// with ErrVersionConflict unless the stored row is still at the previous version.
+func (r *MySQLRepository) SaveStrategyVersion(strategy *models.Strategy, change *models.ConfigChange) error {
+	return r.saveVersion(strategy, strategy.Version, change)
+}
+
+func (r *MySQLRepository) saveVersion(row interface{}, version uint, change *models.ConfigChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(row).Where("version = ?", version-1).Select("*").Updates(row)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		return tx.Create(change).Error
+	})
+}
+
+// GetConfigChanges returns the newest changes first; empty entity or name match any
+func (r *MySQLRepository) GetConfigChanges(entity, name string, limit int) ([]*models.ConfigChange, error) {
+	var changes []*models.ConfigChange
+	query := r.db.Model(&models.ConfigChange{})
+	if entity != "" {
+		query = query.Where("entity = ?", entity)
+	}
+	if name != "" {
+		query = query.Where("name = ?", name)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Order("id DESC").Find(&changes).Error
+	return changes, err
+}
+
+// Sentiment operations
+func (r *MySQLRepository) SaveSentimentItem(item *models.SentimentItem) error {
+	// Feeds are polled repeatedly; items already stored for the symbol and source are ignored
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(item).Error
+}
+
+func (r *MySQLRepository) GetSentimentItems(symbol string, since time.Time) ([]*models.SentimentItem, error) {
+	var items []*models.SentimentItem
+	query := r.db.Where("published_at >= ?", since)
+	if symbol != "" {
+		query = query.Where("symbol = ?", symbol)
+	}
+	err := query.Order("published_at ASC").Find(&items).Error
+	return items, err
+}
+
+// Audit operations
+func (r *MySQLRepository) CreateAuditEvent(event *models.AuditEvent) error {
+	return r.db.Create(event).Error
+}
+
+// CreateOrderAuditEntry appends to the order audit log; entries are never updated or deleted
+func (r *MySQLRepository) CreateOrderAuditEntry(entry *models.OrderAuditEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// GetLastOrderAuditEntry returns the head of the order audit chain, or nil when it is empty
+func (r *MySQLRepository) GetLastOrderAuditEntry() (*models.OrderAuditEntry, error) {
+	var entry models.OrderAuditEntry
+	err := r.db.Order("seq DESC").First(&entry).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetOrderAuditEntries returns up to limit entries after afterSeq in chain order
+func (r *MySQLRepository) GetOrderAuditEntries(afterSeq uint64, limit int) ([]*models.OrderAuditEntry, error) {
+	var entries []*models.OrderAuditEntry
+	err := r.db.Where("seq > ?", afterSeq).Order("seq ASC").Limit(limit).Find(&entries).Error
+	return entries, err
+}
+
+func (r *MySQLRepository) GetAuditEvents(category string, limit int) ([]*models.AuditEvent, error) {
+	var events []*models.AuditEvent
+	query := r.db.Model(&models.AuditEvent{})
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Order("created_at DESC").Find(&events).Error
+	return events, err
+}
+
+// Cooldown operations
+func (r *MySQLRepository) SaveSymbolCooldown(cooldown *models.SymbolCooldown) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}},
+		DoUpdates: clause.AssignmentColumns([]string{"consecutive_losses", "last_entry_time", "last_exit_time", "cooldown_until", "updated_at"}),
+	}).Create(cooldown).Error
+}
+
+func (r *MySQLRepository) GetSymbolCooldowns() ([]*models.SymbolCooldown, error) {
+	var cooldowns []*models.SymbolCooldown
+	err := r.db.Find(&cooldowns).Error
+	return cooldowns, err
+}
+
+func (r *MySQLRepository) SaveGridState(state *models.GridState) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "symbol"}, {Name: "level"}},
+		DoUpdates: clause.AssignmentColumns([]string{"base_price", "price", "quantity", "active", "side", "exchange_order_id", "filled_legs", "last_fill_price", "last_fill_time", "updated_at"}),
+	}).Create(state).Error
+}
+
+// Strategy state operations
+func (r *MySQLRepository) SaveStrategyState(state *models.StrategyState) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "strategy"}, {Name: "symbol"}},
+		DoUpdates: clause.AssignmentColumns([]string{"state", "updated_at"}),
+	}).Create(state).Error
+}
+
+func (r *MySQLRepository) GetStrategyState(strategy, symbol string) (*models.StrategyState, error) {
+	var state models.StrategyState
+	err := r.db.Where("strategy = ? AND symbol = ?", strategy, symbol).First(&state).Error
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// Strategy pause operations
+func (r *MySQLRepository) SaveStrategyPause(pause *models.StrategyPause) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "strategy"}},
+		DoUpdates: clause.AssignmentColumns([]string{"paused", "reason", "stats", "paused_at", "resumed_at", "updated_at"}),
+	}).Create(pause).Error
+}
+
+func (r *MySQLRepository) GetStrategyPause(strategy string) (*models.StrategyPause, error) {
+	var pause models.StrategyPause
+	err := r.db.Where("strategy = ?", strategy).First(&pause).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pause, nil
+}
+
+// Shadow test operations
+func (r *MySQLRepository) CreateShadowSignal(signal *models.ShadowSignal) error {
+	return r.db.Create(signal).Error
+}
+
+// Journal operations
+func (r *MySQLRepository) CreateJournalEntry(entry *models.TradeJournal) error {
+	return r.db.Create(entry).Error
+}
+
+// GetJournalEntries returns the newest entries first, optionally filtered by symbol and position
+func (r *MySQLRepository) GetJournalEntries(symbol string, positionID uint, limit int) ([]*models.TradeJournal, error) {
+	var entries []*models.TradeJournal
+	query := r.db.Omit("strategy_state", "klines")
+	if symbol != "" {
+		query = query.Where("symbol = ?", symbol)
+	}
+	if positionID > 0 {
+		query = query.Where("position_id = ?", positionID)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&entries).Error
+	return entries, err
+}
+
+func (r *MySQLRepository) GetJournalEntry(id uint) (*models.TradeJournal, error) {
+	var entry models.TradeJournal
+	err := r.db.First(&entry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Outbox operations
+func (r *MySQLRepository) CreateOutboxMessage(message *models.OutboxMessage) error {
+	if message.NextAttemptAt.IsZero() {
+		message.NextAttemptAt = time.Now()
+	}
+	return r.db.Create(message).Error
+}
+
+// GetDueOutboxMessages returns pending messages whose next attempt is due, oldest first
+func (r *MySQLRepository) GetDueOutboxMessages(now time.Time, limit int) ([]*models.OutboxMessage, error) {
+	var messages []*models.OutboxMessage
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", models.OutboxPending, now).
+		Order("id ASC").Limit(limit).Find(&messages).Error
+	return messages, err
+}
+
+func (r *MySQLRepository) UpdateOutboxMessage(message *models.OutboxMessage) error {
+	return r.db.Save(message).Error
+}
+
+// GetOutboxMessages returns the newest messages first; an empty status matches any
+func (r *MySQLRepository) GetOutboxMessages(status string, limit int) ([]*models.OutboxMessage, error) {
+	var messages []*models.OutboxMessage
+	query := r.db.Model(&models.OutboxMessage{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&messages).Error
+	return messages, err
+}
+
+// RequeueOutboxMessage schedules a dead message for immediate delivery with fresh attempts
+func (r *MySQLRepository) RequeueOutboxMessage(id uint) error {
+	result := r.db.Model(&models.OutboxMessage{}).Where("id = ? AND status = ?", id, models.OutboxDead).Updates(map[string]interface{}{
+		"status":          models.OutboxPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+	})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+func (r *MySQLRepository) DeleteDeliveredOutboxMessagesBefore(before time.Time) (int64, error) {
+	result := r.db.Where("status = ? AND delivered_at < ?", models.OutboxDelivered, before).Delete(&models.OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
+
+// Wallet operations
+func (r *MySQLRepository) CreateWalletTransfer(transfer *models.WalletTransfer) error {
+	return r.db.Create(transfer).Error
+}
+
+// GetWalletTransfers returns the newest transfers first; an empty strategy matches any and a
+// limit of 0 returns all
+func (r *MySQLRepository) GetWalletTransfers(strategy string, limit int) ([]*models.WalletTransfer, error) {
+	var transfers []*models.WalletTransfer
+	query := r.db.Model(&models.WalletTransfer{})
+	if strategy != "" {
+		query = query.Where("strategy = ?", strategy)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Order("created_at DESC, id DESC").Find(&transfers).Error
+	return transfers, err
+}
+
+// SumClosedNetPnL totals the net PnL of a strategy's positions closed since the given time
+func (r *MySQLRepository) SumClosedNetPnL(strategy string, since time.Time) (float64, error) {
+	var total sql.NullFloat64
+	err := r.db.Model(&models.Position{}).
+		Select("SUM(net_pnl)").
+		Where("status = ? AND strategy = ? AND close_time >= ?", "CLOSED", strategy, since).
+		Scan(&total).Error
+	return total.Float64, err
+}
+
+func (r *MySQLRepository) SaveWalletSnapshot(snapshot *models.WalletSnapshot) error {
+	return r.db.Create(snapshot).Error
+}
+
+func (r *MySQLRepository) GetWalletSnapshots(strategy string, since time.Time) ([]*models.WalletSnapshot, error) {
+	var snapshots []*models.WalletSnapshot
+	err := r.db.Where("strategy = ? AND time >= ?", strategy, since).Order("time ASC").Find(&snapshots).Error
+	return snapshots, err
+}
+
+func (r *MySQLRepository) DeleteWalletSnapshotsBefore(before time.Time) (int64, error) {
+	result := r.db.Where("time < ?", before).Delete(&models.WalletSnapshot{})
+	return result.RowsAffected, result.Error
+}
+
+// ADL quantile operations
+func (r *MySQLRepository) SaveADLQuantiles(quantiles []*models.ADLQuantile) error {
+	if len(quantiles) == 0 {
+		return nil
+	}
+	return r.db.Create(&quantiles).Error
+}
+
+// GetADLQuantiles returns the recorded changes since the given time, oldest first; an empty
+// symbol matches any
+func (r *MySQLRepository) GetADLQuantiles(symbol string, since time.Time) ([]*models.ADLQuantile, error) {
+	var quantiles []*models.ADLQuantile
+	query := r.db.Where("created_at >= ?", since)
+	if symbol != "" {
+		query = query.Where("symbol = ?", symbol)
+	}
+	err := query.Order("created_at ASC, id ASC").Find(&quantiles).Error
+	return quantiles, err
+}
+
+func (r *MySQLRepository) DeleteADLQuantilesBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.ADLQuantile{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *MySQLRepository) GetGridStates(symbol string) ([]*models.GridState, error) {
+	var states []*models.GridState
+	err := r.db.Where("symbol = ?", symbol).Order("level ASC").Find(&states).Error
+	return states, err
+}
+
+func (r *MySQLRepository) DeleteGridStates(symbol string) error {
+	return r.db.Where("symbol = ?", symbol).Delete(&models.GridState{}).Error
+}
+
+func (r *MySQLRepository) CreateFundingArbPosition(position *models.FundingArbPosition) error {
+	return r.db.Create(position).Error
+}
+
+func (r *MySQLRepository) UpdateFundingArbPosition(position *models.FundingArbPosition) error {
+	return r.db.Save(position).Error
+}
+
+func (r *MySQLRepository) GetOpenFundingArbPosition(symbol string) (*models.FundingArbPosition, error) {
+	var position models.FundingArbPosition
+	err := r.db.Where("symbol = ? AND status = ?", symbol, "OPEN").First(&position).Error
+	if err != nil {
+		return nil, err
+	}
+	return &position, nil
+}
+
+func (r *MySQLRepository) SaveSpreadSample(sample *models.SpreadSample) error {
+	return r.db.Create(sample).Error
+}
+
+func (r *MySQLRepository) GetSpreadSamples(symbol, venue string, since time.Time) ([]*models.SpreadSample, error) {
+	var samples []*models.SpreadSample
+	query := r.db.Where("symbol = ? AND created_at >= ?", symbol, since)
+	if venue != "" {
+		query = query.Where("venue = ?", venue)
+	}
+	err := query.Order("created_at ASC").Find(&samples).Error
+	return samples, err
+}
+
+func (r *MySQLRepository) GetOrderByClientID(clientOrderID string) (*models.Order, error) {
+	var order models.Order
+	err := r.db.Where("client_order_id = ?", clientOrderID).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// Income operations
+func (r *MySQLRepository) SaveIncomes(incomes []*models.Income) error {
+	if len(incomes) == 0 {
+		return nil
+	}
+	// Sync windows overlap at their boundaries; entries already stored are ignored
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&incomes).Error
+}
+
+// GetLatestIncomeTime returns the time of the newest stored income entry, or the zero time
+func (r *MySQLRepository) GetLatestIncomeTime() (time.Time, error) {
+	var latest sql.NullTime
+	if err := r.db.Model(&models.Income{}).Select("MAX(time)").Scan(&latest).Error; err != nil {
+		return time.Time{}, err
+	}
+	return latest.Time, nil
+}
+
+func (r *MySQLRepository) SumIncome(symbol, incomeType string, start, end time.Time) (float64, error) {
+	var total sql.NullFloat64
+	err := r.db.Model(&models.Income{}).
+		Select("SUM(income)").
+		Where("symbol = ? AND income_type = ? AND time >= ? AND time <= ?", symbol, incomeType, start, end).
+		Scan(&total).Error
+	return total.Float64, err
+}
+
+func (r *MySQLRepository) GetClosedPositions(start, end time.Time) ([]*models.Position, error) {
+	var positions []*models.Position
+	err := r.db.Where("status = ? AND close_time >= ? AND close_time < ?", "CLOSED", start, end).
+		Order("close_time ASC").
+		Find(&positions).Error
+	return positions, err
+}
+
+func (r *MySQLRepository) GetIncomes(start, end time.Time) ([]*models.Income, error) {
+	var incomes []*models.Income
+	err := r.db.Where("time >= ? AND time < ?", start, end).Order("time ASC").Find(&incomes).Error
+	return incomes, err
+}
@@ -0,0 +1,293 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// latencyBucketsMs are the upper bounds of the query latency histogram buckets
+var latencyBucketsMs = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// queryStartKey holds the start time of a statement between the before and after callbacks
+const queryStartKey = "metrics:query_start"
+
+// HistogramBucket counts the queries that took at most LeMs; buckets are cumulative
+type HistogramBucket struct {
+	LeMs  float64 `json:"le_ms"`
+	Count int64   `json:"count"`
+}
+
+// QueryLatency is the latency histogram of one operation on one table
+type QueryLatency struct {
+	Operation string            `json:"operation"` // create, query, update, delete, row or raw
+	Table     string            `json:"table"`
+	Count     int64             `json:"count"`
+	Errors    int64             `json:"errors"`
+	Slow      int64             `json:"slow"` // At or above the slow query threshold
+	SumMs     float64           `json:"sum_ms"`
+	MaxMs     float64           `json:"max_ms"`
+	P95Ms     float64           `json:"p95_ms"` // Upper bound of the bucket holding the 95th percentile
+	Buckets   []HistogramBucket `json:"buckets"`
+	counts    []int64           // Per bucket plus one overflow bucket, not cumulative
+}
+
+// PoolStats is the connection pool usage against its soft cap
+type PoolStats struct {
+	MaxOpen           int     `json:"max_open"`
+	Open              int     `json:"open"`
+	InUse             int     `json:"in_use"`
+	Idle              int     `json:"idle"`
+	PeakInUse         int     `json:"peak_in_use"`
+	SoftCap           int     `json:"soft_cap"` // In-use connections that trigger a warning; 0 when the pool is unlimited
+	OverSoftCap       bool    `json:"over_soft_cap"`
+	WaitCount         int64   `json:"wait_count"` // Queries that waited for a free connection
+	WaitDurationMs    float64 `json:"wait_duration_ms"`
+	MaxIdleClosed     int64   `json:"max_idle_closed"`
+	MaxLifetimeClosed int64   `json:"max_lifetime_closed"`
+}
+
+// DatabaseMetrics is the pool usage and the query latencies since start
+type DatabaseMetrics struct {
+	Since       time.Time       `json:"since"`
+	SlowQueryMs int             `json:"slow_query_ms"`
+	Pool        *PoolStats      `json:"pool"`
+	Queries     []*QueryLatency `json:"queries"`
+}
+
+// Monitor times every GORM statement into per operation and table histograms, logs slow
+// queries and watches the connection pool, warning when in-use connections pass the soft
+// cap or queries start waiting for a connection
+type Monitor struct {
+	config config.DatabaseMonitoringConfig
+	sqlDB  *sql.DB
+	logger *logrus.Logger
+	since  time.Time
+
+	mu        sync.Mutex
+	latencies map[string]*QueryLatency
+	peakInUse int
+	overCap   bool
+	lastWaits int64
+}
+
+// NewMonitor creates a monitor and registers its callbacks on db
+func NewMonitor(db *gorm.DB, cfg config.DatabaseMonitoringConfig, logger *logrus.Logger) (*Monitor, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+	m := &Monitor{
+		config:    cfg,
+		sqlDB:     sqlDB,
+		logger:    logger,
+		since:     time.Now(),
+		latencies: make(map[string]*QueryLatency),
+		lastWaits: sqlDB.Stats().WaitCount,
+	}
+	if err := db.Use(m); err != nil {
+		return nil, fmt.Errorf("failed to register database monitor: %w", err)
+	}
+	return m, nil
+}
+
+// Name implements gorm.Plugin
+func (m *Monitor) Name() string {
+	return "database_monitor"
+}
+
+// Initialize implements gorm.Plugin by timing every callback chain
+func (m *Monitor) Initialize(db *gorm.DB) error {
+	start := func(tx *gorm.DB) {
+		tx.InstanceSet(queryStartKey, time.Now())
+	}
+	callbacks := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+	for _, processor := range processors {
+		if err := processor.before("metrics:before_"+processor.operation, start); err != nil {
+			return err
+		}
+		if err := processor.after("metrics:after_"+processor.operation, m.finish(processor.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finish records the latency of a statement and logs it when slow
+func (m *Monitor) finish(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		value, ok := tx.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		started, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(started)
+		table := tx.Statement.Table
+		if table == "" {
+			table = "-"
+		}
+		failed := tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound)
+		slow := m.config.SlowQueryMs > 0 && elapsed >= time.Duration(m.config.SlowQueryMs)*time.Millisecond
+		m.record(operation, table, elapsed, failed, slow)
+
+		if slow {
+			// Placeholders are logged instead of values, which may hold account data
+			m.logger.Warnf("Slow %s on %s took %dms (%d rows): %s",
+				operation, table, elapsed.Milliseconds(), tx.RowsAffected, tx.Statement.SQL.String())
+		}
+	}
+}
+
+func (m *Monitor) record(operation, table string, elapsed time.Duration, failed, slow bool) {
+	ms := float64(elapsed) / float64(time.Millisecond)
+	bucket := sort.SearchFloat64s(latencyBucketsMs, ms)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := operation + " " + table
+	latency, ok := m.latencies[key]
+	if !ok {
+		latency = &QueryLatency{Operation: operation, Table: table, counts: make([]int64, len(latencyBucketsMs)+1)}
+		m.latencies[key] = latency
+	}
+	latency.Count++
+	latency.SumMs += ms
+	latency.MaxMs = math.Max(latency.MaxMs, ms)
+	latency.counts[bucket]++
+	if failed {
+		latency.Errors++
+	}
+	if slow {
+		latency.Slow++
+	}
+}
+
+// snapshot returns a copy of the histogram with cumulative buckets
+func (q *QueryLatency) snapshot() *QueryLatency {
+	copied := *q
+	copied.counts = nil
+	copied.Buckets = make([]HistogramBucket, len(latencyBucketsMs))
+	var cumulative int64
+	p95 := int64(math.Ceil(float64(q.Count) * 0.95))
+	copied.P95Ms = q.MaxMs // When it falls beyond the last bucket
+	found := false
+	for i, le := range latencyBucketsMs {
+		cumulative += q.counts[i]
+		copied.Buckets[i] = HistogramBucket{LeMs: le, Count: cumulative}
+		if !found && cumulative >= p95 {
+			copied.P95Ms = math.Min(le, q.MaxMs)
+			found = true
+		}
+	}
+	return &copied
+}
+
+// Start samples the connection pool until ctx is cancelled
+func (m *Monitor) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(m.config.SampleSeconds) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.samplePool()
+			}
+		}
+	}()
+}
+
+// samplePool tracks the peak usage and warns once when the pool passes its soft cap and
+// whenever queries had to wait for a connection since the last sample
+func (m *Monitor) samplePool() {
+	stats := m.sqlDB.Stats()
+	softCap := m.softCap(stats.MaxOpenConnections)
+
+	m.mu.Lock()
+	m.peakInUse = max(m.peakInUse, stats.InUse)
+	over := softCap > 0 && stats.InUse >= softCap
+	crossed := over != m.overCap
+	m.overCap = over
+	waits := stats.WaitCount - m.lastWaits
+	m.lastWaits = stats.WaitCount
+	m.mu.Unlock()
+
+	if crossed && over {
+		m.logger.Warnf("Database pool at %d of %d connections in use, above the soft cap of %d",
+			stats.InUse, stats.MaxOpenConnections, softCap)
+	} else if crossed {
+		m.logger.Infof("Database pool back below its soft cap: %d of %d connections in use", stats.InUse, stats.MaxOpenConnections)
+	}
+	if waits > 0 {
+		m.logger.Warnf("%d database queries waited for a free connection in the last %ds; max_open_conns is %d",
+			waits, m.config.SampleSeconds, stats.MaxOpenConnections)
+	}
+}
+
+// softCap returns the in-use connections that trigger a warning, 0 for an unlimited pool
+func (m *Monitor) softCap(maxOpen int) int {
+	if maxOpen <= 0 {
+		return 0
+	}
+	return max(1, int(math.Ceil(float64(maxOpen)*m.config.PoolSoftCapPercent/100)))
+}
+
+// Metrics returns the current pool usage and the query latencies, slowest on average first
+func (m *Monitor) Metrics() *DatabaseMetrics {
+	stats := m.sqlDB.Stats()
+	softCap := m.softCap(stats.MaxOpenConnections)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metrics := &DatabaseMetrics{
+		Since:       m.since,
+		SlowQueryMs: m.config.SlowQueryMs,
+		Pool: &PoolStats{
+			MaxOpen:           stats.MaxOpenConnections,
+			Open:              stats.OpenConnections,
+			InUse:             stats.InUse,
+			Idle:              stats.Idle,
+			PeakInUse:         max(m.peakInUse, stats.InUse),
+			SoftCap:           softCap,
+			OverSoftCap:       softCap > 0 && stats.InUse >= softCap,
+			WaitCount:         stats.WaitCount,
+			WaitDurationMs:    float64(stats.WaitDuration) / float64(time.Millisecond),
+			MaxIdleClosed:     stats.MaxIdleClosed,
+			MaxLifetimeClosed: stats.MaxLifetimeClosed,
+		},
+		Queries: make([]*QueryLatency, 0, len(m.latencies)),
+	}
+	for _, latency := range m.latencies {
+		metrics.Queries = append(metrics.Queries, latency.snapshot())
+	}
+	sort.Slice(metrics.Queries, func(i, j int) bool {
+		return metrics.Queries[i].SumMs/float64(metrics.Queries[i].Count) > metrics.Queries[j].SumMs/float64(metrics.Queries[j].Count)
+	})
+	return metrics
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"contract_playground/internal/config"
+)
+
+func TestQueryLatencyHistogram(t *testing.T) {
+	m := &Monitor{latencies: make(map[string]*QueryLatency)}
+	for i := 0; i < 19; i++ {
+		m.record("query", "positions", 3*time.Millisecond, false, false)
+	}
+	m.record("query", "positions", 8*time.Second, true, true)
+
+	latency := m.latencies["query positions"].snapshot()
+	if latency.Count != 20 || latency.Errors != 1 || latency.Slow != 1 || latency.MaxMs != 8000 {
+		t.Fatalf("latency = %+v, want 20 queries with one slow error of 8s", latency)
+	}
+	// 3ms lands in the 5ms bucket; the 8s query only in the overflow
+	if latency.Buckets[1].Count != 0 || latency.Buckets[2].Count != 19 || latency.Buckets[len(latency.Buckets)-1].Count != 19 {
+		t.Errorf("buckets = %+v, want 19 queries from the 5ms bucket on", latency.Buckets)
+	}
+	if latency.P95Ms != 5 {
+		t.Errorf("p95 = %v, want the 5ms bucket", latency.P95Ms)
+	}
+}
+
+func TestPoolSoftCap(t *testing.T) {
+	m := &Monitor{config: config.DatabaseMonitoringConfig{PoolSoftCapPercent: 80}}
+	for _, test := range []struct{ maxOpen, want int }{{25, 20}, {1, 1}, {3, 3}, {0, 0}} {
+		if got := m.softCap(test.maxOpen); got != test.want {
+			t.Errorf("softCap(%d) = %d, want %d", test.maxOpen, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package database
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/models"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(\w+?)(\.down)?\.sql$`)
+
+// databaseStatement matches the CREATE DATABASE and USE lines the scripts carry for manual
+// runs with the mysql client; the migrator stays on the database of the DSN
+var databaseStatement = regexp.MustCompile(`(?is)^(USE\s+\S+|CREATE\s+DATABASE\s.*)$`)
+
+// Migration is one versioned schema change. Down is empty when the change cannot be
+// rolled back.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus is a migration and whether it has been applied
+type MigrationStatus struct {
+	Version    int        `json:"version"`
+	Name       string     `json:"name"`
+	Applied    bool       `json:"applied"`
+	Dirty      bool       `json:"dirty"` // Started but did not finish; fix the schema by hand, then force
+	AppliedAt  *time.Time `json:"applied_at,omitempty"`
+	Reversible bool       `json:"reversible"`
+}
+
+// LoadMigrations reads NNN_name.sql and NNN_name.down.sql scripts, ordered by version
+func LoadMigrations(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(match[1])
+		data, err := fs.ReadFile(fsys, path.Join(".", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] != "" {
+			migration.Down = string(data)
+		} else {
+			migration.Up = string(data)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has a rollback but no script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// splitStatements splits a script into statements, dropping comment lines and database
+// statements. DELIMITER lines change the statement terminator as in the mysql client, so
+// triggers with semicolons in their body can be created.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	delimiter := ";"
+
+	scanner := bufio.NewScanner(strings.NewReader(script))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if fields := strings.Fields(trimmed); strings.EqualFold(fields[0], "DELIMITER") && len(fields) == 2 {
+			delimiter = fields[1]
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+		if !strings.HasSuffix(trimmed, delimiter) {
+			continue
+		}
+		statement := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(current.String()), delimiter))
+		current.Reset()
+		if statement != "" && !databaseStatement.MatchString(statement) {
+			statements = append(statements, statement)
+		}
+	}
+	if statement := strings.TrimSpace(current.String()); statement != "" && !databaseStatement.MatchString(statement) {
+		statements = append(statements, statement)
+	}
+	return statements
+}
+
+// Migrator applies and rolls back migrations, recording them in schema_migrations. MySQL
+// commits DDL implicitly, so a migration is marked dirty while it runs; a failure leaves
+// it dirty and blocks further changes until the schema is checked and the version forced.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+	logger     *logrus.Logger
+}
+
+// NewMigrator creates a migrator of the given migrations
+func NewMigrator(db *gorm.DB, migrations []*Migration, logger *logrus.Logger) *Migrator {
+	return &Migrator{db: db, migrations: migrations, logger: logger}
+}
+
+// history returns the recorded migrations by version, creating the table on first use
+func (m *Migrator) history() (map[int]*models.SchemaMigration, error) {
+	if err := m.db.AutoMigrate(&models.SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	var rows []*models.SchemaMigration
+	if err := m.db.Order("version ASC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	applied := make(map[int]*models.SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// Status lists every known migration and whether it has been applied
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	applied, err := m.history()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]*MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := &MigrationStatus{Version: migration.Version, Name: migration.Name, Reversible: migration.Down != ""}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = !row.Dirty
+			status.Dirty = row.Dirty
+			status.AppliedAt = &row.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// checkClean fails while a migration is dirty
+func checkClean(applied map[int]*models.SchemaMigration) error {
+	for _, row := range applied {
+		if row.Dirty {
+			return fmt.Errorf("migration %d_%s did not finish; check the schema by hand, then force the version", row.Version, row.Name)
+		}
+	}
+	return nil
+}
+
+// Pending returns the migrations not applied yet
+func (m *Migrator) Pending() ([]*Migration, error) {
+	applied, err := m.history()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkClean(applied); err != nil {
+		return nil, err
+	}
+	var pending []*Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies the pending migrations up to and including target (0 for all) and returns how
+// many were applied
+func (m *Migrator) Up(target int) (int, error) {
+	pending, err := m.Pending()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, migration := range pending {
+		if target > 0 && migration.Version > target {
+			break
+		}
+		if err := m.run(migration, migration.Up); err != nil {
+			return count, err
+		}
+		if err := m.db.Model(&models.SchemaMigration{}).Where("version = ?", migration.Version).
+			Updates(map[string]interface{}{"dirty": false, "applied_at": time.Now()}).Error; err != nil {
+			return count, fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+		}
+		m.logger.Infof("Applied migration %03d_%s", migration.Version, migration.Name)
+		count++
+	}
+	return count, nil
+}
+
+// Down rolls back the last steps applied migrations, newest first, and returns how many
+// were rolled back. It stops at a migration without a rollback script.
+func (m *Migrator) Down(steps int) (int, error) {
+	applied, err := m.history()
+	if err != nil {
+		return 0, err
+	}
+	if err := checkClean(applied); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return count, fmt.Errorf("migration %03d_%s has no rollback script", migration.Version, migration.Name)
+		}
+		if err := m.run(migration, migration.Down); err != nil {
+			return count, err
+		}
+		if err := m.db.Delete(&models.SchemaMigration{}, migration.Version).Error; err != nil {
+			return count, fmt.Errorf("failed to remove migration %d from the history: %w", migration.Version, err)
+		}
+		m.logger.Infof("Rolled back migration %03d_%s", migration.Version, migration.Name)
+		count++
+	}
+	return count, nil
+}
+
+// run executes a script with its migration marked dirty
+func (m *Migrator) run(migration *Migration, script string) error {
+	row := &models.SchemaMigration{Version: migration.Version, Name: migration.Name, Dirty: true, AppliedAt: time.Now()}
+	if err := m.db.Save(row).Error; err != nil {
+		return fmt.Errorf("failed to mark migration %d as running: %w", migration.Version, err)
+	}
+
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+	for _, statement := range splitStatements(script) {
+		if _, err := sqlDB.Exec(statement); err != nil {
+			return fmt.Errorf("migration %03d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+// Baseline records every migration up to version as applied without running it, for a
+// database whose schema was created before migrations were tracked
+func (m *Migrator) Baseline(version int) error {
+	applied, err := m.history()
+	if err != nil {
+		return err
+	}
+	if len(applied) > 0 {
+		return fmt.Errorf("schema_migrations already has %d entries; use force to change it", len(applied))
+	}
+	return m.markApplied(version)
+}
+
+// Force marks the migrations up to version as applied and removes later ones and the
+// dirty flag from the history, after a failed migration has been fixed by hand
+func (m *Migrator) Force(version int) error {
+	if err := m.db.Where("version > ?", version).Delete(&models.SchemaMigration{}).Error; err != nil {
+		return fmt.Errorf("failed to remove migrations after %d: %w", version, err)
+	}
+	return m.markApplied(version)
+}
+
+func (m *Migrator) markApplied(version int) error {
+	now := time.Now()
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			break
+		}
+		row := &models.SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: now}
+		if err := m.db.Save(row).Error; err != nil {
+			return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+		}
+	}
+	m.logger.Infof("Marked migrations up to %03d as applied", version)
+	return nil
+}
+
+// ApplyMigrations brings the schema up to date at startup. A database with tables but no
+// migration history must be baselined first. Without auto_apply, pending migrations stop
+// the start so they can be reviewed and applied with the migrate command.
+func ApplyMigrations(db *gorm.DB, cfg config.MigrationsConfig, fsys fs.FS, logger *logrus.Logger) error {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return err
+	}
+	migrator := NewMigrator(db, migrations, logger)
+
+	applied, err := migrator.history()
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 && db.Migrator().HasTable(&models.Order{}) {
+		return fmt.Errorf("database has tables but no migration history; record the applied migrations with `migrate baseline -version N` first")
+	}
+
+	pending, err := migrator.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	if !cfg.AutoApply {
+		return fmt.Errorf("%d pending migrations starting at %03d_%s; apply them with `migrate up`", len(pending), pending[0].Version, pending[0].Name)
+	}
+	_, err = migrator.Up(0)
+	return err
+}
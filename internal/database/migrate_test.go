@@ -0,0 +1,75 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"contract_playground/migrations"
+)
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- 注释
+CREATE DATABASE IF NOT EXISTS trading_bot CHARACTER SET utf8mb4;
+USE trading_bot;
+
+CREATE TABLE a (
+    id INT -- trailing comment stays
+);
+INSERT INTO a VALUES (1);
+
+DELIMITER //
+CREATE TRIGGER a_no_update BEFORE UPDATE ON a
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'no; updates'//
+DELIMITER ;
+DROP TABLE b`
+
+	want := []string{
+		"CREATE TABLE a (\n    id INT -- trailing comment stays\n)",
+		"INSERT INTO a VALUES (1)",
+		"CREATE TRIGGER a_no_update BEFORE UPDATE ON a\nFOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'no; updates'",
+		"DROP TABLE b",
+	}
+	if got := splitStatements(script); !reflect.DeepEqual(got, want) {
+		t.Errorf("statements = %q, want %q", got, want)
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_grid.sql":      {Data: []byte("CREATE TABLE grid (id INT);")},
+		"002_seed.sql":      {Data: []byte("INSERT INTO a VALUES (1);")},
+		"010_grid.down.sql": {Data: []byte("DROP TABLE grid;")},
+		"embed.go":          {Data: []byte("package migrations")},
+	}
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("LoadMigrations: %v", err)
+	}
+	if len(migrations) != 2 || migrations[0].Version != 2 || migrations[1].Version != 10 {
+		t.Fatalf("migrations = %+v, want versions 2 and 10", migrations)
+	}
+	if migrations[0].Down != "" || migrations[1].Name != "grid" || migrations[1].Down != "DROP TABLE grid;" {
+		t.Errorf("migrations = %+v, want only grid reversible", migrations)
+	}
+
+	fsys["010_grids.down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE grids;")}
+	if _, err := LoadMigrations(fsys); err == nil {
+		t.Error("two names for version 10 should fail")
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	list, err := LoadMigrations(migrations.Files)
+	if err != nil {
+		t.Fatalf("LoadMigrations: %v", err)
+	}
+	for i, migration := range list {
+		if migration.Version != i+1 {
+			t.Fatalf("migration %d_%s breaks the sequence at %d", migration.Version, migration.Name, i+1)
+		}
+		if len(splitStatements(migration.Up)) == 0 {
+			t.Errorf("migration %d_%s has no statements", migration.Version, migration.Name)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Package events fans engine order, position and risk events out to subscribers such as
+// the streaming API.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	TypeOrder    = "order"
+	TypePosition = "position"
+	TypeRisk     = "risk"
+)
+
+// Event is a single engine event. Data holds the order, position or risk payload and is
+// encoded as JSON by the streaming API.
+type Event struct {
+	Instance string      `json:"instance,omitempty"` // Bot instance of a multi-instance process
+	Type     string      `json:"type"`
+	Action   string      `json:"action"` // e.g. placed, opened, closed, rejected, metrics
+	Symbol   string      `json:"symbol,omitempty"`
+	Time     time.Time   `json:"time"`
+	Data     interface{} `json:"data,omitempty"`
+}
+
+// Bus delivers published events to every subscriber. Publishing never blocks: a subscriber
+// that falls behind loses events rather than stalling the trading loop.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[*subscription]struct{}
+}
+
+type subscription struct {
+	ch    chan Event
+	types map[string]bool // Empty means all types
+}
+
+// NewBus creates an event bus
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[*subscription]struct{})}
+}
+
+// Subscribe returns a channel of events of the given types (all types when none are given)
+// and a function that ends the subscription and closes the channel
+func (b *Bus) Subscribe(buffer int, types ...string) (<-chan Event, func()) {
+	sub := &subscription{ch: make(chan Event, buffer)}
+	if len(types) > 0 {
+		sub.types = make(map[string]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, sub)
+			b.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+}
+
+// Publish sends an event to all matching subscribers
+func (b *Bus) Publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		if sub.types != nil && !sub.types[event.Type] {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+}
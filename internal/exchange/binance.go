@@ -3,7 +3,10 @@ package exchange
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"contract_playground/internal/config"
@@ -17,26 +20,46 @@ type Client interface {
 	// Account information
 	GetAccountInfo(ctx context.Context) (*AccountInfo, error)
 	GetPositions(ctx context.Context) ([]*PositionInfo, error)
+	GetADLQuantiles(ctx context.Context) ([]*ADLQuantile, error)
 	GetBalance(ctx context.Context) ([]*BalanceInfo, error)
 
 	// Market data
 	GetSymbolPrice(ctx context.Context, symbol string) (float64, error)
 	GetSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error)
 	GetKlines(ctx context.Context, symbol string, interval string, limit int) ([]*KlineData, error)
+	GetPremiumIndex(ctx context.Context, symbol string) (*PremiumIndexInfo, error)
+	GetOpenInterest(ctx context.Context, symbol string) (*OpenInterestInfo, error)
+	GetLongShortRatio(ctx context.Context, symbol string, period string) (*LongShortRatioInfo, error)
+	GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error)
+	GetTicker24h(ctx context.Context, symbol string) (*Ticker24h, error)
 
 	// Order operations
 	PlaceOrder(ctx context.Context, order *OrderRequest) (*OrderResponse, error)
+	ModifyOrder(ctx context.Context, order *ModifyOrderRequest) (*OrderResponse, error)
 	CancelOrder(ctx context.Context, symbol string, orderID int64) error
 	GetOrder(ctx context.Context, symbol string, orderID int64) (*OrderInfo, error)
 	GetOpenOrders(ctx context.Context, symbol string) ([]*OrderInfo, error)
+	GetOrderTrades(ctx context.Context, symbol string, orderID int64) ([]*TradeInfo, error)
+
+	// Income history (realized PnL, commissions, funding)
+	GetIncomeHistory(ctx context.Context, symbol string, incomeType string, startTime, endTime int64, limit int) ([]*IncomeInfo, error)
 
 	// Real-time data streams
 	StartUserDataStream(ctx context.Context, handler UserDataHandler) error
 	StartMarketDataStream(ctx context.Context, symbols []string, handler MarketDataHandler) error
+	StartDepthStream(ctx context.Context, symbols []string, handler DepthHandler) error
+	StartMarkPriceStream(ctx context.Context, symbols []string, handler MarkPriceHandler) error
+	StartKlineStream(ctx context.Context, symbols []string, interval string, handler KlineHandler) error
+	StartAggTradeStream(ctx context.Context, symbols []string, handler AggTradeHandler) error
+	StartLiquidationStream(ctx context.Context, symbols []string, handler LiquidationHandler) error
 
 	// Exchange specific
 	SetLeverage(ctx context.Context, symbol string, leverage int) error
 	ChangeMarginType(ctx context.Context, symbol string, marginType string) error
+	GetSymbolSettings(ctx context.Context) ([]*SymbolSettings, error)
+	SetAutoCancel(ctx context.Context, symbol string, countdown time.Duration) error
+	GetPositionMode(ctx context.Context) (bool, error)
+	SetPositionMode(ctx context.Context, dualSide bool) error
 	GetExchangeInfo(ctx context.Context) (*ExchangeInfo, error)
 }
 
@@ -47,6 +70,7 @@ type AccountInfo struct {
 	TotalMarginBalance      float64 `json:"total_margin_balance"`
 	TotalPositionIM         float64 `json:"total_position_im"`
 	TotalOpenOrderIM        float64 `json:"total_open_order_im"`
+	TotalMaintMargin        float64 `json:"total_maint_margin"` // Margin ratio = TotalMaintMargin / TotalMarginBalance
 	TotalCrossWalletBalance float64 `json:"total_cross_wallet_balance"`
 	AvailableBalance        float64 `json:"available_balance"`
 	MaxWithdrawAmount       float64 `json:"max_withdraw_amount"`
@@ -56,6 +80,14 @@ type AccountInfo struct {
 	UpdateTime              int64   `json:"update_time"`
 }
 
+// ADLQuantile is the auto-deleveraging queue bucket of a symbol's positions per side, from 0
+// (last in the queue) to 4 (deleveraged first). Sides are LONG, SHORT and BOTH; HEDGE only
+// marks cross margined hedge mode positions.
+type ADLQuantile struct {
+	Symbol    string         `json:"symbol"`
+	Quantiles map[string]int `json:"adlQuantile"`
+}
+
 type PositionInfo struct {
 	Symbol            string  `json:"symbol"`
 	PositionSide      string  `json:"position_side"`
@@ -70,6 +102,12 @@ type PositionInfo struct {
 	UpdateTime        int64   `json:"update_time"`
 }
 
+type SymbolSettings struct {
+	Symbol     string `json:"symbol"`
+	Leverage   int    `json:"leverage"`
+	MarginType string `json:"margin_type"` // CROSSED, ISOLATED
+}
+
 type BalanceInfo struct {
 	Asset              string  `json:"asset"`
 	WalletBalance      float64 `json:"wallet_balance"`
@@ -89,9 +127,13 @@ type BalanceInfo struct {
 
 type SymbolInfo struct {
 	Symbol                string  `json:"symbol"`
-	Status                string  `json:"status"`
+	Status                string  `json:"status"`        // TRADING while orders are accepted
+	ContractType          string  `json:"contract_type"` // PERPETUAL, CURRENT_QUARTER, ...
+	DeliveryDate          int64   `json:"delivery_date"` // Settlement time in ms; far in the future for live perpetuals
 	BaseAsset             string  `json:"base_asset"`
 	QuoteAsset            string  `json:"quote_asset"`
+	MarginAsset           string  `json:"margin_asset"`  // USDT or USDC for linear contracts, the base asset for inverse ones
+	ContractSize          float64 `json:"contract_size"` // Quote value of one inverse contract; 0 for linear contracts
 	PricePrecision        int     `json:"price_precision"`
 	QuantityPrecision     int     `json:"quantity_precision"`
 	MinQty                float64 `json:"min_qty"`
@@ -119,6 +161,44 @@ type KlineData struct {
 	TakerBuyQuoteAssetVolume float64 `json:"taker_buy_quote_asset_volume"`
 }
 
+type PremiumIndexInfo struct {
+	Symbol          string  `json:"symbol"`
+	MarkPrice       float64 `json:"mark_price"`
+	IndexPrice      float64 `json:"index_price"`
+	FundingRate     float64 `json:"funding_rate"`
+	NextFundingTime int64   `json:"next_funding_time"`
+	Time            int64   `json:"time"`
+}
+
+// Ticker24h holds rolling 24 hour price statistics of a symbol
+type Ticker24h struct {
+	Symbol             string  `json:"symbol"`
+	PriceChange        float64 `json:"price_change"`
+	PriceChangePercent float64 `json:"price_change_percent"`
+	LastPrice          float64 `json:"last_price"`
+	OpenPrice          float64 `json:"open_price"`
+	HighPrice          float64 `json:"high_price"`
+	LowPrice           float64 `json:"low_price"`
+	Volume             float64 `json:"volume"`       // Base asset
+	QuoteVolume        float64 `json:"quote_volume"` // Quote asset
+	OpenTime           int64   `json:"open_time"`
+	CloseTime          int64   `json:"close_time"`
+}
+
+type OpenInterestInfo struct {
+	Symbol       string  `json:"symbol"`
+	OpenInterest float64 `json:"open_interest"` // In contracts (base asset)
+	Time         int64   `json:"time"`
+}
+
+type LongShortRatioInfo struct {
+	Symbol         string  `json:"symbol"`
+	LongShortRatio float64 `json:"long_short_ratio"`
+	LongAccount    float64 `json:"long_account"`  // Share of accounts net long (0-1)
+	ShortAccount   float64 `json:"short_account"` // Share of accounts net short (0-1)
+	Timestamp      int64   `json:"timestamp"`
+}
+
 type OrderRequest struct {
 	Symbol           string  `json:"symbol"`
 	Side             string  `json:"side"`
@@ -132,9 +212,21 @@ type OrderRequest struct {
 	PositionSide     string  `json:"position_side,omitempty"`
 	WorkingType      string  `json:"working_type,omitempty"`
 	PriceProtect     bool    `json:"price_protect,omitempty"`
+	ActivationPrice  float64 `json:"activation_price,omitempty"` // TRAILING_STOP_MARKET only
+	CallbackRate     float64 `json:"callback_rate,omitempty"`    // TRAILING_STOP_MARKET only, percent
 	NewClientOrderID string  `json:"new_client_order_id,omitempty"`
 }
 
+// ModifyOrderRequest changes the quantity and price of an open LIMIT order in place. Binance
+// keeps the queue position when only the quantity decreases.
+type ModifyOrderRequest struct {
+	Symbol   string  `json:"symbol"`
+	OrderID  int64   `json:"order_id"`
+	Side     string  `json:"side"` // Must match the original order
+	Quantity float64 `json:"quantity"`
+	Price    float64 `json:"price"`
+}
+
 type OrderResponse struct {
 	OrderID       int64   `json:"order_id"`
 	Symbol        string  `json:"symbol"`
@@ -192,6 +284,7 @@ type UserDataHandler interface {
 	OnOrderUpdate(order *OrderInfo)
 	OnPositionUpdate(position *PositionInfo)
 	OnTradeUpdate(trade *TradeInfo)
+	OnResync(snapshot *UserDataSnapshot)
 	OnError(err error)
 }
 
@@ -215,6 +308,26 @@ type TradeInfo struct {
 	RealizedPnL     float64 `json:"realized_pnl"`
 }
 
+// Income types reported by the futures income history
+const (
+	IncomeRealizedPnL = "REALIZED_PNL"
+	IncomeCommission  = "COMMISSION"
+	IncomeFundingFee  = "FUNDING_FEE"
+)
+
+// IncomeInfo is one entry of the futures income history. Income is signed: commissions
+// and funding paid are negative.
+type IncomeInfo struct {
+	Symbol     string  `json:"symbol"`
+	IncomeType string  `json:"income_type"`
+	Income     float64 `json:"income"`
+	Asset      string  `json:"asset"`
+	Info       string  `json:"info"`
+	Time       int64   `json:"time"`
+	TranID     int64   `json:"tran_id"`
+	TradeID    string  `json:"trade_id"`
+}
+
 // BinanceClient implements Client interface for Binance futures
 type BinanceClient struct {
 	client *futures.Client
@@ -228,28 +341,61 @@ func NewBinanceClient(cfg config.ExchangeConfig, logger *logrus.Logger) (Client,
 		futures.UseTestnet = true
 	}
 
+	// Detect silently dead websocket connections so streams reconnect
+	futures.WebsocketKeepalive = true
+
 	client := futures.NewClient(cfg.APIKey, cfg.SecretKey)
+	b := &BinanceClient{
+		client: client,
+		config: cfg,
+		logger: logger,
+	}
+
+	// Sync server time before the first signed request so a drifted clock cannot fail it
+	if err := syncServerTime(context.Background(), "futures", b.syncTime, cfg.RecvWindowMs, logger); err != nil {
+		logger.Warnf("Failed to sync futures server time, using local clock: %v", err)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	_, err := client.NewGetAccountService().Do(ctx)
+	_, err := client.NewGetAccountService().Do(ctx, b.signedOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Binance: %w", err)
 	}
 
 	logger.Info("Successfully connected to Binance futures API")
 
-	return &BinanceClient{
-		client: client,
-		config: cfg,
-		logger: logger,
-	}, nil
+	go runServerTimeSync("futures", b.syncTime, time.Duration(cfg.TimeSyncIntervalSeconds)*time.Second, cfg.RecvWindowMs, logger)
+
+	return wrapClient(b, cfg, logger)
+}
+
+// NewClient creates the futures client of the configured market
+func NewClient(cfg config.ExchangeConfig, logger *logrus.Logger) (Client, error) {
+	if cfg.Market == MarketCoinM {
+		return NewDeliveryClient(cfg, logger)
+	}
+	return NewBinanceClient(cfg, logger)
+}
+
+// wrapClient adds the configured fault injection, recording and guard layers to a client
+func wrapClient(inner Client, cfg config.ExchangeConfig, logger *logrus.Logger) (Client, error) {
+	if cfg.Chaos.Enabled {
+		inner = NewChaosClient(inner, cfg.Chaos, logger)
+	}
+	if cfg.RecordFile != "" {
+		var err error
+		if inner, err = NewRecordingClient(inner, cfg.RecordFile, logger); err != nil {
+			return nil, err
+		}
+	}
+	return NewGuardedClient(inner, cfg, logger), nil
 }
 
 // GetAccountInfo retrieves account information
 func (b *BinanceClient) GetAccountInfo(ctx context.Context) (*AccountInfo, error) {
-	account, err := b.client.NewGetAccountService().Do(ctx)
+	account, err := b.client.NewGetAccountService().Do(ctx, b.signedOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get account info: %w", err)
 	}
@@ -259,6 +405,7 @@ func (b *BinanceClient) GetAccountInfo(ctx context.Context) (*AccountInfo, error
 	totalMarginBalance, _ := strconv.ParseFloat(account.TotalMarginBalance, 64)
 	totalPositionIM, _ := strconv.ParseFloat(account.TotalPositionInitialMargin, 64)
 	totalOpenOrderIM, _ := strconv.ParseFloat(account.TotalOpenOrderInitialMargin, 64)
+	totalMaintMargin, _ := strconv.ParseFloat(account.TotalMaintMargin, 64)
 	totalCrossWalletBalance, _ := strconv.ParseFloat(account.TotalCrossWalletBalance, 64)
 	availableBalance, _ := strconv.ParseFloat(account.AvailableBalance, 64)
 	maxWithdrawAmount, _ := strconv.ParseFloat(account.MaxWithdrawAmount, 64)
@@ -269,6 +416,7 @@ func (b *BinanceClient) GetAccountInfo(ctx context.Context) (*AccountInfo, error
 		TotalMarginBalance:      totalMarginBalance,
 		TotalPositionIM:         totalPositionIM,
 		TotalOpenOrderIM:        totalOpenOrderIM,
+		TotalMaintMargin:        totalMaintMargin,
 		TotalCrossWalletBalance: totalCrossWalletBalance,
 		AvailableBalance:        availableBalance,
 		MaxWithdrawAmount:       maxWithdrawAmount,
@@ -281,7 +429,7 @@ func (b *BinanceClient) GetAccountInfo(ctx context.Context) (*AccountInfo, error
 
 // GetPositions retrieves current positions
 func (b *BinanceClient) GetPositions(ctx context.Context) ([]*PositionInfo, error) {
-	positions, err := b.client.NewGetPositionRiskService().Do(ctx)
+	positions, err := b.client.NewGetPositionRiskService().Do(ctx, b.signedOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get positions: %w", err)
 	}
@@ -315,7 +463,7 @@ func (b *BinanceClient) GetPositions(ctx context.Context) ([]*PositionInfo, erro
 
 // GetBalance retrieves account balance
 func (b *BinanceClient) GetBalance(ctx context.Context) ([]*BalanceInfo, error) {
-	account, err := b.client.NewGetAccountService().Do(ctx)
+	account, err := b.client.NewGetAccountService().Do(ctx, b.signedOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get balance: %w", err)
 	}
@@ -383,16 +531,33 @@ func (b *BinanceClient) GetSymbolInfo(ctx context.Context, symbol string) (*Symb
 
 	for _, s := range exchangeInfo.Symbols {
 		if s.Symbol == symbol {
-			return &SymbolInfo{
+			info := &SymbolInfo{
 				Symbol:                s.Symbol,
 				Status:                string(s.Status),
+				ContractType:          string(s.ContractType),
+				DeliveryDate:          s.DeliveryDate,
 				BaseAsset:             s.BaseAsset,
 				QuoteAsset:            s.QuoteAsset,
+				MarginAsset:           s.MarginAsset,
 				PricePrecision:        s.PricePrecision,
 				QuantityPrecision:     s.QuantityPrecision,
 				MaintMarginPercent:    parseFloat(s.MaintMarginPercent),
 				RequiredMarginPercent: parseFloat(s.RequiredMarginPercent),
-			}, nil
+			}
+			if lot := s.LotSizeFilter(); lot != nil {
+				info.MinQty = parseFloat(lot.MinQuantity)
+				info.MaxQty = parseFloat(lot.MaxQuantity)
+				info.StepSize = parseFloat(lot.StepSize)
+			}
+			if price := s.PriceFilter(); price != nil {
+				info.MinPrice = parseFloat(price.MinPrice)
+				info.MaxPrice = parseFloat(price.MaxPrice)
+				info.TickSize = parseFloat(price.TickSize)
+			}
+			if notional := s.MinNotionalFilter(); notional != nil {
+				info.MinNotional = parseFloat(notional.Notional)
+			}
+			return info, nil
 		}
 	}
 
@@ -430,13 +595,115 @@ func (b *BinanceClient) GetKlines(ctx context.Context, symbol string, interval s
 	return result, nil
 }
 
+// GetPremiumIndex retrieves mark price and funding rate for a symbol
+func (b *BinanceClient) GetPremiumIndex(ctx context.Context, symbol string) (*PremiumIndexInfo, error) {
+	indexes, err := b.client.NewPremiumIndexService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get premium index: %w", err)
+	}
+
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("no premium index data for symbol %s", symbol)
+	}
+
+	index := indexes[0]
+	return &PremiumIndexInfo{
+		Symbol:          index.Symbol,
+		MarkPrice:       parseFloat(index.MarkPrice),
+		IndexPrice:      parseFloat(index.IndexPrice),
+		FundingRate:     parseFloat(index.LastFundingRate),
+		NextFundingTime: index.NextFundingTime,
+		Time:            index.Time,
+	}, nil
+}
+
+// GetTicker24h retrieves the rolling 24 hour statistics of a symbol
+func (b *BinanceClient) GetTicker24h(ctx context.Context, symbol string) (*Ticker24h, error) {
+	stats, err := b.client.NewListPriceChangeStatsService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get 24h ticker: %w", err)
+	}
+
+	if len(stats) == 0 {
+		return nil, fmt.Errorf("no 24h ticker data for symbol %s", symbol)
+	}
+
+	stat := stats[0]
+	return &Ticker24h{
+		Symbol:             stat.Symbol,
+		PriceChange:        parseFloat(stat.PriceChange),
+		PriceChangePercent: parseFloat(stat.PriceChangePercent),
+		LastPrice:          parseFloat(stat.LastPrice),
+		OpenPrice:          parseFloat(stat.OpenPrice),
+		HighPrice:          parseFloat(stat.HighPrice),
+		LowPrice:           parseFloat(stat.LowPrice),
+		Volume:             parseFloat(stat.Volume),
+		QuoteVolume:        parseFloat(stat.QuoteVolume),
+		OpenTime:           stat.OpenTime,
+		CloseTime:          stat.CloseTime,
+	}, nil
+}
+
+// GetOpenInterest retrieves the current open interest for a symbol
+func (b *BinanceClient) GetOpenInterest(ctx context.Context, symbol string) (*OpenInterestInfo, error) {
+	oi, err := b.client.NewGetOpenInterestService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open interest: %w", err)
+	}
+
+	return &OpenInterestInfo{
+		Symbol:       oi.Symbol,
+		OpenInterest: parseFloat(oi.OpenInterest),
+		Time:         oi.Time,
+	}, nil
+}
+
+// GetLongShortRatio retrieves the latest global account long/short ratio for a symbol.
+// Period is one of 5m, 15m, 30m, 1h, 2h, 4h, 6h, 12h, 1d.
+func (b *BinanceClient) GetLongShortRatio(ctx context.Context, symbol string, period string) (*LongShortRatioInfo, error) {
+	ratios, err := b.client.NewLongShortRatioService().Symbol(symbol).Period(period).Limit(1).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get long/short ratio: %w", err)
+	}
+
+	if len(ratios) == 0 {
+		return nil, fmt.Errorf("no long/short ratio data for symbol %s", symbol)
+	}
+
+	ratio := ratios[len(ratios)-1]
+	return &LongShortRatioInfo{
+		Symbol:         ratio.Symbol,
+		LongShortRatio: parseFloat(ratio.LongShortRatio),
+		LongAccount:    parseFloat(ratio.LongAccount),
+		ShortAccount:   parseFloat(ratio.ShortAccount),
+		Timestamp:      ratio.Timestamp,
+	}, nil
+}
+
+// GetOrderBook retrieves a REST snapshot of the top levels of the order book
+func (b *BinanceClient) GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	depth, err := b.client.NewDepthService().Symbol(symbol).Limit(limit).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order book: %w", err)
+	}
+
+	return &OrderBook{
+		Symbol:       symbol,
+		LastUpdateID: depth.LastUpdateID,
+		EventTime:    depth.Time,
+		Bids:         toPriceLevels(depth.Bids),
+		Asks:         toPriceLevels(depth.Asks),
+	}, nil
+}
+
 // PlaceOrder places a new order
 func (b *BinanceClient) PlaceOrder(ctx context.Context, order *OrderRequest) (*OrderResponse, error) {
 	service := b.client.NewCreateOrderService().
 		Symbol(order.Symbol).
 		Side(futures.SideType(order.Side)).
 		Type(futures.OrderType(order.Type)).
-		Quantity(fmt.Sprintf("%.8f", order.Quantity))
+		Quantity(fmt.Sprintf("%.8f", order.Quantity)).
+		NewOrderResponseType(futures.NewOrderRespTypeRESULT) // Include fill status and average price
 
 	if order.Price > 0 {
 		service = service.Price(fmt.Sprintf("%.8f", order.Price))
@@ -462,15 +729,61 @@ func (b *BinanceClient) PlaceOrder(ctx context.Context, order *OrderRequest) (*O
 		service = service.PositionSide(futures.PositionSideType(order.PositionSide))
 	}
 
+	if order.WorkingType != "" {
+		service = service.WorkingType(futures.WorkingType(order.WorkingType))
+	}
+
+	if order.PriceProtect {
+		service = service.PriceProtect(order.PriceProtect)
+	}
+
+	if order.ActivationPrice > 0 {
+		service = service.ActivationPrice(fmt.Sprintf("%.8f", order.ActivationPrice))
+	}
+
+	if order.CallbackRate > 0 {
+		service = service.CallbackRate(fmt.Sprintf("%.1f", order.CallbackRate))
+	}
+
 	if order.NewClientOrderID != "" {
 		service = service.NewClientOrderID(order.NewClientOrderID)
 	}
 
-	response, err := service.Do(ctx)
+	var response *futures.CreateOrderResponse
+	err := b.withTimeSync(ctx, func() error {
+		var err error
+		response, err = service.Do(ctx, b.signedOpts()...)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to place order: %w", err)
 	}
 
+	return orderResponse(response), nil
+}
+
+// ModifyOrder amends the quantity and price of an open LIMIT order
+func (b *BinanceClient) ModifyOrder(ctx context.Context, order *ModifyOrderRequest) (*OrderResponse, error) {
+	params := url.Values{}
+	params.Set("symbol", order.Symbol)
+	params.Set("orderId", strconv.FormatInt(order.OrderID, 10))
+	params.Set("side", order.Side)
+	params.Set("quantity", fmt.Sprintf("%.8f", order.Quantity))
+	params.Set("price", fmt.Sprintf("%.8f", order.Price))
+
+	response := new(futures.CreateOrderResponse)
+	err := b.withTimeSync(ctx, func() error {
+		return b.signedRequest(ctx, http.MethodPut, "/fapi/v1/order", params, response)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to modify order: %w", err)
+	}
+
+	return orderResponse(response), nil
+}
+
+// orderResponse converts a create or modify order response
+func orderResponse(response *futures.CreateOrderResponse) *OrderResponse {
 	return &OrderResponse{
 		OrderID:       response.OrderID,
 		Symbol:        response.Symbol,
@@ -491,15 +804,18 @@ func (b *BinanceClient) PlaceOrder(ctx context.Context, order *OrderRequest) (*O
 		WorkingType:   string(response.WorkingType),
 		PriceProtect:  response.PriceProtect,
 		UpdateTime:    response.UpdateTime,
-	}, nil
+	}
 }
 
 // CancelOrder cancels an order
 func (b *BinanceClient) CancelOrder(ctx context.Context, symbol string, orderID int64) error {
-	_, err := b.client.NewCancelOrderService().
-		Symbol(symbol).
-		OrderID(orderID).
-		Do(ctx)
+	err := b.withTimeSync(ctx, func() error {
+		_, err := b.client.NewCancelOrderService().
+			Symbol(symbol).
+			OrderID(orderID).
+			Do(ctx, b.signedOpts()...)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to cancel order: %w", err)
 	}
@@ -512,7 +828,7 @@ func (b *BinanceClient) GetOrder(ctx context.Context, symbol string, orderID int
 	order, err := b.client.NewGetOrderService().
 		Symbol(symbol).
 		OrderID(orderID).
-		Do(ctx)
+		Do(ctx, b.signedOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
@@ -548,7 +864,7 @@ func (b *BinanceClient) GetOpenOrders(ctx context.Context, symbol string) ([]*Or
 		service = service.Symbol(symbol)
 	}
 
-	orders, err := service.Do(ctx)
+	orders, err := service.Do(ctx, b.signedOpts()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get open orders: %w", err)
 	}
@@ -582,12 +898,75 @@ func (b *BinanceClient) GetOpenOrders(ctx context.Context, symbol string) ([]*Or
 	return result, nil
 }
 
+// GetOrderTrades retrieves the fills of an order
+func (b *BinanceClient) GetOrderTrades(ctx context.Context, symbol string, orderID int64) ([]*TradeInfo, error) {
+	trades, err := b.client.NewListAccountTradeService().Symbol(symbol).OrderID(orderID).Do(ctx, b.signedOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order trades: %w", err)
+	}
+
+	result := make([]*TradeInfo, 0, len(trades))
+	for _, trade := range trades {
+		result = append(result, &TradeInfo{
+			Symbol:          trade.Symbol,
+			ID:              trade.ID,
+			OrderID:         trade.OrderID,
+			Side:            string(trade.Side),
+			Quantity:        parseFloat(trade.Quantity),
+			Price:           parseFloat(trade.Price),
+			Commission:      parseFloat(trade.Commission),
+			CommissionAsset: trade.CommissionAsset,
+			Time:            trade.Time,
+			IsMaker:         trade.Maker,
+			RealizedPnL:     parseFloat(trade.RealizedPnl),
+		})
+	}
+
+	return result, nil
+}
+
+// GetIncomeHistory retrieves income history entries; empty symbol and income type match all
+// and zero times leave the range open
+func (b *BinanceClient) GetIncomeHistory(ctx context.Context, symbol string, incomeType string, startTime, endTime int64, limit int) ([]*IncomeInfo, error) {
+	service := b.client.NewGetIncomeHistoryService().Symbol(symbol).IncomeType(incomeType)
+	if startTime > 0 {
+		service.StartTime(startTime)
+	}
+	if endTime > 0 {
+		service.EndTime(endTime)
+	}
+	if limit > 0 {
+		service.Limit(int64(limit))
+	}
+
+	incomes, err := service.Do(ctx, b.signedOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get income history: %w", err)
+	}
+
+	result := make([]*IncomeInfo, 0, len(incomes))
+	for _, income := range incomes {
+		result = append(result, &IncomeInfo{
+			Symbol:     income.Symbol,
+			IncomeType: income.IncomeType,
+			Income:     parseFloat(income.Income),
+			Asset:      income.Asset,
+			Info:       income.Info,
+			Time:       income.Time,
+			TranID:     income.TranID,
+			TradeID:    income.TradeID,
+		})
+	}
+
+	return result, nil
+}
+
 // SetLeverage sets leverage for a symbol
 func (b *BinanceClient) SetLeverage(ctx context.Context, symbol string, leverage int) error {
 	_, err := b.client.NewChangeLeverageService().
 		Symbol(symbol).
 		Leverage(leverage).
-		Do(ctx)
+		Do(ctx, b.signedOpts()...)
 	if err != nil {
 		return fmt.Errorf("failed to set leverage: %w", err)
 	}
@@ -601,7 +980,7 @@ func (b *BinanceClient) ChangeMarginType(ctx context.Context, symbol string, mar
 	err := b.client.NewChangeMarginTypeService().
 		Symbol(symbol).
 		MarginType(futures.MarginType(marginType)).
-		Do(ctx)
+		Do(ctx, b.signedOpts()...)
 	if err != nil {
 		return fmt.Errorf("failed to change margin type: %w", err)
 	}
@@ -610,6 +989,86 @@ func (b *BinanceClient) ChangeMarginType(ctx context.Context, symbol string, mar
 	return nil
 }
 
+// GetADLQuantiles retrieves the auto-deleveraging quantile of every symbol with a position;
+// the exchange refreshes them every 30 seconds
+func (b *BinanceClient) GetADLQuantiles(ctx context.Context) ([]*ADLQuantile, error) {
+	var quantiles []*ADLQuantile
+	err := b.withTimeSync(ctx, func() error {
+		return b.signedRequest(ctx, http.MethodGet, "/fapi/v1/adlQuantile", url.Values{}, &quantiles)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ADL quantiles: %w", err)
+	}
+	return quantiles, nil
+}
+
+// SetAutoCancel starts or refreshes the exchange's countdown that cancels all open orders of
+// the symbol unless refreshed again before it runs out. A zero countdown stops the timer.
+func (b *BinanceClient) SetAutoCancel(ctx context.Context, symbol string, countdown time.Duration) error {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("countdownTime", strconv.FormatInt(countdown.Milliseconds(), 10))
+
+	err := b.withTimeSync(ctx, func() error {
+		return b.signedRequest(ctx, http.MethodPost, "/fapi/v1/countdownCancelAll", params, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set auto-cancel countdown: %w", err)
+	}
+	return nil
+}
+
+// GetSymbolSettings retrieves the leverage and margin type currently set for every symbol
+func (b *BinanceClient) GetSymbolSettings(ctx context.Context) ([]*SymbolSettings, error) {
+	positions, err := b.client.NewGetPositionRiskService().Do(ctx, b.signedOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get position risk: %w", err)
+	}
+
+	seen := make(map[string]bool, len(positions))
+	var result []*SymbolSettings
+	for _, pos := range positions {
+		// Hedge mode returns one entry per side; settings are per symbol
+		if seen[pos.Symbol] {
+			continue
+		}
+		seen[pos.Symbol] = true
+
+		leverage, _ := strconv.Atoi(pos.Leverage)
+		marginType := "CROSSED"
+		if strings.EqualFold(pos.MarginType, "isolated") {
+			marginType = "ISOLATED"
+		}
+
+		result = append(result, &SymbolSettings{
+			Symbol:     pos.Symbol,
+			Leverage:   leverage,
+			MarginType: marginType,
+		})
+	}
+
+	return result, nil
+}
+
+// GetPositionMode reports whether the account is in hedge (dual side) position mode
+func (b *BinanceClient) GetPositionMode(ctx context.Context) (bool, error) {
+	mode, err := b.client.NewGetPositionModeService().Do(ctx, b.signedOpts()...)
+	if err != nil {
+		return false, fmt.Errorf("failed to get position mode: %w", err)
+	}
+	return mode.DualSidePosition, nil
+}
+
+// SetPositionMode switches between hedge (dual side) and one-way position mode
+func (b *BinanceClient) SetPositionMode(ctx context.Context, dualSide bool) error {
+	if err := b.client.NewChangePositionModeService().DualSide(dualSide).Do(ctx, b.signedOpts()...); err != nil {
+		return fmt.Errorf("failed to change position mode: %w", err)
+	}
+
+	b.logger.Infof("Changed position mode (dual side: %t)", dualSide)
+	return nil
+}
+
 // GetExchangeInfo retrieves exchange information
 func (b *BinanceClient) GetExchangeInfo(ctx context.Context) (*ExchangeInfo, error) {
 	info, err := b.client.NewExchangeInfoService().Do(ctx)
@@ -622,8 +1081,11 @@ func (b *BinanceClient) GetExchangeInfo(ctx context.Context) (*ExchangeInfo, err
 		symbols = append(symbols, &SymbolInfo{
 			Symbol:                s.Symbol,
 			Status:                string(s.Status),
+			ContractType:          string(s.ContractType),
+			DeliveryDate:          s.DeliveryDate,
 			BaseAsset:             s.BaseAsset,
 			QuoteAsset:            s.QuoteAsset,
+			MarginAsset:           s.MarginAsset,
 			PricePrecision:        s.PricePrecision,
 			QuantityPrecision:     s.QuantityPrecision,
 			MaintMarginPercent:    parseFloat(s.MaintMarginPercent),
@@ -638,14 +1100,6 @@ func (b *BinanceClient) GetExchangeInfo(ctx context.Context) (*ExchangeInfo, err
 	}, nil
 }
 
-// StartUserDataStream starts user data stream (placeholder implementation)
-func (b *BinanceClient) StartUserDataStream(ctx context.Context, handler UserDataHandler) error {
-	// This would implement WebSocket user data stream
-	// For now, it's a placeholder
-	b.logger.Info("User data stream would be started here")
-	return nil
-}
-
 // StartMarketDataStream starts market data stream (placeholder implementation)
 func (b *BinanceClient) StartMarketDataStream(ctx context.Context, symbols []string, handler MarketDataHandler) error {
 	// This would implement WebSocket market data stream
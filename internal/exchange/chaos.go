@@ -0,0 +1,368 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/sirupsen/logrus"
+)
+
+// ChaosClient wraps a Client and injects latency, failed requests, lost order responses,
+// partially filled market orders and stream disconnects. Lost responses and partial fills
+// are reported to the caller only; the exchange processed the request in full, so the
+// engine's reconciliation has to find the difference.
+type ChaosClient struct {
+	Client
+
+	cfg    config.ChaosConfig
+	logger *logrus.Logger
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewChaosClient wraps the client with the configured fault injection
+func NewChaosClient(client Client, cfg config.ChaosConfig, logger *logrus.Logger) *ChaosClient {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	logger.Warnf("Exchange chaos injection enabled (seed %d): latency %dms+%dms, error rate %.2f, lost responses %.2f, partial fills %.2f, stream drops every ~%ds",
+		seed, cfg.LatencyMs, cfg.LatencyJitterMs, cfg.ErrorRate, cfg.LostResponseRate, cfg.PartialFillRate, cfg.DisconnectMeanSeconds)
+
+	return &ChaosClient{
+		Client: client,
+		cfg:    cfg,
+		logger: logger,
+		rng:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// chance reports true with the given probability
+func (c *ChaosClient) chance(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Float64() < rate
+}
+
+func (c *ChaosClient) float64() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Float64()
+}
+
+// delay sleeps for the configured latency plus jitter, or until ctx is done
+func (c *ChaosClient) delay(ctx context.Context) error {
+	latency := time.Duration(c.cfg.LatencyMs) * time.Millisecond
+	if c.cfg.LatencyJitterMs > 0 {
+		latency += time.Duration(c.float64() * float64(time.Duration(c.cfg.LatencyJitterMs)*time.Millisecond))
+	}
+	if latency <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// injectedError returns either a transport error or an overloaded-exchange API error, both of
+// which the circuit breaker counts as failures
+func (c *ChaosClient) injectedError(name string) error {
+	if c.chance(0.5) {
+		return fmt.Errorf("chaos: connection reset during %s", name)
+	}
+	return &common.APIError{Code: -1008, Message: fmt.Sprintf("chaos: server overloaded during %s", name)}
+}
+
+// chaosCall runs one request after the injected latency, failing it before it is sent at
+// the configured error rate
+func chaosCall[T any](c *ChaosClient, ctx context.Context, name string, call func(context.Context) (T, error)) (T, error) {
+	var zero T
+
+	if err := c.delay(ctx); err != nil {
+		return zero, err
+	}
+	if c.chance(c.cfg.ErrorRate) {
+		err := c.injectedError(name)
+		c.logger.Warnf("Chaos: failing %s: %v", name, err)
+		return zero, err
+	}
+	return call(ctx)
+}
+
+// chaosErr is chaosCall for requests that return only an error
+func chaosErr(c *ChaosClient, ctx context.Context, name string, call func(context.Context) error) error {
+	_, err := chaosCall(c, ctx, name, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, call(ctx)
+	})
+	return err
+}
+
+// loseResponse replaces a successful result with an error at the configured rate
+func (c *ChaosClient) loseResponse(name string, err error) error {
+	if err != nil || !c.chance(c.cfg.LostResponseRate) {
+		return err
+	}
+	c.logger.Warnf("Chaos: dropping the response of a successful %s", name)
+	return fmt.Errorf("chaos: response of %s lost: %w", name, context.DeadlineExceeded)
+}
+
+func (c *ChaosClient) GetAccountInfo(ctx context.Context) (*AccountInfo, error) {
+	return chaosCall(c, ctx, "GetAccountInfo", c.Client.GetAccountInfo)
+}
+
+func (c *ChaosClient) GetPositions(ctx context.Context) ([]*PositionInfo, error) {
+	return chaosCall(c, ctx, "GetPositions", c.Client.GetPositions)
+}
+
+func (c *ChaosClient) GetBalance(ctx context.Context) ([]*BalanceInfo, error) {
+	return chaosCall(c, ctx, "GetBalance", c.Client.GetBalance)
+}
+
+func (c *ChaosClient) GetSymbolPrice(ctx context.Context, symbol string) (float64, error) {
+	return chaosCall(c, ctx, "GetSymbolPrice", func(ctx context.Context) (float64, error) {
+		return c.Client.GetSymbolPrice(ctx, symbol)
+	})
+}
+
+func (c *ChaosClient) GetSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	return chaosCall(c, ctx, "GetSymbolInfo", func(ctx context.Context) (*SymbolInfo, error) {
+		return c.Client.GetSymbolInfo(ctx, symbol)
+	})
+}
+
+func (c *ChaosClient) GetKlines(ctx context.Context, symbol string, interval string, limit int) ([]*KlineData, error) {
+	return chaosCall(c, ctx, "GetKlines", func(ctx context.Context) ([]*KlineData, error) {
+		return c.Client.GetKlines(ctx, symbol, interval, limit)
+	})
+}
+
+func (c *ChaosClient) GetPremiumIndex(ctx context.Context, symbol string) (*PremiumIndexInfo, error) {
+	return chaosCall(c, ctx, "GetPremiumIndex", func(ctx context.Context) (*PremiumIndexInfo, error) {
+		return c.Client.GetPremiumIndex(ctx, symbol)
+	})
+}
+
+func (c *ChaosClient) GetOpenInterest(ctx context.Context, symbol string) (*OpenInterestInfo, error) {
+	return chaosCall(c, ctx, "GetOpenInterest", func(ctx context.Context) (*OpenInterestInfo, error) {
+		return c.Client.GetOpenInterest(ctx, symbol)
+	})
+}
+
+func (c *ChaosClient) GetLongShortRatio(ctx context.Context, symbol string, period string) (*LongShortRatioInfo, error) {
+	return chaosCall(c, ctx, "GetLongShortRatio", func(ctx context.Context) (*LongShortRatioInfo, error) {
+		return c.Client.GetLongShortRatio(ctx, symbol, period)
+	})
+}
+
+func (c *ChaosClient) GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	return chaosCall(c, ctx, "GetOrderBook", func(ctx context.Context) (*OrderBook, error) {
+		return c.Client.GetOrderBook(ctx, symbol, limit)
+	})
+}
+
+func (c *ChaosClient) GetTicker24h(ctx context.Context, symbol string) (*Ticker24h, error) {
+	return chaosCall(c, ctx, "GetTicker24h", func(ctx context.Context) (*Ticker24h, error) {
+		return c.Client.GetTicker24h(ctx, symbol)
+	})
+}
+
+// PlaceOrder may also lose the response of an accepted order or report a filled market
+// order as partially filled
+func (c *ChaosClient) PlaceOrder(ctx context.Context, order *OrderRequest) (*OrderResponse, error) {
+	resp, err := chaosCall(c, ctx, "PlaceOrder", func(ctx context.Context) (*OrderResponse, error) {
+		return c.Client.PlaceOrder(ctx, order)
+	})
+	if err = c.loseResponse("PlaceOrder", err); err != nil {
+		return nil, err
+	}
+
+	if resp.Type == "MARKET" && resp.Status == "FILLED" && resp.ExecutedQty > 0 && c.chance(c.cfg.PartialFillRate) {
+		partial := *resp
+		fraction := 0.2 + 0.6*c.float64()
+		partial.ExecutedQty = resp.ExecutedQty * fraction
+		partial.CumQuote = resp.CumQuote * fraction
+		partial.Status = "PARTIALLY_FILLED"
+		c.logger.Warnf("Chaos: reporting order %d on %s as partially filled (%.8f of %.8f)",
+			resp.OrderID, resp.Symbol, partial.ExecutedQty, resp.ExecutedQty)
+		return &partial, nil
+	}
+	return resp, nil
+}
+
+func (c *ChaosClient) ModifyOrder(ctx context.Context, order *ModifyOrderRequest) (*OrderResponse, error) {
+	resp, err := chaosCall(c, ctx, "ModifyOrder", func(ctx context.Context) (*OrderResponse, error) {
+		return c.Client.ModifyOrder(ctx, order)
+	})
+	if err = c.loseResponse("ModifyOrder", err); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *ChaosClient) CancelOrder(ctx context.Context, symbol string, orderID int64) error {
+	err := chaosErr(c, ctx, "CancelOrder", func(ctx context.Context) error {
+		return c.Client.CancelOrder(ctx, symbol, orderID)
+	})
+	return c.loseResponse("CancelOrder", err)
+}
+
+func (c *ChaosClient) GetOrder(ctx context.Context, symbol string, orderID int64) (*OrderInfo, error) {
+	return chaosCall(c, ctx, "GetOrder", func(ctx context.Context) (*OrderInfo, error) {
+		return c.Client.GetOrder(ctx, symbol, orderID)
+	})
+}
+
+func (c *ChaosClient) GetOpenOrders(ctx context.Context, symbol string) ([]*OrderInfo, error) {
+	return chaosCall(c, ctx, "GetOpenOrders", func(ctx context.Context) ([]*OrderInfo, error) {
+		return c.Client.GetOpenOrders(ctx, symbol)
+	})
+}
+
+func (c *ChaosClient) GetOrderTrades(ctx context.Context, symbol string, orderID int64) ([]*TradeInfo, error) {
+	return chaosCall(c, ctx, "GetOrderTrades", func(ctx context.Context) ([]*TradeInfo, error) {
+		return c.Client.GetOrderTrades(ctx, symbol, orderID)
+	})
+}
+
+func (c *ChaosClient) GetIncomeHistory(ctx context.Context, symbol string, incomeType string, startTime, endTime int64, limit int) ([]*IncomeInfo, error) {
+	return chaosCall(c, ctx, "GetIncomeHistory", func(ctx context.Context) ([]*IncomeInfo, error) {
+		return c.Client.GetIncomeHistory(ctx, symbol, incomeType, startTime, endTime, limit)
+	})
+}
+
+func (c *ChaosClient) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	return chaosErr(c, ctx, "SetLeverage", func(ctx context.Context) error {
+		return c.Client.SetLeverage(ctx, symbol, leverage)
+	})
+}
+
+func (c *ChaosClient) ChangeMarginType(ctx context.Context, symbol string, marginType string) error {
+	return chaosErr(c, ctx, "ChangeMarginType", func(ctx context.Context) error {
+		return c.Client.ChangeMarginType(ctx, symbol, marginType)
+	})
+}
+
+func (c *ChaosClient) GetADLQuantiles(ctx context.Context) ([]*ADLQuantile, error) {
+	return chaosCall(c, ctx, "GetADLQuantiles", c.Client.GetADLQuantiles)
+}
+
+func (c *ChaosClient) SetAutoCancel(ctx context.Context, symbol string, countdown time.Duration) error {
+	return chaosErr(c, ctx, "SetAutoCancel", func(ctx context.Context) error {
+		return c.Client.SetAutoCancel(ctx, symbol, countdown)
+	})
+}
+
+func (c *ChaosClient) GetSymbolSettings(ctx context.Context) ([]*SymbolSettings, error) {
+	return chaosCall(c, ctx, "GetSymbolSettings", c.Client.GetSymbolSettings)
+}
+
+func (c *ChaosClient) GetPositionMode(ctx context.Context) (bool, error) {
+	return chaosCall(c, ctx, "GetPositionMode", c.Client.GetPositionMode)
+}
+
+func (c *ChaosClient) SetPositionMode(ctx context.Context, dualSide bool) error {
+	return chaosErr(c, ctx, "SetPositionMode", func(ctx context.Context) error {
+		return c.Client.SetPositionMode(ctx, dualSide)
+	})
+}
+
+func (c *ChaosClient) GetExchangeInfo(ctx context.Context) (*ExchangeInfo, error) {
+	return chaosCall(c, ctx, "GetExchangeInfo", c.Client.GetExchangeInfo)
+}
+
+// stream starts a stream under a child context and, when disconnects are enabled, cancels
+// and restarts it after exponentially distributed intervals so handlers go through their
+// reconnect and re-sync paths
+func (c *ChaosClient) stream(ctx context.Context, name string, start func(context.Context) error) error {
+	if c.cfg.DisconnectMeanSeconds <= 0 {
+		return start(ctx)
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	if err := start(streamCtx); err != nil {
+		cancel()
+		return err
+	}
+
+	go func() {
+		mean := float64(time.Duration(c.cfg.DisconnectMeanSeconds) * time.Second)
+		for {
+			c.mu.Lock()
+			wait := time.Duration(c.rng.ExpFloat64() * mean)
+			c.mu.Unlock()
+
+			select {
+			case <-ctx.Done():
+				cancel()
+				return
+			case <-time.After(wait):
+			}
+
+			c.logger.Warnf("Chaos: dropping %s stream", name)
+			cancel()
+			streamCtx, cancel = context.WithCancel(ctx)
+			if err := start(streamCtx); err != nil {
+				c.logger.Errorf("Chaos: failed to restart %s stream: %v", name, err)
+			}
+		}
+	}()
+	return nil
+}
+
+func (c *ChaosClient) StartUserDataStream(ctx context.Context, handler UserDataHandler) error {
+	return c.stream(ctx, "user data", func(ctx context.Context) error {
+		return c.Client.StartUserDataStream(ctx, handler)
+	})
+}
+
+func (c *ChaosClient) StartMarketDataStream(ctx context.Context, symbols []string, handler MarketDataHandler) error {
+	return c.stream(ctx, "market data", func(ctx context.Context) error {
+		return c.Client.StartMarketDataStream(ctx, symbols, handler)
+	})
+}
+
+func (c *ChaosClient) StartDepthStream(ctx context.Context, symbols []string, handler DepthHandler) error {
+	return c.stream(ctx, "depth", func(ctx context.Context) error {
+		return c.Client.StartDepthStream(ctx, symbols, handler)
+	})
+}
+
+func (c *ChaosClient) StartMarkPriceStream(ctx context.Context, symbols []string, handler MarkPriceHandler) error {
+	return c.stream(ctx, "mark price", func(ctx context.Context) error {
+		return c.Client.StartMarkPriceStream(ctx, symbols, handler)
+	})
+}
+
+func (c *ChaosClient) StartKlineStream(ctx context.Context, symbols []string, interval string, handler KlineHandler) error {
+	return c.stream(ctx, "kline", func(ctx context.Context) error {
+		return c.Client.StartKlineStream(ctx, symbols, interval, handler)
+	})
+}
+
+func (c *ChaosClient) StartAggTradeStream(ctx context.Context, symbols []string, handler AggTradeHandler) error {
+	return c.stream(ctx, "aggregate trade", func(ctx context.Context) error {
+		return c.Client.StartAggTradeStream(ctx, symbols, handler)
+	})
+}
+
+func (c *ChaosClient) StartLiquidationStream(ctx context.Context, symbols []string, handler LiquidationHandler) error {
+	return c.stream(ctx, "liquidation", func(ctx context.Context) error {
+		return c.Client.StartLiquidationStream(ctx, symbols, handler)
+	})
+}
@@ -0,0 +1,61 @@
+package exchange
+
+// Futures markets a client can trade
+const (
+	MarketUSDM  = "usdm"  // USDT- and USDC-margined linear contracts (fapi)
+	MarketCoinM = "coinm" // Coin-margined inverse contracts (dapi)
+)
+
+// Inverse reports whether the contract is coin-margined: quantities are contracts worth
+// ContractSize units of the quote asset and PnL is paid in the base asset
+func (s *SymbolInfo) Inverse() bool {
+	return s.ContractSize > 0
+}
+
+// Notional returns the quote asset value of a quantity at a price
+func (s *SymbolInfo) Notional(quantity, price float64) float64 {
+	if s.Inverse() {
+		return quantity * s.ContractSize
+	}
+	return quantity * price
+}
+
+// Quantity returns the order quantity worth a quote asset notional at a price
+func (s *SymbolInfo) Quantity(notional, price float64) float64 {
+	if s.Inverse() {
+		return notional / s.ContractSize
+	}
+	if price <= 0 {
+		return 0
+	}
+	return notional / price
+}
+
+// PnL returns the profit of a long quantity entered at entry and exited at exit, in the quote
+// asset. Inverse contracts pay quantity*ContractSize*(1/entry - 1/exit) in the base asset,
+// which is valued at the exit price.
+func (s *SymbolInfo) PnL(entry, exit, quantity float64) float64 {
+	if !s.Inverse() {
+		return (exit - entry) * quantity
+	}
+	if entry <= 0 || exit <= 0 {
+		return 0
+	}
+	return quantity * s.ContractSize * (1/entry - 1/exit) * exit
+}
+
+// AverageEntry returns the entry price after adding quantity at price to size at entry. Inverse
+// contracts average the entry harmonically because each contract is a fixed quote amount.
+func (s *SymbolInfo) AverageEntry(entry, size, price, quantity float64) float64 {
+	total := size + quantity
+	if total <= 0 {
+		return entry
+	}
+	if !s.Inverse() {
+		return (entry*size + price*quantity) / total
+	}
+	if entry <= 0 {
+		return price
+	}
+	return total / (size/entry + quantity/price)
+}
@@ -0,0 +1,856 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"contract_playground/internal/config"
+
+	"github.com/adshao/go-binance/v2/delivery"
+	"github.com/sirupsen/logrus"
+)
+
+// DeliveryClient implements Client for Binance coin-margined futures (dapi). Quantities are
+// contracts and the exchange reports balances and PnL in the margin coin; account and
+// position amounts are valued in USD at the perpetual's mark price so the engine's quote
+// asset accounting applies unchanged.
+type DeliveryClient struct {
+	client *delivery.Client
+	config config.ExchangeConfig
+	logger *logrus.Logger
+}
+
+// NewDeliveryClient creates a new Binance coin-margined futures client
+func NewDeliveryClient(cfg config.ExchangeConfig, logger *logrus.Logger) (Client, error) {
+	if cfg.Testnet {
+		delivery.UseTestnet = true
+	}
+
+	// Detect silently dead websocket connections so streams reconnect
+	delivery.WebsocketKeepalive = true
+
+	client := delivery.NewClient(cfg.APIKey, cfg.SecretKey)
+	d := &DeliveryClient{
+		client: client,
+		config: cfg,
+		logger: logger,
+	}
+
+	if err := syncServerTime(context.Background(), "delivery", d.syncTime, cfg.RecvWindowMs, logger); err != nil {
+		logger.Warnf("Failed to sync delivery server time, using local clock: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := client.NewGetAccountService().Do(ctx, d.signedOpts()...); err != nil {
+		return nil, fmt.Errorf("failed to connect to Binance coin-margined futures: %w", err)
+	}
+
+	logger.Info("Successfully connected to Binance coin-margined futures API")
+
+	go runServerTimeSync("delivery", d.syncTime, time.Duration(cfg.TimeSyncIntervalSeconds)*time.Second, cfg.RecvWindowMs, logger)
+
+	return wrapClient(d, cfg, logger)
+}
+
+// signedOpts returns the request options for signed delivery endpoints
+func (d *DeliveryClient) signedOpts() []delivery.RequestOption {
+	if d.config.RecvWindowMs <= 0 {
+		return nil
+	}
+	return []delivery.RequestOption{delivery.WithRecvWindow(d.config.RecvWindowMs)}
+}
+
+// withTimeSync runs a signed delivery request, retrying once after a timestamp rejection
+func (d *DeliveryClient) withTimeSync(ctx context.Context, call func() error) error {
+	return retryAfterTimeSync(ctx, "delivery", d.syncTime, d.config.RecvWindowMs, d.logger, call)
+}
+
+// syncTime updates the delivery client's server-time offset
+func (d *DeliveryClient) syncTime(ctx context.Context) (int64, error) {
+	return d.client.NewSetServerTimeService().Do(ctx)
+}
+
+// request calls a dapi endpoint that go-binance has no delivery service for
+func (d *DeliveryClient) request(ctx context.Context, method, endpoint string, params url.Values, signed bool, result interface{}) error {
+	rest := &restEndpoint{
+		baseURL:      d.client.BaseURL,
+		apiKey:       d.client.APIKey,
+		secretKey:    d.client.SecretKey,
+		keyType:      d.client.KeyType,
+		httpClient:   d.client.HTTPClient,
+		timeOffset:   d.client.TimeOffset,
+		recvWindowMs: d.config.RecvWindowMs,
+	}
+	return rest.do(ctx, method, endpoint, params, signed, result)
+}
+
+// deliveryPremiumIndex is an entry of /dapi/v1/premiumIndex
+type deliveryPremiumIndex struct {
+	Symbol          string `json:"symbol"`
+	Pair            string `json:"pair"`
+	MarkPrice       string `json:"markPrice"`
+	IndexPrice      string `json:"indexPrice"`
+	LastFundingRate string `json:"lastFundingRate"`
+	NextFundingTime int64  `json:"nextFundingTime"`
+	Time            int64  `json:"time"`
+}
+
+// premiumIndexes returns the premium index of a symbol, or of every symbol when empty
+func (d *DeliveryClient) premiumIndexes(ctx context.Context, symbol string) ([]*deliveryPremiumIndex, error) {
+	params := url.Values{}
+	if symbol != "" {
+		params.Set("symbol", symbol)
+	}
+	var indexes []*deliveryPremiumIndex
+	if err := d.request(ctx, http.MethodGet, "/dapi/v1/premiumIndex", params, false, &indexes); err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+// coinPrices returns the USD mark price of every margin coin, taken from its perpetual
+func (d *DeliveryClient) coinPrices(ctx context.Context) (map[string]float64, error) {
+	indexes, err := d.premiumIndexes(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mark prices: %w", err)
+	}
+	prices := make(map[string]float64, len(indexes))
+	for _, index := range indexes {
+		if strings.HasSuffix(index.Symbol, "_PERP") && strings.HasSuffix(index.Pair, "USD") {
+			prices[strings.TrimSuffix(index.Pair, "USD")] = parseFloat(index.MarkPrice)
+		}
+	}
+	return prices, nil
+}
+
+// GetAccountInfo retrieves the account with every margin coin valued in USD
+func (d *DeliveryClient) GetAccountInfo(ctx context.Context) (*AccountInfo, error) {
+	account, err := d.client.NewGetAccountService().Do(ctx, d.signedOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account info: %w", err)
+	}
+	prices, err := d.coinPrices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account info: %w", err)
+	}
+
+	info := &AccountInfo{
+		CanTrade:    account.CanTrade,
+		CanWithdraw: account.CanWithdraw,
+		CanDeposit:  account.CanDeposit,
+		UpdateTime:  account.UpdateTime,
+	}
+	for _, asset := range account.Assets {
+		price := prices[asset.Asset]
+		info.TotalWalletBalance += parseFloat(asset.WalletBalance) * price
+		info.TotalUnrealizedPnL += parseFloat(asset.UnrealizedProfit) * price
+		info.TotalMarginBalance += parseFloat(asset.MarginBalance) * price
+		info.TotalPositionIM += parseFloat(asset.PositionInitialMargin) * price
+		info.TotalOpenOrderIM += parseFloat(asset.OpenOrderInitialMargin) * price
+		info.TotalMaintMargin += parseFloat(asset.MaintMargin) * price
+		info.TotalCrossWalletBalance += parseFloat(asset.CrossWalletBalance) * price
+		info.AvailableBalance += parseFloat(asset.AvailableBalance) * price
+		info.MaxWithdrawAmount += parseFloat(asset.MaxWithdrawAmount) * price
+	}
+	return info, nil
+}
+
+// GetPositions retrieves current positions; unrealized PnL is valued in USD
+func (d *DeliveryClient) GetPositions(ctx context.Context) ([]*PositionInfo, error) {
+	positions, err := d.client.NewGetPositionRiskService().Do(ctx, d.signedOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	var result []*PositionInfo
+	for _, pos := range positions {
+		positionAmt := parseFloat(pos.PositionAmt)
+		if positionAmt == 0 {
+			continue
+		}
+		markPrice := parseFloat(pos.MarkPrice)
+		leverage, _ := strconv.Atoi(pos.Leverage)
+		result = append(result, &PositionInfo{
+			Symbol:        pos.Symbol,
+			PositionSide:  pos.PositionSide,
+			PositionAmt:   positionAmt,
+			EntryPrice:    parseFloat(pos.EntryPrice),
+			MarkPrice:     markPrice,
+			UnrealizedPnL: parseFloat(pos.UnRealizedProfit) * markPrice,
+			Leverage:      leverage,
+			Margin:        parseFloat(pos.IsolatedMargin) * markPrice,
+		})
+	}
+
+	return result, nil
+}
+
+// GetBalance retrieves the balance of every margin coin, in the coin
+func (d *DeliveryClient) GetBalance(ctx context.Context) ([]*BalanceInfo, error) {
+	account, err := d.client.NewGetAccountService().Do(ctx, d.signedOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	result := make([]*BalanceInfo, 0, len(account.Assets))
+	for _, asset := range account.Assets {
+		result = append(result, &BalanceInfo{
+			Asset:              asset.Asset,
+			WalletBalance:      parseFloat(asset.WalletBalance),
+			UnrealizedPnL:      parseFloat(asset.UnrealizedProfit),
+			MarginBalance:      parseFloat(asset.MarginBalance),
+			MaintMargin:        parseFloat(asset.MaintMargin),
+			InitialMargin:      parseFloat(asset.InitialMargin),
+			PositionIM:         parseFloat(asset.PositionInitialMargin),
+			OpenOrderIM:        parseFloat(asset.OpenOrderInitialMargin),
+			CrossWalletBalance: parseFloat(asset.CrossWalletBalance),
+			CrossUnPnL:         parseFloat(asset.CrossUnPnl),
+			AvailableBalance:   parseFloat(asset.AvailableBalance),
+			MaxWithdrawAmount:  parseFloat(asset.MaxWithdrawAmount),
+			MarginAvailable:    true,
+			UpdateTime:         account.UpdateTime,
+		})
+	}
+
+	return result, nil
+}
+
+// GetSymbolPrice retrieves current price for a symbol
+func (d *DeliveryClient) GetSymbolPrice(ctx context.Context, symbol string) (float64, error) {
+	prices, err := d.client.NewListPricesService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get symbol price: %w", err)
+	}
+
+	for _, price := range prices {
+		if price.Symbol == symbol {
+			return parseFloat(price.Price), nil
+		}
+	}
+	return 0, fmt.Errorf("no price data for symbol %s", symbol)
+}
+
+// GetSymbolInfo retrieves symbol information
+func (d *DeliveryClient) GetSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	info, err := d.GetExchangeInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range info.Symbols {
+		if s.Symbol == symbol {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("symbol %s not found", symbol)
+}
+
+// GetKlines retrieves kline/candlestick data. Volume is in contracts and the exchange reports
+// the quote volume in the base asset, which is valued at the close price.
+func (d *DeliveryClient) GetKlines(ctx context.Context, symbol string, interval string, limit int) ([]*KlineData, error) {
+	klines, err := d.client.NewKlinesService().
+		Symbol(symbol).
+		Interval(interval).
+		Limit(limit).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get klines: %w", err)
+	}
+
+	result := make([]*KlineData, 0, len(klines))
+	for _, k := range klines {
+		closePrice := parseFloat(k.Close)
+		result = append(result, &KlineData{
+			OpenTime:                 k.OpenTime,
+			Open:                     parseFloat(k.Open),
+			High:                     parseFloat(k.High),
+			Low:                      parseFloat(k.Low),
+			Close:                    closePrice,
+			Volume:                   parseFloat(k.Volume),
+			CloseTime:                k.CloseTime,
+			QuoteAssetVolume:         parseFloat(k.QuoteAssetVolume) * closePrice,
+			TradeCount:               k.TradeNum,
+			TakerBuyBaseAssetVolume:  parseFloat(k.TakerBuyBaseAssetVolume),
+			TakerBuyQuoteAssetVolume: parseFloat(k.TakerBuyQuoteAssetVolume) * closePrice,
+		})
+	}
+
+	return result, nil
+}
+
+// GetPremiumIndex retrieves mark price and funding rate for a symbol
+func (d *DeliveryClient) GetPremiumIndex(ctx context.Context, symbol string) (*PremiumIndexInfo, error) {
+	indexes, err := d.premiumIndexes(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get premium index: %w", err)
+	}
+
+	for _, index := range indexes {
+		if index.Symbol == symbol {
+			return &PremiumIndexInfo{
+				Symbol:          index.Symbol,
+				MarkPrice:       parseFloat(index.MarkPrice),
+				IndexPrice:      parseFloat(index.IndexPrice),
+				FundingRate:     parseFloat(index.LastFundingRate),
+				NextFundingTime: index.NextFundingTime,
+				Time:            index.Time,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("no premium index data for symbol %s", symbol)
+}
+
+// GetTicker24h retrieves the rolling 24 hour statistics of a symbol. The exchange reports the
+// volume in contracts and the base asset; the base volume is valued at the last price.
+func (d *DeliveryClient) GetTicker24h(ctx context.Context, symbol string) (*Ticker24h, error) {
+	stats, err := d.client.NewListPriceChangeStatsService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get 24h ticker: %w", err)
+	}
+
+	for _, stat := range stats {
+		if stat.Symbol != symbol {
+			continue
+		}
+		lastPrice := parseFloat(stat.LastPrice)
+		baseVolume := parseFloat(stat.BaseVolume)
+		return &Ticker24h{
+			Symbol:             stat.Symbol,
+			PriceChange:        parseFloat(stat.PriceChange),
+			PriceChangePercent: parseFloat(stat.PriceChangePercent),
+			LastPrice:          lastPrice,
+			OpenPrice:          parseFloat(stat.OpenPrice),
+			HighPrice:          parseFloat(stat.HighPrice),
+			LowPrice:           parseFloat(stat.LowPrice),
+			Volume:             baseVolume,
+			QuoteVolume:        baseVolume * lastPrice,
+			OpenTime:           stat.OpenTime,
+			CloseTime:          stat.CloseTime,
+		}, nil
+	}
+	return nil, fmt.Errorf("no 24h ticker data for symbol %s", symbol)
+}
+
+// GetOpenInterest retrieves the current open interest for a symbol, in contracts
+func (d *DeliveryClient) GetOpenInterest(ctx context.Context, symbol string) (*OpenInterestInfo, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+
+	var oi struct {
+		Symbol       string `json:"symbol"`
+		OpenInterest string `json:"openInterest"`
+		Time         int64  `json:"time"`
+	}
+	if err := d.request(ctx, http.MethodGet, "/dapi/v1/openInterest", params, false, &oi); err != nil {
+		return nil, fmt.Errorf("failed to get open interest: %w", err)
+	}
+
+	return &OpenInterestInfo{
+		Symbol:       oi.Symbol,
+		OpenInterest: parseFloat(oi.OpenInterest),
+		Time:         oi.Time,
+	}, nil
+}
+
+// GetLongShortRatio retrieves the latest global account long/short ratio of the symbol's pair
+func (d *DeliveryClient) GetLongShortRatio(ctx context.Context, symbol string, period string) (*LongShortRatioInfo, error) {
+	params := url.Values{}
+	params.Set("pair", deliveryPair(symbol))
+	params.Set("period", period)
+	params.Set("limit", "1")
+
+	var ratios []struct {
+		LongShortRatio string `json:"longShortRatio"`
+		LongAccount    string `json:"longAccount"`
+		ShortAccount   string `json:"shortAccount"`
+		Timestamp      int64  `json:"timestamp"`
+	}
+	if err := d.request(ctx, http.MethodGet, "/futures/data/globalLongShortAccountRatio", params, false, &ratios); err != nil {
+		return nil, fmt.Errorf("failed to get long/short ratio: %w", err)
+	}
+
+	if len(ratios) == 0 {
+		return nil, fmt.Errorf("no long/short ratio data for symbol %s", symbol)
+	}
+
+	ratio := ratios[len(ratios)-1]
+	return &LongShortRatioInfo{
+		Symbol:         symbol,
+		LongShortRatio: parseFloat(ratio.LongShortRatio),
+		LongAccount:    parseFloat(ratio.LongAccount),
+		ShortAccount:   parseFloat(ratio.ShortAccount),
+		Timestamp:      ratio.Timestamp,
+	}, nil
+}
+
+// deliveryPair returns the pair of a coin-margined symbol (BTCUSD_PERP -> BTCUSD)
+func deliveryPair(symbol string) string {
+	if i := strings.Index(symbol, "_"); i > 0 {
+		return symbol[:i]
+	}
+	return symbol
+}
+
+// GetOrderBook retrieves a REST snapshot of the top levels of the order book
+func (d *DeliveryClient) GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("limit", strconv.Itoa(limit))
+
+	var depth struct {
+		LastUpdateID int64       `json:"lastUpdateId"`
+		Time         int64       `json:"E"`
+		Bids         [][2]string `json:"bids"`
+		Asks         [][2]string `json:"asks"`
+	}
+	if err := d.request(ctx, http.MethodGet, "/dapi/v1/depth", params, false, &depth); err != nil {
+		return nil, fmt.Errorf("failed to get order book: %w", err)
+	}
+
+	levels := func(raw [][2]string) []PriceLevel {
+		result := make([]PriceLevel, 0, len(raw))
+		for _, level := range raw {
+			result = append(result, PriceLevel{Price: parseFloat(level[0]), Quantity: parseFloat(level[1])})
+		}
+		return result
+	}
+	return &OrderBook{
+		Symbol:       symbol,
+		LastUpdateID: depth.LastUpdateID,
+		EventTime:    depth.Time,
+		Bids:         levels(depth.Bids),
+		Asks:         levels(depth.Asks),
+	}, nil
+}
+
+// PlaceOrder places a new order; the quantity is a number of contracts
+func (d *DeliveryClient) PlaceOrder(ctx context.Context, order *OrderRequest) (*OrderResponse, error) {
+	service := d.client.NewCreateOrderService().
+		Symbol(order.Symbol).
+		Side(delivery.SideType(order.Side)).
+		Type(delivery.OrderType(order.Type)).
+		Quantity(fmt.Sprintf("%.8f", order.Quantity)).
+		NewOrderResponseType(delivery.NewOrderRespTypeRESULT)
+
+	if order.Price > 0 {
+		service = service.Price(fmt.Sprintf("%.8f", order.Price))
+	}
+	if order.StopPrice > 0 {
+		service = service.StopPrice(fmt.Sprintf("%.8f", order.StopPrice))
+	}
+	if order.TimeInForce != "" {
+		service = service.TimeInForce(delivery.TimeInForceType(order.TimeInForce))
+	}
+	if order.ReduceOnly {
+		service = service.ReduceOnly(order.ReduceOnly)
+	}
+	if order.ClosePosition {
+		service = service.ClosePosition(order.ClosePosition)
+	}
+	if order.PositionSide != "" {
+		service = service.PositionSide(delivery.PositionSideType(order.PositionSide))
+	}
+	if order.WorkingType != "" {
+		service = service.WorkingType(delivery.WorkingType(order.WorkingType))
+	}
+	if order.PriceProtect {
+		service = service.PriceProtect(order.PriceProtect)
+	}
+	if order.ActivationPrice > 0 {
+		service = service.ActivationPrice(fmt.Sprintf("%.8f", order.ActivationPrice))
+	}
+	if order.CallbackRate > 0 {
+		service = service.CallbackRate(fmt.Sprintf("%.1f", order.CallbackRate))
+	}
+	if order.NewClientOrderID != "" {
+		service = service.NewClientOrderID(order.NewClientOrderID)
+	}
+
+	var response *delivery.CreateOrderResponse
+	err := d.withTimeSync(ctx, func() error {
+		var err error
+		response, err = service.Do(ctx, d.signedOpts()...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to place order: %w", err)
+	}
+
+	return deliveryOrderResponse(response), nil
+}
+
+// ModifyOrder amends the quantity and price of an open LIMIT order
+func (d *DeliveryClient) ModifyOrder(ctx context.Context, order *ModifyOrderRequest) (*OrderResponse, error) {
+	params := url.Values{}
+	params.Set("symbol", order.Symbol)
+	params.Set("orderId", strconv.FormatInt(order.OrderID, 10))
+	params.Set("side", order.Side)
+	params.Set("quantity", fmt.Sprintf("%.8f", order.Quantity))
+	params.Set("price", fmt.Sprintf("%.8f", order.Price))
+
+	response := new(delivery.CreateOrderResponse)
+	err := d.withTimeSync(ctx, func() error {
+		return d.request(ctx, http.MethodPut, "/dapi/v1/order", params, true, response)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to modify order: %w", err)
+	}
+
+	return deliveryOrderResponse(response), nil
+}
+
+// deliveryOrderResponse converts a create or modify order response. CumQuote is the filled
+// value in USD: the base asset amount times the average price.
+func deliveryOrderResponse(response *delivery.CreateOrderResponse) *OrderResponse {
+	avgPrice := parseFloat(response.AvgPrice)
+	return &OrderResponse{
+		OrderID:       response.OrderID,
+		Symbol:        response.Symbol,
+		Status:        string(response.Status),
+		ClientOrderID: response.ClientOrderID,
+		Price:         parseFloat(response.Price),
+		AvgPrice:      avgPrice,
+		OrigQty:       parseFloat(response.OrigQuantity),
+		ExecutedQty:   parseFloat(response.ExecutedQuantity),
+		CumQuote:      parseFloat(response.CumBase) * avgPrice,
+		TimeInForce:   string(response.TimeInForce),
+		Type:          string(response.Type),
+		ReduceOnly:    response.ReduceOnly,
+		ClosePosition: response.ClosePosition,
+		Side:          string(response.Side),
+		PositionSide:  string(response.PositionSide),
+		StopPrice:     parseFloat(response.StopPrice),
+		WorkingType:   string(response.WorkingType),
+		PriceProtect:  response.PriceProtect,
+		UpdateTime:    response.UpdateTime,
+	}
+}
+
+// deliveryOrderInfo converts a queried order
+func deliveryOrderInfo(order *delivery.Order) *OrderInfo {
+	avgPrice := parseFloat(order.AvgPrice)
+	return &OrderInfo{
+		OrderID:       order.OrderID,
+		Symbol:        order.Symbol,
+		Status:        string(order.Status),
+		ClientOrderID: order.ClientOrderID,
+		Price:         parseFloat(order.Price),
+		AvgPrice:      avgPrice,
+		OrigQty:       parseFloat(order.OrigQuantity),
+		ExecutedQty:   parseFloat(order.ExecutedQuantity),
+		CumQuote:      parseFloat(order.CumBase) * avgPrice,
+		TimeInForce:   string(order.TimeInForce),
+		Type:          string(order.Type),
+		ReduceOnly:    order.ReduceOnly,
+		ClosePosition: order.ClosePosition,
+		Side:          string(order.Side),
+		PositionSide:  string(order.PositionSide),
+		StopPrice:     parseFloat(order.StopPrice),
+		WorkingType:   string(order.WorkingType),
+		PriceProtect:  order.PriceProtect,
+		Time:          order.Time,
+		UpdateTime:    order.UpdateTime,
+	}
+}
+
+// CancelOrder cancels an order
+func (d *DeliveryClient) CancelOrder(ctx context.Context, symbol string, orderID int64) error {
+	err := d.withTimeSync(ctx, func() error {
+		_, err := d.client.NewCancelOrderService().
+			Symbol(symbol).
+			OrderID(orderID).
+			Do(ctx, d.signedOpts()...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	return nil
+}
+
+// GetOrder retrieves order information
+func (d *DeliveryClient) GetOrder(ctx context.Context, symbol string, orderID int64) (*OrderInfo, error) {
+	order, err := d.client.NewGetOrderService().
+		Symbol(symbol).
+		OrderID(orderID).
+		Do(ctx, d.signedOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	return deliveryOrderInfo(order), nil
+}
+
+// GetOpenOrders retrieves open orders
+func (d *DeliveryClient) GetOpenOrders(ctx context.Context, symbol string) ([]*OrderInfo, error) {
+	service := d.client.NewListOpenOrdersService()
+	if symbol != "" {
+		service = service.Symbol(symbol)
+	}
+
+	orders, err := service.Do(ctx, d.signedOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	result := make([]*OrderInfo, 0, len(orders))
+	for _, order := range orders {
+		result = append(result, deliveryOrderInfo(order))
+	}
+
+	return result, nil
+}
+
+// GetOrderTrades retrieves the fills of an order; commission and realized PnL are in the
+// margin coin
+func (d *DeliveryClient) GetOrderTrades(ctx context.Context, symbol string, orderID int64) ([]*TradeInfo, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("orderId", strconv.FormatInt(orderID, 10))
+
+	var trades []struct {
+		Symbol          string `json:"symbol"`
+		ID              int64  `json:"id"`
+		OrderID         int64  `json:"orderId"`
+		Side            string `json:"side"`
+		Quantity        string `json:"qty"`
+		Price           string `json:"price"`
+		Commission      string `json:"commission"`
+		CommissionAsset string `json:"commissionAsset"`
+		Time            int64  `json:"time"`
+		Maker           bool   `json:"maker"`
+		RealizedPnL     string `json:"realizedPnl"`
+	}
+	if err := d.request(ctx, http.MethodGet, "/dapi/v1/userTrades", params, true, &trades); err != nil {
+		return nil, fmt.Errorf("failed to get order trades: %w", err)
+	}
+
+	result := make([]*TradeInfo, 0, len(trades))
+	for _, trade := range trades {
+		result = append(result, &TradeInfo{
+			Symbol:          trade.Symbol,
+			ID:              trade.ID,
+			OrderID:         trade.OrderID,
+			Side:            trade.Side,
+			Quantity:        parseFloat(trade.Quantity),
+			Price:           parseFloat(trade.Price),
+			Commission:      parseFloat(trade.Commission),
+			CommissionAsset: trade.CommissionAsset,
+			Time:            trade.Time,
+			IsMaker:         trade.Maker,
+			RealizedPnL:     parseFloat(trade.RealizedPnL),
+		})
+	}
+
+	return result, nil
+}
+
+// GetIncomeHistory retrieves income history entries in the margin coin; empty symbol and
+// income type match all and zero times leave the range open
+func (d *DeliveryClient) GetIncomeHistory(ctx context.Context, symbol string, incomeType string, startTime, endTime int64, limit int) ([]*IncomeInfo, error) {
+	params := url.Values{}
+	if symbol != "" {
+		params.Set("symbol", symbol)
+	}
+	if incomeType != "" {
+		params.Set("incomeType", incomeType)
+	}
+	if startTime > 0 {
+		params.Set("startTime", strconv.FormatInt(startTime, 10))
+	}
+	if endTime > 0 {
+		params.Set("endTime", strconv.FormatInt(endTime, 10))
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	var incomes []struct {
+		Symbol     string `json:"symbol"`
+		IncomeType string `json:"incomeType"`
+		Income     string `json:"income"`
+		Asset      string `json:"asset"`
+		Info       string `json:"info"`
+		Time       int64  `json:"time"`
+		TranID     int64  `json:"tranId"`
+		TradeID    string `json:"tradeId"`
+	}
+	if err := d.request(ctx, http.MethodGet, "/dapi/v1/income", params, true, &incomes); err != nil {
+		return nil, fmt.Errorf("failed to get income history: %w", err)
+	}
+
+	result := make([]*IncomeInfo, 0, len(incomes))
+	for _, income := range incomes {
+		result = append(result, &IncomeInfo{
+			Symbol:     income.Symbol,
+			IncomeType: income.IncomeType,
+			Income:     parseFloat(income.Income),
+			Asset:      income.Asset,
+			Info:       income.Info,
+			Time:       income.Time,
+			TranID:     income.TranID,
+			TradeID:    income.TradeID,
+		})
+	}
+
+	return result, nil
+}
+
+// SetLeverage sets leverage for a symbol
+func (d *DeliveryClient) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	_, err := d.client.NewChangeLeverageService().
+		Symbol(symbol).
+		Leverage(leverage).
+		Do(ctx, d.signedOpts()...)
+	if err != nil {
+		return fmt.Errorf("failed to set leverage: %w", err)
+	}
+
+	d.logger.Infof("Set leverage for %s to %d", symbol, leverage)
+	return nil
+}
+
+// ChangeMarginType changes margin type for a symbol
+func (d *DeliveryClient) ChangeMarginType(ctx context.Context, symbol string, marginType string) error {
+	err := d.client.NewChangeMarginTypeService().
+		Symbol(symbol).
+		MarginType(delivery.MarginType(marginType)).
+		Do(ctx, d.signedOpts()...)
+	if err != nil {
+		return fmt.Errorf("failed to change margin type: %w", err)
+	}
+
+	d.logger.Infof("Changed margin type for %s to %s", symbol, marginType)
+	return nil
+}
+
+// GetSymbolSettings retrieves the leverage and margin type currently set for every symbol
+func (d *DeliveryClient) GetSymbolSettings(ctx context.Context) ([]*SymbolSettings, error) {
+	positions, err := d.client.NewGetPositionRiskService().Do(ctx, d.signedOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get position risk: %w", err)
+	}
+
+	seen := make(map[string]bool, len(positions))
+	var result []*SymbolSettings
+	for _, pos := range positions {
+		// Hedge mode returns one entry per side; settings are per symbol
+		if seen[pos.Symbol] {
+			continue
+		}
+		seen[pos.Symbol] = true
+
+		leverage, _ := strconv.Atoi(pos.Leverage)
+		marginType := "CROSSED"
+		if strings.EqualFold(pos.MarginType, "isolated") {
+			marginType = "ISOLATED"
+		}
+
+		result = append(result, &SymbolSettings{
+			Symbol:     pos.Symbol,
+			Leverage:   leverage,
+			MarginType: marginType,
+		})
+	}
+
+	return result, nil
+}
+
+// GetADLQuantiles retrieves the auto-deleveraging quantile of every symbol with a position
+func (d *DeliveryClient) GetADLQuantiles(ctx context.Context) ([]*ADLQuantile, error) {
+	var quantiles []*ADLQuantile
+	err := d.withTimeSync(ctx, func() error {
+		return d.request(ctx, http.MethodGet, "/dapi/v1/adlQuantile", url.Values{}, true, &quantiles)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ADL quantiles: %w", err)
+	}
+	return quantiles, nil
+}
+
+// SetAutoCancel starts or refreshes the exchange's countdown that cancels all open orders of
+// the symbol unless refreshed again before it runs out. A zero countdown stops the timer.
+func (d *DeliveryClient) SetAutoCancel(ctx context.Context, symbol string, countdown time.Duration) error {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("countdownTime", strconv.FormatInt(countdown.Milliseconds(), 10))
+
+	err := d.withTimeSync(ctx, func() error {
+		return d.request(ctx, http.MethodPost, "/dapi/v1/countdownCancelAll", params, true, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set auto-cancel countdown: %w", err)
+	}
+	return nil
+}
+
+// GetPositionMode reports whether the account is in hedge (dual side) position mode
+func (d *DeliveryClient) GetPositionMode(ctx context.Context) (bool, error) {
+	mode, err := d.client.NewGetPositionModeService().Do(ctx, d.signedOpts()...)
+	if err != nil {
+		return false, fmt.Errorf("failed to get position mode: %w", err)
+	}
+	return mode.DualSidePosition, nil
+}
+
+// SetPositionMode switches between hedge (dual side) and one-way position mode
+func (d *DeliveryClient) SetPositionMode(ctx context.Context, dualSide bool) error {
+	if err := d.client.NewChangePositionModeService().DualSide(dualSide).Do(ctx, d.signedOpts()...); err != nil {
+		return fmt.Errorf("failed to change position mode: %w", err)
+	}
+
+	d.logger.Infof("Changed position mode (dual side: %t)", dualSide)
+	return nil
+}
+
+// GetExchangeInfo retrieves exchange information, including each contract's size
+func (d *DeliveryClient) GetExchangeInfo(ctx context.Context) (*ExchangeInfo, error) {
+	info, err := d.client.NewExchangeInfoService().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange info: %w", err)
+	}
+
+	symbols := make([]*SymbolInfo, 0, len(info.Symbols))
+	for i := range info.Symbols {
+		s := &info.Symbols[i]
+		symbol := &SymbolInfo{
+			Symbol:                s.Symbol,
+			Status:                s.ContractStatus,
+			ContractType:          s.ContractType,
+			DeliveryDate:          s.DeliveryDate,
+			BaseAsset:             s.BaseAsset,
+			QuoteAsset:            s.QuoteAsset,
+			MarginAsset:           s.MarginAsset,
+			ContractSize:          float64(s.ContractSize),
+			PricePrecision:        s.PricePrecision,
+			QuantityPrecision:     s.QuantityPrecision,
+			MaintMarginPercent:    parseFloat(s.MaintMarginPercent),
+			RequiredMarginPercent: parseFloat(s.RequiredMarginPercent),
+		}
+		if lot := s.LotSizeFilter(); lot != nil {
+			symbol.MinQty = parseFloat(lot.MinQuantity)
+			symbol.MaxQty = parseFloat(lot.MaxQuantity)
+			symbol.StepSize = parseFloat(lot.StepSize)
+		}
+		if price := s.PriceFilter(); price != nil {
+			symbol.MinPrice = parseFloat(price.MinPrice)
+			symbol.MaxPrice = parseFloat(price.MaxPrice)
+			symbol.TickSize = parseFloat(price.TickSize)
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	return &ExchangeInfo{
+		Timezone:   info.Timezone,
+		ServerTime: info.ServerTime,
+		Symbols:    symbols,
+	}, nil
+}
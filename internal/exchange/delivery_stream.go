@@ -0,0 +1,155 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/adshao/go-binance/v2/delivery"
+)
+
+// StartMarketDataStream starts market data stream (placeholder implementation)
+func (d *DeliveryClient) StartMarketDataStream(ctx context.Context, symbols []string, handler MarketDataHandler) error {
+	d.logger.Infof("Market data stream would be started for symbols: %v", symbols)
+	return nil
+}
+
+// StartUserDataStream is not implemented for coin-margined futures; account state is polled
+func (d *DeliveryClient) StartUserDataStream(ctx context.Context, handler UserDataHandler) error {
+	return fmt.Errorf("user data stream is not supported for coin-margined contracts")
+}
+
+// StartDepthStream is not implemented for coin-margined futures; use GetOrderBook
+func (d *DeliveryClient) StartDepthStream(ctx context.Context, symbols []string, handler DepthHandler) error {
+	return fmt.Errorf("depth stream is not supported for coin-margined contracts")
+}
+
+// StartMarkPriceStream streams mark prices of the given symbols, one connection per symbol
+func (d *DeliveryClient) StartMarkPriceStream(ctx context.Context, symbols []string, handler MarkPriceHandler) error {
+	if len(symbols) == 0 {
+		return fmt.Errorf("no symbols given for mark price stream")
+	}
+
+	onEvent := func(event *delivery.WsMarkPriceEvent) {
+		handler.OnMarkPrice(&MarkPriceUpdate{
+			Symbol:          event.Symbol,
+			MarkPrice:       parseFloat(event.MarkPrice),
+			FundingRate:     parseFloat(event.FundingRate),
+			NextFundingTime: event.NextFundingTime,
+			Time:            event.Time,
+		})
+	}
+
+	for _, symbol := range symbols {
+		symbol := symbol
+		connect := func() (chan struct{}, chan struct{}, error) {
+			return delivery.WsMarkPriceServe(symbol, onEvent, handler.OnError)
+		}
+		go runStream(ctx, d.logger, "mark price "+symbol, connect, func(bool) {}, nil)
+	}
+
+	return nil
+}
+
+// StartKlineStream streams candles of the given interval and forwards each one when it closes.
+// The kline volume is in contracts and its quote volume in the base asset, which is valued at
+// the close price.
+func (d *DeliveryClient) StartKlineStream(ctx context.Context, symbols []string, interval string, handler KlineHandler) error {
+	if len(symbols) == 0 {
+		return fmt.Errorf("no symbols given for kline stream")
+	}
+
+	onEvent := func(event *delivery.WsKlineEvent) {
+		k := event.Kline
+		if !k.IsFinal {
+			return
+		}
+		closePrice := parseFloat(k.Close)
+		handler.OnKlineClosed(k.Symbol, &KlineData{
+			OpenTime:                 k.StartTime,
+			Open:                     parseFloat(k.Open),
+			High:                     parseFloat(k.High),
+			Low:                      parseFloat(k.Low),
+			Close:                    closePrice,
+			Volume:                   parseFloat(k.Volume),
+			CloseTime:                k.EndTime,
+			QuoteAssetVolume:         parseFloat(k.QuoteVolume) * closePrice,
+			TradeCount:               k.TradeNum,
+			TakerBuyBaseAssetVolume:  parseFloat(k.ActiveBuyVolume),
+			TakerBuyQuoteAssetVolume: parseFloat(k.ActiveBuyQuoteVolume) * closePrice,
+		})
+	}
+
+	for _, symbol := range symbols {
+		symbol := symbol
+		connect := func() (chan struct{}, chan struct{}, error) {
+			return delivery.WsKlineServe(symbol, interval, onEvent, handler.OnError)
+		}
+		go runStream(ctx, d.logger, "kline "+symbol, connect, func(bool) {}, nil)
+	}
+
+	return nil
+}
+
+// StartAggTradeStream streams aggregate trades of the given symbols; quantities are contracts
+func (d *DeliveryClient) StartAggTradeStream(ctx context.Context, symbols []string, handler AggTradeHandler) error {
+	if len(symbols) == 0 {
+		return fmt.Errorf("no symbols given for aggregate trade stream")
+	}
+
+	onEvent := func(event *delivery.WsAggTradeEvent) {
+		handler.OnAggTrade(&AggTrade{
+			Symbol:     event.Symbol,
+			ID:         event.AggregateTradeID,
+			Price:      parseFloat(event.Price),
+			Quantity:   parseFloat(event.Quantity),
+			BuyerMaker: event.Maker,
+			Time:       event.TradeTime,
+		})
+	}
+
+	for _, symbol := range symbols {
+		symbol := symbol
+		connect := func() (chan struct{}, chan struct{}, error) {
+			return delivery.WsAggTradeServe(symbol, onEvent, handler.OnError)
+		}
+		go runStream(ctx, d.logger, "aggregate trade "+symbol, connect, func(bool) {}, nil)
+	}
+
+	return nil
+}
+
+// StartLiquidationStream streams liquidation orders of the given symbols from the all-market
+// stream
+func (d *DeliveryClient) StartLiquidationStream(ctx context.Context, symbols []string, handler LiquidationHandler) error {
+	if len(symbols) == 0 {
+		return fmt.Errorf("no symbols given for liquidation stream")
+	}
+
+	wanted := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		wanted[symbol] = true
+	}
+
+	onEvent := func(event *delivery.WsLiquidationOrderEvent) {
+		order := event.LiquidationOrder
+		if !wanted[order.Symbol] {
+			return
+		}
+		handler.OnLiquidation(&Liquidation{
+			Symbol:   order.Symbol,
+			Side:     string(order.Side),
+			Price:    parseFloat(order.Price),
+			AvgPrice: parseFloat(order.AvgPrice),
+			Quantity: parseFloat(order.AccumulatedFilledQty),
+			Time:     order.TradeTime,
+		})
+	}
+
+	connect := func() (chan struct{}, chan struct{}, error) {
+		return delivery.WsAllLiquidationOrderServe(onEvent, handler.OnError)
+	}
+
+	go runStream(ctx, d.logger, "liquidation", connect, func(bool) {}, nil)
+
+	return nil
+}
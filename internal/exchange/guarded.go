@@ -0,0 +1,344 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrCircuitOpen is returned without calling the exchange while the circuit breaker is open
+var ErrCircuitOpen = errors.New("exchange circuit breaker open")
+
+// CircuitState is the state of the exchange circuit breaker
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // Requests flow normally
+	CircuitOpen     CircuitState = "open"      // Requests fail fast until the open period ends
+	CircuitHalfOpen CircuitState = "half_open" // One probe request decides whether to close again
+)
+
+// Binance API errors that mean the exchange did not process the request
+var unavailableCodes = map[int64]bool{
+	-1001: true, // Internal error; unable to process the request
+	-1003: true, // Too many requests
+	-1007: true, // Timeout waiting for response from backend server
+	-1008: true, // Server is currently overloaded
+}
+
+// GuardedClient wraps a Client with a per-call timeout and a circuit breaker. The breaker
+// opens after FailureThreshold consecutive failures, fails every call fast for OpenSeconds,
+// then lets a single probe through: success closes it, failure opens it again. Errors the
+// exchange returns for the request itself, such as insufficient margin, are not failures.
+// Streams are long-lived and pass through unguarded.
+type GuardedClient struct {
+	Client
+
+	timeout   time.Duration
+	threshold int
+	openFor   time.Duration
+	logger    *logrus.Logger
+
+	mu            sync.Mutex
+	state         CircuitState
+	failures      int
+	openedAt      time.Time
+	probing       bool
+	onStateChange func(state CircuitState, err error)
+}
+
+// NewGuardedClient wraps the client with the configured timeout and circuit breaker
+func NewGuardedClient(client Client, cfg config.ExchangeConfig, logger *logrus.Logger) *GuardedClient {
+	return &GuardedClient{
+		Client:    client,
+		timeout:   time.Duration(cfg.RequestTimeoutMs) * time.Millisecond,
+		threshold: cfg.CircuitBreaker.FailureThreshold,
+		openFor:   time.Duration(cfg.CircuitBreaker.OpenSeconds) * time.Second,
+		logger:    logger,
+		state:     CircuitClosed,
+	}
+}
+
+// OnStateChange registers a function called after every breaker state change. It runs on
+// the goroutine of the request that caused the change and must not block.
+func (g *GuardedClient) OnStateChange(fn func(state CircuitState, err error)) {
+	g.mu.Lock()
+	g.onStateChange = fn
+	g.mu.Unlock()
+}
+
+// State returns the current breaker state
+func (g *GuardedClient) State() CircuitState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state == CircuitOpen && time.Since(g.openedAt) >= g.openFor {
+		return CircuitHalfOpen
+	}
+	return g.state
+}
+
+// allow reports whether a request may be sent and whether it is the half-open probe
+func (g *GuardedClient) allow() (bool, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch g.state {
+	case CircuitClosed:
+		return true, false
+	case CircuitOpen:
+		if time.Since(g.openedAt) < g.openFor {
+			return false, false
+		}
+		g.state = CircuitHalfOpen
+		fallthrough
+	default:
+		if g.probing {
+			return false, false
+		}
+		g.probing = true
+		return true, true
+	}
+}
+
+// record updates the breaker with the outcome of a request
+func (g *GuardedClient) record(ctx context.Context, probe bool, err error) {
+	failed := isUnavailable(ctx, err)
+
+	g.mu.Lock()
+	if probe {
+		g.probing = false
+	}
+
+	previous := g.state
+	switch {
+	case !failed:
+		g.failures = 0
+		if probe || g.state == CircuitHalfOpen {
+			g.state = CircuitClosed
+		}
+	case probe:
+		g.state = CircuitOpen
+		g.openedAt = time.Now()
+	default:
+		g.failures++
+		if g.state == CircuitClosed && g.threshold > 0 && g.failures >= g.threshold {
+			g.state = CircuitOpen
+			g.openedAt = time.Now()
+		}
+	}
+	state, notify := g.state, g.onStateChange
+	g.mu.Unlock()
+
+	if state == previous {
+		return
+	}
+	if previous == CircuitHalfOpen && state == CircuitOpen {
+		g.logger.Warnf("Exchange circuit breaker probe failed, staying open: %v", err)
+		return
+	}
+	switch state {
+	case CircuitOpen:
+		g.logger.Errorf("Exchange circuit breaker opened for %s: %v", g.openFor, err)
+	case CircuitClosed:
+		g.logger.Info("Exchange circuit breaker closed, requests resumed")
+	}
+	if notify != nil {
+		notify(state, err)
+	}
+}
+
+// isUnavailable reports whether err means the exchange could not be reached or did not
+// process the request. Cancellation by the caller is not the exchange's fault.
+func isUnavailable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() == context.Canceled {
+		return false
+	}
+
+	var apiErr *common.APIError
+	if errors.As(err, &apiErr) {
+		return unavailableCodes[apiErr.Code]
+	}
+	return true
+}
+
+// guardCall runs one exchange request under the timeout and circuit breaker
+func guardCall[T any](g *GuardedClient, ctx context.Context, name string, call func(context.Context) (T, error)) (T, error) {
+	var zero T
+
+	allowed, probe := g.allow()
+	if !allowed {
+		return zero, fmt.Errorf("%s: %w", name, ErrCircuitOpen)
+	}
+
+	callCtx := ctx
+	if g.timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, g.timeout)
+		defer cancel()
+	}
+
+	result, err := call(callCtx)
+	if err != nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		err = fmt.Errorf("%s timed out after %s: %w", name, g.timeout, err)
+	}
+	g.record(ctx, probe, err)
+	return result, err
+}
+
+// guardErr is guardCall for requests that return only an error
+func guardErr(g *GuardedClient, ctx context.Context, name string, call func(context.Context) error) error {
+	_, err := guardCall(g, ctx, name, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, call(ctx)
+	})
+	return err
+}
+
+func (g *GuardedClient) GetAccountInfo(ctx context.Context) (*AccountInfo, error) {
+	return guardCall(g, ctx, "GetAccountInfo", g.Client.GetAccountInfo)
+}
+
+func (g *GuardedClient) GetPositions(ctx context.Context) ([]*PositionInfo, error) {
+	return guardCall(g, ctx, "GetPositions", g.Client.GetPositions)
+}
+
+func (g *GuardedClient) GetBalance(ctx context.Context) ([]*BalanceInfo, error) {
+	return guardCall(g, ctx, "GetBalance", g.Client.GetBalance)
+}
+
+func (g *GuardedClient) GetSymbolPrice(ctx context.Context, symbol string) (float64, error) {
+	return guardCall(g, ctx, "GetSymbolPrice", func(ctx context.Context) (float64, error) {
+		return g.Client.GetSymbolPrice(ctx, symbol)
+	})
+}
+
+func (g *GuardedClient) GetSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	return guardCall(g, ctx, "GetSymbolInfo", func(ctx context.Context) (*SymbolInfo, error) {
+		return g.Client.GetSymbolInfo(ctx, symbol)
+	})
+}
+
+func (g *GuardedClient) GetKlines(ctx context.Context, symbol string, interval string, limit int) ([]*KlineData, error) {
+	return guardCall(g, ctx, "GetKlines", func(ctx context.Context) ([]*KlineData, error) {
+		return g.Client.GetKlines(ctx, symbol, interval, limit)
+	})
+}
+
+func (g *GuardedClient) GetPremiumIndex(ctx context.Context, symbol string) (*PremiumIndexInfo, error) {
+	return guardCall(g, ctx, "GetPremiumIndex", func(ctx context.Context) (*PremiumIndexInfo, error) {
+		return g.Client.GetPremiumIndex(ctx, symbol)
+	})
+}
+
+func (g *GuardedClient) GetOpenInterest(ctx context.Context, symbol string) (*OpenInterestInfo, error) {
+	return guardCall(g, ctx, "GetOpenInterest", func(ctx context.Context) (*OpenInterestInfo, error) {
+		return g.Client.GetOpenInterest(ctx, symbol)
+	})
+}
+
+func (g *GuardedClient) GetLongShortRatio(ctx context.Context, symbol string, period string) (*LongShortRatioInfo, error) {
+	return guardCall(g, ctx, "GetLongShortRatio", func(ctx context.Context) (*LongShortRatioInfo, error) {
+		return g.Client.GetLongShortRatio(ctx, symbol, period)
+	})
+}
+
+func (g *GuardedClient) GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	return guardCall(g, ctx, "GetOrderBook", func(ctx context.Context) (*OrderBook, error) {
+		return g.Client.GetOrderBook(ctx, symbol, limit)
+	})
+}
+
+func (g *GuardedClient) GetTicker24h(ctx context.Context, symbol string) (*Ticker24h, error) {
+	return guardCall(g, ctx, "GetTicker24h", func(ctx context.Context) (*Ticker24h, error) {
+		return g.Client.GetTicker24h(ctx, symbol)
+	})
+}
+
+func (g *GuardedClient) PlaceOrder(ctx context.Context, order *OrderRequest) (*OrderResponse, error) {
+	return guardCall(g, ctx, "PlaceOrder", func(ctx context.Context) (*OrderResponse, error) {
+		return g.Client.PlaceOrder(ctx, order)
+	})
+}
+
+func (g *GuardedClient) ModifyOrder(ctx context.Context, order *ModifyOrderRequest) (*OrderResponse, error) {
+	return guardCall(g, ctx, "ModifyOrder", func(ctx context.Context) (*OrderResponse, error) {
+		return g.Client.ModifyOrder(ctx, order)
+	})
+}
+
+func (g *GuardedClient) CancelOrder(ctx context.Context, symbol string, orderID int64) error {
+	return guardErr(g, ctx, "CancelOrder", func(ctx context.Context) error {
+		return g.Client.CancelOrder(ctx, symbol, orderID)
+	})
+}
+
+func (g *GuardedClient) GetOrder(ctx context.Context, symbol string, orderID int64) (*OrderInfo, error) {
+	return guardCall(g, ctx, "GetOrder", func(ctx context.Context) (*OrderInfo, error) {
+		return g.Client.GetOrder(ctx, symbol, orderID)
+	})
+}
+
+func (g *GuardedClient) GetOpenOrders(ctx context.Context, symbol string) ([]*OrderInfo, error) {
+	return guardCall(g, ctx, "GetOpenOrders", func(ctx context.Context) ([]*OrderInfo, error) {
+		return g.Client.GetOpenOrders(ctx, symbol)
+	})
+}
+
+func (g *GuardedClient) GetOrderTrades(ctx context.Context, symbol string, orderID int64) ([]*TradeInfo, error) {
+	return guardCall(g, ctx, "GetOrderTrades", func(ctx context.Context) ([]*TradeInfo, error) {
+		return g.Client.GetOrderTrades(ctx, symbol, orderID)
+	})
+}
+
+func (g *GuardedClient) GetIncomeHistory(ctx context.Context, symbol string, incomeType string, startTime, endTime int64, limit int) ([]*IncomeInfo, error) {
+	return guardCall(g, ctx, "GetIncomeHistory", func(ctx context.Context) ([]*IncomeInfo, error) {
+		return g.Client.GetIncomeHistory(ctx, symbol, incomeType, startTime, endTime, limit)
+	})
+}
+
+func (g *GuardedClient) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	return guardErr(g, ctx, "SetLeverage", func(ctx context.Context) error {
+		return g.Client.SetLeverage(ctx, symbol, leverage)
+	})
+}
+
+func (g *GuardedClient) ChangeMarginType(ctx context.Context, symbol string, marginType string) error {
+	return guardErr(g, ctx, "ChangeMarginType", func(ctx context.Context) error {
+		return g.Client.ChangeMarginType(ctx, symbol, marginType)
+	})
+}
+
+func (g *GuardedClient) GetADLQuantiles(ctx context.Context) ([]*ADLQuantile, error) {
+	return guardCall(g, ctx, "GetADLQuantiles", g.Client.GetADLQuantiles)
+}
+
+func (g *GuardedClient) SetAutoCancel(ctx context.Context, symbol string, countdown time.Duration) error {
+	return guardErr(g, ctx, "SetAutoCancel", func(ctx context.Context) error {
+		return g.Client.SetAutoCancel(ctx, symbol, countdown)
+	})
+}
+
+func (g *GuardedClient) GetSymbolSettings(ctx context.Context) ([]*SymbolSettings, error) {
+	return guardCall(g, ctx, "GetSymbolSettings", g.Client.GetSymbolSettings)
+}
+
+func (g *GuardedClient) GetPositionMode(ctx context.Context) (bool, error) {
+	return guardCall(g, ctx, "GetPositionMode", g.Client.GetPositionMode)
+}
+
+func (g *GuardedClient) SetPositionMode(ctx context.Context, dualSide bool) error {
+	return guardErr(g, ctx, "SetPositionMode", func(ctx context.Context) error {
+		return g.Client.SetPositionMode(ctx, dualSide)
+	})
+}
+
+func (g *GuardedClient) GetExchangeInfo(ctx context.Context) (*ExchangeInfo, error) {
+	return guardCall(g, ctx, "GetExchangeInfo", g.Client.GetExchangeInfo)
+}
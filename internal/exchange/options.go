@@ -0,0 +1,202 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OptionsVolatility summarizes an underlying's option market: implied volatility near a target
+// tenor, the put/call skew around it and the put/call open interest ratio. Volatilities are
+// annualized percentages.
+type OptionsVolatility struct {
+	Underlying      string    `json:"underlying"`
+	UnderlyingPrice float64   `json:"underlying_price"`
+	Expiry          time.Time `json:"expiry"`      // Expiry the volatility and skew are read from
+	ATMVolatility   float64   `json:"atm_iv"`      // Implied volatility of the strike nearest the underlying
+	PutVolatility   float64   `json:"put_iv"`      // OTM put at the skew moneyness
+	CallVolatility  float64   `json:"call_iv"`     // OTM call at the skew moneyness
+	Skew            float64   `json:"skew"`        // PutVolatility - CallVolatility; positive when puts are bid
+	PutCallRatio    float64   `json:"put_call_oi"` // Put over call open interest across all expiries
+	Time            time.Time `json:"time"`
+}
+
+// OptionsVenue is a read-only view of an options exchange
+type OptionsVenue interface {
+	Name() string
+	GetVolatility(ctx context.Context, underlying string, tenor time.Duration, moneyness float64) (*OptionsVolatility, error)
+}
+
+// NewOptionsVenue returns the adapter for an options venue name
+func NewOptionsVenue(name string, timeout time.Duration) (OptionsVenue, error) {
+	httpClient := &http.Client{Timeout: timeout}
+
+	switch name {
+	case "deribit":
+		return &DeribitVenue{httpClient: httpClient}, nil
+	default:
+		return nil, fmt.Errorf("unsupported options venue: %s", name)
+	}
+}
+
+// DeribitVenue reads option book summaries from the Deribit public API
+type DeribitVenue struct {
+	httpClient *http.Client
+}
+
+// Name returns the venue name
+func (d *DeribitVenue) Name() string {
+	return "deribit"
+}
+
+type deribitBookSummaryResponse struct {
+	Result []struct {
+		InstrumentName  string  `json:"instrument_name"` // BTC-27DEC24-60000-C
+		MarkIV          float64 `json:"mark_iv"`
+		OpenInterest    float64 `json:"open_interest"`
+		UnderlyingPrice float64 `json:"underlying_price"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// deribitOption is a parsed option of the book summary
+type deribitOption struct {
+	expiry          time.Time
+	strike          float64
+	put             bool
+	iv              float64
+	underlyingPrice float64
+}
+
+// GetVolatility reads the expiry closest to the tenor. The ATM volatility is the mean of the
+// call and put at the strike nearest the underlying; the skew compares the put and the call
+// whose strikes are nearest moneyness (a fraction) below and above it.
+func (d *DeribitVenue) GetVolatility(ctx context.Context, underlying string, tenor time.Duration, moneyness float64) (*OptionsVolatility, error) {
+	query := url.Values{}
+	query.Set("currency", underlying)
+	query.Set("kind", "option")
+
+	var data deribitBookSummaryResponse
+	if err := getJSON(ctx, d.httpClient, "https://www.deribit.com/api/v2/public/get_book_summary_by_currency?"+query.Encode(), &data); err != nil {
+		return nil, fmt.Errorf("failed to get deribit option summary: %w", err)
+	}
+	if data.Error != nil {
+		return nil, fmt.Errorf("failed to get deribit option summary: %s", data.Error.Message)
+	}
+
+	now := time.Now()
+	var options []deribitOption
+	var putOI, callOI float64
+	for _, summary := range data.Result {
+		option, ok := parseDeribitOption(summary.InstrumentName)
+		if !ok || !option.expiry.After(now) {
+			continue
+		}
+		if option.put {
+			putOI += summary.OpenInterest
+		} else {
+			callOI += summary.OpenInterest
+		}
+		if summary.MarkIV <= 0 || summary.UnderlyingPrice <= 0 {
+			continue
+		}
+		option.iv = summary.MarkIV
+		option.underlyingPrice = summary.UnderlyingPrice
+		options = append(options, option)
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("no deribit options for %s", underlying)
+	}
+
+	// Pick the expiry nearest the target tenor
+	target := now.Add(tenor)
+	expiry := options[0].expiry
+	for _, option := range options {
+		if math.Abs(float64(option.expiry.Sub(target))) < math.Abs(float64(expiry.Sub(target))) {
+			expiry = option.expiry
+		}
+	}
+
+	var chain []deribitOption
+	for _, option := range options {
+		if option.expiry.Equal(expiry) {
+			chain = append(chain, option)
+		}
+	}
+	spot := chain[0].underlyingPrice
+
+	result := &OptionsVolatility{
+		Underlying:      underlying,
+		UnderlyingPrice: spot,
+		Expiry:          expiry,
+		Time:            now,
+	}
+	atmPut, atmCall := nearestIV(chain, spot, true), nearestIV(chain, spot, false)
+	if atmPut > 0 && atmCall > 0 {
+		result.ATMVolatility = (atmPut + atmCall) / 2
+	} else {
+		result.ATMVolatility = math.Max(atmPut, atmCall)
+	}
+	result.PutVolatility = nearestIV(chain, spot*(1-moneyness), true)
+	result.CallVolatility = nearestIV(chain, spot*(1+moneyness), false)
+	if result.PutVolatility > 0 && result.CallVolatility > 0 {
+		result.Skew = result.PutVolatility - result.CallVolatility
+	}
+	if callOI > 0 {
+		result.PutCallRatio = putOI / callOI
+	}
+
+	return result, nil
+}
+
+// nearestIV returns the implied volatility of the put or call whose strike is nearest the
+// given strike, or 0 when the chain has none
+func nearestIV(chain []deribitOption, strike float64, put bool) float64 {
+	best, iv := math.Inf(1), 0.0
+	for _, option := range chain {
+		if option.put != put {
+			continue
+		}
+		if distance := math.Abs(option.strike - strike); distance < best {
+			best, iv = distance, option.iv
+		}
+	}
+	return iv
+}
+
+// parseDeribitOption parses an instrument name such as BTC-27DEC24-60000-C. Options expire at
+// 08:00 UTC.
+func parseDeribitOption(name string) (deribitOption, bool) {
+	parts := strings.Split(name, "-")
+	if len(parts) != 4 {
+		return deribitOption{}, false
+	}
+
+	expiry, err := time.Parse("2Jan06", parts[1])
+	if err != nil {
+		return deribitOption{}, false
+	}
+	strike, err := strconv.ParseFloat(strings.ReplaceAll(parts[2], "d", "."), 64)
+	if err != nil {
+		return deribitOption{}, false
+	}
+
+	var put bool
+	switch parts[3] {
+	case "P":
+		put = true
+	case "C":
+	default:
+		return deribitOption{}, false
+	}
+
+	return deribitOption{expiry: expiry.Add(8 * time.Hour), strike: strike, put: put}, true
+}
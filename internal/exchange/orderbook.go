@@ -0,0 +1,157 @@
+package exchange
+
+import (
+	"sort"
+)
+
+// PriceLevel represents a single order book level
+type PriceLevel struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity"`
+}
+
+// OrderBook is a point-in-time copy of a locally maintained order book
+type OrderBook struct {
+	Symbol       string       `json:"symbol"`
+	LastUpdateID int64        `json:"last_update_id"`
+	EventTime    int64        `json:"event_time"`
+	Bids         []PriceLevel `json:"bids"` // Sorted by price descending
+	Asks         []PriceLevel `json:"asks"` // Sorted by price ascending
+}
+
+// BestBid returns the highest bid level
+func (o *OrderBook) BestBid() (PriceLevel, bool) {
+	if len(o.Bids) == 0 {
+		return PriceLevel{}, false
+	}
+	return o.Bids[0], true
+}
+
+// BestAsk returns the lowest ask level
+func (o *OrderBook) BestAsk() (PriceLevel, bool) {
+	if len(o.Asks) == 0 {
+		return PriceLevel{}, false
+	}
+	return o.Asks[0], true
+}
+
+// MidPrice returns the midpoint between best bid and best ask
+func (o *OrderBook) MidPrice() float64 {
+	bid, okBid := o.BestBid()
+	ask, okAsk := o.BestAsk()
+	if !okBid || !okAsk {
+		return 0
+	}
+	return (bid.Price + ask.Price) / 2
+}
+
+// SpreadBps returns the bid/ask spread in basis points of the mid price
+func (o *OrderBook) SpreadBps() float64 {
+	bid, okBid := o.BestBid()
+	ask, okAsk := o.BestAsk()
+	mid := o.MidPrice()
+	if !okBid || !okAsk || mid == 0 {
+		return 0
+	}
+	return (ask.Price - bid.Price) / mid * 10000
+}
+
+// ExpectedFillPrice walks the book to estimate the average price of a market order of the
+// given quantity. BUY orders consume asks and SELL orders consume bids. It returns false when
+// the visible book is too thin to fill the quantity.
+func (o *OrderBook) ExpectedFillPrice(side string, quantity float64) (float64, bool) {
+	if quantity <= 0 {
+		return 0, false
+	}
+
+	levels := o.Asks
+	if side == "SELL" {
+		levels = o.Bids
+	}
+
+	remaining := quantity
+	notional := 0.0
+	for _, level := range levels {
+		fill := level.Quantity
+		if fill > remaining {
+			fill = remaining
+		}
+		notional += fill * level.Price
+		remaining -= fill
+		if remaining <= 0 {
+			return notional / quantity, true
+		}
+	}
+
+	return 0, false
+}
+
+// localOrderBook maintains the full book state for a symbol from snapshot and diff events
+type localOrderBook struct {
+	symbol       string
+	lastUpdateID int64
+	eventTime    int64
+	bids         map[float64]float64
+	asks         map[float64]float64
+}
+
+func newLocalOrderBook(symbol string, lastUpdateID int64, bids, asks []PriceLevel) *localOrderBook {
+	book := &localOrderBook{
+		symbol:       symbol,
+		lastUpdateID: lastUpdateID,
+		bids:         make(map[float64]float64, len(bids)),
+		asks:         make(map[float64]float64, len(asks)),
+	}
+	book.apply(bids, asks)
+	return book
+}
+
+// apply merges level updates into the book; a zero quantity removes the level
+func (l *localOrderBook) apply(bids, asks []PriceLevel) {
+	for _, level := range bids {
+		if level.Quantity == 0 {
+			delete(l.bids, level.Price)
+		} else {
+			l.bids[level.Price] = level.Quantity
+		}
+	}
+	for _, level := range asks {
+		if level.Quantity == 0 {
+			delete(l.asks, level.Price)
+		} else {
+			l.asks[level.Price] = level.Quantity
+		}
+	}
+}
+
+// snapshot returns a sorted copy of the top levels of the book
+func (l *localOrderBook) snapshot(levels int) *OrderBook {
+	book := &OrderBook{
+		Symbol:       l.symbol,
+		LastUpdateID: l.lastUpdateID,
+		EventTime:    l.eventTime,
+		Bids:         make([]PriceLevel, 0, len(l.bids)),
+		Asks:         make([]PriceLevel, 0, len(l.asks)),
+	}
+
+	for price, qty := range l.bids {
+		book.Bids = append(book.Bids, PriceLevel{Price: price, Quantity: qty})
+	}
+	for price, qty := range l.asks {
+		book.Asks = append(book.Asks, PriceLevel{Price: price, Quantity: qty})
+	}
+
+	sort.Slice(book.Bids, func(i, j int) bool { return book.Bids[i].Price > book.Bids[j].Price })
+	sort.Slice(book.Asks, func(i, j int) bool { return book.Asks[i].Price < book.Asks[j].Price })
+
+	if levels > 0 {
+		if len(book.Bids) > levels {
+			book.Bids = book.Bids[:levels]
+		}
+		if len(book.Asks) > levels {
+			book.Asks = book.Asks[:levels]
+		}
+	}
+
+	return book
+}
@@ -0,0 +1,433 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Kinds of recorded entries
+const (
+	recordCall  = "call"  // A REST request and its response
+	recordEvent = "event" // A stream event delivered to a handler
+)
+
+// recordEntry is one line of a session recording. Args holds the request arguments or the
+// handler method's arguments as a JSON array.
+type recordEntry struct {
+	Seq    int64           `json:"seq"`
+	Time   int64           `json:"time"` // Unix ms
+	Kind   string          `json:"kind"`
+	Stream string          `json:"stream,omitempty"`
+	Method string          `json:"method"`
+	Args   json.RawMessage `json:"args,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// RecordingClient wraps a Client and appends every REST response and stream event to a JSON
+// lines file so the session can be replayed with ReplayClient
+type RecordingClient struct {
+	Client
+
+	logger *logrus.Logger
+
+	mu      sync.Mutex
+	encoder *json.Encoder // Writes straight to the file so a crash loses at most one line
+	seq     int64
+}
+
+// NewRecordingClient creates the recording file and wraps the client
+func NewRecordingClient(client Client, path string, logger *logrus.Logger) (*RecordingClient, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording file: %w", err)
+	}
+
+	logger.Infof("Recording exchange session to %s", path)
+	return &RecordingClient{
+		Client:  client,
+		logger:  logger,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+// write appends an entry; failures are logged so recording never breaks trading
+func (r *RecordingClient) write(entry *recordEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq++
+	entry.Seq = r.seq
+	entry.Time = time.Now().UnixMilli()
+	if err := r.encoder.Encode(entry); err != nil {
+		r.logger.Errorf("Failed to record %s %s: %v", entry.Kind, entry.Method, err)
+	}
+}
+
+func (r *RecordingClient) writeEvent(stream, method string, args ...interface{}) {
+	entry := &recordEntry{Kind: recordEvent, Stream: stream, Method: method}
+	if len(args) > 0 {
+		entry.Args, _ = json.Marshal(args)
+	}
+	r.write(entry)
+}
+
+// recorded runs one request and records its arguments and outcome
+func recorded[T any](r *RecordingClient, method string, args []interface{}, call func() (T, error)) (T, error) {
+	result, err := call()
+
+	entry := &recordEntry{Kind: recordCall, Method: method}
+	if len(args) > 0 {
+		entry.Args, _ = json.Marshal(args)
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Result, _ = json.Marshal(result)
+	}
+	r.write(entry)
+	return result, err
+}
+
+// recordedErr is recorded for requests that return only an error
+func recordedErr(r *RecordingClient, method string, args []interface{}, call func() error) error {
+	_, err := recorded(r, method, args, func() (struct{}, error) {
+		return struct{}{}, call()
+	})
+	return err
+}
+
+func (r *RecordingClient) GetAccountInfo(ctx context.Context) (*AccountInfo, error) {
+	return recorded(r, "GetAccountInfo", nil, func() (*AccountInfo, error) {
+		return r.Client.GetAccountInfo(ctx)
+	})
+}
+
+func (r *RecordingClient) GetPositions(ctx context.Context) ([]*PositionInfo, error) {
+	return recorded(r, "GetPositions", nil, func() ([]*PositionInfo, error) {
+		return r.Client.GetPositions(ctx)
+	})
+}
+
+func (r *RecordingClient) GetBalance(ctx context.Context) ([]*BalanceInfo, error) {
+	return recorded(r, "GetBalance", nil, func() ([]*BalanceInfo, error) {
+		return r.Client.GetBalance(ctx)
+	})
+}
+
+func (r *RecordingClient) GetSymbolPrice(ctx context.Context, symbol string) (float64, error) {
+	return recorded(r, "GetSymbolPrice", []interface{}{symbol}, func() (float64, error) {
+		return r.Client.GetSymbolPrice(ctx, symbol)
+	})
+}
+
+func (r *RecordingClient) GetSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	return recorded(r, "GetSymbolInfo", []interface{}{symbol}, func() (*SymbolInfo, error) {
+		return r.Client.GetSymbolInfo(ctx, symbol)
+	})
+}
+
+func (r *RecordingClient) GetKlines(ctx context.Context, symbol string, interval string, limit int) ([]*KlineData, error) {
+	return recorded(r, "GetKlines", []interface{}{symbol, interval, limit}, func() ([]*KlineData, error) {
+		return r.Client.GetKlines(ctx, symbol, interval, limit)
+	})
+}
+
+func (r *RecordingClient) GetPremiumIndex(ctx context.Context, symbol string) (*PremiumIndexInfo, error) {
+	return recorded(r, "GetPremiumIndex", []interface{}{symbol}, func() (*PremiumIndexInfo, error) {
+		return r.Client.GetPremiumIndex(ctx, symbol)
+	})
+}
+
+func (r *RecordingClient) GetOpenInterest(ctx context.Context, symbol string) (*OpenInterestInfo, error) {
+	return recorded(r, "GetOpenInterest", []interface{}{symbol}, func() (*OpenInterestInfo, error) {
+		return r.Client.GetOpenInterest(ctx, symbol)
+	})
+}
+
+func (r *RecordingClient) GetLongShortRatio(ctx context.Context, symbol string, period string) (*LongShortRatioInfo, error) {
+	return recorded(r, "GetLongShortRatio", []interface{}{symbol, period}, func() (*LongShortRatioInfo, error) {
+		return r.Client.GetLongShortRatio(ctx, symbol, period)
+	})
+}
+
+func (r *RecordingClient) GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	return recorded(r, "GetOrderBook", []interface{}{symbol, limit}, func() (*OrderBook, error) {
+		return r.Client.GetOrderBook(ctx, symbol, limit)
+	})
+}
+
+func (r *RecordingClient) GetTicker24h(ctx context.Context, symbol string) (*Ticker24h, error) {
+	return recorded(r, "GetTicker24h", []interface{}{symbol}, func() (*Ticker24h, error) {
+		return r.Client.GetTicker24h(ctx, symbol)
+	})
+}
+
+func (r *RecordingClient) PlaceOrder(ctx context.Context, order *OrderRequest) (*OrderResponse, error) {
+	return recorded(r, "PlaceOrder", []interface{}{order}, func() (*OrderResponse, error) {
+		return r.Client.PlaceOrder(ctx, order)
+	})
+}
+
+func (r *RecordingClient) ModifyOrder(ctx context.Context, order *ModifyOrderRequest) (*OrderResponse, error) {
+	return recorded(r, "ModifyOrder", []interface{}{order}, func() (*OrderResponse, error) {
+		return r.Client.ModifyOrder(ctx, order)
+	})
+}
+
+func (r *RecordingClient) CancelOrder(ctx context.Context, symbol string, orderID int64) error {
+	return recordedErr(r, "CancelOrder", []interface{}{symbol, orderID}, func() error {
+		return r.Client.CancelOrder(ctx, symbol, orderID)
+	})
+}
+
+func (r *RecordingClient) GetOrder(ctx context.Context, symbol string, orderID int64) (*OrderInfo, error) {
+	return recorded(r, "GetOrder", []interface{}{symbol, orderID}, func() (*OrderInfo, error) {
+		return r.Client.GetOrder(ctx, symbol, orderID)
+	})
+}
+
+func (r *RecordingClient) GetOpenOrders(ctx context.Context, symbol string) ([]*OrderInfo, error) {
+	return recorded(r, "GetOpenOrders", []interface{}{symbol}, func() ([]*OrderInfo, error) {
+		return r.Client.GetOpenOrders(ctx, symbol)
+	})
+}
+
+func (r *RecordingClient) GetOrderTrades(ctx context.Context, symbol string, orderID int64) ([]*TradeInfo, error) {
+	return recorded(r, "GetOrderTrades", []interface{}{symbol, orderID}, func() ([]*TradeInfo, error) {
+		return r.Client.GetOrderTrades(ctx, symbol, orderID)
+	})
+}
+
+func (r *RecordingClient) GetIncomeHistory(ctx context.Context, symbol string, incomeType string, startTime, endTime int64, limit int) ([]*IncomeInfo, error) {
+	return recorded(r, "GetIncomeHistory", []interface{}{symbol, incomeType, startTime, endTime, limit}, func() ([]*IncomeInfo, error) {
+		return r.Client.GetIncomeHistory(ctx, symbol, incomeType, startTime, endTime, limit)
+	})
+}
+
+func (r *RecordingClient) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	return recordedErr(r, "SetLeverage", []interface{}{symbol, leverage}, func() error {
+		return r.Client.SetLeverage(ctx, symbol, leverage)
+	})
+}
+
+func (r *RecordingClient) ChangeMarginType(ctx context.Context, symbol string, marginType string) error {
+	return recordedErr(r, "ChangeMarginType", []interface{}{symbol, marginType}, func() error {
+		return r.Client.ChangeMarginType(ctx, symbol, marginType)
+	})
+}
+
+func (r *RecordingClient) GetADLQuantiles(ctx context.Context) ([]*ADLQuantile, error) {
+	return recorded(r, "GetADLQuantiles", nil, func() ([]*ADLQuantile, error) {
+		return r.Client.GetADLQuantiles(ctx)
+	})
+}
+
+func (r *RecordingClient) SetAutoCancel(ctx context.Context, symbol string, countdown time.Duration) error {
+	return recordedErr(r, "SetAutoCancel", []interface{}{symbol, countdown}, func() error {
+		return r.Client.SetAutoCancel(ctx, symbol, countdown)
+	})
+}
+
+func (r *RecordingClient) GetSymbolSettings(ctx context.Context) ([]*SymbolSettings, error) {
+	return recorded(r, "GetSymbolSettings", nil, func() ([]*SymbolSettings, error) {
+		return r.Client.GetSymbolSettings(ctx)
+	})
+}
+
+func (r *RecordingClient) GetPositionMode(ctx context.Context) (bool, error) {
+	return recorded(r, "GetPositionMode", nil, func() (bool, error) {
+		return r.Client.GetPositionMode(ctx)
+	})
+}
+
+func (r *RecordingClient) SetPositionMode(ctx context.Context, dualSide bool) error {
+	return recordedErr(r, "SetPositionMode", []interface{}{dualSide}, func() error {
+		return r.Client.SetPositionMode(ctx, dualSide)
+	})
+}
+
+func (r *RecordingClient) GetExchangeInfo(ctx context.Context) (*ExchangeInfo, error) {
+	return recorded(r, "GetExchangeInfo", nil, func() (*ExchangeInfo, error) {
+		return r.Client.GetExchangeInfo(ctx)
+	})
+}
+
+func (r *RecordingClient) StartUserDataStream(ctx context.Context, handler UserDataHandler) error {
+	return r.Client.StartUserDataStream(ctx, &recordingUserDataHandler{r, handler})
+}
+
+func (r *RecordingClient) StartMarketDataStream(ctx context.Context, symbols []string, handler MarketDataHandler) error {
+	return r.Client.StartMarketDataStream(ctx, symbols, &recordingMarketDataHandler{r, handler})
+}
+
+func (r *RecordingClient) StartDepthStream(ctx context.Context, symbols []string, handler DepthHandler) error {
+	return r.Client.StartDepthStream(ctx, symbols, &recordingDepthHandler{r, handler})
+}
+
+func (r *RecordingClient) StartMarkPriceStream(ctx context.Context, symbols []string, handler MarkPriceHandler) error {
+	return r.Client.StartMarkPriceStream(ctx, symbols, &recordingMarkPriceHandler{r, handler})
+}
+
+func (r *RecordingClient) StartKlineStream(ctx context.Context, symbols []string, interval string, handler KlineHandler) error {
+	return r.Client.StartKlineStream(ctx, symbols, interval, &recordingKlineHandler{r, handler})
+}
+
+func (r *RecordingClient) StartAggTradeStream(ctx context.Context, symbols []string, handler AggTradeHandler) error {
+	return r.Client.StartAggTradeStream(ctx, symbols, &recordingAggTradeHandler{r, handler})
+}
+
+func (r *RecordingClient) StartLiquidationStream(ctx context.Context, symbols []string, handler LiquidationHandler) error {
+	return r.Client.StartLiquidationStream(ctx, symbols, &recordingLiquidationHandler{r, handler})
+}
+
+// Names of recorded streams
+const (
+	streamUserData    = "user_data"
+	streamMarketData  = "market_data"
+	streamDepth       = "depth"
+	streamMarkPrice   = "mark_price"
+	streamKline       = "kline"
+	streamAggTrade    = "agg_trade"
+	streamLiquidation = "liquidation"
+)
+
+type recordingUserDataHandler struct {
+	r *RecordingClient
+	h UserDataHandler
+}
+
+func (h *recordingUserDataHandler) OnAccountUpdate(account *AccountInfo) {
+	h.r.writeEvent(streamUserData, "OnAccountUpdate", account)
+	h.h.OnAccountUpdate(account)
+}
+
+func (h *recordingUserDataHandler) OnOrderUpdate(order *OrderInfo) {
+	h.r.writeEvent(streamUserData, "OnOrderUpdate", order)
+	h.h.OnOrderUpdate(order)
+}
+
+func (h *recordingUserDataHandler) OnPositionUpdate(position *PositionInfo) {
+	h.r.writeEvent(streamUserData, "OnPositionUpdate", position)
+	h.h.OnPositionUpdate(position)
+}
+
+func (h *recordingUserDataHandler) OnTradeUpdate(trade *TradeInfo) {
+	h.r.writeEvent(streamUserData, "OnTradeUpdate", trade)
+	h.h.OnTradeUpdate(trade)
+}
+
+func (h *recordingUserDataHandler) OnResync(snapshot *UserDataSnapshot) {
+	h.r.writeEvent(streamUserData, "OnResync", snapshot)
+	h.h.OnResync(snapshot)
+}
+
+func (h *recordingUserDataHandler) OnError(err error) {
+	h.r.writeEvent(streamUserData, "OnError", err.Error())
+	h.h.OnError(err)
+}
+
+type recordingMarketDataHandler struct {
+	r *RecordingClient
+	h MarketDataHandler
+}
+
+func (h *recordingMarketDataHandler) OnPriceUpdate(symbol string, price float64) {
+	h.r.writeEvent(streamMarketData, "OnPriceUpdate", symbol, price)
+	h.h.OnPriceUpdate(symbol, price)
+}
+
+func (h *recordingMarketDataHandler) OnKlineUpdate(symbol string, kline *KlineData) {
+	h.r.writeEvent(streamMarketData, "OnKlineUpdate", symbol, kline)
+	h.h.OnKlineUpdate(symbol, kline)
+}
+
+func (h *recordingMarketDataHandler) OnError(err error) {
+	h.r.writeEvent(streamMarketData, "OnError", err.Error())
+	h.h.OnError(err)
+}
+
+type recordingDepthHandler struct {
+	r *RecordingClient
+	h DepthHandler
+}
+
+func (h *recordingDepthHandler) OnDepthUpdate(symbol string, book *OrderBook) {
+	h.r.writeEvent(streamDepth, "OnDepthUpdate", symbol, book)
+	h.h.OnDepthUpdate(symbol, book)
+}
+
+func (h *recordingDepthHandler) OnError(err error) {
+	h.r.writeEvent(streamDepth, "OnError", err.Error())
+	h.h.OnError(err)
+}
+
+type recordingMarkPriceHandler struct {
+	r *RecordingClient
+	h MarkPriceHandler
+}
+
+func (h *recordingMarkPriceHandler) OnMarkPrice(update *MarkPriceUpdate) {
+	h.r.writeEvent(streamMarkPrice, "OnMarkPrice", update)
+	h.h.OnMarkPrice(update)
+}
+
+func (h *recordingMarkPriceHandler) OnError(err error) {
+	h.r.writeEvent(streamMarkPrice, "OnError", err.Error())
+	h.h.OnError(err)
+}
+
+type recordingKlineHandler struct {
+	r *RecordingClient
+	h KlineHandler
+}
+
+func (h *recordingKlineHandler) OnKlineClosed(symbol string, kline *KlineData) {
+	h.r.writeEvent(streamKline, "OnKlineClosed", symbol, kline)
+	h.h.OnKlineClosed(symbol, kline)
+}
+
+func (h *recordingKlineHandler) OnError(err error) {
+	h.r.writeEvent(streamKline, "OnError", err.Error())
+	h.h.OnError(err)
+}
+
+type recordingAggTradeHandler struct {
+	r *RecordingClient
+	h AggTradeHandler
+}
+
+func (h *recordingAggTradeHandler) OnAggTrade(trade *AggTrade) {
+	h.r.writeEvent(streamAggTrade, "OnAggTrade", trade)
+	h.h.OnAggTrade(trade)
+}
+
+func (h *recordingAggTradeHandler) OnError(err error) {
+	h.r.writeEvent(streamAggTrade, "OnError", err.Error())
+	h.h.OnError(err)
+}
+
+type recordingLiquidationHandler struct {
+	r *RecordingClient
+	h LiquidationHandler
+}
+
+func (h *recordingLiquidationHandler) OnLiquidation(liquidation *Liquidation) {
+	h.r.writeEvent(streamLiquidation, "OnLiquidation", liquidation)
+	h.h.OnLiquidation(liquidation)
+}
+
+func (h *recordingLiquidationHandler) OnError(err error) {
+	h.r.writeEvent(streamLiquidation, "OnError", err.Error())
+	h.h.OnError(err)
+}
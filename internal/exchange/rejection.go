@@ -0,0 +1,53 @@
+package exchange
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/adshao/go-binance/v2/common"
+)
+
+// Rejection classes of orders the exchange refused
+const (
+	RejectMinNotional        = "min_notional"        // Order value below the symbol's minimum notional
+	RejectPrecision          = "precision"           // Quantity or price off the step or tick size
+	RejectInsufficientMargin = "insufficient_margin" // Not enough available balance for the margin
+	RejectReduceOnly         = "reduce_only"         // Reduce-only flag conflicts with the position or mode
+	RejectOther              = "other"               // Any other error the exchange returned for the order
+)
+
+// rejectionCodes maps Binance futures error codes to their rejection class
+var rejectionCodes = map[int64]string{
+	-4164: RejectMinNotional,        // Order's notional must be no smaller than the minimum
+	-1111: RejectPrecision,          // Precision is over the maximum defined for this asset
+	-4014: RejectPrecision,          // Price not increased by tick size
+	-4023: RejectPrecision,          // Quantity not increased by step size
+	-2018: RejectInsufficientMargin, // Balance is insufficient
+	-2019: RejectInsufficientMargin, // Margin is insufficient
+	-2022: RejectReduceOnly,         // ReduceOnly order is rejected
+	-4118: RejectReduceOnly,         // ReduceOnly order failed
+}
+
+// ClassifyRejection returns the rejection class of an order placement error, or "" when the
+// error is not a rejection: the exchange was unreachable, overloaded or timed out, so the
+// order may or may not exist and must not be treated as refused.
+func ClassifyRejection(err error) string {
+	var apiErr *common.APIError
+	if err == nil || !errors.As(err, &apiErr) || unavailableCodes[apiErr.Code] {
+		return ""
+	}
+	if class, ok := rejectionCodes[apiErr.Code]; ok {
+		return class
+	}
+
+	message := strings.ToLower(apiErr.Message)
+	switch {
+	case apiErr.Code == -1013 && strings.Contains(message, "notional"):
+		return RejectMinNotional
+	case apiErr.Code == -1013 && (strings.Contains(message, "lot_size") || strings.Contains(message, "price_filter")):
+		return RejectPrecision
+	case apiErr.Code == -1106 && strings.Contains(message, "reduceonly"):
+		return RejectReduceOnly
+	}
+	return RejectOther
+}
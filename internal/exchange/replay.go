@@ -0,0 +1,490 @@
+package exchange
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrReplayExhausted is returned for requests the recording has no more responses for
+var ErrReplayExhausted = errors.New("no recorded response left")
+
+// Streams started within this long of the first one receive events from the beginning
+const replayStreamGrace = 2 * time.Second
+
+// ReplayClient implements Client from a session recording. Each request is answered with the
+// next recorded response of the same method, preferring one with identical arguments, then
+// one for the same symbol. Stream events are delivered in recorded order, spaced by their
+// recorded gaps divided by speed (0 delivers them back to back).
+type ReplayClient struct {
+	logger *logrus.Logger
+	speed  float64
+
+	mu       sync.Mutex
+	calls    map[string][]*recordEntry
+	events   []*recordEntry
+	handlers map[string]interface{}
+	started  bool
+	done     chan struct{}
+}
+
+// NewReplayClient loads a recording written by RecordingClient
+func NewReplayClient(path string, speed float64, logger *logrus.Logger) (*ReplayClient, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+
+	r := &ReplayClient{
+		logger:   logger,
+		speed:    speed,
+		calls:    make(map[string][]*recordEntry),
+		handlers: make(map[string]interface{}),
+		done:     make(chan struct{}),
+	}
+
+	decoder := json.NewDecoder(file)
+	for {
+		var entry recordEntry
+		if err := decoder.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read recording: %w", err)
+		}
+		switch entry.Kind {
+		case recordCall:
+			r.calls[entry.Method] = append(r.calls[entry.Method], &entry)
+		case recordEvent:
+			r.events = append(r.events, &entry)
+		}
+	}
+
+	calls := 0
+	for _, queue := range r.calls {
+		calls += len(queue)
+	}
+	logger.Infof("Replaying %s: %d requests and %d stream events at speed %g", path, calls, len(r.events), speed)
+	return r, nil
+}
+
+// Done is closed once every recorded stream event has been delivered
+func (r *ReplayClient) Done() <-chan struct{} {
+	return r.done
+}
+
+// next removes and returns the recorded call that answers the request
+func (r *ReplayClient) next(method string, args []interface{}) (*recordEntry, error) {
+	key, _ := json.Marshal(args)
+	if len(args) == 0 {
+		key = nil
+	}
+	symbol := replaySymbol(args)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	queue := r.calls[method]
+	match := -1
+	for i, entry := range queue {
+		if bytes.Equal(entry.Args, key) {
+			match = i
+			break
+		}
+	}
+	if match < 0 {
+		for i, entry := range queue {
+			var recordedArgs []json.RawMessage
+			if json.Unmarshal(entry.Args, &recordedArgs) == nil && replayRawSymbol(recordedArgs) == symbol {
+				match = i
+				break
+			}
+		}
+		if match >= 0 {
+			r.logger.Warnf("Replay diverged: %s%s answered with the recording of %s%s", method, key, method, queue[match].Args)
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("replay %s%s: %w", method, key, ErrReplayExhausted)
+	}
+
+	entry := queue[match]
+	r.calls[method] = append(queue[:match:match], queue[match+1:]...)
+	return entry, nil
+}
+
+// replaySymbol is the symbol a request is for, used to match requests whose other arguments
+// differ from the recording, such as generated client order IDs
+func replaySymbol(args []interface{}) string {
+	if len(args) == 0 {
+		return ""
+	}
+	switch arg := args[0].(type) {
+	case string:
+		return arg
+	case *OrderRequest:
+		return arg.Symbol
+	case *ModifyOrderRequest:
+		return arg.Symbol
+	}
+	return ""
+}
+
+// replayRawSymbol is replaySymbol for recorded arguments
+func replayRawSymbol(args []json.RawMessage) string {
+	if len(args) == 0 {
+		return ""
+	}
+	var symbol string
+	if json.Unmarshal(args[0], &symbol) == nil {
+		return symbol
+	}
+	var order OrderRequest
+	if json.Unmarshal(args[0], &order) == nil {
+		return order.Symbol
+	}
+	return ""
+}
+
+// replayed answers a request from the recording
+func replayed[T any](r *ReplayClient, method string, args ...interface{}) (T, error) {
+	var result T
+
+	entry, err := r.next(method, args)
+	if err != nil {
+		return result, err
+	}
+	if entry.Error != "" {
+		return result, errors.New(entry.Error)
+	}
+	if len(entry.Result) > 0 {
+		if err := json.Unmarshal(entry.Result, &result); err != nil {
+			return result, fmt.Errorf("failed to decode recorded %s: %w", method, err)
+		}
+	}
+	return result, nil
+}
+
+func replayedErr(r *ReplayClient, method string, args ...interface{}) error {
+	_, err := replayed[struct{}](r, method, args...)
+	return err
+}
+
+func (r *ReplayClient) GetAccountInfo(ctx context.Context) (*AccountInfo, error) {
+	return replayed[*AccountInfo](r, "GetAccountInfo")
+}
+
+func (r *ReplayClient) GetPositions(ctx context.Context) ([]*PositionInfo, error) {
+	return replayed[[]*PositionInfo](r, "GetPositions")
+}
+
+func (r *ReplayClient) GetBalance(ctx context.Context) ([]*BalanceInfo, error) {
+	return replayed[[]*BalanceInfo](r, "GetBalance")
+}
+
+func (r *ReplayClient) GetSymbolPrice(ctx context.Context, symbol string) (float64, error) {
+	return replayed[float64](r, "GetSymbolPrice", symbol)
+}
+
+func (r *ReplayClient) GetSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	return replayed[*SymbolInfo](r, "GetSymbolInfo", symbol)
+}
+
+func (r *ReplayClient) GetKlines(ctx context.Context, symbol string, interval string, limit int) ([]*KlineData, error) {
+	return replayed[[]*KlineData](r, "GetKlines", symbol, interval, limit)
+}
+
+func (r *ReplayClient) GetPremiumIndex(ctx context.Context, symbol string) (*PremiumIndexInfo, error) {
+	return replayed[*PremiumIndexInfo](r, "GetPremiumIndex", symbol)
+}
+
+func (r *ReplayClient) GetOpenInterest(ctx context.Context, symbol string) (*OpenInterestInfo, error) {
+	return replayed[*OpenInterestInfo](r, "GetOpenInterest", symbol)
+}
+
+func (r *ReplayClient) GetLongShortRatio(ctx context.Context, symbol string, period string) (*LongShortRatioInfo, error) {
+	return replayed[*LongShortRatioInfo](r, "GetLongShortRatio", symbol, period)
+}
+
+func (r *ReplayClient) GetOrderBook(ctx context.Context, symbol string, limit int) (*OrderBook, error) {
+	return replayed[*OrderBook](r, "GetOrderBook", symbol, limit)
+}
+
+func (r *ReplayClient) GetTicker24h(ctx context.Context, symbol string) (*Ticker24h, error) {
+	return replayed[*Ticker24h](r, "GetTicker24h", symbol)
+}
+
+func (r *ReplayClient) PlaceOrder(ctx context.Context, order *OrderRequest) (*OrderResponse, error) {
+	return replayed[*OrderResponse](r, "PlaceOrder", order)
+}
+
+func (r *ReplayClient) ModifyOrder(ctx context.Context, order *ModifyOrderRequest) (*OrderResponse, error) {
+	return replayed[*OrderResponse](r, "ModifyOrder", order)
+}
+
+func (r *ReplayClient) CancelOrder(ctx context.Context, symbol string, orderID int64) error {
+	return replayedErr(r, "CancelOrder", symbol, orderID)
+}
+
+func (r *ReplayClient) GetOrder(ctx context.Context, symbol string, orderID int64) (*OrderInfo, error) {
+	return replayed[*OrderInfo](r, "GetOrder", symbol, orderID)
+}
+
+func (r *ReplayClient) GetOpenOrders(ctx context.Context, symbol string) ([]*OrderInfo, error) {
+	return replayed[[]*OrderInfo](r, "GetOpenOrders", symbol)
+}
+
+func (r *ReplayClient) GetOrderTrades(ctx context.Context, symbol string, orderID int64) ([]*TradeInfo, error) {
+	return replayed[[]*TradeInfo](r, "GetOrderTrades", symbol, orderID)
+}
+
+func (r *ReplayClient) GetIncomeHistory(ctx context.Context, symbol string, incomeType string, startTime, endTime int64, limit int) ([]*IncomeInfo, error) {
+	return replayed[[]*IncomeInfo](r, "GetIncomeHistory", symbol, incomeType, startTime, endTime, limit)
+}
+
+func (r *ReplayClient) SetLeverage(ctx context.Context, symbol string, leverage int) error {
+	return replayedErr(r, "SetLeverage", symbol, leverage)
+}
+
+func (r *ReplayClient) ChangeMarginType(ctx context.Context, symbol string, marginType string) error {
+	return replayedErr(r, "ChangeMarginType", symbol, marginType)
+}
+
+func (r *ReplayClient) GetADLQuantiles(ctx context.Context) ([]*ADLQuantile, error) {
+	return replayed[[]*ADLQuantile](r, "GetADLQuantiles")
+}
+
+func (r *ReplayClient) SetAutoCancel(ctx context.Context, symbol string, countdown time.Duration) error {
+	return replayedErr(r, "SetAutoCancel", symbol, countdown)
+}
+
+func (r *ReplayClient) GetSymbolSettings(ctx context.Context) ([]*SymbolSettings, error) {
+	return replayed[[]*SymbolSettings](r, "GetSymbolSettings")
+}
+
+func (r *ReplayClient) GetPositionMode(ctx context.Context) (bool, error) {
+	return replayed[bool](r, "GetPositionMode")
+}
+
+func (r *ReplayClient) SetPositionMode(ctx context.Context, dualSide bool) error {
+	return replayedErr(r, "SetPositionMode", dualSide)
+}
+
+func (r *ReplayClient) GetExchangeInfo(ctx context.Context) (*ExchangeInfo, error) {
+	return replayed[*ExchangeInfo](r, "GetExchangeInfo")
+}
+
+func (r *ReplayClient) StartUserDataStream(ctx context.Context, handler UserDataHandler) error {
+	r.register(ctx, streamUserData, handler)
+	return nil
+}
+
+func (r *ReplayClient) StartMarketDataStream(ctx context.Context, symbols []string, handler MarketDataHandler) error {
+	r.register(ctx, streamMarketData, handler)
+	return nil
+}
+
+func (r *ReplayClient) StartDepthStream(ctx context.Context, symbols []string, handler DepthHandler) error {
+	r.register(ctx, streamDepth, handler)
+	return nil
+}
+
+func (r *ReplayClient) StartMarkPriceStream(ctx context.Context, symbols []string, handler MarkPriceHandler) error {
+	r.register(ctx, streamMarkPrice, handler)
+	return nil
+}
+
+func (r *ReplayClient) StartKlineStream(ctx context.Context, symbols []string, interval string, handler KlineHandler) error {
+	r.register(ctx, streamKline, handler)
+	return nil
+}
+
+func (r *ReplayClient) StartAggTradeStream(ctx context.Context, symbols []string, handler AggTradeHandler) error {
+	r.register(ctx, streamAggTrade, handler)
+	return nil
+}
+
+func (r *ReplayClient) StartLiquidationStream(ctx context.Context, symbols []string, handler LiquidationHandler) error {
+	r.register(ctx, streamLiquidation, handler)
+	return nil
+}
+
+// register subscribes the handler to the stream's events and starts delivery with the first
+// stream
+func (r *ReplayClient) register(ctx context.Context, stream string, handler interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers[stream] = handler
+	if !r.started {
+		r.started = true
+		go r.dispatch(ctx)
+	}
+}
+
+// dispatch delivers the recorded stream events in order
+func (r *ReplayClient) dispatch(ctx context.Context) {
+	defer close(r.done)
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(replayStreamGrace):
+	}
+
+	var previous int64
+	for _, entry := range r.events {
+		if previous > 0 && r.speed > 0 {
+			if gap := time.Duration(float64(time.Duration(entry.Time-previous)*time.Millisecond) / r.speed); gap > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(gap):
+				}
+			}
+		}
+		previous = entry.Time
+
+		r.mu.Lock()
+		handler, ok := r.handlers[entry.Stream]
+		r.mu.Unlock()
+		if !ok {
+			continue
+		}
+		if err := deliver(handler, entry); err != nil {
+			r.logger.Warnf("Failed to replay %s event %d: %v", entry.Stream, entry.Seq, err)
+		}
+	}
+	r.logger.Infof("Replay finished: delivered %d stream events", len(r.events))
+}
+
+// deliver decodes a recorded event and calls the matching handler method
+func deliver(handler interface{}, entry *recordEntry) error {
+	var args []json.RawMessage
+	if err := json.Unmarshal(entry.Args, &args); err != nil {
+		return err
+	}
+	arg := func(i int, v interface{}) error {
+		if i >= len(args) {
+			return fmt.Errorf("%s has %d arguments, want more than %d", entry.Method, len(args), i)
+		}
+		return json.Unmarshal(args[i], v)
+	}
+	var symbol string
+
+	if entry.Method == "OnError" {
+		var message string
+		if err := arg(0, &message); err != nil {
+			return err
+		}
+		handler.(interface{ OnError(error) }).OnError(errors.New(message))
+		return nil
+	}
+
+	switch entry.Stream {
+	case streamUserData:
+		h := handler.(UserDataHandler)
+		switch entry.Method {
+		case "OnAccountUpdate":
+			var account AccountInfo
+			if err := arg(0, &account); err != nil {
+				return err
+			}
+			h.OnAccountUpdate(&account)
+		case "OnOrderUpdate":
+			var order OrderInfo
+			if err := arg(0, &order); err != nil {
+				return err
+			}
+			h.OnOrderUpdate(&order)
+		case "OnPositionUpdate":
+			var position PositionInfo
+			if err := arg(0, &position); err != nil {
+				return err
+			}
+			h.OnPositionUpdate(&position)
+		case "OnTradeUpdate":
+			var trade TradeInfo
+			if err := arg(0, &trade); err != nil {
+				return err
+			}
+			h.OnTradeUpdate(&trade)
+		case "OnResync":
+			var snapshot UserDataSnapshot
+			if err := arg(0, &snapshot); err != nil {
+				return err
+			}
+			h.OnResync(&snapshot)
+		default:
+			return fmt.Errorf("unknown user data event %s", entry.Method)
+		}
+	case streamMarketData:
+		h := handler.(MarketDataHandler)
+		if err := arg(0, &symbol); err != nil {
+			return err
+		}
+		switch entry.Method {
+		case "OnPriceUpdate":
+			var price float64
+			if err := arg(1, &price); err != nil {
+				return err
+			}
+			h.OnPriceUpdate(symbol, price)
+		case "OnKlineUpdate":
+			var kline KlineData
+			if err := arg(1, &kline); err != nil {
+				return err
+			}
+			h.OnKlineUpdate(symbol, &kline)
+		default:
+			return fmt.Errorf("unknown market data event %s", entry.Method)
+		}
+	case streamDepth:
+		var book OrderBook
+		if err := arg(0, &symbol); err != nil {
+			return err
+		}
+		if err := arg(1, &book); err != nil {
+			return err
+		}
+		handler.(DepthHandler).OnDepthUpdate(symbol, &book)
+	case streamMarkPrice:
+		var update MarkPriceUpdate
+		if err := arg(0, &update); err != nil {
+			return err
+		}
+		handler.(MarkPriceHandler).OnMarkPrice(&update)
+	case streamKline:
+		var kline KlineData
+		if err := arg(0, &symbol); err != nil {
+			return err
+		}
+		if err := arg(1, &kline); err != nil {
+			return err
+		}
+		handler.(KlineHandler).OnKlineClosed(symbol, &kline)
+	case streamAggTrade:
+		var trade AggTrade
+		if err := arg(0, &trade); err != nil {
+			return err
+		}
+		handler.(AggTradeHandler).OnAggTrade(&trade)
+	case streamLiquidation:
+		var liquidation Liquidation
+		if err := arg(0, &liquidation); err != nil {
+			return err
+		}
+		handler.(LiquidationHandler).OnLiquidation(&liquidation)
+	default:
+		return fmt.Errorf("unknown stream %q", entry.Stream)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/adshao/go-binance/v2/common"
+)
+
+// restEndpoint is what a direct REST call needs from a go-binance client: credentials, base
+// URL and server-time offset
+type restEndpoint struct {
+	baseURL      string
+	apiKey       string
+	secretKey    string
+	keyType      string
+	httpClient   *http.Client
+	timeOffset   int64
+	recvWindowMs int64
+}
+
+// signedRequest calls a signed futures REST endpoint that go-binance has no service for,
+// using the client's credentials, base URL and server-time offset. Error responses are
+// returned as *common.APIError like the library's own services.
+func (b *BinanceClient) signedRequest(ctx context.Context, method, endpoint string, params url.Values, result interface{}) error {
+	rest := &restEndpoint{
+		baseURL:      b.client.BaseURL,
+		apiKey:       b.client.APIKey,
+		secretKey:    b.client.SecretKey,
+		keyType:      b.client.KeyType,
+		httpClient:   b.client.HTTPClient,
+		timeOffset:   b.client.TimeOffset,
+		recvWindowMs: b.config.RecvWindowMs,
+	}
+	return rest.do(ctx, method, endpoint, params, true, result)
+}
+
+// do sends the request, signing it when signed is set, and decodes the JSON response
+func (r *restEndpoint) do(ctx context.Context, method, endpoint string, params url.Values, signed bool, result interface{}) error {
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	encoded := query.Encode()
+	if signed {
+		if r.recvWindowMs > 0 {
+			query.Set("recvWindow", strconv.FormatInt(r.recvWindowMs, 10))
+		}
+		query.Set("timestamp", strconv.FormatInt(time.Now().UnixMilli()-r.timeOffset, 10))
+
+		keyType := r.keyType
+		if keyType == "" {
+			keyType = common.KeyTypeHmac
+		}
+		sign, err := common.SignFunc(keyType)
+		if err != nil {
+			return err
+		}
+		encoded = query.Encode()
+		signature, err := sign(r.secretKey, encoded)
+		if err != nil {
+			return fmt.Errorf("failed to sign request: %w", err)
+		}
+		encoded += "&signature=" + url.QueryEscape(*signature)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+endpoint+"?"+encoded, nil)
+	if err != nil {
+		return err
+	}
+	if signed {
+		req.Header.Set("X-MBX-APIKEY", r.apiKey)
+	}
+
+	httpClient := r.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &common.APIError{}
+		if err := json.Unmarshal(data, apiErr); err != nil || !apiErr.IsValid() {
+			apiErr.Response = data
+		}
+		return apiErr
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
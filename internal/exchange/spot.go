@@ -0,0 +1,329 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"contract_playground/internal/config"
+
+	binance "github.com/adshao/go-binance/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// SpotClient defines the spot market operations used to hedge perpetual positions
+type SpotClient interface {
+	GetSpotPrice(ctx context.Context, symbol string) (float64, error)
+	GetSpotSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error)
+	GetSpotBalance(ctx context.Context, asset string) (*SpotBalance, error)
+	GetSpotBalances(ctx context.Context) ([]*SpotBalance, error)
+	PlaceSpotOrder(ctx context.Context, order *SpotOrderRequest) (*SpotOrderResponse, error)
+	GetSpotOrder(ctx context.Context, symbol string, orderID int64) (*SpotOrderResponse, error)
+	CancelSpotOrder(ctx context.Context, symbol string, orderID int64) error
+	GetSpotOpenOrders(ctx context.Context, symbol string) ([]*SpotOrderResponse, error)
+}
+
+type SpotBalance struct {
+	Asset  string  `json:"asset"`
+	Free   float64 `json:"free"`
+	Locked float64 `json:"locked"`
+}
+
+type SpotOrderRequest struct {
+	Symbol           string  `json:"symbol"`
+	Side             string  `json:"side"` // BUY, SELL
+	Type             string  `json:"type"` // MARKET, LIMIT
+	Quantity         float64 `json:"quantity"`
+	Price            float64 `json:"price,omitempty"`
+	TimeInForce      string  `json:"time_in_force,omitempty"`
+	NewClientOrderID string  `json:"new_client_order_id,omitempty"`
+}
+
+type SpotOrderResponse struct {
+	OrderID         int64   `json:"order_id"`
+	Symbol          string  `json:"symbol"`
+	Status          string  `json:"status"`
+	ClientOrderID   string  `json:"client_order_id"`
+	Side            string  `json:"side"`
+	Type            string  `json:"type"`
+	Price           float64 `json:"price,omitempty"` // Limit price
+	OrigQty         float64 `json:"orig_qty"`
+	ExecutedQty     float64 `json:"executed_qty"`
+	CumQuote        float64 `json:"cum_quote"`
+	AvgPrice        float64 `json:"avg_price"`
+	Commission      float64 `json:"commission"` // Summed over fills, in CommissionAsset
+	CommissionAsset string  `json:"commission_asset"`
+	TransactTime    int64   `json:"transact_time"`
+}
+
+// BinanceSpotClient implements SpotClient for Binance spot
+type BinanceSpotClient struct {
+	client *binance.Client
+	config config.ExchangeConfig
+	logger *logrus.Logger
+}
+
+// NewBinanceSpotClient creates a new Binance spot client with the futures API credentials
+func NewBinanceSpotClient(cfg config.ExchangeConfig, logger *logrus.Logger) (SpotClient, error) {
+	if cfg.Testnet {
+		binance.UseTestnet = true
+	}
+
+	client := binance.NewClient(cfg.APIKey, cfg.SecretKey)
+	b := &BinanceSpotClient{
+		client: client,
+		config: cfg,
+		logger: logger,
+	}
+
+	if err := syncServerTime(context.Background(), "spot", b.syncTime, cfg.RecvWindowMs, logger); err != nil {
+		logger.Warnf("Failed to sync spot server time, using local clock: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := client.NewGetAccountService().Do(ctx, b.signedOpts()...); err != nil {
+		return nil, fmt.Errorf("failed to connect to Binance spot: %w", err)
+	}
+
+	logger.Info("Successfully connected to Binance spot API")
+
+	go runServerTimeSync("spot", b.syncTime, time.Duration(cfg.TimeSyncIntervalSeconds)*time.Second, cfg.RecvWindowMs, logger)
+
+	return b, nil
+}
+
+// GetSpotPrice retrieves the last spot price for a symbol
+func (b *BinanceSpotClient) GetSpotPrice(ctx context.Context, symbol string) (float64, error) {
+	prices, err := b.client.NewListPricesService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get spot price: %w", err)
+	}
+
+	if len(prices) == 0 {
+		return 0, fmt.Errorf("no spot price data for symbol %s", symbol)
+	}
+
+	return parseFloat(prices[0].Price), nil
+}
+
+// GetSpotSymbolInfo retrieves trading rules for a spot symbol
+func (b *BinanceSpotClient) GetSpotSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	exchangeInfo, err := b.client.NewExchangeInfoService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot exchange info: %w", err)
+	}
+
+	for _, s := range exchangeInfo.Symbols {
+		if s.Symbol != symbol {
+			continue
+		}
+
+		info := &SymbolInfo{
+			Symbol:     s.Symbol,
+			Status:     s.Status,
+			BaseAsset:  s.BaseAsset,
+			QuoteAsset: s.QuoteAsset,
+		}
+		if lot := s.LotSizeFilter(); lot != nil {
+			info.MinQty = parseFloat(lot.MinQuantity)
+			info.MaxQty = parseFloat(lot.MaxQuantity)
+			info.StepSize = parseFloat(lot.StepSize)
+		}
+		if price := s.PriceFilter(); price != nil {
+			info.MinPrice = parseFloat(price.MinPrice)
+			info.MaxPrice = parseFloat(price.MaxPrice)
+			info.TickSize = parseFloat(price.TickSize)
+		}
+		if notional := s.NotionalFilter(); notional != nil {
+			info.MinNotional = parseFloat(notional.MinNotional)
+		}
+		return info, nil
+	}
+
+	return nil, fmt.Errorf("spot symbol %s not found", symbol)
+}
+
+// GetSpotBalance retrieves the spot wallet balance of an asset
+func (b *BinanceSpotClient) GetSpotBalance(ctx context.Context, asset string) (*SpotBalance, error) {
+	account, err := b.client.NewGetAccountService().Do(ctx, b.signedOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot balance: %w", err)
+	}
+
+	for _, balance := range account.Balances {
+		if balance.Asset == asset {
+			return &SpotBalance{
+				Asset:  balance.Asset,
+				Free:   parseFloat(balance.Free),
+				Locked: parseFloat(balance.Locked),
+			}, nil
+		}
+	}
+
+	return &SpotBalance{Asset: asset}, nil
+}
+
+// GetSpotBalances retrieves every non-zero spot wallet balance
+func (b *BinanceSpotClient) GetSpotBalances(ctx context.Context) ([]*SpotBalance, error) {
+	account, err := b.client.NewGetAccountService().Do(ctx, b.signedOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot balances: %w", err)
+	}
+
+	var result []*SpotBalance
+	for _, balance := range account.Balances {
+		free, locked := parseFloat(balance.Free), parseFloat(balance.Locked)
+		if free == 0 && locked == 0 {
+			continue
+		}
+		result = append(result, &SpotBalance{Asset: balance.Asset, Free: free, Locked: locked})
+	}
+
+	return result, nil
+}
+
+// PlaceSpotOrder places a spot order and reports its fills
+func (b *BinanceSpotClient) PlaceSpotOrder(ctx context.Context, order *SpotOrderRequest) (*SpotOrderResponse, error) {
+	service := b.client.NewCreateOrderService().
+		Symbol(order.Symbol).
+		Side(binance.SideType(order.Side)).
+		Type(binance.OrderType(order.Type)).
+		Quantity(fmt.Sprintf("%.8f", order.Quantity)).
+		NewOrderRespType(binance.NewOrderRespTypeFULL) // Include fills for the average price and fees
+
+	if order.Price > 0 {
+		service = service.Price(fmt.Sprintf("%.8f", order.Price))
+	}
+
+	if order.TimeInForce != "" {
+		service = service.TimeInForce(binance.TimeInForceType(order.TimeInForce))
+	}
+
+	if order.NewClientOrderID != "" {
+		service = service.NewClientOrderID(order.NewClientOrderID)
+	}
+
+	var response *binance.CreateOrderResponse
+	err := b.withTimeSync(ctx, func() error {
+		var err error
+		response, err = service.Do(ctx, b.signedOpts()...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to place spot order: %w", err)
+	}
+
+	result := &SpotOrderResponse{
+		OrderID:       response.OrderID,
+		Symbol:        response.Symbol,
+		Status:        string(response.Status),
+		ClientOrderID: response.ClientOrderID,
+		Side:          string(response.Side),
+		Type:          string(response.Type),
+		Price:         parseFloat(response.Price),
+		OrigQty:       parseFloat(response.OrigQuantity),
+		ExecutedQty:   parseFloat(response.ExecutedQuantity),
+		CumQuote:      parseFloat(response.CummulativeQuoteQuantity),
+		TransactTime:  response.TransactTime,
+	}
+
+	if result.ExecutedQty > 0 {
+		result.AvgPrice = result.CumQuote / result.ExecutedQty
+	}
+	for _, fill := range response.Fills {
+		result.Commission += parseFloat(fill.Commission)
+		result.CommissionAsset = fill.CommissionAsset
+	}
+
+	return result, nil
+}
+
+// GetSpotOrder retrieves a spot order; commissions are not reported for queried orders
+func (b *BinanceSpotClient) GetSpotOrder(ctx context.Context, symbol string, orderID int64) (*SpotOrderResponse, error) {
+	order, err := b.client.NewGetOrderService().
+		Symbol(symbol).
+		OrderID(orderID).
+		Do(ctx, b.signedOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot order: %w", err)
+	}
+
+	return spotOrderInfo(order), nil
+}
+
+// CancelSpotOrder cancels an open spot order
+func (b *BinanceSpotClient) CancelSpotOrder(ctx context.Context, symbol string, orderID int64) error {
+	err := b.withTimeSync(ctx, func() error {
+		_, err := b.client.NewCancelOrderService().
+			Symbol(symbol).
+			OrderID(orderID).
+			Do(ctx, b.signedOpts()...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel spot order: %w", err)
+	}
+
+	return nil
+}
+
+// GetSpotOpenOrders retrieves the open spot orders of a symbol, or of every symbol when empty
+func (b *BinanceSpotClient) GetSpotOpenOrders(ctx context.Context, symbol string) ([]*SpotOrderResponse, error) {
+	service := b.client.NewListOpenOrdersService()
+	if symbol != "" {
+		service = service.Symbol(symbol)
+	}
+
+	orders, err := service.Do(ctx, b.signedOpts()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot open orders: %w", err)
+	}
+
+	result := make([]*SpotOrderResponse, 0, len(orders))
+	for _, order := range orders {
+		result = append(result, spotOrderInfo(order))
+	}
+
+	return result, nil
+}
+
+// spotOrderInfo converts a queried spot order
+func spotOrderInfo(order *binance.Order) *SpotOrderResponse {
+	result := &SpotOrderResponse{
+		OrderID:       order.OrderID,
+		Symbol:        order.Symbol,
+		Status:        string(order.Status),
+		ClientOrderID: order.ClientOrderID,
+		Side:          string(order.Side),
+		Type:          string(order.Type),
+		Price:         parseFloat(order.Price),
+		OrigQty:       parseFloat(order.OrigQuantity),
+		ExecutedQty:   parseFloat(order.ExecutedQuantity),
+		CumQuote:      parseFloat(order.CummulativeQuoteQuantity),
+		TransactTime:  order.UpdateTime,
+	}
+	if result.ExecutedQty > 0 {
+		result.AvgPrice = result.CumQuote / result.ExecutedQty
+	}
+	return result
+}
+
+// signedOpts returns the request options for signed spot endpoints
+func (b *BinanceSpotClient) signedOpts() []binance.RequestOption {
+	if b.config.RecvWindowMs <= 0 {
+		return nil
+	}
+	return []binance.RequestOption{binance.WithRecvWindow(b.config.RecvWindowMs)}
+}
+
+// withTimeSync runs a signed spot request, retrying once after a timestamp rejection
+func (b *BinanceSpotClient) withTimeSync(ctx context.Context, call func() error) error {
+	return retryAfterTimeSync(ctx, "spot", b.syncTime, b.config.RecvWindowMs, b.logger, call)
+}
+
+// syncTime updates the spot client's server-time offset
+func (b *BinanceSpotClient) syncTime(ctx context.Context) (int64, error) {
+	return b.client.NewSetServerTimeService().Do(ctx)
+}
@@ -0,0 +1,738 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Reconnect backoff bounds for websocket streams
+	streamMinBackoff = 1 * time.Second
+	streamMaxBackoff = 60 * time.Second
+
+	// Binance listen keys expire after 60 minutes without a keepalive
+	listenKeyKeepaliveInterval = 30 * time.Minute
+
+	// Depth snapshot size used to seed the local order book
+	depthSnapshotLimit = 1000
+	// Number of levels passed to depth handlers on each update
+	depthHandlerLevels = 100
+	// Maximum number of diff events buffered while a snapshot is loading
+	depthMaxBufferedEvents = 1000
+)
+
+// UserDataSnapshot is the full account state fetched over REST after a stream gap
+type UserDataSnapshot struct {
+	Account    *AccountInfo    `json:"account"`
+	Positions  []*PositionInfo `json:"positions"`
+	OpenOrders []*OrderInfo    `json:"open_orders"`
+	Time       time.Time       `json:"time"`
+}
+
+// DepthHandler receives order book updates from the depth stream
+type DepthHandler interface {
+	OnDepthUpdate(symbol string, book *OrderBook)
+	OnError(err error)
+}
+
+// MarkPriceUpdate is a mark price push from the mark price stream
+type MarkPriceUpdate struct {
+	Symbol          string  `json:"symbol"`
+	MarkPrice       float64 `json:"mark_price"`
+	IndexPrice      float64 `json:"index_price"`
+	FundingRate     float64 `json:"funding_rate"`
+	NextFundingTime int64   `json:"next_funding_time"`
+	Time            int64   `json:"time"`
+}
+
+// MarkPriceHandler receives updates from the mark price stream
+type MarkPriceHandler interface {
+	OnMarkPrice(update *MarkPriceUpdate)
+	OnError(err error)
+}
+
+// KlineHandler receives candles from the kline stream once they close
+type KlineHandler interface {
+	OnKlineClosed(symbol string, kline *KlineData)
+	OnError(err error)
+}
+
+// AggTrade is a trade aggregated for a single taker order
+type AggTrade struct {
+	Symbol     string  `json:"symbol"`
+	ID         int64   `json:"id"`
+	Price      float64 `json:"price"`
+	Quantity   float64 `json:"quantity"`
+	BuyerMaker bool    `json:"buyer_maker"` // The taker sold
+	Time       int64   `json:"time"`
+}
+
+// AggTradeHandler receives trades from the aggregate trade stream
+type AggTradeHandler interface {
+	OnAggTrade(trade *AggTrade)
+	OnError(err error)
+}
+
+// Liquidation is a forced liquidation order; a SELL liquidates a long position
+type Liquidation struct {
+	Symbol   string  `json:"symbol"`
+	Side     string  `json:"side"`
+	Price    float64 `json:"price"`
+	AvgPrice float64 `json:"avg_price"`
+	Quantity float64 `json:"quantity"` // Filled quantity
+	Time     int64   `json:"time"`
+}
+
+// LiquidationHandler receives liquidation orders from the forceOrder stream
+type LiquidationHandler interface {
+	OnLiquidation(liquidation *Liquidation)
+	OnError(err error)
+}
+
+// streamConnector opens a websocket connection and returns its done/stop channels
+type streamConnector func() (doneC, stopC chan struct{}, err error)
+
+// runStream keeps a websocket stream alive until ctx is cancelled, reconnecting with
+// exponential backoff. onConnect runs after every successful connection so callers can
+// re-sync state that may have been missed while disconnected. Sending on restart forces
+// the current connection to be dropped and re-established.
+func runStream(ctx context.Context, logger *logrus.Logger, name string, connect streamConnector, onConnect func(reconnect bool), restart <-chan struct{}) {
+	backoff := streamMinBackoff
+	reconnect := false
+
+	for {
+		doneC, stopC, err := connect()
+		if err != nil {
+			logger.Errorf("Failed to connect %s stream: %v (retrying in %s)", name, err, backoff)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = nextBackoff(backoff)
+			continue
+		}
+
+		if reconnect {
+			logger.Infof("Reconnected %s stream", name)
+		} else {
+			logger.Infof("Connected %s stream", name)
+		}
+		backoff = streamMinBackoff
+		onConnect(reconnect)
+
+		select {
+		case <-ctx.Done():
+			close(stopC)
+			<-doneC
+			logger.Infof("Stopped %s stream", name)
+			return
+		case <-restart:
+			logger.Warnf("Restarting %s stream", name)
+			close(stopC)
+			<-doneC
+		case <-doneC:
+			logger.Warnf("%s stream disconnected", name)
+		}
+
+		reconnect = true
+	}
+}
+
+// nextBackoff doubles the backoff up to the configured maximum
+func nextBackoff(current time.Duration) time.Duration {
+	next := current * 2
+	if next > streamMaxBackoff {
+		return streamMaxBackoff
+	}
+	return next
+}
+
+// StartUserDataStream starts the user data stream. Whenever the stream (re)connects the
+// full account, position and open order state is re-fetched over REST and delivered via
+// OnResync; events received while the re-sync is in flight are buffered and replayed
+// afterwards so handlers never see stream updates older than the snapshot.
+func (b *BinanceClient) StartUserDataStream(ctx context.Context, handler UserDataHandler) error {
+	uds := &userDataSync{client: b, handler: handler, restart: make(chan struct{}, 1)}
+
+	connect := func() (chan struct{}, chan struct{}, error) {
+		key, err := b.client.NewStartUserStreamService().Do(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to obtain listen key: %w", err)
+		}
+		uds.setListenKey(key)
+		return futures.WsUserDataServe(key, uds.onEvent, handler.OnError)
+	}
+
+	go uds.keepalive(ctx)
+	go runStream(ctx, b.logger, "user data", connect, func(bool) { uds.beginResync(ctx) }, uds.restart)
+
+	return nil
+}
+
+// userDataSync tracks user data stream state and REST re-synchronisation
+type userDataSync struct {
+	client  *BinanceClient
+	handler UserDataHandler
+	restart chan struct{}
+
+	mu            sync.Mutex
+	listenKey     string
+	resyncing     bool
+	buffer        []*futures.WsUserDataEvent
+	lastEventTime int64
+}
+
+func (u *userDataSync) setListenKey(key string) {
+	u.mu.Lock()
+	u.listenKey = key
+	u.mu.Unlock()
+}
+
+// keepalive extends the listen key validity until ctx is cancelled
+func (u *userDataSync) keepalive(ctx context.Context) {
+	ticker := time.NewTicker(listenKeyKeepaliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			u.mu.Lock()
+			key := u.listenKey
+			u.mu.Unlock()
+			// Not connected yet; the next connect obtains a fresh key
+			if key == "" {
+				continue
+			}
+
+			if err := u.client.client.NewKeepaliveUserStreamService().ListenKey(key).Do(ctx); err != nil {
+				u.client.logger.Errorf("Failed to keep user data stream alive: %v", err)
+				u.requestRestart()
+			}
+		}
+	}
+}
+
+func (u *userDataSync) requestRestart() {
+	select {
+	case u.restart <- struct{}{}:
+	default:
+	}
+}
+
+// beginResync marks the stream as out of sync and refreshes state over REST
+func (u *userDataSync) beginResync(ctx context.Context) {
+	u.mu.Lock()
+	u.resyncing = true
+	u.buffer = nil
+	u.mu.Unlock()
+
+	go u.resync(ctx)
+}
+
+// resync fetches the full account state, delivers it and replays buffered events
+func (u *userDataSync) resync(ctx context.Context) {
+	backoff := streamMinBackoff
+	for {
+		snapshot, err := u.client.fetchUserDataSnapshot(ctx)
+		if err == nil {
+			u.handler.OnResync(snapshot)
+			break
+		}
+
+		u.handler.OnError(fmt.Errorf("user data re-sync failed: %w", err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = nextBackoff(backoff)
+	}
+
+	u.mu.Lock()
+	buffered := u.buffer
+	u.buffer = nil
+	u.resyncing = false
+	u.mu.Unlock()
+
+	u.client.logger.Infof("User data re-sync complete, replaying %d buffered events", len(buffered))
+	for _, event := range buffered {
+		u.dispatch(event)
+	}
+}
+
+// onEvent is the raw websocket callback
+func (u *userDataSync) onEvent(event *futures.WsUserDataEvent) {
+	if event.Event == futures.UserDataEventTypeListenKeyExpired {
+		u.client.logger.Warn("User data listen key expired")
+		u.requestRestart()
+		return
+	}
+
+	u.mu.Lock()
+	if u.resyncing {
+		u.buffer = append(u.buffer, event)
+		u.mu.Unlock()
+		return
+	}
+	u.mu.Unlock()
+
+	u.dispatch(event)
+}
+
+// dispatch converts an event and forwards it to the handler, dropping out-of-order events
+func (u *userDataSync) dispatch(event *futures.WsUserDataEvent) {
+	u.mu.Lock()
+	if event.Time < u.lastEventTime {
+		u.mu.Unlock()
+		u.client.logger.Debugf("Dropping out-of-order user data event %s (time %d < %d)", event.Event, event.Time, u.lastEventTime)
+		return
+	}
+	u.lastEventTime = event.Time
+	u.mu.Unlock()
+
+	switch event.Event {
+	case futures.UserDataEventTypeAccountUpdate:
+		for _, balance := range event.AccountUpdate.Balances {
+			u.handler.OnAccountUpdate(&AccountInfo{
+				TotalWalletBalance:      parseFloat(balance.Balance),
+				TotalCrossWalletBalance: parseFloat(balance.CrossWalletBalance),
+				UpdateTime:              event.Time,
+			})
+		}
+		for _, pos := range event.AccountUpdate.Positions {
+			u.handler.OnPositionUpdate(&PositionInfo{
+				Symbol:            pos.Symbol,
+				PositionSide:      string(pos.Side),
+				PositionAmt:       parseFloat(pos.Amount),
+				EntryPrice:        parseFloat(pos.EntryPrice),
+				MarkPrice:         parseFloat(pos.MarkPrice),
+				UnrealizedPnL:     parseFloat(pos.UnrealizedPnL),
+				MaintenanceMargin: parseFloat(pos.MaintenanceMarginRequired),
+				UpdateTime:        event.Time,
+			})
+		}
+
+	case futures.UserDataEventTypeOrderTradeUpdate:
+		o := event.OrderTradeUpdate
+		u.handler.OnOrderUpdate(&OrderInfo{
+			OrderID:       o.ID,
+			Symbol:        o.Symbol,
+			Status:        string(o.Status),
+			ClientOrderID: o.ClientOrderID,
+			Price:         parseFloat(o.OriginalPrice),
+			AvgPrice:      parseFloat(o.AveragePrice),
+			OrigQty:       parseFloat(o.OriginalQty),
+			ExecutedQty:   parseFloat(o.AccumulatedFilledQty),
+			TimeInForce:   string(o.TimeInForce),
+			Type:          string(o.Type),
+			ReduceOnly:    o.IsReduceOnly,
+			ClosePosition: o.IsClosingPosition,
+			Side:          string(o.Side),
+			PositionSide:  string(o.PositionSide),
+			StopPrice:     parseFloat(o.StopPrice),
+			WorkingType:   string(o.WorkingType),
+			PriceProtect:  o.PriceProtect,
+			Time:          o.TradeTime,
+			UpdateTime:    event.Time,
+		})
+
+		if o.ExecutionType == futures.OrderExecutionTypeTrade {
+			u.handler.OnTradeUpdate(&TradeInfo{
+				Symbol:          o.Symbol,
+				ID:              o.TradeID,
+				OrderID:         o.ID,
+				Side:            string(o.Side),
+				Quantity:        parseFloat(o.LastFilledQty),
+				Price:           parseFloat(o.LastFilledPrice),
+				Commission:      parseFloat(o.Commission),
+				CommissionAsset: o.CommissionAsset,
+				Time:            o.TradeTime,
+				IsMaker:         o.IsMaker,
+				RealizedPnL:     parseFloat(o.RealizedPnL),
+			})
+		}
+	}
+}
+
+// fetchUserDataSnapshot loads account, positions and open orders over REST
+func (b *BinanceClient) fetchUserDataSnapshot(ctx context.Context) (*UserDataSnapshot, error) {
+	account, err := b.GetAccountInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := b.GetPositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := b.GetOpenOrders(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserDataSnapshot{
+		Account:    account,
+		Positions:  positions,
+		OpenOrders: orders,
+		Time:       time.Now(),
+	}, nil
+}
+
+// StartDepthStream maintains local order books for the given symbols from the diff depth
+// stream. Each book is seeded from a REST snapshot and validated against the update ID
+// sequence; a gap (missed event or reconnect) discards the book and re-seeds it.
+func (b *BinanceClient) StartDepthStream(ctx context.Context, symbols []string, handler DepthHandler) error {
+	if len(symbols) == 0 {
+		return fmt.Errorf("no symbols given for depth stream")
+	}
+
+	books := make(map[string]*depthSync, len(symbols))
+	for _, symbol := range symbols {
+		books[symbol] = &depthSync{client: b, symbol: symbol, handler: handler}
+	}
+
+	onEvent := func(event *futures.WsDepthEvent) {
+		if book, ok := books[event.Symbol]; ok {
+			book.onEvent(ctx, event)
+		}
+	}
+
+	connect := func() (chan struct{}, chan struct{}, error) {
+		return futures.WsCombinedDiffDepthServe(symbols, onEvent, handler.OnError)
+	}
+
+	onConnect := func(bool) {
+		for _, book := range books {
+			book.invalidate(ctx)
+		}
+	}
+
+	go runStream(ctx, b.logger, "depth", connect, onConnect, nil)
+
+	return nil
+}
+
+// StartMarkPriceStream streams mark prices (every 3s) for the given symbols
+func (b *BinanceClient) StartMarkPriceStream(ctx context.Context, symbols []string, handler MarkPriceHandler) error {
+	if len(symbols) == 0 {
+		return fmt.Errorf("no symbols given for mark price stream")
+	}
+
+	onEvent := func(event *futures.WsMarkPriceEvent) {
+		handler.OnMarkPrice(&MarkPriceUpdate{
+			Symbol:          event.Symbol,
+			MarkPrice:       parseFloat(event.MarkPrice),
+			IndexPrice:      parseFloat(event.IndexPrice),
+			FundingRate:     parseFloat(event.FundingRate),
+			NextFundingTime: event.NextFundingTime,
+			Time:            event.Time,
+		})
+	}
+
+	connect := func() (chan struct{}, chan struct{}, error) {
+		return futures.WsCombinedMarkPriceServe(symbols, onEvent, handler.OnError)
+	}
+
+	go runStream(ctx, b.logger, "mark price", connect, func(bool) {}, nil)
+
+	return nil
+}
+
+// StartKlineStream streams candles of the given interval and forwards each one when it closes
+func (b *BinanceClient) StartKlineStream(ctx context.Context, symbols []string, interval string, handler KlineHandler) error {
+	if len(symbols) == 0 {
+		return fmt.Errorf("no symbols given for kline stream")
+	}
+
+	pairs := make(map[string]string, len(symbols))
+	for _, symbol := range symbols {
+		pairs[symbol] = interval
+	}
+
+	onEvent := func(event *futures.WsKlineEvent) {
+		k := event.Kline
+		if !k.IsFinal {
+			return
+		}
+		handler.OnKlineClosed(k.Symbol, &KlineData{
+			OpenTime:                 k.StartTime,
+			Open:                     parseFloat(k.Open),
+			High:                     parseFloat(k.High),
+			Low:                      parseFloat(k.Low),
+			Close:                    parseFloat(k.Close),
+			Volume:                   parseFloat(k.Volume),
+			CloseTime:                k.EndTime,
+			QuoteAssetVolume:         parseFloat(k.QuoteVolume),
+			TradeCount:               k.TradeNum,
+			TakerBuyBaseAssetVolume:  parseFloat(k.ActiveBuyVolume),
+			TakerBuyQuoteAssetVolume: parseFloat(k.ActiveBuyQuoteVolume),
+		})
+	}
+
+	connect := func() (chan struct{}, chan struct{}, error) {
+		return futures.WsCombinedKlineServe(pairs, onEvent, handler.OnError)
+	}
+
+	go runStream(ctx, b.logger, "kline", connect, func(bool) {}, nil)
+
+	return nil
+}
+
+// StartAggTradeStream streams aggregate trades of the given symbols
+func (b *BinanceClient) StartAggTradeStream(ctx context.Context, symbols []string, handler AggTradeHandler) error {
+	if len(symbols) == 0 {
+		return fmt.Errorf("no symbols given for aggregate trade stream")
+	}
+
+	onEvent := func(event *futures.WsAggTradeEvent) {
+		handler.OnAggTrade(&AggTrade{
+			Symbol:     event.Symbol,
+			ID:         event.AggregateTradeID,
+			Price:      parseFloat(event.Price),
+			Quantity:   parseFloat(event.Quantity),
+			BuyerMaker: event.Maker,
+			Time:       event.TradeTime,
+		})
+	}
+
+	connect := func() (chan struct{}, chan struct{}, error) {
+		return futures.WsCombinedAggTradeServe(symbols, onEvent, handler.OnError)
+	}
+
+	go runStream(ctx, b.logger, "aggregate trade", connect, func(bool) {}, nil)
+
+	return nil
+}
+
+// StartLiquidationStream streams liquidation orders of the given symbols. The exchange only
+// offers per-symbol and all-market streams, so the all-market stream is filtered.
+func (b *BinanceClient) StartLiquidationStream(ctx context.Context, symbols []string, handler LiquidationHandler) error {
+	if len(symbols) == 0 {
+		return fmt.Errorf("no symbols given for liquidation stream")
+	}
+
+	wanted := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		wanted[symbol] = true
+	}
+
+	onEvent := func(event *futures.WsLiquidationOrderEvent) {
+		order := event.LiquidationOrder
+		if !wanted[order.Symbol] {
+			return
+		}
+		handler.OnLiquidation(&Liquidation{
+			Symbol:   order.Symbol,
+			Side:     string(order.Side),
+			Price:    parseFloat(order.Price),
+			AvgPrice: parseFloat(order.AvgPrice),
+			Quantity: parseFloat(order.AccumulatedFilledQty),
+			Time:     order.TradeTime,
+		})
+	}
+
+	connect := func() (chan struct{}, chan struct{}, error) {
+		return futures.WsAllLiquidationOrderServe(onEvent, handler.OnError)
+	}
+
+	go runStream(ctx, b.logger, "liquidation", connect, func(bool) {}, nil)
+
+	return nil
+}
+
+// depthSync tracks the sequence state of one symbol's local order book
+type depthSync struct {
+	client  *BinanceClient
+	symbol  string
+	handler DepthHandler
+
+	mu        sync.Mutex
+	book      *localOrderBook // Seeded from a snapshot; nil while unseeded
+	synced    bool            // The book is bridged to the stream and kept current
+	resyncing bool
+	buffer    []*futures.WsDepthEvent
+}
+
+// invalidate drops the local book and starts re-seeding it from a REST snapshot
+func (d *depthSync) invalidate(ctx context.Context) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.synced = false
+	d.book = nil
+	d.buffer = nil
+	d.startResyncLocked(ctx)
+}
+
+func (d *depthSync) startResyncLocked(ctx context.Context) {
+	if d.resyncing {
+		return
+	}
+	d.resyncing = true
+	go d.resync(ctx)
+}
+
+// onEvent applies a diff event, buffering while unsynced and re-seeding on sequence gaps
+func (d *depthSync) onEvent(ctx context.Context, event *futures.WsDepthEvent) {
+	d.mu.Lock()
+
+	if !d.synced && d.book == nil {
+		if len(d.buffer) < depthMaxBufferedEvents {
+			d.buffer = append(d.buffer, event)
+		}
+		d.startResyncLocked(ctx)
+		d.mu.Unlock()
+		return
+	}
+
+	if !d.synced {
+		// Seeded from a snapshot that no buffered event bridged; wait for the live one that does
+		switch bridge := bridgesSnapshot(event, d.book.lastUpdateID); {
+		case bridge < 0:
+			d.mu.Unlock()
+			return
+		case bridge > 0:
+			d.client.logger.Warnf("Depth snapshot for %s at update ID %d is older than the stream (U=%d); re-seeding order book",
+				d.symbol, d.book.lastUpdateID, event.FirstUpdateID)
+			d.book = nil
+			d.buffer = []*futures.WsDepthEvent{event}
+			d.startResyncLocked(ctx)
+			d.mu.Unlock()
+			return
+		}
+		d.synced = true
+		d.client.logger.Infof("Order book for %s synced at update ID %d", d.symbol, event.LastUpdateID)
+	} else if event.PrevLastUpdateID != d.book.lastUpdateID {
+		d.client.logger.Warnf("Depth sequence gap for %s: expected pu=%d, got pu=%d; re-seeding order book",
+			d.symbol, d.book.lastUpdateID, event.PrevLastUpdateID)
+		d.synced = false
+		d.book = nil
+		d.buffer = []*futures.WsDepthEvent{event}
+		d.startResyncLocked(ctx)
+		d.mu.Unlock()
+		return
+	}
+
+	d.applyLocked(event)
+	book := d.book.snapshot(depthHandlerLevels)
+	d.mu.Unlock()
+
+	d.handler.OnDepthUpdate(d.symbol, book)
+}
+
+func (d *depthSync) applyLocked(event *futures.WsDepthEvent) {
+	d.book.apply(toPriceLevels(event.Bids), toPriceLevels(event.Asks))
+	d.book.lastUpdateID = event.LastUpdateID
+	d.book.eventTime = event.Time
+}
+
+// resync fetches a REST snapshot and replays buffered events on top of it
+func (d *depthSync) resync(ctx context.Context) {
+	backoff := streamMinBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		snapshot, err := d.client.client.NewDepthService().Symbol(d.symbol).Limit(depthSnapshotLimit).Do(ctx)
+		if err != nil {
+			d.handler.OnError(fmt.Errorf("failed to fetch depth snapshot for %s: %w", d.symbol, err))
+		} else if book, ok := d.seed(snapshot); ok {
+			if book != nil {
+				d.handler.OnDepthUpdate(d.symbol, book)
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = nextBackoff(backoff)
+	}
+}
+
+// bridgesSnapshot applies the futures rule for the first event after a snapshot: it must
+// satisfy U <= lastUpdateId <= u. It returns -1 for an event already contained in the
+// snapshot, 1 for one past it (the snapshot is stale) and 0 for the bridging event.
+func bridgesSnapshot(event *futures.WsDepthEvent, lastUpdateID int64) int {
+	switch {
+	case event.LastUpdateID < lastUpdateID:
+		return -1
+	case event.FirstUpdateID > lastUpdateID:
+		return 1
+	}
+	return 0
+}
+
+// seed builds the book from a snapshot and bridges it to the buffered events. It returns
+// false when the buffered events cannot be connected to the snapshot and another attempt
+// is needed. When no buffered event reaches the snapshot yet, the book stays unsynced
+// without a result and the next live event bridges it in onEvent.
+func (d *depthSync) seed(snapshot *futures.DepthResponse) (*OrderBook, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	book := newLocalOrderBook(d.symbol, snapshot.LastUpdateID, toPriceLevels(snapshot.Bids), toPriceLevels(snapshot.Asks))
+	book.eventTime = snapshot.Time
+
+	// Drop events already contained in the snapshot
+	pending := make([]*futures.WsDepthEvent, 0, len(d.buffer))
+	for _, event := range d.buffer {
+		if bridgesSnapshot(event, snapshot.LastUpdateID) >= 0 {
+			pending = append(pending, event)
+		}
+	}
+
+	// The first applied event must straddle the snapshot update ID
+	if len(pending) > 0 && bridgesSnapshot(pending[0], snapshot.LastUpdateID) > 0 {
+		d.client.logger.Debugf("Depth snapshot for %s is older than buffered events, retrying", d.symbol)
+		return nil, false
+	}
+
+	d.book = book
+	for i, event := range pending {
+		if i > 0 && event.PrevLastUpdateID != d.book.lastUpdateID {
+			d.client.logger.Debugf("Buffered depth events for %s are not contiguous, retrying", d.symbol)
+			d.book = nil
+			d.buffer = pending[i:]
+			return nil, false
+		}
+		d.applyLocked(event)
+	}
+
+	d.buffer = nil
+	d.resyncing = false
+	if len(pending) == 0 {
+		d.client.logger.Debugf("Depth snapshot for %s at update ID %d awaits a bridging event", d.symbol, book.lastUpdateID)
+		return nil, true
+	}
+	d.synced = true
+	d.client.logger.Infof("Order book for %s synced at update ID %d", d.symbol, d.book.lastUpdateID)
+
+	return d.book.snapshot(depthHandlerLevels), true
+}
+
+// toPriceLevels converts exchange price levels to parsed levels
+func toPriceLevels(levels []futures.Bid) []PriceLevel {
+	result := make([]PriceLevel, 0, len(levels))
+	for _, level := range levels {
+		result = append(result, PriceLevel{
+			Price:    parseFloat(level.Price),
+			Quantity: parseFloat(level.Quantity),
+		})
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package exchange
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/sirupsen/logrus"
+)
+
+func TestBridgesSnapshot(t *testing.T) {
+	// Snapshot at update ID 100
+	tests := []struct {
+		name        string
+		first, last int64
+		want        int
+	}{
+		{"contained in the snapshot", 90, 99, -1},
+		{"ends at the snapshot", 95, 100, 0},
+		{"straddles the snapshot", 95, 105, 0},
+		{"starts at the snapshot", 100, 105, 0},
+		{"starts after the snapshot", 101, 110, 1},
+	}
+	for _, tt := range tests {
+		event := &futures.WsDepthEvent{FirstUpdateID: tt.first, LastUpdateID: tt.last}
+		if got := bridgesSnapshot(event, 100); got != tt.want {
+			t.Errorf("%s: bridgesSnapshot(U=%d, u=%d) = %d, want %d", tt.name, tt.first, tt.last, got, tt.want)
+		}
+	}
+}
+
+// depthRecorder keeps the books passed to OnDepthUpdate
+type depthRecorder struct {
+	books []*OrderBook
+}
+
+func (r *depthRecorder) OnDepthUpdate(symbol string, book *OrderBook) {
+	r.books = append(r.books, book)
+}
+func (r *depthRecorder) OnError(err error) {}
+
+func newTestDepthSync() (*depthSync, *depthRecorder) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	recorder := &depthRecorder{}
+	return &depthSync{client: &BinanceClient{logger: logger}, symbol: "BTCUSDT", handler: recorder}, recorder
+}
+
+func depthEvent(first, last, prev int64) *futures.WsDepthEvent {
+	return &futures.WsDepthEvent{Symbol: "BTCUSDT", FirstUpdateID: first, LastUpdateID: last, PrevLastUpdateID: prev}
+}
+
+func TestDepthSyncSeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		buffered []*futures.WsDepthEvent
+		ok       bool // The snapshot is usable
+		synced   bool
+		lastID   int64 // Update ID of the book once seeded
+		retained int   // Events kept for the next attempt
+	}{
+		{
+			name:     "buffered event bridges",
+			buffered: []*futures.WsDepthEvent{depthEvent(90, 99, 89), depthEvent(100, 105, 99), depthEvent(106, 110, 105)},
+			ok:       true,
+			synced:   true,
+			lastID:   110,
+		},
+		{
+			name:     "no buffered event reaches the snapshot",
+			buffered: []*futures.WsDepthEvent{depthEvent(90, 99, 89)},
+			ok:       true,
+			lastID:   101,
+		},
+		{
+			name:     "snapshot older than the buffer",
+			buffered: []*futures.WsDepthEvent{depthEvent(120, 125, 119)},
+			retained: 1,
+		},
+		{
+			name:     "gap in the buffer",
+			buffered: []*futures.WsDepthEvent{depthEvent(100, 105, 99), depthEvent(108, 110, 107)},
+			retained: 1,
+		},
+	}
+	for _, tt := range tests {
+		d, _ := newTestDepthSync()
+		d.buffer = tt.buffered
+		d.resyncing = true
+
+		book, ok := d.seed(&futures.DepthResponse{LastUpdateID: 101})
+		if ok != tt.ok || d.synced != tt.synced || (book != nil) != tt.synced {
+			t.Errorf("%s: ok = %v, synced = %v, book = %v; want %v, %v", tt.name, ok, d.synced, book != nil, tt.ok, tt.synced)
+			continue
+		}
+		if !ok {
+			if d.book != nil || len(d.buffer) != tt.retained {
+				t.Errorf("%s: kept a book or %d events, want none and %d", tt.name, len(d.buffer), tt.retained)
+			}
+			continue
+		}
+		if d.book.lastUpdateID != tt.lastID || d.resyncing || len(d.buffer) != 0 {
+			t.Errorf("%s: book at %d, resyncing = %v, %d buffered; want %d, false, 0",
+				tt.name, d.book.lastUpdateID, d.resyncing, len(d.buffer), tt.lastID)
+		}
+	}
+}
+
+func TestDepthSyncOnEvent(t *testing.T) {
+	// A cancelled context stops the REST re-seed a gap starts before it fetches anything
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		event   *futures.WsDepthEvent
+		synced  bool
+		lastID  int64 // 0 when the book was dropped for a re-seed
+		updates int   // Books passed to the handler so far
+	}{
+		{"already in the snapshot", depthEvent(95, 100, 94), false, 101, 0},
+		{"first event bridges", depthEvent(100, 103, 99), true, 103, 1},
+		{"next in sequence", depthEvent(104, 106, 103), true, 106, 2},
+		{"gap", depthEvent(110, 112, 109), false, 0, 2},
+		{"buffered while re-seeding", depthEvent(113, 115, 112), false, 0, 2},
+	}
+
+	// Seeded from a snapshot at 101 that no buffered event reached
+	d, recorder := newTestDepthSync()
+	d.book = newLocalOrderBook("BTCUSDT", 101, nil, nil)
+	for _, tt := range tests {
+		d.onEvent(ctx, tt.event)
+
+		lastID := int64(0)
+		if d.book != nil {
+			lastID = d.book.lastUpdateID
+		}
+		if d.synced != tt.synced || lastID != tt.lastID || len(recorder.books) != tt.updates {
+			t.Fatalf("%s: synced = %v, book at %d, %d updates; want %v, %d, %d",
+				tt.name, d.synced, lastID, len(recorder.books), tt.synced, tt.lastID, tt.updates)
+		}
+	}
+	// The events since the gap wait for the next snapshot
+	if len(d.buffer) != 2 || d.buffer[0].FirstUpdateID != 110 {
+		t.Errorf("buffered %d events, want the 2 since the gap", len(d.buffer))
+	}
+
+	// A snapshot older than the first live event is replaced
+	d, recorder = newTestDepthSync()
+	d.book = newLocalOrderBook("BTCUSDT", 101, nil, nil)
+	d.onEvent(ctx, depthEvent(105, 108, 104))
+	if d.book != nil || d.synced || len(d.buffer) != 1 || len(recorder.books) != 0 {
+		t.Errorf("stale snapshot kept: book = %v, synced = %v, %d buffered", d.book != nil, d.synced, len(d.buffer))
+	}
+}
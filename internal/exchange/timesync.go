@@ -0,0 +1,101 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/adshao/go-binance/v2/futures"
+	"github.com/sirupsen/logrus"
+)
+
+// Binance rejects signed requests whose timestamp is outside recvWindow of its clock
+const timestampErrorCode = -1021
+
+// serverTimeSyncer updates a client's server-time offset. The go-binance clients subtract
+// TimeOffset from the local clock when signing requests.
+type serverTimeSyncer func(ctx context.Context) (int64, error)
+
+// syncServerTime measures the local clock's offset from the exchange once
+func syncServerTime(ctx context.Context, name string, sync serverTimeSyncer, recvWindowMs int64, logger *logrus.Logger) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	offset, err := sync(ctx)
+	if err != nil {
+		return err
+	}
+
+	if recvWindowMs > 0 && abs64(offset) > recvWindowMs/2 {
+		logger.Warnf("Local clock is %dms off %s server time; compensating, but consider fixing host time sync", offset, name)
+	} else {
+		logger.Debugf("Synced %s server time, offset %dms", name, offset)
+	}
+	return nil
+}
+
+// runServerTimeSync resyncs the offset periodically for the life of the process, so
+// drift on hosts without NTP never reaches recvWindow
+func runServerTimeSync(name string, sync serverTimeSyncer, interval time.Duration, recvWindowMs int64, logger *logrus.Logger) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := syncServerTime(context.Background(), name, sync, recvWindowMs, logger); err != nil {
+			logger.Warnf("Failed to sync %s server time: %v", name, err)
+		}
+	}
+}
+
+// isTimestampError reports whether the exchange rejected a request for its timestamp
+func isTimestampError(err error) bool {
+	var apiErr *common.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == timestampErrorCode
+}
+
+// signedOpts returns the request options for signed futures endpoints
+func (b *BinanceClient) signedOpts() []futures.RequestOption {
+	if b.config.RecvWindowMs <= 0 {
+		return nil
+	}
+	return []futures.RequestOption{futures.WithRecvWindow(b.config.RecvWindowMs)}
+}
+
+// retryAfterTimeSync runs a signed request and, if it was rejected for its timestamp,
+// resyncs server time and retries it once. Rejected requests are not executed, so the
+// retry is safe.
+func retryAfterTimeSync(ctx context.Context, name string, sync serverTimeSyncer, recvWindowMs int64, logger *logrus.Logger, call func() error) error {
+	err := call()
+	if !isTimestampError(err) {
+		return err
+	}
+
+	logger.Warnf("Binance %s request rejected for timestamp, resyncing server time: %v", name, err)
+	if syncErr := syncServerTime(ctx, name, sync, recvWindowMs, logger); syncErr != nil {
+		logger.Warnf("Failed to sync %s server time: %v", name, syncErr)
+		return err
+	}
+	return call()
+}
+
+// withTimeSync runs a signed futures request, retrying once after a timestamp rejection
+func (b *BinanceClient) withTimeSync(ctx context.Context, call func() error) error {
+	return retryAfterTimeSync(ctx, "futures", b.syncTime, b.config.RecvWindowMs, b.logger, call)
+}
+
+// syncTime updates the futures client's server-time offset
+func (b *BinanceClient) syncTime(ctx context.Context) (int64, error) {
+	return b.client.NewSetServerTimeService().Do(ctx)
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
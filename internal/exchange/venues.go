@@ -0,0 +1,149 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PriceVenue is a read-only view of another exchange's perpetual contracts, used to compare
+// prices across venues. Symbols use the Binance naming (e.g. BTCUSDT).
+type PriceVenue interface {
+	Name() string
+	GetSymbolPrice(ctx context.Context, symbol string) (float64, error)
+}
+
+// NewPriceVenue returns the adapter for a venue name
+func NewPriceVenue(name string, timeout time.Duration) (PriceVenue, error) {
+	httpClient := &http.Client{Timeout: timeout}
+
+	switch name {
+	case "bybit":
+		return &BybitVenue{httpClient: httpClient}, nil
+	case "okx":
+		return &OKXVenue{httpClient: httpClient}, nil
+	default:
+		return nil, fmt.Errorf("unsupported price venue: %s", name)
+	}
+}
+
+// BybitVenue reads USDT perpetual prices from the Bybit public API
+type BybitVenue struct {
+	httpClient *http.Client
+}
+
+// Name returns the venue name
+func (b *BybitVenue) Name() string {
+	return "bybit"
+}
+
+type bybitTickersResponse struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+	Result  struct {
+		List []struct {
+			Symbol    string `json:"symbol"`
+			LastPrice string `json:"lastPrice"`
+		} `json:"list"`
+	} `json:"result"`
+}
+
+// GetSymbolPrice retrieves the last traded price of the linear perpetual
+func (b *BybitVenue) GetSymbolPrice(ctx context.Context, symbol string) (float64, error) {
+	query := url.Values{}
+	query.Set("category", "linear")
+	query.Set("symbol", symbol)
+
+	var data bybitTickersResponse
+	if err := getJSON(ctx, b.httpClient, "https://api.bybit.com/v5/market/tickers?"+query.Encode(), &data); err != nil {
+		return 0, fmt.Errorf("failed to get bybit price: %w", err)
+	}
+
+	if data.RetCode != 0 {
+		return 0, fmt.Errorf("failed to get bybit price: %s", data.RetMsg)
+	}
+	if len(data.Result.List) == 0 {
+		return 0, fmt.Errorf("no bybit price data for symbol %s", symbol)
+	}
+
+	return strconv.ParseFloat(data.Result.List[0].LastPrice, 64)
+}
+
+// OKXVenue reads USDT perpetual swap prices from the OKX public API
+type OKXVenue struct {
+	httpClient *http.Client
+}
+
+// Name returns the venue name
+func (o *OKXVenue) Name() string {
+	return "okx"
+}
+
+type okxTickerResponse struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data []struct {
+		InstID string `json:"instId"`
+		Last   string `json:"last"`
+	} `json:"data"`
+}
+
+// GetSymbolPrice retrieves the last traded price of the perpetual swap
+func (o *OKXVenue) GetSymbolPrice(ctx context.Context, symbol string) (float64, error) {
+	instrument, err := okxSwapInstrument(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	var data okxTickerResponse
+	if err := getJSON(ctx, o.httpClient, "https://www.okx.com/api/v5/market/ticker?instId="+url.QueryEscape(instrument), &data); err != nil {
+		return 0, fmt.Errorf("failed to get okx price: %w", err)
+	}
+
+	if data.Code != "0" {
+		return 0, fmt.Errorf("failed to get okx price: %s", data.Msg)
+	}
+	if len(data.Data) == 0 {
+		return 0, fmt.Errorf("no okx price data for symbol %s", symbol)
+	}
+
+	return strconv.ParseFloat(data.Data[0].Last, 64)
+}
+
+// okxSwapInstrument converts a Binance symbol such as BTCUSDT to the OKX swap BTC-USDT-SWAP
+func okxSwapInstrument(symbol string) (string, error) {
+	for _, quote := range []string{"USDT", "USDC"} {
+		if base := strings.TrimSuffix(symbol, quote); base != symbol && base != "" {
+			return base + "-" + quote + "-SWAP", nil
+		}
+	}
+	return "", fmt.Errorf("cannot map symbol %s to an okx swap", symbol)
+}
+
+// getJSON performs a GET request and decodes the JSON response into out
+func getJSON(ctx context.Context, httpClient *http.Client, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Item is a news headline or sentiment reading returned by a source
+type Item struct {
+	ID          string
+	Title       string
+	URL         string
+	PublishedAt time.Time
+	Currencies  []string // Base asset tickers the source tagged the item with, if any
+	Score       *float64 // Source-provided score in [-1, 1]; nil means score the title locally
+}
+
+// Source fetches the latest items from a news or sentiment provider
+type Source interface {
+	Name() string
+	Fetch(ctx context.Context) ([]*Item, error)
+}
+
+// Well-known names used to match untagged headlines to base assets
+var assetAliases = map[string][]string{
+	"BTC":  {"bitcoin"},
+	"ETH":  {"ethereum", "ether"},
+	"BNB":  {"binance coin"},
+	"SOL":  {"solana"},
+	"XRP":  {"ripple"},
+	"ADA":  {"cardano"},
+	"DOGE": {"dogecoin"},
+	"DOT":  {"polkadot"},
+	"AVAX": {"avalanche"},
+	"LINK": {"chainlink"},
+	"LTC":  {"litecoin"},
+	"TRX":  {"tron"},
+}
+
+// Service polls the configured sources, stores scored items per symbol and keeps a
+// rolling window in memory for sentiment queries
+type Service struct {
+	config     config.FeedsConfig
+	repository database.Repository
+	sources    []Source
+	logger     *logrus.Logger
+
+	// Base asset (e.g. BTC) and matching pattern for every traded symbol
+	assets   map[string]string
+	patterns map[string]*regexp.Regexp
+
+	mu    sync.RWMutex
+	items map[string][]*models.SentimentItem
+}
+
+// NewService creates a feed service for the given trading symbols
+func NewService(cfg config.FeedsConfig, symbols []string, repository database.Repository, logger *logrus.Logger) *Service {
+	httpClient := &http.Client{Timeout: 15 * time.Second}
+
+	var sources []Source
+	if cfg.CryptoPanicToken != "" {
+		sources = append(sources, NewCryptoPanicSource(cfg.CryptoPanicToken, baseAssets(symbols), httpClient))
+	}
+	for _, url := range cfg.RSSURLs {
+		sources = append(sources, NewRSSSource(url, httpClient))
+	}
+
+	s := &Service{
+		config:     cfg,
+		repository: repository,
+		sources:    sources,
+		logger:     logger,
+		assets:     make(map[string]string, len(symbols)),
+		patterns:   make(map[string]*regexp.Regexp, len(symbols)),
+		items:      make(map[string][]*models.SentimentItem),
+	}
+
+	for _, symbol := range symbols {
+		asset := baseAsset(symbol)
+		s.assets[symbol] = asset
+
+		terms := []string{regexp.QuoteMeta(strings.ToLower(asset))}
+		for _, alias := range assetAliases[asset] {
+			terms = append(terms, regexp.QuoteMeta(alias))
+		}
+		s.patterns[symbol] = regexp.MustCompile(`\b(` + strings.Join(terms, "|") + `)\b`)
+	}
+
+	return s
+}
+
+// Start loads the recent window from the database and begins polling the sources
+func (s *Service) Start(ctx context.Context) error {
+	if len(s.sources) == 0 {
+		return fmt.Errorf("no feed sources configured")
+	}
+
+	since := time.Now().Add(-s.window())
+	for symbol := range s.assets {
+		items, err := s.repository.GetSentimentItems(symbol, since)
+		if err != nil {
+			return fmt.Errorf("failed to load sentiment items for %s: %w", symbol, err)
+		}
+		s.mu.Lock()
+		s.items[symbol] = items
+		s.mu.Unlock()
+	}
+
+	go s.pollLoop(ctx)
+
+	return nil
+}
+
+func (s *Service) pollLoop(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(s.config.PollIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	s.poll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.poll(ctx)
+		}
+	}
+}
+
+// poll fetches every source once and records new items
+func (s *Service) poll(ctx context.Context) {
+	for _, source := range s.sources {
+		items, err := source.Fetch(ctx)
+		if err != nil {
+			s.logger.Warnf("Failed to fetch %s feed: %v", source.Name(), err)
+			continue
+		}
+
+		added := 0
+		for _, item := range items {
+			added += s.ingest(source.Name(), item)
+		}
+
+		if added > 0 {
+			s.logger.Debugf("Ingested %d new items from %s feed", added, source.Name())
+		}
+	}
+
+	s.prune()
+}
+
+// ingest stores the item for every matching symbol and returns how many were new
+func (s *Service) ingest(source string, item *Item) int {
+	if item.PublishedAt.Before(time.Now().Add(-s.window())) {
+		return 0
+	}
+
+	score := ScoreText(item.Title)
+	if item.Score != nil {
+		score = *item.Score
+	}
+
+	added := 0
+	for _, symbol := range s.matchSymbols(item) {
+		if s.hasItem(symbol, source, item.ID) {
+			continue
+		}
+
+		record := &models.SentimentItem{
+			Symbol:      symbol,
+			Source:      source,
+			ExternalID:  item.ID,
+			Title:       item.Title,
+			URL:         item.URL,
+			Score:       score,
+			PublishedAt: item.PublishedAt,
+		}
+
+		if err := s.repository.SaveSentimentItem(record); err != nil {
+			s.logger.Errorf("Failed to save sentiment item: %v", err)
+			continue
+		}
+
+		s.mu.Lock()
+		s.items[symbol] = append(s.items[symbol], record)
+		s.mu.Unlock()
+		added++
+	}
+
+	return added
+}
+
+// matchSymbols returns the traded symbols an item refers to, using source tags when
+// available and falling back to ticker/name matching on the title
+func (s *Service) matchSymbols(item *Item) []string {
+	var symbols []string
+
+	if len(item.Currencies) > 0 {
+		for symbol, asset := range s.assets {
+			for _, currency := range item.Currencies {
+				if strings.EqualFold(currency, asset) {
+					symbols = append(symbols, symbol)
+					break
+				}
+			}
+		}
+		return symbols
+	}
+
+	title := strings.ToLower(item.Title)
+	for symbol, pattern := range s.patterns {
+		if pattern.MatchString(title) {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+func (s *Service) hasItem(symbol, source, id string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, existing := range s.items[symbol] {
+		if existing.Source == source && existing.ExternalID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// prune drops items that have left the rolling window
+func (s *Service) prune() {
+	cutoff := time.Now().Add(-s.window())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for symbol, items := range s.items {
+		kept := items[:0]
+		for _, item := range items {
+			if !item.PublishedAt.Before(cutoff) {
+				kept = append(kept, item)
+			}
+		}
+		s.items[symbol] = kept
+	}
+}
+
+// Sentiment returns the rolling sentiment score for a symbol in [-1, 1] and the number of
+// items it is based on. Newer items weigh more, halving every half_life_hours.
+func (s *Service) Sentiment(symbol string) (float64, int) {
+	now := time.Now()
+	cutoff := now.Add(-s.window())
+	halfLife := s.config.HalfLifeHours
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var weighted, totalWeight float64
+	count := 0
+	for _, item := range s.items[symbol] {
+		if item.PublishedAt.Before(cutoff) {
+			continue
+		}
+
+		weight := 1.0
+		if halfLife > 0 {
+			age := now.Sub(item.PublishedAt).Hours()
+			weight = math.Pow(0.5, age/halfLife)
+		}
+
+		weighted += item.Score * weight
+		totalWeight += weight
+		count++
+	}
+
+	if totalWeight == 0 {
+		return 0, 0
+	}
+	return weighted / totalWeight, count
+}
+
+// Headlines returns the titles of items for a symbol published within [from, to]
+func (s *Service) Headlines(symbol string, from, to time.Time) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var headlines []string
+	for _, item := range s.items[symbol] {
+		if item.PublishedAt.Before(from) || item.PublishedAt.After(to) {
+			continue
+		}
+		headlines = append(headlines, fmt.Sprintf("[%+.2f] %s", item.Score, item.Title))
+	}
+	return headlines
+}
+
+// BuyBlocked reports whether sentiment is negative enough to veto new long entries
+func (s *Service) BuyBlocked(symbol string) (bool, float64) {
+	score, count := s.Sentiment(symbol)
+	if count < s.config.MinItems {
+		return false, score
+	}
+	return score < s.config.MinBuySentiment, score
+}
+
+func (s *Service) window() time.Duration {
+	return time.Duration(s.config.WindowHours) * time.Hour
+}
+
+// baseAsset strips the quote asset from a futures symbol (BTCUSDT -> BTC, BTCUSD_PERP -> BTC)
+func baseAsset(symbol string) string {
+	if i := strings.Index(symbol, "_"); i > 0 {
+		symbol = symbol[:i]
+	}
+	for _, quote := range []string{"USDT", "USDC", "BUSD", "USD"} {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote)
+		}
+	}
+	return symbol
+}
+
+func baseAssets(symbols []string) []string {
+	assets := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		assets = append(assets, baseAsset(symbol))
+	}
+	return assets
+}
@@ -0,0 +1,44 @@
+package feeds
+
+import (
+	"strings"
+	"unicode"
+)
+
+var positiveWords = map[string]bool{
+	"surge": true, "surges": true, "soar": true, "soars": true, "rally": true, "rallies": true,
+	"gain": true, "gains": true, "jump": true, "jumps": true, "bullish": true, "record": true,
+	"high": true, "approval": true, "approved": true, "adopt": true, "adoption": true,
+	"partnership": true, "upgrade": true, "breakout": true, "inflows": true, "recover": true,
+	"recovers": true, "rebound": true, "rebounds": true, "launch": true, "launches": true,
+}
+
+var negativeWords = map[string]bool{
+	"crash": true, "crashes": true, "plunge": true, "plunges": true, "drop": true, "drops": true,
+	"fall": true, "falls": true, "bearish": true, "hack": true, "hacked": true, "exploit": true,
+	"lawsuit": true, "sue": true, "sues": true, "ban": true, "bans": true, "fraud": true,
+	"scam": true, "liquidation": true, "liquidations": true, "outflows": true, "selloff": true,
+	"delist": true, "delisting": true, "investigation": true, "low": true, "dump": true,
+}
+
+// ScoreText returns a simple lexicon-based sentiment score in [-1, 1] for a headline
+func ScoreText(text string) float64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-'
+	})
+
+	positive, negative := 0, 0
+	for _, word := range words {
+		if positiveWords[word] {
+			positive++
+		}
+		if negativeWords[word] {
+			negative++
+		}
+	}
+
+	if positive+negative == 0 {
+		return 0
+	}
+	return float64(positive-negative) / float64(positive+negative)
+}
@@ -0,0 +1,186 @@
+package feeds
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const cryptoPanicURL = "https://cryptopanic.com/api/v1/posts/"
+
+// CryptoPanicSource reads tagged headlines and community votes from the CryptoPanic API
+type CryptoPanicSource struct {
+	token      string
+	currencies []string
+	httpClient *http.Client
+}
+
+// NewCryptoPanicSource creates a CryptoPanic source for the given base assets
+func NewCryptoPanicSource(token string, currencies []string, httpClient *http.Client) *CryptoPanicSource {
+	return &CryptoPanicSource{token: token, currencies: currencies, httpClient: httpClient}
+}
+
+// Name returns the source name
+func (c *CryptoPanicSource) Name() string {
+	return "cryptopanic"
+}
+
+type cryptoPanicResponse struct {
+	Results []struct {
+		ID          int64     `json:"id"`
+		Title       string    `json:"title"`
+		URL         string    `json:"url"`
+		PublishedAt time.Time `json:"published_at"`
+		Currencies  []struct {
+			Code string `json:"code"`
+		} `json:"currencies"`
+		Votes struct {
+			Positive int `json:"positive"`
+			Negative int `json:"negative"`
+			Liked    int `json:"liked"`
+			Disliked int `json:"disliked"`
+			Toxic    int `json:"toxic"`
+		} `json:"votes"`
+	} `json:"results"`
+}
+
+// Fetch returns the latest public posts for the configured currencies
+func (c *CryptoPanicSource) Fetch(ctx context.Context) ([]*Item, error) {
+	query := url.Values{}
+	query.Set("auth_token", c.token)
+	query.Set("public", "true")
+	query.Set("currencies", strings.Join(c.currencies, ","))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cryptoPanicURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var data cryptoPanicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	items := make([]*Item, 0, len(data.Results))
+	for _, post := range data.Results {
+		item := &Item{
+			ID:          fmt.Sprintf("%d", post.ID),
+			Title:       post.Title,
+			URL:         post.URL,
+			PublishedAt: post.PublishedAt,
+		}
+		for _, currency := range post.Currencies {
+			item.Currencies = append(item.Currencies, currency.Code)
+		}
+
+		// Community votes are a better signal than keywords when there are any
+		positive := post.Votes.Positive + post.Votes.Liked
+		negative := post.Votes.Negative + post.Votes.Disliked + post.Votes.Toxic
+		if total := positive + negative; total > 0 {
+			score := float64(positive-negative) / float64(total)
+			item.Score = &score
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// RSSSource reads headlines from an RSS 2.0 news feed; items are scored from their titles
+type RSSSource struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewRSSSource creates a new RSS source
+func NewRSSSource(url string, httpClient *http.Client) *RSSSource {
+	return &RSSSource{url: url, httpClient: httpClient}
+}
+
+// Name returns the source name
+func (r *RSSSource) Name() string {
+	if u, err := url.Parse(r.url); err == nil && u.Host != "" {
+		return "rss:" + u.Host
+	}
+	return "rss"
+}
+
+type rssDocument struct {
+	Channel struct {
+		Items []struct {
+			Title   string `xml:"title"`
+			Link    string `xml:"link"`
+			GUID    string `xml:"guid"`
+			PubDate string `xml:"pubDate"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+// Fetch returns the items currently in the feed
+func (r *RSSSource) Fetch(ctx context.Context) ([]*Item, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var doc rssDocument
+	if err := xml.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode feed: %w", err)
+	}
+
+	items := make([]*Item, 0, len(doc.Channel.Items))
+	for _, entry := range doc.Channel.Items {
+		id := entry.GUID
+		if id == "" {
+			id = entry.Link
+		}
+		if id == "" {
+			continue
+		}
+
+		items = append(items, &Item{
+			ID:          id,
+			Title:       strings.TrimSpace(entry.Title),
+			URL:         entry.Link,
+			PublishedAt: parseRSSTime(entry.PubDate),
+		})
+	}
+
+	return items, nil
+}
+
+// parseRSSTime parses the common RSS date layouts, defaulting to now when unparseable
+func parseRSSTime(value string) time.Time {
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, time.RFC3339} {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
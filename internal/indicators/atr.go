@@ -0,0 +1,57 @@
+package indicators
+
+import "contract_playground/pkg/utils"
+
+// ATR is the Average True Range with Wilder's smoothing; it matches utils.CalculateATR over
+// the same candles
+type ATR struct {
+	Series
+	period    int
+	prevClose float64
+	value     float64
+	count     int
+}
+
+// NewATR creates an ATR over period candles
+func NewATR(period int) *ATR {
+	return &ATR{period: period}
+}
+
+// Update adds a candle and returns the ATR, or 0 until period true ranges were seen
+func (a *ATR) Update(high, low, close float64) float64 {
+	a.count++
+
+	// The first candle has no previous close; its range is high - low
+	prevClose := 0.0
+	if a.count > 1 {
+		prevClose = a.prevClose
+	}
+	tr := utils.TrueRange(high, low, prevClose)
+	a.prevClose = close
+
+	n := float64(a.period)
+	if a.count <= a.period {
+		a.value += tr / n
+	} else {
+		a.value = (a.value*(n-1) + tr) / n
+	}
+
+	if !a.Ready() {
+		return 0
+	}
+	a.push(a.value)
+	return a.value
+}
+
+// Value returns the current ATR, or 0 until period true ranges were seen
+func (a *ATR) Value() float64 {
+	if !a.Ready() {
+		return 0
+	}
+	return a.value
+}
+
+// Ready reports whether period true ranges were seen
+func (a *ATR) Ready() bool {
+	return a.count >= a.period
+}
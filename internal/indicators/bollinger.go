@@ -0,0 +1,52 @@
+package indicators
+
+import "math"
+
+// Bollinger are Bollinger Bands: a simple moving average with bands a number of population
+// standard deviations above and below it
+type Bollinger struct {
+	sma   *SMA
+	k     float64
+	sumSq float64
+
+	// Output series of the middle, upper and lower bands
+	Middle Series
+	Upper  Series
+	Lower  Series
+}
+
+// NewBollinger creates Bollinger Bands over period closes, k standard deviations wide
+func NewBollinger(period int, k float64) *Bollinger {
+	return &Bollinger{sma: NewSMA(period), k: k}
+}
+
+// Update adds a close and returns the middle, upper and lower bands, which are 0 until
+// period closes were seen
+func (b *Bollinger) Update(close float64) (middle, upper, lower float64) {
+	// The SMA window slot about to be overwritten holds the value leaving the window
+	leaving := b.sma.window[b.sma.next]
+	b.sumSq += close*close - leaving*leaving
+	middle = b.sma.Update(close)
+	if !b.sma.Ready() {
+		return 0, 0, 0
+	}
+
+	variance := b.sumSq/float64(b.sma.period) - middle*middle
+	std := math.Sqrt(math.Max(0, variance))
+	upper, lower = middle+b.k*std, middle-b.k*std
+
+	b.Middle.push(middle)
+	b.Upper.push(upper)
+	b.Lower.push(lower)
+	return middle, upper, lower
+}
+
+// Value returns the latest middle, upper and lower bands
+func (b *Bollinger) Value() (middle, upper, lower float64) {
+	return b.Middle.Last(0), b.Upper.Last(0), b.Lower.Last(0)
+}
+
+// Ready reports whether period closes were seen
+func (b *Bollinger) Ready() bool {
+	return b.sma.Ready()
+}
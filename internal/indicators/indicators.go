@@ -0,0 +1,88 @@
+// Package indicators provides stateful technical indicators that are updated one closed
+// kline at a time, so strategies tracking many symbols don't recompute from full history
+// on every cycle. Every indicator keeps the series of its recent outputs.
+package indicators
+
+import (
+	"sync"
+
+	"contract_playground/internal/exchange"
+)
+
+// historySize is the number of recent outputs each indicator keeps
+const historySize = 500
+
+// Series is the recent output history of an indicator, oldest first
+type Series struct {
+	values []float64
+}
+
+func (s *Series) push(value float64) {
+	s.values = append(s.values, value)
+	// Trim in chunks so appends stay amortized O(1)
+	if len(s.values) >= 2*historySize {
+		s.values = append(s.values[:0], s.values[len(s.values)-historySize:]...)
+	}
+}
+
+// Len returns the number of values kept
+func (s *Series) Len() int {
+	if len(s.values) > historySize {
+		return historySize
+	}
+	return len(s.values)
+}
+
+// Last returns the value n updates ago (0 = latest), or 0 when it is not kept
+func (s *Series) Last(n int) float64 {
+	if n < 0 || n >= s.Len() {
+		return 0
+	}
+	return s.values[len(s.values)-1-n]
+}
+
+// Values returns a copy of the kept values, oldest first
+func (s *Series) Values() []float64 {
+	values := make([]float64, s.Len())
+	copy(values, s.values[len(s.values)-len(values):])
+	return values
+}
+
+// Tracker remembers the last closed kline applied per symbol so that each kline updates the
+// indicators exactly once, however often the strategy is evaluated
+type Tracker struct {
+	mu   sync.Mutex
+	last map[string]int64
+}
+
+// NewTracker creates a kline tracker
+func NewTracker() *Tracker {
+	return &Tracker{last: make(map[string]int64)}
+}
+
+// Closed returns the closed klines of the window not applied yet for the symbol and marks
+// them applied. The last kline of the window is treated as still forming.
+func (t *Tracker) Closed(symbol string, klines []*exchange.KlineData) []*exchange.KlineData {
+	if len(klines) < 2 {
+		return nil
+	}
+	closed := klines[:len(klines)-1]
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	last := t.last[symbol]
+	start := len(closed)
+	for start > 0 && closed[start-1].OpenTime > last {
+		start--
+	}
+	t.last[symbol] = closed[len(closed)-1].OpenTime
+	return closed[start:]
+}
+
+// Reset forgets the symbol so the next window is applied in full
+func (t *Tracker) Reset(symbol string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.last, symbol)
+}
@@ -0,0 +1,55 @@
+package indicators
+
+// MACD is the Moving Average Convergence Divergence: the difference of a fast and a slow EMA,
+// its signal EMA and the histogram between them
+type MACD struct {
+	fast   *EMA
+	slow   *EMA
+	signal *EMA
+
+	// Output series of the MACD line, signal line and histogram
+	Line      Series
+	Signal    Series
+	Histogram Series
+}
+
+// NewMACD creates a MACD (typically 12, 26, 9)
+func NewMACD(fastPeriod, slowPeriod, signalPeriod int) *MACD {
+	return &MACD{
+		fast:   NewEMA(fastPeriod),
+		slow:   NewEMA(slowPeriod),
+		signal: NewEMA(signalPeriod),
+	}
+}
+
+// Update adds a close and returns the MACD line, signal line and histogram, which are 0
+// until the slow and signal averages are ready
+func (m *MACD) Update(close float64) (line, signal, histogram float64) {
+	fast := m.fast.Update(close)
+	slow := m.slow.Update(close)
+	if !m.slow.Ready() {
+		return 0, 0, 0
+	}
+
+	line = fast - slow
+	signal = m.signal.Update(line)
+	if !m.signal.Ready() {
+		return 0, 0, 0
+	}
+
+	histogram = line - signal
+	m.Line.push(line)
+	m.Signal.push(signal)
+	m.Histogram.push(histogram)
+	return line, signal, histogram
+}
+
+// Value returns the latest MACD line, signal line and histogram
+func (m *MACD) Value() (line, signal, histogram float64) {
+	return m.Line.Last(0), m.Signal.Last(0), m.Histogram.Last(0)
+}
+
+// Ready reports whether the MACD has produced a value
+func (m *MACD) Ready() bool {
+	return m.signal.Ready()
+}
@@ -0,0 +1,90 @@
+package indicators
+
+// SMA is a simple moving average
+type SMA struct {
+	Series
+	period int
+	window []float64
+	next   int
+	sum    float64
+	count  int
+}
+
+// NewSMA creates a simple moving average over period values
+func NewSMA(period int) *SMA {
+	return &SMA{period: period, window: make([]float64, period)}
+}
+
+// Update adds a value and returns the average, or 0 until period values were seen
+func (s *SMA) Update(value float64) float64 {
+	s.sum += value - s.window[s.next]
+	s.window[s.next] = value
+	s.next = (s.next + 1) % s.period
+	if s.count < s.period {
+		s.count++
+	}
+
+	result := s.Value()
+	if s.Ready() {
+		s.push(result)
+	}
+	return result
+}
+
+// Value returns the current average, or 0 until period values were seen
+func (s *SMA) Value() float64 {
+	if !s.Ready() {
+		return 0
+	}
+	return s.sum / float64(s.period)
+}
+
+// Ready reports whether period values were seen
+func (s *SMA) Ready() bool {
+	return s.count >= s.period
+}
+
+// EMA is an exponential moving average seeded with the simple average of its first period values
+type EMA struct {
+	Series
+	period     int
+	multiplier float64
+	value      float64
+	seed       float64
+	count      int
+}
+
+// NewEMA creates an exponential moving average over period values
+func NewEMA(period int) *EMA {
+	return &EMA{period: period, multiplier: 2.0 / (float64(period) + 1.0)}
+}
+
+// Update adds a value and returns the average, or 0 until period values were seen
+func (e *EMA) Update(value float64) float64 {
+	e.count++
+	switch {
+	case e.count < e.period:
+		e.seed += value
+		return 0
+	case e.count == e.period:
+		e.value = (e.seed + value) / float64(e.period)
+	default:
+		e.value = (value-e.value)*e.multiplier + e.value
+	}
+
+	e.push(e.value)
+	return e.value
+}
+
+// Value returns the current average, or 0 until period values were seen
+func (e *EMA) Value() float64 {
+	if !e.Ready() {
+		return 0
+	}
+	return e.value
+}
+
+// Ready reports whether period values were seen
+func (e *EMA) Ready() bool {
+	return e.count >= e.period
+}
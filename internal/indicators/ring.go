@@ -0,0 +1,57 @@
+package indicators
+
+// Ring is a fixed-capacity window of the most recent values. Pushing into a full ring
+// overwrites the oldest value, so keeping a rolling window costs no allocation or copying.
+type Ring struct {
+	values []float64
+	next   int // Slot the next value is written to
+	count  int
+}
+
+// NewRing creates a ring keeping the last capacity values
+func NewRing(capacity int) *Ring {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Ring{values: make([]float64, capacity)}
+}
+
+// Push adds a value, dropping the oldest one when the ring is full
+func (r *Ring) Push(value float64) {
+	r.values[r.next] = value
+	r.next = (r.next + 1) % len(r.values)
+	if r.count < len(r.values) {
+		r.count++
+	}
+}
+
+// Len returns the number of values kept
+func (r *Ring) Len() int {
+	return r.count
+}
+
+// Cap returns the number of values the ring keeps at most
+func (r *Ring) Cap() int {
+	return len(r.values)
+}
+
+// Last returns the value n pushes ago (0 = latest), or 0 when it is not kept
+func (r *Ring) Last(n int) float64 {
+	if n < 0 || n >= r.count {
+		return 0
+	}
+	i := r.next - 1 - n
+	if i < 0 {
+		i += len(r.values)
+	}
+	return r.values[i]
+}
+
+// Values returns a copy of the kept values, oldest first
+func (r *Ring) Values() []float64 {
+	values := make([]float64, r.count)
+	for i := range values {
+		values[i] = r.Last(r.count - 1 - i)
+	}
+	return values
+}
@@ -0,0 +1,68 @@
+package indicators
+
+// RSI is the Relative Strength Index with Wilder's smoothing
+type RSI struct {
+	Series
+	period    int
+	prevClose float64
+	avgGain   float64
+	avgLoss   float64
+	count     int // Closes seen
+}
+
+// NewRSI creates an RSI over period price changes
+func NewRSI(period int) *RSI {
+	return &RSI{period: period}
+}
+
+// Update adds a close and returns the RSI, or 50 until period changes were seen
+func (r *RSI) Update(close float64) float64 {
+	r.count++
+	if r.count == 1 {
+		r.prevClose = close
+		return 50
+	}
+
+	change := close - r.prevClose
+	r.prevClose = close
+	gain, loss := 0.0, 0.0
+	if change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+
+	n := float64(r.period)
+	if r.count <= r.period+1 {
+		// Simple average over the first period changes
+		r.avgGain += gain / n
+		r.avgLoss += loss / n
+	} else {
+		r.avgGain = (r.avgGain*(n-1) + gain) / n
+		r.avgLoss = (r.avgLoss*(n-1) + loss) / n
+	}
+
+	if !r.Ready() {
+		return 50
+	}
+
+	value := r.Value()
+	r.push(value)
+	return value
+}
+
+// Value returns the current RSI, or 50 until period changes were seen
+func (r *RSI) Value() float64 {
+	if !r.Ready() {
+		return 50
+	}
+	if r.avgLoss == 0 {
+		return 100
+	}
+	return 100 - 100/(1+r.avgGain/r.avgLoss)
+}
+
+// Ready reports whether period changes were seen
+func (r *RSI) Ready() bool {
+	return r.count > r.period
+}
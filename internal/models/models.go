@@ -15,6 +15,8 @@ type TradingConfig struct {
 	TakeProfit  float64   `gorm:"not null" json:"take_profit"`
 	Leverage    int       `gorm:"default:1" json:"leverage"`
 	RiskPercent float64   `gorm:"not null" json:"risk_percent"`
+	MaxDailyLoss float64  `gorm:"default:0" json:"max_daily_loss"`
+	Version     uint      `gorm:"not null;default:1" json:"version"` // Bumped on every change
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -23,12 +25,18 @@ type TradingConfig struct {
 type Order struct {
 	ID              uint      `gorm:"primaryKey" json:"id"`
 	ExchangeOrderID string    `gorm:"uniqueIndex;not null" json:"exchange_order_id"`
+	ClientOrderID   string    `gorm:"size:64;index" json:"client_order_id"` // Deterministic for strategy signals, used to skip duplicates
 	Symbol          string    `gorm:"not null;index" json:"symbol"`
 	Side            string    `gorm:"not null" json:"side"` // BUY, SELL
 	Type            string    `gorm:"not null" json:"type"` // MARKET, LIMIT, STOP_MARKET
 	Status          string    `gorm:"not null;index" json:"status"` // NEW, PARTIALLY_FILLED, FILLED, CANCELED, REJECTED
 	Quantity        float64   `gorm:"not null" json:"quantity"`
 	Price           float64   `json:"price"`
+	ExpectedPrice   float64   `gorm:"default:0" json:"expected_price"`  // Signal price the order was sized on
+	SubmittedPrice  float64   `gorm:"default:0" json:"submitted_price"` // Limit price sent; 0 for market orders
+	AvgFillPrice    float64   `gorm:"default:0" json:"avg_fill_price"`
+	SubmittedAt     *time.Time `json:"submitted_at"`
+	FillLatencyMs   int64     `gorm:"default:0" json:"fill_latency_ms"` // From submission to the first fill
 	StopPrice       float64   `json:"stop_price"`
 	ExecutedQty     float64   `gorm:"default:0" json:"executed_qty"`
 	CumulativeQuote float64   `gorm:"default:0" json:"cumulative_quote"`
@@ -38,8 +46,14 @@ type Order struct {
 	ReduceOnly      bool      `gorm:"default:false" json:"reduce_only"`
 	ClosePosition   bool      `gorm:"default:false" json:"close_position"`
 	PositionSide    string    `json:"position_side"` // BOTH, LONG, SHORT
+	PositionID      *uint     `gorm:"index" json:"position_id"` // Set on child orders managed for a position
+	Role            string    `gorm:"size:20" json:"role"`      // Order role: TP1, TP2, ..., TRAIL, SO1, SO2, ..., GRID
 	Strategy        string    `json:"strategy"`
 	Notes           string    `json:"notes"`
+	RejectReason    string    `gorm:"size:32" json:"reject_reason,omitempty"`  // Rejection class of a REJECTED order, e.g. min_notional
+	RejectMessage   string    `gorm:"size:255" json:"reject_message,omitempty"` // Error returned by the exchange
+	Remediations    int       `gorm:"default:0" json:"remediations"`            // Automatic fixes tried before the order was given up
+	Version         uint      `gorm:"not null;default:1" json:"version"` // Bumped on every update; stale updates fail
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
@@ -52,7 +66,8 @@ type Position struct {
 	Size           float64   `gorm:"not null" json:"size"`
 	EntryPrice     float64   `gorm:"not null" json:"entry_price"`
 	MarkPrice      float64   `json:"mark_price"`
-	UnrealizedPnL  float64   `gorm:"default:0" json:"unrealized_pnl"`
+	LastPrice      float64   `json:"last_price"`
+	UnrealizedPnL  float64   `gorm:"column:unrealized_pnl;default:0" json:"unrealized_pnl"`
 	Percentage     float64   `gorm:"default:0" json:"percentage"`
 	Leverage       int       `gorm:"default:1" json:"leverage"`
 	Margin         float64   `gorm:"default:0" json:"margin"`
@@ -60,9 +75,16 @@ type Position struct {
 	Status         string    `gorm:"not null;default:'OPEN'" json:"status"` // OPEN, CLOSED
 	OpenTime       time.Time `gorm:"not null" json:"open_time"`
 	CloseTime      *time.Time `json:"close_time"`
-	ClosedPnL      float64   `gorm:"default:0" json:"closed_pnl"`
+	ClosedPnL      float64   `gorm:"column:closed_pnl;default:0" json:"closed_pnl"`
+	Commission     float64   `gorm:"default:0" json:"commission"`  // Signed commission income; negative when paid
+	FundingFee     float64   `gorm:"default:0" json:"funding_fee"` // Signed funding income while the position was open
+	NetPnL         float64   `gorm:"column:net_pnl;default:0" json:"net_pnl"`     // ClosedPnL plus commission and funding
+	AddOns         int       `gorm:"default:0" json:"add_ons"`       // Number of pyramiding adds
+	LastAddPrice   float64   `gorm:"default:0" json:"last_add_price"` // Fill price of the most recent entry or add
 	Strategy       string    `json:"strategy"`
 	Notes          string    `json:"notes"`
+	TakeProfitPlan string    `gorm:"type:text" json:"take_profit_plan"` // JSON ladder: levels, trailing %, filled levels
+	Version        uint      `gorm:"not null;default:1" json:"version"` // Bumped on every update; stale updates fail
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 }
@@ -79,8 +101,10 @@ type Trade struct {
 	QuoteQty        float64   `gorm:"not null" json:"quote_qty"`
 	Commission      float64   `gorm:"default:0" json:"commission"`
 	CommissionAsset string    `json:"commission_asset"`
-	RealizedPnL     float64   `gorm:"default:0" json:"realized_pnl"`
+	RealizedPnL     float64   `gorm:"column:realized_pnl;default:0" json:"realized_pnl"`
 	IsMaker         bool      `gorm:"default:false" json:"is_maker"`
+	ExpectedPrice   float64   `gorm:"default:0" json:"expected_price"` // Reference price when the order was sent
+	SlippageBps     float64   `gorm:"default:0" json:"slippage_bps"`   // Positive when filled worse than expected
 	PositionSide    string    `json:"position_side"`
 	Strategy        string    `json:"strategy"`
 	TradeTime       time.Time `gorm:"not null" json:"trade_time"`
@@ -95,7 +119,7 @@ type Trade struct {
 type Account struct {
 	ID                uint      `gorm:"primaryKey" json:"id"`
 	TotalWalletBalance float64  `gorm:"default:0" json:"total_wallet_balance"`
-	TotalUnrealizedPnL float64  `gorm:"default:0" json:"total_unrealized_pnl"`
+	TotalUnrealizedPnL float64  `gorm:"column:total_unrealized_pnl;default:0" json:"total_unrealized_pnl"`
 	TotalMarginBalance float64  `gorm:"default:0" json:"total_margin_balance"`
 	TotalPositionIM    float64  `gorm:"default:0" json:"total_position_im"`
 	TotalOpenOrderIM   float64  `gorm:"default:0" json:"total_open_order_im"`
@@ -116,14 +140,14 @@ type Balance struct {
 	AccountID         uint      `gorm:"not null;index" json:"account_id"`
 	Asset             string    `gorm:"not null;index" json:"asset"`
 	WalletBalance     float64   `gorm:"default:0" json:"wallet_balance"`
-	UnrealizedPnL     float64   `gorm:"default:0" json:"unrealized_pnl"`
+	UnrealizedPnL     float64   `gorm:"column:unrealized_pnl;default:0" json:"unrealized_pnl"`
 	MarginBalance     float64   `gorm:"default:0" json:"margin_balance"`
 	MaintMargin       float64   `gorm:"default:0" json:"maint_margin"`
 	InitialMargin     float64   `gorm:"default:0" json:"initial_margin"`
 	PositionIM        float64   `gorm:"default:0" json:"position_im"`
 	OpenOrderIM       float64   `gorm:"default:0" json:"open_order_im"`
 	CrossWalletBalance float64  `gorm:"default:0" json:"cross_wallet_balance"`
-	CrossUnPnL        float64   `gorm:"default:0" json:"cross_un_pnl"`
+	CrossUnPnL        float64   `gorm:"column:cross_un_pnl;default:0" json:"cross_un_pnl"`
 	AvailableBalance  float64   `gorm:"default:0" json:"available_balance"`
 	MaxWithdrawAmount float64   `gorm:"default:0" json:"max_withdraw_amount"`
 	MarginAvailable   bool      `gorm:"default:true" json:"margin_available"`
@@ -186,16 +210,36 @@ type Strategy struct {
 	Parameters  string    `gorm:"type:json" json:"parameters"` // JSON string
 	IsActive    bool      `gorm:"default:true" json:"is_active"`
 	Performance string    `gorm:"type:json" json:"performance"` // JSON string for performance metrics
+	Version     uint      `gorm:"not null;default:1" json:"version"` // Bumped on every change
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// Remotely edited configuration entities
+const (
+	ConfigEntityRiskLimits = "risk_limits" // trading_configs row
+	ConfigEntityStrategy   = "strategy"    // strategies row
+)
+
+// ConfigChange records who changed a remotely edited configuration row, when, and what
+type ConfigChange struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Entity    string    `gorm:"not null;size:20;index:idx_config_change_entity" json:"entity"`
+	Name      string    `gorm:"not null;size:255;index:idx_config_change_entity" json:"name"`
+	Version   uint      `gorm:"not null" json:"version"` // Version the change produced
+	Actor     string    `gorm:"not null;size:100" json:"actor"`
+	Changes   string    `gorm:"type:json" json:"changes"` // JSON object of field to {"old", "new"}
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
 // RiskMetric represents risk management metrics
 type RiskMetric struct {
 	ID              uint      `gorm:"primaryKey" json:"id"`
-	Date            time.Time `gorm:"not null;index" json:"date"`
-	TotalPnL        float64   `gorm:"default:0" json:"total_pnl"`
-	DailyPnL        float64   `gorm:"default:0" json:"daily_pnl"`
+	Date            time.Time `gorm:"not null;index" json:"date"` // Calendar day in trading.day_timezone, at midnight UTC; one row per day
+	TotalPnL        float64   `gorm:"column:total_pnl;default:0" json:"total_pnl"`
+	DailyPnL        float64   `gorm:"column:daily_pnl;default:0" json:"daily_pnl"` // Net of commissions and funding
+	DailyFees       float64   `gorm:"default:0" json:"daily_fees"`
+	DailyFunding    float64   `gorm:"default:0" json:"daily_funding"`
 	MaxDrawdown     float64   `gorm:"default:0" json:"max_drawdown"`
 	TotalTrades     int       `gorm:"default:0" json:"total_trades"`
 	WinningTrades   int       `gorm:"default:0" json:"winning_trades"`
@@ -205,13 +249,335 @@ type RiskMetric struct {
 	AvgLoss         float64   `gorm:"default:0" json:"avg_loss"`
 	ProfitFactor    float64   `gorm:"default:0" json:"profit_factor"`
 	SharpeRatio     float64   `gorm:"default:0" json:"sharpe_ratio"`
-	VaR95           float64   `gorm:"default:0" json:"var_95"` // Value at Risk 95%
+	VaR95           float64   `gorm:"column:var_95;default:0" json:"var_95"` // Value at Risk 95%
 	MaxLeverage     float64   `gorm:"default:0" json:"max_leverage"`
 	TotalExposure   float64   `gorm:"default:0" json:"total_exposure"`
+	EquityDrawdownPercent float64 `gorm:"default:0" json:"equity_drawdown_percent"` // Below the high-water mark of drawdown scaling
+	DrawdownScale   float64   `gorm:"default:1" json:"drawdown_scale"`  // Size multiplier applied by drawdown scaling
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// RiskMetricPoint is an intraday sample of the day's risk metric, kept for charts
+type RiskMetricPoint struct {
+	ID                    uint      `gorm:"primaryKey" json:"id"`
+	Time                  time.Time `gorm:"not null;index" json:"time"`
+	DailyPnL              float64   `gorm:"column:daily_pnl" json:"daily_pnl"`
+	DailyFees             float64   `json:"daily_fees"`
+	DailyFunding          float64   `json:"daily_funding"`
+	TotalTrades           int       `json:"total_trades"`
+	WinningTrades         int       `json:"winning_trades"`
+	LosingTrades          int       `json:"losing_trades"`
+	WinRate               float64   `json:"win_rate"`
+	EquityDrawdownPercent float64   `json:"equity_drawdown_percent"`
+	DrawdownScale         float64   `gorm:"default:1" json:"drawdown_scale"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+// SentimentItem represents a scored news headline or sentiment reading for a symbol
+type SentimentItem struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Symbol      string    `gorm:"not null;uniqueIndex:idx_sentiment_symbol_source_external;size:50" json:"symbol"`
+	Source      string    `gorm:"not null;uniqueIndex:idx_sentiment_symbol_source_external;size:50" json:"source"`
+	ExternalID  string    `gorm:"not null;uniqueIndex:idx_sentiment_symbol_source_external;size:191" json:"external_id"`
+	Title       string    `gorm:"type:text" json:"title"`
+	URL         string    `gorm:"type:text" json:"url"`
+	Score       float64   `gorm:"not null" json:"score"` // -1.0 (very negative) to 1.0 (very positive)
+	PublishedAt time.Time `gorm:"not null;index" json:"published_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PositioningData represents open interest and long/short ratio samples for a symbol
+type PositioningData struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	Symbol            string    `gorm:"not null;index" json:"symbol"`
+	OpenInterest      float64   `gorm:"not null" json:"open_interest"`
+	OpenInterestValue float64   `json:"open_interest_value"` // Open interest in USDT at the sample price
+	LongShortRatio    float64   `json:"long_short_ratio"`
+	LongAccount       float64   `json:"long_account"`
+	ShortAccount      float64   `json:"short_account"`
+	Timestamp         int64     `gorm:"not null;index" json:"timestamp"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// OptionsVolatilitySample records an underlying's option implied volatility, skew and put/call
+// open interest ratio; volatilities are annualized percentages
+type OptionsVolatilitySample struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Underlying      string    `gorm:"not null;index:idx_options_underlying_time;size:20" json:"underlying"`
+	Venue           string    `gorm:"not null;size:20" json:"venue"`
+	UnderlyingPrice float64   `json:"underlying_price"`
+	Expiry          time.Time `json:"expiry"`
+	ATMVolatility   float64   `gorm:"column:atm_iv" json:"atm_iv"`
+	PutVolatility   float64   `gorm:"column:put_iv" json:"put_iv"`
+	CallVolatility  float64   `gorm:"column:call_iv" json:"call_iv"`
+	Skew            float64   `json:"skew"`
+	PutCallRatio    float64   `json:"put_call_ratio"`
+	Timestamp       int64     `gorm:"not null;index:idx_options_underlying_time" json:"timestamp"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// OrderFlowSample aggregates one minute of a symbol's aggregate trades; volumes are in the
+// quote asset and split by taker side
+type OrderFlowSample struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Symbol          string    `gorm:"not null;uniqueIndex:idx_order_flow_symbol_time;size:50" json:"symbol"`
+	BuyVolume       float64   `gorm:"not null" json:"buy_volume"`
+	SellVolume      float64   `gorm:"not null" json:"sell_volume"`
+	Delta           float64   `gorm:"not null" json:"delta"` // BuyVolume - SellVolume
+	TradeCount      int       `gorm:"not null" json:"trade_count"`
+	LargeBuys       int       `gorm:"default:0" json:"large_buys"`
+	LargeSells      int       `gorm:"default:0" json:"large_sells"`
+	LargeBuyVolume  float64   `gorm:"default:0" json:"large_buy_volume"`
+	LargeSellVolume float64   `gorm:"default:0" json:"large_sell_volume"`
+	Timestamp       int64     `gorm:"not null;uniqueIndex:idx_order_flow_symbol_time" json:"timestamp"` // Minute start, Unix seconds
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// Liquidation is a forced liquidation order from the exchange's liquidation feed
+type Liquidation struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Symbol    string    `gorm:"not null;index:idx_liquidation_symbol_time;size:50" json:"symbol"`
+	Side      string    `gorm:"not null;size:10" json:"side"` // SELL liquidates a long, BUY a short
+	Price     float64   `gorm:"not null" json:"price"`
+	Quantity  float64   `gorm:"not null" json:"quantity"`
+	Notional  float64   `gorm:"not null" json:"notional"` // Quantity at the average fill price
+	Time      time.Time `gorm:"not null;index:idx_liquidation_symbol_time" json:"time"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TradeJournal is a snapshot of what the strategy saw when a position was entered, added to
+// or exited
+type TradeJournal struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	PositionID    uint      `gorm:"index" json:"position_id"`
+	Symbol        string    `gorm:"not null;index;size:50" json:"symbol"`
+	Event         string    `gorm:"not null;size:20" json:"event"` // entry, add, exit
+	Strategy      string    `gorm:"size:100" json:"strategy"`
+	Reason        string    `gorm:"type:text" json:"reason"`
+	Confidence    float64   `json:"confidence"`
+	Price         float64   `json:"price"`
+	Quantity      float64   `json:"quantity"`
+	Features      string    `gorm:"type:text" json:"features"`             // JSON market data features
+	Indicators    string    `gorm:"type:text" json:"indicators"`           // JSON indicator values reported by the strategy
+	StrategyState string    `gorm:"type:mediumtext" json:"strategy_state"` // JSON
+	Klines        string    `gorm:"type:mediumtext" json:"klines"`         // JSON kline window
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+}
+
+// Outbox message kinds and states
+const (
+	OutboxKindNotification = "notification"
+	OutboxKindEvent        = "event"
+
+	OutboxPending   = "PENDING"
+	OutboxDelivered = "DELIVERED"
+	OutboxDead      = "DEAD" // Gave up after the last attempt
+)
+
+// OutboxMessage is a notification or event stored for asynchronous delivery, written in the
+// same transaction as the state change it reports
+type OutboxMessage struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	Kind          string     `gorm:"not null;size:20" json:"kind"`
+	Payload       string     `gorm:"type:mediumtext;not null" json:"payload"` // JSON
+	Status        string     `gorm:"not null;size:20;default:'PENDING';index:idx_outbox_due,priority:1" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_outbox_due,priority:2" json:"next_attempt_at"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// WalletTransfer moves virtual capital into (positive) or out of (negative) the wallet of a
+// strategy within the shared exchange account
+type WalletTransfer struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Strategy  string    `gorm:"not null;size:100;index" json:"strategy"`
+	Amount    float64   `gorm:"not null" json:"amount"` // USDT
+	Note      string    `json:"note,omitempty"`
+	Actor     string    `gorm:"size:100" json:"actor,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WalletSnapshot is a point of a strategy wallet's equity curve
+type WalletSnapshot struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	Strategy      string    `gorm:"not null;size:100;index:idx_wallet_time,priority:1" json:"strategy"`
+	Time          time.Time `gorm:"not null;index:idx_wallet_time,priority:2" json:"time"`
+	Deposits      float64   `json:"deposits"`                                // Net transfers
+	RealizedPnL   float64   `gorm:"column:realized_pnl" json:"realized_pnl"` // Net of commissions and funding
+	UnrealizedPnL float64   `gorm:"column:unrealized_pnl" json:"unrealized_pnl"`
+	Equity        float64   `json:"equity"`
+	MarginUsed    float64   `json:"margin_used"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ADLQuantile records a change of the auto-deleveraging queue bucket of a position side
+type ADLQuantile struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Symbol    string    `gorm:"not null;size:20;index:idx_adl_symbol_time,priority:1" json:"symbol"`
+	Side      string    `gorm:"not null;size:10" json:"side"` // LONG, SHORT or BOTH
+	Quantile  int       `gorm:"not null" json:"quantile"`     // 0 (last in the queue) to 4 (deleveraged first)
+	CreatedAt time.Time `gorm:"index:idx_adl_symbol_time,priority:2" json:"created_at"`
+}
+
+// StrategyState is a strategy's serialized in-memory state for one symbol
+type StrategyState struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Strategy  string    `gorm:"not null;uniqueIndex:idx_strategy_state;size:100" json:"strategy"`
+	Symbol    string    `gorm:"not null;uniqueIndex:idx_strategy_state;size:50" json:"symbol"`
+	State     string    `gorm:"type:mediumtext" json:"state"` // JSON
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StrategyPause persists the guard's pause of a strategy's entries and the last resume,
+// which starts a fresh performance window
+type StrategyPause struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Strategy  string     `gorm:"uniqueIndex;not null;size:100" json:"strategy"`
+	Paused    bool       `gorm:"default:false" json:"paused"`
+	Reason    string     `gorm:"type:text" json:"reason"`
+	Stats     string     `gorm:"type:text" json:"stats"` // JSON of the stats that triggered the pause
+	PausedAt  *time.Time `json:"paused_at"`
+	ResumedAt *time.Time `json:"resumed_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// ShadowSignal is a non-HOLD signal of a shadow test variant (the control copy of the live
+// strategy or the candidate) and how its simulated book acted on it
+type ShadowSignal struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Variant    string    `gorm:"not null;index:idx_shadow_variant_time;size:100" json:"variant"`
+	Strategy   string    `gorm:"size:100" json:"strategy"`
+	Symbol     string    `gorm:"not null;index;size:50" json:"symbol"`
+	Action     string    `gorm:"not null;size:10" json:"action"` // BUY, SELL
+	Price      float64   `json:"price"`
+	Confidence float64   `json:"confidence"`
+	Reason     string    `gorm:"type:text" json:"reason"`
+	Filled     bool      `json:"filled"` // The simulated book entered or exited on it
+	PnL        float64   `gorm:"column:pnl" json:"pnl"`    // Net simulated PnL of the trade an exit closed
+	SignalTime time.Time `gorm:"not null;index:idx_shadow_variant_time" json:"signal_time"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AuditEvent records a change made by or detected by the bot for later review
+type AuditEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Category  string    `gorm:"not null;index;size:50" json:"category"` // e.g. drift
+	Action    string    `gorm:"not null;size:100" json:"action"`
+	Symbol    string    `gorm:"index;size:50" json:"symbol"`
+	Actor     string    `gorm:"size:100" json:"actor"`
+	Details   string    `gorm:"type:json" json:"details"` // JSON string
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// OrderAuditEntry is one order action in the append-only order audit log. Entries are chained
+// by hash and the request and exchange result are stored AES-256-GCM encrypted in Payload.
+type OrderAuditEntry struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Seq             uint64    `gorm:"uniqueIndex;not null" json:"seq"`
+	Action          string    `gorm:"not null;size:20" json:"action"` // SUBMIT, MODIFY, CANCEL
+	Market          string    `gorm:"not null;size:10" json:"market"` // futures or spot
+	Symbol          string    `gorm:"not null;index;size:50" json:"symbol"`
+	ExchangeOrderID string    `gorm:"size:50" json:"exchange_order_id"`
+	ClientOrderID   string    `gorm:"size:64" json:"client_order_id"`
+	TriggeredBy     string    `gorm:"not null;size:20" json:"triggered_by"` // strategy, manual, risk, system
+	Actor           string    `gorm:"size:100" json:"actor"`           // Strategy name, API caller or engine component
+	Success         bool      `json:"success"`
+	Payload         string    `gorm:"type:text" json:"payload"` // Base64 nonce and sealed JSON
+	PrevHash        string    `gorm:"size:64" json:"prev_hash"`
+	Hash            string    `gorm:"not null;size:64" json:"hash"`
+	Timestamp       int64     `gorm:"not null;index" json:"timestamp"` // Unix milliseconds, covered by the hash
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// SymbolCooldown persists per-symbol anti-churn state so restarts don't reset cooldowns
+type SymbolCooldown struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	Symbol            string     `gorm:"uniqueIndex;not null;size:50" json:"symbol"`
+	ConsecutiveLosses int        `gorm:"default:0" json:"consecutive_losses"`
+	LastEntryTime     *time.Time `json:"last_entry_time"`
+	LastExitTime      *time.Time `json:"last_exit_time"`
+	CooldownUntil     *time.Time `json:"cooldown_until"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// SchemaMigration records a versioned schema migration applied to the database
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	Dirty     bool      `gorm:"not null;default:false" json:"dirty"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// GridState persists one level of a grid so the grid survives restarts
+type GridState struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Symbol          string    `gorm:"uniqueIndex:idx_grid_symbol_level;not null;size:50" json:"symbol"`
+	Level           int       `gorm:"uniqueIndex:idx_grid_symbol_level;not null" json:"level"`
+	BasePrice       float64   `gorm:"not null" json:"base_price"` // Price the grid was built around
+	Price           float64   `gorm:"not null" json:"price"`
+	Quantity        float64   `gorm:"default:0" json:"quantity"`
+	Active          bool      `gorm:"default:false" json:"active"` // A filled buy leg is held at this level
+	Side            string    `gorm:"size:10" json:"side"`          // Resting order side in neutral mode: BUY, SELL or empty
+	ExchangeOrderID string    `gorm:"size:50" json:"exchange_order_id"`
+	FilledLegs      int       `gorm:"default:0" json:"filled_legs"`
+	LastFillPrice   float64   `gorm:"default:0" json:"last_fill_price"`
+	LastFillTime    *time.Time `json:"last_fill_time"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
 
+// FundingArbPosition is a delta-neutral pair: a spot long hedged by a perpetual short,
+// held to collect funding
+type FundingArbPosition struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	Symbol           string     `gorm:"not null;index;size:50" json:"symbol"`
+	Status           string     `gorm:"not null;default:'OPEN';index" json:"status"` // OPEN, CLOSED
+	SpotQuantity     float64    `gorm:"not null" json:"spot_quantity"`
+	PerpQuantity     float64    `gorm:"not null" json:"perp_quantity"`
+	SpotEntryPrice   float64    `gorm:"not null" json:"spot_entry_price"`
+	PerpEntryPrice   float64    `gorm:"not null" json:"perp_entry_price"`
+	SpotExitPrice    float64    `gorm:"default:0" json:"spot_exit_price"`
+	PerpExitPrice    float64    `gorm:"default:0" json:"perp_exit_price"`
+	EntryFundingRate float64    `gorm:"default:0" json:"entry_funding_rate"`
+	ExitFundingRate  float64    `gorm:"default:0" json:"exit_funding_rate"`
+	ClosedPnL        float64    `gorm:"column:closed_pnl;default:0" json:"closed_pnl"` // Price PnL of both legs, excluding funding and fees
+	OpenTime         time.Time  `gorm:"not null" json:"open_time"`
+	CloseTime        *time.Time `json:"close_time"`
+	Notes            string     `json:"notes"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// SpreadSample records the price of a contract on another venue against Binance
+type SpreadSample struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	Symbol         string    `gorm:"not null;index:idx_spread_symbol_venue;size:50" json:"symbol"`
+	Venue          string    `gorm:"not null;index:idx_spread_symbol_venue;size:20" json:"venue"`
+	ReferencePrice float64   `gorm:"not null" json:"reference_price"` // Binance price
+	VenuePrice     float64   `gorm:"not null" json:"venue_price"`
+	SpreadBps      float64   `gorm:"not null" json:"spread_bps"` // (venue - Binance) / Binance in basis points
+	CreatedAt      time.Time `gorm:"index" json:"created_at"`
+}
+
+// Income represents one entry of the exchange's futures income history
+type Income struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	TranID     int64     `gorm:"not null;uniqueIndex:idx_income_tran_type" json:"tran_id"`
+	IncomeType string    `gorm:"not null;uniqueIndex:idx_income_tran_type;size:32" json:"income_type"` // REALIZED_PNL, COMMISSION, FUNDING_FEE, ...
+	Symbol     string    `gorm:"index:idx_income_symbol_time;size:50" json:"symbol"`
+	Income     float64   `gorm:"not null" json:"income"` // Signed; negative when paid
+	Asset      string    `gorm:"size:20" json:"asset"`
+	Info       string    `gorm:"size:191" json:"info"`
+	TradeID    string    `gorm:"size:64" json:"trade_id"`
+	Time       time.Time `gorm:"not null;index:idx_income_symbol_time" json:"time"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 // TableName methods for custom table names
 func (TradingConfig) TableName() string {
 	return "trading_configs"
@@ -252,3 +618,63 @@ func (Strategy) TableName() string {
 func (RiskMetric) TableName() string {
 	return "risk_metrics"
 }
+
+func (SentimentItem) TableName() string {
+	return "sentiment_items"
+}
+
+func (PositioningData) TableName() string {
+	return "positioning_data"
+}
+
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
+
+func (ShadowSignal) TableName() string {
+	return "shadow_signals"
+}
+
+func (StrategyPause) TableName() string {
+	return "strategy_pauses"
+}
+
+func (SymbolCooldown) TableName() string {
+	return "symbol_cooldowns"
+}
+
+func (GridState) TableName() string {
+	return "grid_states"
+}
+
+func (FundingArbPosition) TableName() string {
+	return "funding_arb_positions"
+}
+
+func (OrderAuditEntry) TableName() string {
+	return "order_audit_log"
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+func (SpreadSample) TableName() string {
+	return "spread_samples"
+}
+
+func (WalletTransfer) TableName() string {
+	return "wallet_transfers"
+}
+
+func (WalletSnapshot) TableName() string {
+	return "wallet_snapshots"
+}
+
+func (ADLQuantile) TableName() string {
+	return "adl_quantiles"
+}
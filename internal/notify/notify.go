@@ -0,0 +1,169 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"contract_playground/internal/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Level indicates how urgent a notification is
+type Level string
+
+const (
+	LevelInfo     Level = "INFO"
+	LevelWarning  Level = "WARNING"
+	LevelCritical Level = "CRITICAL"
+)
+
+// Message is a single notification
+type Message struct {
+	Title  string            `json:"title"`
+	Body   string            `json:"body"`
+	Level  Level             `json:"level"`
+	Symbol string            `json:"symbol,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+	Time   time.Time         `json:"time"`
+}
+
+// Notifier delivers notifications to a destination
+type Notifier interface {
+	Notify(ctx context.Context, msg *Message) error
+}
+
+// New builds the notifier described by the configuration. Notifications are always
+// logged; configured webhooks receive them as well.
+func New(cfg config.NotificationConfig, logger *logrus.Logger) Notifier {
+	notifiers := []Notifier{NewLogNotifier(logger)}
+
+	for _, url := range cfg.WebhookURLs {
+		notifiers = append(notifiers, NewWebhookNotifier(url, time.Duration(cfg.TimeoutSeconds)*time.Second))
+	}
+
+	if len(notifiers) == 1 {
+		return notifiers[0]
+	}
+	return Multi(notifiers)
+}
+
+// instanceNotifier labels notifications with the bot instance that raised them
+type instanceNotifier struct {
+	next     Notifier
+	instance string
+}
+
+// WithInstance adds an instance field to every notification, telling apart the bots of a
+// multi-instance process that share webhooks
+func WithInstance(next Notifier, instance string) Notifier {
+	return &instanceNotifier{next: next, instance: instance}
+}
+
+// Notify passes a labelled copy of the message on
+func (n *instanceNotifier) Notify(ctx context.Context, msg *Message) error {
+	labelled := *msg
+	labelled.Fields = make(map[string]string, len(msg.Fields)+1)
+	for k, v := range msg.Fields {
+		labelled.Fields[k] = v
+	}
+	labelled.Fields["instance"] = n.instance
+	return n.next.Notify(ctx, &labelled)
+}
+
+// LogNotifier writes notifications to the application log
+type LogNotifier struct {
+	logger *logrus.Logger
+}
+
+// NewLogNotifier creates a new log notifier
+func NewLogNotifier(logger *logrus.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// Notify logs the message at a level matching its urgency
+func (l *LogNotifier) Notify(ctx context.Context, msg *Message) error {
+	entry := l.logger.WithField("notification", msg.Title)
+	if msg.Symbol != "" {
+		entry = entry.WithField("symbol", msg.Symbol)
+	}
+	for k, v := range msg.Fields {
+		entry = entry.WithField(k, v)
+	}
+
+	switch msg.Level {
+	case LevelCritical:
+		entry.Error(msg.Body)
+	case LevelWarning:
+		entry.Warn(msg.Body)
+	default:
+		entry.Info(msg.Body)
+	}
+	return nil
+}
+
+// WebhookNotifier posts notifications as JSON to an HTTP endpoint
+type WebhookNotifier struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Notify posts the message to the webhook
+func (w *WebhookNotifier) Notify(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	return w.Post(ctx, body)
+}
+
+// Post sends a JSON body to the webhook
+func (w *WebhookNotifier) Post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(text)))
+	}
+
+	return nil
+}
+
+// Multi fans a notification out to several notifiers
+type Multi []Notifier
+
+// Notify delivers the message to every notifier and joins their errors
+func (m Multi) Notify(ctx context.Context, msg *Message) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
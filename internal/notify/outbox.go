@@ -0,0 +1,215 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// OutboxStore persists outbox messages. A repository bound to a transaction can be passed
+// to Enqueue, so a message is stored only if the state change it reports commits.
+type OutboxStore interface {
+	CreateOutboxMessage(message *models.OutboxMessage) error
+	GetDueOutboxMessages(now time.Time, limit int) ([]*models.OutboxMessage, error)
+	UpdateOutboxMessage(message *models.OutboxMessage) error
+	DeleteDeliveredOutboxMessagesBefore(before time.Time) (int64, error)
+}
+
+// DeliverFunc delivers the payload of one outbox message
+type DeliverFunc func(ctx context.Context, payload []byte) error
+
+// NotificationMessage encodes a notification for the outbox
+func NotificationMessage(msg *Message) (*models.OutboxMessage, error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode notification: %w", err)
+	}
+	return &models.OutboxMessage{Kind: models.OutboxKindNotification, Payload: string(payload)}, nil
+}
+
+// EventMessage encodes an engine event for the outbox
+func EventMessage(event interface{}) (*models.OutboxMessage, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return &models.OutboxMessage{Kind: models.OutboxKindEvent, Payload: string(payload)}, nil
+}
+
+// Outbox is a notifier that stores notifications for the relay instead of sending them, so
+// they survive webhook outages and restarts. Notifications are still logged right away.
+type Outbox struct {
+	store    OutboxStore
+	log      *LogNotifier
+	fallback Notifier // Sends directly when the message cannot be stored
+}
+
+// Notify logs the message and stores it for delivery
+func (o *Outbox) Notify(ctx context.Context, msg *Message) error {
+	o.log.Notify(ctx, msg)
+	if err := o.Enqueue(o.store, msg); err != nil {
+		o.log.logger.Errorf("Failed to store notification in the outbox, sending it directly: %v", err)
+		return o.fallback.Notify(ctx, msg)
+	}
+	return nil
+}
+
+// Enqueue stores a notification through store, e.g. a repository bound to a transaction
+func (o *Outbox) Enqueue(store OutboxStore, msg *Message) error {
+	message, err := NotificationMessage(msg)
+	if err != nil {
+		return err
+	}
+	return store.CreateOutboxMessage(message)
+}
+
+// Relay delivers due outbox messages in the background. A failed delivery is retried with
+// exponential backoff until max_attempts, then the message is marked dead and kept for
+// inspection. Delivery is at least once: a crash after sending and before recording the
+// delivery sends the message again.
+type Relay struct {
+	config   config.OutboxConfig
+	store    OutboxStore
+	logger   *logrus.Logger
+	handlers map[string]DeliverFunc
+
+	mu      sync.Mutex // Serializes passes
+	cleaned time.Time
+}
+
+// NewOutbox builds the outbox notifier and the relay that delivers its messages: notifications
+// go to the configured webhooks and events to the event webhooks
+func NewOutbox(cfg config.NotificationConfig, store OutboxStore, logger *logrus.Logger) (*Outbox, *Relay) {
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+	webhooks := make(Multi, 0, len(cfg.WebhookURLs))
+	for _, url := range cfg.WebhookURLs {
+		webhooks = append(webhooks, NewWebhookNotifier(url, timeout))
+	}
+	eventWebhooks := make([]*WebhookNotifier, 0, len(cfg.EventWebhookURLs))
+	for _, url := range cfg.EventWebhookURLs {
+		eventWebhooks = append(eventWebhooks, NewWebhookNotifier(url, timeout))
+	}
+
+	outbox := &Outbox{store: store, log: NewLogNotifier(logger), fallback: webhooks}
+	relay := &Relay{
+		config: cfg.Outbox,
+		store:  store,
+		logger: logger,
+		handlers: map[string]DeliverFunc{
+			models.OutboxKindNotification: func(ctx context.Context, payload []byte) error {
+				var msg Message
+				if err := json.Unmarshal(payload, &msg); err != nil {
+					return fmt.Errorf("failed to decode notification: %w", err)
+				}
+				return webhooks.Notify(ctx, &msg)
+			},
+			models.OutboxKindEvent: func(ctx context.Context, payload []byte) error {
+				var errs []error
+				for _, webhook := range eventWebhooks {
+					if err := webhook.Post(ctx, payload); err != nil {
+						errs = append(errs, err)
+					}
+				}
+				return errors.Join(errs...)
+			},
+		},
+	}
+	return outbox, relay
+}
+
+// Start delivers due messages every poll interval until ctx is cancelled
+func (r *Relay) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(r.config.PollSeconds) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.DeliverDue(ctx); err != nil {
+					r.logger.Errorf("Outbox delivery failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// DeliverDue makes one delivery attempt for every due message and prunes delivered messages
+// once an hour
+func (r *Relay) DeliverDue(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	messages, err := r.store.GetDueOutboxMessages(now, r.config.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get due outbox messages: %w", err)
+	}
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			return nil
+		}
+		r.deliver(ctx, message)
+	}
+
+	if r.config.RetentionDays > 0 && now.Sub(r.cleaned) >= time.Hour {
+		r.cleaned = now
+		deleted, err := r.store.DeleteDeliveredOutboxMessagesBefore(now.AddDate(0, 0, -r.config.RetentionDays))
+		if err != nil {
+			return fmt.Errorf("failed to prune delivered outbox messages: %w", err)
+		}
+		if deleted > 0 {
+			r.logger.Debugf("Pruned %d delivered outbox messages", deleted)
+		}
+	}
+	return nil
+}
+
+// deliver attempts one message and records the outcome
+func (r *Relay) deliver(ctx context.Context, message *models.OutboxMessage) {
+	handler, ok := r.handlers[message.Kind]
+	err := fmt.Errorf("no handler for outbox messages of kind %q", message.Kind)
+	if ok {
+		err = handler(ctx, []byte(message.Payload))
+	}
+
+	now := time.Now()
+	message.Attempts++
+	if err == nil {
+		message.Status = models.OutboxDelivered
+		message.DeliveredAt = &now
+		message.LastError = ""
+	} else {
+		message.LastError = err.Error()
+		if message.Attempts >= r.config.MaxAttempts {
+			message.Status = models.OutboxDead
+			r.logger.Errorf("Giving up on outbox %s %d after %d attempts: %v", message.Kind, message.ID, message.Attempts, err)
+		} else {
+			message.NextAttemptAt = now.Add(retryDelay(r.config, message.Attempts))
+			r.logger.Warnf("Outbox %s %d failed (attempt %d of %d), retrying at %s: %v",
+				message.Kind, message.ID, message.Attempts, r.config.MaxAttempts, message.NextAttemptAt.Format(time.RFC3339), err)
+		}
+	}
+	if err := r.store.UpdateOutboxMessage(message); err != nil {
+		r.logger.Errorf("Failed to record delivery of outbox %s %d: %v", message.Kind, message.ID, err)
+	}
+}
+
+// retryDelay doubles the base delay for every failed attempt up to the maximum
+func retryDelay(cfg config.OutboxConfig, attempts int) time.Duration {
+	delay := time.Duration(cfg.RetryBaseSeconds) * time.Second
+	limit := time.Duration(cfg.RetryMaxSeconds) * time.Second
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// memoryStore keeps outbox messages in memory
+type memoryStore struct {
+	messages []*models.OutboxMessage
+}
+
+func (s *memoryStore) CreateOutboxMessage(message *models.OutboxMessage) error {
+	message.ID = uint(len(s.messages) + 1)
+	message.Status = models.OutboxPending
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func (s *memoryStore) GetDueOutboxMessages(now time.Time, limit int) ([]*models.OutboxMessage, error) {
+	var due []*models.OutboxMessage
+	for _, message := range s.messages {
+		if message.Status == models.OutboxPending && !message.NextAttemptAt.After(now) && len(due) < limit {
+			copied := *message
+			due = append(due, &copied)
+		}
+	}
+	return due, nil
+}
+
+func (s *memoryStore) UpdateOutboxMessage(message *models.OutboxMessage) error {
+	copied := *message
+	s.messages[message.ID-1] = &copied
+	return nil
+}
+
+func (s *memoryStore) DeleteDeliveredOutboxMessagesBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestRetryDelay(t *testing.T) {
+	cfg := config.OutboxConfig{RetryBaseSeconds: 5, RetryMaxSeconds: 60}
+	for _, test := range []struct {
+		attempts int
+		want     time.Duration
+	}{{1, 5 * time.Second}, {2, 10 * time.Second}, {4, 40 * time.Second}, {5, time.Minute}, {30, time.Minute}} {
+		if got := retryDelay(cfg, test.attempts); got != test.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", test.attempts, got, test.want)
+		}
+	}
+}
+
+func TestRelayRetriesAndDeadLetters(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	store := &memoryStore{}
+	failures := 0
+	relay := &Relay{
+		config: config.OutboxConfig{BatchSize: 10, MaxAttempts: 3, RetryBaseSeconds: 1, RetryMaxSeconds: 1},
+		store:  store,
+		logger: logger,
+		handlers: map[string]DeliverFunc{
+			models.OutboxKindEvent: func(ctx context.Context, payload []byte) error {
+				if string(payload) == `"fail"` {
+					failures++
+					return errors.New("webhook down")
+				}
+				return nil
+			},
+		},
+	}
+	for _, event := range []string{"ok", "fail"} {
+		message, err := EventMessage(event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		store.CreateOutboxMessage(message)
+	}
+
+	ctx := context.Background()
+	if err := relay.DeliverDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	delivered, failed := store.messages[0], store.messages[1]
+	if delivered.Status != models.OutboxDelivered || delivered.DeliveredAt == nil {
+		t.Fatalf("first message = %+v, want delivered", delivered)
+	}
+	if failed.Status != models.OutboxPending || failed.Attempts != 1 || failed.LastError != "webhook down" || !failed.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("second message = %+v, want a pending retry after one attempt", failed)
+	}
+
+	// Not due yet, so the next pass leaves it alone
+	relay.DeliverDue(ctx)
+	if failures != 1 {
+		t.Fatalf("attempts = %d before the retry is due, want 1", failures)
+	}
+
+	for i := 0; i < 2; i++ {
+		store.messages[1].NextAttemptAt = time.Now().Add(-time.Second)
+		relay.DeliverDue(ctx)
+	}
+	if failed := store.messages[1]; failed.Status != models.OutboxDead || failed.Attempts != 3 {
+		t.Fatalf("second message = %+v, want dead after 3 attempts", failed)
+	}
+	if relay.DeliverDue(ctx); failures != 3 {
+		t.Errorf("attempts = %d, want no delivery after the message is dead", failures)
+	}
+}
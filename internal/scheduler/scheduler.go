@@ -0,0 +1,181 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Job is a unit of periodic work
+type Job struct {
+	Name       string
+	Spec       string // Cron expression or descriptor, see Parse
+	RunOnStart bool   // Run once as soon as the scheduler starts
+	Run        func(ctx context.Context) error
+}
+
+// JobStatus is the run history of a job
+type JobStatus struct {
+	Name         string        `json:"name"`
+	Schedule     string        `json:"schedule"`
+	Running      bool          `json:"running"`
+	Runs         int           `json:"runs"`
+	Failures     int           `json:"failures"`
+	Skipped      int           `json:"skipped"` // Runs skipped because the previous run was still going
+	LastStart    *time.Time    `json:"last_start,omitempty"`
+	LastDuration time.Duration `json:"last_duration_ns"`
+	LastError    string        `json:"last_error,omitempty"`
+	NextRun      *time.Time    `json:"next_run,omitempty"`
+}
+
+// Scheduler runs registered jobs on their schedules with random jitter. A job never runs
+// concurrently with itself: a run that comes due while the previous one is still going is
+// skipped.
+type Scheduler struct {
+	jitter time.Duration
+	logger *logrus.Logger
+
+	mu      sync.Mutex
+	jobs    []*scheduledJob
+	started bool
+}
+
+type scheduledJob struct {
+	job      Job
+	schedule Schedule
+	status   JobStatus
+}
+
+// New creates a scheduler that delays each run by a random duration of up to jitter
+func New(jitter time.Duration, logger *logrus.Logger) *Scheduler {
+	return &Scheduler{jitter: jitter, logger: logger}
+}
+
+// Register adds a job; it must be called before Start
+func (s *Scheduler) Register(job Job) error {
+	schedule, err := Parse(job.Spec)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q for job %s: %w", job.Spec, job.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return fmt.Errorf("cannot register job %s after the scheduler started", job.Name)
+	}
+	for _, existing := range s.jobs {
+		if existing.job.Name == job.Name {
+			return fmt.Errorf("job %s is already registered", job.Name)
+		}
+	}
+	s.jobs = append(s.jobs, &scheduledJob{
+		job:      job,
+		schedule: schedule,
+		status:   JobStatus{Name: job.Name, Schedule: job.Spec},
+	})
+	return nil
+}
+
+// Start runs every registered job until ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.started = true
+	jobs := append([]*scheduledJob(nil), s.jobs...)
+	s.mu.Unlock()
+
+	for _, job := range jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+// Status returns the status of every job sorted by name
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		statuses = append(statuses, job.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// loop fires the job at each scheduled time
+func (s *Scheduler) loop(ctx context.Context, job *scheduledJob) {
+	done := make(chan struct{}, 1)
+	if job.job.RunOnStart {
+		s.fire(ctx, job, done)
+	}
+
+	last := time.Now()
+	for {
+		next := job.schedule.Next(last)
+		if next.IsZero() {
+			s.logger.Warnf("Job %s has no future run time for schedule %q", job.job.Name, job.job.Spec)
+			return
+		}
+		last = next
+		if s.jitter > 0 {
+			next = next.Add(time.Duration(rand.Int63n(int64(s.jitter))))
+		}
+
+		s.mu.Lock()
+		job.status.NextRun = &next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.fire(ctx, job, done)
+
+		// Interval schedules count from the fire time so a slow run does not shift them
+		if now := time.Now(); last.Before(now.Add(-time.Minute)) {
+			last = now
+		}
+	}
+}
+
+// fire starts a run unless the previous one is still going
+func (s *Scheduler) fire(ctx context.Context, job *scheduledJob, done chan struct{}) {
+	s.mu.Lock()
+	if job.status.Running {
+		job.status.Skipped++
+		s.mu.Unlock()
+		s.logger.Warnf("Skipping job %s: previous run is still going", job.job.Name)
+		return
+	}
+	start := time.Now()
+	job.status.Running = true
+	job.status.LastStart = &start
+	s.mu.Unlock()
+
+	go func() {
+		err := job.job.Run(ctx)
+
+		s.mu.Lock()
+		job.status.Running = false
+		job.status.Runs++
+		job.status.LastDuration = time.Since(start)
+		job.status.LastError = ""
+		if err != nil {
+			job.status.Failures++
+			job.status.LastError = err.Error()
+		}
+		s.mu.Unlock()
+
+		if err != nil {
+			s.logger.Errorf("Job %s failed: %v", job.job.Name, err)
+		}
+	}()
+}
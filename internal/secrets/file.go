@@ -0,0 +1,126 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Encrypted secrets files are the magic header, a random salt and nonce, then the
+// AES-256-GCM sealed JSON credentials. The key is derived from the passphrase with
+// PBKDF2-HMAC-SHA256.
+const (
+	fileMagic        = "CPSECRET1"
+	saltSize         = 16
+	nonceSize        = 12
+	keySize          = 32
+	pbkdf2Iterations = 600000
+)
+
+// EnvPassphrase supplies the secrets file passphrase non-interactively
+const EnvPassphrase = "TRADER_SECRETS_PASSPHRASE"
+
+// FromFile decrypts credentials from an encrypted secrets file. The passphrase comes
+// from TRADER_SECRETS_PASSPHRASE or, when unset, an interactive prompt.
+func FromFile(path string) (*Credentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+
+	passphrase, err := Passphrase(fmt.Sprintf("Passphrase for %s: ", path))
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := Decrypt(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds Credentials
+	if err := json.Unmarshal(plaintext, &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets file: %w", err)
+	}
+	if err := creds.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid secrets file: %w", err)
+	}
+	return &creds, nil
+}
+
+// Passphrase returns TRADER_SECRETS_PASSPHRASE or prompts for it on the terminal
+func Passphrase(prompt string) ([]byte, error) {
+	if passphrase := os.Getenv(EnvPassphrase); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	passphrase, err := readPassword(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("empty passphrase")
+	}
+	return passphrase, nil
+}
+
+// Encrypt seals plaintext with a key derived from the passphrase
+func Encrypt(plaintext, passphrase []byte) ([]byte, error) {
+	header := make([]byte, len(fileMagic)+saltSize+nonceSize)
+	copy(header, fileMagic)
+	salt := header[len(fileMagic) : len(fileMagic)+saltSize]
+	nonce := header[len(fileMagic)+saltSize:]
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	// The header is authenticated so it cannot be swapped between files
+	return aead.Seal(header, nonce, plaintext, header), nil
+}
+
+// Decrypt opens data sealed by Encrypt
+func Decrypt(data, passphrase []byte) ([]byte, error) {
+	headerSize := len(fileMagic) + saltSize + nonceSize
+	if len(data) < headerSize || !bytes.Equal(data[:len(fileMagic)], []byte(fileMagic)) {
+		return nil, fmt.Errorf("not an encrypted secrets file")
+	}
+	header := data[:headerSize]
+	salt := header[len(fileMagic) : len(fileMagic)+saltSize]
+	nonce := header[len(fileMagic)+saltSize:]
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, data[headerSize:], header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secrets file (wrong passphrase?)")
+	}
+	return plaintext, nil
+}
+
+func newAEAD(passphrase, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key(passphrase, salt, pbkdf2Iterations, keySize, sha256.New))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return aead, nil
+}
@@ -0,0 +1,69 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	plaintext := []byte(`{"api_key":"key","secret_key":"secret"}`)
+	passphrase := []byte("correct horse battery staple")
+
+	sealed, err := Encrypt(plaintext, passphrase)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if bytes.Contains(sealed, []byte("secret")) {
+		t.Fatal("sealed file contains the plaintext")
+	}
+
+	opened, err := Decrypt(sealed, passphrase)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Errorf("round trip = %s, want %s", opened, plaintext)
+	}
+
+	if _, err := Decrypt(sealed, []byte("wrong passphrase")); err == nil {
+		t.Error("decrypted with a wrong passphrase")
+	}
+	// The header is authenticated: a swapped salt byte fails like a wrong passphrase
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(fileMagic)] ^= 1
+	if _, err := Decrypt(tampered, passphrase); err == nil {
+		t.Error("decrypted a file with a tampered header")
+	}
+	if _, err := Decrypt([]byte("plain text"), passphrase); err == nil {
+		t.Error("decrypted a file without the header")
+	}
+}
+
+func TestFromFile(t *testing.T) {
+	plaintext, _ := json.Marshal(&Credentials{APIKey: "key", SecretKey: "secret"})
+	sealed, err := Encrypt(plaintext, []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	if err := os.WriteFile(path, sealed, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EnvPassphrase, "passphrase")
+	creds, err := FromFile(path)
+	if err != nil {
+		t.Fatalf("FromFile: %v", err)
+	}
+	if creds.APIKey != "key" || creds.SecretKey != "secret" {
+		t.Errorf("credentials = %+v, want key and secret", creds)
+	}
+
+	t.Setenv(EnvPassphrase, "not the passphrase")
+	if _, err := FromFile(path); err == nil {
+		t.Error("loaded credentials with a wrong passphrase")
+	}
+}
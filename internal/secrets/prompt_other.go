@@ -0,0 +1,10 @@
+//go:build !linux
+
+package secrets
+
+import "fmt"
+
+// readPassword is only supported on Linux; elsewhere the passphrase must come from the environment
+func readPassword(prompt string) ([]byte, error) {
+	return nil, fmt.Errorf("passphrase prompt is not supported on this platform; set %s", EnvPassphrase)
+}
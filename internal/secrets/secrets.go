@@ -0,0 +1,49 @@
+// Package secrets loads exchange API credentials from sources other than the plaintext
+// config file: the environment, a passphrase-encrypted file or HashiCorp Vault.
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Credential sources selectable with exchange.credentials_source
+const (
+	SourceConfig = "config" // api_key/secret_key from config.yaml or TRADER_* overrides
+	SourceEnv    = "env"    // BINANCE_API_KEY/BINANCE_SECRET_KEY only
+	SourceFile   = "file"   // Encrypted secrets file
+	SourceVault  = "vault"  // HashiCorp Vault KV secret
+)
+
+// Environment variables read by the env source
+const (
+	EnvAPIKey    = "BINANCE_API_KEY"
+	EnvSecretKey = "BINANCE_SECRET_KEY"
+)
+
+// Credentials are exchange API credentials
+type Credentials struct {
+	APIKey    string `json:"api_key"`
+	SecretKey string `json:"secret_key"`
+}
+
+// Validate checks that both keys are present
+func (c *Credentials) Validate() error {
+	if strings.TrimSpace(c.APIKey) == "" || strings.TrimSpace(c.SecretKey) == "" {
+		return fmt.Errorf("api_key and secret_key are both required")
+	}
+	return nil
+}
+
+// FromEnv reads credentials from the environment only, ignoring anything in config files
+func FromEnv() (*Credentials, error) {
+	creds := &Credentials{
+		APIKey:    os.Getenv(EnvAPIKey),
+		SecretKey: os.Getenv(EnvSecretKey),
+	}
+	if err := creds.Validate(); err != nil {
+		return nil, fmt.Errorf("%s and %s must be set: %w", EnvAPIKey, EnvSecretKey, err)
+	}
+	return creds, nil
+}
@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultOptions locate a credentials secret in HashiCorp Vault
+type VaultOptions struct {
+	Address        string // Falls back to VAULT_ADDR
+	Path           string // Secret path under /v1/, e.g. secret/data/trader/binance for KV v2
+	Namespace      string // Enterprise namespace, optional
+	APIKeyField    string
+	SecretKeyField string
+}
+
+// FromVault reads credentials from a Vault KV secret. Both KV v1 and v2 layouts are
+// accepted. The token comes from VAULT_TOKEN so it never has to be written to disk.
+func FromVault(ctx context.Context, opts VaultOptions) (*Credentials, error) {
+	address := opts.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if address == "" || opts.Path == "" {
+		return nil, fmt.Errorf("vault address and path are required")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("VAULT_TOKEN must be set")
+	}
+
+	url := strings.TrimRight(address, "/") + "/v1/" + strings.TrimLeft(opts.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if opts.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", opts.Namespace)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("vault returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(text)))
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	// KV v2 nests the secret under data.data
+	fields := body.Data
+	if nested, ok := fields["data"].(map[string]interface{}); ok {
+		fields = nested
+	}
+
+	creds := &Credentials{
+		APIKey:    stringField(fields, opts.APIKeyField),
+		SecretKey: stringField(fields, opts.SecretKeyField),
+	}
+	if err := creds.Validate(); err != nil {
+		return nil, fmt.Errorf("vault secret %s is missing fields %s/%s: %w", opts.Path, opts.APIKeyField, opts.SecretKeyField, err)
+	}
+	return creds, nil
+}
+
+func stringField(fields map[string]interface{}, key string) string {
+	value, _ := fields[key].(string)
+	return value
+}
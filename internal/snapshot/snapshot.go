@@ -0,0 +1,209 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"contract_playground/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Version is the snapshot format written by Export; Restore rejects other versions
+const Version = 1
+
+// Snapshot is the bot state needed to resume trading on another host: open positions and
+// the orders managed for them, strategy, grid, cooldown and strategy pause state, open funding arbitrage
+// pairs and the latest risk counters. Trade and market data history is not included.
+type Snapshot struct {
+	Version    int       `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+	Host       string    `json:"host"`
+	ConfigHash string    `json:"config_hash"` // SHA-256 of the redacted effective configuration
+
+	Positions           []*models.Position           `json:"positions"`
+	Orders              []*models.Order              `json:"orders"` // Open orders and orders of open positions
+	StrategyStates      []*models.StrategyState      `json:"strategy_states"`
+	GridStates          []*models.GridState          `json:"grid_states"`
+	Cooldowns           []*models.SymbolCooldown     `json:"cooldowns"`
+	StrategyPauses      []*models.StrategyPause      `json:"strategy_pauses,omitempty"`
+	FundingArbPositions []*models.FundingArbPosition `json:"funding_arb_positions"`
+	RiskMetric          *models.RiskMetric           `json:"risk_metric,omitempty"`
+}
+
+// Summary describes the snapshot contents in one line
+func (s *Snapshot) Summary() string {
+	return fmt.Sprintf("%d positions, %d orders, %d strategy states, %d grid levels, %d cooldowns, %d funding arbitrage pairs",
+		len(s.Positions), len(s.Orders), len(s.StrategyStates), len(s.GridStates), len(s.Cooldowns), len(s.FundingArbPositions))
+}
+
+// Export reads the current bot state in one transaction so the parts are consistent
+func Export(db *gorm.DB, configHash string) (*Snapshot, error) {
+	host, _ := os.Hostname()
+	snap := &Snapshot{
+		Version:    Version,
+		CreatedAt:  time.Now().UTC(),
+		Host:       host,
+		ConfigHash: configHash,
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("status = ?", "OPEN").Order("id").Find(&snap.Positions).Error; err != nil {
+			return fmt.Errorf("failed to read positions: %w", err)
+		}
+
+		positionIDs := make([]uint, 0, len(snap.Positions))
+		for _, position := range snap.Positions {
+			positionIDs = append(positionIDs, position.ID)
+		}
+		query := tx.Where("status IN ?", []string{"NEW", "PARTIALLY_FILLED"})
+		if len(positionIDs) > 0 {
+			query = query.Or("position_id IN ?", positionIDs)
+		}
+		if err := query.Order("id").Find(&snap.Orders).Error; err != nil {
+			return fmt.Errorf("failed to read orders: %w", err)
+		}
+
+		if err := tx.Order("id").Find(&snap.StrategyStates).Error; err != nil {
+			return fmt.Errorf("failed to read strategy states: %w", err)
+		}
+		if err := tx.Order("id").Find(&snap.GridStates).Error; err != nil {
+			return fmt.Errorf("failed to read grid states: %w", err)
+		}
+		if err := tx.Order("id").Find(&snap.Cooldowns).Error; err != nil {
+			return fmt.Errorf("failed to read cooldowns: %w", err)
+		}
+		if err := tx.Order("id").Find(&snap.StrategyPauses).Error; err != nil {
+			return fmt.Errorf("failed to read strategy pauses: %w", err)
+		}
+		if err := tx.Where("status = ?", "OPEN").Order("id").Find(&snap.FundingArbPositions).Error; err != nil {
+			return fmt.Errorf("failed to read funding arbitrage positions: %w", err)
+		}
+
+		var metric models.RiskMetric
+		err := tx.Order("date DESC").First(&metric).Error
+		if err == nil {
+			snap.RiskMetric = &metric
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to read risk metrics: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// Write encodes the snapshot as indented JSON
+func Write(w io.Writer, snap *Snapshot) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snap)
+}
+
+// Read decodes a snapshot and checks its version
+func Read(r io.Reader) (*Snapshot, error) {
+	var snap Snapshot
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if snap.Version != Version {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d", snap.Version, Version)
+	}
+	return &snap, nil
+}
+
+// Restore writes the snapshot into db in one transaction. Positions, orders and funding
+// arbitrage pairs keep their IDs so references between them stay valid; the target must
+// not already have open positions or orders. Strategy, cooldown and pause state replace the
+// target's rows for the same keys and each snapshot grid replaces the symbol's grid.
+func Restore(db *gorm.DB, snap *Snapshot) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var open int64
+		if err := tx.Model(&models.Position{}).Where("status = ?", "OPEN").Count(&open).Error; err != nil {
+			return fmt.Errorf("failed to count open positions: %w", err)
+		}
+		if open > 0 {
+			return fmt.Errorf("target database already has %d open positions", open)
+		}
+		if err := tx.Model(&models.Order{}).Where("status IN ?", []string{"NEW", "PARTIALLY_FILLED"}).Count(&open).Error; err != nil {
+			return fmt.Errorf("failed to count open orders: %w", err)
+		}
+		if open > 0 {
+			return fmt.Errorf("target database already has %d open orders", open)
+		}
+
+		if len(snap.Positions) > 0 {
+			if err := tx.Create(snap.Positions).Error; err != nil {
+				return fmt.Errorf("failed to restore positions: %w", err)
+			}
+		}
+		if len(snap.Orders) > 0 {
+			if err := tx.Create(snap.Orders).Error; err != nil {
+				return fmt.Errorf("failed to restore orders: %w", err)
+			}
+		}
+		if len(snap.FundingArbPositions) > 0 {
+			if err := tx.Create(snap.FundingArbPositions).Error; err != nil {
+				return fmt.Errorf("failed to restore funding arbitrage positions: %w", err)
+			}
+		}
+
+		// Keyed state is matched on its unique keys rather than the source IDs
+		for _, state := range snap.StrategyStates {
+			state.ID = 0
+		}
+		for _, level := range snap.GridStates {
+			level.ID = 0
+		}
+		for _, cooldown := range snap.Cooldowns {
+			cooldown.ID = 0
+		}
+		for _, pause := range snap.StrategyPauses {
+			pause.ID = 0
+		}
+		if len(snap.StrategyStates) > 0 {
+			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(snap.StrategyStates).Error; err != nil {
+				return fmt.Errorf("failed to restore strategy states: %w", err)
+			}
+		}
+		if len(snap.GridStates) > 0 {
+			// A grid is restored whole; leftover levels of a differently sized grid would be managed too
+			symbols := make([]string, 0, len(snap.GridStates))
+			for _, level := range snap.GridStates {
+				symbols = append(symbols, level.Symbol)
+			}
+			if err := tx.Where("symbol IN ?", symbols).Delete(&models.GridState{}).Error; err != nil {
+				return fmt.Errorf("failed to clear grid states: %w", err)
+			}
+			if err := tx.Create(snap.GridStates).Error; err != nil {
+				return fmt.Errorf("failed to restore grid states: %w", err)
+			}
+		}
+		if len(snap.Cooldowns) > 0 {
+			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(snap.Cooldowns).Error; err != nil {
+				return fmt.Errorf("failed to restore cooldowns: %w", err)
+			}
+		}
+		if len(snap.StrategyPauses) > 0 {
+			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(snap.StrategyPauses).Error; err != nil {
+				return fmt.Errorf("failed to restore strategy pauses: %w", err)
+			}
+		}
+
+		if snap.RiskMetric != nil {
+			metric := *snap.RiskMetric
+			metric.ID = 0
+			if err := tx.Create(&metric).Error; err != nil {
+				return fmt.Errorf("failed to restore risk metrics: %w", err)
+			}
+		}
+		return nil
+	})
+}
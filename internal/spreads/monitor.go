@@ -0,0 +1,262 @@
+package spreads
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+
+	"github.com/sirupsen/logrus"
+)
+
+// referenceVenue names the exchange the bot trades on; spreads are measured against it
+const referenceVenue = "binance"
+
+// Spread is the latest price difference of a contract between another venue and Binance
+type Spread struct {
+	Symbol         string    `json:"symbol"`
+	Venue          string    `json:"venue"`
+	ReferencePrice float64   `json:"reference_price"`
+	VenuePrice     float64   `json:"venue_price"`
+	SpreadBps      float64   `json:"spread_bps"`
+	Alerting       bool      `json:"alerting"` // Spread crossed the alert threshold and has not reset yet
+	Time           time.Time `json:"time"`
+}
+
+// Signal is emitted when a spread crosses the alert threshold: buying on BuyVenue and selling
+// on SellVenue captures it
+type Signal struct {
+	Symbol    string    `json:"symbol"`
+	BuyVenue  string    `json:"buy_venue"`
+	SellVenue string    `json:"sell_venue"`
+	BuyPrice  float64   `json:"buy_price"`
+	SellPrice float64   `json:"sell_price"`
+	SpreadBps float64   `json:"spread_bps"`
+	Time      time.Time `json:"time"`
+}
+
+// Monitor samples the traded contracts on other venues, stores the spread series and alerts
+// when a spread widens past the configured threshold
+type Monitor struct {
+	config     config.SpreadsConfig
+	symbols    []string
+	reference  exchange.Client
+	venues     []exchange.PriceVenue
+	repository database.Repository
+	notifier   notify.Notifier
+	logger     *logrus.Logger
+	signals    chan *Signal
+
+	mu     sync.RWMutex
+	latest map[string]*Spread // keyed by symbol and venue
+}
+
+// NewMonitor creates a spread monitor for the given symbols
+func NewMonitor(cfg config.SpreadsConfig, symbols []string, reference exchange.Client, repository database.Repository, notifier notify.Notifier, logger *logrus.Logger) (*Monitor, error) {
+	timeout := time.Duration(cfg.TimeoutSeconds) * time.Second
+
+	venues := make([]exchange.PriceVenue, 0, len(cfg.Venues))
+	for _, name := range cfg.Venues {
+		venue, err := exchange.NewPriceVenue(name, timeout)
+		if err != nil {
+			return nil, err
+		}
+		venues = append(venues, venue)
+	}
+
+	return &Monitor{
+		config:     cfg,
+		symbols:    symbols,
+		reference:  reference,
+		venues:     venues,
+		repository: repository,
+		notifier:   notifier,
+		logger:     logger,
+		signals:    make(chan *Signal, 100),
+		latest:     make(map[string]*Spread),
+	}, nil
+}
+
+// Start begins sampling the venues until ctx is cancelled
+func (m *Monitor) Start(ctx context.Context) {
+	go m.pollLoop(ctx)
+}
+
+// Signals returns the channel alert signals are published on. Signals are dropped when
+// nobody is reading.
+func (m *Monitor) Signals() <-chan *Signal {
+	return m.signals
+}
+
+// Latest returns the most recent spread of every symbol and venue
+func (m *Monitor) Latest() []*Spread {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	spreads := make([]*Spread, 0, len(m.latest))
+	for _, spread := range m.latest {
+		copied := *spread
+		spreads = append(spreads, &copied)
+	}
+	sort.Slice(spreads, func(i, j int) bool {
+		if spreads[i].Symbol != spreads[j].Symbol {
+			return spreads[i].Symbol < spreads[j].Symbol
+		}
+		return spreads[i].Venue < spreads[j].Venue
+	})
+	return spreads
+}
+
+// History returns the stored spread series of a symbol, optionally for a single venue
+func (m *Monitor) History(symbol, venue string, since time.Time) ([]*models.SpreadSample, error) {
+	samples, err := m.repository.GetSpreadSamples(symbol, venue, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spread samples: %w", err)
+	}
+	return samples, nil
+}
+
+func (m *Monitor) pollLoop(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(m.config.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		m.sample(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sample records one spread per symbol and venue
+func (m *Monitor) sample(ctx context.Context) {
+	for _, symbol := range m.symbols {
+		reference, err := m.reference.GetSymbolPrice(ctx, symbol)
+		if err != nil {
+			m.logger.Warnf("Failed to get %s price for spread monitor: %v", symbol, err)
+			continue
+		}
+		if reference <= 0 {
+			continue
+		}
+
+		for _, venue := range m.venues {
+			price, err := venue.GetSymbolPrice(ctx, symbol)
+			if err != nil {
+				m.logger.Warnf("Failed to get %s price from %s: %v", symbol, venue.Name(), err)
+				continue
+			}
+			if price <= 0 {
+				continue
+			}
+
+			m.record(ctx, symbol, venue.Name(), reference, price)
+		}
+	}
+}
+
+// record stores the sample and alerts once per excursion: after crossing AlertBps the spread
+// must narrow below ResetBps before it alerts again
+func (m *Monitor) record(ctx context.Context, symbol, venue string, reference, price float64) {
+	now := time.Now()
+	spreadBps := (price - reference) / reference * 10000
+
+	sample := &models.SpreadSample{
+		Symbol:         symbol,
+		Venue:          venue,
+		ReferencePrice: reference,
+		VenuePrice:     price,
+		SpreadBps:      spreadBps,
+	}
+	if err := m.repository.SaveSpreadSample(sample); err != nil {
+		m.logger.Errorf("Failed to save spread sample: %v", err)
+	}
+
+	key := symbol + ":" + venue
+
+	m.mu.Lock()
+	alerting := false
+	if previous, ok := m.latest[key]; ok {
+		alerting = previous.Alerting
+	}
+
+	trigger := false
+	switch {
+	case math.Abs(spreadBps) >= m.config.AlertBps && !alerting:
+		alerting, trigger = true, true
+	case math.Abs(spreadBps) < m.config.ResetBps:
+		alerting = false
+	}
+
+	m.latest[key] = &Spread{
+		Symbol:         symbol,
+		Venue:          venue,
+		ReferencePrice: reference,
+		VenuePrice:     price,
+		SpreadBps:      spreadBps,
+		Alerting:       alerting,
+		Time:           now,
+	}
+	m.mu.Unlock()
+
+	if trigger {
+		m.emit(ctx, symbol, venue, reference, price, spreadBps, now)
+	}
+}
+
+// emit publishes a signal for the cheap and expensive side and notifies the operator
+func (m *Monitor) emit(ctx context.Context, symbol, venue string, reference, price, spreadBps float64, now time.Time) {
+	signal := &Signal{
+		Symbol:    symbol,
+		BuyVenue:  referenceVenue,
+		SellVenue: venue,
+		BuyPrice:  reference,
+		SellPrice: price,
+		SpreadBps: math.Abs(spreadBps),
+		Time:      now,
+	}
+	if spreadBps < 0 {
+		signal.BuyVenue, signal.SellVenue = venue, referenceVenue
+		signal.BuyPrice, signal.SellPrice = price, reference
+	}
+
+	select {
+	case m.signals <- signal:
+	default:
+		m.logger.Debugf("Spread signal channel full, dropping %s signal", symbol)
+	}
+
+	m.logger.Infof("Spread alert for %s: buy %s %.6f, sell %s %.6f (%.1f bps)",
+		symbol, signal.BuyVenue, signal.BuyPrice, signal.SellVenue, signal.SellPrice, signal.SpreadBps)
+
+	if m.notifier == nil {
+		return
+	}
+
+	msg := &notify.Message{
+		Title:  fmt.Sprintf("Cross-exchange spread: %s %.1f bps", symbol, signal.SpreadBps),
+		Body:   fmt.Sprintf("Buy on %s at %.6f and sell on %s at %.6f", signal.BuyVenue, signal.BuyPrice, signal.SellVenue, signal.SellPrice),
+		Level:  notify.LevelWarning,
+		Symbol: symbol,
+		Fields: map[string]string{
+			"buy_venue":  signal.BuyVenue,
+			"sell_venue": signal.SellVenue,
+			"spread_bps": fmt.Sprintf("%.1f", signal.SpreadBps),
+		},
+		Time: now,
+	}
+	if err := m.notifier.Notify(ctx, msg); err != nil {
+		m.logger.Errorf("Failed to deliver spread notification: %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+package tax
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"contract_playground/internal/models"
+)
+
+// Date format used on Form 8949
+const dateLayout = "01/02/2006"
+
+// Quantities below this are treated as fully matched
+const dustQuantity = 1e-12
+
+// Income types already covered by trades, and transfers, which are not taxable events
+var skippedIncomeTypes = map[string]bool{
+	"REALIZED_PNL": true,
+	"COMMISSION":   true,
+	"TRANSFER":     true,
+}
+
+// Row is one line of the export in the shape of Form 8949
+type Row struct {
+	Description  string
+	DateAcquired time.Time
+	DateSold     time.Time
+	Proceeds     float64
+	CostBasis    float64
+	Gain         float64
+	Term         string // short or long
+}
+
+// lot is the open remainder of an opening fill; fee is the commission per unit
+type lot struct {
+	quantity float64
+	price    float64
+	fee      float64
+	time     time.Time
+	short    bool
+}
+
+// BuildRows matches fills first-in first-out per symbol and returns the disposals closed in
+// the calendar year (UTC), followed by funding and other income aggregated per symbol and
+// month. trades must include every fill before the end of the year so that lots opened in
+// earlier years are known. A short lot is reported with the short sale as the sale and the
+// cover as the purchase, in the year it is covered. Commissions paid in the quote asset
+// are added to the cost basis or deducted from the proceeds; other commission assets are
+// left out.
+func BuildRows(trades []*models.Trade, incomes []*models.Income, year int) []*Row {
+	sorted := make([]*models.Trade, len(trades))
+	copy(sorted, trades)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TradeTime.Before(sorted[j].TradeTime)
+	})
+
+	var rows []*Row
+	open := make(map[string][]*lot)
+	for _, trade := range sorted {
+		if trade.Quantity <= 0 {
+			continue
+		}
+		fee := 0.0
+		if trade.CommissionAsset != "" && strings.HasSuffix(trade.Symbol, trade.CommissionAsset) {
+			fee = trade.Commission / trade.Quantity
+		}
+		buy := trade.Side == "BUY"
+		remaining := trade.Quantity
+
+		lots := open[trade.Symbol]
+		for remaining > dustQuantity && len(lots) > 0 && lots[0].short == buy {
+			matched := lots[0]
+			quantity := math.Min(remaining, matched.quantity)
+			if trade.TradeTime.UTC().Year() == year {
+				rows = append(rows, disposal(trade.Symbol, matched, trade, fee, quantity))
+			}
+
+			matched.quantity -= quantity
+			remaining -= quantity
+			if matched.quantity <= dustQuantity {
+				lots = lots[1:]
+			}
+		}
+		if remaining > dustQuantity {
+			lots = append(lots, &lot{
+				quantity: remaining,
+				price:    trade.Price,
+				fee:      fee,
+				time:     trade.TradeTime,
+				short:    !buy,
+			})
+		}
+		open[trade.Symbol] = lots
+	}
+
+	return append(rows, incomeRows(incomes, year)...)
+}
+
+// disposal builds the row for quantity of an open lot closed by trade
+func disposal(symbol string, matched *lot, trade *models.Trade, tradeFee, quantity float64) *Row {
+	row := &Row{
+		Description:  fmt.Sprintf("%s %s", formatQuantity(quantity), symbol),
+		DateAcquired: matched.time,
+		DateSold:     trade.TradeTime,
+	}
+	if matched.short {
+		row.Description += " (short)"
+		row.Proceeds = quantity * (matched.price - matched.fee)
+		row.CostBasis = quantity * (trade.Price + tradeFee)
+	} else {
+		row.Proceeds = quantity * (trade.Price - tradeFee)
+		row.CostBasis = quantity * (matched.price + matched.fee)
+	}
+	row.Gain = row.Proceeds - row.CostBasis
+	row.Term = term(row.DateAcquired, row.DateSold)
+	return row
+}
+
+// incomeRows aggregates funding and other income per symbol, type and month of the year
+func incomeRows(incomes []*models.Income, year int) []*Row {
+	type key struct {
+		symbol     string
+		incomeType string
+		month      time.Month
+	}
+
+	totals := make(map[key]*Row)
+	var keys []key
+	for _, income := range incomes {
+		at := income.Time.UTC()
+		if at.Year() != year || skippedIncomeTypes[income.IncomeType] {
+			continue
+		}
+		k := key{symbol: income.Symbol, incomeType: income.IncomeType, month: at.Month()}
+		row, ok := totals[k]
+		if !ok {
+			row = &Row{
+				Description:  strings.TrimSpace(fmt.Sprintf("%s %s %d-%02d", income.Symbol, income.IncomeType, year, at.Month())),
+				DateAcquired: at,
+				DateSold:     at,
+				Term:         "short",
+			}
+			totals[k] = row
+			keys = append(keys, k)
+		}
+		if at.Before(row.DateAcquired) {
+			row.DateAcquired = at
+		}
+		if at.After(row.DateSold) {
+			row.DateSold = at
+		}
+		row.Gain += income.Income
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].month != keys[j].month {
+			return keys[i].month < keys[j].month
+		}
+		if keys[i].symbol != keys[j].symbol {
+			return keys[i].symbol < keys[j].symbol
+		}
+		return keys[i].incomeType < keys[j].incomeType
+	})
+
+	rows := make([]*Row, 0, len(keys))
+	for _, k := range keys {
+		row := totals[k]
+		// Net income is reported as proceeds, a net payment as cost basis
+		row.Proceeds = math.Max(row.Gain, 0)
+		row.CostBasis = math.Max(-row.Gain, 0)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// term is long for holdings of more than one year
+func term(acquired, sold time.Time) string {
+	if sold.After(acquired.AddDate(1, 0, 0)) {
+		return "long"
+	}
+	return "short"
+}
+
+// WriteCSV writes the rows with a header and a closing total line
+func WriteCSV(w io.Writer, rows []*Row) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"description", "date_acquired", "date_sold", "proceeds", "cost_basis", "gain_or_loss", "term"}); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	var proceeds, costBasis, gain float64
+	for _, row := range rows {
+		proceeds += row.Proceeds
+		costBasis += row.CostBasis
+		gain += row.Gain
+		record := []string{
+			row.Description,
+			row.DateAcquired.UTC().Format(dateLayout),
+			row.DateSold.UTC().Format(dateLayout),
+			formatAmount(row.Proceeds),
+			formatAmount(row.CostBasis),
+			formatAmount(row.Gain),
+			row.Term,
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write row: %w", err)
+		}
+	}
+
+	if err := writer.Write([]string{"TOTAL", "", "", formatAmount(proceeds), formatAmount(costBasis), formatAmount(gain), ""}); err != nil {
+		return fmt.Errorf("failed to write total: %w", err)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatAmount(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func formatQuantity(value float64) string {
+	return strings.TrimRight(strings.TrimRight(strconv.FormatFloat(value, 'f', 8, 64), "0"), ".")
+}
@@ -0,0 +1,173 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"contract_playground/internal/events"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+)
+
+// adlHedgeKey only marks cross margined hedge mode positions in the exchange's response
+const adlHedgeKey = "HEDGE"
+
+// ADLStatus is the latest auto-deleveraging quantile of a position side
+type ADLStatus struct {
+	Symbol   string    `json:"symbol"`
+	Side     string    `json:"side"`     // LONG, SHORT or BOTH
+	Quantile int       `json:"quantile"` // 0 (last in the queue) to 4 (deleveraged first)
+	Alerting bool      `json:"alerting"` // At or above alert_quantile
+	Since    time.Time `json:"since"`    // When the quantile last changed
+}
+
+// adlChanges are the position sides whose quantile changed in one check
+type adlChanges struct {
+	current map[string]*ADLStatus
+	changed []*ADLStatus // Stored as history
+	raised  []*ADLStatus // Reached the alert quantile
+	cleared []*ADLStatus // Fell below the alert quantile
+}
+
+// diffADL compares the exchange's quantiles with the previous check. A side seen for the
+// first time counts as coming from 0; sides without a position any more are dropped.
+func diffADL(previous map[string]*ADLStatus, quantiles []*exchange.ADLQuantile, alertQuantile int, now time.Time) *adlChanges {
+	changes := &adlChanges{current: make(map[string]*ADLStatus)}
+	for _, quantile := range quantiles {
+		for side, value := range quantile.Quantiles {
+			if side == adlHedgeKey {
+				continue
+			}
+			key := quantile.Symbol + " " + side
+			last, seen := previous[key]
+			if seen && last.Quantile == value {
+				changes.current[key] = last
+				continue
+			}
+
+			status := &ADLStatus{Symbol: quantile.Symbol, Side: side, Quantile: value, Alerting: value >= alertQuantile, Since: now}
+			changes.current[key] = status
+			if !seen && value == 0 {
+				continue
+			}
+			changes.changed = append(changes.changed, status)
+			wasAlerting := seen && last.Alerting
+			if status.Alerting && !wasAlerting {
+				changes.raised = append(changes.raised, status)
+			} else if !status.Alerting && wasAlerting {
+				changes.cleared = append(changes.cleared, status)
+			}
+		}
+	}
+	for _, list := range [][]*ADLStatus{changes.changed, changes.raised, changes.cleared} {
+		sortADL(list)
+	}
+	return changes
+}
+
+func sortADL(statuses []*ADLStatus) {
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Symbol != statuses[j].Symbol {
+			return statuses[i].Symbol < statuses[j].Symbol
+		}
+		return statuses[i].Side < statuses[j].Side
+	})
+}
+
+// checkADL fetches the ADL quantiles, stores the changes and alerts on position sides that
+// reached or left the alert quantile
+func (e *Engine) checkADL(ctx context.Context) error {
+	quantiles, err := e.exchangeClient.GetADLQuantiles(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	e.adlMu.Lock()
+	changes := diffADL(e.adl, quantiles, e.config.ADL.AlertQuantile, now)
+	e.adl = changes.current
+	prune := e.config.ADL.RetentionDays > 0 && now.Sub(e.adlPruned) >= time.Hour
+	if prune {
+		e.adlPruned = now
+	}
+	e.adlMu.Unlock()
+
+	records := make([]*models.ADLQuantile, 0, len(changes.changed))
+	for _, status := range changes.changed {
+		records = append(records, &models.ADLQuantile{Symbol: status.Symbol, Side: status.Side, Quantile: status.Quantile, CreatedAt: now})
+	}
+	if err := e.repository.SaveADLQuantiles(records); err != nil {
+		return fmt.Errorf("failed to save ADL quantiles: %w", err)
+	}
+
+	if len(changes.raised) > 0 {
+		e.alertADL(ctx, changes.raised)
+	}
+	for _, status := range changes.cleared {
+		e.logger.Infof("ADL quantile of %s %s down to %d", status.Symbol, status.Side, status.Quantile)
+		e.publishEvent(events.TypeRisk, "adl_cleared", status.Symbol, status)
+	}
+
+	if prune {
+		if _, err := e.repository.DeleteADLQuantilesBefore(now.AddDate(0, 0, -e.config.ADL.RetentionDays)); err != nil {
+			return fmt.Errorf("failed to prune ADL quantiles: %w", err)
+		}
+	}
+	return nil
+}
+
+// alertADL notifies the position sides that reached the alert quantile along with the
+// stored positions of their symbols
+func (e *Engine) alertADL(ctx context.Context, raised []*ADLStatus) {
+	positions, err := e.repository.GetAllPositions()
+	if err != nil {
+		e.logger.Errorf("Failed to get positions for the ADL alert: %v", err)
+	}
+
+	lines := make([]string, 0, len(raised))
+	for _, status := range raised {
+		e.logger.Warnf("ADL quantile of %s %s reached %d", status.Symbol, status.Side, status.Quantile)
+		e.publishEvent(events.TypeRisk, "adl", status.Symbol, status)
+
+		line := fmt.Sprintf("%s %s: quantile %d of 4", status.Symbol, status.Side, status.Quantile)
+		for _, position := range positions {
+			if position.Symbol == status.Symbol {
+				line += fmt.Sprintf(", position %g @ %.4f, unrealized PnL %.2f", position.Size, position.EntryPrice, position.UnrealizedPnL)
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	msg := &notify.Message{
+		Title: "Position near auto-deleveraging",
+		Body: "These positions are at the front of the exchange's auto-deleveraging queue and may be closed " +
+			"at the bankruptcy price of a liquidated counterparty. Consider reducing them.\n" + strings.Join(lines, "\n"),
+		Level: notify.LevelCritical,
+		Time:  time.Now(),
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver ADL notification: %v", err)
+	}
+}
+
+// ADLQuantiles returns the latest quantile of every position side, ordered by symbol
+func (e *Engine) ADLQuantiles() []*ADLStatus {
+	e.adlMu.Lock()
+	defer e.adlMu.Unlock()
+
+	statuses := make([]*ADLStatus, 0, len(e.adl))
+	for _, status := range e.adl {
+		statuses = append(statuses, status)
+	}
+	sortADL(statuses)
+	return statuses
+}
+
+// ADLHistory returns the recorded quantile changes since the given time, oldest first
+func (e *Engine) ADLHistory(symbol string, since time.Time) ([]*models.ADLQuantile, error) {
+	return e.repository.GetADLQuantiles(symbol, since)
+}
@@ -0,0 +1,49 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"contract_playground/internal/exchange"
+)
+
+func TestDiffADL(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	first := diffADL(nil, []*exchange.ADLQuantile{
+		{Symbol: "BTCUSDT", Quantiles: map[string]int{"LONG": 2, "SHORT": 0, "BOTH": 0}},
+		{Symbol: "ETHUSDT", Quantiles: map[string]int{"LONG": 4, "SHORT": 1, "HEDGE": 0}},
+	}, 4, start)
+	if len(first.current) != 5 {
+		t.Fatalf("tracked %d sides, want 5 without the HEDGE marker", len(first.current))
+	}
+	// Sides first seen at 0 are tracked but not stored
+	if len(first.changed) != 3 || len(first.raised) != 1 || first.raised[0].Symbol != "ETHUSDT" || first.raised[0].Side != "LONG" {
+		t.Fatalf("changed = %d, raised = %+v, want 3 changes and ETHUSDT LONG raised", len(first.changed), first.raised)
+	}
+
+	later := start.Add(30 * time.Second)
+	second := diffADL(first.current, []*exchange.ADLQuantile{
+		{Symbol: "BTCUSDT", Quantiles: map[string]int{"LONG": 4, "SHORT": 0, "BOTH": 0}},
+		{Symbol: "ETHUSDT", Quantiles: map[string]int{"LONG": 3, "SHORT": 1}},
+	}, 4, later)
+	if len(second.changed) != 2 {
+		t.Errorf("changed = %d, want BTCUSDT LONG and ETHUSDT LONG", len(second.changed))
+	}
+	if len(second.raised) != 1 || second.raised[0].Symbol != "BTCUSDT" {
+		t.Errorf("raised = %+v, want BTCUSDT LONG", second.raised)
+	}
+	if len(second.cleared) != 1 || second.cleared[0].Symbol != "ETHUSDT" {
+		t.Errorf("cleared = %+v, want ETHUSDT LONG", second.cleared)
+	}
+	if unchanged := second.current["ETHUSDT SHORT"]; !unchanged.Since.Equal(start) {
+		t.Errorf("unchanged side since %v, want %v", unchanged.Since, start)
+	}
+
+	// A closed position drops out; an alert that is still on is not raised again
+	third := diffADL(second.current, []*exchange.ADLQuantile{
+		{Symbol: "BTCUSDT", Quantiles: map[string]int{"LONG": 4, "SHORT": 0, "BOTH": 0}},
+	}, 4, later.Add(30*time.Second))
+	if len(third.current) != 3 || len(third.raised) != 0 || len(third.changed) != 0 {
+		t.Errorf("current = %d, raised = %d, changed = %d, want 3, 0, 0", len(third.current), len(third.raised), len(third.changed))
+	}
+}
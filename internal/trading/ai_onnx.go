@@ -0,0 +1,103 @@
+//go:build onnx
+
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	ort "github.com/yalue/onnxruntime_go"
+)
+
+// onnxClasses maps the model output indices to predicted directions
+var onnxClasses = []string{"DOWN", "FLAT", "UP"}
+
+var onnxEnvOnce sync.Once
+var onnxEnvErr error
+
+// onnxPredictor runs a local ONNX classification model in-process. The model must take a
+// [1, N] float32 feature tensor and produce [1, 3] class probabilities (DOWN, FLAT, UP).
+type onnxPredictor struct {
+	mu      sync.Mutex
+	session *ort.AdvancedSession
+	input   *ort.Tensor[float32]
+	output  *ort.Tensor[float32]
+}
+
+// newONNXPredictor loads the model file and allocates its input/output tensors
+func newONNXPredictor(modelPath, libraryPath, inputName, outputName string, numFeatures int) (aiPredictor, error) {
+	onnxEnvOnce.Do(func() {
+		if libraryPath != "" {
+			ort.SetSharedLibraryPath(libraryPath)
+		}
+		onnxEnvErr = ort.InitializeEnvironment()
+	})
+	if onnxEnvErr != nil {
+		return nil, fmt.Errorf("failed to initialize onnxruntime: %w", onnxEnvErr)
+	}
+
+	input, err := ort.NewEmptyTensor[float32](ort.NewShape(1, int64(numFeatures)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create input tensor: %w", err)
+	}
+
+	output, err := ort.NewEmptyTensor[float32](ort.NewShape(1, int64(len(onnxClasses))))
+	if err != nil {
+		input.Destroy()
+		return nil, fmt.Errorf("failed to create output tensor: %w", err)
+	}
+
+	session, err := ort.NewAdvancedSession(modelPath,
+		[]string{inputName}, []string{outputName},
+		[]ort.Value{input}, []ort.Value{output}, nil)
+	if err != nil {
+		input.Destroy()
+		output.Destroy()
+		return nil, fmt.Errorf("failed to create session for %s: %w", modelPath, err)
+	}
+
+	return &onnxPredictor{session: session, input: input, output: output}, nil
+}
+
+// Predict runs the model on the feature vector and returns the most likely class
+func (o *onnxPredictor) Predict(ctx context.Context, req *aiPredictRequest) (*aiPredictResponse, error) {
+	vector := req.Features.Vector()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	data := o.input.GetData()
+	if len(vector) != len(data) {
+		return nil, fmt.Errorf("feature vector has %d values, model expects %d", len(vector), len(data))
+	}
+	copy(data, vector)
+
+	if err := o.session.Run(); err != nil {
+		return nil, fmt.Errorf("onnx inference failed: %w", err)
+	}
+
+	probabilities := o.output.GetData()
+	best := 0
+	for i := range probabilities {
+		if probabilities[i] > probabilities[best] {
+			best = i
+		}
+	}
+
+	return &aiPredictResponse{
+		Direction:   onnxClasses[best],
+		Probability: float64(probabilities[best]),
+	}, nil
+}
+
+// Close releases the session and tensors
+func (o *onnxPredictor) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.session.Destroy()
+	o.input.Destroy()
+	o.output.Destroy()
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !onnx
+
+package trading
+
+import "fmt"
+
+// newONNXPredictor is unavailable unless the binary is built with -tags onnx
+func newONNXPredictor(modelPath, libraryPath, inputName, outputName string, numFeatures int) (aiPredictor, error) {
+	return nil, fmt.Errorf("ONNX support not compiled in; rebuild with -tags onnx to load %s", modelPath)
+}
@@ -1,40 +1,368 @@
 package trading
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
 	"contract_playground/internal/models"
+	"contract_playground/pkg/utils"
 )
 
+// AIStrategy delegates trading decisions to a model, either served by an external
+// inference endpoint or loaded in-process from an ONNX file
 type AIStrategy struct {
-	name string
+	name          string
+	endpoint      string
+	apiKey        string
+	timeout       time.Duration
+	minConfidence float64
+	rsiPeriod     int
+	volumePeriod  int
+	returnWindow  int
+	modelPath     string
+	onnxLibrary   string
+	inputName     string
+	outputName    string
+	predictor     aiPredictor
+}
+
+// aiPredictor produces a direction/probability prediction from engineered features
+type aiPredictor interface {
+	Predict(ctx context.Context, req *aiPredictRequest) (*aiPredictResponse, error)
+	Close() error
+}
+
+// AIFeatures holds the engineered features sent to the inference endpoint
+type AIFeatures struct {
+	Return1     float64 `json:"return_1"`
+	Return5     float64 `json:"return_5"`
+	Return20    float64 `json:"return_20"`
+	RSI         float64 `json:"rsi"`
+	Volume      float64 `json:"volume"`
+	VolumeRatio float64 `json:"volume_ratio"`
+	Volatility  float64 `json:"volatility"`
+	FundingRate float64 `json:"funding_rate"`
+
+	// Per-candle percentage returns of the last return_window klines, oldest first
+	Returns []float64 `json:"returns,omitempty"`
+}
+
+// Vector flattens the features into the fixed input order used by local models
+func (f *AIFeatures) Vector() []float32 {
+	vector := []float32{
+		float32(f.Return1),
+		float32(f.Return5),
+		float32(f.Return20),
+		float32(f.RSI),
+		float32(f.Volume),
+		float32(f.VolumeRatio),
+		float32(f.Volatility),
+		float32(f.FundingRate),
+	}
+	for _, r := range f.Returns {
+		vector = append(vector, float32(r))
+	}
+	return vector
+}
+
+// aiBaseFeatureCount is the number of fixed features preceding the returns window
+const aiBaseFeatureCount = 8
+
+// aiPredictRequest is the payload posted to the inference endpoint
+type aiPredictRequest struct {
+	Symbol    string      `json:"symbol"`
+	Timestamp int64       `json:"timestamp"`
+	Price     float64     `json:"price"`
+	Features  *AIFeatures `json:"features"`
+	Position  *aiPosition `json:"position,omitempty"`
 }
 
+type aiPosition struct {
+	Side       string  `json:"side"`
+	Size       float64 `json:"size"`
+	EntryPrice float64 `json:"entry_price"`
+}
+
+// aiPredictResponse is the expected response of the inference endpoint
+type aiPredictResponse struct {
+	Direction   string  `json:"direction"`   // UP, DOWN, FLAT
+	Probability float64 `json:"probability"` // 0.0 to 1.0
+}
+
+// NewAIStrategy creates a new AI strategy
 func NewAIStrategy() Strategy {
 	return &AIStrategy{
-		name: "AIStrategy",
+		name:          "AIStrategy",
+		timeout:       2 * time.Second,
+		minConfidence: 0.6,
+		rsiPeriod:     14,
+		volumePeriod:  20,
+		inputName:     "input",
+		outputName:    "output",
 	}
 }
 
+// Name returns the strategy name
 func (a *AIStrategy) Name() string {
 	return a.name
 }
 
+// Initialize initializes the strategy with parameters
 func (a *AIStrategy) Initialize(config map[string]interface{}) error {
+	if endpoint, ok := getStringParam(config, "endpoint"); ok {
+		a.endpoint = endpoint
+	}
+
+	if apiKey, ok := getStringParam(config, "api_key"); ok {
+		a.apiKey = apiKey
+	}
+
+	if ms, ok := getFloatParam(config, "timeout_ms"); ok {
+		a.timeout = time.Duration(ms) * time.Millisecond
+	}
+
+	if conf, ok := getFloatParam(config, "min_confidence"); ok {
+		a.minConfidence = conf
+	}
+
+	if period, ok := getFloatParam(config, "rsi_period"); ok {
+		a.rsiPeriod = int(period)
+	}
+
+	if period, ok := getFloatParam(config, "volume_period"); ok {
+		a.volumePeriod = int(period)
+	}
+
+	if window, ok := getFloatParam(config, "return_window"); ok {
+		a.returnWindow = int(window)
+	}
+
+	if path, ok := getStringParam(config, "model_path"); ok {
+		a.modelPath = path
+	}
+
+	if lib, ok := getStringParam(config, "onnx_library"); ok {
+		a.onnxLibrary = lib
+	}
+
+	if name, ok := getStringParam(config, "input_name"); ok {
+		a.inputName = name
+	}
+
+	if name, ok := getStringParam(config, "output_name"); ok {
+		a.outputName = name
+	}
+
+	if a.returnWindow < 0 {
+		return fmt.Errorf("AI strategy return window must not be negative")
+	}
+
+	// A local model takes precedence over the remote endpoint
+	if a.modelPath != "" {
+		predictor, err := newONNXPredictor(a.modelPath, a.onnxLibrary, a.inputName, a.outputName, aiBaseFeatureCount+a.returnWindow)
+		if err != nil {
+			return fmt.Errorf("failed to load ONNX model: %w", err)
+		}
+		a.predictor = predictor
+		return nil
+	}
+
+	if a.endpoint == "" {
+		return fmt.Errorf("AI strategy requires an inference endpoint or a model_path")
+	}
+
+	if a.timeout <= 0 {
+		return fmt.Errorf("AI strategy timeout must be positive")
+	}
+
+	a.predictor = &httpPredictor{
+		endpoint:   a.endpoint,
+		apiKey:     a.apiKey,
+		timeout:    a.timeout,
+		httpClient: &http.Client{Timeout: a.timeout},
+	}
+
 	return nil
 }
 
+// ShouldBuy asks the inference endpoint whether to open a long position
 func (a *AIStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
-	// 在这里实现您的 AI 决策逻辑
-	// 您可以使用 'data' 参数来获取市场数据
+	prediction, err := a.predict(ctx, symbol, data, nil)
+	if err != nil {
+		return &Signal{Action: "HOLD", Reason: fmt.Sprintf("AI inference unavailable: %v", err)}, nil
+	}
+
+	if prediction.Direction == "UP" && prediction.Probability >= a.minConfidence {
+		return &Signal{
+			Action:       "BUY",
+			Price:        data.Price,
+			Confidence:   prediction.Probability,
+			Reason:       fmt.Sprintf("AI predicted UP with probability %.2f", prediction.Probability),
+			PositionSide: "LONG",
+		}, nil
+	}
+
 	return &Signal{
-		Action: "BUY", // 这是一个示例，您需要替换为真实的决策
+		Action: "HOLD",
+		Reason: fmt.Sprintf("AI predicted %s with probability %.2f", prediction.Direction, prediction.Probability),
 	}, nil
 }
 
+// ShouldSell asks the inference endpoint whether to close the current position
 func (a *AIStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
-	// 在这里实现您的 AI 决策逻辑
-	// 您可以使用 'position' 参数来获取当前持仓信息
+	prediction, err := a.predict(ctx, symbol, data, position)
+	if err != nil {
+		return &Signal{Action: "HOLD", Reason: fmt.Sprintf("AI inference unavailable: %v", err)}, nil
+	}
+
+	if prediction.Direction == "DOWN" && prediction.Probability >= a.minConfidence {
+		return &Signal{
+			Action:     "SELL",
+			Quantity:   position.Size,
+			Price:      data.Price,
+			Confidence: prediction.Probability,
+			Reason:     fmt.Sprintf("AI predicted DOWN with probability %.2f", prediction.Probability),
+		}, nil
+	}
+
 	return &Signal{
-		Action: "SELL", // 这是一个示例，您需要替换为真实的决策
+		Action: "HOLD",
+		Reason: fmt.Sprintf("AI predicted %s with probability %.2f", prediction.Direction, prediction.Probability),
 	}, nil
 }
+
+// predict builds the features for the current market data and runs the predictor
+func (a *AIStrategy) predict(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*aiPredictResponse, error) {
+	if a.predictor == nil {
+		return nil, fmt.Errorf("strategy not initialized")
+	}
+
+	payload := &aiPredictRequest{
+		Symbol:    symbol,
+		Timestamp: data.Timestamp.Unix(),
+		Price:     data.Price,
+		Features:  a.buildFeatures(data),
+	}
+	if position != nil {
+		payload.Position = &aiPosition{
+			Side:       position.PositionSide,
+			Size:       position.Size,
+			EntryPrice: position.EntryPrice,
+		}
+	}
+
+	prediction, err := a.predictor.Predict(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	prediction.Direction = strings.ToUpper(prediction.Direction)
+	if prediction.Probability < 0 || prediction.Probability > 1 {
+		return nil, fmt.Errorf("prediction probability %.4f out of range", prediction.Probability)
+	}
+
+	return prediction, nil
+}
+
+// httpPredictor posts features to an external inference endpoint
+type httpPredictor struct {
+	endpoint   string
+	apiKey     string
+	timeout    time.Duration
+	httpClient *http.Client
+}
+
+// Predict posts the engineered features to the inference endpoint and parses the prediction
+func (h *httpPredictor) Predict(ctx context.Context, payload *aiPredictRequest) (*aiPredictResponse, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode features: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inference request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+h.apiKey)
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("inference request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("inference endpoint returned status %d", resp.StatusCode)
+	}
+
+	var prediction aiPredictResponse
+	if err := json.NewDecoder(resp.Body).Decode(&prediction); err != nil {
+		return nil, fmt.Errorf("failed to decode prediction: %w", err)
+	}
+
+	return &prediction, nil
+}
+
+// Close releases predictor resources
+func (h *httpPredictor) Close() error {
+	h.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// buildFeatures computes the model input features from the kline window
+func (a *AIStrategy) buildFeatures(data *MarketData) *AIFeatures {
+	closes := make([]float64, 0, len(data.Klines))
+	volumes := make([]float64, 0, len(data.Klines))
+	for _, k := range data.Klines {
+		closes = append(closes, k.Close)
+		volumes = append(volumes, k.Volume)
+	}
+
+	features := &AIFeatures{
+		Return1:     periodReturn(closes, 1),
+		Return5:     periodReturn(closes, 5),
+		Return20:    periodReturn(closes, 20),
+		RSI:         utils.CalculateRSI(closes, a.rsiPeriod),
+		Volume:      data.Volume,
+		Volatility:  utils.CalculateVolatility(closes),
+		FundingRate: data.FundingRate,
+	}
+
+	avgVolume := utils.CalculateMovingAverage(volumes, a.volumePeriod)
+	if avgVolume > 0 {
+		features.VolumeRatio = data.Volume / avgVolume
+	}
+
+	// Local models need a fixed-size input, so the window is zero-padded when history is short
+	if a.returnWindow > 0 {
+		features.Returns = make([]float64, a.returnWindow)
+		for i := 0; i < a.returnWindow; i++ {
+			idx := len(closes) - a.returnWindow + i
+			if idx >= 1 {
+				features.Returns[i] = utils.CalculatePercentageChange(closes[idx-1], closes[idx])
+			}
+		}
+	}
+
+	return features
+}
+
+// periodReturn returns the percentage return over the last n closes
+func periodReturn(closes []float64, n int) float64 {
+	if len(closes) <= n {
+		return 0
+	}
+
+	return utils.CalculatePercentageChange(closes[len(closes)-1-n], closes[len(closes)-1])
+}
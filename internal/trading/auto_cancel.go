@@ -0,0 +1,43 @@
+package trading
+
+import (
+	"context"
+	"time"
+)
+
+// runAutoCancel refreshes the exchange's cancel-all countdown of every traded symbol each
+// heartbeat. The timers are left running on shutdown, so resting orders are canceled by the
+// exchange once the countdown runs out unless the process is back in time to refresh them.
+func (e *Engine) runAutoCancel(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(e.config.AutoCancel.HeartbeatSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		e.refreshAutoCancel(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshAutoCancel restarts each symbol's countdown; the heartbeat is recorded only when
+// every symbol was refreshed
+func (e *Engine) refreshAutoCancel(ctx context.Context) {
+	ok := true
+	for _, symbol := range e.config.Symbols {
+		seconds := e.config.AutoCancel.CountdownFor(symbol)
+		if seconds <= 0 {
+			continue
+		}
+		if err := e.exchangeClient.SetAutoCancel(ctx, symbol, time.Duration(seconds)*time.Second); err != nil {
+			e.logger.Warnf("Failed to refresh auto-cancel countdown for %s: %v", symbol, err)
+			ok = false
+		}
+	}
+	if ok {
+		e.heartbeats.beat(HealthAutoCancel)
+	}
+}
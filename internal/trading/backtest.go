@@ -0,0 +1,355 @@
+package trading
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+)
+
+// Backtest modes
+const (
+	BacktestModeCandle     = "candle"     // Call the live strategy once per candle with its kline window
+	BacktestModeVectorized = "vectorized" // Precompute the strategy's indicators over the whole series
+)
+
+// ErrNotVectorized is returned for a vectorized backtest of a strategy without a vectorized form
+var ErrNotVectorized = errors.New("strategy has no vectorized mode")
+
+// CandleSeries holds candles column by column, oldest first, so indicators can be computed
+// over whole arrays and strategies can address candles by index
+type CandleSeries struct {
+	OpenTime []int64
+	Open     []float64
+	High     []float64
+	Low      []float64
+	Close    []float64
+	Volume   []float64
+}
+
+// Len returns the number of candles
+func (s *CandleSeries) Len() int {
+	return len(s.Close)
+}
+
+// Append adds a candle
+func (s *CandleSeries) Append(openTime int64, open, high, low, close, volume float64) {
+	s.OpenTime = append(s.OpenTime, openTime)
+	s.Open = append(s.Open, open)
+	s.High = append(s.High, high)
+	s.Low = append(s.Low, low)
+	s.Close = append(s.Close, close)
+	s.Volume = append(s.Volume, volume)
+}
+
+// Klines returns the candles as klines for strategies that take a kline window
+func (s *CandleSeries) Klines(interval time.Duration) []*exchange.KlineData {
+	klines := make([]*exchange.KlineData, s.Len())
+	values := make([]exchange.KlineData, s.Len())
+	for i := range klines {
+		values[i] = exchange.KlineData{
+			OpenTime:  s.OpenTime[i],
+			Open:      s.Open[i],
+			High:      s.High[i],
+			Low:       s.Low[i],
+			Close:     s.Close[i],
+			Volume:    s.Volume[i],
+			CloseTime: s.OpenTime[i] + interval.Milliseconds() - 1,
+		}
+		klines[i] = &values[i]
+	}
+	return klines
+}
+
+// ReadCandlesCSV reads klines in Binance's CSV layout (open_time, open, high, low, close,
+// volume, ...; extra columns are ignored). A header line is skipped.
+func ReadCandlesCSV(r io.Reader) (*CandleSeries, error) {
+	reader := csv.NewReader(bufio.NewReaderSize(r, 1<<20))
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	series := &CandleSeries{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read line %d: %w", line, err)
+		}
+		if len(record) < 6 {
+			return nil, fmt.Errorf("line %d has %d columns, want at least 6", line, len(record))
+		}
+
+		openTime, err := strconv.ParseInt(record[0], 10, 64)
+		if err != nil {
+			if line == 1 {
+				continue // Header
+			}
+			return nil, fmt.Errorf("invalid open time on line %d: %w", line, err)
+		}
+		var values [5]float64
+		for i := range values {
+			if values[i], err = strconv.ParseFloat(record[i+1], 64); err != nil {
+				return nil, fmt.Errorf("invalid value in column %d on line %d: %w", i+2, line, err)
+			}
+		}
+		series.Append(openTime, values[0], values[1], values[2], values[3], values[4])
+	}
+	return series, nil
+}
+
+// BacktestConfig describes a backtest run
+type BacktestConfig struct {
+	Symbol          string
+	StrategyType    string                 // As in trading.strategy.type
+	Parameters      map[string]interface{} // Strategy parameters
+	Mode            string                 // BacktestModeCandle or BacktestModeVectorized
+	Interval        time.Duration          // Candle interval, for kline close times in candle mode
+	InitialBalance  float64                // USDT
+	PositionPercent float64                // Percent of the balance put into each entry
+	FeeRate         float64                // Fee per fill as a fraction of the notional
+	SlippageBps     float64                // Each fill is up to this many basis points worse, drawn at random
+	Seed            int64                  // Seeds the slippage draws, so runs are reproducible
+	ATRPeriod       int                    // ATR given to strategies in candle mode
+}
+
+// BacktestTrade is one completed round trip
+type BacktestTrade struct {
+	EntryTime  int64   `json:"entry_time"`
+	ExitTime   int64   `json:"exit_time"`
+	EntryPrice float64 `json:"entry_price"`
+	ExitPrice  float64 `json:"exit_price"`
+	Quantity   float64 `json:"quantity"`
+	PnL        float64 `json:"pnl"` // Net of fees
+	Fees       float64 `json:"fees"`
+}
+
+// BacktestResult summarizes a backtest run
+type BacktestResult struct {
+	Strategy         string          `json:"strategy"`
+	Mode             string          `json:"mode"`
+	Candles          int             `json:"candles"`
+	InitialBalance   float64         `json:"initial_balance"`
+	FinalBalance     float64         `json:"final_balance"` // Open position marked at the last close
+	ReturnPercent    float64         `json:"return_percent"`
+	MaxDrawdown      float64         `json:"max_drawdown"` // Largest peak-to-trough equity decline, as a fraction
+	WinRate          float64         `json:"win_rate"`
+	Fees             float64         `json:"fees"`
+	Trades           []BacktestTrade `json:"trades"`
+	Elapsed          time.Duration   `json:"elapsed"`
+	CandlesPerSecond float64         `json:"candles_per_second"`
+}
+
+// Backtest runs the configured strategy over the series, long only with one position at a
+// time. Entries and exits fill at the close of the candle that signalled them.
+func Backtest(ctx context.Context, cfg BacktestConfig, series *CandleSeries) (*BacktestResult, error) {
+	strategy := newStrategy(cfg.StrategyType)
+	if err := strategy.Initialize(cfg.Parameters); err != nil {
+		return nil, fmt.Errorf("failed to initialize strategy: %w", err)
+	}
+
+	book := &backtestBook{cfg: cfg, balance: cfg.InitialBalance, peak: cfg.InitialBalance}
+	if cfg.SlippageBps > 0 {
+		book.rng = rand.New(rand.NewSource(cfg.Seed))
+	}
+	started := time.Now()
+	switch cfg.Mode {
+	case BacktestModeVectorized:
+		vectorized, ok := strategy.(VectorizedStrategy)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", strategy.Name(), ErrNotVectorized)
+		}
+		runVectorized(vectorized.Vectorize(series), series, book)
+	case BacktestModeCandle, "":
+		if err := runCandles(ctx, cfg, strategy, series, book); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown backtest mode %q", cfg.Mode)
+	}
+	elapsed := time.Since(started)
+
+	result := book.result(series)
+	result.Strategy = strategy.Name()
+	result.Mode = cfg.Mode
+	result.Elapsed = elapsed
+	if elapsed > 0 {
+		result.CandlesPerSecond = float64(series.Len()) / elapsed.Seconds()
+	}
+	return result, nil
+}
+
+// runVectorized walks the series asking the precomputed signals for entries and exits
+func runVectorized(signals VectorSignals, series *CandleSeries, book *backtestBook) {
+	for i, price := range series.Close {
+		if book.open {
+			if signals.Exit(i, book.entryPrice) {
+				book.exit(series.OpenTime[i], price)
+			}
+		} else if signals.Enter(i) {
+			book.enter(series.OpenTime[i], price)
+		}
+		book.mark(price)
+	}
+}
+
+// runCandles feeds the live strategy one candle at a time, as the engine does each cycle
+func runCandles(ctx context.Context, cfg BacktestConfig, strategy Strategy, series *CandleSeries, book *backtestBook) error {
+	window := defaultKlineLimit
+	if warmup, ok := strategy.(WarmupStrategy); ok && warmup.RequiredCandles()+1 > window {
+		window = warmup.RequiredCandles() + 1
+	}
+
+	klines := series.Klines(cfg.Interval)
+	for i, kline := range klines {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		from := i + 1 - window
+		if from < 0 {
+			from = 0
+		}
+		data := &MarketData{
+			Symbol:    cfg.Symbol,
+			Price:     kline.Close,
+			Volume:    kline.Volume,
+			Timestamp: time.UnixMilli(kline.CloseTime),
+			Klines:    klines[from : i+1],
+			ATR:       klinesATR(closedKlines(klines[from:i+1]), cfg.ATRPeriod),
+		}
+
+		if book.open {
+			position := &models.Position{Symbol: cfg.Symbol, PositionSide: "LONG", Size: book.quantity, EntryPrice: book.entryPrice}
+			signal, err := strategy.ShouldSell(ctx, cfg.Symbol, data, position)
+			if err != nil {
+				return fmt.Errorf("strategy failed on candle %d: %w", i, err)
+			}
+			if signal.Action == "SELL" {
+				book.exit(kline.OpenTime, kline.Close)
+			}
+		} else {
+			signal, err := strategy.ShouldBuy(ctx, cfg.Symbol, data)
+			if err != nil {
+				return fmt.Errorf("strategy failed on candle %d: %w", i, err)
+			}
+			if signal.Action == "BUY" {
+				book.enter(kline.OpenTime, kline.Close)
+			}
+		}
+		book.mark(kline.Close)
+	}
+	return nil
+}
+
+// backtestBook tracks the balance, the open position and the equity curve
+type backtestBook struct {
+	cfg     BacktestConfig
+	balance float64
+	rng     *rand.Rand // Slippage draws; nil without slippage
+
+	open       bool
+	entryTime  int64
+	entryPrice float64
+	entryFee   float64
+	quantity   float64
+
+	trades      []BacktestTrade
+	fees        float64
+	equity      float64
+	peak        float64
+	maxDrawdown float64
+}
+
+func (b *backtestBook) enter(openTime int64, price float64) {
+	notional := b.balance * b.cfg.PositionPercent / 100
+	if notional <= 0 || price <= 0 {
+		return
+	}
+	price *= 1 + b.slippage()
+	b.open = true
+	b.entryTime = openTime
+	b.entryPrice = price
+	b.quantity = notional / price
+	b.entryFee = notional * b.cfg.FeeRate
+	b.balance -= b.entryFee
+	b.fees += b.entryFee
+}
+
+func (b *backtestBook) exit(openTime int64, price float64) {
+	price *= 1 - b.slippage()
+	exitFee := b.quantity * price * b.cfg.FeeRate
+	gross := (price - b.entryPrice) * b.quantity
+	b.balance += gross - exitFee
+	b.fees += exitFee
+	b.trades = append(b.trades, BacktestTrade{
+		EntryTime:  b.entryTime,
+		ExitTime:   openTime,
+		EntryPrice: b.entryPrice,
+		ExitPrice:  price,
+		Quantity:   b.quantity,
+		PnL:        gross - b.entryFee - exitFee,
+		Fees:       b.entryFee + exitFee,
+	})
+	b.open = false
+}
+
+// slippage returns the adverse price move of the next fill as a fraction
+func (b *backtestBook) slippage() float64 {
+	if b.rng == nil {
+		return 0
+	}
+	return b.rng.Float64() * b.cfg.SlippageBps / 10000
+}
+
+// mark updates the equity curve at the candle's close
+func (b *backtestBook) mark(price float64) {
+	b.equity = b.balance
+	if b.open {
+		b.equity += (price - b.entryPrice) * b.quantity
+	}
+	if b.equity > b.peak {
+		b.peak = b.equity
+	} else if b.peak > 0 {
+		b.maxDrawdown = math.Max(b.maxDrawdown, (b.peak-b.equity)/b.peak)
+	}
+}
+
+func (b *backtestBook) result(series *CandleSeries) *BacktestResult {
+	final := b.balance
+	if series.Len() > 0 {
+		b.mark(series.Close[series.Len()-1])
+		final = b.equity
+	}
+
+	result := &BacktestResult{
+		Candles:        series.Len(),
+		InitialBalance: b.cfg.InitialBalance,
+		FinalBalance:   final,
+		MaxDrawdown:    b.maxDrawdown,
+		Fees:           b.fees,
+		Trades:         b.trades,
+	}
+	if b.cfg.InitialBalance > 0 {
+		result.ReturnPercent = (final - b.cfg.InitialBalance) / b.cfg.InitialBalance * 100
+	}
+	if len(b.trades) > 0 {
+		wins := 0
+		for _, trade := range b.trades {
+			if trade.PnL > 0 {
+				wins++
+			}
+		}
+		result.WinRate = float64(wins) / float64(len(b.trades))
+	}
+	return result
+}
@@ -0,0 +1,248 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BacktestJob is one (symbol, parameter set) combination of a backtest run
+type BacktestJob struct {
+	Symbol       string                 `json:"symbol"`
+	ParameterSet int                    `json:"parameter_set"` // Index into the run's parameter sets
+	Parameters   map[string]interface{} `json:"parameters"`
+	Seed         int64                  `json:"seed"`
+}
+
+// BacktestJobResult is the outcome of a job; Error is set instead of Result when it failed
+type BacktestJobResult struct {
+	BacktestJob
+	Result *BacktestResult `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// BacktestSummary aggregates a parameter set's results over all symbols
+type BacktestSummary struct {
+	ParameterSet      int                    `json:"parameter_set"`
+	Parameters        map[string]interface{} `json:"parameters"`
+	Symbols           int                    `json:"symbols"` // Symbols that completed
+	Failed            int                    `json:"failed"`
+	MeanReturnPercent float64                `json:"mean_return_percent"`
+	MinReturnPercent  float64                `json:"min_return_percent"`
+	MaxReturnPercent  float64                `json:"max_return_percent"`
+	WorstDrawdown     float64                `json:"worst_drawdown"`
+	Trades            int                    `json:"trades"`
+	WinRate           float64                `json:"win_rate"` // Over all trades of all symbols
+	Fees              float64                `json:"fees"`
+}
+
+// BacktestReport is the aggregated outcome of a run. Jobs are ordered by symbol and then
+// parameter set, Summaries by mean return, best first, so reports of the same inputs compare
+// equal whatever order the workers finished in.
+type BacktestReport struct {
+	Jobs      []*BacktestJobResult `json:"jobs"`
+	Summaries []*BacktestSummary   `json:"summaries"`
+	Elapsed   time.Duration        `json:"elapsed"`
+}
+
+// BacktestProgress is called after each job completes with the number of completed jobs
+type BacktestProgress func(completed, total int, job *BacktestJobResult)
+
+// BacktestRunner runs independent backtests across a worker pool
+type BacktestRunner struct {
+	Workers  int              // Concurrent backtests; runtime.NumCPU() when 0
+	Progress BacktestProgress // Optional; called from the worker goroutines, one call at a time
+}
+
+// Run backtests every (symbol, parameter set) combination, with base supplying everything but
+// the symbol, parameters and seed. Each job's seed is derived from base.Seed, the symbol and
+// the parameter set index, so a run is reproducible regardless of the worker count.
+func (r *BacktestRunner) Run(ctx context.Context, base BacktestConfig, data map[string]*CandleSeries, parameterSets []map[string]interface{}) (*BacktestReport, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no candle data to backtest")
+	}
+	if len(parameterSets) == 0 {
+		parameterSets = []map[string]interface{}{base.Parameters}
+	}
+
+	symbols := make([]string, 0, len(data))
+	for symbol := range data {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	jobs := make([]*BacktestJobResult, 0, len(symbols)*len(parameterSets))
+	for _, symbol := range symbols {
+		for i, parameters := range parameterSets {
+			jobs = append(jobs, &BacktestJobResult{BacktestJob: BacktestJob{
+				Symbol:       symbol,
+				ParameterSet: i,
+				Parameters:   parameters,
+				Seed:         jobSeed(base.Seed, symbol, i),
+			}})
+		}
+	}
+
+	workers := r.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+
+	started := time.Now()
+	queue := make(chan *BacktestJobResult)
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		completed int
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				cfg := base
+				cfg.Symbol = job.Symbol
+				cfg.Parameters = job.Parameters
+				cfg.Seed = job.Seed
+				result, err := Backtest(ctx, cfg, data[job.Symbol])
+				if err != nil {
+					job.Error = err.Error()
+				} else {
+					job.Result = result
+				}
+
+				mu.Lock()
+				completed++
+				if r.Progress != nil {
+					r.Progress(completed, len(jobs), job)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, job := range jobs {
+		select {
+		case queue <- job:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(queue)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return &BacktestReport{
+		Jobs:      jobs,
+		Summaries: summarizeBacktests(jobs, parameterSets),
+		Elapsed:   time.Since(started),
+	}, nil
+}
+
+// jobSeed mixes the base seed with the job's identity
+func jobSeed(base int64, symbol string, parameterSet int) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d/%s/%d", base, symbol, parameterSet)
+	return int64(h.Sum64())
+}
+
+// summarizeBacktests aggregates the job results per parameter set
+func summarizeBacktests(jobs []*BacktestJobResult, parameterSets []map[string]interface{}) []*BacktestSummary {
+	summaries := make([]*BacktestSummary, len(parameterSets))
+	wins := make([]int, len(parameterSets))
+	for i, parameters := range parameterSets {
+		summaries[i] = &BacktestSummary{ParameterSet: i, Parameters: parameters}
+	}
+
+	for _, job := range jobs {
+		summary := summaries[job.ParameterSet]
+		if job.Result == nil {
+			summary.Failed++
+			continue
+		}
+		result := job.Result
+		if summary.Symbols == 0 {
+			summary.MinReturnPercent = result.ReturnPercent
+			summary.MaxReturnPercent = result.ReturnPercent
+		}
+		summary.Symbols++
+		summary.MeanReturnPercent += result.ReturnPercent
+		summary.MinReturnPercent = math.Min(summary.MinReturnPercent, result.ReturnPercent)
+		summary.MaxReturnPercent = math.Max(summary.MaxReturnPercent, result.ReturnPercent)
+		summary.WorstDrawdown = math.Max(summary.WorstDrawdown, result.MaxDrawdown)
+		summary.Trades += len(result.Trades)
+		summary.Fees += result.Fees
+		for _, trade := range result.Trades {
+			if trade.PnL > 0 {
+				wins[job.ParameterSet]++
+			}
+		}
+	}
+
+	for i, summary := range summaries {
+		if summary.Symbols > 0 {
+			summary.MeanReturnPercent /= float64(summary.Symbols)
+		}
+		if summary.Trades > 0 {
+			summary.WinRate = float64(wins[i]) / float64(summary.Trades)
+		}
+	}
+
+	// Parameter sets where every symbol failed sort last
+	sort.SliceStable(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if (a.Symbols > 0) != (b.Symbols > 0) {
+			return a.Symbols > 0
+		}
+		if a.MeanReturnPercent != b.MeanReturnPercent {
+			return a.MeanReturnPercent > b.MeanReturnPercent
+		}
+		return a.ParameterSet < b.ParameterSet
+	})
+	return summaries
+}
+
+// ParameterGrid expands candidate values per parameter into every combination. Keys vary in
+// sorted order with the last key changing fastest, so the sets are indexed reproducibly.
+func ParameterGrid(base map[string]interface{}, grid map[string][]interface{}) []map[string]interface{} {
+	keys := make([]string, 0, len(grid))
+	for key, values := range grid {
+		if len(values) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	sets := []map[string]interface{}{copyParameters(base)}
+	for _, key := range keys {
+		expanded := make([]map[string]interface{}, 0, len(sets)*len(grid[key]))
+		for _, set := range sets {
+			for _, value := range grid[key] {
+				next := copyParameters(set)
+				next[key] = value
+				expanded = append(expanded, next)
+			}
+		}
+		sets = expanded
+	}
+	return sets
+}
+
+func copyParameters(parameters map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(parameters))
+	for key, value := range parameters {
+		copied[key] = value
+	}
+	return copied
+}
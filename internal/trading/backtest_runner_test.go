@@ -0,0 +1,94 @@
+package trading
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBacktestRunnerIsReproducible(t *testing.T) {
+	data := map[string]*CandleSeries{
+		"BTCUSDT": randomSeries(5000, 1),
+		"ETHUSDT": randomSeries(5000, 2),
+		"BNBUSDT": randomSeries(5000, 3),
+	}
+	sets := ParameterGrid(map[string]interface{}{"min_confidence": 0.02}, map[string][]interface{}{
+		"short_period": {5.0, 10.0},
+		"long_period":  {20.0, 30.0, 50.0},
+	})
+	base := BacktestConfig{
+		StrategyType:    "simple_moving_average",
+		Mode:            BacktestModeVectorized,
+		Interval:        time.Minute,
+		InitialBalance:  10000,
+		PositionPercent: 50,
+		FeeRate:         0.0004,
+		SlippageBps:     5,
+		Seed:            42,
+	}
+
+	run := func(workers int) *BacktestReport {
+		progress := 0
+		runner := &BacktestRunner{Workers: workers, Progress: func(completed, total int, job *BacktestJobResult) {
+			progress++
+			if completed != progress || total != len(data)*len(sets) {
+				t.Errorf("progress %d/%d after %d jobs", completed, total, progress)
+			}
+		}}
+		report, err := runner.Run(context.Background(), base, data, sets)
+		if err != nil {
+			t.Fatalf("run with %d workers: %v", workers, err)
+		}
+		return report
+	}
+	serial, parallel := run(1), run(8)
+
+	if len(serial.Jobs) != 18 || len(serial.Summaries) != 6 {
+		t.Fatalf("got %d jobs and %d summaries, want 18 and 6", len(serial.Jobs), len(serial.Summaries))
+	}
+	for i := range serial.Jobs {
+		a, b := serial.Jobs[i], parallel.Jobs[i]
+		if a.Error != "" {
+			t.Fatalf("job %s/%d failed: %s", a.Symbol, a.ParameterSet, a.Error)
+		}
+		if a.Symbol != b.Symbol || a.ParameterSet != b.ParameterSet || a.Seed != b.Seed ||
+			a.Result.FinalBalance != b.Result.FinalBalance || !reflect.DeepEqual(a.Result.Trades, b.Result.Trades) {
+			t.Fatalf("job %d differs between 1 and 8 workers", i)
+		}
+	}
+	for i := range serial.Summaries {
+		a, b := *serial.Summaries[i], *parallel.Summaries[i]
+		if !reflect.DeepEqual(a, b) {
+			t.Fatalf("summary %d differs: %+v vs %+v", i, a, b)
+		}
+	}
+
+	// A job matches the same backtest run on its own with the job's seed
+	job := serial.Jobs[0]
+	cfg := base
+	cfg.Symbol, cfg.Parameters, cfg.Seed = job.Symbol, job.Parameters, job.Seed
+	alone, err := Backtest(context.Background(), cfg, data[job.Symbol])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alone.FinalBalance != job.Result.FinalBalance {
+		t.Fatalf("standalone final balance %v, runner %v", alone.FinalBalance, job.Result.FinalBalance)
+	}
+}
+
+func TestParameterGrid(t *testing.T) {
+	sets := ParameterGrid(map[string]interface{}{"period": 14.0}, map[string][]interface{}{
+		"oversold":   {25.0, 30.0},
+		"overbought": {70.0, 75.0},
+	})
+	want := []map[string]interface{}{
+		{"period": 14.0, "overbought": 70.0, "oversold": 25.0},
+		{"period": 14.0, "overbought": 70.0, "oversold": 30.0},
+		{"period": 14.0, "overbought": 75.0, "oversold": 25.0},
+		{"period": 14.0, "overbought": 75.0, "oversold": 30.0},
+	}
+	if !reflect.DeepEqual(sets, want) {
+		t.Fatalf("got %v, want %v", sets, want)
+	}
+}
@@ -0,0 +1,146 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+// randomSeries returns n one-minute candles of a random walk
+func randomSeries(n int, seed int64) *CandleSeries {
+	rng := rand.New(rand.NewSource(seed))
+	series := &CandleSeries{}
+	price := 100.0
+	for i := 0; i < n; i++ {
+		open := price
+		price *= 1 + rng.NormFloat64()*0.004
+		high, low := open, price
+		if price > open {
+			high, low = price, open
+		}
+		series.Append(int64(i)*60000, open, high*1.001, low*0.999, price, 10+rng.Float64())
+	}
+	return series
+}
+
+func TestVectorizedBacktestMatchesCandleMode(t *testing.T) {
+	series := randomSeries(20000, 1)
+	tests := []struct {
+		strategy string
+		params   map[string]interface{}
+	}{
+		{"simple_moving_average", map[string]interface{}{"short_period": 10.0, "long_period": 30.0, "min_confidence": 0.02}},
+		{"rsi", map[string]interface{}{"period": 14.0, "oversold": 30.0, "overbought": 70.0, "min_confidence": 0.1}},
+	}
+	for _, tt := range tests {
+		cfg := BacktestConfig{
+			Symbol:          "BTCUSDT",
+			StrategyType:    tt.strategy,
+			Parameters:      tt.params,
+			Interval:        time.Minute,
+			InitialBalance:  10000,
+			PositionPercent: 50,
+			FeeRate:         0.0004,
+			ATRPeriod:       14,
+		}
+
+		cfg.Mode = BacktestModeCandle
+		candle, err := Backtest(context.Background(), cfg, series)
+		if err != nil {
+			t.Fatalf("%s candle backtest: %v", tt.strategy, err)
+		}
+		cfg.Mode = BacktestModeVectorized
+		vectorized, err := Backtest(context.Background(), cfg, series)
+		if err != nil {
+			t.Fatalf("%s vectorized backtest: %v", tt.strategy, err)
+		}
+
+		if len(candle.Trades) == 0 {
+			t.Fatalf("%s made no trades; the test data does not exercise it", tt.strategy)
+		}
+		if len(candle.Trades) != len(vectorized.Trades) {
+			t.Fatalf("%s: %d trades per candle, %d vectorized", tt.strategy, len(candle.Trades), len(vectorized.Trades))
+		}
+		for i := range candle.Trades {
+			if candle.Trades[i].EntryTime != vectorized.Trades[i].EntryTime || candle.Trades[i].ExitTime != vectorized.Trades[i].ExitTime {
+				t.Fatalf("%s trade %d differs: %+v vs %+v", tt.strategy, i, candle.Trades[i], vectorized.Trades[i])
+			}
+		}
+		if diff := candle.FinalBalance - vectorized.FinalBalance; diff > 1e-6 || diff < -1e-6 {
+			t.Fatalf("%s final balance %v per candle, %v vectorized", tt.strategy, candle.FinalBalance, vectorized.FinalBalance)
+		}
+	}
+}
+
+func TestVectorizedBacktestUnsupportedStrategy(t *testing.T) {
+	_, err := Backtest(context.Background(), BacktestConfig{StrategyType: "dca", Mode: BacktestModeVectorized}, randomSeries(10, 1))
+	if !errors.Is(err, ErrNotVectorized) {
+		t.Fatalf("got %v, want %v", err, ErrNotVectorized)
+	}
+}
+
+func TestReadCandlesCSV(t *testing.T) {
+	data := "open_time,open,high,low,close,volume,close_time\n" +
+		"1700000000000,100,101,99,100.5,12.5,1700000059999\n" +
+		"1700000060000,100.5,102,100,101.5,8,1700000119999\n"
+	series, err := ReadCandlesCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to read candles: %v", err)
+	}
+	if series.Len() != 2 || series.OpenTime[1] != 1700000060000 || series.Close[1] != 101.5 || series.Volume[0] != 12.5 {
+		t.Fatalf("read %+v", series)
+	}
+
+	if _, err := ReadCandlesCSV(strings.NewReader("1,2,3\n")); err == nil {
+		t.Fatal("short line was accepted")
+	}
+}
+
+// Vectorized backtests of simple strategies should process well over a million candles per second
+func BenchmarkVectorizedBacktest(b *testing.B) {
+	series := randomSeries(1_000_000, 2)
+	for _, strategy := range []string{"simple_moving_average", "rsi"} {
+		b.Run(strategy, func(b *testing.B) {
+			cfg := BacktestConfig{
+				StrategyType:    strategy,
+				Mode:            BacktestModeVectorized,
+				InitialBalance:  10000,
+				PositionPercent: 50,
+				FeeRate:         0.0004,
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := Backtest(context.Background(), cfg, series); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(series.Len())*float64(b.N)/b.Elapsed().Seconds(), "candles/s")
+		})
+	}
+}
+
+// The per-candle mode on the same data, for comparison
+func BenchmarkCandleBacktest(b *testing.B) {
+	series := randomSeries(20000, 2)
+	cfg := BacktestConfig{
+		StrategyType:    "simple_moving_average",
+		Mode:            BacktestModeCandle,
+		Interval:        time.Minute,
+		InitialBalance:  10000,
+		PositionPercent: 50,
+		FeeRate:         0.0004,
+		ATRPeriod:       14,
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Backtest(context.Background(), cfg, series); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(series.Len())*float64(b.N)/b.Elapsed().Seconds(), "candles/s")
+}
@@ -0,0 +1,259 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"contract_playground/internal/indicators"
+	"contract_playground/internal/models"
+)
+
+// BreakoutStrategy buys when price closes above the high of the last N candles on a volume
+// surge, and exits on an ATR stop that trails the highest high since entry or on a close
+// below the exit channel
+type BreakoutStrategy struct {
+	name             string
+	orderValue       float64 // USDT per entry
+	channelPeriod    int     // Candles whose high must be broken
+	exitPeriod       int     // Candles whose low closes the position when broken (0 = disabled)
+	volumePeriod     int     // Candles averaged for the volume baseline
+	volumeMultiplier float64 // Breakout candle volume required relative to the baseline
+	atrPeriod        int
+	stopATR          float64 // Initial stop distance below entry in ATRs
+	trailATR         float64 // Trailing stop distance below the highest high in ATRs (0 = no trailing)
+	takeProfitATR    float64 // Target above entry in ATRs (0 = no target)
+	stopWorkingType  string
+
+	tracker *indicators.Tracker
+	mu      sync.Mutex
+	state   map[string]*breakoutState
+}
+
+// breakoutState holds the incremental indicators of one symbol
+type breakoutState struct {
+	atr            *indicators.ATR
+	volume         *indicators.SMA
+	volumeBaseline float64 // Average volume of the volumePeriod candles before the last closed one
+}
+
+// NewBreakoutStrategy creates a new breakout strategy
+func NewBreakoutStrategy() Strategy {
+	return &BreakoutStrategy{
+		name:             "Breakout Strategy",
+		orderValue:       100,
+		channelPeriod:    20,
+		exitPeriod:       10,
+		volumePeriod:     20,
+		volumeMultiplier: 1.5,
+		atrPeriod:        14,
+		stopATR:          2.0,
+		trailATR:         3.0,
+		stopWorkingType:  WorkingTypeContractPrice,
+		tracker:          indicators.NewTracker(),
+		state:            make(map[string]*breakoutState),
+	}
+}
+
+// Name returns the strategy name
+func (b *BreakoutStrategy) Name() string {
+	return b.name
+}
+
+// Initialize initializes the breakout strategy with parameters
+func (b *BreakoutStrategy) Initialize(config map[string]interface{}) error {
+	if val, ok := getFloatParam(config, "order_usdt"); ok {
+		b.orderValue = val
+	}
+	if val, ok := getFloatParam(config, "channel_period"); ok {
+		b.channelPeriod = int(val)
+	}
+	if val, ok := getFloatParam(config, "exit_period"); ok {
+		b.exitPeriod = int(val)
+	}
+	if val, ok := getFloatParam(config, "volume_period"); ok {
+		b.volumePeriod = int(val)
+	}
+	if val, ok := getFloatParam(config, "volume_multiplier"); ok {
+		b.volumeMultiplier = val
+	}
+	if val, ok := getFloatParam(config, "atr_period"); ok {
+		b.atrPeriod = int(val)
+	}
+	if val, ok := getFloatParam(config, "stop_atr"); ok {
+		b.stopATR = val
+	}
+	if val, ok := getFloatParam(config, "trail_atr"); ok {
+		b.trailATR = val
+	}
+	if val, ok := getFloatParam(config, "take_profit_atr"); ok {
+		b.takeProfitATR = val
+	}
+	if workingType, ok := getStringParam(config, "stop_working_type"); ok && workingType != "" {
+		b.stopWorkingType = workingType
+	}
+
+	if b.orderValue <= 0 {
+		return fmt.Errorf("order value must be positive")
+	}
+	if b.channelPeriod < 2 || b.volumePeriod < 1 || b.atrPeriod < 1 {
+		return fmt.Errorf("channel, volume and ATR periods must be positive")
+	}
+	if b.exitPeriod < 0 {
+		return fmt.Errorf("exit period must not be negative")
+	}
+	if b.volumeMultiplier < 0 {
+		return fmt.Errorf("volume multiplier must not be negative")
+	}
+	if b.stopATR <= 0 {
+		return fmt.Errorf("stop ATR multiple must be positive")
+	}
+	if b.trailATR < 0 || b.takeProfitATR < 0 {
+		return fmt.Errorf("trailing and take profit ATR multiples must not be negative")
+	}
+
+	// The engine fetches 100 candles of the kline interval; the last one is still forming
+	if needed := b.requiredCandles(); needed > 99 {
+		return fmt.Errorf("breakout periods need %d closed candles, at most 99 are available", needed)
+	}
+
+	return nil
+}
+
+// ShouldBuy enters when the last closed candle breaks the channel high on above-average volume
+func (b *BreakoutStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
+	atr, avgVolume := b.update(symbol, data)
+	closed := closedKlines(data.Klines)
+	if len(closed) < b.requiredCandles() {
+		return &Signal{Action: "HOLD", Reason: "Insufficient data for breakout"}, nil
+	}
+
+	last := closed[len(closed)-1]
+	channel := closed[len(closed)-1-b.channelPeriod : len(closed)-1]
+
+	high := highestHigh(channel)
+	if last.Close <= high {
+		return &Signal{Action: "HOLD", Reason: fmt.Sprintf("No breakout: close %.6f <= channel high %.6f", last.Close, high)}, nil
+	}
+
+	if avgVolume <= 0 || last.Volume < avgVolume*b.volumeMultiplier {
+		return &Signal{Action: "HOLD", Reason: fmt.Sprintf("Breakout without volume: %.2f < %.2fx average %.2f", last.Volume, b.volumeMultiplier, avgVolume)}, nil
+	}
+
+	if atr <= 0 {
+		return &Signal{Action: "HOLD", Reason: "ATR not available"}, nil
+	}
+
+	signal := &Signal{
+		Action:       "BUY",
+		Quantity:     b.orderValue / data.Price,
+		Price:        data.Price,
+		StopLoss:     data.Price - b.stopATR*atr,
+		Confidence:   math.Min(1.0, 0.5*last.Volume/(avgVolume*math.Max(b.volumeMultiplier, 1))),
+		Reason:       fmt.Sprintf("Breakout above %.6f on %.1fx volume (ATR %.6f)", high, last.Volume/avgVolume, atr),
+		PositionSide: "LONG",
+	}
+	if b.takeProfitATR > 0 {
+		signal.TakeProfit = data.Price + b.takeProfitATR*atr
+	}
+
+	return signal, nil
+}
+
+// ShouldSell exits on the ATR stop, the ATR target or a close below the exit channel
+func (b *BreakoutStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
+	closed := closedKlines(data.Klines)
+	atr, _ := b.update(symbol, data)
+	if atr <= 0 {
+		return &Signal{Action: "HOLD", Reason: "ATR not available"}, nil
+	}
+
+	// The stop starts below entry and only ever ratchets up behind the highest high since entry
+	stop := position.EntryPrice - b.stopATR*atr
+	if b.trailATR > 0 {
+		peak := math.Max(position.EntryPrice, data.Price)
+		for _, k := range closed {
+			if k.CloseTime >= position.OpenTime.UnixMilli() && k.High > peak {
+				peak = k.High
+			}
+		}
+		stop = math.Max(stop, peak-b.trailATR*atr)
+	}
+
+	if price := data.WorkingPrice(b.stopWorkingType); price <= stop {
+		return &Signal{
+			Action:     "SELL",
+			Quantity:   position.Size,
+			Price:      data.Price,
+			Confidence: 1.0,
+			Reason:     fmt.Sprintf("Breakout ATR stop: %.6f <= %.6f", price, stop),
+		}, nil
+	}
+
+	if b.takeProfitATR > 0 {
+		if target := position.EntryPrice + b.takeProfitATR*atr; data.Price >= target {
+			return &Signal{
+				Action:     "SELL",
+				Quantity:   position.Size,
+				Price:      data.Price,
+				Confidence: 1.0,
+				Reason:     fmt.Sprintf("Breakout ATR target: %.6f >= %.6f", data.Price, target),
+			}, nil
+		}
+	}
+
+	if b.exitPeriod > 0 && len(closed) > b.exitPeriod {
+		last := closed[len(closed)-1]
+		low := lowestLow(closed[len(closed)-1-b.exitPeriod : len(closed)-1])
+		if last.Close < low {
+			return &Signal{
+				Action:     "SELL",
+				Quantity:   position.Size,
+				Price:      data.Price,
+				Confidence: 0.8,
+				Reason:     fmt.Sprintf("Close %.6f below %d-candle low %.6f", last.Close, b.exitPeriod, low),
+			}, nil
+		}
+	}
+
+	return &Signal{Action: "HOLD", Reason: fmt.Sprintf("Breakout stop %.6f", stop)}, nil
+}
+
+// requiredCandles returns the number of closed candles needed for an entry decision
+func (b *BreakoutStrategy) requiredCandles() int {
+	needed := b.channelPeriod
+	if b.volumePeriod > needed {
+		needed = b.volumePeriod
+	}
+	if b.atrPeriod > needed {
+		needed = b.atrPeriod
+	}
+	if b.exitPeriod > needed {
+		needed = b.exitPeriod
+	}
+	return needed + 1
+}
+
+// update applies closed klines not seen yet and returns the ATR and the volume baseline
+func (b *BreakoutStrategy) update(symbol string, data *MarketData) (atr, volumeBaseline float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.state[symbol]
+	if !ok {
+		state = &breakoutState{
+			atr:    indicators.NewATR(b.atrPeriod),
+			volume: indicators.NewSMA(b.volumePeriod),
+		}
+		b.state[symbol] = state
+	}
+
+	for _, k := range b.tracker.Closed(symbol, data.Klines) {
+		state.atr.Update(k.High, k.Low, k.Close)
+		state.volumeBaseline = state.volume.Value()
+		state.volume.Update(k.Volume)
+	}
+
+	return state.atr.Value(), state.volumeBaseline
+}
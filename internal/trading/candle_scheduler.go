@@ -0,0 +1,131 @@
+package trading
+
+import (
+	"context"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/exchange"
+)
+
+const (
+	// Attempts to fetch a kline window that includes a just-closed candle
+	candleRefreshAttempts   = 3
+	candleRefreshRetryDelay = 500 * time.Millisecond
+)
+
+// candleClose is a candle of a symbol that has closed and awaits evaluation
+type candleClose struct {
+	symbol   string
+	openTime int64
+}
+
+// candleStreamHandler forwards closed candles from the kline stream to the scheduler
+type candleStreamHandler struct {
+	engine *Engine
+	closes chan<- candleClose
+}
+
+func (h *candleStreamHandler) OnKlineClosed(symbol string, kline *exchange.KlineData) {
+	h.engine.heartbeats.beat(HealthKlineStream)
+	select {
+	case h.closes <- candleClose{symbol: symbol, openTime: kline.OpenTime}:
+	default:
+		h.engine.logger.Warnf("Candle close queue full, dropping %s close", symbol)
+	}
+}
+
+func (h *candleStreamHandler) OnError(err error) {
+	h.engine.logger.Errorf("Kline stream error: %v", err)
+}
+
+// candleCloseLoop evaluates every symbol exactly once per closed candle. Closes come from
+// the kline stream; a timer aligned to candle boundaries covers candles the stream missed.
+func (e *Engine) candleCloseLoop(ctx context.Context) {
+	interval := config.KlineIntervals[e.config.KlineInterval]
+	delay := time.Duration(e.config.CandleCloseDelayMs) * time.Millisecond
+
+	closes := make(chan candleClose, 4*len(e.config.Symbols))
+	handler := &candleStreamHandler{engine: e, closes: closes}
+	if err := e.exchangeClient.StartKlineStream(ctx, e.config.Symbols, e.config.KlineInterval, handler); err != nil {
+		e.logger.Warnf("Failed to start kline stream, evaluating on aligned timer only: %v", err)
+	}
+
+	evaluated := make(map[string]int64, len(e.config.Symbols))
+	evaluate := func(c candleClose) {
+		if c.openTime <= evaluated[c.symbol] {
+			return
+		}
+		evaluated[c.symbol] = c.openTime
+		e.heartbeats.beat(HealthTradingLoop)
+
+		err := e.processSymbol(ctx, c.symbol, func(ctx context.Context, symbol string) error {
+			return e.evaluateClosedCandle(ctx, c)
+		})
+		if err != nil {
+			e.logger.Errorf("Error processing signals for %s: %v", c.symbol, err)
+		}
+	}
+
+	timer := time.NewTimer(time.Until(nextCandleBoundary(time.Now(), interval).Add(delay)))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case c := <-closes:
+			evaluate(c)
+		case now := <-timer.C:
+			e.heartbeats.beat(HealthTradingLoop)
+			// The candle that ended at the last boundary
+			openTime := nextCandleBoundary(now.Add(-delay), interval).Add(-2 * interval).UnixMilli()
+			for _, symbol := range e.config.Symbols {
+				if evaluated[symbol] < openTime {
+					e.logger.Debugf("No stream close for %s candle %d, evaluating from timer", symbol, openTime)
+					evaluate(candleClose{symbol: symbol, openTime: openTime})
+				}
+			}
+			timer.Reset(time.Until(nextCandleBoundary(time.Now(), interval).Add(delay)))
+		}
+	}
+}
+
+// evaluateClosedCandle refreshes the kline window so that the closed candle is the last
+// closed one, then runs the strategy for the symbol
+func (e *Engine) evaluateClosedCandle(ctx context.Context, c candleClose) error {
+	if e.config.EnablePaperTrading {
+		e.logger.Debug("Paper trading mode enabled - not executing real trades")
+		return nil
+	}
+
+	for attempt := 1; ; attempt++ {
+		_, klines, err := e.refreshMarketData(ctx, c.symbol)
+		if err != nil {
+			return err
+		}
+
+		// The window ends with the forming candle, so the closed one must precede it
+		if len(klines) >= 2 && klines[len(klines)-2].OpenTime >= c.openTime {
+			break
+		}
+		if attempt == candleRefreshAttempts {
+			e.logger.Warnf("Kline window for %s does not include closed candle %d yet, evaluating anyway", c.symbol, c.openTime)
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(candleRefreshRetryDelay):
+		}
+	}
+
+	return e.processSymbolSignals(ctx, c.symbol)
+}
+
+// nextCandleBoundary returns the first candle open time after t for the interval. Binance
+// candles are aligned to the Unix epoch in UTC.
+func nextCandleBoundary(t time.Time, interval time.Duration) time.Time {
+	return t.Truncate(interval).Add(interval)
+}
@@ -0,0 +1,57 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/notify"
+)
+
+// watchCircuitBreaker alerts when the exchange circuit breaker opens or closes. New entries
+// are refused while it is not closed; see circuitFilter.
+func (e *Engine) watchCircuitBreaker(ctx context.Context) {
+	guarded, ok := e.exchangeClient.(*exchange.GuardedClient)
+	if !ok {
+		return
+	}
+
+	guarded.OnStateChange(func(state exchange.CircuitState, err error) {
+		msg := &notify.Message{
+			Title: "Exchange requests resumed",
+			Body:  "The exchange circuit breaker closed; new entries are allowed again.",
+			Level: notify.LevelInfo,
+			Time:  time.Now(),
+		}
+		if state == exchange.CircuitOpen {
+			msg.Title = "Exchange circuit breaker open"
+			msg.Body = fmt.Sprintf("Exchange requests are failing (%v). New entries are paused until a probe request succeeds.", err)
+			msg.Level = notify.LevelCritical
+		}
+
+		details := map[string]string{"error": fmt.Sprint(err)}
+		go func() {
+			e.recordAudit("exchange", "circuit_"+string(state), "", details)
+			if err := e.notifier.Notify(ctx, msg); err != nil {
+				e.logger.Errorf("Failed to deliver circuit breaker notification: %v", err)
+			}
+		}()
+	})
+}
+
+// circuitState returns the exchange circuit breaker state, or closed when the client has none
+func (e *Engine) circuitState() exchange.CircuitState {
+	if guarded, ok := e.exchangeClient.(*exchange.GuardedClient); ok {
+		return guarded.State()
+	}
+	return exchange.CircuitClosed
+}
+
+// circuitFilter returns a reason when the exchange circuit breaker blocks new entries
+func (e *Engine) circuitFilter() string {
+	if state := e.circuitState(); state != exchange.CircuitClosed {
+		return fmt.Sprintf("exchange circuit breaker %s", state)
+	}
+	return ""
+}
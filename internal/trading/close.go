@@ -0,0 +1,172 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"contract_playground/internal/models"
+)
+
+// ErrPositionNotOpen is returned for a position ID without an open position
+var ErrPositionNotOpen = errors.New("no open position")
+
+// CloseResult reports the outcome of closing one position
+type CloseResult struct {
+	PositionID uint   `json:"position_id"`
+	Symbol     string `json:"symbol"`
+	Closed     bool   `json:"closed"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ClosePosition closes an open position at market through the engine's sell path, or places
+// a reduce-only limit order for its full size that is reconciled like any manual order
+func (e *Engine) ClosePosition(ctx context.Context, id uint, orderType string, price float64) (*CloseResult, error) {
+	if e.config.EnablePaperTrading {
+		return nil, fmt.Errorf("closing positions is disabled in paper trading mode")
+	}
+
+	position, err := e.openPosition(id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToUpper(orderType) {
+	case "", "MARKET":
+		defer e.lockSymbol(position.Symbol)()
+		// Re-read under the lock; a strategy exit may have closed it meanwhile
+		if position, err = e.openPosition(id); err != nil {
+			return nil, err
+		}
+		return e.closePositionMarket(ctx, position, "manual close"), nil
+	case "LIMIT":
+		order, err := e.PlaceManualOrder(ctx, &ManualOrderRequest{
+			Symbol:   position.Symbol,
+			Side:     "SELL",
+			Type:     "LIMIT",
+			Quantity: position.Size,
+			Price:    price,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &CloseResult{PositionID: position.ID, Symbol: position.Symbol, Closed: order.Status == "FILLED"}, nil
+	default:
+		return nil, fmt.Errorf("type must be MARKET or LIMIT")
+	}
+}
+
+// CloseSymbol closes every open position of a symbol at market
+func (e *Engine) CloseSymbol(ctx context.Context, symbol string) ([]*CloseResult, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	return e.closePositionsWhere(ctx, "manual close "+symbol, func(position *models.Position) bool {
+		return position.Symbol == symbol
+	})
+}
+
+// FlattenAccount closes every open position at market and cancels manual orders still
+// waiting to open one
+func (e *Engine) FlattenAccount(ctx context.Context) ([]*CloseResult, error) {
+	results, err := e.closePositionsWhere(ctx, "flatten", func(*models.Position) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := e.repository.GetOpenOrders("")
+	if err != nil {
+		return results, fmt.Errorf("failed to get open orders: %w", err)
+	}
+	for _, order := range orders {
+		if order.Strategy != manualStrategy || order.PositionID != nil {
+			continue
+		}
+		if err := e.CancelManualOrder(ctx, order.ID); err != nil {
+			e.logger.Errorf("Failed to cancel manual order %d while flattening: %v", order.ID, err)
+		}
+	}
+
+	e.recordAudit("position", "flatten", "", results)
+	return results, nil
+}
+
+// closePositionsWhere closes the matching open positions at market
+func (e *Engine) closePositionsWhere(ctx context.Context, reason string, match func(*models.Position) bool) ([]*CloseResult, error) {
+	if e.config.EnablePaperTrading {
+		return nil, fmt.Errorf("closing positions is disabled in paper trading mode")
+	}
+
+	positions, err := e.repository.GetAllPositions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	results := make([]*CloseResult, 0, len(positions))
+	for _, position := range positions {
+		if !match(position) {
+			continue
+		}
+		unlock := e.lockSymbol(position.Symbol)
+		// A position closed meanwhile by a strategy exit is skipped
+		if current, err := e.openPosition(position.ID); err == nil {
+			results = append(results, e.closePositionMarket(ctx, current, reason))
+		} else if !errors.Is(err, ErrPositionNotOpen) {
+			results = append(results, &CloseResult{PositionID: position.ID, Symbol: position.Symbol, Error: err.Error()})
+		}
+		unlock()
+	}
+	return results, nil
+}
+
+// closePositionMarket closes the position through executeSellOrder so the order, PnL and
+// statistics are recorded exactly as for a strategy exit. Callers hold the symbol lock.
+func (e *Engine) closePositionMarket(ctx context.Context, position *models.Position, reason string) *CloseResult {
+	result := &CloseResult{PositionID: position.ID, Symbol: position.Symbol}
+
+	price, err := e.exchangeClient.GetSymbolPrice(ctx, position.Symbol)
+	if err != nil {
+		e.logger.Warnf("Failed to get price for %s before closing: %v", position.Symbol, err)
+	}
+
+	signal := &Signal{Action: "SELL", Quantity: position.Size, Price: price, Confidence: 1.0, Reason: reason}
+	if err := e.executeSellOrder(ctx, position.Symbol, signal, position); err != nil {
+		e.logger.Errorf("Failed to close position for %s: %v", position.Symbol, err)
+		result.Error = err.Error()
+		return result
+	}
+
+	// A market sell that is not reported filled leaves the position open; a failed lookup
+	// says nothing about it
+	_, err = e.openPosition(position.ID)
+	switch {
+	case errors.Is(err, ErrPositionNotOpen):
+		result.Closed = true
+	case err != nil:
+		e.logger.Errorf("Failed to check the close of position %d for %s: %v", position.ID, position.Symbol, err)
+		result.Error = err.Error()
+	}
+	e.recordAudit("position", "close", position.Symbol, map[string]interface{}{
+		"position_id": position.ID,
+		"reason":      reason,
+	})
+	return result
+}
+
+// openPosition returns the open position with the given ID
+func (e *Engine) openPosition(id uint) (*models.Position, error) {
+	positions, err := e.repository.GetAllPositions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+	for _, position := range positions {
+		if position.ID == id {
+			return position, nil
+		}
+	}
+	return nil, fmt.Errorf("%w with id %d", ErrPositionNotOpen, id)
+}
+
+// OpenPositions returns the open positions recorded by the engine
+func (e *Engine) OpenPositions() ([]*models.Position, error) {
+	return e.repository.GetAllPositions()
+}
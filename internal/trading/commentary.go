@@ -0,0 +1,91 @@
+package trading
+
+import (
+	"context"
+	"time"
+
+	"contract_playground/internal/commentary"
+	"contract_playground/internal/models"
+	"contract_playground/pkg/utils"
+)
+
+// commentaryTimeout bounds the whole annotation, including the LLM round trip
+const commentaryTimeout = 2 * time.Minute
+
+// annotateClosedPosition asks the LLM to explain a closed trade and stores the result in
+// Position.Notes. It runs in the background so a slow LLM never delays trading.
+func (e *Engine) annotateClosedPosition(position *models.Position, exitSignal *Signal, exitPrice, pnl float64) {
+	ctx, cancel := context.WithTimeout(e.ctx, commentaryTimeout)
+	defer cancel()
+
+	trade := &commentary.TradeContext{
+		Symbol:       position.Symbol,
+		PositionSide: position.PositionSide,
+		Strategy:     position.Strategy,
+		Size:         position.Size,
+		EntryPrice:   position.EntryPrice,
+		ExitPrice:    exitPrice,
+		PnL:          pnl,
+		OpenTime:     position.OpenTime,
+		CloseTime:    time.Now(),
+		EntryReason:  e.findEntryReason(position),
+		Indicators:   e.commentaryIndicators(position.Symbol),
+	}
+	if exitSignal != nil {
+		trade.ExitReason = exitSignal.Reason
+	}
+	if e.feeds != nil {
+		trade.News = e.feeds.Headlines(position.Symbol, trade.OpenTime, trade.CloseTime)
+	}
+
+	notes, err := e.commentary.Explain(ctx, trade)
+	if err != nil {
+		e.logger.Warnf("Failed to generate trade commentary for position %d: %v", position.ID, err)
+		return
+	}
+
+	if err := e.repository.UpdatePositionNotes(position.ID, notes); err != nil {
+		e.logger.Errorf("Failed to save trade commentary for position %d: %v", position.ID, err)
+		return
+	}
+
+	e.logger.Debugf("Saved trade commentary for position %d", position.ID)
+}
+
+// findEntryReason returns the signal reason of the buy order that opened the position
+func (e *Engine) findEntryReason(position *models.Position) string {
+	orders, err := e.repository.GetOrderHistory(position.Symbol, 50)
+	if err != nil {
+		return ""
+	}
+
+	// Orders are newest first; the opening order is the latest buy placed before the position was recorded
+	for _, order := range orders {
+		if order.Side == "BUY" && !order.CreatedAt.After(position.OpenTime.Add(time.Minute)) {
+			return order.Notes
+		}
+	}
+
+	return ""
+}
+
+// commentaryIndicators summarizes the cached market data for the trade context
+func (e *Engine) commentaryIndicators(symbol string) map[string]float64 {
+	data, err := e.getMarketData(symbol)
+	if err != nil {
+		return nil
+	}
+
+	closes := make([]float64, 0, len(data.Klines))
+	for _, k := range data.Klines {
+		closes = append(closes, k.Close)
+	}
+
+	return map[string]float64{
+		"rsi_14":       utils.CalculateRSI(closes, 14),
+		"sma_20":       utils.CalculateMovingAverage(closes, 20),
+		"volatility":   utils.CalculateVolatility(closes),
+		"return_20":    periodReturn(closes, 20),
+		"funding_rate": data.FundingRate,
+	}
+}
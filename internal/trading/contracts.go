@@ -0,0 +1,104 @@
+package trading
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"contract_playground/internal/exchange"
+)
+
+// contractSpecs caches the contract specification of each traded symbol so PnL and notional
+// math can tell linear from inverse (coin-margined) contracts without an exchange call
+type contractSpecs struct {
+	mu    sync.RWMutex
+	specs map[string]*exchange.SymbolInfo
+}
+
+func newContractSpecs() *contractSpecs {
+	return &contractSpecs{specs: make(map[string]*exchange.SymbolInfo)}
+}
+
+// load fetches the specifications of the given symbols; a symbol that fails keeps linear math
+func (c *contractSpecs) load(ctx context.Context, client exchange.Client, symbols []string) error {
+	var firstErr error
+	for _, symbol := range symbols {
+		info, err := client.GetSymbolInfo(ctx, symbol)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		c.mu.Lock()
+		c.specs[symbol] = info
+		c.mu.Unlock()
+	}
+	return firstErr
+}
+
+// get returns the specification of a symbol, or a linear contract when it is not loaded
+func (c *contractSpecs) get(symbol string) *exchange.SymbolInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if info, ok := c.specs[symbol]; ok {
+		return info
+	}
+	return &exchange.SymbolInfo{Symbol: symbol}
+}
+
+// marginAsset returns the asset a symbol's margin and PnL are settled in; symbols whose
+// specification is not loaded are treated as USDT-margined like the default linear contract
+func (c *contractSpecs) marginAsset(symbol string) string {
+	if asset := c.get(symbol).MarginAsset; asset != "" {
+		return asset
+	}
+	return "USDT"
+}
+
+// marginAssets lists the distinct margin assets of the symbols, joined for messages
+func (c *contractSpecs) marginAssets(symbols []string) string {
+	var assets []string
+	for _, symbol := range symbols {
+		if asset := c.marginAsset(symbol); !slices.Contains(assets, asset) {
+			assets = append(assets, asset)
+		}
+	}
+	if len(assets) == 0 {
+		return "USDT"
+	}
+	return strings.Join(assets, "/")
+}
+
+// contractQuantity converts a base asset quantity at a price into the symbol's order quantity:
+// unchanged for linear contracts and a number of contracts for inverse ones
+func (c *contractSpecs) contractQuantity(symbol string, quantity, price float64) float64 {
+	info := c.get(symbol)
+	if !info.Inverse() {
+		return quantity
+	}
+	return info.Quantity(quantity*price, price)
+}
+
+// positionPnL returns the quote asset PnL of a long quantity of the symbol from entry to exit
+func (e *Engine) positionPnL(symbol string, entry, exit, quantity float64) float64 {
+	return e.contracts.get(symbol).PnL(entry, exit, quantity)
+}
+
+// averageEntry returns the entry price after adding quantity at price to a position
+func (e *Engine) averageEntry(symbol string, entry, size, price, quantity float64) float64 {
+	return e.contracts.get(symbol).AverageEntry(entry, size, price, quantity)
+}
+
+// orderInfo builds the risk view of an order, carrying the contract size for notional math
+func (e *Engine) orderInfo(symbol, side string, quantity, price float64) *OrderInfo {
+	return &OrderInfo{
+		Symbol:       symbol,
+		Side:         side,
+		Quantity:     quantity,
+		Price:        price,
+		ContractSize: e.contracts.get(symbol).ContractSize,
+	}
+}
@@ -0,0 +1,111 @@
+package trading
+
+import (
+	"fmt"
+	"time"
+
+	"contract_playground/internal/models"
+)
+
+// LoadCooldowns restores persisted cooldown state, e.g. after a restart
+func (rm *RiskManager) LoadCooldowns(cooldowns []*models.SymbolCooldown) {
+	rm.cooldownsMu.Lock()
+	defer rm.cooldownsMu.Unlock()
+
+	for _, cooldown := range cooldowns {
+		rm.cooldowns[cooldown.Symbol] = cooldown
+	}
+}
+
+// CooldownReason returns why a new entry on the symbol is blocked at now, or "" if allowed
+func (rm *RiskManager) CooldownReason(symbol string, now time.Time) string {
+	rm.cooldownsMu.Lock()
+	defer rm.cooldownsMu.Unlock()
+
+	cooldown, ok := rm.cooldowns[symbol]
+	if !ok {
+		return ""
+	}
+
+	if cooldown.CooldownUntil != nil && now.Before(*cooldown.CooldownUntil) {
+		return fmt.Sprintf("loss cooldown until %s", cooldown.CooldownUntil.Format(time.RFC3339))
+	}
+
+	if rm.Limits().MinReentryInterval > 0 && cooldown.LastEntryTime != nil {
+		if next := cooldown.LastEntryTime.Add(rm.Limits().MinReentryInterval); now.Before(next) {
+			return fmt.Sprintf("re-entry not allowed until %s", next.Format(time.RFC3339))
+		}
+	}
+
+	return ""
+}
+
+// RecordEntry notes a new position on the symbol and returns the state to persist
+func (rm *RiskManager) RecordEntry(symbol string, at time.Time) *models.SymbolCooldown {
+	rm.cooldownsMu.Lock()
+	defer rm.cooldownsMu.Unlock()
+
+	cooldown := rm.cooldownLocked(symbol)
+	cooldown.LastEntryTime = &at
+	return snapshotCooldown(cooldown)
+}
+
+// RecordExit updates the loss streak for the symbol and starts a cooldown once the streak
+// reaches the limit. It returns the state to persist.
+func (rm *RiskManager) RecordExit(symbol string, pnl float64, at time.Time) *models.SymbolCooldown {
+	rm.cooldownsMu.Lock()
+	defer rm.cooldownsMu.Unlock()
+
+	cooldown := rm.cooldownLocked(symbol)
+	cooldown.LastExitTime = &at
+
+	if pnl >= 0 {
+		cooldown.ConsecutiveLosses = 0
+		return snapshotCooldown(cooldown)
+	}
+
+	cooldown.ConsecutiveLosses++
+	if rm.Limits().MaxConsecutiveLosses > 0 && cooldown.ConsecutiveLosses >= rm.Limits().MaxConsecutiveLosses {
+		until := at.Add(rm.Limits().LossCooldown)
+		cooldown.CooldownUntil = &until
+		cooldown.ConsecutiveLosses = 0
+		rm.logger.Warnf("%s paused until %s after %d consecutive losses",
+			symbol, until.Format(time.RFC3339), rm.Limits().MaxConsecutiveLosses)
+	}
+
+	return snapshotCooldown(cooldown)
+}
+
+// cooldownLocked returns the cooldown state for the symbol, creating it if needed.
+// Callers hold cooldownsMu.
+func (rm *RiskManager) cooldownLocked(symbol string) *models.SymbolCooldown {
+	cooldown, ok := rm.cooldowns[symbol]
+	if !ok {
+		cooldown = &models.SymbolCooldown{Symbol: symbol}
+		rm.cooldowns[symbol] = cooldown
+	}
+	return cooldown
+}
+
+// snapshotCooldown copies cooldown state so it can be persisted outside the lock
+func snapshotCooldown(cooldown *models.SymbolCooldown) *models.SymbolCooldown {
+	snapshot := *cooldown
+	return &snapshot
+}
+
+// loadCooldowns restores persisted cooldown state into the risk manager
+func (e *Engine) loadCooldowns() {
+	cooldowns, err := e.repository.GetSymbolCooldowns()
+	if err != nil {
+		e.logger.Warnf("Failed to load symbol cooldowns: %v", err)
+		return
+	}
+	e.riskManager.LoadCooldowns(cooldowns)
+}
+
+// saveCooldown persists cooldown state; failures only lose state across restarts
+func (e *Engine) saveCooldown(cooldown *models.SymbolCooldown) {
+	if err := e.repository.SaveSymbolCooldown(cooldown); err != nil {
+		e.logger.Errorf("Failed to save cooldown for %s: %v", cooldown.Symbol, err)
+	}
+}
@@ -0,0 +1,236 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+
+	"github.com/sirupsen/logrus"
+)
+
+// minCorrelationSamples is the number of aligned returns required for a correlation
+const minCorrelationSamples = 10
+
+// CorrelationMatrix holds pairwise return correlations across the traded universe
+type CorrelationMatrix struct {
+	Symbols   []string     `json:"symbols"`
+	Values    [][]*float64 `json:"values"`  // nil where there was not enough overlapping data
+	Samples   [][]int      `json:"samples"` // Number of aligned returns behind each value
+	Window    string       `json:"window"`
+	Bucket    string       `json:"bucket"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// CorrelationPair is the correlation between two symbols
+type CorrelationPair struct {
+	SymbolA     string  `json:"symbol_a"`
+	SymbolB     string  `json:"symbol_b"`
+	Correlation float64 `json:"correlation"`
+	Samples     int     `json:"samples"`
+}
+
+// Get returns the correlation between two symbols
+func (m *CorrelationMatrix) Get(a, b string) (float64, bool) {
+	i, j := m.index(a), m.index(b)
+	if i < 0 || j < 0 || m.Values[i][j] == nil {
+		return 0, false
+	}
+	return *m.Values[i][j], true
+}
+
+// Pairs returns all symbol pairs whose absolute correlation is at least min, most
+// correlated first. Useful for selecting pairs-trading candidates.
+func (m *CorrelationMatrix) Pairs(min float64) []*CorrelationPair {
+	var pairs []*CorrelationPair
+	for i := range m.Symbols {
+		for j := i + 1; j < len(m.Symbols); j++ {
+			if m.Values[i][j] == nil || math.Abs(*m.Values[i][j]) < min {
+				continue
+			}
+			pairs = append(pairs, &CorrelationPair{
+				SymbolA:     m.Symbols[i],
+				SymbolB:     m.Symbols[j],
+				Correlation: *m.Values[i][j],
+				Samples:     m.Samples[i][j],
+			})
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		return math.Abs(pairs[i].Correlation) > math.Abs(pairs[j].Correlation)
+	})
+	return pairs
+}
+
+func (m *CorrelationMatrix) index(symbol string) int {
+	for i, s := range m.Symbols {
+		if s == symbol {
+			return i
+		}
+	}
+	return -1
+}
+
+// CorrelationTracker periodically recomputes the correlation matrix from stored market data
+type CorrelationTracker struct {
+	config     config.CorrelationConfig
+	symbols    []string
+	repository database.Repository
+	logger     *logrus.Logger
+
+	mu     sync.RWMutex
+	matrix *CorrelationMatrix
+}
+
+// NewCorrelationTracker creates a new correlation tracker
+func NewCorrelationTracker(cfg config.CorrelationConfig, symbols []string, repository database.Repository, logger *logrus.Logger) *CorrelationTracker {
+	return &CorrelationTracker{
+		config:     cfg,
+		symbols:    symbols,
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// run refreshes the cached matrix until ctx is cancelled
+func (c *CorrelationTracker) run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(c.config.RefreshMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Refresh(); err != nil {
+			c.logger.Errorf("Failed to refresh correlation matrix: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Matrix returns the latest cached matrix, or nil if none has been computed yet
+func (c *CorrelationTracker) Matrix() *CorrelationMatrix {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.matrix
+}
+
+// Refresh recomputes the matrix from stored market data
+func (c *CorrelationTracker) Refresh() error {
+	window := time.Duration(c.config.WindowHours) * time.Hour
+	bucket := time.Duration(c.config.BucketMinutes) * time.Minute
+	since := time.Now().Add(-window).Unix()
+
+	prices := make([]map[int64]float64, len(c.symbols))
+	for i, symbol := range c.symbols {
+		data, err := c.repository.GetMarketDataSince(symbol, since)
+		if err != nil {
+			return fmt.Errorf("failed to load market data for %s: %w", symbol, err)
+		}
+
+		// Resample to the last price seen in each bucket
+		series := make(map[int64]float64)
+		for _, d := range data {
+			if d.Price > 0 {
+				series[d.Timestamp/int64(bucket.Seconds())] = d.Price
+			}
+		}
+		prices[i] = series
+	}
+
+	n := len(c.symbols)
+	matrix := &CorrelationMatrix{
+		Symbols:   append([]string(nil), c.symbols...),
+		Values:    make([][]*float64, n),
+		Samples:   make([][]int, n),
+		Window:    window.String(),
+		Bucket:    bucket.String(),
+		UpdatedAt: time.Now(),
+	}
+	for i := range matrix.Values {
+		matrix.Values[i] = make([]*float64, n)
+		matrix.Samples[i] = make([]int, n)
+	}
+
+	for i := 0; i < n; i++ {
+		one := 1.0
+		matrix.Values[i][i] = &one
+		matrix.Samples[i][i] = len(prices[i])
+
+		for j := i + 1; j < n; j++ {
+			a, b := alignedReturns(prices[i], prices[j])
+			matrix.Samples[i][j] = len(a)
+			matrix.Samples[j][i] = len(a)
+
+			if len(a) < minCorrelationSamples {
+				continue
+			}
+			if corr, ok := pearson(a, b); ok {
+				matrix.Values[i][j] = &corr
+				matrix.Values[j][i] = &corr
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.matrix = matrix
+	c.mu.Unlock()
+
+	return nil
+}
+
+// alignedReturns returns log returns for buckets where both series have a price in the
+// bucket and the one before it
+func alignedReturns(x, y map[int64]float64) ([]float64, []float64) {
+	keys := make([]int64, 0, len(x))
+	for k := range x {
+		if _, ok := y[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	var a, b []float64
+	for _, k := range keys {
+		prevX, okX := x[k-1]
+		prevY, okY := y[k-1]
+		if !okX || !okY {
+			continue
+		}
+		a = append(a, math.Log(x[k]/prevX))
+		b = append(b, math.Log(y[k]/prevY))
+	}
+	return a, b
+}
+
+// pearson returns the Pearson correlation coefficient of two equal-length series
+func pearson(a, b []float64) (float64, bool) {
+	n := float64(len(a))
+	var sumA, sumB float64
+	for i := range a {
+		sumA += a[i]
+		sumB += b[i]
+	}
+	meanA, meanB := sumA/n, sumB/n
+
+	var cov, varA, varB float64
+	for i := range a {
+		da, db := a[i]-meanA, b[i]-meanB
+		cov += da * db
+		varA += da * da
+		varB += db * db
+	}
+
+	if varA == 0 || varB == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varA*varB), true
+}
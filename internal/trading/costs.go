@@ -0,0 +1,61 @@
+package trading
+
+import (
+	"context"
+	"time"
+
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+)
+
+// Income entries fetched per position; one position rarely has more fills and funding events
+const incomeHistoryLimit = 1000
+
+// positionCosts are the commission and funding attributed to a position, signed as income
+type positionCosts struct {
+	commission float64
+	funding    float64
+}
+
+// attributeCosts sums the commissions and funding payments of the position's symbol while it
+// was open, stores them on the position and returns its net PnL. Only one position per
+// symbol is open at a time, so every entry in the window belongs to it. When the income
+// history is unavailable the gross PnL is returned and the costs stay zero. Coin-margined
+// income is paid in the base asset and valued at the current price.
+func (e *Engine) attributeCosts(ctx context.Context, position *models.Position, grossPnL float64) (float64, positionCosts) {
+	var costs positionCosts
+
+	incomes, err := e.exchangeClient.GetIncomeHistory(ctx, position.Symbol, "", position.OpenTime.UnixMilli(), time.Now().UnixMilli(), incomeHistoryLimit)
+	if err != nil {
+		e.logger.Warnf("Failed to get income history for %s, recording gross PnL: %v", position.Symbol, err)
+		position.NetPnL = grossPnL
+		if err := e.repository.UpdatePositionCosts(position.ID, 0, 0, grossPnL); err != nil {
+			e.logger.Errorf("Failed to save PnL for position %d: %v", position.ID, err)
+		}
+		return grossPnL, costs
+	}
+	rate := 1.0
+	if e.contracts.get(position.Symbol).Inverse() {
+		rate = position.EntryPrice
+		if data, err := e.getMarketData(position.Symbol); err == nil && data.Price > 0 {
+			rate = data.Price
+		}
+	}
+	for _, income := range incomes {
+		switch income.IncomeType {
+		case exchange.IncomeCommission:
+			costs.commission += income.Income * rate
+		case exchange.IncomeFundingFee:
+			costs.funding += income.Income * rate
+		}
+	}
+
+	netPnL := grossPnL + costs.commission + costs.funding
+	position.Commission = costs.commission
+	position.FundingFee = costs.funding
+	position.NetPnL = netPnL
+	if err := e.repository.UpdatePositionCosts(position.ID, costs.commission, costs.funding, netPnL); err != nil {
+		e.logger.Errorf("Failed to save costs for position %d: %v", position.ID, err)
+	}
+	return netPnL, costs
+}
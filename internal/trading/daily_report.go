@@ -0,0 +1,143 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"contract_playground/internal/analytics"
+	"contract_playground/internal/events"
+	"contract_playground/internal/notify"
+)
+
+// dailyReport summarizes positions closed during one day in trading.day_timezone
+type dailyReport struct {
+	Date       string                 `json:"date"`
+	Closed     int                    `json:"closed"`
+	Wins       int                    `json:"wins"`
+	Losses     int                    `json:"losses"`
+	NetPnL     float64                `json:"net_pnl"`
+	Fees       float64                `json:"fees"`
+	Funding    float64                `json:"funding"`
+	Benchmarks []*analytics.Benchmark `json:"benchmarks,omitempty"`
+}
+
+// sendDailyReport reports the day ending at end, a midnight in the day location
+func (e *Engine) sendDailyReport(ctx context.Context, end time.Time) error {
+	start := end.AddDate(0, 0, -1)
+	positions, err := e.repository.GetClosedPositions(start, end)
+	if err != nil {
+		return fmt.Errorf("failed to get closed positions: %w", err)
+	}
+
+	stats := summarizeClosedPositions(positions)
+	report := &dailyReport{
+		Date:    start.Format("2006-01-02"),
+		Closed:  stats.Closed,
+		Wins:    stats.Wins,
+		Losses:  stats.Losses,
+		NetPnL:  stats.NetPnL,
+		Fees:    stats.Fees,
+		Funding: stats.Funding,
+	}
+
+	if e.config.DailyReport.BenchmarkDays > 0 {
+		// Exchange daily candles open at midnight UTC, so the benchmark keeps to UTC days
+		benchmarks, err := e.benchmarkSymbols(ctx, end.UTC().Truncate(24*time.Hour))
+		if err != nil {
+			e.logger.Warnf("Daily report has no benchmark: %v", err)
+		}
+		report.Benchmarks = benchmarks
+	}
+
+	lines := []string{
+		fmt.Sprintf("Closed %d positions (%d wins, %d losses), net PnL %.2f %s, fees %.2f, funding %.2f",
+			report.Closed, report.Wins, report.Losses, report.NetPnL, e.contracts.marginAssets(e.config.Symbols), report.Fees, report.Funding),
+	}
+	if len(report.Benchmarks) > 0 {
+		lines = append(lines, fmt.Sprintf("Versus buy-and-hold over %d days:", e.config.DailyReport.BenchmarkDays))
+	}
+	for _, b := range report.Benchmarks {
+		lines = append(lines, fmt.Sprintf("%s: strategy %+.2f%%, buy-and-hold %+.2f%%, alpha %+.2f%%/yr, beta %.2f, drawdown %.2f%% vs %.2f%%, relative drawdown %.2f%%",
+			b.Symbol, b.StrategyReturn*100, b.BenchmarkReturn*100, b.Alpha*100, b.Beta,
+			b.StrategyDrawdown*100, b.BenchmarkDrawdown*100, b.RelativeDrawdown*100))
+	}
+
+	e.recordAudit("report", "daily", "", report)
+	e.publishEvent(events.TypeRisk, "daily_report", "", report)
+
+	msg := &notify.Message{
+		Title: fmt.Sprintf("Daily report %s", report.Date),
+		Body:  strings.Join(lines, "\n"),
+		Level: notify.LevelInfo,
+		Time:  time.Now(),
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		return fmt.Errorf("failed to deliver daily report: %w", err)
+	}
+	return nil
+}
+
+// benchmarkSymbols compares each symbol's daily net PnL, as a fraction of current equity,
+// with the daily returns of holding the symbol over the benchmark_days before end. Symbols
+// without a complete set of daily candles are skipped.
+func (e *Engine) benchmarkSymbols(ctx context.Context, end time.Time) ([]*analytics.Benchmark, error) {
+	days := e.config.DailyReport.BenchmarkDays
+	start := end.AddDate(0, 0, -days)
+
+	account, err := e.exchangeClient.GetAccountInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account info: %w", err)
+	}
+	if account.TotalMarginBalance <= 0 {
+		return nil, fmt.Errorf("account equity %.2f is not positive", account.TotalMarginBalance)
+	}
+
+	positions, err := e.repository.GetClosedPositions(start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get closed positions: %w", err)
+	}
+	pnl := make(map[string][]float64, len(e.config.Symbols))
+	for _, position := range positions {
+		if position.CloseTime == nil {
+			continue
+		}
+		day := int(position.CloseTime.Sub(start) / (24 * time.Hour))
+		if day < 0 || day >= days {
+			continue
+		}
+		if pnl[position.Symbol] == nil {
+			pnl[position.Symbol] = make([]float64, days)
+		}
+		pnl[position.Symbol][day] += position.NetPnL
+	}
+
+	benchmarks := make([]*analytics.Benchmark, 0, len(e.config.Symbols))
+	for _, symbol := range e.config.Symbols {
+		// The close of the day before start anchors the first daily return
+		klines, err := e.exchangeClient.GetKlines(ctx, symbol, "1d", days+2)
+		if err != nil {
+			e.logger.Warnf("Failed to get daily klines of %s for the benchmark: %v", symbol, err)
+			continue
+		}
+		first := start.AddDate(0, 0, -1).UnixMilli()
+		closes := make([]float64, 0, days+1)
+		for _, k := range klines {
+			if k.OpenTime >= first && k.OpenTime < end.UnixMilli() {
+				closes = append(closes, k.Close)
+			}
+		}
+		if len(closes) != days+1 {
+			e.logger.Warnf("Benchmark of %s skipped: %d of %d daily candles", symbol, len(closes), days+1)
+			continue
+		}
+
+		returns := make([]float64, days)
+		for day, amount := range pnl[symbol] {
+			returns[day] = amount / account.TotalMarginBalance
+		}
+		benchmarks = append(benchmarks, analytics.Compare(symbol, returns, analytics.DailyReturns(closes)))
+	}
+	return benchmarks, nil
+}
@@ -0,0 +1,76 @@
+package trading
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"contract_playground/internal/models"
+)
+
+// dailyStats summarizes the positions closed during one day
+type dailyStats struct {
+	Day     time.Time // Midnight in the day location
+	Closed  int
+	Wins    int
+	Losses  int
+	NetPnL  float64 // Net of commissions and funding
+	Fees    float64 // Commission income; negative when paid
+	Funding float64 // Funding income; negative when paid
+}
+
+// summarizeClosedPositions totals closed positions; a position without a positive net PnL
+// counts as a loss
+func summarizeClosedPositions(positions []*models.Position) *dailyStats {
+	stats := &dailyStats{Closed: len(positions)}
+	for _, position := range positions {
+		stats.NetPnL += position.NetPnL
+		stats.Fees += position.Commission
+		stats.Funding += position.FundingFee
+		if position.NetPnL > 0 {
+			stats.Wins++
+		} else {
+			stats.Losses++
+		}
+	}
+	return stats
+}
+
+// todayStats returns the figures of positions closed since midnight. They are read from the
+// positions table, like the daily report, so they survive restarts and are shared by every
+// instance; the result is cached until the next close or midnight.
+func (e *Engine) todayStats() (*dailyStats, error) {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+
+	now := time.Now()
+	day := startOfDay(now, e.dayLocation)
+	if e.stats != nil && e.stats.Day.Equal(day) {
+		stats := *e.stats
+		return &stats, nil
+	}
+
+	positions, err := e.repository.GetClosedPositions(day, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get closed positions: %w", err)
+	}
+	e.stats = summarizeClosedPositions(positions)
+	e.stats.Day = day
+	stats := *e.stats
+	return &stats, nil
+}
+
+// refreshDailyLoss reloads today's figures after a close and hands the day's net loss to
+// the risk manager, so max_daily_loss sees the same number as the status and reports
+func (e *Engine) refreshDailyLoss() {
+	e.statsMu.Lock()
+	e.stats = nil
+	e.statsMu.Unlock()
+
+	stats, err := e.todayStats()
+	if err != nil {
+		e.logger.Errorf("Failed to load today's closed positions for the daily loss limit: %v", err)
+		return
+	}
+	e.riskManager.SetDailyLoss(math.Max(0, -stats.NetPnL))
+}
@@ -0,0 +1,37 @@
+package trading
+
+import (
+	"io"
+	"testing"
+
+	"contract_playground/internal/models"
+)
+
+func TestSummarizeClosedPositions(t *testing.T) {
+	stats := summarizeClosedPositions([]*models.Position{
+		{NetPnL: 12, Commission: -1, FundingFee: 0.5},
+		{NetPnL: -4, Commission: -1},
+		{NetPnL: 0},
+	})
+	if stats.Closed != 3 || stats.Wins != 1 || stats.Losses != 2 {
+		t.Errorf("counts = %d closed, %d wins, %d losses; want 3, 1, 2", stats.Closed, stats.Wins, stats.Losses)
+	}
+	if stats.NetPnL != 8 || stats.Fees != -2 || stats.Funding != 0.5 {
+		t.Errorf("totals = %+v, want net 8, fees -2, funding 0.5", stats)
+	}
+}
+
+func TestSetDailyLossKeepsHaltedDay(t *testing.T) {
+	rm := NewRiskManager(&RiskConfig{MaxDailyLoss: 100})
+	rm.logger.SetOutput(io.Discard)
+
+	rm.SetDailyLoss(40)
+	if !rm.isTradingAllowed() {
+		t.Fatal("trading halted below the daily loss limit")
+	}
+	rm.SetDailyLoss(120)
+	rm.SetDailyLoss(30)
+	if rm.isTradingAllowed() || rm.GetRiskMetrics().DailyLoss != 120 {
+		t.Errorf("daily loss = %v after recovering, want the halted 120", rm.GetRiskMetrics().DailyLoss)
+	}
+}
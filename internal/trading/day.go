@@ -0,0 +1,12 @@
+package trading
+
+import (
+	"time"
+)
+
+// startOfDay returns midnight of the day containing t in location. Days follow the local
+// calendar, so they last 23 or 25 hours when daylight saving time changes.
+func startOfDay(t time.Time, location *time.Location) time.Time {
+	local := t.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+}
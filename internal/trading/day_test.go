@@ -0,0 +1,28 @@
+package trading
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStartOfDayFollowsLocalCalendar(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no time zone data: %v", err)
+	}
+
+	// 03:30 UTC on March 10 is still March 9 in New York
+	if got, want := startOfDay(time.Date(2024, 3, 10, 3, 30, 0, 0, time.UTC), newYork), time.Date(2024, 3, 9, 5, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("start of day = %v, want %v", got, want)
+	}
+
+	// Clocks spring forward on March 10, so that day lasts 23 hours
+	start := startOfDay(time.Date(2024, 3, 10, 12, 0, 0, 0, newYork), newYork)
+	next := startOfDay(time.Date(2024, 3, 11, 12, 0, 0, 0, newYork), newYork)
+	if length := next.Sub(start); length != 23*time.Hour {
+		t.Errorf("day length = %v, want 23h", length)
+	}
+	if !start.AddDate(0, 0, 1).Equal(next) {
+		t.Errorf("AddDate(0, 0, 1) = %v, want %v", start.AddDate(0, 0, 1), next)
+	}
+}
@@ -0,0 +1,167 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"contract_playground/internal/models"
+)
+
+// DCAStrategy opens a base order and averages down with a ladder of safety orders,
+// closing the whole position once price recovers to a target above the average entry
+type DCAStrategy struct {
+	name              string
+	baseOrderValue    float64 // USDT
+	safetyOrderValue  float64 // USDT of the first safety order
+	maxSafetyOrders   int
+	priceDeviation    float64 // Percent below entry of the first safety order
+	stepScale         float64 // Multiplier applied to each subsequent deviation step
+	volumeScale       float64 // Multiplier applied to each subsequent safety order value
+	takeProfitPercent float64 // Percent above the average entry
+	stopLossPercent   float64 // Percent below the average entry (0 = no stop)
+	stopWorkingType   string
+}
+
+// NewDCAStrategy creates a new DCA strategy
+func NewDCAStrategy() Strategy {
+	return &DCAStrategy{
+		name:              "DCA Strategy",
+		baseOrderValue:    100,
+		safetyOrderValue:  200,
+		maxSafetyOrders:   5,
+		priceDeviation:    1.0,
+		stepScale:         1.0,
+		volumeScale:       1.5,
+		takeProfitPercent: 1.5,
+		stopWorkingType:   WorkingTypeContractPrice,
+	}
+}
+
+// Name returns the strategy name
+func (d *DCAStrategy) Name() string {
+	return d.name
+}
+
+// Initialize initializes the DCA strategy with parameters
+func (d *DCAStrategy) Initialize(config map[string]interface{}) error {
+	if val, ok := getFloatParam(config, "base_order_usdt"); ok {
+		d.baseOrderValue = val
+	}
+	if val, ok := getFloatParam(config, "safety_order_usdt"); ok {
+		d.safetyOrderValue = val
+	}
+	if val, ok := getFloatParam(config, "max_safety_orders"); ok {
+		d.maxSafetyOrders = int(val)
+	}
+	if val, ok := getFloatParam(config, "price_deviation"); ok {
+		d.priceDeviation = val
+	}
+	if val, ok := getFloatParam(config, "step_scale"); ok {
+		d.stepScale = val
+	}
+	if val, ok := getFloatParam(config, "volume_scale"); ok {
+		d.volumeScale = val
+	}
+	if val, ok := getFloatParam(config, "take_profit"); ok {
+		d.takeProfitPercent = val
+	}
+	if val, ok := getFloatParam(config, "stop_loss"); ok {
+		d.stopLossPercent = val
+	}
+	if workingType, ok := getStringParam(config, "stop_working_type"); ok && workingType != "" {
+		d.stopWorkingType = workingType
+	}
+
+	if d.baseOrderValue <= 0 {
+		return fmt.Errorf("base order value must be positive")
+	}
+	if d.maxSafetyOrders < 0 {
+		return fmt.Errorf("max safety orders must not be negative")
+	}
+	if d.maxSafetyOrders > 0 && (d.safetyOrderValue <= 0 || d.priceDeviation <= 0) {
+		return fmt.Errorf("safety order value and price deviation must be positive")
+	}
+	if d.stepScale <= 0 || d.volumeScale <= 0 {
+		return fmt.Errorf("step scale and volume scale must be positive")
+	}
+	if d.takeProfitPercent <= 0 {
+		return fmt.Errorf("take profit must be positive")
+	}
+
+	// The deepest safety order must stay above zero
+	if deviation := d.safetyDeviation(d.maxSafetyOrders); deviation >= 100 {
+		return fmt.Errorf("safety order deviation %.2f%% reaches zero price", deviation)
+	}
+
+	return nil
+}
+
+// ShouldBuy opens the base order with its safety order ladder whenever flat
+func (d *DCAStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
+	if data.Price <= 0 {
+		return &Signal{Action: "HOLD", Reason: "No price"}, nil
+	}
+
+	return &Signal{
+		Action:       "BUY",
+		Quantity:     d.baseOrderValue / data.Price,
+		Price:        data.Price,
+		Confidence:   1.0,
+		Reason:       fmt.Sprintf("DCA base order with %d safety orders", d.maxSafetyOrders),
+		PositionSide: "LONG",
+		SafetyOrders: d.safetyOrders(data.Price),
+	}, nil
+}
+
+// ShouldSell closes the position at the take-profit on the average entry, or at the stop
+func (d *DCAStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
+	target := position.EntryPrice * (1 + d.takeProfitPercent/100)
+	if data.Price >= target {
+		return &Signal{
+			Action:     "SELL",
+			Quantity:   position.Size,
+			Price:      data.Price,
+			Confidence: 1.0,
+			Reason:     fmt.Sprintf("DCA take profit: %.6f >= %.6f (avg entry %.6f)", data.Price, target, position.EntryPrice),
+		}, nil
+	}
+
+	if d.stopLossPercent > 0 {
+		stop := position.EntryPrice * (1 - d.stopLossPercent/100)
+		if data.WorkingPrice(d.stopWorkingType) <= stop {
+			return &Signal{
+				Action:     "SELL",
+				Quantity:   position.Size,
+				Price:      data.Price,
+				Confidence: 1.0,
+				Reason:     fmt.Sprintf("DCA stop loss: %.6f", stop),
+			}, nil
+		}
+	}
+
+	return &Signal{Action: "HOLD", Reason: fmt.Sprintf("DCA target %.6f", target)}, nil
+}
+
+// safetyOrders builds the ladder below the base order price
+func (d *DCAStrategy) safetyOrders(price float64) []SafetyOrder {
+	orders := make([]SafetyOrder, 0, d.maxSafetyOrders)
+	for i := 1; i <= d.maxSafetyOrders; i++ {
+		orderPrice := price * (1 - d.safetyDeviation(i)/100)
+		value := d.safetyOrderValue * math.Pow(d.volumeScale, float64(i-1))
+		orders = append(orders, SafetyOrder{Price: orderPrice, Quantity: value / orderPrice})
+	}
+	return orders
+}
+
+// safetyDeviation returns the total deviation from the base price of the n-th safety order:
+// each step is the previous one scaled by stepScale
+func (d *DCAStrategy) safetyDeviation(n int) float64 {
+	deviation := 0.0
+	step := d.priceDeviation
+	for i := 0; i < n; i++ {
+		deviation += step
+		step *= d.stepScale
+	}
+	return deviation
+}
@@ -0,0 +1,205 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"contract_playground/internal/auditlog"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+)
+
+// Perpetuals carry a delivery date far in the future; an earlier date is a scheduled
+// settlement or delisting
+const delistingHorizon = 365 * 24 * time.Hour
+
+// symbolDelisting is a traded symbol the exchange is winding down
+type symbolDelisting struct {
+	Symbol   string     `json:"symbol"`
+	Status   string     `json:"status"`
+	Deadline *time.Time `json:"deadline,omitempty"` // Settlement time, if announced
+	Closed   bool       `json:"closed"`             // Positions were closed ahead of the deadline
+}
+
+func (d *symbolDelisting) describe() string {
+	if d.Deadline != nil {
+		return fmt.Sprintf("status %s, settles at %s", d.Status, d.Deadline.UTC().Format(time.RFC3339))
+	}
+	return "status " + d.Status
+}
+
+// isBlacklisted reports whether the symbol is on the configured blacklist
+func (e *Engine) isBlacklisted(symbol string) bool {
+	for _, blocked := range e.config.Blacklist {
+		if strings.EqualFold(blocked, symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// delistingFilter returns a reason when the symbol is blacklisted or being delisted
+func (e *Engine) delistingFilter(symbol string) string {
+	if e.isBlacklisted(symbol) {
+		return "symbol is blacklisted"
+	}
+
+	e.delistingMu.Lock()
+	defer e.delistingMu.Unlock()
+	if delisting, ok := e.delistings[symbol]; ok {
+		return "symbol is being delisted: " + delisting.describe()
+	}
+	return ""
+}
+
+// refreshSymbols reloads contract specs and checks exchange info for traded symbols being
+// wound down
+func (e *Engine) refreshSymbols(ctx context.Context) error {
+	if err := e.contracts.load(ctx, e.exchangeClient, e.config.Symbols); err != nil {
+		e.logger.Warnf("Failed to reload contract specs: %v", err)
+	}
+	if err := e.checkDelistings(ctx); err != nil {
+		return fmt.Errorf("failed to check for delistings: %w", err)
+	}
+	return nil
+}
+
+// checkDelistings blocks entries and alerts when a traded symbol leaves TRADING status or
+// gets a settlement date, and closes its positions once the deadline is within
+// close_before_hours or trading has already stopped
+func (e *Engine) checkDelistings(ctx context.Context) error {
+	info, err := e.exchangeClient.GetExchangeInfo(ctx)
+	if err != nil {
+		return err
+	}
+	symbols := make(map[string]*exchange.SymbolInfo, len(info.Symbols))
+	for _, symbol := range info.Symbols {
+		symbols[symbol.Symbol] = symbol
+	}
+
+	now := time.Now()
+	for _, symbol := range e.config.Symbols {
+		current, ok := symbols[symbol]
+		if !ok {
+			// Removed from exchange info altogether
+			current = &exchange.SymbolInfo{Symbol: symbol, Status: "REMOVED"}
+		}
+
+		var deadline *time.Time
+		if current.DeliveryDate > 0 {
+			if at := time.UnixMilli(current.DeliveryDate); at.Before(now.Add(delistingHorizon)) {
+				deadline = &at
+			}
+		}
+		if current.Status == "TRADING" && deadline == nil {
+			e.clearDelisting(ctx, symbol)
+			continue
+		}
+
+		delisting := e.trackDelisting(ctx, symbol, current.Status, deadline)
+		closeBy := now.Add(time.Duration(e.config.Delisting.CloseBeforeHours) * time.Hour)
+		if !delisting.Closed && !e.config.EnablePaperTrading && (current.Status != "TRADING" || deadline.Before(closeBy)) {
+			e.closeDelistedPositions(ctx, delisting)
+		}
+	}
+	return nil
+}
+
+// trackDelisting records the symbol's wind-down and alerts the first time it is seen or when
+// its status or deadline changes
+func (e *Engine) trackDelisting(ctx context.Context, symbol, status string, deadline *time.Time) *symbolDelisting {
+	e.delistingMu.Lock()
+	delisting, known := e.delistings[symbol]
+	changed := !known || delisting.Status != status || !sameDeadline(delisting.Deadline, deadline)
+	if !known {
+		delisting = &symbolDelisting{Symbol: symbol}
+		e.delistings[symbol] = delisting
+	}
+	delisting.Status = status
+	delisting.Deadline = deadline
+	snapshot := *delisting
+	e.delistingMu.Unlock()
+
+	if changed {
+		e.logger.Warnf("%s is being delisted (%s); new entries are blocked", symbol, snapshot.describe())
+		e.recordAudit("delisting", "detected", symbol, snapshot)
+		e.notifyDelisting(ctx, symbol, fmt.Sprintf("%s is being delisted", symbol),
+			fmt.Sprintf("Exchange reports %s. New entries are blocked; open positions are closed %dh before the deadline.",
+				snapshot.describe(), e.config.Delisting.CloseBeforeHours), notify.LevelCritical)
+	}
+	return delisting
+}
+
+// clearDelisting lifts the entry block when a tracked symbol is trading normally again
+func (e *Engine) clearDelisting(ctx context.Context, symbol string) {
+	e.delistingMu.Lock()
+	_, known := e.delistings[symbol]
+	delete(e.delistings, symbol)
+	e.delistingMu.Unlock()
+
+	if known {
+		e.logger.Infof("%s is trading normally again; new entries are allowed", symbol)
+		e.recordAudit("delisting", "cleared", symbol, nil)
+		e.notifyDelisting(ctx, symbol, fmt.Sprintf("%s delisting cancelled", symbol),
+			"The exchange reports the symbol as trading with no settlement date; new entries are allowed.", notify.LevelInfo)
+	}
+}
+
+func sameDeadline(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// closeDelistedPositions closes the symbol's open positions at market and alerts with the
+// outcome. It is retried on the next check until every close succeeds.
+func (e *Engine) closeDelistedPositions(ctx context.Context, delisting *symbolDelisting) {
+	symbol := delisting.Symbol
+	ctx = auditlog.WithSource(ctx, auditlog.TriggerRisk, "delisting")
+	results, err := e.closePositionsWhere(ctx, "delisting", func(position *models.Position) bool {
+		return position.Symbol == symbol
+	})
+	if err != nil {
+		e.logger.Errorf("Failed to close positions of delisted %s: %v", symbol, err)
+		return
+	}
+
+	var failures []string
+	for _, result := range results {
+		if result.Error != "" {
+			failures = append(failures, fmt.Sprintf("position %d: %s", result.PositionID, result.Error))
+		}
+	}
+	if len(failures) > 0 {
+		e.notifyDelisting(ctx, symbol, fmt.Sprintf("Failed to close %s before delisting", symbol),
+			strings.Join(failures, "\n"), notify.LevelCritical)
+		return
+	}
+
+	e.delistingMu.Lock()
+	delisting.Closed = true
+	e.delistingMu.Unlock()
+
+	if len(results) > 0 {
+		e.recordAudit("delisting", "positions_closed", symbol, results)
+		e.notifyDelisting(ctx, symbol, fmt.Sprintf("Closed %s ahead of delisting", symbol),
+			fmt.Sprintf("Closed %d position(s) at market.", len(results)), notify.LevelWarning)
+	}
+}
+
+func (e *Engine) notifyDelisting(ctx context.Context, symbol, title, body string, level notify.Level) {
+	msg := &notify.Message{
+		Title:  title,
+		Body:   body,
+		Level:  level,
+		Symbol: symbol,
+		Time:   time.Now(),
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver delisting notification: %v", err)
+	}
+}
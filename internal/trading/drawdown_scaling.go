@@ -0,0 +1,118 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DrawdownScaleStatus is the account's distance from its high-water mark and the size scale
+// derived from it
+type DrawdownScaleStatus struct {
+	Equity          float64   `json:"equity"`
+	HighWaterMark   float64   `json:"high_water_mark"`
+	DrawdownPercent float64   `json:"drawdown_percent"`
+	Scale           float64   `json:"scale"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// DrawdownScaler follows account equity against its high-water mark and derives the factor
+// new position sizes are multiplied by, so risk per trade shrinks in a drawdown and grows
+// back as equity recovers
+type DrawdownScaler struct {
+	config     config.DrawdownScalingConfig
+	repository database.Repository
+	logger     *logrus.Logger
+
+	mu     sync.RWMutex
+	status *DrawdownScaleStatus
+}
+
+// NewDrawdownScaler creates a drawdown scaler; sizes are unscaled until the first refresh
+func NewDrawdownScaler(cfg config.DrawdownScalingConfig, repository database.Repository, logger *logrus.Logger) *DrawdownScaler {
+	return &DrawdownScaler{
+		config:     cfg,
+		repository: repository,
+		logger:     logger,
+		status:     &DrawdownScaleStatus{Scale: 1},
+	}
+}
+
+// Refresh reads the latest account snapshot and the high-water mark over the lookback.
+// Withdrawals look like losses, so they reduce sizes until equity makes a new high or the
+// peak leaves the lookback.
+func (d *DrawdownScaler) Refresh(ctx context.Context) error {
+	account, err := d.repository.GetLatestAccount()
+	if err != nil {
+		return fmt.Errorf("failed to get latest account snapshot: %w", err)
+	}
+	var since time.Time
+	if d.config.LookbackDays > 0 {
+		since = time.Now().AddDate(0, 0, -d.config.LookbackDays)
+	}
+	peak, err := d.repository.GetPeakEquity(since)
+	if err != nil {
+		return fmt.Errorf("failed to get high-water mark: %w", err)
+	}
+
+	status := &DrawdownScaleStatus{
+		Equity:        account.TotalMarginBalance,
+		HighWaterMark: math.Max(peak, account.TotalMarginBalance),
+		Scale:         1,
+		UpdatedAt:     time.Now(),
+	}
+	if status.HighWaterMark > 0 {
+		status.DrawdownPercent = (status.HighWaterMark - status.Equity) / status.HighWaterMark * 100
+		status.Scale = drawdownScale(d.config.Schedule, status.DrawdownPercent)
+	}
+
+	d.mu.Lock()
+	previous := d.status.Scale
+	d.status = status
+	d.mu.Unlock()
+
+	if math.Abs(status.Scale-previous) >= 0.05 {
+		d.logger.Infof("Equity %.2f is %.1f%% below its high-water mark %.2f, position sizes scaled by %.2f",
+			status.Equity, status.DrawdownPercent, status.HighWaterMark, status.Scale)
+	}
+	return nil
+}
+
+// drawdownScale interpolates the schedule linearly, starting from a scale of 1 at no
+// drawdown and keeping the last step's scale beyond it
+func drawdownScale(schedule []config.DrawdownStepConfig, drawdownPercent float64) float64 {
+	previous := config.DrawdownStepConfig{Scale: 1}
+	for _, step := range schedule {
+		if drawdownPercent < step.DrawdownPercent {
+			if drawdownPercent <= previous.DrawdownPercent {
+				return previous.Scale
+			}
+			fraction := (drawdownPercent - previous.DrawdownPercent) / (step.DrawdownPercent - previous.DrawdownPercent)
+			return previous.Scale + fraction*(step.Scale-previous.Scale)
+		}
+		previous = step
+	}
+	return previous.Scale
+}
+
+// Scale returns the factor applied to new position sizes
+func (d *DrawdownScaler) Scale() float64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.status.Scale
+}
+
+// Status returns the latest drawdown and scale
+func (d *DrawdownScaler) Status() *DrawdownScaleStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	status := *d.status
+	return &status
+}
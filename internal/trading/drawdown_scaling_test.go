@@ -0,0 +1,34 @@
+package trading
+
+import (
+	"math"
+	"testing"
+
+	"contract_playground/internal/config"
+)
+
+func TestDrawdownScale(t *testing.T) {
+	schedule := []config.DrawdownStepConfig{
+		{DrawdownPercent: 5, Scale: 0.75},
+		{DrawdownPercent: 10, Scale: 0.5},
+		{DrawdownPercent: 20, Scale: 0.25},
+	}
+	for _, tc := range []struct {
+		drawdown float64
+		want     float64
+	}{
+		{0, 1},
+		{2.5, 0.875},
+		{5, 0.75},
+		{15, 0.375},
+		{20, 0.25},
+		{50, 0.25},
+	} {
+		if got := drawdownScale(schedule, tc.drawdown); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("drawdownScale(%v) = %v, want %v", tc.drawdown, got, tc.want)
+		}
+	}
+	if got := drawdownScale(nil, 30); got != 1 {
+		t.Errorf("empty schedule scale = %v, want 1", got)
+	}
+}
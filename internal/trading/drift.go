@@ -0,0 +1,173 @@
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+)
+
+// Orders are placed with position side BOTH, which requires one-way position mode
+const expectedDualSidePosition = false
+
+// settingDrift is a difference between a configured and an actual exchange setting
+type settingDrift struct {
+	Symbol   string `json:"symbol,omitempty"`
+	Setting  string `json:"setting"` // leverage, margin_type, position_mode
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Result   string `json:"result,omitempty"`
+}
+
+// checkDrift detects drift in position mode, leverage and margin type and reconciles or
+// alerts on each difference
+func (e *Engine) checkDrift(ctx context.Context) error {
+	var drifts []*settingDrift
+
+	dualSide, err := e.exchangeClient.GetPositionMode(ctx)
+	if err != nil {
+		return err
+	}
+	if dualSide != expectedDualSidePosition {
+		drifts = append(drifts, &settingDrift{
+			Setting:  "position_mode",
+			Expected: positionModeName(expectedDualSidePosition),
+			Actual:   positionModeName(dualSide),
+		})
+	}
+
+	settings, err := e.exchangeClient.GetSymbolSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	actual := make(map[string]*exchangeSymbolSettings, len(settings))
+	for _, setting := range settings {
+		actual[setting.Symbol] = &exchangeSymbolSettings{leverage: setting.Leverage, marginType: setting.MarginType}
+	}
+
+	for _, symbol := range e.config.Symbols {
+		current, ok := actual[symbol]
+		if !ok {
+			e.logger.Warnf("No exchange settings returned for %s", symbol)
+			continue
+		}
+
+		if expected := e.symbolLeverage(symbol); current.leverage != expected {
+			drifts = append(drifts, &settingDrift{
+				Symbol:   symbol,
+				Setting:  "leverage",
+				Expected: strconv.Itoa(expected),
+				Actual:   strconv.Itoa(current.leverage),
+			})
+		}
+
+		if current.marginType != e.config.MarginType {
+			drifts = append(drifts, &settingDrift{
+				Symbol:   symbol,
+				Setting:  "margin_type",
+				Expected: e.config.MarginType,
+				Actual:   current.marginType,
+			})
+		}
+	}
+
+	for _, drift := range drifts {
+		e.handleDrift(ctx, drift)
+	}
+
+	return nil
+}
+
+type exchangeSymbolSettings struct {
+	leverage   int
+	marginType string
+}
+
+// handleDrift reconciles a drifted setting when enabled, then alerts and audits the outcome
+func (e *Engine) handleDrift(ctx context.Context, drift *settingDrift) {
+	action := "drift_detected"
+	level := notify.LevelWarning
+
+	if e.config.Drift.AutoReconcile {
+		action = "drift_reconciled"
+		if err := e.reconcileDrift(ctx, drift); err != nil {
+			action = "drift_reconcile_failed"
+			level = notify.LevelCritical
+			drift.Result = err.Error()
+		} else {
+			drift.Result = "restored"
+		}
+	}
+
+	e.logger.Warnf("Exchange setting drift on %s %s: expected %s, actual %s (%s)",
+		drift.Symbol, drift.Setting, drift.Expected, drift.Actual, action)
+
+	e.recordAudit("drift", action, drift.Symbol, drift)
+
+	subject := drift.Setting
+	if drift.Symbol != "" {
+		subject = drift.Symbol + " " + drift.Setting
+	}
+	msg := &notify.Message{
+		Title:  fmt.Sprintf("Exchange setting drift: %s", subject),
+		Body:   fmt.Sprintf("%s is %s on the exchange but configured as %s (%s)", subject, drift.Actual, drift.Expected, action),
+		Level:  level,
+		Symbol: drift.Symbol,
+		Time:   time.Now(),
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver drift notification: %v", err)
+	}
+}
+
+// reconcileDrift restores the configured value of a drifted setting
+func (e *Engine) reconcileDrift(ctx context.Context, drift *settingDrift) error {
+	switch drift.Setting {
+	case "position_mode":
+		return e.exchangeClient.SetPositionMode(ctx, expectedDualSidePosition)
+	case "leverage":
+		return e.exchangeClient.SetLeverage(ctx, drift.Symbol, e.symbolLeverage(drift.Symbol))
+	case "margin_type":
+		return e.exchangeClient.ChangeMarginType(ctx, drift.Symbol, e.config.MarginType)
+	default:
+		return fmt.Errorf("unknown setting %s", drift.Setting)
+	}
+}
+
+// recordAudit writes an audit trail entry made by the engine itself
+func (e *Engine) recordAudit(category, action, symbol string, details interface{}) {
+	e.RecordAudit(category, action, symbol, "engine", details)
+}
+
+// RecordAudit writes an audit trail entry attributed to actor, such as an API caller;
+// failures are logged but never block trading
+func (e *Engine) RecordAudit(category, action, symbol, actor string, details interface{}) {
+	payload, err := json.Marshal(details)
+	if err != nil {
+		e.logger.Errorf("Failed to encode audit details: %v", err)
+		return
+	}
+
+	event := &models.AuditEvent{
+		Category: category,
+		Action:   action,
+		Symbol:   symbol,
+		Actor:    actor,
+		Details:  string(payload),
+	}
+	if err := e.repository.CreateAuditEvent(event); err != nil {
+		e.logger.Errorf("Failed to record audit event: %v", err)
+	}
+}
+
+func positionModeName(dualSide bool) string {
+	if dualSide {
+		return "hedge"
+	}
+	return "one_way"
+}
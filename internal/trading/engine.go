@@ -2,14 +2,23 @@ package trading
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"sync"
 	"time"
 
+	"contract_playground/internal/auditlog"
+	"contract_playground/internal/commentary"
 	"contract_playground/internal/config"
 	"contract_playground/internal/database"
+	"contract_playground/internal/events"
 	"contract_playground/internal/exchange"
+	"contract_playground/internal/feeds"
 	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+	"contract_playground/internal/scheduler"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
@@ -23,6 +32,7 @@ type Engine struct {
 	redis          *redis.Client
 	repository     database.Repository
 	exchangeClient exchange.Client
+	spotClient     exchange.SpotClient
 	logger         *logrus.Logger
 
 	// Internal state
@@ -32,18 +42,145 @@ type Engine struct {
 	cancel    context.CancelFunc
 
 	// Strategy and risk management
-	strategy    Strategy
-	riskManager *RiskManager
-
-	// Market data
-	marketData   map[string]*exchange.KlineData
-	marketDataMu sync.RWMutex
-
-	// Performance tracking
-	dailyPnL      float64
-	totalTrades   int
-	winningTrades int
-	losingTrades  int
+	strategy       Strategy
+	warmupStrategy WarmupStrategy // nil when the strategy needs no history
+	// nil when the strategy keeps no state or state persistence is disabled
+	statefulStrategy StatefulStrategy
+	// Journal snapshots, nil when the strategy does not report them
+	indicatorStrategy IndicatorStrategy
+	snapshotStrategy  StatefulStrategy
+	strategyCloser    io.Closer // External strategy processes, closed on Stop
+	riskManager       *RiskManager
+	sizer             *Sizer
+	contracts         *contractSpecs // Linear or inverse contract math per symbol
+	volTarget         *VolTargeter
+	drawdownScaler    *DrawdownScaler
+	profitLock        *ProfitLock
+
+	// Optional post-trade commentary
+	commentary *commentary.Generator
+
+	// Notifications and unrealized PnL alerts
+	notifier   notify.Notifier
+	pnlAlerter *pnlAlerter
+
+	// Order, position and risk events for streaming subscribers; position changes are also
+	// queued in the outbox with eventOutbox
+	events      *events.Bus
+	eventOutbox bool
+	instance    string
+
+	// Per-symbol execution locks serializing strategy evaluation, manual orders and closes
+	symbolLocks   map[string]*sync.Mutex
+	symbolLocksMu sync.Mutex
+	entryMu       sync.Mutex // Serializes entry risk checks and buys across symbols
+
+	// Last signs of life of the trading loop and streams, for health checks
+	heartbeats *heartbeats
+
+	// Symbols whose new entries are paused, and consecutive processing errors per symbol
+	pauses       map[string]*SymbolPause
+	symbolErrors map[string]int
+	pauseMu      sync.Mutex
+
+	// Guard pause of the whole strategy and the last resume, guarded by pauseMu
+	strategyPause     *StrategyPause
+	strategyResumedAt time.Time
+
+	// Optional news/sentiment feeds
+	feeds *feeds.Service
+
+	// Optional options venue for implied volatility and skew
+	options exchange.OptionsVenue
+
+	// Append-only log of every order action; nil disables it
+	orderAudit *auditlog.Log
+
+	// Periodic jobs: income sync, symbol refresh, drift checks, reports and snapshots
+	scheduler             *scheduler.Scheduler
+	incomeReconciledUntil time.Time // Positions closed before this were reconciled with the income history
+
+	// Versions of the remotely edited risk limits and strategy parameters in effect
+	riskLimitsVersion uint
+	strategyVersion   uint
+	remoteConfigMu    sync.Mutex
+
+	// Rolling correlation matrix across the traded universe
+	correlations *CorrelationTracker
+
+	// Trading hours and blackout windows for new entries; nil allows entries at any time
+	sessions *sessionSchedule
+
+	// Entry signal filters of the strategy type, empty when signal filters are disabled
+	signalFilters signalFilterChain
+
+	// Candidate strategy run beside the live one on simulated books; nil when disabled
+	shadow *shadowRunner
+
+	// Neutral grid driven by resting orders instead of signals; nil otherwise
+	grid *GridStrategy
+
+	// Market data; klines are kept in a bounded ring per symbol
+	klines            *klineStore
+	fundingRates      map[string]float64
+	markPrices        map[string]float64
+	tickers           map[string]*exchange.Ticker24h
+	fundingTimes      map[string]fundingSchedule
+	positioning       map[string]*positioningSnapshot
+	optionsVolatility map[string]*exchange.OptionsVolatility // By underlying asset
+	dataTimes         map[string]time.Time                   // Last successful kline refresh per symbol
+	startedAt         time.Time
+	marketDataMu      sync.RWMutex
+
+	// Batching writer of price ticks; nil when trading.retention.persist_market_data is off
+	marketDataWriter *marketDataWriter
+
+	// Taker order flow per symbol from the aggregate trade stream, and minute samples
+	// waiting to be persisted
+	orderFlows  map[string]*symbolFlow
+	pendingFlow []*models.OrderFlowSample
+	orderFlowMu sync.Mutex
+
+	// Liquidations within the rolling window per symbol, and symbols whose alarm was raised
+	liquidationEvents map[string][]*exchange.Liquidation
+	liquidationAlarms map[string]bool
+	liquidationMu     sync.Mutex
+
+	// Local order books from the depth stream, used by the slippage guard while fresh
+	orderBooks   map[string]*streamedBook
+	orderBooksMu sync.RWMutex
+
+	// Exchange positions from user data stream re-syncs, applied by the position sync
+	positionSnapshots chan []*exchange.PositionInfo
+
+	// Symbols the exchange is winding down; entries are blocked while listed
+	delistings  map[string]*symbolDelisting
+	delistingMu sync.Mutex
+
+	// Leverage set per symbol by the volatility regime; max_leverage until changed
+	leverages        map[string]int
+	leverageFailures map[string]int // Target of the last failed change per symbol
+	leverageMu       sync.Mutex
+
+	// Daily figures of closed positions, cached from the positions table; days start at
+	// midnight in dayLocation
+	dayLocation *time.Location
+	stats       *dailyStats
+	statsMu     sync.Mutex
+
+	// Latest account margin ratio and the last de-risking close of the margin monitor
+	margin           *MarginStatus
+	marginDeriskedAt time.Time
+	marginMu         sync.Mutex
+
+	// Latest auto-deleveraging quantile per symbol and side, and the last history prune
+	adl       map[string]*ADLStatus
+	adlPruned time.Time
+	adlMu     sync.Mutex
+
+	// Times of the rejected orders fixed and retried within the last hour
+	remediations  []time.Time
+	remediationMu sync.Mutex
 }
 
 // EngineConfig holds the configuration for the trading engine
@@ -53,6 +190,14 @@ type EngineConfig struct {
 	ExchangeClient exchange.Client
 	Config         config.TradingConfig
 	Logger         *logrus.Logger
+	Commentary     *commentary.Generator // Optional; nil disables trade commentary
+	Notifier       notify.Notifier       // Optional; defaults to logging notifications
+	Feeds          *feeds.Service        // Optional; nil disables sentiment data
+	Options        exchange.OptionsVenue // Optional; nil disables options volatility data
+	SpotClient     exchange.SpotClient   // Optional; required for funding arbitrage and spot exposure
+	OrderAudit     *auditlog.Log         // Optional; nil disables the order audit log
+	EventOutbox    bool                  // Queue position events in the outbox for the event webhooks
+	Instance       string                // Bot instance name labelling status and events; empty for a single bot
 }
 
 // Strategy interface for trading strategies
@@ -65,24 +210,114 @@ type Strategy interface {
 
 // Signal represents a trading signal
 type Signal struct {
-	Action       string // BUY, SELL, HOLD
-	Quantity     float64
+	ID           string  // Deterministic per strategy, symbol, action and candle; empty for manual actions
+	Action       string  // BUY, SELL, HOLD; BUY from ShouldSell asks to add to the position
+	Quantity     float64 // Sized by the engine's Sizer; see config trading.sizing
 	Price        float64
 	StopLoss     float64
 	TakeProfit   float64
 	Confidence   float64 // 0.0 to 1.0
 	Reason       string
 	PositionSide string // LONG, SHORT
+
+	// Optional partial exits for entries; the unallocated remainder is the runner
+	TakeProfitLevels    []TakeProfitLevel
+	TrailingStopPercent float64 // Callback rate of the runner's trailing stop (0 = no trailing stop)
+
+	// Optional resting limit buys placed after entry to average down
+	SafetyOrders []SafetyOrder
 }
 
 // MarketData represents current market information
 type MarketData struct {
-	Symbol    string
-	Price     float64
-	Volume    float64
-	Change    float64
-	Timestamp time.Time
-	Klines    []*exchange.KlineData
+	Symbol         string
+	Price          float64 // Last traded price
+	MarkPrice      float64 // Exchange mark price used for liquidation and PnL (0 until known)
+	Volume         float64
+	Change         float64 // 24h price change (0 until the ticker is known)
+	ChangePercent  float64 // 24h price change in percent
+	High24h        float64
+	Low24h         float64
+	QuoteVolume24h float64 // 24h volume in the quote asset
+	FundingRate    float64
+	Timestamp      time.Time
+	Klines         []*exchange.KlineData
+	ATR            float64 // Average True Range of the closed klines over trading.atr_period (0 when too few)
+
+	// Rolling news sentiment in [-1, 1] and the number of items behind it (0 when feeds are disabled)
+	Sentiment        float64
+	SentimentSamples int
+
+	// Positioning data (0 when positioning collection is disabled or not yet sampled)
+	OpenInterest       float64 // Contracts
+	OpenInterestChange float64 // Percent change since the previous sample
+	LongShortRatio     float64 // Global account long/short ratio
+	LongAccount        float64 // Share of accounts net long (0-1)
+
+	// Option market of the base asset as volatility regime features (0 when disabled or the
+	// asset has no listed options); volatilities are annualized percentages
+	ImpliedVolatility float64 // At the money, near trading.options.tenor_days
+	VolatilitySkew    float64 // OTM put minus OTM call implied volatility
+	PutCallRatio      float64 // Put over call open interest
+
+	// Taker order flow over trading.order_flow.window_seconds in the quote asset (0 when disabled)
+	BuyVolume   float64
+	SellVolume  float64
+	VolumeDelta float64 // BuyVolume - SellVolume
+	LargeBuys   int     // Taker buys of at least large_trade_notional
+	LargeSells  int
+
+	// Notional liquidated over trading.liquidations.window_seconds (0 when disabled)
+	LongLiquidations  float64
+	ShortLiquidations float64
+}
+
+// newStrategy creates an uninitialized strategy of the configured type
+func newStrategy(strategyType string) Strategy {
+	switch strategyType {
+	case "simple_moving_average":
+		return NewSMAStrategy()
+	case "rsi":
+		return NewRSIStrategy()
+	case "ai":
+		return NewAIStrategy()
+	case "grid":
+		return NewGridStrategy()
+	case "dca":
+		return NewDCAStrategy()
+	case "breakout":
+		return NewBreakoutStrategy()
+	case "vwap_reversion":
+		return NewVWAPReversionStrategy()
+	case "ichimoku":
+		return NewIchimokuStrategy()
+	case "stochastic":
+		return NewStochasticStrategy()
+	case "external":
+		return NewExternalStrategy()
+	case "plugin":
+		return NewPluginStrategy()
+	case "python":
+		return NewPythonStrategy()
+	default:
+		return NewSMAStrategy() // Default strategy
+	}
+}
+
+// strategyParameters returns the file's strategy parameters with overrides applied on top.
+// Stops follow the trading-level working type unless the strategy overrides it.
+func strategyParameters(cfg config.TradingConfig, overrides map[string]interface{}) map[string]interface{} {
+	params := make(map[string]interface{}, len(cfg.Strategy.Parameters)+len(overrides)+1)
+	for key, value := range cfg.Strategy.Parameters {
+		params[key] = value
+	}
+	for key, value := range overrides {
+		params[key] = value
+	}
+	if _, ok := params["stop_working_type"]; !ok {
+		params["stop_working_type"] = cfg.StopWorkingType
+	}
+	return params
 }
 
 // NewEngine creates a new trading engine
@@ -91,48 +326,208 @@ func NewEngine(cfg *EngineConfig) *Engine {
 
 	repository := database.NewMySQLRepository(cfg.DB)
 
-	// Initialize strategy based on config
-	var strategy Strategy
-	switch cfg.Config.Strategy.Type {
-	case "simple_moving_average":
-		strategy = NewSMAStrategy()
-	case "rsi":
-		strategy = NewRSIStrategy()
-	case "ai":
-		strategy = NewAIStrategy()
-	default:
-		strategy = NewSMAStrategy() // Default strategy
+	// Initialize strategy based on config, with parameters
+	strategy := newStrategy(cfg.Config.Strategy.Type)
+	if err := strategy.Initialize(strategyParameters(cfg.Config, nil)); err != nil {
+		cfg.Logger.Errorf("Failed to initialize strategy: %v", err)
 	}
 
-	// Initialize strategy with parameters
-	if err := strategy.Initialize(cfg.Config.Strategy.Parameters); err != nil {
-		cfg.Logger.Errorf("Failed to initialize strategy: %v", err)
+	// Strategies that persist their own state get the repository
+	if stateful, ok := strategy.(interface{ SetRepository(database.Repository) }); ok {
+		stateful.SetRepository(repository)
+	}
+	// External strategy processes log through the engine
+	if logged, ok := strategy.(interface{ SetLogger(*logrus.Logger) }); ok {
+		logged.SetLogger(cfg.Logger)
+	}
+
+	var grid *GridStrategy
+	if g, ok := strategy.(*GridStrategy); ok && g.Neutral() {
+		grid = g
+	}
+
+	notifier := cfg.Notifier
+	if notifier == nil {
+		notifier = notify.NewLogNotifier(cfg.Logger)
+	}
+
+	// Keep the unwrapped strategy for warmup, state persistence and the journal; the sandbox
+	// does not forward them
+	warmupStrategy, _ := strategy.(WarmupStrategy)
+	indicatorStrategy, _ := strategy.(IndicatorStrategy)
+	snapshotStrategy, _ := strategy.(StatefulStrategy)
+	strategyCloser, _ := strategy.(io.Closer)
+	var statefulStrategy StatefulStrategy
+	if stateful, ok := strategy.(StatefulStrategy); ok && cfg.Config.Strategy.State.Enabled {
+		statefulStrategy = stateful
+	}
+
+	// Bound every strategy evaluation so a slow or broken strategy cannot stall the tick
+	if cfg.Config.Strategy.Sandbox.Enabled {
+		strategy = NewSandboxedStrategy(strategy, cfg.Config.Strategy.Sandbox, notifier, cfg.Logger)
 	}
 
 	// Initialize risk manager
+	quoteExposure := make(map[string]float64, len(cfg.Config.Exposure.MaxQuoteExposure))
+	for asset, limit := range cfg.Config.Exposure.MaxQuoteExposure {
+		// Config keys are lower-cased when loaded
+		quoteExposure[strings.ToUpper(asset)] = limit
+	}
 	riskManager := NewRiskManager(&RiskConfig{
-		MaxPositionSize:   cfg.Config.MaxPositionSize,
-		StopLossPercent:   cfg.Config.StopLossPercent,
-		TakeProfitPercent: cfg.Config.TakeProfitPercent,
-		MaxDailyLoss:      cfg.Config.MaxDailyLoss,
-		MaxLeverage:       cfg.Config.MaxLeverage,
-		RiskPerTrade:      cfg.Config.RiskPerTrade,
+		MaxPositionSize:       cfg.Config.MaxPositionSize,
+		StopLossPercent:       cfg.Config.StopLossPercent,
+		TakeProfitPercent:     cfg.Config.TakeProfitPercent,
+		MaxDailyLoss:          cfg.Config.MaxDailyLoss,
+		MaxLeverage:           cfg.Config.MaxLeverage,
+		RiskPerTrade:          cfg.Config.RiskPerTrade,
+		CorrelationLimit:      cfg.Config.Correlation.Limit,
+		MaxCorrelatedExposure: cfg.Config.Correlation.MaxCorrelatedExposure,
+		MaxNetDelta:           cfg.Config.Exposure.MaxNetDelta,
+		MaxGrossExposure:      cfg.Config.Exposure.MaxGrossExposure,
+		MaxQuoteExposure:      quoteExposure,
+		StopWorkingType:       cfg.Config.StopWorkingType,
+		MaxConsecutiveLosses:  cfg.Config.Cooldown.MaxConsecutiveLosses,
+		LossCooldown:          time.Duration(cfg.Config.Cooldown.CooldownMinutes) * time.Minute,
+		MinReentryInterval:    time.Duration(cfg.Config.Cooldown.MinReentryMinutes) * time.Minute,
 	})
+	dayLocation, err := time.LoadLocation(cfg.Config.DayTimezone)
+	if err != nil {
+		cfg.Logger.Errorf("Failed to load the day timezone, days start at midnight UTC: %v", err)
+		dayLocation = time.UTC
+	}
+	riskManager.SetDayLocation(dayLocation)
+
+	var correlations *CorrelationTracker
+	if cfg.Config.Correlation.Enabled {
+		correlations = NewCorrelationTracker(cfg.Config.Correlation, cfg.Config.Symbols, repository, cfg.Logger)
+	}
+
+	var sessions *sessionSchedule
+	if cfg.Config.Sessions.Enabled {
+		schedule, err := newSessionSchedule(cfg.Config.Sessions)
+		if err != nil {
+			cfg.Logger.Errorf("Failed to load trading sessions, entries are not restricted: %v", err)
+		} else {
+			sessions = schedule
+		}
+	}
+
+	var signalFilters signalFilterChain
+	if cfg.Config.Strategy.EnableSignalFilters {
+		chain, err := newSignalFilterChain(cfg.Config.Strategy.Filters.For(cfg.Config.Strategy.Type), cfg.ExchangeClient)
+		if err != nil {
+			cfg.Logger.Errorf("Failed to load signal filters, signals are not filtered: %v", err)
+		} else {
+			signalFilters = chain
+		}
+	}
+
+	var shadow *shadowRunner
+	if cfg.Config.Shadow.Enabled {
+		runner, err := newShadowRunner(cfg.Config, repository, cfg.Logger)
+		if err != nil {
+			cfg.Logger.Errorf("Failed to start shadow test, only the live strategy runs: %v", err)
+		} else {
+			shadow = runner
+		}
+	}
 
-	return &Engine{
-		config:         cfg.Config,
-		db:             cfg.DB,
-		redis:          cfg.Redis,
-		repository:     repository,
-		exchangeClient: cfg.ExchangeClient,
-		logger:         cfg.Logger,
-		ctx:            ctx,
-		cancel:         cancel,
-		strategy:       strategy,
-		riskManager:    riskManager,
-		marketData:     make(map[string]*exchange.KlineData),
-		isRunning:      false,
+	var volTarget *VolTargeter
+	if cfg.Config.VolTarget.Enabled {
+		volTarget = NewVolTargeter(cfg.Config.VolTarget, repository, cfg.Logger)
+	}
+	var drawdownScaler *DrawdownScaler
+	if cfg.Config.DrawdownScaling.Enabled {
+		drawdownScaler = NewDrawdownScaler(cfg.Config.DrawdownScaling, repository, cfg.Logger)
+	}
+	var profitLock *ProfitLock
+	if cfg.Config.ProfitLock.Enabled {
+		lockConfig := cfg.Config.ProfitLock
+		if lockConfig.Timezone == "" {
+			lockConfig.Timezone = dayLocation.String()
+		}
+		lock, err := NewProfitLock(lockConfig, time.Now())
+		if err != nil {
+			cfg.Logger.Errorf("Failed to load the daily profit lock, entries are not locked: %v", err)
+		} else {
+			profitLock = lock
+		}
 	}
+
+	contracts := newContractSpecs()
+
+	var alerter *pnlAlerter
+	if cfg.Config.PnLAlerts.Enabled {
+		alerter = newPnLAlerter(cfg.Config.PnLAlerts, repository, notifier, contracts, cfg.Logger)
+	}
+
+	var writer *marketDataWriter
+	if cfg.Config.Retention.PersistMarketData {
+		writer = newMarketDataWriter(cfg.Config.Retention, repository, cfg.Logger)
+	}
+
+	engine := &Engine{
+		config:            cfg.Config,
+		db:                cfg.DB,
+		redis:             cfg.Redis,
+		repository:        repository,
+		exchangeClient:    cfg.ExchangeClient,
+		spotClient:        cfg.SpotClient,
+		logger:            cfg.Logger,
+		ctx:               ctx,
+		cancel:            cancel,
+		strategy:          strategy,
+		warmupStrategy:    warmupStrategy,
+		statefulStrategy:  statefulStrategy,
+		indicatorStrategy: indicatorStrategy,
+		snapshotStrategy:  snapshotStrategy,
+		strategyCloser:    strategyCloser,
+		riskManager:       riskManager,
+		sizer:             NewSizer(cfg.Config.Sizing.For(cfg.Config.Strategy.Type), strategy.Name(), riskManager, cfg.ExchangeClient, repository, volTarget, drawdownScaler, profitLock, contracts),
+		contracts:         contracts,
+		volTarget:         volTarget,
+		drawdownScaler:    drawdownScaler,
+		profitLock:        profitLock,
+		dayLocation:       dayLocation,
+		commentary:        cfg.Commentary,
+		notifier:          notifier,
+		pnlAlerter:        alerter,
+		events:            events.NewBus(),
+		eventOutbox:       cfg.EventOutbox,
+		instance:          cfg.Instance,
+		symbolLocks:       make(map[string]*sync.Mutex),
+		heartbeats:        newHeartbeats(),
+		pauses:            make(map[string]*SymbolPause),
+		symbolErrors:      make(map[string]int),
+		feeds:             cfg.Feeds,
+		options:           cfg.Options,
+		orderAudit:        cfg.OrderAudit,
+		scheduler:         scheduler.New(time.Duration(cfg.Config.Scheduler.JitterSeconds)*time.Second, cfg.Logger),
+		correlations:      correlations,
+		sessions:          sessions,
+		signalFilters:     signalFilters,
+		shadow:            shadow,
+		grid:              grid,
+		marketDataWriter:  writer,
+		fundingRates:      make(map[string]float64),
+		markPrices:        make(map[string]float64),
+		tickers:           make(map[string]*exchange.Ticker24h),
+		fundingTimes:      make(map[string]fundingSchedule),
+		dataTimes:         make(map[string]time.Time),
+		orderFlows:        make(map[string]*symbolFlow),
+		liquidationEvents: make(map[string][]*exchange.Liquidation),
+		liquidationAlarms: make(map[string]bool),
+		orderBooks:        make(map[string]*streamedBook),
+		positionSnapshots: make(chan []*exchange.PositionInfo, 1),
+		delistings:        make(map[string]*symbolDelisting),
+		leverages:         make(map[string]int),
+		leverageFailures:  make(map[string]int),
+		positioning:       make(map[string]*positioningSnapshot),
+		optionsVolatility: make(map[string]*exchange.OptionsVolatility),
+		isRunning:         false,
+	}
+	engine.klines = newKlineStore(engine.klineWindow())
+	return engine
 }
 
 // Start starts the trading engine
@@ -145,24 +540,174 @@ func (e *Engine) Start(ctx context.Context) error {
 	}
 
 	e.isRunning = true
+	e.startedAt = time.Now()
 	e.logger.Info("Starting trading engine...")
 
+	// Risk limits and strategy parameters edited through the API take precedence over the file
+	if e.config.RemoteConfig.Enabled {
+		if err := e.loadRemoteConfig(ctx); err != nil {
+			return fmt.Errorf("failed to load remote config: %w", err)
+		}
+	}
+
 	// Initialize symbols and leverage
 	if err := e.initializeSymbols(ctx); err != nil {
 		return fmt.Errorf("failed to initialize symbols: %w", err)
 	}
 
-	// Start market data collection
+	// Restore loss cooldowns so a restart does not clear them
+	e.loadCooldowns()
+
+	// Keep a strategy paused by the performance guard paused until an operator resumes it
+	e.loadStrategyPause()
+	e.loadProfitLock()
+	e.refreshDailyLoss()
+
+	// Fund configured strategy wallets that have no transfers yet
+	if e.config.Wallets.Enabled {
+		if err := e.seedWallets(); err != nil {
+			return fmt.Errorf("failed to seed strategy wallets: %w", err)
+		}
+	}
+
+	// Load history before the first decision so indicators are warm
+	if e.config.Warmup.Enabled {
+		e.warmup(ctx)
+	}
+
+	// Restore strategy state saved before the restart; it replaces the warmup history
+	if e.statefulStrategy != nil {
+		e.loadStrategyState()
+		go e.persistStrategyState(ctx)
+	}
+
+	// Alert when exchange requests keep failing; entries pause while the breaker is open
+	e.watchCircuitBreaker(ctx)
+
+	// Reconcile positions and orders with the exchange before managing them
+	if e.config.Recovery.Enabled && !e.config.EnablePaperTrading {
+		e.runRecovery(ctx)
+	}
+
+	// Start news/sentiment feeds; trading continues without them if they fail
+	if e.feeds != nil {
+		if err := e.feeds.Start(ctx); err != nil {
+			e.logger.Warnf("Failed to start sentiment feeds: %v", err)
+		}
+	}
+
+	// Keep DB positions in line with the exchange while running; every (re)connect of the
+	// user data stream reconciles positions and open orders with a fresh snapshot
+	if e.config.PositionSync.Enabled && !e.config.EnablePaperTrading {
+		go e.runPositionSync(ctx)
+		if err := e.exchangeClient.StartUserDataStream(ctx, &userDataHandler{engine: e, ctx: ctx}); err != nil {
+			e.logger.Warnf("Failed to start user data stream: %v", err)
+		}
+	}
+
+	// Start market data collection and alert when a symbol's data stops updating. The tick
+	// writer runs until Stop so it can write what is still queued.
+	if e.marketDataWriter != nil {
+		go e.marketDataWriter.run(e.ctx)
+	}
 	go e.collectMarketData(ctx)
+	if e.config.StaleData.Enabled {
+		go e.monitorStaleData(ctx)
+	}
+
+	// Start open interest / long-short ratio collection
+	if e.config.Positioning.Enabled {
+		go e.collectPositioningData(ctx)
+	}
+
+	// Start implied volatility and skew collection from the options venue
+	if e.options != nil {
+		go e.collectOptionsData(ctx)
+	}
 
-	// Start trading loop
-	go e.tradingLoop(ctx)
+	// Start order flow metrics from the aggregate trade stream
+	if e.config.OrderFlow.Enabled {
+		if err := e.exchangeClient.StartAggTradeStream(ctx, e.config.Symbols, &aggTradeHandler{engine: e}); err != nil {
+			e.logger.Warnf("Failed to start aggregate trade stream: %v", err)
+		} else if e.config.OrderFlow.Persist {
+			go e.persistOrderFlow(ctx)
+		}
+	}
+
+	// Keep local order books for the slippage guard instead of fetching one per entry
+	if e.config.Slippage.Enabled {
+		if err := e.exchangeClient.StartDepthStream(ctx, e.config.Symbols, &depthHandler{engine: e}); err != nil {
+			e.logger.Warnf("Failed to start depth stream: %v", err)
+		}
+	}
+
+	// Start liquidation feed monitoring
+	if e.config.Liquidations.Enabled {
+		if err := e.exchangeClient.StartLiquidationStream(ctx, e.config.Symbols, &liquidationHandler{engine: e, ctx: ctx}); err != nil {
+			e.logger.Warnf("Failed to start liquidation stream: %v", err)
+		}
+	}
+
+	// Start the exchange's cancel-all countdown so orders do not outlive the process
+	if e.config.AutoCancel.Enabled {
+		go e.runAutoCancel(ctx)
+	}
+
+	// Start leverage adjustment to each symbol's volatility regime
+	if e.config.LeverageRegime.Enabled {
+		go e.monitorVolatilityRegime(ctx)
+	}
 
-	// Start risk monitoring
-	go e.monitorRisk(ctx)
+	// Start portfolio volatility targeting
+	if e.volTarget != nil {
+		go e.volTarget.run(ctx)
+	}
+
+	// Start correlation matrix refresh
+	if e.correlations != nil {
+		go e.correlations.run(ctx)
+	}
+
+	// Start delta-neutral funding arbitrage on its own symbols
+	if e.config.FundingArbitrage.Enabled {
+		if e.spotClient == nil {
+			e.logger.Warn("Funding arbitrage is enabled but no spot client is configured")
+		} else {
+			go e.runFundingArbitrage(ctx)
+		}
+	}
+
+	// Start trading loop, either on a fixed interval or once per closed candle
+	e.expectHeartbeats()
+	if e.config.EvaluationMode == config.EvaluationModeCandleClose {
+		go e.candleCloseLoop(ctx)
+	} else {
+		go e.tradingLoop(ctx)
+	}
+
+	// Start periodic jobs: income sync and reconciliation, symbol refresh with delisting
+	// protection, settings drift detection, the daily report, risk metrics and account snapshots
+	if err := e.registerJobs(); err != nil {
+		return fmt.Errorf("failed to register scheduled jobs: %w", err)
+	}
+	e.scheduler.Start(ctx)
 
-	// Start account monitoring
-	go e.monitorAccount(ctx)
+	// Start the mark price stream; unrealized PnL alerts depend on it, everything else
+	// falls back to the mark price polled with market data
+	if e.pnlAlerter != nil {
+		if err := e.pnlAlerter.refresh(); err != nil {
+			e.logger.Warnf("Failed to load positions for PnL alerts: %v", err)
+		}
+	}
+	if err := e.exchangeClient.StartMarkPriceStream(ctx, e.config.Symbols, &markPriceHandler{engine: e}); err != nil {
+		if e.pnlAlerter != nil {
+			return fmt.Errorf("failed to start mark price stream: %w", err)
+		}
+		e.logger.Warnf("Failed to start mark price stream: %v", err)
+	}
+	if e.pnlAlerter != nil {
+		go e.pnlAlerter.run(ctx)
+	}
 
 	e.logger.Info("Trading engine started successfully")
 	return nil
@@ -181,9 +726,24 @@ func (e *Engine) Stop(ctx context.Context) error {
 
 	// Cancel context to stop all goroutines
 	e.cancel()
+	if e.marketDataWriter != nil {
+		e.marketDataWriter.wait(ctx)
+	}
+
+	if e.statefulStrategy != nil {
+		e.saveStrategyState()
+	}
+	if e.strategyCloser != nil {
+		if err := e.strategyCloser.Close(); err != nil {
+			e.logger.Warnf("Failed to close strategy: %v", err)
+		}
+	}
+	if e.shadow != nil {
+		e.shadow.Close()
+	}
 
 	// Close all positions if needed (optional)
-	if err := e.closeAllPositions(ctx); err != nil {
+	if err := e.closeAllPositions(auditlog.WithSource(ctx, auditlog.TriggerRisk, "shutdown")); err != nil {
 		e.logger.Errorf("Error closing positions during shutdown: %v", err)
 	}
 
@@ -194,18 +754,22 @@ func (e *Engine) Stop(ctx context.Context) error {
 
 // initializeSymbols sets up trading symbols with leverage and margin type
 func (e *Engine) initializeSymbols(ctx context.Context) error {
+	if err := e.contracts.load(ctx, e.exchangeClient, e.config.Symbols); err != nil {
+		e.logger.Warnf("Failed to load contract specifications, assuming linear contracts: %v", err)
+	}
+
 	for _, symbol := range e.config.Symbols {
 		// Set leverage
-		if err := e.exchangeClient.SetLeverage(ctx, symbol, e.config.MaxLeverage); err != nil {
+		if err := e.exchangeClient.SetLeverage(ctx, symbol, e.riskManager.Limits().MaxLeverage); err != nil {
 			e.logger.Warnf("Failed to set leverage for %s: %v", symbol, err)
 		}
 
-		// Set margin type to CROSSED (default for most strategies)
-		if err := e.exchangeClient.ChangeMarginType(ctx, symbol, "CROSSED"); err != nil {
+		// Set margin type (CROSSED by default)
+		if err := e.exchangeClient.ChangeMarginType(ctx, symbol, e.config.MarginType); err != nil {
 			e.logger.Warnf("Failed to set margin type for %s: %v", symbol, err)
 		}
 
-		e.logger.Infof("Initialized symbol %s with leverage %d", symbol, e.config.MaxLeverage)
+		e.logger.Infof("Initialized symbol %s with leverage %d", symbol, e.riskManager.Limits().MaxLeverage)
 	}
 
 	return nil
@@ -226,30 +790,23 @@ func (e *Engine) collectMarketData(ctx context.Context) {
 					e.logger.Errorf("Failed to update market data for %s: %v", symbol, err)
 				}
 			}
+
+			if err := e.updatePositionPrices(); err != nil {
+				e.logger.Errorf("Failed to update position prices: %v", err)
+			}
 		}
 	}
 }
 
 // updateMarketData updates market data for a symbol
 func (e *Engine) updateMarketData(ctx context.Context, symbol string) error {
-	// Get current price
-	price, err := e.exchangeClient.GetSymbolPrice(ctx, symbol)
+	price, klines, err := e.refreshMarketData(ctx, symbol)
 	if err != nil {
-		return fmt.Errorf("failed to get price for %s: %w", symbol, err)
+		return err
 	}
 
-	// Get kline data for strategy analysis
-	klines, err := e.exchangeClient.GetKlines(ctx, symbol, "1m", 100)
-	if err != nil {
-		return fmt.Errorf("failed to get klines for %s: %w", symbol, err)
-	}
-
-	if len(klines) > 0 {
-		e.marketDataMu.Lock()
-		e.marketData[symbol] = klines[len(klines)-1] // Store latest kline
-		e.marketDataMu.Unlock()
-
-		// Save to database
+	if len(klines) > 0 && e.marketDataWriter != nil {
+		// Queue for the database writer
 		marketData := &models.MarketData{
 			Symbol:    symbol,
 			Price:     price,
@@ -260,15 +817,65 @@ func (e *Engine) updateMarketData(ctx context.Context, symbol string) error {
 			Close:     klines[len(klines)-1].Close,
 			Timestamp: time.Now().Unix(),
 		}
-
-		if err := e.repository.SaveMarketData(marketData); err != nil {
-			e.logger.Errorf("Failed to save market data: %v", err)
+		e.marketDataMu.RLock()
+		if ticker := e.tickers[symbol]; ticker != nil {
+			marketData.Change = ticker.PriceChange
+			marketData.ChangePercent = ticker.PriceChangePercent
 		}
+		e.marketDataMu.RUnlock()
+
+		e.marketDataWriter.enqueue(ctx, marketData)
 	}
 
 	return nil
 }
 
+// refreshMarketData fetches the latest price, klines and funding rate for a symbol into
+// the in-memory cache without persisting anything
+func (e *Engine) refreshMarketData(ctx context.Context, symbol string) (float64, []*exchange.KlineData, error) {
+	// Get current price
+	price, err := e.exchangeClient.GetSymbolPrice(ctx, symbol)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get price for %s: %w", symbol, err)
+	}
+
+	// Get kline data for strategy analysis
+	klines, err := e.exchangeClient.GetKlines(ctx, symbol, e.config.KlineInterval, e.klineLimit())
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get klines for %s: %w", symbol, err)
+	}
+
+	// Funding rate, mark price and 24h statistics are optional; a failure here should not
+	// block price updates
+	premiumIndex, err := e.exchangeClient.GetPremiumIndex(ctx, symbol)
+	if err != nil {
+		e.logger.Debugf("Failed to get funding rate for %s: %v", symbol, err)
+	}
+	ticker, err := e.exchangeClient.GetTicker24h(ctx, symbol)
+	if err != nil {
+		e.logger.Debugf("Failed to get 24h ticker for %s: %v", symbol, err)
+	}
+
+	if len(klines) > 0 {
+		e.klines.update(symbol, klines)
+		e.marketDataMu.Lock()
+		e.dataTimes[symbol] = time.Now()
+		if premiumIndex != nil {
+			e.fundingRates[symbol] = premiumIndex.FundingRate
+			if premiumIndex.MarkPrice > 0 {
+				e.markPrices[symbol] = premiumIndex.MarkPrice
+			}
+			e.recordFundingTime(symbol, premiumIndex.NextFundingTime)
+		}
+		if ticker != nil {
+			e.tickers[symbol] = ticker
+		}
+		e.marketDataMu.Unlock()
+	}
+
+	return price, klines, nil
+}
+
 // tradingLoop is the main trading logic loop
 func (e *Engine) tradingLoop(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(e.config.TradingInterval) * time.Second)
@@ -279,6 +886,7 @@ func (e *Engine) tradingLoop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			e.heartbeats.beat(HealthTradingLoop)
 			if err := e.processTradingSignals(ctx); err != nil {
 				e.logger.Errorf("Error processing trading signals: %v", err)
 			}
@@ -294,29 +902,55 @@ func (e *Engine) processTradingSignals(ctx context.Context) error {
 		return nil
 	}
 
-	for _, symbol := range e.config.Symbols {
-		if err := e.processSymbolSignals(ctx, symbol); err != nil {
-			e.logger.Errorf("Error processing signals for %s: %v", symbol, err)
-		}
-	}
-
-	return nil
+	return e.processSymbols(ctx, e.config.Symbols, e.processSymbolSignals)
 }
 
 // processSymbolSignals processes trading signals for a specific symbol
 func (e *Engine) processSymbolSignals(ctx context.Context, symbol string) error {
+	ctx = auditlog.WithSource(ctx, auditlog.TriggerStrategy, e.strategy.Name())
+
 	// Get current market data
 	marketData, err := e.getMarketData(symbol)
 	if err != nil {
 		return fmt.Errorf("failed to get market data for %s: %w", symbol, err)
 	}
 
+	if e.grid != nil {
+		if reason := e.staleFilter(symbol); reason != "" {
+			return fmt.Errorf("grid not synced: %s", reason)
+		}
+		if reason := e.delistingFilter(symbol); reason != "" {
+			return fmt.Errorf("grid not synced: %s", reason)
+		}
+		return e.syncNeutralGrid(ctx, symbol, marketData.Price)
+	}
+
+	// The shadow test sees the same data once the live decisions are done and the symbol is
+	// unlocked
+	if e.shadow != nil {
+		defer e.shadow.evaluate(ctx, symbol, marketData)
+	}
+	defer e.lockSymbol(symbol)()
+
+	// Manual buys placed while flat open a position once they fill
+	if err := e.reconcileManualOrders(ctx, symbol); err != nil {
+		e.logger.Errorf("Failed to reconcile manual orders for %s: %v", symbol, err)
+	}
+
 	// Get current position
 	position, err := e.repository.GetPosition(symbol, "LONG")
 	if err != nil && err != gorm.ErrRecordNotFound {
 		return fmt.Errorf("failed to get position for %s: %w", symbol, err)
 	}
 
+	// Apply take-profit and safety order fills before asking the strategy about the position
+	if position != nil && position.Status == "OPEN" {
+		position, err = e.reconcileChildOrders(ctx, position)
+		if err != nil {
+			e.logger.Errorf("Failed to reconcile child orders for %s: %v", symbol, err)
+		}
+	}
+
 	// Check for sell signals if we have a position
 	if position != nil && position.Status == "OPEN" {
 		sellSignal, err := e.strategy.ShouldSell(ctx, symbol, marketData, position)
@@ -325,32 +959,41 @@ func (e *Engine) processSymbolSignals(ctx context.Context, symbol string) error
 		}
 
 		if sellSignal != nil && sellSignal.Action == "SELL" {
+			sellSignal.ID = e.signalID(symbol, "SELL", marketData)
 			if err := e.executeSellOrder(ctx, symbol, sellSignal, position); err != nil {
 				e.logger.Errorf("Failed to execute sell order: %v", err)
 			}
 		}
+
+		// A BUY while holding the position asks to scale in
+		if sellSignal != nil && sellSignal.Action == "BUY" && e.config.Pyramiding.Enabled && !e.isPaused(symbol) &&
+			e.signalPassesFilters(ctx, marketData, sellSignal) {
+			if err := e.sizer.Size(ctx, sellSignal, marketData); err != nil {
+				return fmt.Errorf("failed to size add-on: %w", err)
+			}
+			addSignal := e.addOnSignal(position, sellSignal)
+			addSignal.ID = e.signalID(symbol, "ADD", marketData)
+			if reason := e.pyramidReason(position, marketData.Price, addSignal.Quantity); reason != "" {
+				e.logger.Debugf("Add-on for %s skipped: %s", symbol, reason)
+			} else if err := e.enterPosition(ctx, symbol, marketData, addSignal, position); err != nil {
+				e.logger.Errorf("Failed to execute add-on order: %v", err)
+			}
+		}
 	}
 
-	// Check for buy signals if we don't have a position
-	if position == nil || position.Status != "OPEN" {
+	// Check for buy signals if we don't have a position; paused symbols take no new entries
+	if (position == nil || position.Status != "OPEN") && !e.isPaused(symbol) {
 		buySignal, err := e.strategy.ShouldBuy(ctx, symbol, marketData)
 		if err != nil {
 			return fmt.Errorf("failed to get buy signal: %w", err)
 		}
 
-		if buySignal != nil && buySignal.Action == "BUY" {
-			// Validate with risk manager
-			if !e.riskManager.ValidateOrder(ctx, &OrderInfo{
-				Symbol:   symbol,
-				Side:     "BUY",
-				Quantity: buySignal.Quantity,
-				Price:    buySignal.Price,
-			}) {
-				e.logger.Warnf("Order rejected by risk manager for %s", symbol)
-				return nil
+		if buySignal != nil && buySignal.Action == "BUY" && e.signalPassesFilters(ctx, marketData, buySignal) {
+			if err := e.sizer.Size(ctx, buySignal, marketData); err != nil {
+				return fmt.Errorf("failed to size entry: %w", err)
 			}
-
-			if err := e.executeBuyOrder(ctx, symbol, buySignal); err != nil {
+			buySignal.ID = e.signalID(symbol, "BUY", marketData)
+			if err := e.enterPosition(ctx, symbol, marketData, buySignal, nil); err != nil {
 				e.logger.Errorf("Failed to execute buy order: %v", err)
 			}
 		}
@@ -359,40 +1002,220 @@ func (e *Engine) processSymbolSignals(ctx context.Context, symbol string) error
 	return nil
 }
 
+// enterPosition opens or adds to a position when the entry checks pass. Entries of all
+// symbols are serialized so that portfolio-wide risk checks see each other's fills.
+func (e *Engine) enterPosition(ctx context.Context, symbol string, marketData *MarketData, signal *Signal, existing *models.Position) error {
+	e.entryMu.Lock()
+	defer e.entryMu.Unlock()
+
+	if !e.entryAllowed(ctx, symbol, marketData, signal) {
+		return nil
+	}
+	return e.executeBuyOrder(ctx, symbol, signal, existing)
+}
+
+// entryBlock is why a new entry or add-on is not allowed
+type entryBlock struct {
+	reason   string
+	routine  bool       // Expected skips (session, profit lock, sentiment) are logged at info level
+	rejected *OrderInfo // The order the risk checks rejected
+}
+
+// entryAllowed runs the session, sentiment and risk checks for a new entry or add-on and
+// logs and publishes why one is skipped
+func (e *Engine) entryAllowed(ctx context.Context, symbol string, marketData *MarketData, signal *Signal) bool {
+	block := e.checkEntry(ctx, symbol, marketData, signal)
+	switch {
+	case block == nil:
+		return true
+	case block.rejected != nil:
+		e.logger.Warnf("Buy signal for %s rejected: %s", symbol, block.reason)
+		e.publishEvent(events.TypeRisk, "rejected", symbol, block.rejected)
+	case block.routine:
+		e.logger.Infof("Buy signal for %s skipped: %s", symbol, block.reason)
+	default:
+		e.logger.Warnf("Buy signal for %s skipped: %s", symbol, block.reason)
+	}
+	return false
+}
+
+// checkEntry returns why a new entry or add-on is not allowed, or nil when it is. It logs
+// and publishes nothing itself, so plan mode runs exactly the checks of the trading loop.
+func (e *Engine) checkEntry(ctx context.Context, symbol string, marketData *MarketData, signal *Signal) *entryBlock {
+	for _, check := range []struct {
+		filter  func() string
+		routine bool
+	}{
+		{e.circuitFilter, false},
+		{func() string { return e.staleFilter(symbol) }, false},
+		{func() string { return e.delistingFilter(symbol) }, false},
+		{func() string { return e.liquidationFilter(symbol) }, false},
+		{func() string { return e.sessionFilter(symbol, time.Now()) }, true},
+		{e.profitLockFilter, true},
+		{e.marginFilter, false},
+		{func() string { return e.sentimentFilter(marketData) }, true},
+	} {
+		if reason := check.filter(); reason != "" {
+			return &entryBlock{reason: reason, routine: check.routine}
+		}
+	}
+
+	// Validate with risk manager
+	orderInfo := e.orderInfo(symbol, "BUY", signal.Quantity, signal.Price)
+	if !e.riskManager.ValidateOrder(ctx, orderInfo) || !e.validateExposure(orderInfo) {
+		return &entryBlock{reason: "order rejected by risk manager", rejected: orderInfo}
+	}
+
+	// The strategy may only commit capital of its own wallet
+	if reason := e.walletFilter(e.strategy.Name(), orderInfo); reason != "" {
+		return &entryBlock{reason: reason, rejected: orderInfo}
+	}
+
+	return nil
+}
+
 // getMarketData gets market data for analysis
 func (e *Engine) getMarketData(symbol string) (*MarketData, error) {
+	klines := e.klines.window(symbol)
 	e.marketDataMu.RLock()
-	kline, exists := e.marketData[symbol]
+	fundingRate := e.fundingRates[symbol]
+	markPrice := e.markPrices[symbol]
+	ticker := e.tickers[symbol]
+	positioning := e.positioning[symbol]
+	optionsVol := e.optionsVolatility[baseAsset(symbol)]
 	e.marketDataMu.RUnlock()
 
-	if !exists {
+	if len(klines) == 0 {
 		return nil, fmt.Errorf("no market data available for %s", symbol)
 	}
 
-	return &MarketData{
-		Symbol:    symbol,
-		Price:     kline.Close,
-		Volume:    kline.Volume,
-		Timestamp: time.Unix(kline.CloseTime/1000, 0),
-		Klines:    []*exchange.KlineData{kline},
-	}, nil
+	kline := klines[len(klines)-1]
+	data := &MarketData{
+		Symbol:      symbol,
+		Price:       kline.Close,
+		MarkPrice:   markPrice,
+		Volume:      kline.Volume,
+		FundingRate: fundingRate,
+		Timestamp:   time.Unix(kline.CloseTime/1000, 0),
+		Klines:      klines,
+		ATR:         klinesATR(closedKlines(klines), e.config.ATRPeriod),
+	}
+
+	if ticker != nil {
+		data.Change = ticker.PriceChange
+		data.ChangePercent = ticker.PriceChangePercent
+		data.High24h = ticker.HighPrice
+		data.Low24h = ticker.LowPrice
+		data.QuoteVolume24h = ticker.QuoteVolume
+	}
+
+	if e.feeds != nil {
+		data.Sentiment, data.SentimentSamples = e.feeds.Sentiment(symbol)
+	}
+
+	if positioning != nil {
+		data.OpenInterest = positioning.openInterest
+		data.OpenInterestChange = positioning.openInterestChange
+		data.LongShortRatio = positioning.longShortRatio
+		data.LongAccount = positioning.longAccount
+	}
+
+	if optionsVol != nil {
+		data.ImpliedVolatility = optionsVol.ATMVolatility
+		data.VolatilitySkew = optionsVol.Skew
+		data.PutCallRatio = optionsVol.PutCallRatio
+	}
+
+	if flow := e.orderFlow(symbol, time.Now()); flow != nil {
+		data.BuyVolume = flow.buyVolume
+		data.SellVolume = flow.sellVolume
+		data.VolumeDelta = flow.buyVolume - flow.sellVolume
+		data.LargeBuys = flow.largeBuys
+		data.LargeSells = flow.largeSells
+	}
+
+	if e.config.Liquidations.Enabled {
+		liquidations := e.liquidationVolume(symbol, time.Now())
+		data.LongLiquidations = liquidations.longs
+		data.ShortLiquidations = liquidations.shorts
+	}
+
+	return data, nil
 }
 
-// executeBuyOrder executes a buy order
-func (e *Engine) executeBuyOrder(ctx context.Context, symbol string, signal *Signal) error {
-	e.logger.Infof("Executing BUY order for %s: quantity=%.6f, price=%.6f",
-		symbol, signal.Quantity, signal.Price)
+// signalPassesFilters runs an entry signal through the strategy's signal filters. Exits are
+// never filtered.
+func (e *Engine) signalPassesFilters(ctx context.Context, data *MarketData, signal *Signal) bool {
+	filter, reason := e.signalFilters.check(ctx, data, signal, time.Now())
+	if filter == "" {
+		return true
+	}
+	e.logger.Infof("Buy signal for %s filtered by %s: %s", data.Symbol, filter, reason)
+	return false
+}
+
+// sentimentFilter returns a reason when news sentiment vetoes a new long entry
+func (e *Engine) sentimentFilter(data *MarketData) string {
+	if e.feeds == nil || !e.config.Strategy.EnableSignalFilters {
+		return ""
+	}
+
+	if blocked, score := e.feeds.BuyBlocked(data.Symbol); blocked {
+		return fmt.Sprintf("news sentiment %.2f below threshold", score)
+	}
+	return ""
+}
 
-	orderRequest := &exchange.OrderRequest{
+// buildBuyOrderRequest builds the exchange order for a buy signal
+func (e *Engine) buildBuyOrderRequest(symbol string, signal *Signal) *exchange.OrderRequest {
+	clientOrderID := signal.ID
+	if clientOrderID == "" {
+		clientOrderID = fmt.Sprintf("buy_%s_%d", symbol, time.Now().Unix())
+	}
+	return &exchange.OrderRequest{
 		Symbol:           symbol,
 		Side:             "BUY",
 		Type:             "MARKET",
 		Quantity:         signal.Quantity,
 		PositionSide:     "BOTH",
-		NewClientOrderID: fmt.Sprintf("buy_%s_%d", symbol, time.Now().Unix()),
+		NewClientOrderID: clientOrderID,
+	}
+}
+
+// buildSellOrderRequest builds the exchange order that closes a position
+func (e *Engine) buildSellOrderRequest(symbol string, signal *Signal, position *models.Position) *exchange.OrderRequest {
+	clientOrderID := signal.ID
+	if clientOrderID == "" {
+		clientOrderID = fmt.Sprintf("sell_%s_%d", symbol, time.Now().Unix())
+	}
+	return &exchange.OrderRequest{
+		Symbol:           symbol,
+		Side:             "SELL",
+		Type:             "MARKET",
+		Quantity:         position.Size,
+		PositionSide:     "BOTH",
+		NewClientOrderID: clientOrderID,
+		// A stored size larger than the exchange position must never flip it short
+		ReduceOnly: true,
+	}
+}
+
+// executeBuyOrder executes a buy order, opening a new position or adding to existing
+func (e *Engine) executeBuyOrder(ctx context.Context, symbol string, signal *Signal, existing *models.Position) error {
+	if e.duplicateSignal(signal) {
+		return nil
+	}
+
+	e.logger.Infof("Executing BUY order for %s: quantity=%.6f, price=%.6f",
+		symbol, signal.Quantity, signal.Price)
+
+	orderRequest, err := e.guardSlippage(ctx, e.buildBuyOrderRequest(symbol, signal), signal.Price)
+	if err != nil {
+		return fmt.Errorf("buy order rejected by slippage guard: %w", err)
 	}
 
-	response, err := e.exchangeClient.PlaceOrder(ctx, orderRequest)
+	submittedAt := time.Now()
+	response, err := e.placeOrder(ctx, orderRequest)
 	if err != nil {
 		return fmt.Errorf("failed to place buy order: %w", err)
 	}
@@ -400,12 +1223,17 @@ func (e *Engine) executeBuyOrder(ctx context.Context, symbol string, signal *Sig
 	// Save order to database
 	order := &models.Order{
 		ExchangeOrderID: fmt.Sprintf("%d", response.OrderID),
+		ClientOrderID:   response.ClientOrderID,
 		Symbol:          response.Symbol,
 		Side:            response.Side,
 		Type:            response.Type,
 		Status:          response.Status,
 		Quantity:        response.OrigQty,
 		Price:           response.Price,
+		ExpectedPrice:   signal.Price,
+		SubmittedPrice:  orderRequest.Price,
+		AvgFillPrice:    response.AvgPrice,
+		SubmittedAt:     &submittedAt,
 		ExecutedQty:     response.ExecutedQty,
 		CumulativeQuote: response.CumQuote,
 		TimeInForce:     response.TimeInForce,
@@ -416,29 +1244,69 @@ func (e *Engine) executeBuyOrder(ctx context.Context, symbol string, signal *Sig
 		Notes:           signal.Reason,
 	}
 
-	if err := e.repository.CreateOrder(order); err != nil {
-		e.logger.Errorf("Failed to save order to database: %v", err)
-	}
-
-	// Create position if order is filled
-	if response.Status == "FILLED" {
-		position := &models.Position{
+	// Create position if order is filled; an IOC limit from the slippage guard may fill partially
+	filled := response.Status == "FILLED" || (orderRequest.TimeInForce == "IOC" && response.ExecutedQty > 0)
+	var position *models.Position
+	if filled && existing != nil {
+		e.mergeAddOn(existing, response)
+	} else if filled {
+		position = &models.Position{
 			Symbol:       symbol,
 			PositionSide: "LONG",
 			Size:         response.ExecutedQty,
 			EntryPrice:   response.AvgPrice,
-			Leverage:     e.config.MaxLeverage,
+			LastAddPrice: response.AvgPrice,
+			Leverage:     e.symbolLeverage(symbol),
 			Status:       "OPEN",
 			OpenTime:     time.Now(),
 			Strategy:     e.strategy.Name(),
 		}
+		position.TakeProfitPlan = e.newTakeProfitLadder(signal).encode()
+	}
 
-		if err := e.repository.CreatePosition(position); err != nil {
-			e.logger.Errorf("Failed to save position to database: %v", err)
+	// The order and the position it opened or grew are saved together, so a failed write
+	// cannot leave a filled order without its position
+	saveErr := e.repository.WithTx(func(tx database.Repository) error {
+		if err := tx.CreateOrder(order); err != nil {
+			return fmt.Errorf("failed to save order: %w", err)
+		}
+		if position != nil {
+			if err := tx.CreatePosition(position); err != nil {
+				return fmt.Errorf("failed to save position: %w", err)
+			}
+			return e.enqueueEvent(tx, events.TypePosition, "opened", symbol, position)
+		} else if filled {
+			if err := tx.UpdatePosition(existing); err != nil {
+				return fmt.Errorf("failed to update position after add-on: %w", err)
+			}
+			return e.enqueueEvent(tx, events.TypePosition, "increased", symbol, existing)
 		}
+		return nil
+	})
+	if saveErr != nil {
+		e.logger.Errorf("Failed to save buy order for %s to database: %v", symbol, saveErr)
+		order.ID = 0 // Rolled back; fills are stored without an order
+	}
+	e.recordFills(ctx, order, response, signal.Price)
+	e.publishEvent(events.TypeOrder, "placed", symbol, order)
+
+	if filled && existing != nil {
+		if saveErr == nil {
+			e.addToPosition(ctx, existing, response)
+		}
+		e.journalTrade(journalAdd, existing, signal, response.AvgPrice, response.ExecutedQty)
+		e.refreshPnLAlerts()
+	} else if filled {
+		if saveErr == nil {
+			e.publishEvent(events.TypePosition, "opened", symbol, position)
+			e.journalTrade(journalEntry, position, signal, response.AvgPrice, response.ExecutedQty)
+			e.placeTakeProfitOrders(ctx, position)
+			e.placeSafetyOrders(ctx, position, signal)
+		}
+		e.refreshPnLAlerts()
+		e.saveCooldown(e.riskManager.RecordEntry(symbol, position.OpenTime))
 	}
 
-	e.totalTrades++
 	e.logger.Infof("Buy order executed successfully: %s", response.ClientOrderID)
 
 	return nil
@@ -446,18 +1314,24 @@ func (e *Engine) executeBuyOrder(ctx context.Context, symbol string, signal *Sig
 
 // executeSellOrder executes a sell order
 func (e *Engine) executeSellOrder(ctx context.Context, symbol string, signal *Signal, position *models.Position) error {
+	if e.duplicateSignal(signal) {
+		return nil
+	}
+
 	e.logger.Infof("Executing SELL order for %s: quantity=%.6f", symbol, position.Size)
 
-	orderRequest := &exchange.OrderRequest{
-		Symbol:           symbol,
-		Side:             "SELL",
-		Type:             "MARKET",
-		Quantity:         position.Size,
-		PositionSide:     "BOTH",
-		NewClientOrderID: fmt.Sprintf("sell_%s_%d", symbol, time.Now().Unix()),
+	// Take-profit and trailing stop orders must not fire after the full exit
+	e.cancelChildOrders(ctx, position)
+
+	orderRequest := e.buildSellOrderRequest(symbol, signal, position)
+	exitInfo := e.orderInfo(symbol, orderRequest.Side, orderRequest.Quantity, signal.Price)
+	if !e.riskManager.ValidateReduceOnly(exitInfo, position.Size) {
+		e.publishEvent(events.TypeRisk, "rejected", symbol, exitInfo)
+		return fmt.Errorf("sell order for %s would exceed the position size %.6f", symbol, position.Size)
 	}
 
-	response, err := e.exchangeClient.PlaceOrder(ctx, orderRequest)
+	submittedAt := time.Now()
+	response, err := e.placeOrder(ctx, orderRequest)
 	if err != nil {
 		return fmt.Errorf("failed to place sell order: %w", err)
 	}
@@ -465,12 +1339,17 @@ func (e *Engine) executeSellOrder(ctx context.Context, symbol string, signal *Si
 	// Save order to database
 	order := &models.Order{
 		ExchangeOrderID: fmt.Sprintf("%d", response.OrderID),
+		ClientOrderID:   response.ClientOrderID,
 		Symbol:          response.Symbol,
 		Side:            response.Side,
 		Type:            response.Type,
 		Status:          response.Status,
 		Quantity:        response.OrigQty,
 		Price:           response.Price,
+		ExpectedPrice:   signal.Price,
+		SubmittedPrice:  orderRequest.Price,
+		AvgFillPrice:    response.AvgPrice,
+		SubmittedAt:     &submittedAt,
 		ExecutedQty:     response.ExecutedQty,
 		CumulativeQuote: response.CumQuote,
 		TimeInForce:     response.TimeInForce,
@@ -481,25 +1360,59 @@ func (e *Engine) executeSellOrder(ctx context.Context, symbol string, signal *Si
 		Notes:           signal.Reason,
 	}
 
-	if err := e.repository.CreateOrder(order); err != nil {
-		e.logger.Errorf("Failed to save order to database: %v", err)
+	// Close position if order is filled; PnL includes partial take-profits already realized
+	filled := response.Status == "FILLED"
+	var pnl float64
+	if filled {
+		pnl = e.positionPnL(symbol, position.EntryPrice, response.AvgPrice, position.Size) + position.ClosedPnL
 	}
 
-	// Close position if order is filled
-	if response.Status == "FILLED" {
-		pnl := (response.AvgPrice - position.EntryPrice) * position.Size
-
-		if err := e.repository.ClosePosition(position.ID, response.AvgPrice, pnl); err != nil {
-			e.logger.Errorf("Failed to close position in database: %v", err)
+	// The exit order and the closed position with its PnL are saved together
+	alreadyClosed := false
+	saveErr := e.repository.WithTx(func(tx database.Repository) error {
+		if err := tx.CreateOrder(order); err != nil {
+			return fmt.Errorf("failed to save order: %w", err)
 		}
-
-		// Update statistics
-		e.dailyPnL += pnl
-		if pnl > 0 {
-			e.winningTrades++
-		} else {
-			e.losingTrades++
+		if !filled {
+			return nil
+		}
+		err := tx.ClosePosition(position.ID, response.AvgPrice, pnl)
+		if errors.Is(err, database.ErrVersionConflict) {
+			// Another writer, e.g. the position sync, recorded the close first
+			alreadyClosed = true
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to close position: %w", err)
+		}
+		return e.enqueueEvent(tx, events.TypePosition, "closed", symbol, map[string]interface{}{
+			"position_id": position.ID,
+			"exit_price":  response.AvgPrice,
+			"pnl":         pnl,
+		})
+	})
+	if saveErr != nil {
+		e.logger.Errorf("Failed to save sell order for %s to database: %v", symbol, saveErr)
+		order.ID = 0 // Rolled back; fills are stored without an order
+	}
+	if alreadyClosed {
+		e.logger.Warnf("Position %d for %s was already closed by another writer; its PnL is not recorded again", position.ID, symbol)
+	}
+	e.recordFills(ctx, order, response, signal.Price)
+	e.publishEvent(events.TypeOrder, "placed", symbol, order)
+
+	if filled && !alreadyClosed {
+		netPnL := e.recordClosedPosition(ctx, position, pnl)
+		e.journalTrade(journalExit, position, signal, response.AvgPrice, position.Size)
+		if saveErr == nil && e.commentary != nil {
+			go e.annotateClosedPosition(position, signal, response.AvgPrice, netPnL)
 		}
+		e.publishEvent(events.TypePosition, "closed", symbol, map[string]interface{}{
+			"position_id": position.ID,
+			"exit_price":  response.AvgPrice,
+			"pnl":         pnl,
+			"net_pnl":     netPnL,
+		})
 	}
 
 	e.logger.Infof("Sell order executed successfully: %s", response.ClientOrderID)
@@ -507,58 +1420,54 @@ func (e *Engine) executeSellOrder(ctx context.Context, symbol string, signal *Si
 	return nil
 }
 
-// monitorRisk monitors risk metrics
-func (e *Engine) monitorRisk(ctx context.Context) {
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			if err := e.updateRiskMetrics(ctx); err != nil {
-				e.logger.Errorf("Failed to update risk metrics: %v", err)
-			}
-		}
-	}
+// recordClosedPosition attributes commissions and funding to a closed position, then
+// updates cooldowns, alerts, the profit lock and the daily loss with its net PnL, which it
+// returns
+func (e *Engine) recordClosedPosition(ctx context.Context, position *models.Position, pnl float64) float64 {
+	pnl, _ = e.attributeCosts(ctx, position, pnl)
+
+	e.refreshPnLAlerts()
+	e.saveCooldown(e.riskManager.RecordExit(position.Symbol, pnl, time.Now()))
+	e.recordProfitLock(ctx, pnl)
+	e.refreshDailyLoss()
+	return pnl
 }
 
 // updateRiskMetrics updates risk metrics
 func (e *Engine) updateRiskMetrics(ctx context.Context) error {
-	// Calculate win rate
-	winRate := 0.0
-	if e.totalTrades > 0 {
-		winRate = float64(e.winningTrades) / float64(e.totalTrades) * 100
+	stats, err := e.todayStats()
+	if err != nil {
+		return err
 	}
-
 	metric := &models.RiskMetric{
-		Date:          time.Now(),
-		DailyPnL:      e.dailyPnL,
-		TotalTrades:   e.totalTrades,
-		WinningTrades: e.winningTrades,
-		LosingTrades:  e.losingTrades,
-		WinRate:       winRate,
+		Date:          metricDate(stats.Day),
+		DailyPnL:      stats.NetPnL,
+		DailyFees:     stats.Fees,
+		DailyFunding:  stats.Funding,
+		TotalTrades:   stats.Closed,
+		WinningTrades: stats.Wins,
+		LosingTrades:  stats.Losses,
+		DrawdownScale: 1,
 	}
 
-	return e.repository.SaveRiskMetric(metric)
-}
-
-// monitorAccount monitors account information
-func (e *Engine) monitorAccount(ctx context.Context) {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
+	// Calculate win rate
+	if metric.TotalTrades > 0 {
+		metric.WinRate = float64(metric.WinningTrades) / float64(metric.TotalTrades) * 100
+	}
+	if e.drawdownScaler != nil {
+		status := e.drawdownScaler.Status()
+		metric.EquityDrawdownPercent = status.DrawdownPercent
+		metric.DrawdownScale = status.Scale
+	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			if err := e.updateAccountInfo(ctx); err != nil {
-				e.logger.Errorf("Failed to update account info: %v", err)
-			}
-		}
+	e.publishEvent(events.TypeRisk, "metrics", "", metric)
+	if err := e.repository.SaveRiskMetric(metric); err != nil {
+		return fmt.Errorf("failed to save risk metric: %w", err)
 	}
+	if e.config.RiskMetrics.Intraday {
+		return e.saveRiskMetricPoint(metric)
+	}
+	return nil
 }
 
 // updateAccountInfo updates account information
@@ -586,7 +1495,7 @@ func (e *Engine) updateAccountInfo(ctx context.Context) error {
 	return e.repository.UpdateAccount(account)
 }
 
-// closeAllPositions closes all open positions
+// closeAllPositions closes all open positions through the engine's sell path
 func (e *Engine) closeAllPositions(ctx context.Context) error {
 	positions, err := e.repository.GetAllPositions()
 	if err != nil {
@@ -594,31 +1503,184 @@ func (e *Engine) closeAllPositions(ctx context.Context) error {
 	}
 
 	for _, position := range positions {
-		orderRequest := &exchange.OrderRequest{
-			Symbol:        position.Symbol,
-			Side:          "SELL",
-			Type:          "MARKET",
-			Quantity:      position.Size,
-			PositionSide:  "BOTH",
-			ClosePosition: true,
+		unlock := e.lockSymbol(position.Symbol)
+		if result := e.closePositionMarket(ctx, position, "engine shutdown"); result.Closed {
+			e.logger.Infof("Closed position for %s", position.Symbol)
 		}
+		unlock()
+	}
 
-		_, err := e.exchangeClient.PlaceOrder(ctx, orderRequest)
-		if err != nil {
-			e.logger.Errorf("Failed to close position for %s: %v", position.Symbol, err)
-			continue
+	return nil
+}
+
+// refreshPnLAlerts reloads the positions watched by the PnL alerter after a position change
+func (e *Engine) refreshPnLAlerts() {
+	if e.pnlAlerter == nil {
+		return
+	}
+	if err := e.pnlAlerter.refresh(); err != nil {
+		e.logger.Warnf("Failed to refresh positions for PnL alerts: %v", err)
+	}
+}
+
+// validateExposure runs the portfolio and correlation-aware exposure checks against open
+// positions
+func (e *Engine) validateExposure(order *OrderInfo) bool {
+	if e.correlations == nil && !e.riskManager.LimitsPortfolioExposure() {
+		return true
+	}
+
+	positions, err := e.repository.GetAllPositions()
+	if err != nil {
+		e.logger.Errorf("Failed to get positions for exposure check: %v", err)
+		return false
+	}
+
+	portfolio := make([]PortfolioPosition, 0, len(positions))
+	for _, position := range positions {
+		price, markPrice := position.EntryPrice, position.MarkPrice
+		if data, err := e.getMarketData(position.Symbol); err == nil {
+			price, markPrice = data.Price, data.MarkPrice
 		}
+		portfolio = append(portfolio, PortfolioPosition{
+			Symbol:       position.Symbol,
+			Side:         position.PositionSide,
+			Size:         position.Size,
+			EntryPrice:   position.EntryPrice,
+			CurrentPrice: price,
+			MarkPrice:    markPrice,
+			Value:        e.contracts.get(position.Symbol).Notional(position.Size, price),
+			Leverage:     position.Leverage,
+		})
+	}
 
-		e.logger.Infof("Closed position for %s", position.Symbol)
+	if !e.riskManager.ValidatePortfolioExposure(order, portfolio) {
+		return false
+	}
+	if e.correlations == nil {
+		return true
 	}
+	return e.riskManager.ValidateCorrelatedExposure(order, portfolio, e.correlations.Matrix())
+}
 
-	return nil
+// Correlations returns the latest correlation matrix, or nil if unavailable
+func (e *Engine) Correlations() *CorrelationMatrix {
+	if e.correlations == nil {
+		return nil
+	}
+	return e.correlations.Matrix()
+}
+
+// StrategyName returns the name of the active strategy
+func (e *Engine) StrategyName() string {
+	return e.strategy.Name()
+}
+
+// Events returns the bus carrying order, position and risk events
+func (e *Engine) Events() *events.Bus {
+	return e.events
+}
+
+// publishEvent sends an engine event to streaming subscribers
+func (e *Engine) publishEvent(eventType, action, symbol string, data interface{}) {
+	e.events.Publish(events.Event{Instance: e.instance, Type: eventType, Action: action, Symbol: symbol, Data: data})
+}
+
+// EngineStatus is a snapshot of the engine state for the status endpoint
+type EngineStatus struct {
+	Instance      string               `json:"instance,omitempty"`
+	Running       bool                 `json:"running"`
+	Strategy      string               `json:"strategy"`
+	PaperTrading  bool                 `json:"paper_trading"`
+	Symbols       []string             `json:"symbols"`
+	PausedSymbols []*SymbolPause       `json:"paused_symbols"`
+	StrategyPause *StrategyPause       `json:"strategy_pause,omitempty"`
+	Circuit       string               `json:"exchange_circuit"` // closed, open or half_open
+	VolTarget     *VolTargetStatus     `json:"vol_target,omitempty"`
+	Drawdown      *DrawdownScaleStatus `json:"drawdown_scaling,omitempty"`
+	ProfitLock    *ProfitLockStatus    `json:"profit_lock,omitempty"`
+	Margin        *MarginStatus        `json:"margin,omitempty"`
+	DailyPnL      float64              `json:"daily_pnl"`     // Net of commissions and funding
+	DailyFees     float64              `json:"daily_fees"`    // Commission income; negative when paid
+	DailyFunding  float64              `json:"daily_funding"` // Funding income; negative when paid
+	TotalTrades   int                  `json:"total_trades"`  // Positions closed today
+	WinningTrades int                  `json:"winning_trades"`
+	LosingTrades  int                  `json:"losing_trades"`
+}
+
+func (e *Engine) volTargetStatus() *VolTargetStatus {
+	if e.volTarget == nil {
+		return nil
+	}
+	return e.volTarget.Status()
+}
+
+func (e *Engine) drawdownScaleStatus() *DrawdownScaleStatus {
+	if e.drawdownScaler == nil {
+		return nil
+	}
+	return e.drawdownScaler.Status()
+}
+
+func (e *Engine) profitLockStatus() *ProfitLockStatus {
+	if e.profitLock == nil {
+		return nil
+	}
+	return e.profitLock.Status(time.Now())
+}
+
+// Status returns a snapshot of the engine state
+func (e *Engine) Status() *EngineStatus {
+	e.mu.RLock()
+	running := e.isRunning
+	e.mu.RUnlock()
+
+	status := &EngineStatus{
+		Instance:      e.instance,
+		Running:       running,
+		Strategy:      e.strategy.Name(),
+		PaperTrading:  e.config.EnablePaperTrading,
+		Symbols:       e.config.Symbols,
+		PausedSymbols: e.PausedSymbols(),
+		StrategyPause: e.PausedStrategy(),
+		Circuit:       string(e.circuitState()),
+		VolTarget:     e.volTargetStatus(),
+		Drawdown:      e.drawdownScaleStatus(),
+		ProfitLock:    e.profitLockStatus(),
+		Margin:        e.marginStatus(),
+	}
+	stats, err := e.todayStats()
+	if err != nil {
+		e.logger.Errorf("Failed to get today's figures for the status: %v", err)
+		return status
+	}
+	status.DailyPnL = stats.NetPnL
+	status.DailyFees = stats.Fees
+	status.DailyFunding = stats.Funding
+	status.TotalTrades = stats.Closed
+	status.WinningTrades = stats.Wins
+	status.LosingTrades = stats.Losses
+	return status
+}
+
+// PaperTrading reports whether the engine is in paper trading mode
+func (e *Engine) PaperTrading() bool {
+	return e.config.EnablePaperTrading
 }
 
 // OrderInfo represents order information for risk validation
 type OrderInfo struct {
-	Symbol   string
-	Side     string
-	Quantity float64
-	Price    float64
+	Symbol       string
+	Side         string
+	Quantity     float64
+	Price        float64
+	ContractSize float64 // Quote value of one contract for inverse symbols, 0 for linear ones
+}
+
+// Value returns the order's notional in the quote asset
+func (o *OrderInfo) Value() float64 {
+	if o.ContractSize > 0 {
+		return o.Quantity * o.ContractSize
+	}
+	return o.Quantity * o.Price
 }
@@ -0,0 +1,122 @@
+package trading
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"contract_playground/internal/models"
+)
+
+// ExecutionStats summarizes the fills of one group of orders
+type ExecutionStats struct {
+	Key              string  `json:"key"` // Symbol, UTC hour of day or order type
+	Orders           int     `json:"orders"`
+	Fills            int     `json:"fills"`
+	Notional         float64 `json:"notional"`
+	AvgSlippageBps   float64 `json:"avg_slippage_bps"`    // Notional-weighted; positive when filled worse than expected
+	MakerRatio       float64 `json:"maker_ratio"`         // Share of notional filled as maker
+	AvgFillLatencyMs float64 `json:"avg_fill_latency_ms"` // Over orders with a recorded latency
+	latencyOrders    int
+	slippageNotional float64 // Notional of fills with an expected price
+}
+
+// ExecutionReport groups execution quality of stored fills by symbol, time of day and order type
+type ExecutionReport struct {
+	Since    time.Time         `json:"since"`
+	Total    *ExecutionStats   `json:"total"`
+	BySymbol []*ExecutionStats `json:"by_symbol"`
+	ByHour   []*ExecutionStats `json:"by_hour"`
+	ByType   []*ExecutionStats `json:"by_type"`
+}
+
+// ExecutionReport builds the execution quality report of fills since the given time
+func (e *Engine) ExecutionReport(since time.Time) (*ExecutionReport, error) {
+	trades, err := e.repository.GetTradesSince(since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trades: %w", err)
+	}
+
+	total := &ExecutionStats{Key: "total"}
+	bySymbol := make(map[string]*ExecutionStats)
+	byHour := make(map[string]*ExecutionStats)
+	byType := make(map[string]*ExecutionStats)
+	group := func(groups map[string]*ExecutionStats, key string) *ExecutionStats {
+		stats, ok := groups[key]
+		if !ok {
+			stats = &ExecutionStats{Key: key}
+			groups[key] = stats
+		}
+		return stats
+	}
+
+	counted := make(map[uint]bool)
+	for _, trade := range trades {
+		groups := []*ExecutionStats{
+			total,
+			group(bySymbol, trade.Symbol),
+			group(byHour, fmt.Sprintf("%02d", trade.TradeTime.UTC().Hour())),
+			group(byType, trade.Order.Type),
+		}
+		first := !counted[trade.OrderID]
+		counted[trade.OrderID] = true
+		for _, stats := range groups {
+			stats.addFill(trade, first)
+		}
+	}
+
+	report := &ExecutionReport{
+		Since:    since,
+		Total:    total.finish(),
+		BySymbol: sortedExecutionStats(bySymbol),
+		ByHour:   sortedExecutionStats(byHour),
+		ByType:   sortedExecutionStats(byType),
+	}
+	return report, nil
+}
+
+// addFill accumulates one fill; first is set on the first fill of its order
+func (s *ExecutionStats) addFill(trade *models.Trade, first bool) {
+	notional := trade.QuoteQty
+	s.Fills++
+	s.Notional += notional
+	if trade.IsMaker {
+		s.MakerRatio += notional
+	}
+	if trade.ExpectedPrice > 0 {
+		s.AvgSlippageBps += trade.SlippageBps * notional
+		s.slippageNotional += notional
+	}
+	if !first {
+		return
+	}
+	s.Orders++
+	if trade.Order.SubmittedAt != nil {
+		s.AvgFillLatencyMs += float64(trade.Order.FillLatencyMs)
+		s.latencyOrders++
+	}
+}
+
+// finish turns the accumulated sums into averages and ratios
+func (s *ExecutionStats) finish() *ExecutionStats {
+	if s.slippageNotional > 0 {
+		s.AvgSlippageBps /= s.slippageNotional
+	}
+	if s.Notional > 0 {
+		s.MakerRatio /= s.Notional
+	}
+	if s.latencyOrders > 0 {
+		s.AvgFillLatencyMs /= float64(s.latencyOrders)
+	}
+	return s
+}
+
+func sortedExecutionStats(groups map[string]*ExecutionStats) []*ExecutionStats {
+	stats := make([]*ExecutionStats, 0, len(groups))
+	for _, s := range groups {
+		stats = append(stats, s.finish())
+	}
+	// Hours are zero-padded, so every key sorts alphabetically
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Key < stats[j].Key })
+	return stats
+}
@@ -0,0 +1,333 @@
+package trading
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"contract_playground/internal/models"
+	"contract_playground/pkg/strategysdk"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ExternalStrategy runs a strategy as a subprocess speaking the strategysdk protocol: one
+// JSON request per line on its stdin, one JSON response per line on its stdout. Requests are
+// sent one at a time. The process is started on first use and restarted after it exits or
+// overruns a call's deadline, since its stream can no longer be trusted.
+type ExternalStrategy struct {
+	name    string
+	command string
+	args    []string
+	dir     string
+	logger  *logrus.Logger
+
+	busy      chan struct{} // Capacity 1; held for a whole request and response
+	params    map[string]interface{}
+	needsInit bool // The process has not seen the current parameters
+	limits    ProcessLimits
+	process   *strategyProcess
+	nextID    uint64
+
+	// Separate from mu so Name does not wait for an evaluation
+	nameMu     sync.Mutex
+	remoteName string // Name reported by the process
+}
+
+// NewExternalStrategy creates an external strategy; the command comes from Initialize
+func NewExternalStrategy() Strategy {
+	return newExternalStrategy()
+}
+
+func newExternalStrategy() *ExternalStrategy {
+	return &ExternalStrategy{name: "External", logger: logrus.StandardLogger(), busy: make(chan struct{}, 1)}
+}
+
+// lock waits for the process to be free, giving up when ctx ends so queued evaluations
+// respect their deadlines too
+func (e *ExternalStrategy) lock(ctx context.Context) error {
+	select {
+	case e.busy <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("external strategy %s is busy: %w", e.Name(), ctx.Err())
+	}
+}
+
+func (e *ExternalStrategy) unlock() {
+	<-e.busy
+}
+
+// SetLogger sets the logger the process's stderr is forwarded to
+func (e *ExternalStrategy) SetLogger(logger *logrus.Logger) {
+	e.lock(context.Background())
+	defer e.unlock()
+	e.logger = logger
+}
+
+// SetProcessLimits applies resource limits to the running process and any later one
+func (e *ExternalStrategy) SetProcessLimits(limits ProcessLimits) {
+	e.lock(context.Background())
+	defer e.unlock()
+	e.limits = limits
+	if e.process != nil {
+		if err := applyProcessLimits(e.process.cmd.Process.Pid, limits); err != nil {
+			e.logger.Warnf("Failed to limit external strategy %s: %v", e.name, err)
+		}
+	}
+}
+
+// Name returns the name the process reported, or the command's
+func (e *ExternalStrategy) Name() string {
+	e.nameMu.Lock()
+	defer e.nameMu.Unlock()
+	if e.remoteName != "" {
+		return e.remoteName
+	}
+	return e.name
+}
+
+// Initialize reads the command (with optional args and dir) and keeps the other parameters
+// for the process. The process itself starts on the first evaluation, so validating a
+// configuration does not spawn one; a changed command restarts it.
+func (e *ExternalStrategy) Initialize(config map[string]interface{}) error {
+	command, _ := getStringParam(config, "command")
+	if command == "" {
+		return fmt.Errorf("external strategy requires a command parameter")
+	}
+	if _, err := exec.LookPath(command); err != nil {
+		return fmt.Errorf("external strategy command: %w", err)
+	}
+	args, err := getStringListParam(config, "args")
+	if err != nil {
+		return err
+	}
+	dir, _ := getStringParam(config, "dir")
+	name, _ := getStringParam(config, "name")
+	if name == "" {
+		name = "External " + filepath.Base(command)
+	}
+
+	e.lock(context.Background())
+	defer e.unlock()
+	if command != e.command || strings.Join(args, "\x00") != strings.Join(e.args, "\x00") || dir != e.dir {
+		e.stopProcess()
+	}
+	e.command, e.args, e.dir = command, args, dir
+	e.nameMu.Lock()
+	e.name = name
+	e.nameMu.Unlock()
+	e.params = sdkParameters(config)
+	e.needsInit = true
+	return nil
+}
+
+// ShouldBuy asks the process for an entry
+func (e *ExternalStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
+	return e.evaluate(ctx, &strategysdk.Request{
+		Method:     strategysdk.MethodShouldBuy,
+		MarketData: toSDKMarketData(data),
+	})
+}
+
+// ShouldSell asks the process about the open position
+func (e *ExternalStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
+	return e.evaluate(ctx, &strategysdk.Request{
+		Method:     strategysdk.MethodShouldSell,
+		MarketData: toSDKMarketData(data),
+		Position:   toSDKPosition(position),
+	})
+}
+
+// Close stops the process
+func (e *ExternalStrategy) Close() error {
+	e.lock(context.Background())
+	defer e.unlock()
+	e.stopProcess()
+	return nil
+}
+
+func (e *ExternalStrategy) evaluate(ctx context.Context, request *strategysdk.Request) (*Signal, error) {
+	if err := e.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer e.unlock()
+
+	if e.command == "" {
+		return nil, fmt.Errorf("external strategy is not initialized")
+	}
+	if e.process == nil {
+		if err := e.startProcess(); err != nil {
+			return nil, err
+		}
+	}
+	if e.needsInit {
+		response, err := e.call(ctx, &strategysdk.Request{
+			Method:     strategysdk.MethodInitialize,
+			Version:    strategysdk.ProtocolVersion,
+			Parameters: e.params,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize external strategy %s: %w", e.name, err)
+		}
+		e.nameMu.Lock()
+		e.remoteName = response.Name
+		e.nameMu.Unlock()
+		e.needsInit = false
+	}
+
+	response, err := e.call(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return fromSDKSignal(response.Signal)
+}
+
+// call sends a request and waits for its response; the process is stopped when it cannot
+// be trusted to answer the next request in order
+func (e *ExternalStrategy) call(ctx context.Context, request *strategysdk.Request) (*strategysdk.Response, error) {
+	e.nextID++
+	request.ID = e.nextID
+
+	process := e.process
+	if err := process.encoder.Encode(request); err != nil {
+		e.stopProcess()
+		return nil, fmt.Errorf("failed to send %s to external strategy: %w", request.Method, err)
+	}
+
+	go process.read()
+	var result processResult
+	select {
+	case result = <-process.responses:
+	case <-ctx.Done():
+		e.stopProcess()
+		return nil, fmt.Errorf("external strategy %s did not answer %s: %w", e.name, request.Method, ctx.Err())
+	}
+
+	if result.err != nil {
+		e.stopProcess()
+		return nil, fmt.Errorf("failed to read %s response from external strategy: %w", request.Method, result.err)
+	}
+	if result.response.ID != request.ID {
+		e.stopProcess()
+		return nil, fmt.Errorf("external strategy answered request %d, want %d", result.response.ID, request.ID)
+	}
+	if result.response.Error != "" {
+		return nil, fmt.Errorf("external strategy %s: %s", e.name, result.response.Error)
+	}
+	return &result.response, nil
+}
+
+// startProcess starts the command with the current limits; every new process needs the
+// initialize request
+func (e *ExternalStrategy) startProcess() error {
+	cmd := exec.Command(e.command, e.args...)
+	cmd.Dir = e.dir
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create external strategy stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create external strategy stdout: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create external strategy stderr: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start external strategy %s: %w", e.command, err)
+	}
+
+	process := &strategyProcess{
+		cmd:       cmd,
+		stdin:     stdin,
+		encoder:   json.NewEncoder(stdin),
+		decoder:   json.NewDecoder(bufio.NewReader(stdout)),
+		responses: make(chan processResult, 1),
+	}
+	if err := applyProcessLimits(cmd.Process.Pid, e.limits); err != nil {
+		process.stop()
+		return fmt.Errorf("failed to limit external strategy %s: %w", e.command, err)
+	}
+	go forwardStderr(stderr, e.logger, e.name)
+
+	e.process = process
+	e.needsInit = true
+	e.logger.Infof("Started external strategy %s (pid %d)", e.name, cmd.Process.Pid)
+	return nil
+}
+
+func (e *ExternalStrategy) stopProcess() {
+	if e.process == nil {
+		return
+	}
+	e.process.stop()
+	e.process = nil
+}
+
+// strategyProcess is a running external strategy
+type strategyProcess struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	encoder   *json.Encoder
+	decoder   *json.Decoder
+	responses chan processResult // Capacity 1 so an abandoned read never blocks
+}
+
+type processResult struct {
+	response strategysdk.Response
+	err      error
+}
+
+// read decodes the next response into the responses channel
+func (p *strategyProcess) read() {
+	var result processResult
+	result.err = p.decoder.Decode(&result.response)
+	p.responses <- result
+}
+
+// stop closes stdin and kills the process, reaping it in the background
+func (p *strategyProcess) stop() {
+	p.stdin.Close()
+	p.cmd.Process.Kill()
+	go p.cmd.Wait()
+}
+
+// forwardStderr logs the process's stderr line by line until it closes
+func forwardStderr(stderr io.Reader, logger *logrus.Logger, name string) {
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		logger.Infof("[%s] %s", name, scanner.Text())
+	}
+}
+
+// getStringListParam reads a list of strings given as a YAML list or a space-separated string
+func getStringListParam(config map[string]interface{}, key string) ([]string, error) {
+	switch v := config[key].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return strings.Fields(v), nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		values := make([]string, len(v))
+		for i, item := range v {
+			value, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s[%d] must be a string, got %T", key, i, item)
+			}
+			values[i] = value
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("%s must be a list of strings, got %T", key, v)
+	}
+}
@@ -0,0 +1,120 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"contract_playground/internal/models"
+	"contract_playground/pkg/strategysdk"
+)
+
+// externalHelperEnv makes the test binary serve helperStrategy instead of running tests
+const externalHelperEnv = "EXTERNAL_STRATEGY_HELPER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(externalHelperEnv) == "1" {
+		if err := strategysdk.Serve(&helperStrategy{}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// helperStrategy buys above a threshold, fails on FAIL and hangs on SLOW
+type helperStrategy struct {
+	threshold float64
+}
+
+func (h *helperStrategy) Name() string { return "Helper" }
+
+func (h *helperStrategy) Initialize(parameters map[string]interface{}) error {
+	threshold, ok := parameters["threshold"].(float64)
+	if !ok {
+		return fmt.Errorf("threshold is required")
+	}
+	h.threshold = threshold
+	return nil
+}
+
+func (h *helperStrategy) ShouldBuy(ctx context.Context, data *strategysdk.MarketData) (*strategysdk.Signal, error) {
+	switch data.Symbol {
+	case "FAIL":
+		return nil, fmt.Errorf("no opinion on %s", data.Symbol)
+	case "SLOW":
+		time.Sleep(time.Minute)
+	}
+	if data.Price > h.threshold {
+		return &strategysdk.Signal{Action: strategysdk.ActionBuy, Confidence: 0.8, Reason: "above threshold"}, nil
+	}
+	return &strategysdk.Signal{Action: strategysdk.ActionHold}, nil
+}
+
+func (h *helperStrategy) ShouldSell(ctx context.Context, data *strategysdk.MarketData, position *strategysdk.Position) (*strategysdk.Signal, error) {
+	if data.Price < position.EntryPrice {
+		return &strategysdk.Signal{Action: strategysdk.ActionSell, Confidence: 1}, nil
+	}
+	return &strategysdk.Signal{Action: strategysdk.ActionHold}, nil
+}
+
+func TestExternalStrategy(t *testing.T) {
+	t.Setenv(externalHelperEnv, "1")
+	strategy := NewExternalStrategy().(*ExternalStrategy)
+	defer strategy.Close()
+
+	if err := strategy.Initialize(map[string]interface{}{"threshold": 100.0}); err == nil {
+		t.Fatal("Initialize accepted parameters without a command")
+	}
+	if err := strategy.Initialize(map[string]interface{}{"command": os.Args[0], "threshold": 100.0}); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	ctx := context.Background()
+	data := &MarketData{Symbol: "BTCUSDT", Price: 101, Timestamp: time.Now()}
+	signal, err := strategy.ShouldBuy(ctx, "BTCUSDT", data)
+	if err != nil {
+		t.Fatalf("ShouldBuy: %v", err)
+	}
+	if signal.Action != "BUY" || signal.Confidence != 0.8 || strategy.Name() != "Helper" {
+		t.Fatalf("got %+v from %s", signal, strategy.Name())
+	}
+
+	position := &models.Position{Symbol: "BTCUSDT", PositionSide: "LONG", Size: 1, EntryPrice: 102}
+	if signal, err := strategy.ShouldSell(ctx, "BTCUSDT", data, position); err != nil || signal.Action != "SELL" {
+		t.Fatalf("ShouldSell: %+v, %v", signal, err)
+	}
+
+	// Strategy errors keep the process
+	pid := strategy.process.cmd.Process.Pid
+	if _, err := strategy.ShouldBuy(ctx, "FAIL", &MarketData{Symbol: "FAIL"}); err == nil {
+		t.Fatal("strategy error was not returned")
+	}
+	if strategy.process == nil || strategy.process.cmd.Process.Pid != pid {
+		t.Fatal("a strategy error restarted the process")
+	}
+
+	// A missed deadline replaces the process, which gets the parameters again
+	timeout, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	if _, err := strategy.ShouldBuy(timeout, "SLOW", &MarketData{Symbol: "SLOW"}); err == nil {
+		t.Fatal("hung evaluation did not time out")
+	}
+	if signal, err := strategy.ShouldBuy(ctx, "BTCUSDT", data); err != nil || signal.Action != "BUY" {
+		t.Fatalf("after restart: %+v, %v", signal, err)
+	}
+	if strategy.process.cmd.Process.Pid == pid {
+		t.Fatal("the hung process was kept")
+	}
+
+	// New parameters reach the running process
+	if err := strategy.Initialize(map[string]interface{}{"command": os.Args[0], "threshold": 200.0}); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	if signal, err := strategy.ShouldBuy(ctx, "BTCUSDT", data); err != nil || signal.Action != "HOLD" {
+		t.Fatalf("after reinitialize: %+v, %v", signal, err)
+	}
+}
@@ -0,0 +1,264 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"contract_playground/internal/auditlog"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+	"contract_playground/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// annualizedFundingPercent converts a per-interval funding rate into an annual percentage
+func annualizedFundingPercent(rate, intervalHours float64) float64 {
+	return rate * (24 / intervalHours) * 365 * 100
+}
+
+// runFundingArbitrage periodically opens and unwinds delta-neutral funding positions
+func (e *Engine) runFundingArbitrage(ctx context.Context) {
+	cfg := e.config.FundingArbitrage
+	ctx = auditlog.WithSource(ctx, auditlog.TriggerStrategy, "funding_arbitrage")
+
+	for _, symbol := range cfg.Symbols {
+		if err := e.exchangeClient.SetLeverage(ctx, symbol, cfg.Leverage); err != nil {
+			e.logger.Warnf("Failed to set funding arbitrage leverage for %s: %v", symbol, err)
+		}
+	}
+
+	ticker := time.NewTicker(time.Duration(cfg.CheckIntervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		for _, symbol := range cfg.Symbols {
+			if err := e.checkFundingArbitrage(ctx, symbol); err != nil {
+				e.logger.Errorf("Funding arbitrage check failed for %s: %v", symbol, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkFundingArbitrage opens a pair when funding is rich and unwinds it once funding decays
+func (e *Engine) checkFundingArbitrage(ctx context.Context, symbol string) error {
+	cfg := e.config.FundingArbitrage
+
+	premium, err := e.exchangeClient.GetPremiumIndex(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	annualized := annualizedFundingPercent(premium.FundingRate, cfg.FundingIntervalHours)
+
+	position, err := e.repository.GetOpenFundingArbPosition(symbol)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("failed to get funding arbitrage position: %w", err)
+	}
+
+	// A pair whose perp leg is already closed only waits for its spot sale to be retried
+	if position != nil {
+		if annualized < cfg.ExitAnnualizedRate || position.PerpExitPrice > 0 {
+			return e.closeFundingArbitrage(ctx, position, premium.FundingRate,
+				fmt.Sprintf("annualized funding %.2f%% below exit %.2f%%", annualized, cfg.ExitAnnualizedRate))
+		}
+		return nil
+	}
+
+	if annualized < cfg.EntryAnnualizedRate || e.config.EnablePaperTrading {
+		return nil
+	}
+
+	spotPrice, err := e.spotClient.GetSpotPrice(ctx, symbol)
+	if err != nil {
+		return err
+	}
+
+	basis := (premium.MarkPrice - spotPrice) / spotPrice * 100
+	if cfg.MaxBasisPercent > 0 && math.Abs(basis) > cfg.MaxBasisPercent {
+		e.logger.Infof("Funding arbitrage entry for %s skipped: basis %.3f%% exceeds %.3f%%", symbol, basis, cfg.MaxBasisPercent)
+		return nil
+	}
+
+	return e.openFundingArbitrage(ctx, symbol, spotPrice, premium.FundingRate, annualized)
+}
+
+// openFundingArbitrage buys spot and shorts the same quantity of the perpetual. If the perp
+// leg fails the spot leg is sold again so no unhedged exposure is left behind.
+func (e *Engine) openFundingArbitrage(ctx context.Context, symbol string, spotPrice, fundingRate, annualized float64) error {
+	cfg := e.config.FundingArbitrage
+
+	perpInfo, err := e.exchangeClient.GetSymbolInfo(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	spotInfo, err := e.spotClient.GetSpotSymbolInfo(ctx, symbol)
+	if err != nil {
+		return err
+	}
+
+	// Both legs must be tradable at the same size
+	quantity := utils.NormalizeQuantity(utils.NormalizeQuantity(cfg.NotionalUSDT/spotPrice, perpInfo.StepSize), spotInfo.StepSize)
+	if quantity < perpInfo.MinQty || quantity < spotInfo.MinQty {
+		return fmt.Errorf("quantity %.8f below minimum order size", quantity)
+	}
+
+	quote, err := e.spotClient.GetSpotBalance(ctx, spotInfo.QuoteAsset)
+	if err != nil {
+		return err
+	}
+	if quote.Free < quantity*spotPrice*1.01 {
+		return fmt.Errorf("insufficient spot %s balance: %.2f", spotInfo.QuoteAsset, quote.Free)
+	}
+
+	spot, err := e.placeSpotOrder(ctx, &exchange.SpotOrderRequest{
+		Symbol:   symbol,
+		Side:     "BUY",
+		Type:     "MARKET",
+		Quantity: quantity,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to buy spot leg: %w", err)
+	}
+
+	// Hedge what was actually bought; a fee charged in the base asset is left unhedged
+	perpQuantity := utils.NormalizeQuantity(spot.ExecutedQty, perpInfo.StepSize)
+	perp, err := e.placeOrder(ctx, &exchange.OrderRequest{
+		Symbol:       symbol,
+		Side:         "SELL",
+		Type:         "MARKET",
+		Quantity:     perpQuantity,
+		PositionSide: "BOTH",
+	})
+	if err != nil {
+		e.logger.Errorf("Failed to short perp leg for %s, selling spot leg: %v", symbol, err)
+		if e.unwindSpotLeg(ctx, symbol, spotInfo, spot.ExecutedQty) == nil {
+			e.notifyFundingArbitrage(ctx, notify.LevelCritical, symbol,
+				fmt.Sprintf("Funding arbitrage spot leg unhedged: %s", symbol),
+				"The perp short failed and selling the spot leg back failed too; sell it manually")
+		}
+		return fmt.Errorf("failed to short perp leg: %w", err)
+	}
+
+	position := &models.FundingArbPosition{
+		Symbol:           symbol,
+		Status:           "OPEN",
+		SpotQuantity:     spot.ExecutedQty,
+		PerpQuantity:     perp.ExecutedQty,
+		SpotEntryPrice:   spot.AvgPrice,
+		PerpEntryPrice:   perp.AvgPrice,
+		EntryFundingRate: fundingRate,
+		OpenTime:         time.Now(),
+	}
+	if err := e.repository.CreateFundingArbPosition(position); err != nil {
+		e.logger.Errorf("Failed to save funding arbitrage position: %v", err)
+	}
+
+	e.notifyFundingArbitrage(ctx, notify.LevelInfo, symbol,
+		fmt.Sprintf("Funding arbitrage opened: %s", symbol),
+		fmt.Sprintf("Bought %.6f spot at %.6f and shorted %.6f perp at %.6f; annualized funding %.2f%%",
+			spot.ExecutedQty, spot.AvgPrice, perp.ExecutedQty, perp.AvgPrice, annualized))
+
+	return nil
+}
+
+// closeFundingArbitrage buys back the perp short and sells the spot leg. A closed perp leg
+// is recorded first so a failed spot sale is retried without touching the perp again.
+func (e *Engine) closeFundingArbitrage(ctx context.Context, position *models.FundingArbPosition, fundingRate float64, reason string) error {
+	if position.PerpExitPrice == 0 {
+		perp, err := e.placeOrder(ctx, &exchange.OrderRequest{
+			Symbol:       position.Symbol,
+			Side:         "BUY",
+			Type:         "MARKET",
+			Quantity:     position.PerpQuantity,
+			ReduceOnly:   true,
+			PositionSide: "BOTH",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to close perp leg: %w", err)
+		}
+
+		position.PerpExitPrice = perp.AvgPrice
+		if err := e.repository.UpdateFundingArbPosition(position); err != nil {
+			e.logger.Errorf("Failed to update funding arbitrage position: %v", err)
+		}
+	}
+
+	spotInfo, err := e.spotClient.GetSpotSymbolInfo(ctx, position.Symbol)
+	if err != nil {
+		return fmt.Errorf("perp leg closed but spot leg is still open: %w", err)
+	}
+	spot := e.unwindSpotLeg(ctx, position.Symbol, spotInfo, position.SpotQuantity)
+	if spot == nil {
+		e.notifyFundingArbitrage(ctx, notify.LevelCritical, position.Symbol,
+			fmt.Sprintf("Funding arbitrage spot leg still open: %s", position.Symbol),
+			"The perp short was closed but selling the spot leg failed; sell it manually")
+		return fmt.Errorf("perp leg closed but spot leg is still open")
+	}
+
+	now := time.Now()
+	position.Status = "CLOSED"
+	position.SpotExitPrice = spot.AvgPrice
+	position.ExitFundingRate = fundingRate
+	position.ClosedPnL = (spot.AvgPrice-position.SpotEntryPrice)*spot.ExecutedQty +
+		(position.PerpEntryPrice-position.PerpExitPrice)*position.PerpQuantity
+	position.CloseTime = &now
+	position.Notes = reason
+	if err := e.repository.UpdateFundingArbPosition(position); err != nil {
+		e.logger.Errorf("Failed to update funding arbitrage position: %v", err)
+	}
+
+	e.notifyFundingArbitrage(ctx, notify.LevelInfo, position.Symbol,
+		fmt.Sprintf("Funding arbitrage closed: %s", position.Symbol),
+		fmt.Sprintf("%s; price PnL of both legs %.2f (funding not included)", reason, position.ClosedPnL))
+
+	return nil
+}
+
+// unwindSpotLeg sells up to quantity of the spot base asset, limited to the free balance.
+// It returns nil when the sale failed.
+func (e *Engine) unwindSpotLeg(ctx context.Context, symbol string, info *exchange.SymbolInfo, quantity float64) *exchange.SpotOrderResponse {
+	if balance, err := e.spotClient.GetSpotBalance(ctx, info.BaseAsset); err == nil && balance.Free < quantity {
+		quantity = balance.Free
+	}
+
+	quantity = utils.NormalizeQuantity(quantity, info.StepSize)
+	if quantity < info.MinQty || quantity <= 0 {
+		e.logger.Errorf("Spot %s quantity %.8f is below the minimum order size", symbol, quantity)
+		return nil
+	}
+
+	response, err := e.placeSpotOrder(ctx, &exchange.SpotOrderRequest{
+		Symbol:   symbol,
+		Side:     "SELL",
+		Type:     "MARKET",
+		Quantity: quantity,
+	})
+	if err != nil {
+		e.logger.Errorf("Failed to sell spot %s: %v", symbol, err)
+		return nil
+	}
+	return response
+}
+
+// notifyFundingArbitrage sends a funding arbitrage notification
+func (e *Engine) notifyFundingArbitrage(ctx context.Context, level notify.Level, symbol, title, body string) {
+	msg := &notify.Message{
+		Title:  title,
+		Body:   body,
+		Level:  level,
+		Symbol: symbol,
+		Time:   time.Now(),
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver funding arbitrage notification: %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/pkg/utils"
+)
+
+// gridOrderRole marks orders placed by the neutral grid
+const gridOrderRole = "GRID"
+
+// syncNeutralGrid keeps a resting limit order on every grid level except the one nearest the
+// last fill: buys below price and sells above. A filled buy is answered by a sell one level
+// up and a filled sell by a buy one level down, so each round trip earns one grid step.
+func (e *Engine) syncNeutralGrid(ctx context.Context, symbol string, price float64) error {
+	levels, err := e.grid.Levels(symbol, price)
+	if err != nil {
+		return err
+	}
+
+	// A fresh grid gets buy legs below price and sell legs above, leaving the nearest level empty.
+	// If price falls through the grid every level can end up bought, so that must fit the limit.
+	if !gridHasLegs(levels) {
+		if value := e.grid.OrderValue() * float64(len(levels)-1); value > e.riskManager.Limits().MaxPositionSize {
+			return fmt.Errorf("grid exposure %.2f exceeds max position size %.2f", value, e.riskManager.Limits().MaxPositionSize)
+		}
+
+		gap := e.grid.findGridLevel(levels[0].BasePrice, price)
+		for i, level := range levels {
+			switch {
+			case i == gap:
+			case level.Price < price:
+				level.Side = "BUY"
+			default:
+				level.Side = "SELL"
+			}
+		}
+	}
+
+	for i, level := range levels {
+		if level.ExchangeOrderID == "" {
+			continue
+		}
+
+		filled, err := e.checkGridOrder(ctx, level)
+		if err != nil {
+			e.logger.Errorf("Failed to check grid order at level %d for %s: %v", level.Level, symbol, err)
+			continue
+		}
+		if !filled {
+			continue
+		}
+
+		// Answer the fill on the adjacent level
+		next, counterSide := i+1, "SELL"
+		if level.Side == "SELL" {
+			next, counterSide = i-1, "BUY"
+		}
+		level.Active = level.Side == "BUY"
+		level.Side = ""
+		if err := e.grid.SaveLevel(level); err != nil {
+			e.logger.Errorf("Failed to save grid level: %v", err)
+		}
+
+		if next >= 0 && next < len(levels) && levels[next].Side == "" {
+			levels[next].Side = counterSide
+			if err := e.grid.SaveLevel(levels[next]); err != nil {
+				e.logger.Errorf("Failed to save grid level: %v", err)
+			}
+		}
+	}
+
+	info, err := e.exchangeClient.GetSymbolInfo(ctx, symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get symbol info for %s: %w", symbol, err)
+	}
+
+	for _, level := range levels {
+		if level.Side == "" || level.ExchangeOrderID != "" {
+			continue
+		}
+
+		// A leg on the wrong side of price would fill immediately; wait for price to move
+		if (level.Side == "BUY" && level.Price >= price) || (level.Side == "SELL" && level.Price <= price) {
+			continue
+		}
+
+		if err := e.placeGridOrder(ctx, level, info); err != nil {
+			e.logger.Errorf("Failed to place grid %s at level %d for %s: %v", level.Side, level.Level, symbol, err)
+			continue
+		}
+
+		if err := e.grid.SaveLevel(level); err != nil {
+			e.logger.Errorf("Failed to save grid level: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// checkGridOrder refreshes the level's resting order. It returns true when the order filled;
+// orders canceled outside the engine are cleared so the leg is placed again.
+func (e *Engine) checkGridOrder(ctx context.Context, level *models.GridState) (bool, error) {
+	orderID, err := strconv.ParseInt(level.ExchangeOrderID, 10, 64)
+	if err != nil {
+		level.ExchangeOrderID = ""
+		return false, fmt.Errorf("invalid order id: %w", err)
+	}
+
+	info, err := e.exchangeClient.GetOrder(ctx, level.Symbol, orderID)
+	if err != nil {
+		return false, err
+	}
+
+	if order, err := e.repository.GetOrderByExchangeID(level.ExchangeOrderID); err == nil && order.Status != info.Status {
+		order.Status = info.Status
+		order.ExecutedQty = info.ExecutedQty
+		order.CumulativeQuote = info.CumQuote
+		if err := e.repository.UpdateOrder(order); err != nil {
+			e.logger.Errorf("Failed to update grid order %s: %v", level.ExchangeOrderID, err)
+		}
+	}
+
+	switch info.Status {
+	case "FILLED":
+		now := time.Now()
+		level.ExchangeOrderID = ""
+		level.FilledLegs++
+		level.LastFillPrice = info.AvgPrice
+		level.LastFillTime = &now
+		e.logger.Infof("Grid %s filled at level %d for %s: %.6f @ %.6f",
+			level.Side, level.Level, level.Symbol, info.ExecutedQty, info.AvgPrice)
+		return true, nil
+	case "CANCELED", "EXPIRED", "REJECTED":
+		level.ExchangeOrderID = ""
+		if err := e.grid.SaveLevel(level); err != nil {
+			e.logger.Errorf("Failed to save grid level: %v", err)
+		}
+	}
+
+	return false, nil
+}
+
+// placeGridOrder places the level's leg as a GTC limit order and records it
+func (e *Engine) placeGridOrder(ctx context.Context, level *models.GridState, info *exchange.SymbolInfo) error {
+	price := utils.NormalizePrice(level.Price, info.TickSize)
+	quantity := utils.NormalizeQuantity(e.grid.OrderValue()/price, info.StepSize)
+	if quantity < info.MinQty {
+		return fmt.Errorf("quantity %.8f below minimum %.8f", quantity, info.MinQty)
+	}
+
+	response, err := e.placeOrder(ctx, &exchange.OrderRequest{
+		Symbol:       level.Symbol,
+		Side:         level.Side,
+		Type:         "LIMIT",
+		Quantity:     quantity,
+		Price:        price,
+		TimeInForce:  "GTC",
+		PositionSide: "BOTH",
+	})
+	if err != nil {
+		return err
+	}
+
+	level.ExchangeOrderID = fmt.Sprintf("%d", response.OrderID)
+	level.Quantity = quantity
+
+	order := &models.Order{
+		ExchangeOrderID: level.ExchangeOrderID,
+		ClientOrderID:   response.ClientOrderID,
+		Symbol:          response.Symbol,
+		Side:            response.Side,
+		Type:            response.Type,
+		Status:          response.Status,
+		Quantity:        response.OrigQty,
+		Price:           response.Price,
+		ExecutedQty:     response.ExecutedQty,
+		CumulativeQuote: response.CumQuote,
+		TimeInForce:     response.TimeInForce,
+		PositionSide:    response.PositionSide,
+		Role:            gridOrderRole,
+		Strategy:        e.strategy.Name(),
+		Notes:           fmt.Sprintf("Grid level %d", level.Level),
+	}
+	if err := e.repository.CreateOrder(order); err != nil {
+		e.logger.Errorf("Failed to save grid order: %v", err)
+	}
+
+	e.logger.Infof("Placed grid %s at level %d for %s: %.6f @ %.6f", level.Side, level.Level, level.Symbol, quantity, price)
+	return nil
+}
+
+// gridHasLegs reports whether any level has a leg assigned
+func gridHasLegs(levels []*models.GridState) bool {
+	for _, level := range levels {
+		if level.Side != "" {
+			return true
+		}
+	}
+	return false
+}
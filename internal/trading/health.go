@@ -0,0 +1,194 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/exchange"
+)
+
+// Heartbeat and dependency check names
+const (
+	HealthTradingLoop     = "trading_loop"
+	HealthMarkPriceStream = "mark_price_stream"
+	HealthKlineStream     = "kline_stream"
+	HealthAutoCancel      = "auto_cancel"
+	HealthDatabase        = "database"
+	HealthRedis           = "redis"
+	HealthExchange        = "exchange"
+)
+
+// Deadline of each dependency check
+const healthCheckTimeout = 5 * time.Second
+
+// HealthCheck is the result of one health check
+type HealthCheck struct {
+	Name       string  `json:"name"`
+	OK         bool    `json:"ok"`
+	Detail     string  `json:"detail,omitempty"`
+	AgeSeconds float64 `json:"age_seconds,omitempty"` // Time since the last heartbeat
+}
+
+// HealthReport is the result of a liveness or readiness check
+type HealthReport struct {
+	OK     bool           `json:"ok"`
+	Time   time.Time      `json:"time"`
+	Checks []*HealthCheck `json:"checks"`
+}
+
+func (r *HealthReport) add(check *HealthCheck) {
+	r.Checks = append(r.Checks, check)
+	if !check.OK {
+		r.OK = false
+	}
+}
+
+// heartbeats records when each background loop or stream last showed signs of life
+type heartbeats struct {
+	mu      sync.Mutex
+	started time.Time
+	last    map[string]time.Time
+	maxAge  map[string]time.Duration
+	order   []string
+}
+
+func newHeartbeats() *heartbeats {
+	return &heartbeats{
+		last:   make(map[string]time.Time),
+		maxAge: make(map[string]time.Duration),
+	}
+}
+
+// expect registers a heartbeat that is unhealthy when older than maxAge. Until the first
+// beat its age counts from the start of the engine.
+func (h *heartbeats) expect(name string, maxAge time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.started.IsZero() {
+		h.started = time.Now()
+	}
+	if _, ok := h.maxAge[name]; !ok {
+		h.order = append(h.order, name)
+	}
+	h.maxAge[name] = maxAge
+}
+
+func (h *heartbeats) beat(name string) {
+	h.mu.Lock()
+	h.last[name] = time.Now()
+	h.mu.Unlock()
+}
+
+func (h *heartbeats) checks() []*HealthCheck {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	checks := make([]*HealthCheck, 0, len(h.order))
+	for _, name := range h.order {
+		last, ok := h.last[name]
+		if !ok {
+			last = h.started
+		}
+		age := time.Since(last)
+		check := &HealthCheck{Name: name, OK: age <= h.maxAge[name], AgeSeconds: age.Seconds()}
+		switch {
+		case !ok && !check.OK:
+			check.Detail = "no heartbeat since start"
+		case !check.OK:
+			check.Detail = fmt.Sprintf("last heartbeat older than %s", h.maxAge[name])
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// expectHeartbeats registers the loops and streams started by Start
+func (e *Engine) expectHeartbeats() {
+	streamMaxAge := time.Duration(e.config.Health.StreamMaxAgeSeconds) * time.Second
+	symbolTimeout := time.Duration(e.config.SymbolTimeoutSeconds) * time.Second
+
+	if e.config.EvaluationMode == config.EvaluationModeCandleClose {
+		// The scheduler beats once per candle, from the stream or the aligned timer
+		interval := config.KlineIntervals[e.config.KlineInterval]
+		delay := time.Duration(e.config.CandleCloseDelayMs) * time.Millisecond
+		e.heartbeats.expect(HealthTradingLoop, 2*interval+delay+symbolTimeout)
+		e.heartbeats.expect(HealthKlineStream, 2*interval+streamMaxAge)
+	} else {
+		interval := time.Duration(e.config.TradingInterval) * time.Second
+		e.heartbeats.expect(HealthTradingLoop, 3*interval+symbolTimeout)
+	}
+	e.heartbeats.expect(HealthMarkPriceStream, streamMaxAge)
+	if e.config.AutoCancel.Enabled {
+		// Orders are canceled by the exchange once refreshes stop for the countdown
+		e.heartbeats.expect(HealthAutoCancel, time.Duration(e.config.AutoCancel.CountdownSeconds)*time.Second)
+	}
+}
+
+// Liveness reports whether the engine is running and its loops and streams are alive. It
+// makes no external calls, so an outage of a dependency does not fail it.
+func (e *Engine) Liveness() *HealthReport {
+	e.mu.RLock()
+	running := e.isRunning
+	e.mu.RUnlock()
+
+	report := &HealthReport{OK: true, Time: time.Now()}
+	engine := &HealthCheck{Name: "engine", OK: running}
+	if !running {
+		engine.Detail = "not running"
+	}
+	report.add(engine)
+
+	for _, check := range e.heartbeats.checks() {
+		report.add(check)
+	}
+	return report
+}
+
+// Readiness reports liveness plus connectivity to the database, Redis and the exchange, and
+// whether the market data writer keeps up
+func (e *Engine) Readiness(ctx context.Context) *HealthReport {
+	report := e.Liveness()
+	report.add(e.checkDependency(ctx, HealthDatabase, e.pingDatabase))
+	report.add(e.checkDependency(ctx, HealthRedis, func(ctx context.Context) error {
+		return e.redis.Ping(ctx).Err()
+	}))
+	report.add(e.checkDependency(ctx, HealthExchange, e.pingExchange))
+	if e.marketDataWriter != nil {
+		report.add(e.marketDataWriter.check())
+	}
+	return report
+}
+
+func (e *Engine) checkDependency(ctx context.Context, name string, ping func(context.Context) error) *HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	if err := ping(ctx); err != nil {
+		return &HealthCheck{Name: name, Detail: err.Error()}
+	}
+	return &HealthCheck{Name: name, OK: true}
+}
+
+func (e *Engine) pingDatabase(ctx context.Context) error {
+	sqlDB, err := e.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// pingExchange fetches a price; an open circuit breaker counts as unreachable
+func (e *Engine) pingExchange(ctx context.Context) error {
+	if state := e.circuitState(); state == exchange.CircuitOpen {
+		return fmt.Errorf("circuit breaker %s", state)
+	}
+	if len(e.config.Symbols) == 0 {
+		return nil
+	}
+	_, err := e.exchangeClient.GetSymbolPrice(ctx, e.config.Symbols[0])
+	return err
+}
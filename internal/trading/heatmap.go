@@ -0,0 +1,155 @@
+package trading
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"contract_playground/internal/models"
+)
+
+// ReturnCell summarizes the closed trades entered in one bucket of time. Weekday is -1 in
+// the by-hour marginal and Hour is -1 in the by-weekday marginal.
+type ReturnCell struct {
+	Weekday          int     `json:"weekday"` // 0 = Sunday
+	Hour             int     `json:"hour"`
+	Trades           int     `json:"trades"`
+	AvgReturnPercent float64 `json:"avg_return_percent"` // Mean net PnL over entry notional
+	WinRate          float64 `json:"win_rate"`
+	NetPnL           float64 `json:"net_pnl"`
+	wins             int
+}
+
+// ReturnHeatmap buckets one symbol's closed trades by the weekday and hour they were entered
+type ReturnHeatmap struct {
+	Symbol    string        `json:"symbol"` // ALL for every symbol together
+	Trades    int           `json:"trades"`
+	Cells     []*ReturnCell `json:"cells"` // Non-empty weekday and hour cells
+	ByHour    []*ReturnCell `json:"by_hour"`
+	ByWeekday []*ReturnCell `json:"by_weekday"`
+
+	cells map[[2]int]*ReturnCell
+}
+
+// ReturnHeatmapReport holds the heatmaps of every traded symbol and of all of them together
+type ReturnHeatmapReport struct {
+	Since    time.Time        `json:"since"`
+	Timezone string           `json:"timezone"` // Zone the hours and weekdays are in
+	Total    *ReturnHeatmap   `json:"total"`
+	Symbols  []*ReturnHeatmap `json:"symbols"`
+}
+
+// ReturnHeatmap buckets positions closed since the given time by the hour of day and
+// weekday of their entry in timezone (trading.sessions.timezone when empty), so session
+// windows can be set where the strategy has an edge. symbol limits the report to one symbol.
+func (e *Engine) ReturnHeatmap(since time.Time, timezone, symbol string) (*ReturnHeatmapReport, error) {
+	if timezone == "" {
+		timezone = e.config.Sessions.Timezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+
+	positions, err := e.repository.GetClosedPositions(since, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get closed positions: %w", err)
+	}
+	if symbol != "" {
+		filtered := positions[:0]
+		for _, position := range positions {
+			if position.Symbol == symbol {
+				filtered = append(filtered, position)
+			}
+		}
+		positions = filtered
+	}
+
+	notional := func(position *models.Position) float64 {
+		return e.orderInfo(position.Symbol, "BUY", position.Size, position.EntryPrice).Value()
+	}
+	report := buildReturnHeatmaps(positions, notional, location)
+	report.Since = since
+	report.Timezone = location.String()
+	return report, nil
+}
+
+// buildReturnHeatmaps buckets positions by their entry time in location; notional gives a
+// position's entry value for its return
+func buildReturnHeatmaps(positions []*models.Position, notional func(*models.Position) float64, location *time.Location) *ReturnHeatmapReport {
+	total := newReturnHeatmap("ALL")
+	bySymbol := make(map[string]*ReturnHeatmap)
+	for _, position := range positions {
+		value := notional(position)
+		if value <= 0 {
+			continue
+		}
+		heatmap, ok := bySymbol[position.Symbol]
+		if !ok {
+			heatmap = newReturnHeatmap(position.Symbol)
+			bySymbol[position.Symbol] = heatmap
+		}
+
+		entered := position.OpenTime.In(location)
+		returnPercent := position.NetPnL / value * 100
+		total.add(entered, returnPercent, position.NetPnL)
+		heatmap.add(entered, returnPercent, position.NetPnL)
+	}
+
+	report := &ReturnHeatmapReport{Total: total.finish()}
+	for _, heatmap := range bySymbol {
+		report.Symbols = append(report.Symbols, heatmap.finish())
+	}
+	sort.Slice(report.Symbols, func(i, j int) bool { return report.Symbols[i].Symbol < report.Symbols[j].Symbol })
+	return report
+}
+
+func newReturnHeatmap(symbol string) *ReturnHeatmap {
+	return &ReturnHeatmap{Symbol: symbol, cells: make(map[[2]int]*ReturnCell)}
+}
+
+// add counts one trade in its cell and both marginals
+func (h *ReturnHeatmap) add(entered time.Time, returnPercent, pnl float64) {
+	h.Trades++
+	weekday, hour := int(entered.Weekday()), entered.Hour()
+	for _, key := range [][2]int{{weekday, hour}, {-1, hour}, {weekday, -1}} {
+		cell, ok := h.cells[key]
+		if !ok {
+			cell = &ReturnCell{Weekday: key[0], Hour: key[1]}
+			h.cells[key] = cell
+		}
+		cell.Trades++
+		cell.AvgReturnPercent += returnPercent
+		cell.NetPnL += pnl
+		if pnl > 0 {
+			cell.wins++
+		}
+	}
+}
+
+// finish averages the cells and sorts them by weekday, then hour
+func (h *ReturnHeatmap) finish() *ReturnHeatmap {
+	h.Cells, h.ByHour, h.ByWeekday = []*ReturnCell{}, []*ReturnCell{}, []*ReturnCell{}
+	for _, cell := range h.cells {
+		cell.AvgReturnPercent /= float64(cell.Trades)
+		cell.WinRate = float64(cell.wins) / float64(cell.Trades)
+		switch {
+		case cell.Weekday < 0:
+			h.ByHour = append(h.ByHour, cell)
+		case cell.Hour < 0:
+			h.ByWeekday = append(h.ByWeekday, cell)
+		default:
+			h.Cells = append(h.Cells, cell)
+		}
+	}
+	for _, cells := range [][]*ReturnCell{h.Cells, h.ByHour, h.ByWeekday} {
+		sort.Slice(cells, func(i, j int) bool {
+			if cells[i].Weekday != cells[j].Weekday {
+				return cells[i].Weekday < cells[j].Weekday
+			}
+			return cells[i].Hour < cells[j].Hour
+		})
+	}
+	h.cells = nil
+	return h
+}
@@ -0,0 +1,49 @@
+package trading
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"contract_playground/internal/models"
+)
+
+func TestBuildReturnHeatmaps(t *testing.T) {
+	// Friday 2024-03-01 09:30 UTC is 17:30 in Shanghai
+	friday := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	positions := []*models.Position{
+		{Symbol: "BTCUSDT", Size: 1, EntryPrice: 100, NetPnL: 2, OpenTime: friday},
+		{Symbol: "BTCUSDT", Size: 1, EntryPrice: 100, NetPnL: -1, OpenTime: friday.Add(10 * time.Minute)},
+		{Symbol: "ETHUSDT", Size: 2, EntryPrice: 50, NetPnL: 4, OpenTime: friday.Add(24 * time.Hour)},
+	}
+	notional := func(position *models.Position) float64 { return position.Size * position.EntryPrice }
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("no time zone data: %v", err)
+	}
+
+	report := buildReturnHeatmaps(positions, notional, shanghai)
+	if report.Total.Trades != 3 || len(report.Symbols) != 2 {
+		t.Fatalf("total %d trades over %d symbols, want 3 over 2", report.Total.Trades, len(report.Symbols))
+	}
+
+	btc := report.Symbols[0]
+	if btc.Symbol != "BTCUSDT" || len(btc.Cells) != 1 {
+		t.Fatalf("BTCUSDT cells = %+v, want one", btc.Cells)
+	}
+	cell := btc.Cells[0]
+	if cell.Weekday != int(time.Friday) || cell.Hour != 17 || cell.Trades != 2 {
+		t.Errorf("cell = %+v, want Friday 17:00 with 2 trades", cell)
+	}
+	if math.Abs(cell.AvgReturnPercent-0.5) > 1e-9 || cell.WinRate != 0.5 || cell.NetPnL != 1 {
+		t.Errorf("cell = %+v, want 0.5%% average return, 0.5 win rate and 1 net", cell)
+	}
+
+	// The marginals of all symbols: both entries at 17:00, on Friday and Saturday
+	if len(report.Total.ByHour) != 1 || report.Total.ByHour[0].Trades != 3 || report.Total.ByHour[0].Weekday != -1 {
+		t.Errorf("by hour = %+v, want one 17:00 bucket of 3 trades", report.Total.ByHour)
+	}
+	if len(report.Total.ByWeekday) != 2 || report.Total.ByWeekday[1].Weekday != int(time.Saturday) || report.Total.ByWeekday[1].AvgReturnPercent != 4 {
+		t.Errorf("by weekday = %+v, want Friday and Saturday with a 4%% return", report.Total.ByWeekday)
+	}
+}
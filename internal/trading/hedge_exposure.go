@@ -0,0 +1,117 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Quote assets whose spot balances are cash rather than exposure
+var stableAssets = map[string]bool{"USDT": true, "USDC": true, "BUSD": true, "FDUSD": true, "USD": true}
+
+// AssetExposure is the combined perpetual and spot holding of one base asset. Quantities are
+// in the base asset; perpetual shorts are negative.
+type AssetExposure struct {
+	Asset        string  `json:"asset"`
+	PerpQuantity float64 `json:"perp_quantity"`
+	SpotQuantity float64 `json:"spot_quantity"` // Free plus locked
+	NetQuantity  float64 `json:"net_quantity"`
+	Price        float64 `json:"price"` // 0 when no price is known
+	PerpValue    float64 `json:"perp_value"`
+	SpotValue    float64 `json:"spot_value"`
+	NetValue     float64 `json:"net_value"`
+}
+
+// CombinedExposure is the exposure of the futures account and the spot wallet per base asset,
+// showing how much of the perpetual exposure is hedged with spot
+type CombinedExposure struct {
+	Assets      []*AssetExposure `json:"assets"`
+	NetValue    float64          `json:"net_value"`
+	GrossValue  float64          `json:"gross_value"` // Sum of absolute net values per asset
+	SpotEnabled bool             `json:"spot_enabled"`
+	Time        time.Time        `json:"time"`
+}
+
+// CombinedExposure builds the exposure view from the exchange positions and, when a spot
+// client is configured, the spot wallet balances
+func (e *Engine) CombinedExposure(ctx context.Context) (*CombinedExposure, error) {
+	positions, err := e.exchangeClient.GetPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange positions: %w", err)
+	}
+
+	assets := make(map[string]*AssetExposure)
+	exposure := func(asset string) *AssetExposure {
+		if assets[asset] == nil {
+			assets[asset] = &AssetExposure{Asset: asset}
+		}
+		return assets[asset]
+	}
+
+	for _, position := range positions {
+		if position.PositionAmt == 0 || position.MarkPrice <= 0 {
+			continue
+		}
+		info := e.contracts.get(position.Symbol)
+		asset := info.BaseAsset
+		if asset == "" {
+			asset = baseAsset(position.Symbol)
+		}
+
+		quantity := position.PositionAmt
+		if info.Inverse() {
+			quantity = math.Copysign(info.Notional(math.Abs(quantity), position.MarkPrice)/position.MarkPrice, quantity)
+		}
+		entry := exposure(asset)
+		entry.PerpQuantity += quantity
+		entry.Price = position.MarkPrice
+	}
+
+	spotEnabled := e.spotClient != nil
+	if spotEnabled {
+		balances, err := e.spotClient.GetSpotBalances(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get spot balances: %w", err)
+		}
+		for _, balance := range balances {
+			if stableAssets[balance.Asset] {
+				continue
+			}
+			entry := exposure(balance.Asset)
+			entry.SpotQuantity += balance.Free + balance.Locked
+			if entry.Price == 0 {
+				if price, err := e.spotClient.GetSpotPrice(ctx, balance.Asset+"USDT"); err == nil {
+					entry.Price = price
+				}
+			}
+		}
+	}
+
+	result := &CombinedExposure{SpotEnabled: spotEnabled, Time: time.Now()}
+	for _, entry := range assets {
+		entry.NetQuantity = entry.PerpQuantity + entry.SpotQuantity
+		entry.PerpValue = entry.PerpQuantity * entry.Price
+		entry.SpotValue = entry.SpotQuantity * entry.Price
+		entry.NetValue = entry.NetQuantity * entry.Price
+		result.NetValue += entry.NetValue
+		result.GrossValue += math.Abs(entry.NetValue)
+		result.Assets = append(result.Assets, entry)
+	}
+	sort.Slice(result.Assets, func(i, j int) bool {
+		return math.Abs(result.Assets[i].NetValue) > math.Abs(result.Assets[j].NetValue)
+	})
+
+	return result, nil
+}
+
+// baseAsset strips the contract suffix and quote asset from a futures symbol
+// (BTCUSDT -> BTC, BTCUSD_PERP -> BTC)
+func baseAsset(symbol string) string {
+	if i := strings.Index(symbol, "_"); i > 0 {
+		symbol = symbol[:i]
+	}
+	return strings.TrimSuffix(symbol, quoteAsset(symbol))
+}
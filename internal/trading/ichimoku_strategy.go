@@ -0,0 +1,172 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+
+	"contract_playground/internal/models"
+	"contract_playground/pkg/utils"
+)
+
+// IchimokuStrategy buys when price is above the cloud with the conversion line above the base
+// line and one of them just turned: a close breaking up through the cloud or a bullish TK cross.
+// The position is closed on a close below the base line or back below the cloud.
+type IchimokuStrategy struct {
+	name          string
+	orderValue    float64 // USDT per entry
+	tenkanPeriod  int
+	kijunPeriod   int
+	senkouBPeriod int
+	displacement  int
+	requireChikou bool // Require the close to be above the close displacement candles ago
+}
+
+// NewIchimokuStrategy creates a new Ichimoku strategy
+func NewIchimokuStrategy() Strategy {
+	return &IchimokuStrategy{
+		name:          "Ichimoku Strategy",
+		orderValue:    100,
+		tenkanPeriod:  9,
+		kijunPeriod:   26,
+		senkouBPeriod: 52,
+		displacement:  26,
+		requireChikou: true,
+	}
+}
+
+// Name returns the strategy name
+func (s *IchimokuStrategy) Name() string {
+	return s.name
+}
+
+// Initialize initializes the Ichimoku strategy with parameters
+func (s *IchimokuStrategy) Initialize(config map[string]interface{}) error {
+	if val, ok := getFloatParam(config, "order_usdt"); ok {
+		s.orderValue = val
+	}
+	if val, ok := getFloatParam(config, "tenkan_period"); ok {
+		s.tenkanPeriod = int(val)
+	}
+	if val, ok := getFloatParam(config, "kijun_period"); ok {
+		s.kijunPeriod = int(val)
+	}
+	if val, ok := getFloatParam(config, "senkou_b_period"); ok {
+		s.senkouBPeriod = int(val)
+	}
+	if val, ok := getFloatParam(config, "displacement"); ok {
+		s.displacement = int(val)
+	}
+	if val, ok := getBoolParam(config, "require_chikou"); ok {
+		s.requireChikou = val
+	}
+
+	if s.orderValue <= 0 {
+		return fmt.Errorf("order value must be positive")
+	}
+	if s.tenkanPeriod < 1 || s.kijunPeriod < 1 || s.senkouBPeriod < 1 || s.displacement < 1 {
+		return fmt.Errorf("ichimoku periods must be positive")
+	}
+	if s.tenkanPeriod >= s.kijunPeriod || s.kijunPeriod >= s.senkouBPeriod {
+		return fmt.Errorf("ichimoku periods must satisfy tenkan < kijun < senkou B")
+	}
+
+	// The engine fetches 100 candles of the kline interval; the last one is still forming
+	if needed := s.requiredCandles(); needed > 99 {
+		return fmt.Errorf("ichimoku periods need %d closed candles, at most 99 are available", needed)
+	}
+
+	return nil
+}
+
+// ShouldBuy enters on a cloud break or TK cross while both conditions hold
+func (s *IchimokuStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
+	series, closes := s.calculate(data)
+	if len(closes) < s.requiredCandles() {
+		return &Signal{Action: "HOLD", Reason: "Insufficient data for Ichimoku"}, nil
+	}
+
+	i := len(closes) - 1
+	aboveCloud := closes[i] > series.CloudTop(i)
+	bullishTK := series.Tenkan[i] > series.Kijun[i]
+	if !aboveCloud || !bullishTK {
+		return &Signal{Action: "HOLD", Reason: fmt.Sprintf("Ichimoku: above cloud %t, tenkan above kijun %t", aboveCloud, bullishTK)}, nil
+	}
+
+	cloudBreak := closes[i-1] <= series.CloudTop(i-1)
+	tkCross := series.Tenkan[i-1] <= series.Kijun[i-1]
+	if !cloudBreak && !tkCross {
+		return &Signal{Action: "HOLD", Reason: "Ichimoku trend already established"}, nil
+	}
+
+	if s.requireChikou && closes[i] <= closes[i-s.displacement] {
+		return &Signal{Action: "HOLD", Reason: "Chikou span below price"}, nil
+	}
+
+	reason := "Ichimoku cloud break"
+	confidence := 0.7
+	switch {
+	case cloudBreak && tkCross:
+		reason, confidence = "Ichimoku cloud break with TK cross", 1.0
+	case tkCross:
+		reason = "Ichimoku TK cross above cloud"
+	}
+
+	return &Signal{
+		Action:       "BUY",
+		Quantity:     s.orderValue / data.Price,
+		Price:        data.Price,
+		StopLoss:     series.Kijun[i],
+		Confidence:   confidence,
+		Reason:       fmt.Sprintf("%s (tenkan %.6f, kijun %.6f, cloud top %.6f)", reason, series.Tenkan[i], series.Kijun[i], series.CloudTop(i)),
+		PositionSide: "LONG",
+	}, nil
+}
+
+// ShouldSell exits on a close below the base line or below the cloud
+func (s *IchimokuStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
+	series, closes := s.calculate(data)
+	if len(closes) < s.requiredCandles() {
+		return &Signal{Action: "HOLD", Reason: "Insufficient data for Ichimoku"}, nil
+	}
+
+	i := len(closes) - 1
+	if closes[i] < series.CloudBottom(i) {
+		return &Signal{
+			Action:     "SELL",
+			Quantity:   position.Size,
+			Price:      data.Price,
+			Confidence: 1.0,
+			Reason:     fmt.Sprintf("Close %.6f below cloud %.6f", closes[i], series.CloudBottom(i)),
+		}, nil
+	}
+
+	if closes[i] < series.Kijun[i] {
+		return &Signal{
+			Action:     "SELL",
+			Quantity:   position.Size,
+			Price:      data.Price,
+			Confidence: 0.8,
+			Reason:     fmt.Sprintf("Close %.6f below kijun %.6f", closes[i], series.Kijun[i]),
+		}, nil
+	}
+
+	return &Signal{Action: "HOLD", Reason: fmt.Sprintf("Ichimoku kijun %.6f", series.Kijun[i])}, nil
+}
+
+// calculate computes the Ichimoku lines over the closed candles
+func (s *IchimokuStrategy) calculate(data *MarketData) (*utils.IchimokuSeries, []float64) {
+	closed := closedKlines(data.Klines)
+	highs := make([]float64, len(closed))
+	lows := make([]float64, len(closed))
+	closes := make([]float64, len(closed))
+	for i, k := range closed {
+		highs[i], lows[i], closes[i] = k.High, k.Low, k.Close
+	}
+
+	return utils.CalculateIchimoku(highs, lows, closes, s.tenkanPeriod, s.kijunPeriod, s.senkouBPeriod, s.displacement), closes
+}
+
+// requiredCandles returns the closed candles needed for the cloud on the previous candle
+func (s *IchimokuStrategy) requiredCandles() int {
+	return s.senkouBPeriod + s.displacement + 1
+}
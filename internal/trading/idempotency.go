@@ -0,0 +1,60 @@
+package trading
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// lockSymbol takes the execution lock of a symbol and returns the function that releases it.
+// Strategy evaluation, manual orders and closes of the same symbol never interleave, while
+// different symbols proceed independently.
+func (e *Engine) lockSymbol(symbol string) func() {
+	e.symbolLocksMu.Lock()
+	lock, ok := e.symbolLocks[symbol]
+	if !ok {
+		lock = &sync.Mutex{}
+		e.symbolLocks[symbol] = lock
+	}
+	e.symbolLocksMu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// signalID derives a deterministic ID for a strategy signal from the strategy, symbol,
+// action and the candle it was produced on. It is used as the client order ID, so the same
+// signal evaluated twice within a candle maps to the same order.
+func (e *Engine) signalID(symbol, action string, data *MarketData) string {
+	var candle int64
+	if len(data.Klines) > 0 {
+		candle = data.Klines[len(data.Klines)-1].OpenTime
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%d", e.strategy.Name(), symbol, action, candle)))
+	// Binance limits client order IDs to 36 characters
+	return "sig_" + hex.EncodeToString(sum[:8])
+}
+
+// duplicateSignal reports whether an order for the signal was already placed
+func (e *Engine) duplicateSignal(signal *Signal) bool {
+	if signal.ID == "" {
+		return false
+	}
+
+	order, err := e.repository.GetOrderByClientID(signal.ID)
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			// Without the check an order could be sent twice; skip this evaluation instead
+			e.logger.Errorf("Failed to look up order for signal %s: %v", signal.ID, err)
+			return true
+		}
+		return false
+	}
+
+	e.logger.Infof("Signal %s already executed as order %s (%s), skipping", signal.ID, order.ExchangeOrderID, order.Status)
+	return true
+}
@@ -0,0 +1,107 @@
+package trading
+
+import (
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestSignalID(t *testing.T) {
+	e := &Engine{strategy: &bandStrategy{}}
+	candle := func(openTime int64, close float64) *MarketData {
+		return &MarketData{Klines: []*exchange.KlineData{{OpenTime: openTime - 60000}, {OpenTime: openTime, Close: close}}}
+	}
+
+	id := e.signalID("BTCUSDT", "BUY", candle(1000, 100))
+	if len(id) > 36 {
+		t.Errorf("id %q is longer than the exchange's 36 characters", id)
+	}
+	tests := []struct {
+		name   string
+		symbol string
+		action string
+		data   *MarketData
+		same   bool
+	}{
+		// The forming candle's price changes between evaluations; the ID must not
+		{"same candle", "BTCUSDT", "BUY", candle(1000, 101), true},
+		{"next candle", "BTCUSDT", "BUY", candle(61000, 100), false},
+		{"other action", "BTCUSDT", "SELL", candle(1000, 100), false},
+		{"other symbol", "ETHUSDT", "BUY", candle(1000, 100), false},
+	}
+	for _, tt := range tests {
+		if got := e.signalID(tt.symbol, tt.action, tt.data); (got == id) != tt.same {
+			t.Errorf("%s: id %q against %q, want same = %v", tt.name, got, id, tt.same)
+		}
+	}
+}
+
+func TestDuplicateSignal(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	repo := &orderRepo{}
+	e := &Engine{strategy: &bandStrategy{}, repository: repo, logger: logger}
+
+	first := &MarketData{Klines: []*exchange.KlineData{{OpenTime: 0}}}
+	second := &MarketData{Klines: []*exchange.KlineData{{OpenTime: time.Hour.Milliseconds()}}}
+
+	signal := &Signal{Action: "BUY", ID: e.signalID("BTCUSDT", "BUY", first)}
+	if e.duplicateSignal(signal) {
+		t.Fatal("new signal reported as a duplicate")
+	}
+	repo.CreateOrder(&models.Order{ClientOrderID: signal.ID, Status: "FILLED"})
+
+	// Evaluated again within the candle it maps to the placed order
+	again := &Signal{Action: "BUY", ID: e.signalID("BTCUSDT", "BUY", first)}
+	if !e.duplicateSignal(again) {
+		t.Error("repeated signal within the candle not reported as a duplicate")
+	}
+	next := &Signal{Action: "BUY", ID: e.signalID("BTCUSDT", "BUY", second)}
+	if e.duplicateSignal(next) {
+		t.Error("signal of the next candle reported as a duplicate")
+	}
+	if e.duplicateSignal(&Signal{Action: "BUY"}) {
+		t.Error("signal without an ID reported as a duplicate")
+	}
+}
+
+func TestLockSymbol(t *testing.T) {
+	e := &Engine{symbolLocks: make(map[string]*sync.Mutex)}
+
+	unlock := e.lockSymbol("BTCUSDT")
+	// Another symbol proceeds while BTCUSDT is held
+	done := make(chan struct{})
+	go func() {
+		e.lockSymbol("ETHUSDT")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ETHUSDT waited for the BTCUSDT lock")
+	}
+
+	// The same symbol waits until it is released
+	acquired := make(chan struct{})
+	go func() {
+		e.lockSymbol("BTCUSDT")()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("BTCUSDT locked twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("BTCUSDT not acquired after release")
+	}
+}
@@ -0,0 +1,161 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+)
+
+// incomeDiscrepancy is a closed position whose recorded amount differs from the income
+// history by more than the tolerance
+type incomeDiscrepancy struct {
+	PositionID uint    `json:"position_id"`
+	Symbol     string  `json:"symbol"`
+	IncomeType string  `json:"income_type"`
+	Recorded   float64 `json:"recorded"` // Amount stored on the position
+	Exchange   float64 `json:"exchange"` // Sum of income entries while the position was open
+}
+
+// runIncomeSync copies new income history and reconciles positions closed since the last
+// successful run
+func (e *Engine) runIncomeSync(ctx context.Context) error {
+	syncedUntil, err := e.syncIncome(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to sync income history: %w", err)
+	}
+	if err := e.reconcileIncome(ctx, e.incomeReconciledUntil, syncedUntil); err != nil {
+		return fmt.Errorf("failed to reconcile income history: %w", err)
+	}
+	e.incomeReconciledUntil = syncedUntil
+	return nil
+}
+
+// syncIncome stores income entries newer than the latest stored one, starting backfill_days
+// ago when the table is empty, and returns the time the history is complete up to
+func (e *Engine) syncIncome(ctx context.Context) (time.Time, error) {
+	latest, err := e.repository.GetLatestIncomeTime()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get latest income time: %w", err)
+	}
+	start := latest
+	if start.IsZero() {
+		start = time.Now().AddDate(0, 0, -e.config.IncomeSync.BackfillDays)
+	}
+	end := time.Now()
+
+	stored := 0
+	startMs := start.UnixMilli()
+	for {
+		incomes, err := e.exchangeClient.GetIncomeHistory(ctx, "", "", startMs, end.UnixMilli(), incomeHistoryLimit)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		rows := make([]*models.Income, 0, len(incomes))
+		for _, income := range incomes {
+			rows = append(rows, incomeModel(income))
+		}
+		if err := e.repository.SaveIncomes(rows); err != nil {
+			return time.Time{}, fmt.Errorf("failed to save income history: %w", err)
+		}
+		stored += len(rows)
+
+		if len(incomes) < incomeHistoryLimit {
+			break
+		}
+		// A full page may have cut entries sharing the last timestamp; the next page starts at
+		// that timestamp and duplicates are ignored on insert
+		next := incomes[len(incomes)-1].Time
+		if next <= startMs {
+			next = startMs + 1
+		}
+		startMs = next
+	}
+
+	if stored > 0 {
+		e.logger.Infof("Synced %d income entries since %s", stored, start.Format(time.RFC3339))
+	}
+	return end, nil
+}
+
+func incomeModel(income *exchange.IncomeInfo) *models.Income {
+	return &models.Income{
+		TranID:     income.TranID,
+		IncomeType: income.IncomeType,
+		Symbol:     income.Symbol,
+		Income:     income.Income,
+		Asset:      income.Asset,
+		Info:       income.Info,
+		TradeID:    income.TradeID,
+		Time:       time.UnixMilli(income.Time),
+	}
+}
+
+// reconcileIncome compares the realized PnL, commission and funding stored on positions
+// closed in [start, end) with the stored income history, then audits and alerts on
+// differences above the tolerance
+func (e *Engine) reconcileIncome(ctx context.Context, start, end time.Time) error {
+	positions, err := e.repository.GetClosedPositions(start, end)
+	if err != nil {
+		return fmt.Errorf("failed to get closed positions: %w", err)
+	}
+
+	var discrepancies []*incomeDiscrepancy
+	for _, position := range positions {
+		if position.CloseTime == nil {
+			continue
+		}
+		for _, recorded := range []struct {
+			incomeType string
+			amount     float64
+		}{
+			{exchange.IncomeRealizedPnL, position.ClosedPnL},
+			{exchange.IncomeCommission, position.Commission},
+			{exchange.IncomeFundingFee, position.FundingFee},
+		} {
+			total, err := e.repository.SumIncome(position.Symbol, recorded.incomeType, position.OpenTime, *position.CloseTime)
+			if err != nil {
+				return fmt.Errorf("failed to sum %s income for %s: %w", recorded.incomeType, position.Symbol, err)
+			}
+			if math.Abs(total-recorded.amount) > e.config.IncomeSync.Tolerance {
+				discrepancies = append(discrepancies, &incomeDiscrepancy{
+					PositionID: position.ID,
+					Symbol:     position.Symbol,
+					IncomeType: recorded.incomeType,
+					Recorded:   recorded.amount,
+					Exchange:   total,
+				})
+			}
+		}
+	}
+
+	if len(discrepancies) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(discrepancies))
+	for _, d := range discrepancies {
+		e.logger.Warnf("Income discrepancy on position %d (%s) %s: recorded %.4f, exchange %.4f",
+			d.PositionID, d.Symbol, d.IncomeType, d.Recorded, d.Exchange)
+		e.recordAudit("income", "discrepancy", d.Symbol, d)
+		lines = append(lines, fmt.Sprintf("position %d %s %s: recorded %.4f, exchange %.4f",
+			d.PositionID, d.Symbol, d.IncomeType, d.Recorded, d.Exchange))
+	}
+
+	msg := &notify.Message{
+		Title: fmt.Sprintf("Income reconciliation found %d differences", len(discrepancies)),
+		Body:  strings.Join(lines, "\n"),
+		Level: notify.LevelWarning,
+		Time:  time.Now(),
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver income reconciliation notification: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/scheduler"
+)
+
+// registerJobs adds the engine's periodic work to the scheduler under the same enable
+// conditions the individual sections use
+func (e *Engine) registerJobs() error {
+	var jobs []scheduler.Job
+
+	if e.config.IncomeSync.Enabled && !e.config.EnablePaperTrading {
+		// Positions closed during the first interval are reconciled by the first run
+		e.incomeReconciledUntil = time.Now().Add(-time.Duration(e.config.IncomeSync.IntervalMinutes) * time.Minute)
+		jobs = append(jobs, scheduler.Job{
+			Name:       config.JobIncomeSync,
+			Spec:       e.jobSpec(config.JobIncomeSync, everyMinutes(e.config.IncomeSync.IntervalMinutes)),
+			RunOnStart: true,
+			Run:        e.runIncomeSync,
+		})
+	}
+	if e.config.Delisting.Enabled {
+		jobs = append(jobs, scheduler.Job{
+			Name:       config.JobSymbolRefresh,
+			Spec:       e.jobSpec(config.JobSymbolRefresh, everyMinutes(e.config.Delisting.IntervalMinutes)),
+			RunOnStart: true,
+			Run:        e.refreshSymbols,
+		})
+	}
+	if e.config.Drift.Enabled {
+		jobs = append(jobs, scheduler.Job{
+			Name: config.JobDriftCheck,
+			Spec: e.jobSpec(config.JobDriftCheck, everyMinutes(e.config.Drift.IntervalMinutes)),
+			Run:  e.checkDrift,
+		})
+	}
+	if e.config.DailyReport.Enabled {
+		jobs = append(jobs, scheduler.Job{
+			Name: config.JobDailyReport,
+			Spec: e.jobSpec(config.JobDailyReport, fmt.Sprintf("0 %d * * *", e.config.DailyReport.Hour)),
+			Run: func(ctx context.Context) error {
+				return e.sendDailyReport(ctx, startOfDay(time.Now(), e.dayLocation))
+			},
+		})
+	}
+	if e.config.RemoteConfig.Enabled {
+		jobs = append(jobs, scheduler.Job{
+			Name: config.JobRemoteConfig,
+			Spec: e.jobSpec(config.JobRemoteConfig, fmt.Sprintf("@every %ds", e.config.RemoteConfig.PollSeconds)),
+			Run:  e.syncRemoteConfig,
+		})
+	}
+	if e.config.MarginMonitor.Enabled {
+		jobs = append(jobs, scheduler.Job{
+			Name:       config.JobMarginMonitor,
+			Spec:       e.jobSpec(config.JobMarginMonitor, fmt.Sprintf("@every %ds", e.config.MarginMonitor.IntervalSeconds)),
+			RunOnStart: true,
+			Run:        e.checkMarginRatio,
+		})
+	}
+	if e.config.ADL.Enabled {
+		jobs = append(jobs, scheduler.Job{
+			Name:       config.JobADLMonitor,
+			Spec:       e.jobSpec(config.JobADLMonitor, fmt.Sprintf("@every %ds", e.config.ADL.IntervalSeconds)),
+			RunOnStart: true,
+			Run:        e.checkADL,
+		})
+	}
+	if e.config.Wallets.Enabled {
+		jobs = append(jobs, scheduler.Job{
+			Name:       config.JobWalletSnapshot,
+			Spec:       e.jobSpec(config.JobWalletSnapshot, everyMinutes(e.config.Wallets.SnapshotMinutes)),
+			RunOnStart: true,
+			Run:        e.snapshotWallets,
+		})
+	}
+	if e.drawdownScaler != nil {
+		jobs = append(jobs, scheduler.Job{
+			Name:       config.JobDrawdownScaling,
+			Spec:       e.jobSpec(config.JobDrawdownScaling, everyMinutes(e.config.DrawdownScaling.RefreshMinutes)),
+			RunOnStart: true,
+			Run:        e.drawdownScaler.Refresh,
+		})
+	}
+	if e.config.StrategyGuard.Enabled {
+		jobs = append(jobs, scheduler.Job{
+			Name:       config.JobStrategyGuard,
+			Spec:       e.jobSpec(config.JobStrategyGuard, everyMinutes(e.config.StrategyGuard.IntervalMinutes)),
+			RunOnStart: true,
+			Run:        e.checkStrategyGuard,
+		})
+	}
+	jobs = append(jobs,
+		scheduler.Job{
+			Name: config.JobRiskMetrics,
+			Spec: e.jobSpec(config.JobRiskMetrics, everyMinutes(e.config.RiskMetrics.IntervalMinutes)),
+			Run:  e.updateRiskMetrics,
+		},
+		scheduler.Job{
+			Name: config.JobAccountSnapshot,
+			Spec: e.jobSpec(config.JobAccountSnapshot, "@every 1m"),
+			Run:  e.updateAccountInfo,
+		},
+	)
+
+	for _, job := range jobs {
+		if err := e.scheduler.Register(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jobSpec returns the configured schedule override for a job, or its default
+func (e *Engine) jobSpec(name, fallback string) string {
+	if spec, ok := e.config.Scheduler.Jobs[name]; ok && spec != "" {
+		return spec
+	}
+	return fallback
+}
+
+func everyMinutes(minutes int) string {
+	return fmt.Sprintf("@every %dm", minutes)
+}
+
+// SchedulerStatus returns the run history and next run of every scheduled job
+func (e *Engine) SchedulerStatus() []scheduler.JobStatus {
+	return e.scheduler.Status()
+}
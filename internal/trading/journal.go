@@ -0,0 +1,145 @@
+package trading
+
+import (
+	"encoding/json"
+	"time"
+
+	"contract_playground/internal/models"
+)
+
+// Journal events
+const (
+	journalEntry = "entry"
+	journalAdd   = "add"
+	journalExit  = "exit"
+)
+
+// IndicatorStrategy is implemented by strategies that can report the indicator values behind
+// their latest decision for a symbol
+type IndicatorStrategy interface {
+	Indicators(symbol string) map[string]float64
+}
+
+// journalFeatures is the market data a strategy decides on, without the kline window
+type journalFeatures struct {
+	Price              float64   `json:"price"`
+	MarkPrice          float64   `json:"mark_price"`
+	Volume             float64   `json:"volume"`
+	ChangePercent      float64   `json:"change_percent"`
+	High24h            float64   `json:"high_24h"`
+	Low24h             float64   `json:"low_24h"`
+	QuoteVolume24h     float64   `json:"quote_volume_24h"`
+	FundingRate        float64   `json:"funding_rate"`
+	ATR                float64   `json:"atr"`
+	Sentiment          float64   `json:"sentiment"`
+	SentimentSamples   int       `json:"sentiment_samples"`
+	OpenInterest       float64   `json:"open_interest"`
+	OpenInterestChange float64   `json:"open_interest_change"`
+	LongShortRatio     float64   `json:"long_short_ratio"`
+	LongAccount        float64   `json:"long_account"`
+	ImpliedVolatility  float64   `json:"implied_volatility"`
+	VolatilitySkew     float64   `json:"volatility_skew"`
+	PutCallRatio       float64   `json:"put_call_ratio"`
+	BuyVolume          float64   `json:"buy_volume"`
+	SellVolume         float64   `json:"sell_volume"`
+	VolumeDelta        float64   `json:"volume_delta"`
+	LargeBuys          int       `json:"large_buys"`
+	LargeSells         int       `json:"large_sells"`
+	LongLiquidations   float64   `json:"long_liquidations"`
+	ShortLiquidations  float64   `json:"short_liquidations"`
+	Timestamp          time.Time `json:"timestamp"`
+}
+
+// journalTrade stores the features, indicators, strategy state and recent candles of the
+// symbol alongside the signal that entered, added to or exited the position. Failures are
+// logged; the journal never blocks trading.
+func (e *Engine) journalTrade(event string, position *models.Position, signal *Signal, price, quantity float64) {
+	if !e.config.Journal.Enabled {
+		return
+	}
+
+	symbol := position.Symbol
+	entry := &models.TradeJournal{
+		PositionID: position.ID,
+		Symbol:     symbol,
+		Event:      event,
+		Strategy:   e.strategy.Name(),
+		Reason:     signal.Reason,
+		Confidence: signal.Confidence,
+		Price:      price,
+		Quantity:   quantity,
+	}
+
+	if data, err := e.getMarketData(symbol); err != nil {
+		e.logger.Warnf("Journal entry for %s has no market data: %v", symbol, err)
+	} else {
+		entry.Features = journalJSON(&journalFeatures{
+			Price:              data.Price,
+			MarkPrice:          data.MarkPrice,
+			Volume:             data.Volume,
+			ChangePercent:      data.ChangePercent,
+			High24h:            data.High24h,
+			Low24h:             data.Low24h,
+			QuoteVolume24h:     data.QuoteVolume24h,
+			FundingRate:        data.FundingRate,
+			ATR:                data.ATR,
+			Sentiment:          data.Sentiment,
+			SentimentSamples:   data.SentimentSamples,
+			OpenInterest:       data.OpenInterest,
+			OpenInterestChange: data.OpenInterestChange,
+			LongShortRatio:     data.LongShortRatio,
+			LongAccount:        data.LongAccount,
+			ImpliedVolatility:  data.ImpliedVolatility,
+			VolatilitySkew:     data.VolatilitySkew,
+			PutCallRatio:       data.PutCallRatio,
+			BuyVolume:          data.BuyVolume,
+			SellVolume:         data.SellVolume,
+			VolumeDelta:        data.VolumeDelta,
+			LargeBuys:          data.LargeBuys,
+			LargeSells:         data.LargeSells,
+			LongLiquidations:   data.LongLiquidations,
+			ShortLiquidations:  data.ShortLiquidations,
+			Timestamp:          data.Timestamp,
+		})
+
+		klines := data.Klines
+		if window := e.config.Journal.KlineWindow; len(klines) > window {
+			klines = klines[len(klines)-window:]
+		}
+		entry.Klines = journalJSON(klines)
+	}
+
+	if e.indicatorStrategy != nil {
+		entry.Indicators = journalJSON(e.indicatorStrategy.Indicators(symbol))
+	}
+	if e.snapshotStrategy != nil {
+		if state, err := e.snapshotStrategy.SaveState(symbol); err != nil {
+			e.logger.Warnf("Failed to snapshot strategy state of %s for the journal: %v", symbol, err)
+		} else if state != nil {
+			entry.StrategyState = string(state)
+		}
+	}
+
+	if err := e.repository.CreateJournalEntry(entry); err != nil {
+		e.logger.Errorf("Failed to save journal entry for %s: %v", symbol, err)
+	}
+}
+
+func journalJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// JournalEntries returns the newest journal entries without their strategy state and klines,
+// optionally filtered by symbol and position
+func (e *Engine) JournalEntries(symbol string, positionID uint, limit int) ([]*models.TradeJournal, error) {
+	return e.repository.GetJournalEntries(symbol, positionID, limit)
+}
+
+// JournalEntry returns a complete journal entry
+func (e *Engine) JournalEntry(id uint) (*models.TradeJournal, error) {
+	return e.repository.GetJournalEntry(id)
+}
@@ -0,0 +1,109 @@
+package trading
+
+import (
+	"sync"
+
+	"contract_playground/internal/exchange"
+)
+
+// klineStore keeps a fixed-size ring of the most recent klines per symbol. Fetched windows
+// are merged by open time, so memory stays bounded however long the process runs and the
+// slices returned by the exchange client are not retained.
+type klineStore struct {
+	mu       sync.RWMutex
+	capacity int
+	rings    map[string]*klineRing
+}
+
+func newKlineStore(capacity int) *klineStore {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &klineStore{capacity: capacity, rings: make(map[string]*klineRing)}
+}
+
+// update merges a fetched window (oldest first) into the symbol's klines. A kline with the
+// open time of the latest stored one replaces it, since the forming candle keeps changing;
+// a window that does not overlap the stored klines replaces them, so no gap is hidden.
+func (s *klineStore) update(symbol string, klines []*exchange.KlineData) {
+	if len(klines) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ring := s.rings[symbol]
+	if ring == nil {
+		ring = &klineRing{klines: make([]*exchange.KlineData, s.capacity)}
+		s.rings[symbol] = ring
+	}
+	if last := ring.last(); last == nil || klines[0].OpenTime > last.OpenTime {
+		ring.reset()
+	}
+
+	for _, kline := range klines {
+		last := ring.last()
+		switch {
+		case last == nil || kline.OpenTime > last.OpenTime:
+			ring.push(kline)
+		case kline.OpenTime == last.OpenTime:
+			ring.replaceLast(kline)
+		}
+	}
+}
+
+// window returns a copy of the symbol's klines, oldest first
+func (s *klineStore) window(symbol string) []*exchange.KlineData {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ring := s.rings[symbol]
+	if ring == nil {
+		return nil
+	}
+	return ring.values()
+}
+
+// klineRing is a fixed-capacity ring of klines; pushing into a full ring overwrites the oldest
+type klineRing struct {
+	klines []*exchange.KlineData
+	next   int
+	count  int
+}
+
+func (r *klineRing) push(kline *exchange.KlineData) {
+	r.klines[r.next] = kline
+	r.next = (r.next + 1) % len(r.klines)
+	if r.count < len(r.klines) {
+		r.count++
+	}
+}
+
+func (r *klineRing) last() *exchange.KlineData {
+	if r.count == 0 {
+		return nil
+	}
+	return r.klines[(r.next-1+len(r.klines))%len(r.klines)]
+}
+
+func (r *klineRing) replaceLast(kline *exchange.KlineData) {
+	r.klines[(r.next-1+len(r.klines))%len(r.klines)] = kline
+}
+
+func (r *klineRing) reset() {
+	for i := range r.klines {
+		r.klines[i] = nil
+	}
+	r.next, r.count = 0, 0
+}
+
+// values returns the kept klines, oldest first
+func (r *klineRing) values() []*exchange.KlineData {
+	values := make([]*exchange.KlineData, r.count)
+	start := (r.next - r.count + len(r.klines)) % len(r.klines)
+	for i := range values {
+		values[i] = r.klines[(start+i)%len(r.klines)]
+	}
+	return values
+}
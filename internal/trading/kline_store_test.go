@@ -0,0 +1,47 @@
+package trading
+
+import (
+	"testing"
+
+	"contract_playground/internal/exchange"
+)
+
+// klineRange returns klines opening at minutes from..to, closing at close
+func klineRange(from, to int, close float64) []*exchange.KlineData {
+	var klines []*exchange.KlineData
+	for minute := from; minute <= to; minute++ {
+		klines = append(klines, &exchange.KlineData{OpenTime: int64(minute) * 60000, Close: close})
+	}
+	return klines
+}
+
+func TestKlineStoreMergesWindows(t *testing.T) {
+	store := newKlineStore(5)
+	store.update("BTCUSDT", klineRange(1, 4, 100))
+
+	// Overlapping window: the forming candle is replaced and new candles are appended,
+	// dropping the oldest beyond the capacity
+	store.update("BTCUSDT", klineRange(3, 7, 101))
+	window := store.window("BTCUSDT")
+	if len(window) != 5 {
+		t.Fatalf("kept %d klines, want 5", len(window))
+	}
+	for i, kline := range window {
+		if want := int64(3+i) * 60000; kline.OpenTime != want {
+			t.Fatalf("kline %d opens at %d, want %d", i, kline.OpenTime, want)
+		}
+	}
+	if window[1].Close != 101 {
+		t.Fatalf("forming candle was not replaced: close %v", window[1].Close)
+	}
+
+	// A window after a gap replaces the stored klines
+	store.update("BTCUSDT", klineRange(20, 21, 102))
+	if window := store.window("BTCUSDT"); len(window) != 2 || window[0].OpenTime != 20*60000 {
+		t.Fatalf("gap was not reset: %d klines from %d", len(window), window[0].OpenTime)
+	}
+
+	if window := store.window("ETHUSDT"); window != nil {
+		t.Fatalf("unknown symbol returned %d klines", len(window))
+	}
+}
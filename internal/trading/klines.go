@@ -0,0 +1,45 @@
+package trading
+
+import (
+	"math"
+
+	"contract_playground/internal/exchange"
+	"contract_playground/pkg/utils"
+)
+
+// closedKlines drops the still-forming last candle returned by the exchange
+func closedKlines(klines []*exchange.KlineData) []*exchange.KlineData {
+	if len(klines) == 0 {
+		return klines
+	}
+	return klines[:len(klines)-1]
+}
+
+// highestHigh returns the highest high of the candles
+func highestHigh(klines []*exchange.KlineData) float64 {
+	high := 0.0
+	for _, k := range klines {
+		high = math.Max(high, k.High)
+	}
+	return high
+}
+
+// lowestLow returns the lowest low of the candles
+func lowestLow(klines []*exchange.KlineData) float64 {
+	low := math.Inf(1)
+	for _, k := range klines {
+		low = math.Min(low, k.Low)
+	}
+	return low
+}
+
+// klinesATR returns the Average True Range of the candles, or 0 when there are too few
+func klinesATR(klines []*exchange.KlineData, period int) float64 {
+	highs := make([]float64, len(klines))
+	lows := make([]float64, len(klines))
+	closes := make([]float64, len(klines))
+	for i, k := range klines {
+		highs[i], lows[i], closes[i] = k.High, k.Low, k.Close
+	}
+	return utils.CalculateATR(highs, lows, closes, period)
+}
@@ -0,0 +1,170 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"contract_playground/internal/notify"
+	"contract_playground/pkg/utils"
+)
+
+// Volatility regimes
+const (
+	regimeLow    = "low"
+	regimeNormal = "normal"
+	regimeHigh   = "high"
+)
+
+// volatilityRegime is the latest classification of a symbol's volatility
+type volatilityRegime struct {
+	Symbol     string  `json:"symbol"`
+	Regime     string  `json:"regime"`
+	Volatility float64 `json:"volatility"` // Standard deviation of the window's returns
+	Percentile float64 `json:"percentile"` // Rank of Volatility among the lookback's values
+	Leverage   int     `json:"leverage"`
+	Previous   int     `json:"previous_leverage"`
+}
+
+// symbolLeverage returns the leverage the symbol is meant to have on the exchange
+func (e *Engine) symbolLeverage(symbol string) int {
+	e.leverageMu.Lock()
+	defer e.leverageMu.Unlock()
+
+	if leverage, ok := e.leverages[symbol]; ok {
+		return leverage
+	}
+	return e.riskManager.Limits().MaxLeverage
+}
+
+func (e *Engine) setSymbolLeverage(symbol string, leverage int) {
+	e.leverageMu.Lock()
+	e.leverages[symbol] = leverage
+	delete(e.leverageFailures, symbol)
+	e.leverageMu.Unlock()
+}
+
+// leverageFailed records a failed change and reports whether it is the first failure for
+// this target, so a change that keeps failing alerts once
+func (e *Engine) leverageFailed(symbol string, leverage int) bool {
+	e.leverageMu.Lock()
+	defer e.leverageMu.Unlock()
+
+	first := e.leverageFailures[symbol] != leverage
+	e.leverageFailures[symbol] = leverage
+	return first
+}
+
+// monitorVolatilityRegime classifies each symbol's volatility regime periodically and moves
+// its leverage to the regime's value
+func (e *Engine) monitorVolatilityRegime(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(e.config.LeverageRegime.IntervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		for _, symbol := range e.config.Symbols {
+			if err := e.adjustLeverage(ctx, symbol); err != nil {
+				e.logger.Errorf("Failed to adjust leverage for %s: %v", symbol, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// adjustLeverage sets the symbol's leverage for its current regime when it differs
+func (e *Engine) adjustLeverage(ctx context.Context, symbol string) error {
+	regime, err := e.classifyVolatility(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	regime.Previous = e.symbolLeverage(symbol)
+	if regime.Leverage == regime.Previous {
+		return nil
+	}
+
+	if err := e.exchangeClient.SetLeverage(ctx, symbol, regime.Leverage); err != nil {
+		// Lowering leverage fails when the open position lacks margin for it
+		if e.leverageFailed(symbol, regime.Leverage) {
+			e.recordAudit("leverage", "regime_change_failed", symbol, regime)
+			e.notifyLeverageChange(ctx, regime, err)
+		}
+		return fmt.Errorf("failed to set leverage to %d: %w", regime.Leverage, err)
+	}
+	e.setSymbolLeverage(symbol, regime.Leverage)
+
+	e.logger.Infof("Leverage for %s changed from %d to %d: %s volatility regime (%.4f, percentile %.0f)",
+		symbol, regime.Previous, regime.Leverage, regime.Regime, regime.Volatility, regime.Percentile)
+	e.recordAudit("leverage", "regime_change", symbol, regime)
+	e.notifyLeverageChange(ctx, regime, nil)
+	return nil
+}
+
+// classifyVolatility ranks the realized volatility of the latest window of closed candles
+// among the values of the preceding windows
+func (e *Engine) classifyVolatility(ctx context.Context, symbol string) (*volatilityRegime, error) {
+	cfg := e.config.LeverageRegime
+	klines, err := e.exchangeClient.GetKlines(ctx, symbol, cfg.KlineInterval, cfg.Window+cfg.Lookback+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get klines: %w", err)
+	}
+	klines = closedKlines(klines)
+	if len(klines) < cfg.Window+2 {
+		return nil, fmt.Errorf("only %d closed klines, need at least %d", len(klines), cfg.Window+2)
+	}
+
+	closes := make([]float64, len(klines))
+	for i, kline := range klines {
+		closes[i] = kline.Close
+	}
+	volatilities := make([]float64, 0, len(closes)-cfg.Window)
+	for i := cfg.Window; i < len(closes); i++ {
+		volatilities = append(volatilities, utils.CalculateVolatility(closes[i-cfg.Window:i+1]))
+	}
+
+	current := volatilities[len(volatilities)-1]
+	below := 0
+	for _, volatility := range volatilities {
+		if volatility <= current {
+			below++
+		}
+	}
+
+	regime := &volatilityRegime{
+		Symbol:     symbol,
+		Regime:     regimeNormal,
+		Volatility: current,
+		Percentile: float64(below) / float64(len(volatilities)) * 100,
+		Leverage:   (cfg.MinLeverage + cfg.MaxLeverage) / 2,
+	}
+	switch {
+	case regime.Percentile <= cfg.LowPercentile:
+		regime.Regime, regime.Leverage = regimeLow, cfg.MaxLeverage
+	case regime.Percentile >= cfg.HighPercentile:
+		regime.Regime, regime.Leverage = regimeHigh, cfg.MinLeverage
+	}
+	return regime, nil
+}
+
+func (e *Engine) notifyLeverageChange(ctx context.Context, regime *volatilityRegime, err error) {
+	msg := &notify.Message{
+		Title: fmt.Sprintf("%s leverage %d -> %d (%s volatility)", regime.Symbol, regime.Previous, regime.Leverage, regime.Regime),
+		Body: fmt.Sprintf("Realized volatility %.4f is at the %.0fth percentile of the last %d windows",
+			regime.Volatility, regime.Percentile, e.config.LeverageRegime.Lookback),
+		Level:  notify.LevelInfo,
+		Symbol: regime.Symbol,
+		Time:   time.Now(),
+	}
+	if err != nil {
+		msg.Title = fmt.Sprintf("%s leverage change to %d failed (%s volatility)", regime.Symbol, regime.Leverage, regime.Regime)
+		msg.Body += fmt.Sprintf("\nError: %v", err)
+		msg.Level = notify.LevelWarning
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver leverage notification: %v", err)
+	}
+}
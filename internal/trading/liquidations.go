@@ -0,0 +1,149 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"contract_playground/internal/events"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+)
+
+// liquidationVolume is the notional liquidated on each side within the rolling window
+type liquidationVolume struct {
+	longs  float64 // Long positions liquidated (SELL orders)
+	shorts float64 // Short positions liquidated (BUY orders)
+}
+
+func (v liquidationVolume) total() float64 {
+	return v.longs + v.shorts
+}
+
+// liquidationHandler stores streamed liquidations and raises the volatility alarm
+type liquidationHandler struct {
+	engine *Engine
+	ctx    context.Context
+}
+
+// OnLiquidation records the liquidation
+func (h *liquidationHandler) OnLiquidation(liquidation *exchange.Liquidation) {
+	h.engine.recordLiquidation(h.ctx, liquidation)
+}
+
+// OnError logs stream errors; the stream reconnects on its own
+func (h *liquidationHandler) OnError(err error) {
+	h.engine.logger.Warnf("Liquidation stream error: %v", err)
+}
+
+// recordLiquidation persists the liquidation, adds it to the symbol's window and alerts once
+// when the window's volume reaches alarm_notional
+func (e *Engine) recordLiquidation(ctx context.Context, liquidation *exchange.Liquidation) {
+	price := fillPrice(liquidation)
+	if err := e.repository.SaveLiquidation(&models.Liquidation{
+		Symbol:   liquidation.Symbol,
+		Side:     liquidation.Side,
+		Price:    price,
+		Quantity: liquidation.Quantity,
+		Notional: price * liquidation.Quantity,
+		Time:     time.UnixMilli(liquidation.Time),
+	}); err != nil {
+		e.logger.Errorf("Failed to save liquidation for %s: %v", liquidation.Symbol, err)
+	}
+
+	e.liquidationMu.Lock()
+	recent := append(e.liquidationEvents[liquidation.Symbol], liquidation)
+	if limit := e.config.Retention.MaxLiquidationEvents; limit > 0 && len(recent) > limit {
+		// Copy so the dropped events' backing array can be released
+		recent = append([]*exchange.Liquidation(nil), recent[len(recent)-limit:]...)
+	}
+	e.liquidationEvents[liquidation.Symbol] = recent
+	e.liquidationMu.Unlock()
+
+	alarm := e.config.Liquidations.AlarmNotional
+	volume := e.liquidationVolume(liquidation.Symbol, time.Now())
+	if alarm <= 0 || volume.total() < alarm {
+		return
+	}
+
+	e.liquidationMu.Lock()
+	raised := !e.liquidationAlarms[liquidation.Symbol]
+	e.liquidationAlarms[liquidation.Symbol] = true
+	e.liquidationMu.Unlock()
+	if !raised {
+		return
+	}
+
+	details := map[string]interface{}{
+		"long_liquidations":  volume.longs,
+		"short_liquidations": volume.shorts,
+		"window_seconds":     e.config.Liquidations.WindowSeconds,
+	}
+	e.logger.Warnf("Heavy liquidations on %s: %.0f USDT of longs and %.0f USDT of shorts in %ds; new entries are blocked",
+		liquidation.Symbol, volume.longs, volume.shorts, e.config.Liquidations.WindowSeconds)
+	e.recordAudit("liquidation", "alarm", liquidation.Symbol, details)
+	e.publishEvent(events.TypeRisk, "liquidation_alarm", liquidation.Symbol, details)
+
+	msg := &notify.Message{
+		Title: fmt.Sprintf("Liquidation cascade on %s", liquidation.Symbol),
+		Body: fmt.Sprintf("%.0f USDT of longs and %.0f USDT of shorts liquidated in the last %ds. New entries are blocked until the volume drops below %.0f USDT.",
+			volume.longs, volume.shorts, e.config.Liquidations.WindowSeconds, alarm),
+		Level:  notify.LevelWarning,
+		Symbol: liquidation.Symbol,
+		Time:   time.Now(),
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver liquidation notification: %v", err)
+	}
+}
+
+// liquidationVolume drops liquidations older than the window and sums the rest per side
+func (e *Engine) liquidationVolume(symbol string, now time.Time) liquidationVolume {
+	since := now.Add(-time.Duration(e.config.Liquidations.WindowSeconds) * time.Second).UnixMilli()
+
+	e.liquidationMu.Lock()
+	defer e.liquidationMu.Unlock()
+
+	recent := e.liquidationEvents[symbol]
+	for len(recent) > 0 && recent[0].Time < since {
+		recent = recent[1:]
+	}
+	e.liquidationEvents[symbol] = recent
+
+	var volume liquidationVolume
+	for _, liquidation := range recent {
+		notional := fillPrice(liquidation) * liquidation.Quantity
+		if liquidation.Side == "SELL" {
+			volume.longs += notional
+		} else {
+			volume.shorts += notional
+		}
+	}
+	return volume
+}
+
+// fillPrice is the average fill price of the liquidation, or its order price before fills
+func fillPrice(liquidation *exchange.Liquidation) float64 {
+	if liquidation.AvgPrice > 0 {
+		return liquidation.AvgPrice
+	}
+	return liquidation.Price
+}
+
+// liquidationFilter returns a reason while the symbol's liquidation volume is above the alarm
+func (e *Engine) liquidationFilter(symbol string) string {
+	alarm := e.config.Liquidations.AlarmNotional
+	if !e.config.Liquidations.Enabled || alarm <= 0 {
+		return ""
+	}
+
+	volume := e.liquidationVolume(symbol, time.Now())
+	if volume.total() < alarm {
+		e.liquidationMu.Lock()
+		delete(e.liquidationAlarms, symbol)
+		e.liquidationMu.Unlock()
+		return ""
+	}
+	return fmt.Sprintf("%.0f USDT liquidated in the last %ds", volume.total(), e.config.Liquidations.WindowSeconds)
+}
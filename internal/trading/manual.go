@@ -0,0 +1,528 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"contract_playground/internal/database"
+	"contract_playground/internal/events"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Manual orders are recorded under this strategy name
+const manualStrategy = "manual"
+
+// Child order roles of manual orders attached to a position
+const (
+	manualBuyRole  = "MANUAL_BUY"
+	manualSellRole = "MANUAL_SELL"
+)
+
+// ManualOrderRequest is an operator order that bypasses the strategy
+type ManualOrderRequest struct {
+	Symbol      string  `json:"symbol"`
+	Side        string  `json:"side"` // BUY adds to or opens the long position, SELL reduces it
+	Type        string  `json:"type"` // MARKET or LIMIT
+	Quantity    float64 `json:"quantity"`
+	Price       float64 `json:"price,omitempty"`         // LIMIT only
+	TimeInForce string  `json:"time_in_force,omitempty"` // LIMIT only, defaults to GTC
+}
+
+// PlaceManualOrder validates an operator order with the risk manager, places it and records
+// it with strategy "manual". Fills are applied to the position like take-profit and safety
+// order fills: a SELL is reduce-only and needs an open position.
+func (e *Engine) PlaceManualOrder(ctx context.Context, req *ManualOrderRequest) (*models.Order, error) {
+	if e.config.EnablePaperTrading {
+		return nil, fmt.Errorf("manual orders are disabled in paper trading mode")
+	}
+
+	req.Symbol = strings.ToUpper(strings.TrimSpace(req.Symbol))
+	req.Side = strings.ToUpper(req.Side)
+	req.Type = strings.ToUpper(req.Type)
+	if err := validateManualOrder(req); err != nil {
+		return nil, err
+	}
+
+	defer e.lockSymbol(req.Symbol)()
+
+	position, err := e.repository.GetPosition(req.Symbol, "LONG")
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get position for %s: %w", req.Symbol, err)
+	}
+	if position != nil && position.Status != "OPEN" {
+		position = nil
+	}
+
+	e.entryMu.Lock()
+	defer e.entryMu.Unlock()
+
+	if err := e.checkManualOrder(ctx, req, position); err != nil {
+		e.publishEvent(events.TypeRisk, "rejected", req.Symbol, req)
+		return nil, err
+	}
+
+	order, err := e.submitManualOrder(ctx, req, position)
+	if err != nil {
+		return nil, err
+	}
+
+	e.recordAudit("manual_order", "place", req.Symbol, req)
+	e.reconcileManualFills(ctx, req.Symbol, position)
+	return e.reloadOrder(order), nil
+}
+
+// ModifyManualOrder amends the quantity and price of an open manual limit order in place,
+// keeping its exchange order ID. The quantity is the new total including fills so far.
+func (e *Engine) ModifyManualOrder(ctx context.Context, id uint, quantity, price float64) (*models.Order, error) {
+	order, unlock, err := e.lockManualOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	if order.Type != "LIMIT" {
+		return nil, fmt.Errorf("only limit orders can be modified")
+	}
+
+	req := &ManualOrderRequest{
+		Symbol:      order.Symbol,
+		Side:        order.Side,
+		Type:        order.Type,
+		Quantity:    quantity,
+		Price:       price,
+		TimeInForce: order.TimeInForce,
+	}
+	if err := validateManualOrder(req); err != nil {
+		return nil, err
+	}
+	orderID, err := strconv.ParseInt(order.ExchangeOrderID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exchange order id %s: %w", order.ExchangeOrderID, err)
+	}
+
+	// Apply fills so far, so the remainder is checked against the current position
+	position, err := e.manualOrderPosition(order)
+	if err != nil {
+		return nil, err
+	}
+	e.reconcileManualFills(ctx, order.Symbol, position)
+	order = e.reloadOrder(order)
+	if isFinalOrderStatus(order.Status) {
+		return nil, fmt.Errorf("order %d is already %s", id, order.Status)
+	}
+	if roundQuantity(quantity) <= roundQuantity(order.ExecutedQty) {
+		return nil, fmt.Errorf("quantity must exceed the filled %.6f", order.ExecutedQty)
+	}
+	if position, err = e.manualOrderPosition(order); err != nil {
+		return nil, err
+	}
+
+	e.entryMu.Lock()
+	defer e.entryMu.Unlock()
+	remainder := *req
+	remainder.Quantity = quantity - order.ExecutedQty
+	if err := e.checkManualOrder(ctx, &remainder, position); err != nil {
+		e.publishEvent(events.TypeRisk, "rejected", req.Symbol, req)
+		return nil, err
+	}
+
+	response, err := e.modifyOrder(ctx, &exchange.ModifyOrderRequest{
+		Symbol:   order.Symbol,
+		OrderID:  orderID,
+		Side:     order.Side,
+		Quantity: quantity,
+		Price:    price,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to modify manual order: %w", err)
+	}
+
+	order.Quantity = response.OrigQty
+	order.Price = response.Price
+	if err := e.repository.UpdateOrder(order); err != nil {
+		e.logger.Errorf("Failed to update manual order for %s: %v", order.Symbol, err)
+	}
+
+	e.logger.Infof("Modified manual order %d for %s: quantity=%.6f, price=%.6f", order.ID, order.Symbol, quantity, price)
+	e.publishEvent(events.TypeOrder, "modified", order.Symbol, order)
+	e.recordAudit("manual_order", "modify", order.Symbol, map[string]interface{}{
+		"order_id": order.ID,
+		"quantity": quantity,
+		"price":    price,
+	})
+	e.reconcileManualFills(ctx, order.Symbol, position)
+	return e.reloadOrder(order), nil
+}
+
+// CancelManualOrder cancels an open manual order
+func (e *Engine) CancelManualOrder(ctx context.Context, id uint) error {
+	order, unlock, err := e.lockManualOrder(id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := e.cancelManualOrder(ctx, order); err != nil {
+		return err
+	}
+
+	e.recordAudit("manual_order", "cancel", order.Symbol, map[string]interface{}{"order_id": order.ID})
+	return nil
+}
+
+// ManualOrders returns the most recent manual orders, optionally for one symbol
+func (e *Engine) ManualOrders(symbol string, limit int) ([]*models.Order, error) {
+	orders, err := e.repository.GetOrderHistory(symbol, limit*4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order history: %w", err)
+	}
+
+	manual := make([]*models.Order, 0, limit)
+	for _, order := range orders {
+		if order.Strategy == manualStrategy && len(manual) < limit {
+			manual = append(manual, order)
+		}
+	}
+	return manual, nil
+}
+
+func validateManualOrder(req *ManualOrderRequest) error {
+	if req.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+	if req.Side != "BUY" && req.Side != "SELL" {
+		return fmt.Errorf("side must be BUY or SELL")
+	}
+	if req.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+
+	switch req.Type {
+	case "MARKET":
+		if req.Price != 0 {
+			return fmt.Errorf("market orders take no price")
+		}
+	case "LIMIT":
+		if req.Price <= 0 {
+			return fmt.Errorf("limit orders need a positive price")
+		}
+		if req.TimeInForce == "" {
+			req.TimeInForce = "GTC"
+		}
+	default:
+		return fmt.Errorf("type must be MARKET or LIMIT")
+	}
+	return nil
+}
+
+// checkManualOrder runs the risk manager on buys and checks that sells only reduce the position
+func (e *Engine) checkManualOrder(ctx context.Context, req *ManualOrderRequest, position *models.Position) error {
+	if req.Side == "SELL" {
+		if position == nil {
+			return fmt.Errorf("no open position for %s to sell", req.Symbol)
+		}
+		if !e.riskManager.ValidateReduceOnly(e.orderInfo(req.Symbol, "SELL", req.Quantity, req.Price), position.Size) {
+			return fmt.Errorf("sell quantity %.6f exceeds position size %.6f", req.Quantity, position.Size)
+		}
+		return nil
+	}
+
+	if reason := e.delistingFilter(req.Symbol); reason != "" {
+		return fmt.Errorf("buy rejected: %s", reason)
+	}
+	if reason := e.marginFilter(); reason != "" {
+		return fmt.Errorf("buy rejected: %s", reason)
+	}
+
+	price := req.Price
+	if price == 0 {
+		current, err := e.exchangeClient.GetSymbolPrice(ctx, req.Symbol)
+		if err != nil {
+			return fmt.Errorf("failed to get price for %s: %w", req.Symbol, err)
+		}
+		price = current
+	}
+
+	orderInfo := e.orderInfo(req.Symbol, "BUY", req.Quantity, price)
+	if !e.riskManager.ValidateOrder(ctx, orderInfo) || !e.validateExposure(orderInfo) {
+		return fmt.Errorf("order rejected by risk manager")
+	}
+	if reason := e.walletFilter(manualStrategy, orderInfo); reason != "" {
+		return fmt.Errorf("buy rejected: %s", reason)
+	}
+	return nil
+}
+
+// submitManualOrder places the order and records it. The order is stored without fills so
+// that reconcileManualFills applies them to the position exactly once.
+func (e *Engine) submitManualOrder(ctx context.Context, req *ManualOrderRequest, position *models.Position) (*models.Order, error) {
+	request := &exchange.OrderRequest{
+		Symbol:           req.Symbol,
+		Side:             req.Side,
+		Type:             req.Type,
+		Quantity:         req.Quantity,
+		Price:            req.Price,
+		ReduceOnly:       req.Side == "SELL",
+		PositionSide:     "BOTH",
+		NewClientOrderID: fmt.Sprintf("manual_%s_%d", req.Symbol, time.Now().UnixMilli()),
+	}
+	if req.Type == "LIMIT" {
+		request.TimeInForce = req.TimeInForce
+	}
+
+	response, err := e.placeOrder(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place manual order: %w", err)
+	}
+
+	role := manualBuyRole
+	if req.Side == "SELL" {
+		role = manualSellRole
+	}
+	order := &models.Order{
+		ExchangeOrderID: fmt.Sprintf("%d", response.OrderID),
+		ClientOrderID:   response.ClientOrderID,
+		Symbol:          response.Symbol,
+		Side:            response.Side,
+		Type:            response.Type,
+		Status:          "NEW",
+		Quantity:        response.OrigQty,
+		Price:           response.Price,
+		TimeInForce:     response.TimeInForce,
+		ReduceOnly:      request.ReduceOnly,
+		PositionSide:    response.PositionSide,
+		Role:            role,
+		Strategy:        manualStrategy,
+		Notes:           "manual order",
+	}
+	if position != nil {
+		positionID := position.ID
+		order.PositionID = &positionID
+	}
+	if err := e.repository.CreateOrder(order); err != nil {
+		return nil, fmt.Errorf("failed to save manual order: %w", err)
+	}
+
+	e.logger.Infof("Placed manual %s %s order for %s: quantity=%.6f, price=%.6f",
+		req.Type, req.Side, req.Symbol, req.Quantity, req.Price)
+	e.publishEvent(events.TypeOrder, "placed", req.Symbol, order)
+	return order, nil
+}
+
+// openManualOrder loads a manual order that can still be modified or canceled
+func (e *Engine) openManualOrder(id uint) (*models.Order, error) {
+	order, err := e.repository.GetOrder(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %d: %w", id, err)
+	}
+	if order.Strategy != manualStrategy {
+		return nil, fmt.Errorf("order %d is not a manual order", id)
+	}
+	if isFinalOrderStatus(order.Status) {
+		return nil, fmt.Errorf("order %d is already %s", id, order.Status)
+	}
+	return order, nil
+}
+
+// lockManualOrder takes the lock of the order's symbol and returns the order re-read under it
+func (e *Engine) lockManualOrder(id uint) (*models.Order, func(), error) {
+	order, err := e.openManualOrder(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unlock := e.lockSymbol(order.Symbol)
+	if order, err = e.openManualOrder(id); err != nil {
+		unlock()
+		return nil, nil, err
+	}
+	return order, unlock, nil
+}
+
+// cancelManualOrder cancels the order on the exchange and applies any fills it had
+func (e *Engine) cancelManualOrder(ctx context.Context, order *models.Order) error {
+	orderID, err := strconv.ParseInt(order.ExchangeOrderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid exchange order id %s: %w", order.ExchangeOrderID, err)
+	}
+	if err := e.cancelOrder(ctx, order.Symbol, orderID); err != nil {
+		return fmt.Errorf("failed to cancel manual order: %w", err)
+	}
+
+	position, err := e.manualOrderPosition(order)
+	if err != nil {
+		return err
+	}
+	e.reconcileManualFills(ctx, order.Symbol, position)
+
+	// Reconciliation skips orders of a position that has since closed
+	order = e.reloadOrder(order)
+	if !isFinalOrderStatus(order.Status) {
+		order.Status = "CANCELED"
+		if err := e.repository.UpdateOrder(order); err != nil {
+			e.logger.Errorf("Failed to update manual order for %s: %v", order.Symbol, err)
+		}
+	}
+
+	e.publishEvent(events.TypeOrder, "canceled", order.Symbol, order)
+	return nil
+}
+
+// reloadOrder returns the stored order with fills applied by reconciliation
+func (e *Engine) reloadOrder(order *models.Order) *models.Order {
+	updated, err := e.repository.GetOrder(order.ID)
+	if err != nil {
+		return order
+	}
+	return updated
+}
+
+// manualOrderPosition returns the open position a manual order is attached to, if any
+func (e *Engine) manualOrderPosition(order *models.Order) (*models.Position, error) {
+	position, err := e.repository.GetPosition(order.Symbol, "LONG")
+	if err == gorm.ErrRecordNotFound || (err == nil && position.Status != "OPEN") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get position for %s: %w", order.Symbol, err)
+	}
+	return position, nil
+}
+
+// reconcileManualFills applies manual order fills: through the child orders of an open
+// position, or by opening a position from manual buys placed while flat
+func (e *Engine) reconcileManualFills(ctx context.Context, symbol string, position *models.Position) {
+	if position != nil {
+		if _, err := e.reconcileChildOrders(ctx, position); err != nil {
+			e.logger.Errorf("Failed to reconcile manual orders for %s: %v", symbol, err)
+		}
+		return
+	}
+
+	if err := e.reconcileManualOrders(ctx, symbol); err != nil {
+		e.logger.Errorf("Failed to reconcile manual orders for %s: %v", symbol, err)
+	}
+}
+
+// reconcileManualOrders opens a position from a manual buy placed while flat once it fills.
+// The order then becomes a child order of the position, so later fills are applied by
+// reconcileChildOrders.
+func (e *Engine) reconcileManualOrders(ctx context.Context, symbol string) error {
+	orders, err := e.repository.GetOpenOrders(symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get open orders: %w", err)
+	}
+
+	for _, order := range orders {
+		if order.Strategy != manualStrategy || order.PositionID != nil {
+			continue
+		}
+
+		orderID, err := strconv.ParseInt(order.ExchangeOrderID, 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := e.exchangeClient.GetOrder(ctx, order.Symbol, orderID)
+		if err != nil {
+			e.logger.Errorf("Failed to get manual order for %s: %v", order.Symbol, err)
+			continue
+		}
+
+		// The fill is applied to the position together with the order that carries it, so a
+		// failed write cannot apply it twice on the next pass
+		var opened *models.Position
+		err = e.repository.WithTx(func(tx database.Repository) error {
+			if info.ExecutedQty > 0 && order.Side == "BUY" {
+				position, created, err := e.saveManualFill(tx, order, info)
+				if err != nil {
+					return err
+				}
+				if created {
+					opened = position
+				}
+				positionID := position.ID
+				order.PositionID = &positionID
+			}
+
+			order.Status = info.Status
+			order.ExecutedQty = info.ExecutedQty
+			order.CumulativeQuote = info.CumQuote
+			if err := tx.UpdateOrder(order); err != nil {
+				return fmt.Errorf("failed to update manual order: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if opened != nil {
+			e.manualPositionOpened(ctx, opened)
+		}
+	}
+
+	return nil
+}
+
+// saveManualFill opens a position from the filled part of a manual buy, or adds to a
+// position opened by an earlier order in the same pass, and reports whether it was opened
+func (e *Engine) saveManualFill(tx database.Repository, order *models.Order, info *exchange.OrderInfo) (*models.Position, bool, error) {
+	position, err := e.manualOrderPosition(order)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if position != nil {
+		size := position.Size + info.ExecutedQty
+		position.EntryPrice = e.averageEntry(position.Symbol, position.EntryPrice, position.Size, info.AvgPrice, info.ExecutedQty)
+		position.Size = size
+		position.LastAddPrice = info.AvgPrice
+		if err := tx.UpdatePosition(position); err != nil {
+			return nil, false, fmt.Errorf("failed to update position: %w", err)
+		}
+		return position, false, nil
+	}
+
+	position = &models.Position{
+		Symbol:       order.Symbol,
+		PositionSide: "LONG",
+		Size:         info.ExecutedQty,
+		EntryPrice:   info.AvgPrice,
+		LastAddPrice: info.AvgPrice,
+		Leverage:     e.symbolLeverage(order.Symbol),
+		Status:       "OPEN",
+		OpenTime:     time.Now(),
+		Strategy:     manualStrategy,
+	}
+	position.TakeProfitPlan = e.newTakeProfitLadder(&Signal{}).encode()
+	if err := tx.CreatePosition(position); err != nil {
+		return nil, false, fmt.Errorf("failed to save position: %w", err)
+	}
+	if err := e.enqueueEvent(tx, events.TypePosition, "opened", position.Symbol, position); err != nil {
+		return nil, false, err
+	}
+	return position, true, nil
+}
+
+// manualPositionOpened follows up a position opened from a manual buy once it is saved
+func (e *Engine) manualPositionOpened(ctx context.Context, position *models.Position) {
+	e.logger.Infof("Opened manual position for %s: size=%.6f, entry=%.6f", position.Symbol, position.Size, position.EntryPrice)
+	e.publishEvent(events.TypePosition, "opened", position.Symbol, position)
+	e.placeTakeProfitOrders(ctx, position)
+	e.refreshPnLAlerts()
+	e.saveCooldown(e.riskManager.RecordEntry(position.Symbol, position.OpenTime))
+}
+
+// addsToPosition reports whether a child order's fills increase the position
+func addsToPosition(order *models.Order) bool {
+	return isSafetyOrder(order) || order.Role == manualBuyRole
+}
+
+// roundQuantity avoids float noise when comparing manual quantities to position sizes
+func roundQuantity(quantity float64) float64 {
+	return math.Round(quantity*1e8) / 1e8
+}
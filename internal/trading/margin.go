@@ -0,0 +1,215 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"contract_playground/internal/auditlog"
+	"contract_playground/internal/config"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+)
+
+// MarginStatus is the latest account margin ratio and the margin monitor stage it reached
+type MarginStatus struct {
+	RatioPercent  float64   `json:"ratio_percent"` // Maintenance margin over margin balance; liquidation at 100
+	MaintMargin   float64   `json:"maint_margin"`
+	MarginBalance float64   `json:"margin_balance"`
+	Stage         int       `json:"stage"`            // Index into the configured stages, -1 below the first
+	Action        string    `json:"action,omitempty"` // Action of the stage
+	StagePercent  float64   `json:"stage_percent,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// marginRatio returns the account margin ratio in percent. An account without margin balance
+// but with maintenance margin is treated as liquidating.
+func marginRatio(account *exchange.AccountInfo) float64 {
+	switch {
+	case account.TotalMaintMargin <= 0:
+		return 0
+	case account.TotalMarginBalance <= 0:
+		return 100
+	}
+	return account.TotalMaintMargin / account.TotalMarginBalance * 100
+}
+
+// marginStage returns the index of the highest stage the ratio has reached, or -1
+func marginStage(stages []config.MarginStageConfig, ratio float64) int {
+	stage := -1
+	for i, s := range stages {
+		if ratio >= s.RatioPercent {
+			stage = i
+		}
+	}
+	return stage
+}
+
+// marginSeverity ranks an action; the empty action of no stage ranks below warn
+func marginSeverity(action string) int {
+	return slices.Index(config.MarginActions, action)
+}
+
+// checkMarginRatio refreshes the margin ratio, reports stage changes and applies the
+// de-risking action of the current stage
+func (e *Engine) checkMarginRatio(ctx context.Context) error {
+	account, err := e.exchangeClient.GetAccountInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get account info: %w", err)
+	}
+
+	stages := e.config.MarginMonitor.Stages
+	ratio := marginRatio(account)
+	status := &MarginStatus{
+		RatioPercent:  ratio,
+		MaintMargin:   account.TotalMaintMargin,
+		MarginBalance: account.TotalMarginBalance,
+		Stage:         marginStage(stages, ratio),
+		UpdatedAt:     time.Now(),
+	}
+	if status.Stage >= 0 {
+		status.Action = stages[status.Stage].Action
+		status.StagePercent = stages[status.Stage].RatioPercent
+	}
+
+	e.marginMu.Lock()
+	previous := -1
+	if e.margin != nil {
+		previous = e.margin.Stage
+	}
+	e.margin = status
+	e.marginMu.Unlock()
+
+	if status.Stage != previous {
+		e.reportMarginStage(ctx, status, previous)
+	}
+
+	switch status.Action {
+	case config.MarginActionReduce:
+		e.deriskMargin(ctx, status, false)
+	case config.MarginActionFlatten:
+		e.deriskMargin(ctx, status, true)
+	}
+	return nil
+}
+
+// reportMarginStage logs, audits and notifies a change of margin monitor stage
+func (e *Engine) reportMarginStage(ctx context.Context, status *MarginStatus, previous int) {
+	e.recordAudit("risk", "margin_stage", "", status)
+
+	msg := &notify.Message{Time: status.UpdatedAt, Level: notify.LevelInfo}
+	switch {
+	case status.Stage < 0:
+		e.logger.Infof("Margin ratio back to %.1f%%, below every margin monitor stage", status.RatioPercent)
+		msg.Title = "Margin ratio recovered"
+		msg.Body = fmt.Sprintf("The account margin ratio is %.1f%%, below every margin monitor stage; entries are allowed again.", status.RatioPercent)
+	case status.Stage < previous:
+		e.logger.Infof("Margin ratio down to %.1f%%, at the %s stage (%.0f%%)", status.RatioPercent, status.Action, status.StagePercent)
+		msg.Title = "Margin ratio easing"
+		msg.Body = fmt.Sprintf("The account margin ratio is down to %.1f%%, at the %s stage (%.0f%%).", status.RatioPercent, status.Action, status.StagePercent)
+	default:
+		e.logger.Warnf("Margin ratio %.1f%% reached the %s stage (%.0f%%)", status.RatioPercent, status.Action, status.StagePercent)
+		msg.Title = "Margin ratio rising"
+		msg.Body = fmt.Sprintf("The account margin ratio is %.1f%% (maintenance margin %.2f of %.2f margin balance), "+
+			"reaching the %s stage at %.0f%%. The exchange liquidates at 100%%.",
+			status.RatioPercent, status.MaintMargin, status.MarginBalance, status.Action, status.StagePercent)
+		msg.Level = notify.LevelWarning
+		if marginSeverity(status.Action) >= marginSeverity(config.MarginActionReduce) {
+			msg.Level = notify.LevelCritical
+		}
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver margin ratio notification: %v", err)
+	}
+}
+
+// deriskMargin closes the position with the largest unrealized loss, or every position when
+// flattening. After a close it waits for the cooldown so the next check sees the new ratio.
+func (e *Engine) deriskMargin(ctx context.Context, status *MarginStatus, flatten bool) {
+	if e.config.EnablePaperTrading {
+		return
+	}
+	e.marginMu.Lock()
+	cooldown := time.Duration(e.config.MarginMonitor.CooldownSeconds) * time.Second
+	if time.Since(e.marginDeriskedAt) < cooldown {
+		e.marginMu.Unlock()
+		return
+	}
+	e.marginDeriskedAt = time.Now()
+	e.marginMu.Unlock()
+
+	ctx = auditlog.WithSource(ctx, auditlog.TriggerRisk, "margin_monitor")
+	if flatten {
+		e.logger.Warnf("Margin ratio %.1f%% at the flatten stage, closing every position", status.RatioPercent)
+		if _, err := e.FlattenAccount(ctx); err != nil {
+			e.logger.Errorf("Failed to flatten the account at margin ratio %.1f%%: %v", status.RatioPercent, err)
+		}
+		return
+	}
+
+	positions, err := e.repository.GetAllPositions()
+	if err != nil {
+		e.logger.Errorf("Failed to get positions to reduce at margin ratio %.1f%%: %v", status.RatioPercent, err)
+		return
+	}
+	// Stored unrealized PnL is only as fresh as the last position sync
+	live, err := e.exchangeClient.GetPositions(ctx)
+	if err != nil {
+		e.logger.Errorf("Failed to get exchange positions to reduce at margin ratio %.1f%%: %v", status.RatioPercent, err)
+		return
+	}
+	unrealized := make(map[string]float64, len(live))
+	for _, position := range live {
+		unrealized[position.Symbol] += position.UnrealizedPnL
+	}
+	for _, position := range positions {
+		if pnl, ok := unrealized[position.Symbol]; ok {
+			position.UnrealizedPnL = pnl
+		}
+	}
+	loser := largestLoser(positions)
+	if loser == nil {
+		e.logger.Warnf("Margin ratio %.1f%% at the reduce stage but no position is losing", status.RatioPercent)
+		return
+	}
+
+	reason := fmt.Sprintf("margin ratio %.1f%%", status.RatioPercent)
+	results, err := e.closePositionsWhere(ctx, reason, func(position *models.Position) bool {
+		return position.ID == loser.ID
+	})
+	if err != nil {
+		e.logger.Errorf("Failed to reduce %s at margin ratio %.1f%%: %v", loser.Symbol, status.RatioPercent, err)
+		return
+	}
+	e.logger.Warnf("Margin ratio %.1f%% at the reduce stage, closing %s with unrealized PnL %.2f", status.RatioPercent, loser.Symbol, loser.UnrealizedPnL)
+	e.recordAudit("risk", "margin_reduce", loser.Symbol, results)
+}
+
+// largestLoser returns the position with the largest unrealized loss, or nil when none loses
+func largestLoser(positions []*models.Position) *models.Position {
+	var loser *models.Position
+	for _, position := range positions {
+		if position.UnrealizedPnL < 0 && (loser == nil || position.UnrealizedPnL < loser.UnrealizedPnL) {
+			loser = position
+		}
+	}
+	return loser
+}
+
+// marginFilter blocks entries from the stop_entries stage up
+func (e *Engine) marginFilter() string {
+	status := e.marginStatus()
+	if status == nil || marginSeverity(status.Action) < marginSeverity(config.MarginActionStopEntries) {
+		return ""
+	}
+	return fmt.Sprintf("margin ratio %.1f%% at the %s stage (%.0f%%)", status.RatioPercent, status.Action, status.StagePercent)
+}
+
+// marginStatus returns the latest margin ratio, or nil before the first check
+func (e *Engine) marginStatus() *MarginStatus {
+	e.marginMu.Lock()
+	defer e.marginMu.Unlock()
+	return e.margin
+}
@@ -0,0 +1,51 @@
+package trading
+
+import (
+	"testing"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+)
+
+func TestMarginStage(t *testing.T) {
+	stages := []config.MarginStageConfig{
+		{RatioPercent: 40, Action: config.MarginActionWarn},
+		{RatioPercent: 55, Action: config.MarginActionStopEntries},
+		{RatioPercent: 70, Action: config.MarginActionReduce},
+		{RatioPercent: 85, Action: config.MarginActionFlatten},
+	}
+	for _, test := range []struct {
+		account exchange.AccountInfo
+		ratio   float64
+		stage   int
+	}{
+		{exchange.AccountInfo{TotalMaintMargin: 0, TotalMarginBalance: 1000}, 0, -1},
+		{exchange.AccountInfo{TotalMaintMargin: 300, TotalMarginBalance: 1000}, 30, -1},
+		{exchange.AccountInfo{TotalMaintMargin: 400, TotalMarginBalance: 1000}, 40, 0},
+		{exchange.AccountInfo{TotalMaintMargin: 720, TotalMarginBalance: 1000}, 72, 2},
+		{exchange.AccountInfo{TotalMaintMargin: 50, TotalMarginBalance: -10}, 100, 3},
+	} {
+		ratio := marginRatio(&test.account)
+		if ratio != test.ratio {
+			t.Errorf("ratio of %+v = %v, want %v", test.account, ratio, test.ratio)
+		}
+		if stage := marginStage(stages, ratio); stage != test.stage {
+			t.Errorf("stage at %v%% = %d, want %d", ratio, stage, test.stage)
+		}
+	}
+}
+
+func TestLargestLoser(t *testing.T) {
+	positions := []*models.Position{
+		{ID: 1, Symbol: "BTCUSDT", UnrealizedPnL: 50},
+		{ID: 2, Symbol: "ETHUSDT", UnrealizedPnL: -20},
+		{ID: 3, Symbol: "SOLUSDT", UnrealizedPnL: -35},
+	}
+	if loser := largestLoser(positions); loser == nil || loser.ID != 3 {
+		t.Errorf("largest loser = %+v, want SOLUSDT", loser)
+	}
+	if loser := largestLoser(positions[:1]); loser != nil {
+		t.Errorf("largest loser among winners = %+v, want nil", loser)
+	}
+}
@@ -0,0 +1,174 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HealthMarketDataWriter is the health check of the market data writer queue
+const HealthMarketDataWriter = "market_data_writer"
+
+// Longest Stop waits for the queued ticks to be written
+const marketDataDrainTimeout = 10 * time.Second
+
+// marketDataWriter persists price ticks in batches off the market data loop. Its queue is
+// bounded: a full queue blocks the producer for up to the enqueue timeout (backpressure on
+// the collection loop) and then drops the tick, so a slow database can neither grow memory
+// nor stall trading indefinitely.
+type marketDataWriter struct {
+	cfg        config.RetentionConfig
+	repository database.Repository
+	logger     *logrus.Logger
+	queue      chan *models.MarketData
+	done       chan struct{}
+
+	// Time of the last queued tick per symbol, for persist_interval_seconds
+	lastQueued map[string]int64
+	mu         sync.Mutex
+
+	written atomic.Int64
+	dropped atomic.Int64
+	failed  atomic.Int64
+}
+
+func newMarketDataWriter(cfg config.RetentionConfig, repository database.Repository, logger *logrus.Logger) *marketDataWriter {
+	return &marketDataWriter{
+		cfg:        cfg,
+		repository: repository,
+		logger:     logger,
+		queue:      make(chan *models.MarketData, cfg.WriterQueueSize),
+		done:       make(chan struct{}),
+		lastQueued: make(map[string]int64),
+	}
+}
+
+// enqueue queues a tick for persistence unless the symbol's last stored tick is more recent
+// than persist_interval_seconds. It reports whether the tick was queued.
+func (w *marketDataWriter) enqueue(ctx context.Context, data *models.MarketData) bool {
+	w.mu.Lock()
+	last, ok := w.lastQueued[data.Symbol]
+	w.mu.Unlock()
+	if ok && data.Timestamp-last < int64(w.cfg.PersistIntervalSeconds) {
+		return false
+	}
+
+	if w.push(ctx, data) {
+		w.mu.Lock()
+		w.lastQueued[data.Symbol] = data.Timestamp
+		w.mu.Unlock()
+		return true
+	}
+
+	// Warn on the first drop and then every writer_queue_size drops
+	if dropped := w.dropped.Add(1); dropped%int64(cap(w.queue)) == 1 {
+		w.logger.Warnf("Market data writer queue is full; %d ticks dropped so far", dropped)
+	}
+	return false
+}
+
+// push queues the tick, waiting up to enqueue_timeout_ms for room
+func (w *marketDataWriter) push(ctx context.Context, data *models.MarketData) bool {
+	select {
+	case w.queue <- data:
+		return true
+	default:
+	}
+
+	timer := time.NewTimer(time.Duration(w.cfg.EnqueueTimeoutMillis) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case w.queue <- data:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// run writes queued ticks in batches of writer_batch_size, or whatever is queued every
+// writer_flush_ms, until ctx is cancelled; the ticks still queued are then written
+func (w *marketDataWriter) run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(time.Duration(w.cfg.WriterFlushMillis) * time.Millisecond)
+	defer ticker.Stop()
+
+	batch := make([]*models.MarketData, 0, w.cfg.WriterBatchSize)
+	for {
+		select {
+		case data := <-w.queue:
+			batch = append(batch, data)
+			if len(batch) >= w.cfg.WriterBatchSize {
+				batch = w.flush(batch)
+			}
+		case <-ticker.C:
+			batch = w.flush(batch)
+		case <-ctx.Done():
+			for {
+				select {
+				case data := <-w.queue:
+					batch = append(batch, data)
+					if len(batch) >= w.cfg.WriterBatchSize {
+						batch = w.flush(batch)
+					}
+				default:
+					w.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush writes the batch and returns it emptied for reuse
+func (w *marketDataWriter) flush(batch []*models.MarketData) []*models.MarketData {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := w.repository.SaveMarketDataBatch(batch); err != nil {
+		w.failed.Add(int64(len(batch)))
+		w.logger.Errorf("Failed to save %d market data ticks: %v", len(batch), err)
+	} else {
+		w.written.Add(int64(len(batch)))
+	}
+
+	// Release the written ticks while keeping the buffer
+	for i := range batch {
+		batch[i] = nil
+	}
+	return batch[:0]
+}
+
+// wait blocks until the queued ticks are written after cancellation, at most
+// marketDataDrainTimeout
+func (w *marketDataWriter) wait(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, marketDataDrainTimeout)
+	defer cancel()
+
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		w.logger.Warnf("Stopped waiting for the market data writer; %d ticks may be lost", len(w.queue))
+	}
+}
+
+// check reports the writer's queue depth; it is unhealthy while the queue is full
+func (w *marketDataWriter) check() *HealthCheck {
+	queued := len(w.queue)
+	return &HealthCheck{
+		Name: HealthMarketDataWriter,
+		OK:   queued < cap(w.queue),
+		Detail: fmt.Sprintf("%d/%d queued, %d written, %d dropped, %d failed",
+			queued, cap(w.queue), w.written.Load(), w.dropped.Load(), w.failed.Load()),
+	}
+}
@@ -0,0 +1,62 @@
+package trading
+
+import (
+	"context"
+	"time"
+
+	"contract_playground/internal/models"
+)
+
+// collectOptionsData periodically samples implied volatility and skew of each traded
+// underlying from the options venue
+func (e *Engine) collectOptionsData(ctx context.Context) {
+	cfg := e.config.Options
+	ticker := time.NewTicker(time.Duration(cfg.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		seen := make(map[string]bool)
+		for _, symbol := range e.config.Symbols {
+			underlying := baseAsset(symbol)
+			if seen[underlying] {
+				continue
+			}
+			seen[underlying] = true
+			if err := e.updateOptionsData(ctx, underlying); err != nil {
+				e.logger.Warnf("Failed to update options data for %s: %v", underlying, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// updateOptionsData fetches, caches and persists the option volatility of an underlying
+func (e *Engine) updateOptionsData(ctx context.Context, underlying string) error {
+	cfg := e.config.Options
+	vol, err := e.options.GetVolatility(ctx, underlying, time.Duration(cfg.TenorDays)*24*time.Hour, cfg.SkewMoneynessPercent/100)
+	if err != nil {
+		return err
+	}
+
+	e.marketDataMu.Lock()
+	e.optionsVolatility[underlying] = vol
+	e.marketDataMu.Unlock()
+
+	return e.repository.SaveOptionsVolatility(&models.OptionsVolatilitySample{
+		Underlying:      underlying,
+		Venue:           e.options.Name(),
+		UnderlyingPrice: vol.UnderlyingPrice,
+		Expiry:          vol.Expiry,
+		ATMVolatility:   vol.ATMVolatility,
+		PutVolatility:   vol.PutVolatility,
+		CallVolatility:  vol.CallVolatility,
+		Skew:            vol.Skew,
+		PutCallRatio:    vol.PutCallRatio,
+		Timestamp:       vol.Time.Unix(),
+	})
+}
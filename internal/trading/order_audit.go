@@ -0,0 +1,96 @@
+package trading
+
+import (
+	"context"
+	"strconv"
+
+	"contract_playground/internal/auditlog"
+	"contract_playground/internal/exchange"
+)
+
+// submitOrder submits a futures order and records the attempt in the order audit log
+func (e *Engine) submitOrder(ctx context.Context, request *exchange.OrderRequest) (*exchange.OrderResponse, error) {
+	response, err := e.exchangeClient.PlaceOrder(ctx, request)
+
+	entry := &auditlog.Entry{
+		Action:        auditlog.ActionSubmit,
+		Market:        auditlog.MarketFutures,
+		Symbol:        request.Symbol,
+		ClientOrderID: request.NewClientOrderID,
+		Request:       request,
+		Err:           err,
+	}
+	if response != nil {
+		entry.Response = response
+		entry.ExchangeOrderID = strconv.FormatInt(response.OrderID, 10)
+	}
+	e.appendOrderAudit(ctx, entry)
+	return response, err
+}
+
+// modifyOrder amends a futures order and records the attempt in the order audit log
+func (e *Engine) modifyOrder(ctx context.Context, request *exchange.ModifyOrderRequest) (*exchange.OrderResponse, error) {
+	response, err := e.exchangeClient.ModifyOrder(ctx, request)
+
+	entry := &auditlog.Entry{
+		Action:          auditlog.ActionModify,
+		Market:          auditlog.MarketFutures,
+		Symbol:          request.Symbol,
+		ExchangeOrderID: strconv.FormatInt(request.OrderID, 10),
+		Request:         request,
+		Err:             err,
+	}
+	if response != nil {
+		entry.Response = response
+		entry.ClientOrderID = response.ClientOrderID
+	}
+	e.appendOrderAudit(ctx, entry)
+	return response, err
+}
+
+// cancelOrder cancels a futures order and records the attempt in the order audit log
+func (e *Engine) cancelOrder(ctx context.Context, symbol string, orderID int64) error {
+	err := e.exchangeClient.CancelOrder(ctx, symbol, orderID)
+
+	e.appendOrderAudit(ctx, &auditlog.Entry{
+		Action:          auditlog.ActionCancel,
+		Market:          auditlog.MarketFutures,
+		Symbol:          symbol,
+		ExchangeOrderID: strconv.FormatInt(orderID, 10),
+		Request:         map[string]interface{}{"symbol": symbol, "order_id": orderID},
+		Err:             err,
+	})
+	return err
+}
+
+// placeSpotOrder submits a spot order and records the attempt in the order audit log
+func (e *Engine) placeSpotOrder(ctx context.Context, request *exchange.SpotOrderRequest) (*exchange.SpotOrderResponse, error) {
+	response, err := e.spotClient.PlaceSpotOrder(ctx, request)
+
+	entry := &auditlog.Entry{
+		Action:        auditlog.ActionSubmit,
+		Market:        auditlog.MarketSpot,
+		Symbol:        request.Symbol,
+		ClientOrderID: request.NewClientOrderID,
+		Request:       request,
+		Err:           err,
+	}
+	if response != nil {
+		entry.Response = response
+		entry.ExchangeOrderID = strconv.FormatInt(response.OrderID, 10)
+	}
+	e.appendOrderAudit(ctx, entry)
+	return response, err
+}
+
+// appendOrderAudit attributes the entry to the context's source and appends it. A failed
+// append is logged but never blocks the order flow.
+func (e *Engine) appendOrderAudit(ctx context.Context, entry *auditlog.Entry) {
+	if e.orderAudit == nil {
+		return
+	}
+	entry.Source = auditlog.SourceFrom(ctx)
+	if err := e.orderAudit.Append(entry); err != nil {
+		e.logger.Errorf("Failed to record %s of %s order in the audit log: %v", entry.Action, entry.Symbol, err)
+	}
+}
@@ -0,0 +1,149 @@
+package trading
+
+import (
+	"context"
+	"time"
+
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+)
+
+// flowBucket holds one second of taker volume in the quote asset
+type flowBucket struct {
+	second     int64
+	buy        float64
+	sell       float64
+	largeBuys  int
+	largeSells int
+}
+
+// symbolFlow is a ring of per-second buckets covering the rolling window, plus the minute
+// sample being accumulated for persistence
+type symbolFlow struct {
+	buckets []flowBucket
+	minute  *models.OrderFlowSample
+}
+
+// orderFlowSnapshot is the taker flow of a symbol over the rolling window
+type orderFlowSnapshot struct {
+	buyVolume  float64
+	sellVolume float64
+	largeBuys  int
+	largeSells int
+}
+
+// aggTradeHandler feeds streamed aggregate trades into the order flow metrics
+type aggTradeHandler struct {
+	engine *Engine
+}
+
+// OnAggTrade records the trade on the taker's side
+func (h *aggTradeHandler) OnAggTrade(trade *exchange.AggTrade) {
+	h.engine.recordAggTrade(trade)
+}
+
+// OnError logs stream errors; the stream reconnects on its own
+func (h *aggTradeHandler) OnError(err error) {
+	h.engine.logger.Warnf("Aggregate trade stream error: %v", err)
+}
+
+// recordAggTrade adds the trade to the symbol's window and minute sample. A trade whose
+// buyer is the maker was initiated by a seller.
+func (e *Engine) recordAggTrade(trade *exchange.AggTrade) {
+	notional := e.contracts.get(trade.Symbol).Notional(trade.Quantity, trade.Price)
+	if notional <= 0 {
+		return
+	}
+	large := notional >= e.config.OrderFlow.LargeTradeNotional
+	second := trade.Time / 1000
+	minute := second - second%60
+
+	e.orderFlowMu.Lock()
+	defer e.orderFlowMu.Unlock()
+
+	flow, ok := e.orderFlows[trade.Symbol]
+	if !ok {
+		flow = &symbolFlow{buckets: make([]flowBucket, e.config.OrderFlow.WindowSeconds)}
+		e.orderFlows[trade.Symbol] = flow
+	}
+
+	bucket := &flow.buckets[second%int64(len(flow.buckets))]
+	if bucket.second != second {
+		*bucket = flowBucket{second: second}
+	}
+
+	if flow.minute == nil || flow.minute.Timestamp < minute {
+		if flow.minute != nil && e.config.OrderFlow.Persist {
+			e.pendingFlow = append(e.pendingFlow, flow.minute)
+		}
+		flow.minute = &models.OrderFlowSample{Symbol: trade.Symbol, Timestamp: minute}
+	}
+	sample := flow.minute
+	sample.TradeCount++
+
+	if trade.BuyerMaker {
+		bucket.sell += notional
+		sample.SellVolume += notional
+		if large {
+			bucket.largeSells++
+			sample.LargeSells++
+			sample.LargeSellVolume += notional
+		}
+	} else {
+		bucket.buy += notional
+		sample.BuyVolume += notional
+		if large {
+			bucket.largeBuys++
+			sample.LargeBuys++
+			sample.LargeBuyVolume += notional
+		}
+	}
+	sample.Delta = sample.BuyVolume - sample.SellVolume
+}
+
+// orderFlow sums the symbol's buckets within the rolling window ending now
+func (e *Engine) orderFlow(symbol string, now time.Time) *orderFlowSnapshot {
+	e.orderFlowMu.Lock()
+	defer e.orderFlowMu.Unlock()
+
+	flow, ok := e.orderFlows[symbol]
+	if !ok {
+		return nil
+	}
+
+	since := now.Unix() - int64(len(flow.buckets))
+	snapshot := &orderFlowSnapshot{}
+	for _, bucket := range flow.buckets {
+		if bucket.second <= since {
+			continue
+		}
+		snapshot.buyVolume += bucket.buy
+		snapshot.sellVolume += bucket.sell
+		snapshot.largeBuys += bucket.largeBuys
+		snapshot.largeSells += bucket.largeSells
+	}
+	return snapshot
+}
+
+// persistOrderFlow stores completed minute samples periodically for backtesting
+func (e *Engine) persistOrderFlow(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		e.orderFlowMu.Lock()
+		samples := e.pendingFlow
+		e.pendingFlow = nil
+		e.orderFlowMu.Unlock()
+
+		if err := e.repository.SaveOrderFlowSamples(samples); err != nil {
+			e.logger.Errorf("Failed to save %d order flow samples: %v", len(samples), err)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package trading
+
+import (
+	"fmt"
+	"time"
+
+	"contract_playground/internal/database"
+	"contract_playground/internal/events"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+)
+
+// enqueueEvent queues an event for the event webhooks in the outbox through tx, so it is
+// delivered only if the change it describes commits
+func (e *Engine) enqueueEvent(tx database.Repository, eventType, action, symbol string, data interface{}) error {
+	if !e.eventOutbox {
+		return nil
+	}
+	message, err := notify.EventMessage(events.Event{Instance: e.instance, Type: eventType, Action: action, Symbol: symbol, Time: time.Now(), Data: data})
+	if err != nil {
+		return err
+	}
+	if err := tx.CreateOutboxMessage(message); err != nil {
+		return fmt.Errorf("failed to queue %s %s event: %w", eventType, action, err)
+	}
+	return nil
+}
+
+// OutboxMessages returns the newest outbox messages with the given status, any when empty
+func (e *Engine) OutboxMessages(status string, limit int) ([]*models.OutboxMessage, error) {
+	return e.repository.GetOutboxMessages(status, limit)
+}
+
+// RequeueOutboxMessage gives a dead outbox message a fresh set of delivery attempts
+func (e *Engine) RequeueOutboxMessage(id uint) error {
+	if err := e.repository.RequeueOutboxMessage(id); err != nil {
+		return err
+	}
+	e.logger.Infof("Requeued outbox message %d", id)
+	return nil
+}
@@ -0,0 +1,130 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"contract_playground/internal/notify"
+)
+
+// SymbolPause records why new entries for a symbol are paused. Exits, stops and child
+// orders of its open position are still managed.
+type SymbolPause struct {
+	Symbol    string    `json:"symbol"`
+	Reason    string    `json:"reason"`
+	Automatic bool      `json:"automatic"` // Paused after repeated processing errors
+	Since     time.Time `json:"since"`
+}
+
+// PauseSymbol stops new entries for a symbol until it is resumed
+func (e *Engine) PauseSymbol(symbol, reason string) error {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if !e.isTradedSymbol(symbol) {
+		return fmt.Errorf("%s is not a traded symbol", symbol)
+	}
+	if reason == "" {
+		reason = "paused by operator"
+	}
+
+	e.pauseSymbol(&SymbolPause{Symbol: symbol, Reason: reason, Since: time.Now()})
+	e.recordAudit("symbol", "pause", symbol, map[string]string{"reason": reason})
+	return nil
+}
+
+// ResumeSymbol allows new entries for a paused symbol again
+func (e *Engine) ResumeSymbol(symbol string) error {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+
+	e.pauseMu.Lock()
+	_, paused := e.pauses[symbol]
+	delete(e.pauses, symbol)
+	delete(e.symbolErrors, symbol)
+	e.pauseMu.Unlock()
+
+	if !paused {
+		return fmt.Errorf("%s is not paused", symbol)
+	}
+
+	e.logger.Infof("Resumed entries for %s", symbol)
+	e.recordAudit("symbol", "resume", symbol, nil)
+	return nil
+}
+
+// PausedSymbols returns the paused symbols sorted by symbol
+func (e *Engine) PausedSymbols() []*SymbolPause {
+	e.pauseMu.Lock()
+	defer e.pauseMu.Unlock()
+
+	pauses := make([]*SymbolPause, 0, len(e.pauses))
+	for _, pause := range e.pauses {
+		copied := *pause
+		pauses = append(pauses, &copied)
+	}
+	sort.Slice(pauses, func(i, j int) bool { return pauses[i].Symbol < pauses[j].Symbol })
+	return pauses
+}
+
+// isPaused reports whether new entries for the symbol are paused, on their own or because
+// the strategy guard paused the whole strategy
+func (e *Engine) isPaused(symbol string) bool {
+	e.pauseMu.Lock()
+	defer e.pauseMu.Unlock()
+	_, paused := e.pauses[symbol]
+	return paused || e.strategyPause != nil
+}
+
+func (e *Engine) pauseSymbol(pause *SymbolPause) {
+	e.pauseMu.Lock()
+	e.pauses[pause.Symbol] = pause
+	e.pauseMu.Unlock()
+
+	e.logger.Warnf("Paused entries for %s: %s", pause.Symbol, pause.Reason)
+}
+
+// recordSymbolResult counts consecutive processing errors of a symbol and pauses its
+// entries once auto_pause_errors is reached
+func (e *Engine) recordSymbolResult(ctx context.Context, symbol string, err error) {
+	e.pauseMu.Lock()
+	if err == nil {
+		delete(e.symbolErrors, symbol)
+		e.pauseMu.Unlock()
+		return
+	}
+
+	e.symbolErrors[symbol]++
+	count := e.symbolErrors[symbol]
+	_, paused := e.pauses[symbol]
+	e.pauseMu.Unlock()
+
+	if paused || e.config.AutoPauseErrors == 0 || count < e.config.AutoPauseErrors {
+		return
+	}
+
+	reason := fmt.Sprintf("%d consecutive errors, last: %v", count, err)
+	e.pauseSymbol(&SymbolPause{Symbol: symbol, Reason: reason, Automatic: true, Since: time.Now()})
+	e.recordAudit("symbol", "auto_pause", symbol, map[string]string{"reason": reason})
+
+	msg := &notify.Message{
+		Title:  fmt.Sprintf("%s entries paused", symbol),
+		Body:   reason + ". Open positions are still managed; resume the symbol once the cause is fixed.",
+		Level:  notify.LevelWarning,
+		Symbol: symbol,
+		Time:   time.Now(),
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver pause notification: %v", err)
+	}
+}
+
+// isTradedSymbol reports whether the symbol is in the configured trading universe
+func (e *Engine) isTradedSymbol(symbol string) bool {
+	for _, traded := range e.config.Symbols {
+		if traded == symbol {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PlanEntry describes what the engine would do for a symbol given current market data
+type PlanEntry struct {
+	Symbol       string                 `json:"symbol"`
+	Price        float64                `json:"price"`
+	Action       string                 `json:"action"` // BUY, SELL, HOLD
+	Reason       string                 `json:"reason"`
+	Confidence   float64                `json:"confidence"`
+	Position     *models.Position       `json:"position,omitempty"`
+	Order        *exchange.OrderRequest `json:"order,omitempty"`
+	RiskApproved bool                   `json:"risk_approved"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+// Plan evaluates the strategy once per symbol on freshly fetched market data, runs the
+// same risk checks as the trading loop and returns the orders that would be placed right
+// now. It never places orders or writes to the database.
+func (e *Engine) Plan(ctx context.Context) ([]*PlanEntry, error) {
+	entries := make([]*PlanEntry, 0, len(e.config.Symbols))
+	e.loadCooldowns()
+
+	for _, symbol := range e.config.Symbols {
+		entry := &PlanEntry{Symbol: symbol, Action: "HOLD"}
+		entries = append(entries, entry)
+
+		if err := e.planSymbol(ctx, symbol, entry); err != nil {
+			entry.Error = err.Error()
+		}
+	}
+
+	return entries, nil
+}
+
+// planSymbol mirrors processSymbolSignals without executing anything
+func (e *Engine) planSymbol(ctx context.Context, symbol string, entry *PlanEntry) error {
+	if _, _, err := e.refreshMarketData(ctx, symbol); err != nil {
+		return err
+	}
+
+	marketData, err := e.getMarketData(symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get market data for %s: %w", symbol, err)
+	}
+	entry.Price = marketData.Price
+
+	position, err := e.repository.GetPosition(symbol, "LONG")
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return fmt.Errorf("failed to get position for %s: %w", symbol, err)
+	}
+
+	if position != nil && position.Status == "OPEN" {
+		entry.Position = position
+
+		signal, err := e.strategy.ShouldSell(ctx, symbol, marketData, position)
+		if err != nil {
+			return fmt.Errorf("failed to get sell signal: %w", err)
+		}
+		e.applyPlanSignal(entry, signal)
+
+		if signal != nil && signal.Action == "SELL" {
+			entry.Order = e.buildSellOrderRequest(symbol, signal, position)
+			// Exits are not subject to entry risk checks
+			entry.RiskApproved = true
+		}
+
+		return nil
+	}
+
+	signal, err := e.strategy.ShouldBuy(ctx, symbol, marketData)
+	if err != nil {
+		return fmt.Errorf("failed to get buy signal: %w", err)
+	}
+	e.applyPlanSignal(entry, signal)
+
+	if signal != nil && signal.Action == "BUY" {
+		if err := e.sizer.Size(ctx, signal, marketData); err != nil {
+			entry.Action = "HOLD"
+			entry.Reason = fmt.Sprintf("%s (not sized: %v)", signal.Reason, err)
+			return nil
+		}
+
+		// The same gate as the trading loop; only risk rejections still show the order
+		block := e.checkEntry(ctx, symbol, marketData, signal)
+		if block != nil && block.rejected == nil {
+			entry.Action = "HOLD"
+			entry.Reason = fmt.Sprintf("%s (skipped: %s)", signal.Reason, block.reason)
+			return nil
+		}
+
+		order, err := e.guardSlippage(ctx, e.buildBuyOrderRequest(symbol, signal), signal.Price)
+		if err != nil {
+			entry.Action = "HOLD"
+			entry.Reason = fmt.Sprintf("%s (rejected: %v)", signal.Reason, err)
+			return nil
+		}
+		entry.Order = order
+		entry.RiskApproved = block == nil
+	}
+
+	return nil
+}
+
+func (e *Engine) applyPlanSignal(entry *PlanEntry, signal *Signal) {
+	if signal == nil {
+		return
+	}
+
+	entry.Action = signal.Action
+	entry.Reason = signal.Reason
+	entry.Confidence = signal.Confidence
+}
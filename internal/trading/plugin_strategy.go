@@ -0,0 +1,108 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"plugin"
+	"strings"
+
+	"contract_playground/internal/models"
+	"contract_playground/pkg/strategysdk"
+)
+
+// PluginStrategy runs a strategy built as a Go plugin (go build -buildmode=plugin) that
+// exports
+//
+//	func NewStrategy() strategysdk.Strategy
+//
+// Go plugins only load on Linux and macOS, into a binary built with the same Go toolchain
+// and the same version of this module.
+type PluginStrategy struct {
+	name  string
+	path  string
+	inner strategysdk.Strategy
+}
+
+// NewPluginStrategy creates a plugin strategy; the plugin is loaded by Initialize
+func NewPluginStrategy() Strategy {
+	return &PluginStrategy{name: "Plugin"}
+}
+
+// Name returns the plugin's name, or the file name when it has none
+func (p *PluginStrategy) Name() string {
+	return p.name
+}
+
+// Initialize loads the plugin named by the path parameter and initializes it with the
+// remaining parameters. A plugin is loaded once per process; reinitializing with the same
+// path keeps the strategy instance.
+func (p *PluginStrategy) Initialize(config map[string]interface{}) error {
+	path, _ := getStringParam(config, "path")
+	if path == "" {
+		return fmt.Errorf("plugin strategy requires a path parameter")
+	}
+
+	if p.inner == nil || path != p.path {
+		inner, err := loadStrategyPlugin(path)
+		if err != nil {
+			return err
+		}
+		p.inner, p.path = inner, path
+		p.name = "Plugin " + strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if namer, ok := inner.(strategysdk.Namer); ok && namer.Name() != "" {
+			p.name = namer.Name()
+		}
+	}
+
+	if err := p.inner.Initialize(sdkParameters(config)); err != nil {
+		return fmt.Errorf("plugin %s: %w", p.name, err)
+	}
+	return nil
+}
+
+// ShouldBuy asks the plugin for an entry
+func (p *PluginStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
+	if p.inner == nil {
+		return nil, fmt.Errorf("plugin strategy is not loaded")
+	}
+	signal, err := p.inner.ShouldBuy(ctx, toSDKMarketData(data))
+	if err != nil {
+		return nil, err
+	}
+	return fromSDKSignal(signal)
+}
+
+// ShouldSell asks the plugin about the open position
+func (p *PluginStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
+	if p.inner == nil {
+		return nil, fmt.Errorf("plugin strategy is not loaded")
+	}
+	signal, err := p.inner.ShouldSell(ctx, toSDKMarketData(data), toSDKPosition(position))
+	if err != nil {
+		return nil, err
+	}
+	return fromSDKSignal(signal)
+}
+
+// loadStrategyPlugin opens the plugin and creates a strategy with its NewStrategy
+func loadStrategyPlugin(path string) (strategysdk.Strategy, error) {
+	plug, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open strategy plugin %s: %w", path, err)
+	}
+	symbol, err := plug.Lookup("NewStrategy")
+	if err != nil {
+		return nil, fmt.Errorf("strategy plugin %s: %w", path, err)
+	}
+	constructor, ok := symbol.(func() strategysdk.Strategy)
+	if !ok {
+		return nil, fmt.Errorf("strategy plugin %s: NewStrategy is %T, want func() strategysdk.Strategy", path, symbol)
+	}
+
+	strategy := constructor()
+	if strategy == nil {
+		return nil, fmt.Errorf("strategy plugin %s: NewStrategy returned nil", path)
+	}
+	return strategy, nil
+}
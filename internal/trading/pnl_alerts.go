@@ -0,0 +1,209 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// pnlAlertRefreshInterval bounds how stale the cached open positions can get
+	pnlAlertRefreshInterval = 30 * time.Second
+	// pnlAlertNotifyTimeout bounds a single notification delivery
+	pnlAlertNotifyTimeout = 10 * time.Second
+)
+
+// pnlAlerter watches mark prices and notifies when a position's unrealized PnL crosses a
+// configured band. A band fires once and only re-arms after PnL retreats past the band by
+// the hysteresis distance, so prices hovering around a threshold do not spam alerts.
+type pnlAlerter struct {
+	bands      []float64 // Sorted ascending
+	hysteresis float64
+	repository database.Repository
+	notifier   notify.Notifier
+	contracts  *contractSpecs
+	logger     *logrus.Logger
+
+	mu        sync.Mutex
+	positions map[string][]*models.Position // Open positions by symbol
+	triggered map[uint]map[float64]bool     // Bands currently fired per position ID
+}
+
+func newPnLAlerter(cfg config.PnLAlertConfig, repository database.Repository, notifier notify.Notifier, contracts *contractSpecs, logger *logrus.Logger) *pnlAlerter {
+	bands := append([]float64(nil), cfg.Bands...)
+	sort.Float64s(bands)
+
+	return &pnlAlerter{
+		bands:      bands,
+		hysteresis: cfg.Hysteresis,
+		repository: repository,
+		notifier:   notifier,
+		contracts:  contracts,
+		logger:     logger,
+		positions:  make(map[string][]*models.Position),
+		triggered:  make(map[uint]map[float64]bool),
+	}
+}
+
+// run periodically reloads open positions until ctx is cancelled
+func (p *pnlAlerter) run(ctx context.Context) {
+	ticker := time.NewTicker(pnlAlertRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.refresh(); err != nil {
+				p.logger.Errorf("Failed to refresh positions for PnL alerts: %v", err)
+			}
+		}
+	}
+}
+
+// refresh reloads open positions and drops alert state for positions that have closed
+func (p *pnlAlerter) refresh() error {
+	positions, err := p.repository.GetAllPositions()
+	if err != nil {
+		return fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	bySymbol := make(map[string][]*models.Position)
+	open := make(map[uint]bool, len(positions))
+	for _, position := range positions {
+		bySymbol[position.Symbol] = append(bySymbol[position.Symbol], position)
+		open[position.ID] = true
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.positions = bySymbol
+	for id := range p.triggered {
+		if !open[id] {
+			delete(p.triggered, id)
+		}
+	}
+
+	return nil
+}
+
+// OnMarkPrice evaluates the alert bands of every open position in the symbol
+func (p *pnlAlerter) OnMarkPrice(update *exchange.MarkPriceUpdate) {
+	if update.MarkPrice <= 0 {
+		return
+	}
+
+	var messages []*notify.Message
+
+	p.mu.Lock()
+	for _, position := range p.positions[update.Symbol] {
+		pct := unrealizedPnLPercent(position, update.MarkPrice)
+		if band, ok := p.evaluateLocked(position.ID, pct); ok {
+			messages = append(messages, p.buildMessage(position, update.MarkPrice, pct, band))
+		}
+	}
+	p.mu.Unlock()
+
+	for _, msg := range messages {
+		go p.deliver(msg)
+	}
+}
+
+// OnError logs mark price stream errors
+func (p *pnlAlerter) OnError(err error) {
+	p.logger.Errorf("Mark price stream error: %v", err)
+}
+
+// evaluateLocked updates the band state of a position and returns the band to alert on,
+// if any. When several bands are crossed at once only the furthest one is reported.
+func (p *pnlAlerter) evaluateLocked(positionID uint, pct float64) (float64, bool) {
+	state, ok := p.triggered[positionID]
+	if !ok {
+		state = make(map[float64]bool)
+		p.triggered[positionID] = state
+	}
+
+	var alert float64
+	fired := false
+
+	for _, band := range p.bands {
+		crossed := (band < 0 && pct <= band) || (band > 0 && pct >= band)
+
+		if state[band] {
+			// Re-arm only once PnL has moved back past the band by the hysteresis distance
+			if (band < 0 && pct > band+p.hysteresis) || (band > 0 && pct < band-p.hysteresis) {
+				delete(state, band)
+			}
+			continue
+		}
+
+		if crossed {
+			state[band] = true
+			if !fired || math.Abs(band) > math.Abs(alert) {
+				alert = band
+				fired = true
+			}
+		}
+	}
+
+	return alert, fired
+}
+
+func (p *pnlAlerter) buildMessage(position *models.Position, markPrice, pct, band float64) *notify.Message {
+	level := notify.LevelInfo
+	if band < 0 {
+		level = notify.LevelWarning
+	}
+
+	pnl := p.contracts.get(position.Symbol).PnL(position.EntryPrice, markPrice, position.Size)
+	if position.PositionSide == "SHORT" {
+		pnl = -pnl
+	}
+
+	return &notify.Message{
+		Title:  fmt.Sprintf("%s unrealized PnL crossed %+.2f%%", position.Symbol, band),
+		Body:   fmt.Sprintf("%s %s position is at %+.2f%% (%.4f %s), mark price %.6f, entry %.6f", position.Symbol, position.PositionSide, pct, pnl, p.contracts.marginAsset(position.Symbol), markPrice, position.EntryPrice),
+		Level:  level,
+		Symbol: position.Symbol,
+		Fields: map[string]string{
+			"position_id": fmt.Sprintf("%d", position.ID),
+			"band":        fmt.Sprintf("%.2f", band),
+		},
+		Time: time.Now(),
+	}
+}
+
+func (p *pnlAlerter) deliver(msg *notify.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), pnlAlertNotifyTimeout)
+	defer cancel()
+
+	if err := p.notifier.Notify(ctx, msg); err != nil {
+		p.logger.Errorf("Failed to deliver PnL alert: %v", err)
+	}
+}
+
+// unrealizedPnLPercent returns the position's unrealized PnL as a percentage of its entry value
+func unrealizedPnLPercent(position *models.Position, markPrice float64) float64 {
+	if position.EntryPrice <= 0 {
+		return 0
+	}
+
+	pct := (markPrice - position.EntryPrice) / position.EntryPrice * 100
+	if position.PositionSide == "SHORT" {
+		return -pct
+	}
+	return pct
+}
@@ -0,0 +1,224 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"contract_playground/internal/auditlog"
+	"contract_playground/internal/database"
+	"contract_playground/internal/events"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+
+	"gorm.io/gorm"
+)
+
+// positionChange describes a difference between a DB position and the exchange that was
+// caused outside the engine
+type positionChange struct {
+	PositionID    uint    `json:"position_id,omitempty"`
+	Symbol        string  `json:"symbol"`
+	Change        string  `json:"change"` // closed, resized, unknown
+	DBSize        float64 `json:"db_size"`
+	ExchangeSize  float64 `json:"exchange_size"`
+	DBEntry       float64 `json:"db_entry,omitempty"`
+	ExchangeEntry float64 `json:"exchange_entry,omitempty"`
+}
+
+// runPositionSync keeps open DB positions in line with the exchange, which is the source of
+// truth. Unknown exchange positions are reported once per size.
+func (e *Engine) runPositionSync(ctx context.Context) {
+	ctx = auditlog.WithSource(ctx, auditlog.TriggerSystem, "position_sync")
+	ticker := time.NewTicker(time.Duration(e.config.PositionSync.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	reported := make(map[string]float64)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.syncPositions(ctx, reported); err != nil {
+				e.logger.Errorf("Failed to sync positions with the exchange: %v", err)
+			}
+		case positions := <-e.positionSnapshots:
+			if err := e.applyExchangePositions(ctx, positions, reported); err != nil {
+				e.logger.Errorf("Failed to sync positions with the user data snapshot: %v", err)
+			}
+		}
+	}
+}
+
+// syncPositions fetches the exchange positions and applies them to the open DB positions
+func (e *Engine) syncPositions(ctx context.Context, reported map[string]float64) error {
+	exchangePositions, err := e.exchangeClient.GetPositions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get exchange positions: %w", err)
+	}
+	return e.applyExchangePositions(ctx, exchangePositions, reported)
+}
+
+// applyExchangePositions diffs the exchange positions against the open DB positions. Mark
+// price, unrealized PnL and margin are always copied; size, entry and closure are corrected
+// after the difference is confirmed under the symbol lock.
+func (e *Engine) applyExchangePositions(ctx context.Context, exchangePositions []*exchange.PositionInfo, reported map[string]float64) error {
+	live := make(map[string]*exchange.PositionInfo, len(exchangePositions))
+	for _, position := range exchangePositions {
+		if position.PositionAmt != 0 {
+			live[position.Symbol] = position
+		}
+	}
+
+	dbPositions, err := e.repository.GetAllPositions()
+	if err != nil {
+		return fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	known := make(map[string]bool, len(dbPositions))
+	for _, position := range dbPositions {
+		known[position.Symbol] = true
+		e.syncSymbolPosition(ctx, position.Symbol, live[position.Symbol], reported)
+	}
+
+	// Grid fills are not recorded as positions, so a grid's exchange position is expected
+	for symbol, position := range live {
+		if known[symbol] || e.grid != nil || !e.isTradedSymbol(symbol) {
+			continue
+		}
+		if reported[symbol] == position.PositionAmt {
+			continue
+		}
+		e.syncSymbolPosition(ctx, symbol, position, reported)
+	}
+	for symbol := range reported {
+		if live[symbol] == nil {
+			delete(reported, symbol)
+		}
+	}
+	return nil
+}
+
+// syncSymbolPosition applies the exchange position of a symbol to its DB position
+func (e *Engine) syncSymbolPosition(ctx context.Context, symbol string, live *exchange.PositionInfo, reported map[string]float64) {
+	defer e.lockSymbol(symbol)()
+
+	// Fills the engine has not applied yet are not external changes
+	if err := e.reconcileManualOrders(ctx, symbol); err != nil {
+		e.logger.Errorf("Failed to reconcile manual orders for %s: %v", symbol, err)
+	}
+	position, err := e.repository.GetPosition(symbol, "LONG")
+	if err != nil && err != gorm.ErrRecordNotFound {
+		e.logger.Errorf("Failed to get position for %s: %v", symbol, err)
+		return
+	}
+	if err == gorm.ErrRecordNotFound {
+		position = nil
+	}
+	if position != nil {
+		if position, err = e.reconcileChildOrders(ctx, position); err != nil {
+			e.logger.Errorf("Failed to reconcile child orders for %s: %v", symbol, err)
+		}
+	}
+
+	// The snapshot predates the lock; an order may have filled in between
+	if positionDiffers(position, live) {
+		if live, err = e.livePosition(ctx, symbol); err != nil {
+			e.logger.Errorf("Failed to confirm exchange position for %s: %v", symbol, err)
+			return
+		}
+	}
+
+	switch {
+	case position == nil && live == nil:
+	case position == nil:
+		change := &positionChange{Symbol: symbol, Change: "unknown", ExchangeSize: live.PositionAmt, ExchangeEntry: live.EntryPrice}
+		reported[symbol] = live.PositionAmt
+		e.reportPositionChange(ctx, change, "The exchange holds a position the engine does not manage")
+	case live == nil || live.PositionAmt < 0:
+		change := &positionChange{PositionID: position.ID, Symbol: symbol, Change: "closed", DBSize: position.Size, DBEntry: position.EntryPrice}
+		if live != nil {
+			change.ExchangeSize = live.PositionAmt
+		}
+		if err := e.closeGonePosition(ctx, position, "closed on the exchange outside the engine"); err != nil {
+			e.logger.Errorf("Failed to close position for %s: %v", symbol, err)
+			return
+		}
+		e.reportPositionChange(ctx, change, "The position was closed outside the engine and is recorded as closed")
+	default:
+		position.MarkPrice = live.MarkPrice
+		position.UnrealizedPnL = live.UnrealizedPnL
+		position.Percentage = live.Percentage
+		position.Margin = live.Margin
+		position.MaintenanceMargin = live.MaintenanceMargin
+		if !positionDiffers(position, live) {
+			err := e.repository.UpdatePosition(position)
+			if errors.Is(err, database.ErrVersionConflict) {
+				// The trading loop changed the position meanwhile; the next sync reads it again
+				e.logger.Debugf("Position for %s changed during the sync, skipping the market refresh", symbol)
+			} else if err != nil {
+				e.logger.Errorf("Failed to update position for %s: %v", symbol, err)
+			}
+			return
+		}
+
+		change := &positionChange{
+			PositionID:    position.ID,
+			Symbol:        symbol,
+			Change:        "resized",
+			DBSize:        position.Size,
+			ExchangeSize:  live.PositionAmt,
+			DBEntry:       position.EntryPrice,
+			ExchangeEntry: live.EntryPrice,
+		}
+		if err := e.resizePosition(ctx, position, live); err != nil {
+			e.logger.Errorf("Failed to resize position for %s: %v", symbol, err)
+			return
+		}
+		e.reportPositionChange(ctx, change, "The position size was changed outside the engine; size and entry now follow the exchange")
+	}
+}
+
+// livePosition returns the current exchange position of a symbol, or nil when it is flat
+func (e *Engine) livePosition(ctx context.Context, symbol string) (*exchange.PositionInfo, error) {
+	positions, err := e.exchangeClient.GetPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange positions: %w", err)
+	}
+	for _, position := range positions {
+		if position.Symbol == symbol && position.PositionAmt != 0 {
+			return position, nil
+		}
+	}
+	return nil, nil
+}
+
+// positionDiffers reports whether the DB position and the exchange position disagree on
+// whether the position exists or on its size
+func positionDiffers(position *models.Position, live *exchange.PositionInfo) bool {
+	if position == nil || live == nil {
+		return (position == nil) != (live == nil)
+	}
+	return math.Abs(live.PositionAmt-position.Size) > 1e-9
+}
+
+// reportPositionChange logs, audits, publishes and notifies an external position change
+func (e *Engine) reportPositionChange(ctx context.Context, change *positionChange, summary string) {
+	e.logger.Warnf("External position change for %s: %s (db=%.6f exchange=%.6f)", change.Symbol, change.Change, change.DBSize, change.ExchangeSize)
+	e.recordAudit("position", "external_"+change.Change, change.Symbol, change)
+	e.publishEvent(events.TypePosition, "external_change", change.Symbol, change)
+
+	msg := &notify.Message{
+		Title: fmt.Sprintf("Position %s changed outside the engine", change.Symbol),
+		Body: fmt.Sprintf("%s.\nDB size %.6f @ %.6f, exchange size %.6f @ %.6f",
+			summary, change.DBSize, change.DBEntry, change.ExchangeSize, change.ExchangeEntry),
+		Level: notify.LevelWarning,
+		Time:  time.Now(),
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver position change notification: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package trading
+
+import (
+	"context"
+	"time"
+
+	"contract_playground/internal/models"
+)
+
+// positioningSnapshot is the latest positioning data for a symbol
+type positioningSnapshot struct {
+	openInterest       float64
+	openInterestChange float64 // Percent change since the previous sample
+	longShortRatio     float64
+	longAccount        float64
+	shortAccount       float64
+}
+
+// collectPositioningData periodically samples open interest and long/short ratios
+func (e *Engine) collectPositioningData(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(e.config.Positioning.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		for _, symbol := range e.config.Symbols {
+			if err := e.updatePositioningData(ctx, symbol); err != nil {
+				e.logger.Warnf("Failed to update positioning data for %s: %v", symbol, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// updatePositioningData fetches, caches and persists positioning data for a symbol
+func (e *Engine) updatePositioningData(ctx context.Context, symbol string) error {
+	oi, err := e.exchangeClient.GetOpenInterest(ctx, symbol)
+	if err != nil {
+		return err
+	}
+
+	ratio, err := e.exchangeClient.GetLongShortRatio(ctx, symbol, e.config.Positioning.Period)
+	if err != nil {
+		return err
+	}
+
+	price := 0.0
+	if data, err := e.getMarketData(symbol); err == nil {
+		price = data.Price
+	}
+
+	e.marketDataMu.Lock()
+	previous := e.positioning[symbol]
+	snapshot := &positioningSnapshot{
+		openInterest:   oi.OpenInterest,
+		longShortRatio: ratio.LongShortRatio,
+		longAccount:    ratio.LongAccount,
+		shortAccount:   ratio.ShortAccount,
+	}
+	if previous != nil && previous.openInterest > 0 {
+		snapshot.openInterestChange = (oi.OpenInterest - previous.openInterest) / previous.openInterest * 100
+	}
+	e.positioning[symbol] = snapshot
+	e.marketDataMu.Unlock()
+
+	return e.repository.SavePositioningData(&models.PositioningData{
+		Symbol:            symbol,
+		OpenInterest:      oi.OpenInterest,
+		OpenInterestValue: e.contracts.get(symbol).Notional(oi.OpenInterest, price),
+		LongShortRatio:    ratio.LongShortRatio,
+		LongAccount:       ratio.LongAccount,
+		ShortAccount:      ratio.ShortAccount,
+		Timestamp:         time.Now().Unix(),
+	})
+}
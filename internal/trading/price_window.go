@@ -0,0 +1,141 @@
+package trading
+
+import (
+	"math"
+
+	"contract_playground/internal/indicators"
+)
+
+// rollingPrices is a symbol's recent price history with running sums, so the SMA and RSI
+// strategies update their indicators in O(1) per price instead of rescanning the window
+type rollingPrices struct {
+	prices *indicators.Ring
+
+	// Running sums of the trailing prices, one per averaged period
+	periods []int
+	sums    []float64
+
+	// Running gain and loss sums over the trailing `changes` price changes. lossCount is the
+	// number of falling changes among them, so an all-gains window reports exactly zero loss.
+	changes   int
+	gainSum   float64
+	lossSum   float64
+	lossCount int
+
+	// Pushes since the sums were last recomputed from the window
+	pushes int
+}
+
+// newRollingPrices creates a history keeping capacity prices, with running averages over
+// periods and gain/loss sums over changes price changes, seeded with history (oldest first)
+func newRollingPrices(capacity int, periods []int, changes int, history []float64) *rollingPrices {
+	h := &rollingPrices{
+		prices:  indicators.NewRing(capacity),
+		periods: periods,
+		sums:    make([]float64, len(periods)),
+		changes: changes,
+	}
+	if len(history) > capacity {
+		history = history[len(history)-capacity:]
+	}
+	for _, price := range history {
+		h.prices.Push(price)
+	}
+	h.resync()
+	return h
+}
+
+// push adds a price, updating the running sums with the values entering and leaving each window
+func (h *rollingPrices) push(price float64) {
+	n := h.prices.Len()
+	for i, period := range h.periods {
+		if n >= period {
+			h.sums[i] -= h.prices.Last(period - 1)
+		}
+		h.sums[i] += price
+	}
+	if h.changes > 0 && n >= 1 {
+		h.addChange(price-h.prices.Last(0), 1)
+		if n > h.changes {
+			h.addChange(h.prices.Last(h.changes-1)-h.prices.Last(h.changes), -1)
+		}
+	}
+	h.prices.Push(price)
+
+	// Bound the float error the running sums accumulate; amortized O(1) per push
+	h.pushes++
+	if h.pushes >= h.prices.Cap() {
+		h.resync()
+	}
+}
+
+// addChange adds (sign 1) or removes (sign -1) a price change from the gain/loss sums
+func (h *rollingPrices) addChange(change float64, sign int) {
+	if change > 0 {
+		h.gainSum += float64(sign) * change
+		return
+	}
+	h.lossSum -= float64(sign) * change
+	if change < 0 {
+		h.lossCount += sign
+	}
+}
+
+// resync recomputes the running sums from the kept prices
+func (h *rollingPrices) resync() {
+	n := h.prices.Len()
+	for i, period := range h.periods {
+		h.sums[i] = 0
+		for j := 0; j < period && j < n; j++ {
+			h.sums[i] += h.prices.Last(j)
+		}
+	}
+	h.gainSum, h.lossSum, h.lossCount = 0, 0, 0
+	for j := 0; j < h.changes && j+1 < n; j++ {
+		h.addChange(h.prices.Last(j)-h.prices.Last(j+1), 1)
+	}
+	h.pushes = 0
+}
+
+// len returns the number of prices kept
+func (h *rollingPrices) len() int {
+	return h.prices.Len()
+}
+
+// average returns the mean of the last period prices, or 0 with fewer prices kept
+func (h *rollingPrices) average(period int) float64 {
+	if h.prices.Len() < period {
+		return 0
+	}
+	for i, p := range h.periods {
+		if p == period {
+			return h.sums[i] / float64(period)
+		}
+	}
+
+	sum := 0.0
+	for j := 0; j < period; j++ {
+		sum += h.prices.Last(j)
+	}
+	return sum / float64(period)
+}
+
+// rsi returns the RSI of the simple average gain and loss over the tracked changes, or 50
+// (neutral) with fewer prices kept
+func (h *rollingPrices) rsi() float64 {
+	if h.changes < 1 || h.prices.Len() < h.changes+1 {
+		return 50
+	}
+	if h.lossCount == 0 {
+		return 100
+	}
+
+	// Removing gains can leave a float remainder just below zero
+	rs := math.Max(0, h.gainSum) / h.lossSum
+	return 100 - (100 / (1 + rs))
+}
+
+// values returns a copy of the kept prices, oldest first
+func (h *rollingPrices) values() []float64 {
+	return h.prices.Values()
+}
@@ -0,0 +1,191 @@
+package trading
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Number of symbols updated each tick in the benchmarks
+const benchSymbols = 100
+
+// randomWalk returns n prices starting at 100
+func randomWalk(rng *rand.Rand, n int) []float64 {
+	prices := make([]float64, n)
+	price := 100.0
+	for i := range prices {
+		// Some flat ticks so the RSI sees zero changes
+		if rng.Intn(5) > 0 {
+			price *= 1 + rng.NormFloat64()*0.002
+		}
+		prices[i] = price
+	}
+	return prices
+}
+
+// rescanSMA is the full-window SMA the rolling sums replace
+func rescanSMA(prices []float64, period int) float64 {
+	if len(prices) < period {
+		return 0
+	}
+	sum := 0.0
+	for _, price := range prices[len(prices)-period:] {
+		sum += price
+	}
+	return sum / float64(period)
+}
+
+// rescanRSI is the full-window RSI the rolling gain/loss sums replace
+func rescanRSI(prices []float64, period int) float64 {
+	if len(prices) < period+1 {
+		return 50
+	}
+	gains := make([]float64, 0)
+	losses := make([]float64, 0)
+	for i := 1; i < len(prices); i++ {
+		change := prices[i] - prices[i-1]
+		if change > 0 {
+			gains = append(gains, change)
+			losses = append(losses, 0)
+		} else {
+			gains = append(gains, 0)
+			losses = append(losses, -change)
+		}
+	}
+	avgGain, avgLoss := rescanSMA(gains, period), rescanSMA(losses, period)
+	if avgLoss == 0 {
+		return 100
+	}
+	return 100 - (100 / (1 + avgGain/avgLoss))
+}
+
+func TestRollingPricesMatchesRescan(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	prices := randomWalk(rng, 5000)
+	history := newRollingPrices(30, []int{10, 20}, 14, prices[:7])
+
+	for i := 7; i < len(prices); i++ {
+		history.push(prices[i])
+		window := prices[:i+1]
+		for _, period := range []int{10, 20, 5} {
+			if got, want := history.average(period), rescanSMA(window, period); math.Abs(got-want) > 1e-9 {
+				t.Fatalf("price %d: SMA(%d) = %v, want %v", i, period, got, want)
+			}
+		}
+		if got, want := history.rsi(), rescanRSI(window, 14); math.Abs(got-want) > 1e-6 {
+			t.Fatalf("price %d: RSI = %v, want %v", i, got, want)
+		}
+	}
+
+	values := history.values()
+	if len(values) != 30 || values[29] != prices[len(prices)-1] || values[0] != prices[len(prices)-30] {
+		t.Fatalf("values() kept the wrong window: %v", values)
+	}
+}
+
+func TestRollingPricesOnlyGains(t *testing.T) {
+	history := newRollingPrices(20, nil, 5, nil)
+	// Losses that leave the window must not keep the RSI below 100
+	for _, price := range []float64{10, 9.7, 9.1, 9.3, 9.9, 10.1, 10.4, 10.6, 10.9, 11.3, 11.4} {
+		history.push(price)
+	}
+	if got := history.rsi(); got != 100 {
+		t.Fatalf("RSI after only gains = %v, want 100", got)
+	}
+}
+
+func TestStrategyRewindowsHistoryOnInitialize(t *testing.T) {
+	strategy := NewSMAStrategy().(*SMAStrategy)
+	prices := randomWalk(rand.New(rand.NewSource(2)), 40)
+	for _, price := range prices {
+		strategy.updatePriceHistory("BTCUSDT", price)
+	}
+
+	if err := strategy.Initialize(map[string]interface{}{"short_period": 5.0, "long_period": 25.0}); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	indicators := strategy.Indicators("BTCUSDT")
+	if got, want := indicators["long_sma"], rescanSMA(prices, 25); math.Abs(got-want) > 1e-9 {
+		t.Fatalf("long SMA after re-initialize = %v, want %v", got, want)
+	}
+	if got, want := indicators["short_sma"], rescanSMA(prices, 5); math.Abs(got-want) > 1e-9 {
+		t.Fatalf("short SMA after re-initialize = %v, want %v", got, want)
+	}
+}
+
+// benchTicks returns per-symbol price walks for the benchmarks
+func benchTicks(n int) (symbols []string, prices [][]float64) {
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < benchSymbols; i++ {
+		symbols = append(symbols, fmt.Sprintf("SYM%dUSDT", i))
+		prices = append(prices, randomWalk(rng, n))
+	}
+	return symbols, prices
+}
+
+// One op is a 1s tick: a new price for every symbol and its updated indicators
+func BenchmarkSMAStrategyTick(b *testing.B) {
+	symbols, prices := benchTicks(4096)
+
+	b.Run("rolling", func(b *testing.B) {
+		strategy := NewSMAStrategy().(*SMAStrategy)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for s, symbol := range symbols {
+				strategy.updatePriceHistory(symbol, prices[s][i%len(prices[s])])
+			}
+		}
+	})
+
+	b.Run("rescan", func(b *testing.B) {
+		histories := make(map[string][]float64)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for s, symbol := range symbols {
+				// The copy-and-rescan update the rolling history replaced
+				history := append(histories[symbol], prices[s][i%len(prices[s])])
+				if len(history) > 30 {
+					history = history[len(history)-30:]
+				}
+				histories[symbol] = history
+				window := append([]float64(nil), history...)
+				rescanSMA(window, 10)
+				rescanSMA(window, 20)
+			}
+		}
+	})
+}
+
+func BenchmarkRSIStrategyTick(b *testing.B) {
+	symbols, prices := benchTicks(4096)
+
+	b.Run("rolling", func(b *testing.B) {
+		strategy := NewRSIStrategy().(*RSIStrategy)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for s, symbol := range symbols {
+				strategy.updatePriceHistory(symbol, prices[s][i%len(prices[s])])
+			}
+		}
+	})
+
+	b.Run("rescan", func(b *testing.B) {
+		histories := make(map[string][]float64)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for s, symbol := range symbols {
+				history := append(histories[symbol], prices[s][i%len(prices[s])])
+				if len(history) > 34 {
+					history = history[len(history)-34:]
+				}
+				histories[symbol] = history
+				rescanRSI(append([]float64(nil), history...), 14)
+			}
+		}
+	})
+}
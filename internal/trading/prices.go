@@ -0,0 +1,82 @@
+package trading
+
+import (
+	"contract_playground/internal/exchange"
+)
+
+// Working price types, mirroring exchange.OrderRequest.WorkingType
+const (
+	WorkingTypeMarkPrice     = "MARK_PRICE"
+	WorkingTypeContractPrice = "CONTRACT_PRICE"
+)
+
+// WorkingPrice returns the price a check should use for the given working type.
+// Mark price falls back to the last price until the first mark price is known.
+func (d *MarketData) WorkingPrice(workingType string) float64 {
+	if workingType == WorkingTypeMarkPrice && d.MarkPrice > 0 {
+		return d.MarkPrice
+	}
+	return d.Price
+}
+
+// markPriceHandler caches streamed mark prices and forwards them to the PnL alerter
+type markPriceHandler struct {
+	engine *Engine
+}
+
+// OnMarkPrice stores the latest mark price for the symbol
+func (h *markPriceHandler) OnMarkPrice(update *exchange.MarkPriceUpdate) {
+	h.engine.heartbeats.beat(HealthMarkPriceStream)
+	if update.MarkPrice > 0 {
+		h.engine.setMarkPrice(update.Symbol, update.MarkPrice)
+	}
+
+	if h.engine.pnlAlerter != nil {
+		h.engine.pnlAlerter.OnMarkPrice(update)
+	}
+}
+
+// OnError logs stream errors; the stream reconnects on its own
+func (h *markPriceHandler) OnError(err error) {
+	h.engine.logger.Warnf("Mark price stream error: %v", err)
+}
+
+// setMarkPrice updates the cached mark price for a symbol
+func (e *Engine) setMarkPrice(symbol string, markPrice float64) {
+	e.marketDataMu.Lock()
+	e.markPrices[symbol] = markPrice
+	e.marketDataMu.Unlock()
+}
+
+// updatePositionPrices stores the latest mark and last price on every open position and
+// recomputes unrealized PnL using the configured PnL working type
+func (e *Engine) updatePositionPrices() error {
+	positions, err := e.repository.GetAllPositions()
+	if err != nil {
+		return err
+	}
+
+	for _, position := range positions {
+		data, err := e.getMarketData(position.Symbol)
+		if err != nil {
+			continue
+		}
+
+		price := data.WorkingPrice(e.config.PnLWorkingType)
+		pnl := e.positionPnL(position.Symbol, position.EntryPrice, price, position.Size)
+		if position.PositionSide == "SHORT" {
+			pnl = -pnl
+		}
+
+		percentage := 0.0
+		if notional := e.contracts.get(position.Symbol).Notional(position.Size, position.EntryPrice); notional > 0 {
+			percentage = pnl / notional * 100
+		}
+
+		if err := e.repository.UpdatePositionPrices(position.ID, data.MarkPrice, data.Price, pnl, percentage); err != nil {
+			e.logger.Errorf("Failed to update prices for position %d: %v", position.ID, err)
+		}
+	}
+
+	return nil
+}
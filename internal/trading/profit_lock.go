@@ -0,0 +1,165 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/notify"
+)
+
+// ProfitLockStatus is the day's realized PnL against the profit lock target
+type ProfitLockStatus struct {
+	DayStart    time.Time  `json:"day_start"`
+	RealizedPnL float64    `json:"realized_pnl"` // Net of commissions and funding
+	Target      float64    `json:"target"`
+	Action      string     `json:"action"`
+	Locked      bool       `json:"locked"`
+	LockedAt    *time.Time `json:"locked_at,omitempty"`
+}
+
+// ProfitLock sums the net PnL of positions closed since midnight in the configured zone and
+// locks the rest of the day once it reaches the target
+type ProfitLock struct {
+	config   config.ProfitLockConfig
+	location *time.Location
+
+	mu       sync.Mutex
+	dayStart time.Time
+	realized float64
+	lockedAt *time.Time
+}
+
+// NewProfitLock creates a profit lock for the day containing now
+func NewProfitLock(cfg config.ProfitLockConfig, now time.Time) (*ProfitLock, error) {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", cfg.Timezone, err)
+	}
+	return &ProfitLock{config: cfg, location: location, dayStart: startOfDay(now, location)}, nil
+}
+
+// rollover starts a new day once now has passed midnight; callers hold mu
+func (p *ProfitLock) rollover(now time.Time) {
+	if start := startOfDay(now, p.location); start.After(p.dayStart) {
+		p.dayStart = start
+		p.realized = 0
+		p.lockedAt = nil
+	}
+}
+
+// Record adds the net PnL of a closed position and reports whether it locked the day
+func (p *ProfitLock) Record(pnl float64, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rollover(now)
+	p.realized += pnl
+	if p.lockedAt == nil && p.realized >= p.config.Target {
+		p.lockedAt = &now
+		return true
+	}
+	return false
+}
+
+// Locked reports whether the day's target has been reached
+func (p *ProfitLock) Locked(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rollover(now)
+	return p.lockedAt != nil
+}
+
+// Scale returns the factor applied to new position sizes: ReduceScale once a day locked with
+// the reduce action, 1 otherwise
+func (p *ProfitLock) Scale() float64 {
+	if p.config.Action != config.ProfitLockActionReduce || !p.Locked(time.Now()) {
+		return 1
+	}
+	return p.config.ReduceScale
+}
+
+// Status returns the day's progress toward the target
+func (p *ProfitLock) Status(now time.Time) *ProfitLockStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rollover(now)
+	return &ProfitLockStatus{
+		DayStart:    p.dayStart,
+		RealizedPnL: p.realized,
+		Target:      p.config.Target,
+		Action:      p.config.Action,
+		Locked:      p.lockedAt != nil,
+		LockedAt:    p.lockedAt,
+	}
+}
+
+// loadProfitLock seeds the day's realized PnL from positions closed since midnight, so a
+// restart neither forgets a locked day nor lets the target be earned twice
+func (e *Engine) loadProfitLock() {
+	if e.profitLock == nil {
+		return
+	}
+	now := time.Now()
+	start := e.profitLock.Status(now).DayStart
+	positions, err := e.repository.GetClosedPositions(start, now)
+	if err != nil {
+		e.logger.Errorf("Failed to load today's closed positions for the profit lock: %v", err)
+		return
+	}
+	var realized float64
+	for _, position := range positions {
+		realized += position.NetPnL
+	}
+	if e.profitLock.Record(realized, now) {
+		e.logger.Warnf("Daily profit target %.2f already reached with %.2f realized, new entries %s until the next day",
+			e.config.ProfitLock.Target, realized, profitLockEffect(e.config.ProfitLock.Action))
+	}
+}
+
+// recordProfitLock counts a closed position toward the daily target and notifies when it
+// locks the day
+func (e *Engine) recordProfitLock(ctx context.Context, pnl float64) {
+	if e.profitLock == nil {
+		return
+	}
+	now := time.Now()
+	if !e.profitLock.Record(pnl, now) {
+		return
+	}
+
+	status := e.profitLock.Status(now)
+	effect := profitLockEffect(status.Action)
+	e.logger.Warnf("Daily profit target %.2f reached with %.2f realized, new entries %s until the next day",
+		status.Target, status.RealizedPnL, effect)
+	e.recordAudit("risk", "profit_lock", "", status)
+
+	msg := &notify.Message{
+		Title: "Daily profit target reached",
+		Body: fmt.Sprintf("Realized %.2f USDT today against a target of %.2f USDT.\n"+
+			"New entries are %s until %s; open positions are still managed.",
+			status.RealizedPnL, status.Target, effect, status.DayStart.AddDate(0, 0, 1).Format("2006-01-02 15:04 MST")),
+		Level: notify.LevelInfo,
+		Time:  now,
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver profit lock notification: %v", err)
+	}
+}
+
+// profitLockFilter blocks entries once a day locked with the stop action
+func (e *Engine) profitLockFilter() string {
+	if e.profitLock == nil || e.config.ProfitLock.Action != config.ProfitLockActionStop || !e.profitLock.Locked(time.Now()) {
+		return ""
+	}
+	return fmt.Sprintf("daily profit target %.2f reached", e.config.ProfitLock.Target)
+}
+
+// profitLockEffect describes what a locked day does to new entries
+func profitLockEffect(action string) string {
+	if action == config.ProfitLockActionReduce {
+		return "reduced"
+	}
+	return "stopped"
+}
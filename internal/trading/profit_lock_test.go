@@ -0,0 +1,38 @@
+package trading
+
+import (
+	"testing"
+	"time"
+
+	"contract_playground/internal/config"
+)
+
+func TestProfitLockResetsAtZoneMidnight(t *testing.T) {
+	cfg := config.ProfitLockConfig{Target: 100, Action: config.ProfitLockActionReduce, ReduceScale: 0.5, Timezone: "Asia/Shanghai"}
+	// 15:00 UTC is 23:00 in Shanghai
+	evening := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	lock, err := NewProfitLock(cfg, evening)
+	if err != nil {
+		t.Skipf("no time zone data: %v", err)
+	}
+
+	if lock.Record(60, evening) || lock.Locked(evening) {
+		t.Fatal("locked below the target")
+	}
+	if !lock.Record(50, evening.Add(10*time.Minute)) {
+		t.Fatal("reaching the target did not lock the day")
+	}
+	if lock.Record(10, evening.Add(20*time.Minute)) {
+		t.Error("an already locked day locked again")
+	}
+
+	// Still 2024-03-01 in UTC but a new day in Shanghai
+	midnight := evening.Add(90 * time.Minute)
+	status := lock.Status(midnight)
+	if status.Locked || status.RealizedPnL != 0 {
+		t.Errorf("status after Shanghai midnight = %+v, want a fresh day", status)
+	}
+	if want := time.Date(2024, 3, 1, 16, 0, 0, 0, time.UTC); !status.DayStart.Equal(want) {
+		t.Errorf("day start = %v, want %v", status.DayStart, want)
+	}
+}
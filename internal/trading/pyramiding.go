@@ -0,0 +1,66 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+)
+
+// pyramidReason returns why an add-on to the position is not allowed at price, or "" if it is
+func (e *Engine) pyramidReason(position *models.Position, price, quantity float64) string {
+	cfg := e.config.Pyramiding
+	if !cfg.Enabled {
+		return "pyramiding disabled"
+	}
+
+	if position.AddOns >= cfg.MaxAddOns {
+		return fmt.Sprintf("max add-ons reached (%d)", cfg.MaxAddOns)
+	}
+
+	last := position.LastAddPrice
+	if last <= 0 {
+		last = position.EntryPrice
+	}
+	if threshold := last * (1 + cfg.SpacingPercent/100); price < threshold {
+		return fmt.Sprintf("price %.6f below add-on threshold %.6f", price, threshold)
+	}
+
+	if value := (position.Size + quantity) * price; value > e.riskManager.Limits().MaxPositionSize {
+		return fmt.Sprintf("position value %.2f would exceed max position size %.2f", value, e.riskManager.Limits().MaxPositionSize)
+	}
+
+	return ""
+}
+
+// addOnSignal scales the strategy's signal for the next add according to the size decay
+func (e *Engine) addOnSignal(position *models.Position, signal *Signal) *Signal {
+	scaled := *signal
+	scaled.Quantity = signal.Quantity * math.Pow(e.config.Pyramiding.SizeDecay, float64(position.AddOns+1))
+	return &scaled
+}
+
+// mergeAddOn merges an add-on fill into the position, keeping a volume-weighted average
+// entry price; the caller saves the position
+func (e *Engine) mergeAddOn(position *models.Position, response *exchange.OrderResponse) {
+	size := position.Size + response.ExecutedQty
+	position.EntryPrice = e.averageEntry(position.Symbol, position.EntryPrice, position.Size, response.AvgPrice, response.ExecutedQty)
+	position.Size = size
+	position.AddOns++
+	position.LastAddPrice = response.AvgPrice
+}
+
+// addToPosition follows up a saved add-on: take-profit orders are re-placed for the new size
+func (e *Engine) addToPosition(ctx context.Context, position *models.Position, response *exchange.OrderResponse) {
+	e.logger.Infof("Added %.6f to %s position (add-on %d): size=%.6f, avg entry=%.6f",
+		response.ExecutedQty, position.Symbol, position.AddOns, position.Size, position.EntryPrice)
+
+	// The position keeps its ladder: unfilled levels are re-placed relative to the new
+	// average entry and sized for the new position
+	e.cancelTakeProfitOrders(ctx, position)
+	e.placeTakeProfitOrders(ctx, position)
+	e.saveCooldown(e.riskManager.RecordEntry(position.Symbol, time.Now()))
+}
@@ -0,0 +1,115 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"contract_playground/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Default per-evaluation budget of a Python strategy
+const defaultPythonTimeout = time.Second
+
+// PythonStrategy runs a Python script built on pkg/strategysdk/python/strategysdk.py as an
+// external strategy. Unlike a plain external strategy it never fails an evaluation: a slow,
+// crashed or erroring script yields HOLD, so research code cannot stall or break the tick.
+type PythonStrategy struct {
+	external *ExternalStrategy
+	timeout  time.Duration
+}
+
+// NewPythonStrategy creates a Python strategy; the script comes from Initialize
+func NewPythonStrategy() Strategy {
+	return &PythonStrategy{external: newExternalStrategy(), timeout: defaultPythonTimeout}
+}
+
+// Name returns the name the script reported, or the script's file name
+func (p *PythonStrategy) Name() string {
+	return p.external.Name()
+}
+
+// SetLogger sets the logger the script's stderr and fallbacks are logged to
+func (p *PythonStrategy) SetLogger(logger *logrus.Logger) {
+	p.external.SetLogger(logger)
+}
+
+// SetProcessLimits applies resource limits to the interpreter
+func (p *PythonStrategy) SetProcessLimits(limits ProcessLimits) {
+	p.external.SetProcessLimits(limits)
+}
+
+// Close stops the interpreter
+func (p *PythonStrategy) Close() error {
+	return p.external.Close()
+}
+
+// Initialize reads script, python (the interpreter, python3 by default) and timeout_ms;
+// the remaining parameters are passed to the script's initialize
+func (p *PythonStrategy) Initialize(config map[string]interface{}) error {
+	script, _ := getStringParam(config, "script")
+	if script == "" {
+		return fmt.Errorf("python strategy requires a script parameter")
+	}
+	python, _ := getStringParam(config, "python")
+	if python == "" {
+		python = "python3"
+	}
+	timeout := defaultPythonTimeout
+	if ms, ok := getFloatParam(config, "timeout_ms"); ok {
+		if ms <= 0 {
+			return fmt.Errorf("timeout_ms must be positive, got %v", ms)
+		}
+		timeout = time.Duration(ms) * time.Millisecond
+	}
+
+	external := make(map[string]interface{}, len(config)+1)
+	for key, value := range config {
+		switch key {
+		case "script", "python", "timeout_ms":
+		default:
+			external[key] = value
+		}
+	}
+	// Unbuffered, so each response line reaches the engine as soon as it is written
+	external["command"] = python
+	external["args"] = []string{"-u", script}
+	if _, ok := external["name"]; !ok {
+		external["name"] = "Python " + strings.TrimSuffix(filepath.Base(script), filepath.Ext(script))
+	}
+
+	if err := p.external.Initialize(external); err != nil {
+		return err
+	}
+	p.timeout = timeout
+	return nil
+}
+
+// ShouldBuy asks the script for an entry, holding when it cannot answer in time
+func (p *PythonStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	signal, err := p.external.ShouldBuy(ctx, symbol, data)
+	return p.orHold(symbol, signal, err), nil
+}
+
+// ShouldSell asks the script about the open position, holding when it cannot answer in time
+func (p *PythonStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	signal, err := p.external.ShouldSell(ctx, symbol, data, position)
+	return p.orHold(symbol, signal, err), nil
+}
+
+// orHold falls back to HOLD when the script failed
+func (p *PythonStrategy) orHold(symbol string, signal *Signal, err error) *Signal {
+	if err == nil {
+		return signal
+	}
+	p.external.logger.Warnf("Python strategy %s failed on %s, holding: %v", p.Name(), symbol, err)
+	return holdSignal(fmt.Sprintf("python strategy unavailable: %v", err))
+}
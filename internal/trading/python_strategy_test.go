@@ -0,0 +1,103 @@
+package trading
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"contract_playground/internal/exchange"
+)
+
+func TestPythonStrategy(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 is not installed")
+	}
+	sdk, err := filepath.Abs("../../pkg/strategysdk/python")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strategy := NewPythonStrategy().(*PythonStrategy)
+	defer strategy.Close()
+	if err := strategy.Initialize(map[string]interface{}{
+		"script":       filepath.Join(sdk, "example_sma.py"),
+		"short_period": 2.0,
+		"long_period":  4.0,
+		"timeout_ms":   5000.0,
+	}); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	data := &MarketData{Symbol: "BTCUSDT", Price: 14, Timestamp: time.Now(), Klines: testKlines(10, 11, 12, 13, 14)}
+	signal, err := strategy.ShouldBuy(context.Background(), "BTCUSDT", data)
+	if err != nil || signal.Action != "BUY" || signal.Confidence <= 0 {
+		t.Fatalf("rising closes: %+v, %v", signal, err)
+	}
+	if strategy.Name() != "Python SMA" {
+		t.Fatalf("name %q", strategy.Name())
+	}
+
+	// Errors raised by the script hold instead of failing the evaluation
+	if err := strategy.Initialize(map[string]interface{}{
+		"script":       filepath.Join(sdk, "example_sma.py"),
+		"short_period": 4.0,
+		"long_period":  2.0,
+	}); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	if signal, err := strategy.ShouldBuy(context.Background(), "BTCUSDT", data); err != nil || signal.Action != "HOLD" {
+		t.Fatalf("invalid parameters: %+v, %v", signal, err)
+	}
+}
+
+func TestPythonStrategyTimeout(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 is not installed")
+	}
+	sdk, err := filepath.Abs("../../pkg/strategysdk/python")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PYTHONPATH", sdk)
+
+	script := filepath.Join(t.TempDir(), "slow.py")
+	source := `import time
+from strategysdk import Strategy, serve
+
+class Slow(Strategy):
+    def should_buy(self, market_data):
+        time.sleep(60)
+
+serve(Slow())
+`
+	if err := os.WriteFile(script, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	strategy := NewPythonStrategy().(*PythonStrategy)
+	defer strategy.Close()
+	if err := strategy.Initialize(map[string]interface{}{"script": script, "timeout_ms": 1000.0}); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+
+	started := time.Now()
+	signal, err := strategy.ShouldBuy(context.Background(), "BTCUSDT", &MarketData{Symbol: "BTCUSDT", Timestamp: time.Now()})
+	if err != nil || signal.Action != "HOLD" {
+		t.Fatalf("slow script: %+v, %v", signal, err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("timeout took %s", elapsed)
+	}
+}
+
+// testKlines returns one-minute klines closing at the given prices
+func testKlines(closes ...float64) []*exchange.KlineData {
+	klines := make([]*exchange.KlineData, len(closes))
+	for i, price := range closes {
+		klines[i] = &exchange.KlineData{OpenTime: int64(i) * 60000, Open: price, High: price, Low: price, Close: price, CloseTime: int64(i+1)*60000 - 1}
+	}
+	return klines
+}
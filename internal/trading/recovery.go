@@ -0,0 +1,323 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"contract_playground/internal/auditlog"
+	"contract_playground/internal/database"
+	"contract_playground/internal/events"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+)
+
+// Positions found on the exchange without a DB record are adopted under this strategy name
+const adoptedStrategy = "adopted"
+
+// RecoveryReport summarizes the startup reconciliation with the exchange
+type RecoveryReport struct {
+	Resumed       []string `json:"resumed"`        // DB positions confirmed on the exchange
+	Resized       []string `json:"resized"`        // DB positions whose size was corrected
+	ClosedStale   []string `json:"closed_stale"`   // DB positions no longer on the exchange
+	Adopted       []string `json:"adopted"`        // Exchange positions recorded as new DB positions
+	Unknown       []string `json:"unknown"`        // Exchange positions left alone
+	UpdatedOrders []string `json:"updated_orders"` // DB orders that finished while the engine was down
+	UnknownOrders []string `json:"unknown_orders"` // Exchange orders without a DB record
+}
+
+func (r *RecoveryReport) discrepancies() int {
+	return len(r.Resized) + len(r.ClosedStale) + len(r.Adopted) + len(r.Unknown) + len(r.UpdatedOrders) + len(r.UnknownOrders)
+}
+
+// recoverState reconciles DB positions and orders with the exchange before trading starts,
+// so that a restart neither orphans live positions nor manages positions that are gone
+func (e *Engine) recoverState(ctx context.Context) (*RecoveryReport, error) {
+	report := &RecoveryReport{}
+
+	exchangePositions, err := e.exchangeClient.GetPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange positions: %w", err)
+	}
+	live := make(map[string]*exchange.PositionInfo, len(exchangePositions))
+	for _, position := range exchangePositions {
+		live[position.Symbol] = position
+	}
+
+	dbPositions, err := e.repository.GetAllPositions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	known := make(map[string]bool, len(dbPositions))
+	for _, position := range dbPositions {
+		known[position.Symbol] = true
+		e.recoverPosition(ctx, position, live[position.Symbol], report)
+	}
+
+	for _, position := range exchangePositions {
+		if !known[position.Symbol] {
+			e.recoverUnknownPosition(ctx, position, report)
+		}
+	}
+
+	if err := e.recoverOrders(ctx, report); err != nil {
+		e.logger.Errorf("Failed to reconcile open orders: %v", err)
+	}
+
+	e.refreshPnLAlerts()
+	return report, nil
+}
+
+// recoverPosition applies child order fills missed while the engine was down, then checks the
+// DB position against the exchange: it is resumed, resized or marked closed
+func (e *Engine) recoverPosition(ctx context.Context, position *models.Position, live *exchange.PositionInfo, report *RecoveryReport) {
+	position, err := e.reconcileChildOrders(ctx, position)
+	if err != nil {
+		e.logger.Errorf("Failed to reconcile child orders for %s: %v", position.Symbol, err)
+	}
+	if position == nil {
+		return // Closed by take-profit fills; reconcileChildOrders recorded it
+	}
+
+	if live == nil || live.PositionAmt <= 0 {
+		if err := e.closeGonePosition(ctx, position, "closed on the exchange while the engine was down"); err != nil {
+			e.logger.Errorf("Failed to close stale position for %s: %v", position.Symbol, err)
+			return
+		}
+		report.ClosedStale = append(report.ClosedStale, position.Symbol)
+		return
+	}
+
+	if math.Abs(live.PositionAmt-position.Size) > 1e-9 {
+		e.logger.Warnf("Position size for %s differs from the exchange: db=%.6f exchange=%.6f", position.Symbol, position.Size, live.PositionAmt)
+		if err := e.resizePosition(ctx, position, live); err != nil {
+			e.logger.Errorf("Failed to resize position for %s: %v", position.Symbol, err)
+			return
+		}
+		report.Resized = append(report.Resized, position.Symbol)
+		return
+	}
+
+	report.Resumed = append(report.Resumed, position.Symbol)
+}
+
+// closeGonePosition records a DB position as closed after it disappeared from the exchange.
+// The real exit is unknown, so PnL is estimated at the current price.
+func (e *Engine) closeGonePosition(ctx context.Context, position *models.Position, reason string) error {
+	exitPrice := position.EntryPrice
+	if price, err := e.exchangeClient.GetSymbolPrice(ctx, position.Symbol); err == nil {
+		exitPrice = price
+	}
+	pnl := position.ClosedPnL + e.positionPnL(position.Symbol, position.EntryPrice, exitPrice, position.Size)
+
+	note := fmt.Sprintf("%s; PnL estimated at %.6f", reason, exitPrice)
+	err := e.repository.WithTx(func(tx database.Repository) error {
+		if err := tx.ClosePosition(position.ID, exitPrice, pnl); err != nil {
+			return fmt.Errorf("failed to close position: %w", err)
+		}
+		if err := tx.UpdatePositionNotes(position.ID, note); err != nil {
+			return fmt.Errorf("failed to annotate closed position: %w", err)
+		}
+		return e.enqueueEvent(tx, events.TypePosition, "closed", position.Symbol, map[string]interface{}{
+			"position_id": position.ID,
+			"exit_price":  exitPrice,
+			"pnl":         pnl,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	e.cancelChildOrders(ctx, position)
+	netPnL := e.recordClosedPosition(ctx, position, pnl)
+	e.publishEvent(events.TypePosition, "closed", position.Symbol, map[string]interface{}{
+		"position_id": position.ID,
+		"exit_price":  exitPrice,
+		"pnl":         pnl,
+		"net_pnl":     netPnL,
+	})
+	return nil
+}
+
+// resizePosition takes size and entry price from the exchange position and replaces the
+// take-profit orders, which were sized for the old position
+func (e *Engine) resizePosition(ctx context.Context, position *models.Position, live *exchange.PositionInfo) error {
+	position.Size = live.PositionAmt
+	position.EntryPrice = live.EntryPrice
+	if err := e.repository.UpdatePosition(position); err != nil {
+		return fmt.Errorf("failed to update position: %w", err)
+	}
+	e.cancelTakeProfitOrders(ctx, position)
+	e.placeTakeProfitOrders(ctx, position)
+	return nil
+}
+
+// recoverUnknownPosition adopts a long exchange position of a traded symbol when enabled;
+// anything else is only reported
+func (e *Engine) recoverUnknownPosition(ctx context.Context, live *exchange.PositionInfo, report *RecoveryReport) {
+	description := fmt.Sprintf("%s %.6f @ %.6f", live.Symbol, live.PositionAmt, live.EntryPrice)
+	if !e.config.Recovery.AdoptUnknown || live.PositionAmt <= 0 || !e.isTradedSymbol(live.Symbol) {
+		report.Unknown = append(report.Unknown, description)
+		return
+	}
+
+	position := &models.Position{
+		Symbol:       live.Symbol,
+		PositionSide: "LONG",
+		Size:         live.PositionAmt,
+		EntryPrice:   live.EntryPrice,
+		MarkPrice:    live.MarkPrice,
+		LastAddPrice: live.EntryPrice,
+		Leverage:     live.Leverage,
+		Status:       "OPEN",
+		OpenTime:     time.Now(),
+		Strategy:     adoptedStrategy,
+		Notes:        "adopted from the exchange at startup",
+	}
+	position.TakeProfitPlan = e.newTakeProfitLadder(&Signal{}).encode()
+	if err := e.repository.CreatePosition(position); err != nil {
+		e.logger.Errorf("Failed to adopt position for %s: %v", live.Symbol, err)
+		report.Unknown = append(report.Unknown, description)
+		return
+	}
+
+	e.placeTakeProfitOrders(ctx, position)
+	e.publishEvent(events.TypePosition, "opened", position.Symbol, position)
+	report.Adopted = append(report.Adopted, description)
+}
+
+// recoverOrders updates DB orders that finished while the engine was down and reports
+// exchange orders of traded symbols the DB does not know
+func (e *Engine) recoverOrders(ctx context.Context, report *RecoveryReport) error {
+	for _, symbol := range e.config.Symbols {
+		if err := e.reconcileManualOrders(ctx, symbol); err != nil {
+			e.logger.Errorf("Failed to reconcile manual orders for %s: %v", symbol, err)
+		}
+	}
+
+	var open []*exchange.OrderInfo
+	for _, symbol := range e.config.Symbols {
+		orders, err := e.exchangeClient.GetOpenOrders(ctx, symbol)
+		if err != nil {
+			e.logger.Errorf("Failed to get open orders for %s: %v", symbol, err)
+			continue
+		}
+		open = append(open, orders...)
+	}
+	return e.reconcileOpenOrders(ctx, open, report)
+}
+
+// reconcileOpenOrders reports open exchange orders of traded symbols without a DB record and
+// stores the final state of DB orders no longer open on the exchange. Child orders of open
+// positions and unfilled manual orders are left to reconcileChildOrders and
+// reconcileManualOrders, which apply their fills together with the order.
+func (e *Engine) reconcileOpenOrders(ctx context.Context, open []*exchange.OrderInfo, report *RecoveryReport) error {
+	dbOrders, err := e.repository.GetOpenOrders("")
+	if err != nil {
+		return fmt.Errorf("failed to get open orders: %w", err)
+	}
+	positions, err := e.repository.GetAllPositions()
+	if err != nil {
+		return fmt.Errorf("failed to get positions: %w", err)
+	}
+	openPositions := make(map[uint]bool, len(positions))
+	for _, position := range positions {
+		openPositions[position.ID] = true
+	}
+
+	recorded := make(map[string]bool, len(dbOrders))
+	for _, order := range dbOrders {
+		recorded[order.ExchangeOrderID] = true
+	}
+
+	for _, order := range open {
+		if !e.isTradedSymbol(order.Symbol) {
+			continue
+		}
+		id := strconv.FormatInt(order.OrderID, 10)
+		if recorded[id] {
+			delete(recorded, id)
+			continue
+		}
+		report.UnknownOrders = append(report.UnknownOrders, fmt.Sprintf("%s %s %s %d", order.Symbol, order.Side, order.Type, order.OrderID))
+	}
+
+	// Orders still open in the DB but not on the exchange have finished
+	for _, order := range dbOrders {
+		if !recorded[order.ExchangeOrderID] || !e.isTradedSymbol(order.Symbol) {
+			continue
+		}
+		if order.PositionID != nil && openPositions[*order.PositionID] || order.PositionID == nil && order.Strategy == manualStrategy {
+			continue
+		}
+		orderID, err := strconv.ParseInt(order.ExchangeOrderID, 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := e.exchangeClient.GetOrder(ctx, order.Symbol, orderID)
+		if err != nil {
+			e.logger.Warnf("Failed to get order %s for %s: %v", order.ExchangeOrderID, order.Symbol, err)
+			continue
+		}
+		order.Status = info.Status
+		order.ExecutedQty = info.ExecutedQty
+		order.CumulativeQuote = info.CumQuote
+		if err := e.repository.UpdateOrder(order); err != nil {
+			e.logger.Errorf("Failed to update order %s for %s: %v", order.ExchangeOrderID, order.Symbol, err)
+			continue
+		}
+		report.UpdatedOrders = append(report.UpdatedOrders, fmt.Sprintf("%s %s %s", order.Symbol, order.ExchangeOrderID, info.Status))
+	}
+
+	return nil
+}
+
+// runRecovery reconciles with the exchange at startup and alerts on discrepancies
+func (e *Engine) runRecovery(ctx context.Context) {
+	ctx = auditlog.WithSource(ctx, auditlog.TriggerSystem, "recovery")
+	report, err := e.recoverState(ctx)
+	if err != nil {
+		e.logger.Errorf("Startup reconciliation failed: %v", err)
+		return
+	}
+
+	e.logger.Infof("Startup reconciliation: %d resumed, %d resized, %d closed, %d adopted, %d unknown positions, %d orders updated, %d unknown orders",
+		len(report.Resumed), len(report.Resized), len(report.ClosedStale), len(report.Adopted), len(report.Unknown),
+		len(report.UpdatedOrders), len(report.UnknownOrders))
+	e.recordAudit("recovery", "startup", "", report)
+
+	if report.discrepancies() == 0 {
+		return
+	}
+
+	var lines []string
+	for _, section := range []struct {
+		title string
+		items []string
+	}{
+		{"Resized", report.Resized},
+		{"Closed (gone from exchange)", report.ClosedStale},
+		{"Adopted", report.Adopted},
+		{"Unknown positions (not managed)", report.Unknown},
+		{"Orders finished while down", report.UpdatedOrders},
+		{"Unknown open orders", report.UnknownOrders},
+	} {
+		if len(section.items) > 0 {
+			lines = append(lines, fmt.Sprintf("%s: %s", section.title, strings.Join(section.items, ", ")))
+		}
+	}
+
+	msg := &notify.Message{
+		Title: "Startup reconciliation found differences",
+		Body:  strings.Join(lines, "\n"),
+		Level: notify.LevelWarning,
+		Time:  time.Now(),
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver reconciliation notification: %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package trading
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// openOrderRepo serves fixed open orders and positions; other calls panic
+type openOrderRepo struct {
+	database.Repository
+	orders    []*models.Order
+	positions []*models.Position
+	updated   []string
+}
+
+func (r *openOrderRepo) GetOpenOrders(symbol string) ([]*models.Order, error) { return r.orders, nil }
+func (r *openOrderRepo) GetAllPositions() ([]*models.Position, error)         { return r.positions, nil }
+
+func (r *openOrderRepo) UpdateOrder(order *models.Order) error {
+	r.updated = append(r.updated, order.ExchangeOrderID)
+	return nil
+}
+
+// filledClient reports every order as filled; other calls panic
+type filledClient struct {
+	exchange.Client
+}
+
+func (c *filledClient) GetOrder(ctx context.Context, symbol string, orderID int64) (*exchange.OrderInfo, error) {
+	return &exchange.OrderInfo{OrderID: orderID, Symbol: symbol, Status: "FILLED"}, nil
+}
+
+func TestReconcileOpenOrders(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	open, closed := uint(1), uint(2)
+	repo := &openOrderRepo{
+		orders: []*models.Order{
+			{ExchangeOrderID: "10", Symbol: "BTCUSDT", Strategy: "band"},
+			{ExchangeOrderID: "11", Symbol: "BTCUSDT", PositionID: &open, Role: "TP1"},
+			{ExchangeOrderID: "12", Symbol: "BTCUSDT", PositionID: &closed, Role: "TP1"},
+			{ExchangeOrderID: "13", Symbol: "BTCUSDT", Strategy: manualStrategy},
+			{ExchangeOrderID: "14", Symbol: "BTCUSDT", Strategy: "band"},
+		},
+		positions: []*models.Position{{ID: open, Symbol: "BTCUSDT", Status: "OPEN"}},
+	}
+	e := &Engine{
+		config:         config.TradingConfig{Symbols: []string{"BTCUSDT"}},
+		repository:     repo,
+		exchangeClient: &filledClient{},
+		logger:         logger,
+	}
+
+	report := &RecoveryReport{}
+	live := []*exchange.OrderInfo{
+		{OrderID: 14, Symbol: "BTCUSDT"},
+		{OrderID: 20, Symbol: "BTCUSDT", Side: "BUY", Type: "LIMIT"},
+		{OrderID: 30, Symbol: "XRPUSDT"},
+	}
+	if err := e.reconcileOpenOrders(context.Background(), live, report); err != nil {
+		t.Fatal(err)
+	}
+
+	// The open position's child and the unfilled manual order are applied by their own
+	// reconciliation, which needs to see the fill
+	if len(repo.updated) != 2 || repo.updated[0] != "10" || repo.updated[1] != "12" {
+		t.Errorf("updated orders %v, want [10 12]", repo.updated)
+	}
+	if len(report.UnknownOrders) != 1 || report.UnknownOrders[0] != "BTCUSDT BUY LIMIT 20" {
+		t.Errorf("unknown orders %v, want the untracked BTCUSDT order", report.UnknownOrders)
+	}
+}
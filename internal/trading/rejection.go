@@ -0,0 +1,230 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"contract_playground/internal/auditlog"
+	"contract_playground/internal/events"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/pkg/utils"
+)
+
+// minNotionalBuffer keeps a resized order above the minimum notional when the price moves
+// before it fills
+const minNotionalBuffer = 1.01
+
+// rejectedExitSuffix marks the client order ID of a rejected exit so it does not count as the
+// signal having been executed
+const rejectedExitSuffix = "_rejected"
+
+// RejectionSummary counts the orders the exchange rejected within a window by class
+type RejectionSummary struct {
+	Since    time.Time       `json:"since"`
+	Total    int             `json:"total"`
+	ByReason map[string]int  `json:"by_reason"`
+	BySymbol map[string]int  `json:"by_symbol"`
+	Recent   []*models.Order `json:"recent"` // Newest first
+}
+
+// placeOrder submits a futures order. When the exchange rejects it for a fixable reason the
+// order is corrected and resubmitted within the retry budget; an order that stays rejected
+// is recorded as a REJECTED row with its rejection class.
+func (e *Engine) placeOrder(ctx context.Context, request *exchange.OrderRequest) (*exchange.OrderResponse, error) {
+	// Decided before a fix may drop the reduce-only flag
+	exit := request.ReduceOnly || request.ClosePosition
+	response, err := e.submitOrder(ctx, request)
+	reason := exchange.ClassifyRejection(err)
+
+	cfg := e.config.Rejections
+	remediations := 0
+	for cfg.Remediate && reason != "" && remediations < cfg.MaxRetries {
+		fixed, change := e.remediateOrder(ctx, request, reason)
+		if fixed == nil || !e.takeRemediation() {
+			break
+		}
+		remediations++
+		e.logger.Warnf("%s %s order rejected (%s), retrying with %s: %v", request.Side, request.Symbol, reason, change, err)
+
+		request = fixed
+		response, err = e.submitOrder(ctx, request)
+		reason = exchange.ClassifyRejection(err)
+	}
+
+	if reason != "" {
+		e.recordRejection(ctx, request, exit, reason, err, remediations)
+	} else if err == nil && remediations > 0 {
+		e.logger.Infof("%s %s order accepted after %d fix(es)", request.Side, request.Symbol, remediations)
+	}
+	return response, err
+}
+
+// remediateOrder returns a corrected copy of a rejected order and a description of the fix,
+// or nil when the rejection cannot be fixed safely
+func (e *Engine) remediateOrder(ctx context.Context, request *exchange.OrderRequest, reason string) (*exchange.OrderRequest, string) {
+	if reason != exchange.RejectMinNotional && reason != exchange.RejectPrecision {
+		return fixOrder(request, reason, nil, 0, 0)
+	}
+
+	info, err := e.exchangeClient.GetSymbolInfo(ctx, request.Symbol)
+	if err != nil {
+		e.logger.Warnf("Failed to get filters of %s to fix a rejected order: %v", request.Symbol, err)
+		return nil, ""
+	}
+	price := request.Price
+	if reason == exchange.RejectMinNotional && price <= 0 {
+		if price, err = e.exchangeClient.GetSymbolPrice(ctx, request.Symbol); err != nil {
+			e.logger.Warnf("Failed to get price of %s to fix a rejected order: %v", request.Symbol, err)
+			return nil, ""
+		}
+	}
+	return fixOrder(request, reason, info, price, e.config.Rejections.MaxResizePercent)
+}
+
+// fixOrder applies the remediation of a rejection class:
+//   - min_notional raises the quantity of an opening order to the minimum notional at price,
+//     by at most maxResizePercent
+//   - precision rounds the quantity down to the step size and prices to the tick size
+//   - reduce_only drops the flag of an order with a hedge mode position side, which closes
+//     that side anyway; in one-way mode the flag is what keeps a sell from opening a short
+//
+// Insufficient margin and unknown rejections are not fixed.
+func fixOrder(request *exchange.OrderRequest, reason string, info *exchange.SymbolInfo, price, maxResizePercent float64) (*exchange.OrderRequest, string) {
+	fixed := *request
+	switch reason {
+	case exchange.RejectMinNotional:
+		if request.ReduceOnly || request.ClosePosition || info.MinNotional <= 0 || info.ContractSize > 0 || price <= 0 {
+			return nil, ""
+		}
+		quantity := info.MinNotional * minNotionalBuffer / price
+		if info.StepSize > 0 {
+			quantity = utils.NormalizeQuantity(math.Ceil(quantity/info.StepSize)*info.StepSize, info.StepSize)
+		}
+		if quantity <= request.Quantity || quantity > request.Quantity*(1+maxResizePercent/100) {
+			return nil, ""
+		}
+		fixed.Quantity = quantity
+		return &fixed, fmt.Sprintf("quantity %.8f raised to %.8f for the minimum notional %.2f", request.Quantity, quantity, info.MinNotional)
+
+	case exchange.RejectPrecision:
+		fixed.Quantity = utils.NormalizeQuantity(request.Quantity, info.StepSize)
+		if request.Price > 0 {
+			fixed.Price = utils.NormalizePrice(request.Price, info.TickSize)
+		}
+		if request.StopPrice > 0 {
+			fixed.StopPrice = utils.NormalizePrice(request.StopPrice, info.TickSize)
+		}
+		if fixed.Quantity <= 0 && !request.ClosePosition {
+			return nil, ""
+		}
+		if fixed == *request {
+			return nil, ""
+		}
+		return &fixed, fmt.Sprintf("quantity %.8f and price %.8f rounded to step %g and tick %g", fixed.Quantity, fixed.Price, info.StepSize, info.TickSize)
+
+	case exchange.RejectReduceOnly:
+		if !request.ReduceOnly || (request.PositionSide != "LONG" && request.PositionSide != "SHORT") {
+			return nil, ""
+		}
+		fixed.ReduceOnly = false
+		return &fixed, "reduce-only dropped for the hedge mode " + request.PositionSide + " side"
+	}
+	return nil, ""
+}
+
+// takeRemediation reserves one retry of the rolling hourly budget
+func (e *Engine) takeRemediation() bool {
+	e.remediationMu.Lock()
+	defer e.remediationMu.Unlock()
+
+	now := time.Now()
+	kept := e.remediations[:0]
+	for _, at := range e.remediations {
+		if now.Sub(at) < time.Hour {
+			kept = append(kept, at)
+		}
+	}
+	e.remediations = kept
+	if len(e.remediations) >= e.config.Rejections.HourlyBudget {
+		e.logger.Warnf("Order remediation budget of %d per hour used up", e.config.Rejections.HourlyBudget)
+		return false
+	}
+	e.remediations = append(e.remediations, now)
+	return true
+}
+
+// recordRejection saves a rejected order as a REJECTED row. A rejected entry keeps the client
+// order ID of its strategy signal, so the same signal is not sent again on the next evaluation.
+// A rejected exit gets a suffixed ID instead: its take-profit and trailing orders are already
+// cancelled, so the signal must be retried rather than wait for the next candle.
+func (e *Engine) recordRejection(ctx context.Context, request *exchange.OrderRequest, exit bool, reason string, err error, remediations int) {
+	e.logger.Warnf("%s %s order rejected by the exchange (%s): %v", request.Side, request.Symbol, reason, err)
+	if !e.config.Rejections.Record {
+		return
+	}
+
+	strategy := ""
+	if source := auditlog.SourceFrom(ctx); source.Trigger == auditlog.TriggerStrategy {
+		strategy = source.Actor
+	} else if source.Trigger == auditlog.TriggerManual {
+		strategy = manualStrategy
+	}
+	message := err.Error()
+	if len(message) > 255 {
+		message = message[:255]
+	}
+	clientOrderID := request.NewClientOrderID
+	if exit && clientOrderID != "" {
+		clientOrderID += rejectedExitSuffix
+	}
+	order := &models.Order{
+		// Rejected orders get no exchange ID; the placeholder keeps the column unique
+		ExchangeOrderID: fmt.Sprintf("rejected-%d", time.Now().UnixNano()),
+		ClientOrderID:   clientOrderID,
+		Symbol:          request.Symbol,
+		Side:            request.Side,
+		Type:            request.Type,
+		Status:          "REJECTED",
+		Quantity:        request.Quantity,
+		Price:           request.Price,
+		SubmittedPrice:  request.Price,
+		StopPrice:       request.StopPrice,
+		TimeInForce:     request.TimeInForce,
+		ReduceOnly:      request.ReduceOnly,
+		ClosePosition:   request.ClosePosition,
+		PositionSide:    request.PositionSide,
+		Strategy:        strategy,
+		RejectReason:    reason,
+		RejectMessage:   message,
+		Remediations:    remediations,
+	}
+	if err := e.repository.CreateOrder(order); err != nil {
+		e.logger.Errorf("Failed to record rejected %s order: %v", request.Symbol, err)
+		return
+	}
+	e.publishEvent(events.TypeOrder, "rejected", request.Symbol, order)
+}
+
+// Rejections summarizes the orders the exchange rejected since the given time
+func (e *Engine) Rejections(since time.Time, recent int) (*RejectionSummary, error) {
+	orders, err := e.repository.GetRejectedOrders(since, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rejected orders: %w", err)
+	}
+
+	summary := &RejectionSummary{
+		Since:    since,
+		Total:    len(orders),
+		ByReason: make(map[string]int),
+		BySymbol: make(map[string]int),
+		Recent:   orders[:min(recent, len(orders))],
+	}
+	for _, order := range orders {
+		summary.ByReason[order.RejectReason]++
+		summary.BySymbol[order.Symbol]++
+	}
+	return summary, nil
+}
@@ -0,0 +1,138 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/events"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+
+	"github.com/adshao/go-binance/v2/common"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+func TestClassifyRejection(t *testing.T) {
+	for _, test := range []struct {
+		err  error
+		want string
+	}{
+		{&common.APIError{Code: -4164, Message: "Order's notional must be no smaller than 5"}, exchange.RejectMinNotional},
+		{fmt.Errorf("failed to place order: %w", &common.APIError{Code: -1111, Message: "Precision is over the maximum"}), exchange.RejectPrecision},
+		{&common.APIError{Code: -1013, Message: "Filter failure: LOT_SIZE"}, exchange.RejectPrecision},
+		{&common.APIError{Code: -2019, Message: "Margin is insufficient."}, exchange.RejectInsufficientMargin},
+		{&common.APIError{Code: -1106, Message: "Parameter 'reduceonly' sent when not required."}, exchange.RejectReduceOnly},
+		{&common.APIError{Code: -4061, Message: "Order's position side does not match user's setting."}, exchange.RejectOther},
+		// The exchange did not process these, so they are not rejections
+		{&common.APIError{Code: -1008, Message: "Server is currently overloaded"}, ""},
+		{errors.New("connection reset by peer"), ""},
+		{nil, ""},
+	} {
+		if got := exchange.ClassifyRejection(test.err); got != test.want {
+			t.Errorf("ClassifyRejection(%v) = %q, want %q", test.err, got, test.want)
+		}
+	}
+}
+
+func TestFixOrder(t *testing.T) {
+	info := &exchange.SymbolInfo{StepSize: 0.001, TickSize: 0.1, MinNotional: 100}
+	buy := &exchange.OrderRequest{Symbol: "ETHUSDT", Side: "BUY", Type: "MARKET", Quantity: 0.045}
+
+	// 100 * 1.01 / 2000 = 0.0505, rounded up to the step
+	fixed, _ := fixOrder(buy, exchange.RejectMinNotional, info, 2000, 25)
+	if fixed == nil || fixed.Quantity != 0.051 {
+		t.Fatalf("min notional fix = %+v, want quantity 0.051", fixed)
+	}
+	if fixed, _ := fixOrder(buy, exchange.RejectMinNotional, info, 2000, 10); fixed != nil {
+		t.Errorf("resized by more than max_resize_percent: %+v", fixed)
+	}
+	sell := &exchange.OrderRequest{Symbol: "ETHUSDT", Side: "SELL", Type: "MARKET", Quantity: 0.045, ReduceOnly: true, PositionSide: "BOTH"}
+	if fixed, _ := fixOrder(sell, exchange.RejectMinNotional, info, 2000, 25); fixed != nil {
+		t.Errorf("resized a reduce-only order: %+v", fixed)
+	}
+
+	limit := &exchange.OrderRequest{Symbol: "ETHUSDT", Side: "BUY", Type: "LIMIT", Quantity: 0.0456, Price: 2000.04}
+	if fixed, _ := fixOrder(limit, exchange.RejectPrecision, info, 0, 0); fixed == nil || fixed.Quantity != 0.045 || fixed.Price != 2000 {
+		t.Errorf("precision fix = %+v, want 0.045 at 2000", fixed)
+	}
+	if fixed, _ := fixOrder(buy, exchange.RejectPrecision, info, 0, 0); fixed != nil {
+		t.Errorf("fixed an order that was already rounded: %+v", fixed)
+	}
+
+	// Dropping reduce-only is only safe when the hedge mode position side closes anyway
+	if fixed, _ := fixOrder(sell, exchange.RejectReduceOnly, nil, 0, 0); fixed != nil {
+		t.Errorf("dropped reduce-only in one-way mode: %+v", fixed)
+	}
+	hedged := *sell
+	hedged.PositionSide = "LONG"
+	if fixed, _ := fixOrder(&hedged, exchange.RejectReduceOnly, nil, 0, 0); fixed == nil || fixed.ReduceOnly {
+		t.Errorf("reduce-only fix = %+v, want the flag dropped", fixed)
+	}
+
+	if fixed, _ := fixOrder(buy, exchange.RejectInsufficientMargin, info, 2000, 25); fixed != nil {
+		t.Errorf("fixed an insufficient margin rejection: %+v", fixed)
+	}
+}
+
+// orderRepo keeps created orders in memory; other calls panic
+type orderRepo struct {
+	database.Repository
+	orders []*models.Order
+}
+
+func (r *orderRepo) CreateOrder(order *models.Order) error {
+	r.orders = append(r.orders, order)
+	return nil
+}
+
+func (r *orderRepo) GetOrderByClientID(clientOrderID string) (*models.Order, error) {
+	for _, order := range r.orders {
+		if order.ClientOrderID == clientOrderID {
+			return order, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// rejectingClient rejects every order for insufficient margin; other calls panic
+type rejectingClient struct {
+	exchange.Client
+}
+
+func (c *rejectingClient) PlaceOrder(ctx context.Context, request *exchange.OrderRequest) (*exchange.OrderResponse, error) {
+	return nil, &common.APIError{Code: -2019, Message: "Margin is insufficient."}
+}
+
+func TestRejectedExitIsRetried(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	repo := &orderRepo{}
+	e := &Engine{
+		config:         config.TradingConfig{Rejections: config.RejectionsConfig{Record: true}},
+		repository:     repo,
+		exchangeClient: &rejectingClient{},
+		logger:         logger,
+		events:         events.NewBus(),
+	}
+
+	entry := &Signal{ID: "sig_entry"}
+	e.placeOrder(context.Background(), &exchange.OrderRequest{Symbol: "BTCUSDT", Side: "BUY", Type: "MARKET", Quantity: 0.01, NewClientOrderID: entry.ID})
+	exit := &Signal{ID: "sig_exit"}
+	e.placeOrder(context.Background(), &exchange.OrderRequest{Symbol: "BTCUSDT", Side: "SELL", Type: "MARKET", Quantity: 0.01, ReduceOnly: true, NewClientOrderID: exit.ID})
+
+	if len(repo.orders) != 2 || repo.orders[1].Status != "REJECTED" {
+		t.Fatalf("recorded %d orders, want both rejections", len(repo.orders))
+	}
+	if !e.duplicateSignal(entry) {
+		t.Error("rejected entry signal sent again within its candle")
+	}
+	if e.duplicateSignal(exit) {
+		t.Error("rejected exit signal skipped on the next evaluation")
+	}
+}
@@ -0,0 +1,548 @@
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"contract_playground/internal/auditlog"
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidRemoteConfig is returned for remote config changes that fail validation
+var ErrInvalidRemoteConfig = errors.New("invalid configuration")
+
+// ErrRemoteConfigDisabled is returned when trading.remote_config is disabled
+var ErrRemoteConfigDisabled = errors.New("remote config is disabled")
+
+// RiskLimits are the risk limits that can be changed while the engine runs
+type RiskLimits struct {
+	MaxPositionSize   float64 `json:"max_position_size"`
+	StopLossPercent   float64 `json:"stop_loss_percent"`
+	TakeProfitPercent float64 `json:"take_profit_percent"`
+	MaxDailyLoss      float64 `json:"max_daily_loss"`
+	MaxLeverage       int     `json:"max_leverage"`
+	RiskPerTrade      float64 `json:"risk_per_trade_percent"`
+}
+
+// RiskLimitsUpdate changes the fields that are set. Version, when set, must match the
+// stored version so concurrent edits are not lost.
+type RiskLimitsUpdate struct {
+	Version           uint     `json:"version"`
+	MaxPositionSize   *float64 `json:"max_position_size"`
+	StopLossPercent   *float64 `json:"stop_loss_percent"`
+	TakeProfitPercent *float64 `json:"take_profit_percent"`
+	MaxDailyLoss      *float64 `json:"max_daily_loss"`
+	MaxLeverage       *int     `json:"max_leverage"`
+	RiskPerTrade      *float64 `json:"risk_per_trade_percent"`
+}
+
+// StrategyParametersUpdate merges Parameters into the stored ones; a null value removes a
+// parameter. Version, when set, must match the stored version.
+type StrategyParametersUpdate struct {
+	Version    uint                   `json:"version"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// RemoteRiskLimits is the stored risk limits row and the version the engine runs with
+type RemoteRiskLimits struct {
+	Name           string     `json:"name"`
+	Version        uint       `json:"version"`
+	AppliedVersion uint       `json:"applied_version"`
+	Limits         RiskLimits `json:"limits"`
+}
+
+// RemoteStrategyParameters is the stored strategy row and the version the engine runs with
+type RemoteStrategyParameters struct {
+	Name           string                 `json:"name"`
+	Type           string                 `json:"type"`
+	Version        uint                   `json:"version"`
+	AppliedVersion uint                   `json:"applied_version"`
+	Parameters     map[string]interface{} `json:"parameters"`
+}
+
+// fieldChange is one changed field of a config change record
+type fieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+func riskLimitsFromRow(row *models.TradingConfig) RiskLimits {
+	return RiskLimits{
+		MaxPositionSize:   row.MaxPosition,
+		StopLossPercent:   row.StopLoss,
+		TakeProfitPercent: row.TakeProfit,
+		MaxDailyLoss:      row.MaxDailyLoss,
+		MaxLeverage:       row.Leverage,
+		RiskPerTrade:      row.RiskPercent,
+	}
+}
+
+func (l RiskLimits) toRow(row *models.TradingConfig) {
+	row.MaxPosition = l.MaxPositionSize
+	row.StopLoss = l.StopLossPercent
+	row.TakeProfit = l.TakeProfitPercent
+	row.MaxDailyLoss = l.MaxDailyLoss
+	row.Leverage = l.MaxLeverage
+	row.RiskPercent = l.RiskPerTrade
+}
+
+// validate applies the file config's rules for the same settings
+func (l RiskLimits) validate(cfg config.TradingConfig) error {
+	var problems []string
+	if l.MaxPositionSize <= 0 {
+		problems = append(problems, fmt.Sprintf("max_position_size must be positive, got %v", l.MaxPositionSize))
+	}
+	if l.StopLossPercent <= 0 || l.StopLossPercent > 50 {
+		problems = append(problems, fmt.Sprintf("stop_loss_percent must be between 0 and 50, got %v", l.StopLossPercent))
+	}
+	if l.TakeProfitPercent <= 0 || l.TakeProfitPercent > 100 {
+		problems = append(problems, fmt.Sprintf("take_profit_percent must be between 0 and 100, got %v", l.TakeProfitPercent))
+	}
+	if l.StopLossPercent >= l.TakeProfitPercent {
+		problems = append(problems, fmt.Sprintf("stop_loss_percent must be below take_profit_percent (%v >= %v)", l.StopLossPercent, l.TakeProfitPercent))
+	}
+	if l.MaxDailyLoss <= 0 {
+		problems = append(problems, fmt.Sprintf("max_daily_loss must be positive, got %v", l.MaxDailyLoss))
+	}
+	if l.MaxLeverage < 1 || l.MaxLeverage > 125 {
+		problems = append(problems, fmt.Sprintf("max_leverage must be between 1 and 125, got %d", l.MaxLeverage))
+	}
+	if cfg.LeverageRegime.Enabled && l.MaxLeverage < cfg.LeverageRegime.MaxLeverage {
+		problems = append(problems, fmt.Sprintf("max_leverage must be at least leverage_regime.max_leverage (%d), got %d", cfg.LeverageRegime.MaxLeverage, l.MaxLeverage))
+	}
+	if l.RiskPerTrade < 0.1 || l.RiskPerTrade > 10 {
+		problems = append(problems, fmt.Sprintf("risk_per_trade_percent must be between 0.1 and 10, got %v", l.RiskPerTrade))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidRemoteConfig, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// RemoteConfigEnabled reports whether risk limits and strategy parameters are kept in the
+// database
+func (e *Engine) RemoteConfigEnabled() bool {
+	return e.config.RemoteConfig.Enabled
+}
+
+// loadRemoteConfig seeds the risk limits and strategy rows from the file on first start and
+// applies the stored versions otherwise
+func (e *Engine) loadRemoteConfig(ctx context.Context) error {
+	remote := e.config.RemoteConfig
+
+	limits, err := e.repository.GetTradingConfig(remote.RiskLimitsName)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		limits = &models.TradingConfig{Name: remote.RiskLimitsName, Symbol: "ALL", IsActive: true, Version: 1}
+		e.currentRiskLimits().toRow(limits)
+		if err := e.repository.CreateTradingConfig(limits); err != nil {
+			return fmt.Errorf("failed to seed risk limits: %w", err)
+		}
+		e.logger.Infof("Seeded remote risk limits %q from the config file", remote.RiskLimitsName)
+	} else if err != nil {
+		return fmt.Errorf("failed to get risk limits: %w", err)
+	}
+	if err := e.applyRiskLimits(ctx, limits, false); err != nil {
+		return err
+	}
+
+	strategy, err := e.repository.GetStrategy(remote.StrategyName)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		parameters, err := json.Marshal(publicParameters(e.config.Strategy.Parameters))
+		if err != nil {
+			return fmt.Errorf("failed to encode strategy parameters: %w", err)
+		}
+		strategy = &models.Strategy{
+			Name:        remote.StrategyName,
+			Type:        e.config.Strategy.Type,
+			Description: "Seeded from the config file",
+			Parameters:  string(parameters),
+			Performance: "{}",
+			IsActive:    true,
+			Version:     1,
+		}
+		if err := e.repository.CreateStrategy(strategy); err != nil {
+			return fmt.Errorf("failed to seed strategy parameters: %w", err)
+		}
+		e.logger.Infof("Seeded remote strategy parameters %q from the config file", remote.StrategyName)
+	} else if err != nil {
+		return fmt.Errorf("failed to get strategy parameters: %w", err)
+	}
+	return e.applyStrategyParameters(strategy)
+}
+
+// syncRemoteConfig applies risk limits and strategy parameters changed by another instance
+// or directly in the database
+func (e *Engine) syncRemoteConfig(ctx context.Context) error {
+	remote := e.config.RemoteConfig
+
+	limits, err := e.repository.GetTradingConfig(remote.RiskLimitsName)
+	if err != nil {
+		return fmt.Errorf("failed to get risk limits: %w", err)
+	}
+	e.remoteConfigMu.Lock()
+	stale := limits.Version != e.riskLimitsVersion
+	e.remoteConfigMu.Unlock()
+	if stale {
+		if err := e.applyRiskLimits(ctx, limits, true); err != nil {
+			return err
+		}
+	}
+
+	strategy, err := e.repository.GetStrategy(remote.StrategyName)
+	if err != nil {
+		return fmt.Errorf("failed to get strategy parameters: %w", err)
+	}
+	e.remoteConfigMu.Lock()
+	stale = strategy.Version != e.strategyVersion
+	e.remoteConfigMu.Unlock()
+	if stale {
+		return e.applyStrategyParameters(strategy)
+	}
+	return nil
+}
+
+// currentRiskLimits returns the risk limits in effect
+func (e *Engine) currentRiskLimits() RiskLimits {
+	current := e.riskManager.Limits()
+	return RiskLimits{
+		MaxPositionSize:   current.MaxPositionSize,
+		StopLossPercent:   current.StopLossPercent,
+		TakeProfitPercent: current.TakeProfitPercent,
+		MaxDailyLoss:      current.MaxDailyLoss,
+		MaxLeverage:       current.MaxLeverage,
+		RiskPerTrade:      current.RiskPerTrade,
+	}
+}
+
+// applyRiskLimits makes a stored risk limits version effective. A changed max leverage is
+// set on the exchange for symbols whose leverage the volatility regime does not manage;
+// at startup initializeSymbols does that instead.
+func (e *Engine) applyRiskLimits(ctx context.Context, row *models.TradingConfig, live bool) error {
+	limits := riskLimitsFromRow(row)
+	if err := limits.validate(e.config); err != nil {
+		return fmt.Errorf("risk limits version %d rejected: %w", row.Version, err)
+	}
+
+	e.remoteConfigMu.Lock()
+	defer e.remoteConfigMu.Unlock()
+
+	previous := e.riskManager.Limits()
+	next := *previous
+	next.MaxPositionSize = limits.MaxPositionSize
+	next.StopLossPercent = limits.StopLossPercent
+	next.TakeProfitPercent = limits.TakeProfitPercent
+	next.MaxDailyLoss = limits.MaxDailyLoss
+	next.MaxLeverage = limits.MaxLeverage
+	next.RiskPerTrade = limits.RiskPerTrade
+	e.riskManager.SetLimits(&next)
+	e.riskLimitsVersion = row.Version
+
+	if live && next.MaxLeverage != previous.MaxLeverage && !e.config.EnablePaperTrading {
+		for _, symbol := range e.config.Symbols {
+			e.leverageMu.Lock()
+			_, managed := e.leverages[symbol]
+			e.leverageMu.Unlock()
+			if managed {
+				continue
+			}
+			if err := e.exchangeClient.SetLeverage(ctx, symbol, next.MaxLeverage); err != nil {
+				e.logger.Warnf("Failed to set leverage for %s: %v", symbol, err)
+			}
+		}
+	}
+
+	e.logger.Infof("Applied risk limits %q version %d", row.Name, row.Version)
+	return nil
+}
+
+// applyStrategyParameters re-initializes the running strategy with a stored parameters
+// version. The parameters are validated on a fresh instance first, and symbols are locked
+// so no evaluation sees a half-applied change. Parameters removed from the row keep their
+// current value until restart.
+func (e *Engine) applyStrategyParameters(row *models.Strategy) error {
+	if row.Type != e.config.Strategy.Type {
+		return fmt.Errorf("strategy %q is of type %s but the engine runs %s", row.Name, row.Type, e.config.Strategy.Type)
+	}
+	overrides, err := decodeParameters(row.Parameters)
+	if err != nil {
+		return fmt.Errorf("strategy parameters version %d rejected: %w", row.Version, err)
+	}
+	params := strategyParameters(e.config, overrides)
+	if err := newStrategy(row.Type).Initialize(params); err != nil {
+		return fmt.Errorf("strategy parameters version %d rejected: %w", row.Version, err)
+	}
+
+	e.remoteConfigMu.Lock()
+	defer e.remoteConfigMu.Unlock()
+
+	for _, symbol := range e.config.Symbols {
+		defer e.lockSymbol(symbol)()
+	}
+	if err := e.strategy.Initialize(params); err != nil {
+		return fmt.Errorf("failed to apply strategy parameters version %d: %w", row.Version, err)
+	}
+	e.strategyVersion = row.Version
+	if e.shadow != nil {
+		e.shadow.applyLiveParameters(e.config, overrides)
+	}
+
+	e.logger.Infof("Applied strategy parameters %q version %d", row.Name, row.Version)
+	return nil
+}
+
+// RemoteRiskLimits returns the stored risk limits
+func (e *Engine) RemoteRiskLimits() (*RemoteRiskLimits, error) {
+	if !e.config.RemoteConfig.Enabled {
+		return nil, ErrRemoteConfigDisabled
+	}
+	row, err := e.repository.GetTradingConfig(e.config.RemoteConfig.RiskLimitsName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk limits: %w", err)
+	}
+
+	e.remoteConfigMu.Lock()
+	applied := e.riskLimitsVersion
+	e.remoteConfigMu.Unlock()
+	return &RemoteRiskLimits{Name: row.Name, Version: row.Version, AppliedVersion: applied, Limits: riskLimitsFromRow(row)}, nil
+}
+
+// UpdateRiskLimits stores a new risk limits version, records the change under the
+// context's audit source and applies it
+func (e *Engine) UpdateRiskLimits(ctx context.Context, update *RiskLimitsUpdate) (*RemoteRiskLimits, error) {
+	if !e.config.RemoteConfig.Enabled {
+		return nil, ErrRemoteConfigDisabled
+	}
+	row, err := e.repository.GetTradingConfig(e.config.RemoteConfig.RiskLimitsName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk limits: %w", err)
+	}
+	if update.Version != 0 && update.Version != row.Version {
+		return nil, database.ErrVersionConflict
+	}
+
+	old := riskLimitsFromRow(row)
+	limits := old
+	if update.MaxPositionSize != nil {
+		limits.MaxPositionSize = *update.MaxPositionSize
+	}
+	if update.StopLossPercent != nil {
+		limits.StopLossPercent = *update.StopLossPercent
+	}
+	if update.TakeProfitPercent != nil {
+		limits.TakeProfitPercent = *update.TakeProfitPercent
+	}
+	if update.MaxDailyLoss != nil {
+		limits.MaxDailyLoss = *update.MaxDailyLoss
+	}
+	if update.MaxLeverage != nil {
+		limits.MaxLeverage = *update.MaxLeverage
+	}
+	if update.RiskPerTrade != nil {
+		limits.RiskPerTrade = *update.RiskPerTrade
+	}
+	if err := limits.validate(e.config); err != nil {
+		return nil, err
+	}
+
+	changes, err := diffFields(old, limits)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) > 0 {
+		limits.toRow(row)
+		row.Version++
+		if err := e.saveConfigChange(ctx, models.ConfigEntityRiskLimits, row.Name, row.Version, changes, func(change *models.ConfigChange) error {
+			return e.repository.SaveTradingConfigVersion(row, change)
+		}); err != nil {
+			return nil, err
+		}
+		if err := e.applyRiskLimits(ctx, row, true); err != nil {
+			return nil, err
+		}
+	}
+	return e.RemoteRiskLimits()
+}
+
+// RemoteStrategyParameters returns the stored strategy parameters
+func (e *Engine) RemoteStrategyParameters() (*RemoteStrategyParameters, error) {
+	if !e.config.RemoteConfig.Enabled {
+		return nil, ErrRemoteConfigDisabled
+	}
+	row, err := e.repository.GetStrategy(e.config.RemoteConfig.StrategyName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get strategy parameters: %w", err)
+	}
+	params, err := decodeParameters(row.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	e.remoteConfigMu.Lock()
+	applied := e.strategyVersion
+	e.remoteConfigMu.Unlock()
+	return &RemoteStrategyParameters{Name: row.Name, Type: row.Type, Version: row.Version, AppliedVersion: applied, Parameters: params}, nil
+}
+
+// UpdateStrategyParameters stores a new strategy parameters version, records the change
+// under the context's audit source and applies it. Secret parameters such as API keys stay
+// in the config file and cannot be set remotely.
+func (e *Engine) UpdateStrategyParameters(ctx context.Context, update *StrategyParametersUpdate) (*RemoteStrategyParameters, error) {
+	if !e.config.RemoteConfig.Enabled {
+		return nil, ErrRemoteConfigDisabled
+	}
+	for key := range update.Parameters {
+		if config.IsSecretKey(key) {
+			return nil, fmt.Errorf("%w: %s is a secret and can only be set in the config file", ErrInvalidRemoteConfig, key)
+		}
+	}
+
+	row, err := e.repository.GetStrategy(e.config.RemoteConfig.StrategyName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get strategy parameters: %w", err)
+	}
+	if update.Version != 0 && update.Version != row.Version {
+		return nil, database.ErrVersionConflict
+	}
+
+	old, err := decodeParameters(row.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	params := make(map[string]interface{}, len(old)+len(update.Parameters))
+	for key, value := range old {
+		params[key] = value
+	}
+	for key, value := range update.Parameters {
+		if value == nil {
+			delete(params, key)
+		} else {
+			params[key] = value
+		}
+	}
+	if err := newStrategy(row.Type).Initialize(strategyParameters(e.config, params)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRemoteConfig, err)
+	}
+
+	changes, err := diffFields(old, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) > 0 {
+		encoded, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode strategy parameters: %w", err)
+		}
+		row.Parameters = string(encoded)
+		row.Version++
+		if err := e.saveConfigChange(ctx, models.ConfigEntityStrategy, row.Name, row.Version, changes, func(change *models.ConfigChange) error {
+			return e.repository.SaveStrategyVersion(row, change)
+		}); err != nil {
+			return nil, err
+		}
+		if err := e.applyStrategyParameters(row); err != nil {
+			return nil, err
+		}
+	}
+	return e.RemoteStrategyParameters()
+}
+
+// ConfigChanges returns the newest remote config changes first; entity may be empty
+func (e *Engine) ConfigChanges(entity string, limit int) ([]*models.ConfigChange, error) {
+	if !e.config.RemoteConfig.Enabled {
+		return nil, ErrRemoteConfigDisabled
+	}
+	return e.repository.GetConfigChanges(entity, "", limit)
+}
+
+// saveConfigChange stores a new version through save with its change record
+func (e *Engine) saveConfigChange(ctx context.Context, entity, name string, version uint, changes map[string]fieldChange, save func(*models.ConfigChange) error) error {
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode config change: %w", err)
+	}
+	actor := auditlog.SourceFrom(ctx).Actor
+	if err := save(&models.ConfigChange{
+		Entity:  entity,
+		Name:    name,
+		Version: version,
+		Actor:   actor,
+		Changes: string(encoded),
+	}); err != nil {
+		if errors.Is(err, database.ErrVersionConflict) {
+			return err
+		}
+		return fmt.Errorf("failed to save %s version %d: %w", entity, version, err)
+	}
+
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	e.logger.Infof("%s changed %s %q to version %d: %s", actor, entity, name, version, strings.Join(fields, ", "))
+	return nil
+}
+
+// diffFields compares two values field by field through their JSON form
+func diffFields(old, new interface{}) (map[string]fieldChange, error) {
+	before, err := toFields(old)
+	if err != nil {
+		return nil, err
+	}
+	after, err := toFields(new)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]fieldChange)
+	for key, value := range after {
+		if previous, ok := before[key]; !ok || !reflect.DeepEqual(previous, value) {
+			changes[key] = fieldChange{Old: before[key], New: value}
+		}
+	}
+	for key, value := range before {
+		if _, ok := after[key]; !ok {
+			changes[key] = fieldChange{Old: value}
+		}
+	}
+	return changes, nil
+}
+
+func toFields(value interface{}) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return decodeParameters(string(encoded))
+}
+
+func decodeParameters(encoded string) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	if encoded == "" {
+		return params, nil
+	}
+	if err := json.Unmarshal([]byte(encoded), &params); err != nil {
+		return nil, fmt.Errorf("failed to decode strategy parameters: %w", err)
+	}
+	return params, nil
+}
+
+// publicParameters drops secret parameters, which stay in the config file
+func publicParameters(params map[string]interface{}) map[string]interface{} {
+	public := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		if !config.IsSecretKey(key) {
+			public[key] = value
+		}
+	}
+	return public
+}
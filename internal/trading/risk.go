@@ -3,24 +3,35 @@ package trading
 import (
 	"context"
 	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"contract_playground/internal/models"
+	"contract_playground/pkg/utils"
+
 	"github.com/sirupsen/logrus"
 )
 
 // RiskManager handles risk management and validation
 type RiskManager struct {
-	config    *RiskConfig
+	config    atomic.Pointer[RiskConfig] // Replaced as a whole when limits change live
 	logger    *logrus.Logger
 	
 	// Track daily metrics
 	dailyLoss     float64
 	dailyTrades   int
-	lastResetDate time.Time
+	lastResetDate time.Time      // Start of the current day in dayLocation
+	dayLocation   *time.Location // Zone whose midnight resets the daily counters
 	
 	// Position tracking
 	totalExposure float64
 	maxExposure   float64
+	
+	// Per-symbol loss streaks and re-entry timing
+	cooldowns   map[string]*models.SymbolCooldown
+	cooldownsMu sync.Mutex
 }
 
 // RiskConfig holds risk management configuration
@@ -37,16 +48,44 @@ type RiskConfig struct {
 	MaxOrderValue     float64 `json:"max_order_value"`
 	VaRLimit          float64 `json:"var_limit"`          // Value at Risk limit
 	CorrelationLimit  float64 `json:"correlation_limit"`  // Max correlation between positions
+	MaxCorrelatedExposure float64 `json:"max_correlated_exposure"` // Max combined value of correlated positions
+	MaxNetDelta       float64 `json:"max_net_delta"`      // Max absolute long minus short notional
+	MaxGrossExposure  float64 `json:"max_gross_exposure"` // Max long plus short notional
+	MaxQuoteExposure  map[string]float64 `json:"max_quote_exposure"` // Max gross notional per upper-case quote asset
+	StopWorkingType   string  `json:"stop_working_type"`  // Price used for stop checks: MARK_PRICE or CONTRACT_PRICE
+	MaxConsecutiveLosses int           `json:"max_consecutive_losses"` // Losing exits in a row before a symbol cools down (0 disables)
+	LossCooldown         time.Duration `json:"loss_cooldown"`
+	MinReentryInterval   time.Duration `json:"min_reentry_interval"` // Minimum time between entries on a symbol
 }
 
 // NewRiskManager creates a new risk manager
 func NewRiskManager(config *RiskConfig) *RiskManager {
-	return &RiskManager{
-		config:        config,
+	rm := &RiskManager{
 		logger:        logrus.New(),
-		lastResetDate: time.Now(),
+		lastResetDate: startOfDay(time.Now(), time.UTC),
+		dayLocation:   time.UTC,
 		maxExposure:   config.MaxPositionSize * 10, // Default max exposure
+		cooldowns:     make(map[string]*models.SymbolCooldown),
 	}
+	rm.config.Store(config)
+	return rm
+}
+
+// SetDayLocation sets the zone whose midnight resets the daily counters; days start at
+// midnight UTC until it is called
+func (rm *RiskManager) SetDayLocation(location *time.Location) {
+	rm.dayLocation = location
+	rm.lastResetDate = startOfDay(time.Now(), location)
+}
+
+// Limits returns the current risk configuration. It must not be modified; use SetLimits.
+func (rm *RiskManager) Limits() *RiskConfig {
+	return rm.config.Load()
+}
+
+// SetLimits replaces the risk configuration; checks already running keep the previous one
+func (rm *RiskManager) SetLimits(config *RiskConfig) {
+	rm.config.Store(config)
 }
 
 // ValidateOrder validates if an order meets risk criteria
@@ -60,6 +99,12 @@ func (rm *RiskManager) ValidateOrder(ctx context.Context, order *OrderInfo) bool
 		return false
 	}
 	
+	// Check loss cooldown and re-entry interval for the symbol
+	if reason := rm.CooldownReason(order.Symbol, time.Now()); reason != "" {
+		rm.logger.Warnf("Order for %s blocked: %s", order.Symbol, reason)
+		return false
+	}
+	
 	// Validate order size
 	if !rm.validateOrderSize(order) {
 		rm.logger.Warnf("Order size validation failed for %s", order.Symbol)
@@ -96,17 +141,17 @@ func (rm *RiskManager) ValidateOrder(ctx context.Context, order *OrderInfo) bool
 
 // validateOrderSize checks if order size is within limits
 func (rm *RiskManager) validateOrderSize(order *OrderInfo) bool {
-	orderValue := order.Quantity * order.Price
+	orderValue := order.Value()
 	
 	// Check minimum order value
-	if orderValue < rm.config.MinOrderValue {
-		rm.logger.Debugf("Order value %.2f below minimum %.2f", orderValue, rm.config.MinOrderValue)
+	if orderValue < rm.Limits().MinOrderValue {
+		rm.logger.Debugf("Order value %.2f below minimum %.2f", orderValue, rm.Limits().MinOrderValue)
 		return false
 	}
 	
 	// Check maximum order value
-	if rm.config.MaxOrderValue > 0 && orderValue > rm.config.MaxOrderValue {
-		rm.logger.Debugf("Order value %.2f exceeds maximum %.2f", orderValue, rm.config.MaxOrderValue)
+	if rm.Limits().MaxOrderValue > 0 && orderValue > rm.Limits().MaxOrderValue {
+		rm.logger.Debugf("Order value %.2f exceeds maximum %.2f", orderValue, rm.Limits().MaxOrderValue)
 		return false
 	}
 	
@@ -115,10 +160,10 @@ func (rm *RiskManager) validateOrderSize(order *OrderInfo) bool {
 
 // validatePositionSize checks if position size is within limits
 func (rm *RiskManager) validatePositionSize(order *OrderInfo) bool {
-	orderValue := order.Quantity * order.Price
+	orderValue := order.Value()
 	
-	if orderValue > rm.config.MaxPositionSize {
-		rm.logger.Debugf("Position size %.2f exceeds maximum %.2f", orderValue, rm.config.MaxPositionSize)
+	if orderValue > rm.Limits().MaxPositionSize {
+		rm.logger.Debugf("Position size %.2f exceeds maximum %.2f", orderValue, rm.Limits().MaxPositionSize)
 		return false
 	}
 	
@@ -127,8 +172,8 @@ func (rm *RiskManager) validatePositionSize(order *OrderInfo) bool {
 
 // validateDailyLossLimit checks daily loss limits
 func (rm *RiskManager) validateDailyLossLimit(order *OrderInfo) bool {
-	if rm.dailyLoss >= rm.config.MaxDailyLoss {
-		rm.logger.Debugf("Daily loss %.2f exceeds limit %.2f", rm.dailyLoss, rm.config.MaxDailyLoss)
+	if rm.dailyLoss >= rm.Limits().MaxDailyLoss {
+		rm.logger.Debugf("Daily loss %.2f exceeds limit %.2f", rm.dailyLoss, rm.Limits().MaxDailyLoss)
 		return false
 	}
 	
@@ -137,7 +182,7 @@ func (rm *RiskManager) validateDailyLossLimit(order *OrderInfo) bool {
 
 // validateExposureLimit checks total exposure limits
 func (rm *RiskManager) validateExposureLimit(order *OrderInfo) bool {
-	orderValue := order.Quantity * order.Price
+	orderValue := order.Value()
 	newExposure := rm.totalExposure + orderValue
 	
 	if newExposure > rm.maxExposure {
@@ -148,13 +193,130 @@ func (rm *RiskManager) validateExposureLimit(order *OrderInfo) bool {
 	return true
 }
 
+// ValidateCorrelatedExposure checks that the order plus open positions highly correlated with
+// its symbol stay within the correlated exposure limit
+func (rm *RiskManager) ValidateCorrelatedExposure(order *OrderInfo, positions []PortfolioPosition, matrix *CorrelationMatrix) bool {
+	if rm.Limits().MaxCorrelatedExposure <= 0 || matrix == nil {
+		return true
+	}
+
+	exposure := order.Value()
+	for _, pos := range positions {
+		if pos.Symbol != order.Symbol {
+			corr, ok := matrix.Get(order.Symbol, pos.Symbol)
+			if !ok || math.Abs(corr) < rm.Limits().CorrelationLimit {
+				continue
+			}
+		}
+		exposure += math.Abs(pos.Value)
+	}
+
+	if exposure > rm.Limits().MaxCorrelatedExposure {
+		rm.logger.Warnf("Correlated exposure %.2f for %s would exceed limit %.2f", exposure, order.Symbol, rm.Limits().MaxCorrelatedExposure)
+		return false
+	}
+
+	return true
+}
+
+// LimitsPortfolioExposure reports whether any net, gross or quote asset exposure limit is set
+func (rm *RiskManager) LimitsPortfolioExposure() bool {
+	return rm.Limits().MaxNetDelta > 0 || rm.Limits().MaxGrossExposure > 0 || len(rm.Limits().MaxQuoteExposure) > 0
+}
+
+// ValidatePortfolioExposure checks the net delta, gross exposure and per quote asset exposure
+// of the open positions after the order fills. BUY adds long notional and SELL short notional
+// to the order's symbol. A limit already exceeded only blocks orders that increase it.
+func (rm *RiskManager) ValidatePortfolioExposure(order *OrderInfo, positions []PortfolioPosition) bool {
+	before := make(map[string]float64, len(positions)+1)
+	for _, pos := range positions {
+		value := math.Abs(pos.Value)
+		if pos.Side == "SHORT" {
+			value = -value
+		}
+		before[pos.Symbol] += value
+	}
+
+	after := make(map[string]float64, len(before)+1)
+	for symbol, value := range before {
+		after[symbol] = value
+	}
+	orderValue := order.Value()
+	if order.Side == "SELL" {
+		orderValue = -orderValue
+	}
+	after[order.Symbol] += orderValue
+
+	netBefore, grossBefore, quoteBefore := exposureTotals(before)
+	netAfter, grossAfter, quoteAfter := exposureTotals(after)
+
+	if rm.Limits().MaxNetDelta > 0 && math.Abs(netAfter) > rm.Limits().MaxNetDelta && math.Abs(netAfter) > math.Abs(netBefore) {
+		rm.logger.Warnf("Net delta %.2f for %s would exceed limit %.2f", netAfter, order.Symbol, rm.Limits().MaxNetDelta)
+		return false
+	}
+	if rm.Limits().MaxGrossExposure > 0 && grossAfter > rm.Limits().MaxGrossExposure && grossAfter > grossBefore {
+		rm.logger.Warnf("Gross exposure %.2f for %s would exceed limit %.2f", grossAfter, order.Symbol, rm.Limits().MaxGrossExposure)
+		return false
+	}
+	quote := quoteAsset(order.Symbol)
+	if limit, ok := rm.Limits().MaxQuoteExposure[quote]; ok && limit > 0 && quoteAfter[quote] > limit && quoteAfter[quote] > quoteBefore[quote] {
+		rm.logger.Warnf("%s exposure %.2f for %s would exceed limit %.2f", quote, quoteAfter[quote], order.Symbol, limit)
+		return false
+	}
+
+	return true
+}
+
+// exposureTotals sums signed notionals per symbol into net delta, gross exposure and gross
+// exposure per quote asset
+func exposureTotals(values map[string]float64) (float64, float64, map[string]float64) {
+	var net, gross float64
+	quotes := make(map[string]float64)
+	for symbol, value := range values {
+		net += value
+		gross += math.Abs(value)
+		quotes[quoteAsset(symbol)] += math.Abs(value)
+	}
+	return net, gross, quotes
+}
+
+// quoteAsset returns the quote asset of a futures symbol (BTCUSDT -> USDT, BTCUSD_PERP -> USD)
+func quoteAsset(symbol string) string {
+	// Delivery and coin-margined symbols carry a contract suffix
+	if i := strings.Index(symbol, "_"); i > 0 {
+		symbol = symbol[:i]
+	}
+	for _, quote := range []string{"USDT", "USDC", "BUSD", "USD"} {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return quote
+		}
+	}
+	return ""
+}
+
+// ValidateReduceOnly rejects a SELL larger than the open long position. Without the reduce-only
+// flag the excess would open a short; with it the exchange caps the order at its own position,
+// so an excess means the stored position is out of sync and the order is not what was meant.
+func (rm *RiskManager) ValidateReduceOnly(order *OrderInfo, positionSize float64) bool {
+	if order.Side != "SELL" {
+		return true
+	}
+
+	if roundQuantity(order.Quantity) > roundQuantity(positionSize) {
+		rm.logger.Warnf("Sell of %.6f %s exceeds the position size %.6f", order.Quantity, order.Symbol, positionSize)
+		return false
+	}
+
+	return true
+}
+
 // validateRiskPerTrade checks risk per trade limits
 func (rm *RiskManager) validateRiskPerTrade(order *OrderInfo) bool {
-	orderValue := order.Quantity * order.Price
-	riskAmount := orderValue * (rm.config.RiskPerTrade / 100.0)
+	orderValue := order.Value()
+	riskAmount := orderValue * (rm.Limits().RiskPerTrade / 100.0)
 	
 	// This is a simplified check - in reality you'd want to factor in stop loss distance
-	maxRiskPerTrade := rm.config.MaxPositionSize * (rm.config.RiskPerTrade / 100.0)
+	maxRiskPerTrade := rm.Limits().MaxPositionSize * (rm.Limits().RiskPerTrade / 100.0)
 	
 	if riskAmount > maxRiskPerTrade {
 		rm.logger.Debugf("Risk amount %.2f exceeds limit %.2f", riskAmount, maxRiskPerTrade)
@@ -167,25 +329,25 @@ func (rm *RiskManager) validateRiskPerTrade(order *OrderInfo) bool {
 // isTradingAllowed checks if trading is currently allowed
 func (rm *RiskManager) isTradingAllowed() bool {
 	// Check if max daily trades reached
-	if rm.config.MaxOpenPositions > 0 && rm.dailyTrades >= rm.config.MaxOpenPositions {
+	if rm.Limits().MaxOpenPositions > 0 && rm.dailyTrades >= rm.Limits().MaxOpenPositions {
 		return false
 	}
 	
 	// Check if daily loss limit reached
-	if rm.dailyLoss >= rm.config.MaxDailyLoss {
+	if rm.dailyLoss >= rm.Limits().MaxDailyLoss {
 		return false
 	}
 	
 	return true
 }
 
-// resetDailyCountersIfNeeded resets daily counters at start of new day
+// resetDailyCountersIfNeeded resets daily counters at the first midnight in the day
+// location after the last reset
 func (rm *RiskManager) resetDailyCountersIfNeeded() {
-	now := time.Now()
-	if now.Day() != rm.lastResetDate.Day() || now.Month() != rm.lastResetDate.Month() || now.Year() != rm.lastResetDate.Year() {
+	if today := startOfDay(time.Now(), rm.dayLocation); today.After(rm.lastResetDate) {
 		rm.dailyLoss = 0
 		rm.dailyTrades = 0
-		rm.lastResetDate = now
+		rm.lastResetDate = today
 		rm.logger.Info("Daily risk counters reset")
 	}
 }
@@ -197,6 +359,17 @@ func (rm *RiskManager) UpdateDailyLoss(loss float64) {
 	rm.logger.Debugf("Daily loss updated: %.2f", rm.dailyLoss)
 }
 
+// SetDailyLoss replaces the day's loss with one computed elsewhere, such as from closed
+// positions. A day that reached the limit, or was stopped, stays halted until the reset.
+func (rm *RiskManager) SetDailyLoss(loss float64) {
+	rm.resetDailyCountersIfNeeded()
+	if rm.dailyLoss >= rm.Limits().MaxDailyLoss {
+		return
+	}
+	rm.dailyLoss = loss
+	rm.logger.Debugf("Daily loss set: %.2f", rm.dailyLoss)
+}
+
 // UpdateDailyTrades updates the daily trade count
 func (rm *RiskManager) UpdateDailyTrades() {
 	rm.resetDailyCountersIfNeeded()
@@ -213,7 +386,7 @@ func (rm *RiskManager) UpdateExposure(exposure float64) {
 // CalculatePositionSize calculates optimal position size based on risk parameters
 func (rm *RiskManager) CalculatePositionSize(accountBalance, entryPrice, stopLoss float64) float64 {
 	// Calculate risk amount per trade
-	riskAmount := accountBalance * (rm.config.RiskPerTrade / 100.0)
+	riskAmount := accountBalance * (rm.Limits().RiskPerTrade / 100.0)
 	
 	// Calculate stop loss distance as percentage
 	stopLossDistance := math.Abs(entryPrice-stopLoss) / entryPrice
@@ -223,7 +396,7 @@ func (rm *RiskManager) CalculatePositionSize(accountBalance, entryPrice, stopLos
 	quantity := positionValue / entryPrice
 	
 	// Ensure position doesn't exceed maximum
-	maxQuantity := rm.config.MaxPositionSize / entryPrice
+	maxQuantity := rm.Limits().MaxPositionSize / entryPrice
 	if quantity > maxQuantity {
 		quantity = maxQuantity
 	}
@@ -233,7 +406,7 @@ func (rm *RiskManager) CalculatePositionSize(accountBalance, entryPrice, stopLos
 
 // CalculateStopLoss calculates stop loss price based on risk parameters
 func (rm *RiskManager) CalculateStopLoss(entryPrice float64, side string) float64 {
-	stopLossPercent := rm.config.StopLossPercent / 100.0
+	stopLossPercent := rm.Limits().StopLossPercent / 100.0
 	
 	if side == "BUY" || side == "LONG" {
 		return entryPrice * (1.0 - stopLossPercent)
@@ -244,7 +417,7 @@ func (rm *RiskManager) CalculateStopLoss(entryPrice float64, side string) float6
 
 // CalculateTakeProfit calculates take profit price based on risk parameters
 func (rm *RiskManager) CalculateTakeProfit(entryPrice float64, side string) float64 {
-	takeProfitPercent := rm.config.TakeProfitPercent / 100.0
+	takeProfitPercent := rm.Limits().TakeProfitPercent / 100.0
 	
 	if side == "BUY" || side == "LONG" {
 		return entryPrice * (1.0 + takeProfitPercent)
@@ -263,7 +436,7 @@ func (rm *RiskManager) GetRiskMetrics() *RiskMetrics {
 		TotalExposure:    rm.totalExposure,
 		MaxExposure:      rm.maxExposure,
 		ExposureRatio:    rm.totalExposure / rm.maxExposure,
-		RemainingRisk:    math.Max(0, rm.config.MaxDailyLoss-rm.dailyLoss),
+		RemainingRisk:    math.Max(0, rm.Limits().MaxDailyLoss-rm.dailyLoss),
 		TradingAllowed:   rm.isTradingAllowed(),
 		LastResetDate:    rm.lastResetDate,
 	}
@@ -317,12 +490,12 @@ func (rm *RiskManager) ValidatePortfolio(positions []PortfolioPosition) *Portfol
 		violations = append(violations, "Total exposure exceeds limit")
 	}
 	
-	if var95 > rm.config.VaRLimit {
+	if var95 > rm.Limits().VaRLimit {
 		isValid = false
 		violations = append(violations, "VaR exceeds limit")
 	}
 	
-	if math.Abs(portfolioReturn) > rm.config.MaxDrawdown {
+	if math.Abs(portfolioReturn) > rm.Limits().MaxDrawdown {
 		isValid = false
 		violations = append(violations, "Drawdown exceeds limit")
 	}
@@ -341,26 +514,7 @@ func (rm *RiskManager) ValidatePortfolio(positions []PortfolioPosition) *Portfol
 
 // calculateVaR95 calculates 95% Value at Risk
 func (rm *RiskManager) calculateVaR95(returns []float64) float64 {
-	if len(returns) == 0 {
-		return 0
-	}
-	
-	// Sort returns
-	for i := 0; i < len(returns); i++ {
-		for j := i + 1; j < len(returns); j++ {
-			if returns[i] > returns[j] {
-				returns[i], returns[j] = returns[j], returns[i]
-			}
-		}
-	}
-	
-	// Get 5th percentile (95% VaR)
-	index := int(float64(len(returns)) * 0.05)
-	if index >= len(returns) {
-		index = len(returns) - 1
-	}
-	
-	return math.Abs(returns[index])
+	return utils.CalculateVaR(returns, 0.95)
 }
 
 // PortfolioPosition represents a position in the portfolio
@@ -369,7 +523,8 @@ type PortfolioPosition struct {
 	Side          string  `json:"side"`
 	Size          float64 `json:"size"`
 	EntryPrice    float64 `json:"entry_price"`
-	CurrentPrice  float64 `json:"current_price"`
+	CurrentPrice  float64 `json:"current_price"` // Last traded price
+	MarkPrice     float64 `json:"mark_price"`
 	Value         float64 `json:"value"`
 	UnrealizedPnL float64 `json:"unrealized_pnl"`
 	Leverage      int     `json:"leverage"`
@@ -392,7 +547,7 @@ func (rm *RiskManager) EmergencyStop(reason string) {
 	rm.logger.Errorf("EMERGENCY STOP TRIGGERED: %s", reason)
 	
 	// Set daily loss to maximum to prevent further trading
-	rm.dailyLoss = rm.config.MaxDailyLoss
+	rm.dailyLoss = rm.Limits().MaxDailyLoss
 	
 	// Additional emergency procedures could be implemented here
 	// Such as closing all positions, sending alerts, etc.
@@ -400,26 +555,28 @@ func (rm *RiskManager) EmergencyStop(reason string) {
 
 // ShouldClosePosition determines if a position should be closed due to risk
 func (rm *RiskManager) ShouldClosePosition(position PortfolioPosition) (bool, string) {
+	price := rm.stopPrice(position)
+	
 	// Check stop loss
-	if position.Side == "LONG" && position.CurrentPrice <= rm.CalculateStopLoss(position.EntryPrice, "BUY") {
+	if position.Side == "LONG" && price <= rm.CalculateStopLoss(position.EntryPrice, "BUY") {
 		return true, "Stop loss triggered"
 	}
 	
-	if position.Side == "SHORT" && position.CurrentPrice >= rm.CalculateStopLoss(position.EntryPrice, "SELL") {
+	if position.Side == "SHORT" && price >= rm.CalculateStopLoss(position.EntryPrice, "SELL") {
 		return true, "Stop loss triggered"
 	}
 	
 	// Check take profit
-	if position.Side == "LONG" && position.CurrentPrice >= rm.CalculateTakeProfit(position.EntryPrice, "BUY") {
+	if position.Side == "LONG" && price >= rm.CalculateTakeProfit(position.EntryPrice, "BUY") {
 		return true, "Take profit triggered"
 	}
 	
-	if position.Side == "SHORT" && position.CurrentPrice <= rm.CalculateTakeProfit(position.EntryPrice, "SELL") {
+	if position.Side == "SHORT" && price <= rm.CalculateTakeProfit(position.EntryPrice, "SELL") {
 		return true, "Take profit triggered"
 	}
 	
 	// Check maximum loss per position
-	maxLossPercent := rm.config.StopLossPercent * 2 // Double stop loss as emergency exit
+	maxLossPercent := rm.Limits().StopLossPercent * 2 // Double stop loss as emergency exit
 	currentLossPercent := math.Abs(position.UnrealizedPnL/position.Value) * 100
 	
 	if currentLossPercent > maxLossPercent {
@@ -428,3 +585,11 @@ func (rm *RiskManager) ShouldClosePosition(position PortfolioPosition) (bool, st
 	
 	return false, ""
 }
+
+// stopPrice returns the price stop checks compare against, following the configured working type
+func (rm *RiskManager) stopPrice(position PortfolioPosition) float64 {
+	if rm.Limits().StopWorkingType == WorkingTypeMarkPrice && position.MarkPrice > 0 {
+		return position.MarkPrice
+	}
+	return position.CurrentPrice
+}
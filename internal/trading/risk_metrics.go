@@ -0,0 +1,64 @@
+package trading
+
+import (
+	"fmt"
+	"time"
+
+	"contract_playground/internal/models"
+)
+
+// metricDate returns the calendar day of a midnight in the day location as midnight UTC,
+// the value the date column of risk_metrics is keyed by
+func metricDate(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// saveRiskMetricPoint appends the snapshot to the intraday series and drops points older
+// than trading.risk_metrics.intraday_days
+func (e *Engine) saveRiskMetricPoint(metric *models.RiskMetric) error {
+	now := time.Now()
+	point := &models.RiskMetricPoint{
+		Time:                  now,
+		DailyPnL:              metric.DailyPnL,
+		DailyFees:             metric.DailyFees,
+		DailyFunding:          metric.DailyFunding,
+		TotalTrades:           metric.TotalTrades,
+		WinningTrades:         metric.WinningTrades,
+		LosingTrades:          metric.LosingTrades,
+		WinRate:               metric.WinRate,
+		EquityDrawdownPercent: metric.EquityDrawdownPercent,
+		DrawdownScale:         metric.DrawdownScale,
+	}
+	if err := e.repository.SaveRiskMetricPoint(point); err != nil {
+		return fmt.Errorf("failed to save risk metric point: %w", err)
+	}
+
+	if days := e.config.RiskMetrics.IntradayDays; days > 0 {
+		deleted, err := e.repository.DeleteRiskMetricPointsBefore(now.AddDate(0, 0, -days))
+		if err != nil {
+			return fmt.Errorf("failed to prune risk metric points: %w", err)
+		}
+		if deleted > 0 {
+			e.logger.Debugf("Pruned %d risk metric points older than %d days", deleted, days)
+		}
+	}
+	return nil
+}
+
+// RiskMetricHistory returns the daily risk metrics of the last days, newest first
+func (e *Engine) RiskMetricHistory(days int) ([]*models.RiskMetric, error) {
+	metrics, err := e.repository.GetRiskMetrics(days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk metrics: %w", err)
+	}
+	return metrics, nil
+}
+
+// RiskMetricPoints returns the intraday risk metric samples taken since the given time
+func (e *Engine) RiskMetricPoints(since time.Time) ([]*models.RiskMetricPoint, error) {
+	points, err := e.repository.GetRiskMetricPoints(since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk metric points: %w", err)
+	}
+	return points, nil
+}
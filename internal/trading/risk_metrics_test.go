@@ -0,0 +1,19 @@
+package trading
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetricDateKeepsCalendarDay(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("no time zone data: %v", err)
+	}
+
+	// Midnight of March 2 in Shanghai is still March 1 in UTC
+	day := startOfDay(time.Date(2024, 3, 1, 17, 0, 0, 0, time.UTC), shanghai)
+	if got, want := metricDate(day), time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("metric date = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,30 @@
+package trading
+
+import (
+	"io"
+	"testing"
+)
+
+func TestValidateReduceOnly(t *testing.T) {
+	rm := NewRiskManager(&RiskConfig{})
+	rm.logger.SetOutput(io.Discard)
+
+	tests := []struct {
+		name     string
+		side     string
+		quantity float64
+		want     bool
+	}{
+		{"partial sell", "SELL", 0.4, true},
+		{"full sell", "SELL", 1.0, true},
+		{"sell within rounding", "SELL", 1.000000001, true},
+		{"oversized sell", "SELL", 1.5, false},
+		{"buy", "BUY", 5, true},
+	}
+	for _, tt := range tests {
+		order := &OrderInfo{Symbol: "BTCUSDT", Side: tt.side, Quantity: tt.quantity, Price: 60000}
+		if got := rm.ValidateReduceOnly(order, 1.0); got != tt.want {
+			t.Errorf("%s: ValidateReduceOnly = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
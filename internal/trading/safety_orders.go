@@ -0,0 +1,67 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/pkg/utils"
+)
+
+// SafetyOrder is a resting limit buy that averages down an open position
+type SafetyOrder struct {
+	Price    float64
+	Quantity float64
+}
+
+// safetyOrderRolePrefix prefixes the child order roles of safety orders (SO1, SO2, ...)
+const safetyOrderRolePrefix = "SO"
+
+// isSafetyOrder reports whether a child order adds to the position rather than reducing it
+func isSafetyOrder(order *models.Order) bool {
+	return strings.HasPrefix(order.Role, safetyOrderRolePrefix)
+}
+
+// placeSafetyOrders places the signal's safety orders as GTC limit buys for the position.
+// Orders that would take the position above the maximum position size are skipped.
+func (e *Engine) placeSafetyOrders(ctx context.Context, position *models.Position, signal *Signal) {
+	if len(signal.SafetyOrders) == 0 {
+		return
+	}
+
+	info, err := e.exchangeClient.GetSymbolInfo(ctx, position.Symbol)
+	if err != nil {
+		e.logger.Errorf("Failed to get symbol info for %s safety orders: %v", position.Symbol, err)
+		return
+	}
+
+	value := info.Notional(position.Size, position.EntryPrice)
+	for i, safety := range signal.SafetyOrders {
+		price := utils.NormalizePrice(safety.Price, info.TickSize)
+		quantity := utils.NormalizeQuantity(safety.Quantity, info.StepSize)
+		if price <= 0 || quantity < info.MinQty {
+			continue
+		}
+
+		if value+info.Notional(quantity, price) > e.riskManager.Limits().MaxPositionSize {
+			e.logger.Warnf("Skipping %d safety orders for %s: max position size %.2f reached",
+				len(signal.SafetyOrders)-i, position.Symbol, e.riskManager.Limits().MaxPositionSize)
+			return
+		}
+
+		if e.placeChildOrder(ctx, position, fmt.Sprintf("%s%d", safetyOrderRolePrefix, i+1), &exchange.OrderRequest{
+			Symbol:           position.Symbol,
+			Side:             "BUY",
+			Type:             "LIMIT",
+			Quantity:         quantity,
+			Price:            price,
+			TimeInForce:      "GTC",
+			PositionSide:     "BOTH",
+			NewClientOrderID: fmt.Sprintf("so_%d_%d", position.ID, i+1),
+		}) {
+			value += info.Notional(quantity, price)
+		}
+	}
+}
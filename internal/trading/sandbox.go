@@ -0,0 +1,196 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ProcessLimits are OS resource limits applied to external strategy processes
+type ProcessLimits struct {
+	MemoryBytes uint64 // Address space limit; 0 means unlimited
+	CPUSeconds  uint64 // CPU time limit; 0 means unlimited
+}
+
+// ProcessLimiter is implemented by strategies that run an external process and can
+// apply resource limits to it
+type ProcessLimiter interface {
+	SetProcessLimits(limits ProcessLimits)
+}
+
+// SandboxedStrategy guards the engine against a misbehaving strategy. Each evaluation is
+// bounded by a timeout, panics are recovered, and a strategy that times out repeatedly is
+// quarantined (it only returns HOLD) for a cool-off period.
+type SandboxedStrategy struct {
+	inner    Strategy
+	config   config.SandboxConfig
+	notifier notify.Notifier
+	logger   *logrus.Logger
+
+	mu               sync.Mutex
+	inflight         map[string]bool // Symbols with an evaluation running
+	consecutiveFails int
+	quarantinedUntil time.Time
+}
+
+// NewSandboxedStrategy wraps a strategy with the configured sandbox limits
+func NewSandboxedStrategy(inner Strategy, cfg config.SandboxConfig, notifier notify.Notifier, logger *logrus.Logger) *SandboxedStrategy {
+	if limiter, ok := inner.(ProcessLimiter); ok {
+		limiter.SetProcessLimits(ProcessLimits{
+			MemoryBytes: uint64(cfg.MemoryLimitMB) * 1024 * 1024,
+			CPUSeconds:  uint64(cfg.CPULimitSeconds),
+		})
+	}
+
+	return &SandboxedStrategy{
+		inner:    inner,
+		config:   cfg,
+		notifier: notifier,
+		logger:   logger,
+		inflight: make(map[string]bool),
+	}
+}
+
+// Name returns the wrapped strategy name
+func (s *SandboxedStrategy) Name() string {
+	return s.inner.Name()
+}
+
+// Initialize initializes the wrapped strategy
+func (s *SandboxedStrategy) Initialize(config map[string]interface{}) error {
+	return s.inner.Initialize(config)
+}
+
+// ShouldBuy evaluates the wrapped strategy's buy signal within the sandbox
+func (s *SandboxedStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
+	return s.run(ctx, symbol, func(ctx context.Context) (*Signal, error) {
+		return s.inner.ShouldBuy(ctx, symbol, data)
+	})
+}
+
+// ShouldSell evaluates the wrapped strategy's sell signal within the sandbox
+func (s *SandboxedStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
+	return s.run(ctx, symbol, func(ctx context.Context) (*Signal, error) {
+		return s.inner.ShouldSell(ctx, symbol, data, position)
+	})
+}
+
+// Quarantined reports whether the strategy is currently quarantined
+func (s *SandboxedStrategy) Quarantined() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Now().Before(s.quarantinedUntil)
+}
+
+type sandboxResult struct {
+	signal   *Signal
+	err      error
+	panicked bool
+}
+
+func (s *SandboxedStrategy) run(ctx context.Context, symbol string, evaluate func(ctx context.Context) (*Signal, error)) (*Signal, error) {
+	s.mu.Lock()
+	if until := s.quarantinedUntil; time.Now().Before(until) {
+		s.mu.Unlock()
+		return holdSignal(fmt.Sprintf("strategy quarantined until %s", until.Format(time.RFC3339))), nil
+	}
+	// A timed-out evaluation of the symbol may still be running; never stack another one on
+	// top of it. Other symbols are evaluated concurrently by the symbol workers.
+	if s.inflight[symbol] {
+		s.mu.Unlock()
+		return holdSignal("previous strategy evaluation still running"), nil
+	}
+	s.inflight[symbol] = true
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.config.TimeoutMs)*time.Millisecond)
+	defer cancel()
+
+	resultC := make(chan sandboxResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				resultC <- sandboxResult{err: fmt.Errorf("strategy panicked: %v", r), panicked: true}
+			}
+			s.mu.Lock()
+			delete(s.inflight, symbol)
+			s.mu.Unlock()
+		}()
+
+		signal, err := evaluate(ctx)
+		resultC <- sandboxResult{signal: signal, err: err}
+	}()
+
+	select {
+	case result := <-resultC:
+		if result.panicked {
+			s.recordFailure(symbol, result.err)
+			return nil, result.err
+		}
+		if result.err != nil {
+			// Ordinary strategy errors (e.g. insufficient data) are not sandbox violations
+			return nil, result.err
+		}
+		s.recordSuccess()
+		return result.signal, nil
+	case <-ctx.Done():
+		err := fmt.Errorf("strategy %s timed out after %dms", s.inner.Name(), s.config.TimeoutMs)
+		s.recordFailure(symbol, err)
+		return holdSignal(err.Error()), nil
+	}
+}
+
+func (s *SandboxedStrategy) recordSuccess() {
+	s.mu.Lock()
+	s.consecutiveFails = 0
+	s.mu.Unlock()
+}
+
+// recordFailure counts a timeout or panic and quarantines the strategy when the limit is hit
+func (s *SandboxedStrategy) recordFailure(symbol string, err error) {
+	s.mu.Lock()
+	s.consecutiveFails++
+	fails := s.consecutiveFails
+	quarantine := s.config.MaxConsecutiveTimeouts > 0 && fails >= s.config.MaxConsecutiveTimeouts
+	if quarantine {
+		s.quarantinedUntil = time.Now().Add(time.Duration(s.config.QuarantineSeconds) * time.Second)
+		s.consecutiveFails = 0
+	}
+	until := s.quarantinedUntil
+	s.mu.Unlock()
+
+	s.logger.Warnf("Strategy %s failed on %s (%d consecutive): %v", s.inner.Name(), symbol, fails, err)
+
+	if !quarantine {
+		return
+	}
+
+	msg := &notify.Message{
+		Title: fmt.Sprintf("Strategy %s quarantined", s.inner.Name()),
+		Body:  fmt.Sprintf("Strategy %s failed %d times in a row (last: %v) and will only return HOLD until %s", s.inner.Name(), fails, err, until.Format(time.RFC3339)),
+		Level: notify.LevelCritical,
+		Time:  time.Now(),
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.notifier.Notify(ctx, msg); err != nil {
+			s.logger.Errorf("Failed to deliver quarantine notification: %v", err)
+		}
+	}()
+}
+
+func holdSignal(reason string) *Signal {
+	return &Signal{
+		Action: "HOLD",
+		Reason: reason,
+	}
+}
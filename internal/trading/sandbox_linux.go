@@ -0,0 +1,29 @@
+//go:build linux
+
+package trading
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// applyProcessLimits sets address space and CPU time limits on a running process
+func applyProcessLimits(pid int, limits ProcessLimits) error {
+	if limits.MemoryBytes > 0 {
+		rlimit := &unix.Rlimit{Cur: limits.MemoryBytes, Max: limits.MemoryBytes}
+		if err := unix.Prlimit(pid, unix.RLIMIT_AS, rlimit, nil); err != nil {
+			return fmt.Errorf("failed to set memory limit: %w", err)
+		}
+	}
+
+	if limits.CPUSeconds > 0 {
+		// The soft limit delivers SIGXCPU; the hard limit a second later kills the process
+		rlimit := &unix.Rlimit{Cur: limits.CPUSeconds, Max: limits.CPUSeconds + 1}
+		if err := unix.Prlimit(pid, unix.RLIMIT_CPU, rlimit, nil); err != nil {
+			return fmt.Errorf("failed to set CPU limit: %w", err)
+		}
+	}
+
+	return nil
+}
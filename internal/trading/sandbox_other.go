@@ -0,0 +1,13 @@
+//go:build !linux
+
+package trading
+
+import "fmt"
+
+// applyProcessLimits is only supported on Linux
+func applyProcessLimits(pid int, limits ProcessLimits) error {
+	if limits.MemoryBytes > 0 || limits.CPUSeconds > 0 {
+		return fmt.Errorf("process resource limits are not supported on this platform")
+	}
+	return nil
+}
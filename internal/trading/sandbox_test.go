@@ -0,0 +1,72 @@
+package trading
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+
+	"github.com/sirupsen/logrus"
+)
+
+// gateStrategy reports each evaluation on started and buys once release is closed
+type gateStrategy struct {
+	started chan string
+	release chan struct{}
+}
+
+func (g *gateStrategy) Name() string                                   { return "gate" }
+func (g *gateStrategy) Initialize(config map[string]interface{}) error { return nil }
+
+func (g *gateStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
+	g.started <- symbol
+	<-g.release
+	return &Signal{Action: "BUY", Confidence: 1}, nil
+}
+
+func (g *gateStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
+	return holdSignal(""), nil
+}
+
+func TestSandboxEvaluatesSymbolsConcurrently(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	inner := &gateStrategy{started: make(chan string, 2), release: make(chan struct{})}
+	sandbox := NewSandboxedStrategy(inner, config.SandboxConfig{Enabled: true, TimeoutMs: 5000}, notify.NewLogNotifier(logger), logger)
+
+	symbols := []string{"BTCUSDT", "ETHUSDT"}
+	signals := make(chan *Signal, len(symbols))
+	for _, symbol := range symbols {
+		go func(symbol string) {
+			signal, err := sandbox.ShouldBuy(context.Background(), symbol, &MarketData{Symbol: symbol})
+			if err != nil {
+				t.Errorf("%s: %v", symbol, err)
+			}
+			signals <- signal
+		}(symbol)
+	}
+
+	// Both evaluations must be running at once
+	for range symbols {
+		select {
+		case <-inner.started:
+		case <-time.After(2 * time.Second):
+			t.Fatal("a symbol was not evaluated while the other was running")
+		}
+	}
+	// A second evaluation of a symbol still running is held
+	if signal, _ := sandbox.ShouldBuy(context.Background(), "BTCUSDT", &MarketData{Symbol: "BTCUSDT"}); signal.Action != "HOLD" {
+		t.Errorf("overlapping evaluation of the same symbol = %s, want HOLD", signal.Action)
+	}
+
+	close(inner.release)
+	for range symbols {
+		if signal := <-signals; signal == nil || signal.Action != "BUY" {
+			t.Errorf("signal = %+v, want BUY", signal)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package trading
+
+import (
+	"fmt"
+	"time"
+
+	"contract_playground/internal/config"
+)
+
+// clockRange is a daily window expressed as offsets from local midnight
+type clockRange struct {
+	start time.Duration
+	end   time.Duration
+}
+
+// contains reports whether the offset falls inside the window, handling windows that cross midnight
+func (r clockRange) contains(offset time.Duration) bool {
+	if r.start < r.end {
+		return offset >= r.start && offset < r.end
+	}
+	return offset >= r.start || offset < r.end
+}
+
+// blackoutWindow is a one-off period without new entries
+type blackoutWindow struct {
+	name  string
+	start time.Time
+	end   time.Time
+}
+
+// fundingSchedule tracks the funding timestamps around the current time for a symbol
+type fundingSchedule struct {
+	previous time.Time
+	next     time.Time
+}
+
+// sessionSchedule decides when the engine may open new positions
+type sessionSchedule struct {
+	location       *time.Location
+	tradingHours   []clockRange
+	dailyBlackouts []clockRange
+	blackouts      []blackoutWindow
+	pauseWeekends  bool
+	fundingWindow  time.Duration
+}
+
+// newSessionSchedule parses the session configuration
+func newSessionSchedule(cfg config.SessionConfig) (*sessionSchedule, error) {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session timezone: %w", err)
+	}
+
+	schedule := &sessionSchedule{
+		location:      location,
+		pauseWeekends: cfg.PauseWeekends,
+		fundingWindow: time.Duration(cfg.FundingBlackoutMinutes) * time.Minute,
+	}
+
+	if schedule.tradingHours, err = parseClockRanges(cfg.TradingHours); err != nil {
+		return nil, err
+	}
+	if schedule.dailyBlackouts, err = parseClockRanges(cfg.DailyBlackouts); err != nil {
+		return nil, err
+	}
+
+	for _, window := range cfg.Blackouts {
+		start, err := time.Parse(time.RFC3339, window.Start)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse blackout %q start: %w", window.Name, err)
+		}
+		end, err := time.Parse(time.RFC3339, window.End)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse blackout %q end: %w", window.Name, err)
+		}
+		schedule.blackouts = append(schedule.blackouts, blackoutWindow{name: window.Name, start: start, end: end})
+	}
+
+	return schedule, nil
+}
+
+// parseClockRanges parses a list of "HH:MM-HH:MM" windows
+func parseClockRanges(windows []string) ([]clockRange, error) {
+	ranges := make([]clockRange, 0, len(windows))
+	for _, window := range windows {
+		start, end, err := config.ParseClockRange(window)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, clockRange{start: start, end: end})
+	}
+	return ranges, nil
+}
+
+// blockReason returns why new positions may not be opened at now, or "" when entries are allowed
+func (s *sessionSchedule) blockReason(now time.Time, funding fundingSchedule) string {
+	local := now.In(s.location)
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+
+	if s.pauseWeekends && (local.Weekday() == time.Saturday || local.Weekday() == time.Sunday) {
+		return "weekend"
+	}
+
+	if len(s.tradingHours) > 0 {
+		inSession := false
+		for _, window := range s.tradingHours {
+			if window.contains(offset) {
+				inSession = true
+				break
+			}
+		}
+		if !inSession {
+			return "outside trading hours"
+		}
+	}
+
+	for _, window := range s.dailyBlackouts {
+		if window.contains(offset) {
+			return "daily blackout window"
+		}
+	}
+
+	for _, window := range s.blackouts {
+		if !now.Before(window.start) && now.Before(window.end) {
+			return fmt.Sprintf("blackout: %s", window.name)
+		}
+	}
+
+	if s.fundingWindow > 0 {
+		if !funding.next.IsZero() && now.After(funding.next.Add(-s.fundingWindow)) && now.Before(funding.next) {
+			return "funding blackout"
+		}
+		if !funding.previous.IsZero() && now.Before(funding.previous.Add(s.fundingWindow)) {
+			return "funding blackout"
+		}
+	}
+
+	return ""
+}
+
+// recordFundingTime remembers the next funding timestamp for a symbol, keeping the previous
+// one so the blackout also covers the minutes after funding settles. Callers hold marketDataMu.
+func (e *Engine) recordFundingTime(symbol string, nextFundingTime int64) {
+	if nextFundingTime <= 0 {
+		return
+	}
+
+	next := time.UnixMilli(nextFundingTime)
+	schedule := e.fundingTimes[symbol]
+	if !schedule.next.IsZero() && !schedule.next.Equal(next) {
+		schedule.previous = schedule.next
+	}
+	schedule.next = next
+	e.fundingTimes[symbol] = schedule
+}
+
+// sessionFilter returns a reason when the trading schedule vetoes a new entry
+func (e *Engine) sessionFilter(symbol string, now time.Time) string {
+	if e.sessions == nil {
+		return ""
+	}
+
+	e.marketDataMu.RLock()
+	funding := e.fundingTimes[symbol]
+	e.marketDataMu.RUnlock()
+
+	return e.sessions.blockReason(now, funding)
+}
@@ -0,0 +1,370 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Label of the live strategy's copy in shadow tests
+const shadowControl = "control"
+
+// shadowRunner evaluates a candidate strategy and a control copy of the live strategy on the
+// market data of every cycle. Each variant trades its own simulated book per symbol, long
+// only and filling at the cycle's price like a backtest, so the two are compared under the
+// same rules rather than against live fills.
+type shadowRunner struct {
+	cfg        config.ShadowConfig
+	repository database.Repository
+	logger     *logrus.Logger
+	variants   []*shadowVariant // Control first
+	started    time.Time
+
+	// Held for reading by evaluations and for writing while parameters change
+	paramsMu sync.RWMutex
+
+	mu          sync.Mutex
+	evaluations int // Cycles both variants answered
+	agreements  int // Of those, cycles with the same action
+}
+
+// shadowVariant is one side of the test
+type shadowVariant struct {
+	name     string
+	strategy Strategy
+
+	mu      sync.Mutex
+	books   map[string]*backtestBook
+	signals map[string]int // Non-HOLD signals by action
+	errors  int
+}
+
+// newShadowRunner creates the control and candidate strategies; the control gets the live
+// parameters and the candidate those of the shadow section
+func newShadowRunner(cfg config.TradingConfig, repository database.Repository, logger *logrus.Logger) (*shadowRunner, error) {
+	control := newStrategy(cfg.Strategy.Type)
+	if err := control.Initialize(strategyParameters(cfg, nil)); err != nil {
+		return nil, fmt.Errorf("failed to initialize shadow control: %w", err)
+	}
+	candidateType, params := shadowCandidateParameters(cfg, nil)
+	candidate := newStrategy(candidateType)
+	if err := candidate.Initialize(params); err != nil {
+		return nil, fmt.Errorf("failed to initialize shadow candidate: %w", err)
+	}
+
+	runner := &shadowRunner{cfg: cfg.Shadow, repository: repository, logger: logger, started: time.Now()}
+	for _, variant := range []struct {
+		name     string
+		strategy Strategy
+	}{{shadowControl, control}, {cfg.Shadow.Name, candidate}} {
+		// External strategy processes log through the engine; the copies never get the
+		// repository, so they cannot overwrite the live strategy's persisted state
+		if logged, ok := variant.strategy.(interface{ SetLogger(*logrus.Logger) }); ok {
+			logged.SetLogger(logger)
+		}
+		runner.variants = append(runner.variants, &shadowVariant{
+			name:     variant.name,
+			strategy: variant.strategy,
+			books:    make(map[string]*backtestBook),
+			signals:  make(map[string]int),
+		})
+	}
+	return runner, nil
+}
+
+// shadowCandidateParameters returns the candidate's type and parameters. A candidate of the
+// live type runs the live parameters, including remote overrides, under its own.
+func shadowCandidateParameters(cfg config.TradingConfig, overrides map[string]interface{}) (string, map[string]interface{}) {
+	candidate := cfg.Shadow.Candidate(cfg.Strategy)
+	if candidate.Type != cfg.Strategy.Type {
+		trading := cfg
+		trading.Strategy = candidate
+		return candidate.Type, strategyParameters(trading, nil)
+	}
+	params := strategyParameters(cfg, overrides)
+	for key, value := range cfg.Shadow.Parameters {
+		params[key] = value
+	}
+	return candidate.Type, params
+}
+
+// evaluate asks both variants about the symbol and applies their signals to their books.
+// Failures only affect the shadow test.
+func (s *shadowRunner) evaluate(ctx context.Context, symbol string, data *MarketData) {
+	s.paramsMu.RLock()
+	defer s.paramsMu.RUnlock()
+
+	actions := make([]string, 0, len(s.variants))
+	for _, variant := range s.variants {
+		action, err := s.evaluateVariant(ctx, variant, symbol, data)
+		if err != nil {
+			variant.mu.Lock()
+			variant.errors++
+			variant.mu.Unlock()
+			s.logger.Debugf("Shadow %s failed on %s: %v", variant.name, symbol, err)
+			continue
+		}
+		actions = append(actions, action)
+	}
+
+	if len(actions) == len(s.variants) {
+		s.mu.Lock()
+		s.evaluations++
+		if actions[0] == actions[1] {
+			s.agreements++
+		}
+		s.mu.Unlock()
+	}
+}
+
+// evaluateVariant returns the variant's action for the symbol after applying it
+func (s *shadowRunner) evaluateVariant(ctx context.Context, variant *shadowVariant, symbol string, data *MarketData) (string, error) {
+	variant.mu.Lock()
+	book, ok := variant.books[symbol]
+	if !ok {
+		book = &backtestBook{
+			cfg:     BacktestConfig{InitialBalance: s.cfg.InitialBalance, PositionPercent: s.cfg.PositionPercent, FeeRate: s.cfg.FeeRate},
+			balance: s.cfg.InitialBalance,
+			peak:    s.cfg.InitialBalance,
+		}
+		variant.books[symbol] = book
+	}
+	open, quantity, entryPrice := book.open, book.quantity, book.entryPrice
+	variant.mu.Unlock()
+
+	var signal *Signal
+	var err error
+	if open {
+		position := &models.Position{Symbol: symbol, PositionSide: "LONG", Size: quantity, EntryPrice: entryPrice, Status: "OPEN"}
+		signal, err = variant.strategy.ShouldSell(ctx, symbol, data, position)
+	} else {
+		signal, err = variant.strategy.ShouldBuy(ctx, symbol, data)
+	}
+	if err != nil {
+		return "", err
+	}
+	action := "HOLD"
+	if signal != nil && signal.Action != "" {
+		action = signal.Action
+	}
+
+	now := data.Timestamp
+	if now.IsZero() {
+		now = time.Now()
+	}
+	variant.mu.Lock()
+	filled := false
+	trades := len(book.trades)
+	switch {
+	case action == "BUY" && !book.open:
+		book.enter(now.UnixMilli(), data.Price)
+		filled = book.open
+	case action == "SELL" && book.open:
+		book.exit(now.UnixMilli(), data.Price)
+		filled = true
+	}
+	book.mark(data.Price)
+	var pnl float64
+	if len(book.trades) > trades {
+		pnl = book.trades[len(book.trades)-1].PnL
+	}
+	if action != "HOLD" {
+		variant.signals[action]++
+	}
+	variant.mu.Unlock()
+
+	if action != "HOLD" && s.cfg.RecordSignals {
+		record := &models.ShadowSignal{
+			Variant:    variant.name,
+			Strategy:   variant.strategy.Name(),
+			Symbol:     symbol,
+			Action:     action,
+			Price:      data.Price,
+			Confidence: signal.Confidence,
+			Reason:     signal.Reason,
+			Filled:     filled,
+			PnL:        pnl,
+			SignalTime: now,
+		}
+		if err := s.repository.CreateShadowSignal(record); err != nil {
+			s.logger.Errorf("Failed to record shadow signal for %s: %v", symbol, err)
+		}
+	}
+	return action, nil
+}
+
+// applyLiveParameters re-initializes the control with new live parameters, and the
+// candidate too when it runs the live type, keeping its own overrides on top
+func (s *shadowRunner) applyLiveParameters(cfg config.TradingConfig, overrides map[string]interface{}) {
+	s.paramsMu.Lock()
+	defer s.paramsMu.Unlock()
+
+	if err := s.variants[0].strategy.Initialize(strategyParameters(cfg, overrides)); err != nil {
+		s.logger.Errorf("Failed to update shadow control parameters: %v", err)
+	}
+	if candidateType, params := shadowCandidateParameters(cfg, overrides); candidateType == cfg.Strategy.Type {
+		if err := s.variants[1].strategy.Initialize(params); err != nil {
+			s.logger.Errorf("Failed to update shadow candidate parameters: %v", err)
+		}
+	}
+}
+
+// Close stops strategies that run processes
+func (s *shadowRunner) Close() error {
+	for _, variant := range s.variants {
+		if closer, ok := variant.strategy.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+	return nil
+}
+
+// ShadowReport compares the shadow test's variants since the engine started
+type ShadowReport struct {
+	Since       time.Time              `json:"since"`
+	Evaluations int                    `json:"evaluations"` // Cycles both variants answered
+	Agreement   float64                `json:"agreement"`   // Fraction of those with the same action
+	Variants    []*ShadowVariantReport `json:"variants"`    // Control first
+	Difference  *ShadowVariantDelta    `json:"difference"`  // Candidate minus control
+	Symbols     []*ShadowSymbolDelta   `json:"symbols"`
+}
+
+// ShadowVariantReport is the simulated performance of one variant over all symbols
+type ShadowVariantReport struct {
+	Name          string         `json:"name"`
+	Strategy      string         `json:"strategy"`
+	Signals       map[string]int `json:"signals"` // Non-HOLD signals by action
+	Errors        int            `json:"errors"`
+	Trades        int            `json:"trades"`
+	WinRate       float64        `json:"win_rate"`
+	NetPnL        float64        `json:"net_pnl"` // Closed trades, net of fees
+	Fees          float64        `json:"fees"`
+	Equity        float64        `json:"equity"` // Open positions marked at the last price
+	ReturnPercent float64        `json:"return_percent"`
+	MaxDrawdown   float64        `json:"max_drawdown"` // Largest per-symbol drawdown, as a fraction
+	OpenPositions int            `json:"open_positions"`
+}
+
+// ShadowVariantDelta is the candidate's result minus the control's
+type ShadowVariantDelta struct {
+	Trades        int     `json:"trades"`
+	WinRate       float64 `json:"win_rate"`
+	NetPnL        float64 `json:"net_pnl"`
+	ReturnPercent float64 `json:"return_percent"`
+	MaxDrawdown   float64 `json:"max_drawdown"`
+}
+
+// ShadowSymbolDelta compares the variants' net PnL on one symbol
+type ShadowSymbolDelta struct {
+	Symbol    string  `json:"symbol"`
+	Control   float64 `json:"control"`
+	Candidate float64 `json:"candidate"`
+}
+
+// report summarizes both variants
+func (s *shadowRunner) report() *ShadowReport {
+	s.mu.Lock()
+	report := &ShadowReport{Since: s.started, Evaluations: s.evaluations}
+	if s.evaluations > 0 {
+		report.Agreement = float64(s.agreements) / float64(s.evaluations)
+	}
+	s.mu.Unlock()
+
+	bySymbol := make(map[string]*ShadowSymbolDelta)
+	for i, variant := range s.variants {
+		summary := variant.summary()
+		report.Variants = append(report.Variants, summary)
+
+		variant.mu.Lock()
+		for symbol, book := range variant.books {
+			delta, ok := bySymbol[symbol]
+			if !ok {
+				delta = &ShadowSymbolDelta{Symbol: symbol}
+				bySymbol[symbol] = delta
+			}
+			pnl := book.equity - s.cfg.InitialBalance
+			if i == 0 {
+				delta.Control = pnl
+			} else {
+				delta.Candidate = pnl
+			}
+		}
+		variant.mu.Unlock()
+	}
+
+	control, candidate := report.Variants[0], report.Variants[1]
+	report.Difference = &ShadowVariantDelta{
+		Trades:        candidate.Trades - control.Trades,
+		WinRate:       candidate.WinRate - control.WinRate,
+		NetPnL:        candidate.NetPnL - control.NetPnL,
+		ReturnPercent: candidate.ReturnPercent - control.ReturnPercent,
+		MaxDrawdown:   candidate.MaxDrawdown - control.MaxDrawdown,
+	}
+	for _, delta := range bySymbol {
+		report.Symbols = append(report.Symbols, delta)
+	}
+	sort.Slice(report.Symbols, func(i, j int) bool { return report.Symbols[i].Symbol < report.Symbols[j].Symbol })
+	return report
+}
+
+// summary totals the variant's books
+func (v *shadowVariant) summary() *ShadowVariantReport {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	summary := &ShadowVariantReport{
+		Name:     v.name,
+		Strategy: v.strategy.Name(),
+		Signals:  make(map[string]int, len(v.signals)),
+		Errors:   v.errors,
+	}
+	for action, count := range v.signals {
+		summary.Signals[action] = count
+	}
+
+	var initial float64
+	wins := 0
+	for _, book := range v.books {
+		initial += book.cfg.InitialBalance
+		summary.Equity += book.equity
+		summary.Fees += book.fees
+		if book.open {
+			summary.OpenPositions++
+		}
+		if book.maxDrawdown > summary.MaxDrawdown {
+			summary.MaxDrawdown = book.maxDrawdown
+		}
+		for _, trade := range book.trades {
+			summary.Trades++
+			summary.NetPnL += trade.PnL
+			if trade.PnL > 0 {
+				wins++
+			}
+		}
+	}
+	if summary.Trades > 0 {
+		summary.WinRate = float64(wins) / float64(summary.Trades)
+	}
+	if initial > 0 {
+		summary.ReturnPercent = (summary.Equity - initial) / initial * 100
+	}
+	return summary
+}
+
+// ShadowReport compares the shadow test's candidate with the control copy of the live
+// strategy
+func (e *Engine) ShadowReport() (*ShadowReport, error) {
+	if e.shadow == nil {
+		return nil, fmt.Errorf("shadow testing is not enabled")
+	}
+	return e.shadow.report(), nil
+}
@@ -0,0 +1,106 @@
+package trading
+
+import (
+	"context"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// bandStrategy buys at or below buyBelow and sells at or above sellAbove
+type bandStrategy struct {
+	buyBelow, sellAbove float64
+}
+
+func (b *bandStrategy) Name() string                                   { return "band" }
+func (b *bandStrategy) Initialize(config map[string]interface{}) error { return nil }
+
+func (b *bandStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
+	if data.Price <= b.buyBelow {
+		return &Signal{Action: "BUY", Confidence: 1}, nil
+	}
+	return holdSignal(""), nil
+}
+
+func (b *bandStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
+	if data.Price >= b.sellAbove {
+		return &Signal{Action: "SELL", Confidence: 1}, nil
+	}
+	return holdSignal(""), nil
+}
+
+func TestShadowRunnerComparesVariants(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	runner := &shadowRunner{
+		cfg:    config.ShadowConfig{Name: "candidate", InitialBalance: 1000, PositionPercent: 100},
+		logger: logger,
+	}
+	for _, variant := range []struct {
+		name     string
+		strategy Strategy
+	}{{shadowControl, &bandStrategy{buyBelow: 100, sellAbove: 110}}, {"candidate", &bandStrategy{buyBelow: 100, sellAbove: 120}}} {
+		runner.variants = append(runner.variants, &shadowVariant{
+			name:     variant.name,
+			strategy: variant.strategy,
+			books:    make(map[string]*backtestBook),
+			signals:  make(map[string]int),
+		})
+	}
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, price := range []float64{100, 110, 120, 105} {
+		runner.evaluate(context.Background(), "BTCUSDT", &MarketData{Symbol: "BTCUSDT", Price: price, Timestamp: start.Add(time.Duration(i) * time.Minute)})
+	}
+
+	report := runner.report()
+	if report.Evaluations != 4 {
+		t.Fatalf("evaluations = %d, want 4", report.Evaluations)
+	}
+	// Both buy at 100 and hold at 105; they differ at 110 and 120
+	if math.Abs(report.Agreement-0.5) > 1e-9 {
+		t.Errorf("agreement = %v, want 0.5", report.Agreement)
+	}
+	control, candidate := report.Variants[0], report.Variants[1]
+	if control.Trades != 1 || math.Abs(control.NetPnL-100) > 1e-9 {
+		t.Errorf("control: %d trades, net %v; want 1 trade of 100", control.Trades, control.NetPnL)
+	}
+	if candidate.Trades != 1 || math.Abs(candidate.NetPnL-200) > 1e-9 {
+		t.Errorf("candidate: %d trades, net %v; want 1 trade of 200", candidate.Trades, candidate.NetPnL)
+	}
+	if math.Abs(report.Difference.NetPnL-100) > 1e-9 {
+		t.Errorf("difference = %v, want 100", report.Difference.NetPnL)
+	}
+	if len(report.Symbols) != 1 || math.Abs(report.Symbols[0].Candidate-200) > 1e-9 {
+		t.Errorf("symbols = %+v, want BTCUSDT with candidate 200", report.Symbols)
+	}
+}
+
+func TestShadowCandidateParameters(t *testing.T) {
+	cfg := config.TradingConfig{
+		StopWorkingType: "MARK_PRICE",
+		Strategy: config.StrategyConfig{
+			Type:       "simple_moving_average",
+			Parameters: map[string]interface{}{"short_period": 10, "long_period": 30},
+		},
+		Shadow: config.ShadowConfig{Parameters: map[string]interface{}{"short_period": 8}},
+	}
+
+	strategyType, params := shadowCandidateParameters(cfg, map[string]interface{}{"long_period": 40})
+	if strategyType != "simple_moving_average" || params["short_period"] != 8 || params["long_period"] != 40 {
+		t.Errorf("same type: %s %v, want the live parameters with overrides under the shadow's", strategyType, params)
+	}
+
+	cfg.Shadow.Type = "rsi"
+	cfg.Shadow.Parameters = map[string]interface{}{"period": 14}
+	strategyType, params = shadowCandidateParameters(cfg, nil)
+	if _, ok := params["short_period"]; strategyType != "rsi" || ok || params["period"] != 14 {
+		t.Errorf("other type: %s %v, want only the shadow's parameters", strategyType, params)
+	}
+}
@@ -0,0 +1,247 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/exchange"
+	"contract_playground/pkg/utils"
+)
+
+// SignalFilter vetoes entry signals that the strategy produced but that should not reach the
+// risk manager, e.g. in illiquid or counter-trend markets
+type SignalFilter interface {
+	Name() string
+	// Check returns why the signal is rejected, or "" to pass it on
+	Check(ctx context.Context, data *MarketData, signal *Signal, now time.Time) (string, error)
+}
+
+// signalRecorder is implemented by filters that track the signals the whole chain accepted
+type signalRecorder interface {
+	accepted(symbol string, now time.Time)
+}
+
+// signalFilterChain applies filters in order; the first rejection wins
+type signalFilterChain []SignalFilter
+
+// newSignalFilterChain builds the filters enabled in the configuration, cheapest first so the
+// network-bound ones only run for signals that got that far
+func newSignalFilterChain(cfg config.SignalFilterConfig, client exchange.Client) (signalFilterChain, error) {
+	var chain signalFilterChain
+	if len(cfg.AllowedHours) > 0 {
+		location, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load signal filter timezone: %w", err)
+		}
+		hours, err := parseClockRanges(cfg.AllowedHours)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, &timeOfDayFilter{location: location, hours: hours})
+	}
+	if cfg.CooldownMinutes > 0 {
+		chain = append(chain, &signalCooldownFilter{
+			cooldown: time.Duration(cfg.CooldownMinutes) * time.Minute,
+			last:     make(map[string]time.Time),
+		})
+	}
+	if cfg.MinQuoteVolume24h > 0 || cfg.MinCandleVolume > 0 {
+		chain = append(chain, &volumeFilter{min24h: cfg.MinQuoteVolume24h, minCandle: cfg.MinCandleVolume})
+	}
+	if cfg.MaxSpreadBps > 0 {
+		chain = append(chain, &spreadFilter{client: client, maxBps: cfg.MaxSpreadBps})
+	}
+	if cfg.TrendInterval != "" {
+		chain = append(chain, &trendFilter{
+			client:   client,
+			interval: cfg.TrendInterval,
+			period:   cfg.TrendPeriod,
+			trends:   make(map[string]higherTrend),
+		})
+	}
+	return chain, nil
+}
+
+// check runs the signal through the chain. It returns the rejecting filter and its reason,
+// or empty strings when every filter passed; filters that could not decide reject.
+func (c signalFilterChain) check(ctx context.Context, data *MarketData, signal *Signal, now time.Time) (string, string) {
+	for _, filter := range c {
+		reason, err := filter.Check(ctx, data, signal, now)
+		if err != nil {
+			return filter.Name(), err.Error()
+		}
+		if reason != "" {
+			return filter.Name(), reason
+		}
+	}
+	for _, filter := range c {
+		if recorder, ok := filter.(signalRecorder); ok {
+			recorder.accepted(data.Symbol, now)
+		}
+	}
+	return "", ""
+}
+
+// timeOfDayFilter only passes signals inside the allowed daily windows
+type timeOfDayFilter struct {
+	location *time.Location
+	hours    []clockRange
+}
+
+func (f *timeOfDayFilter) Name() string { return "time_of_day" }
+
+func (f *timeOfDayFilter) Check(ctx context.Context, data *MarketData, signal *Signal, now time.Time) (string, error) {
+	local := now.In(f.location)
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	for _, window := range f.hours {
+		if window.contains(offset) {
+			return "", nil
+		}
+	}
+	return fmt.Sprintf("%s is outside the allowed hours", local.Format("15:04 MST")), nil
+}
+
+// signalCooldownFilter spaces accepted signals per symbol
+type signalCooldownFilter struct {
+	cooldown time.Duration
+	mu       sync.Mutex
+	last     map[string]time.Time
+}
+
+func (f *signalCooldownFilter) Name() string { return "cooldown" }
+
+func (f *signalCooldownFilter) Check(ctx context.Context, data *MarketData, signal *Signal, now time.Time) (string, error) {
+	f.mu.Lock()
+	last, ok := f.last[data.Symbol]
+	f.mu.Unlock()
+	if ok && now.Before(last.Add(f.cooldown)) {
+		return fmt.Sprintf("previous signal at %s, cooling down until %s",
+			last.Format(time.RFC3339), last.Add(f.cooldown).Format(time.RFC3339)), nil
+	}
+	return "", nil
+}
+
+func (f *signalCooldownFilter) accepted(symbol string, now time.Time) {
+	f.mu.Lock()
+	f.last[symbol] = now
+	f.mu.Unlock()
+}
+
+// volumeFilter rejects signals in thinly traded markets
+type volumeFilter struct {
+	min24h    float64
+	minCandle float64
+}
+
+func (f *volumeFilter) Name() string { return "min_volume" }
+
+func (f *volumeFilter) Check(ctx context.Context, data *MarketData, signal *Signal, now time.Time) (string, error) {
+	if f.min24h > 0 && data.QuoteVolume24h < f.min24h {
+		return fmt.Sprintf("24h volume %.0f USDT below %.0f", data.QuoteVolume24h, f.min24h), nil
+	}
+	if f.minCandle > 0 {
+		closed := closedKlines(data.Klines)
+		if len(closed) == 0 {
+			return "no closed candle to measure volume", nil
+		}
+		if volume := closed[len(closed)-1].QuoteAssetVolume; volume < f.minCandle {
+			return fmt.Sprintf("last candle volume %.0f USDT below %.0f", volume, f.minCandle), nil
+		}
+	}
+	return "", nil
+}
+
+// spreadFilter rejects signals while the order book is wide
+type spreadFilter struct {
+	client exchange.Client
+	maxBps float64
+}
+
+func (f *spreadFilter) Name() string { return "max_spread" }
+
+func (f *spreadFilter) Check(ctx context.Context, data *MarketData, signal *Signal, now time.Time) (string, error) {
+	book, err := f.client.GetOrderBook(ctx, data.Symbol, 5)
+	if err != nil {
+		return "", fmt.Errorf("failed to get order book: %w", err)
+	}
+	if spread := book.SpreadBps(); spread > f.maxBps {
+		return fmt.Sprintf("spread %.1f bps above %.1f", spread, f.maxBps), nil
+	}
+	return "", nil
+}
+
+// trendFilter only passes entries in the direction of the higher timeframe trend: longs
+// while its last close is above its SMA, shorts while below
+type trendFilter struct {
+	client   exchange.Client
+	interval string
+	period   int
+
+	mu     sync.Mutex
+	trends map[string]higherTrend
+}
+
+// higherTrend is the trend of a symbol as of a closed higher timeframe candle
+type higherTrend struct {
+	close   float64
+	sma     float64
+	expires time.Time // Close time of the forming candle, when the trend can change
+}
+
+func (f *trendFilter) Name() string { return "trend_alignment" }
+
+func (f *trendFilter) Check(ctx context.Context, data *MarketData, signal *Signal, now time.Time) (string, error) {
+	trend, err := f.trend(ctx, data.Symbol, now)
+	if err != nil {
+		return "", err
+	}
+
+	if signal.PositionSide == "SHORT" {
+		if trend.close >= trend.sma {
+			return fmt.Sprintf("%s close %.6g is not below its SMA%d %.6g", f.interval, trend.close, f.period, trend.sma), nil
+		}
+		return "", nil
+	}
+	if trend.close <= trend.sma {
+		return fmt.Sprintf("%s close %.6g is not above its SMA%d %.6g", f.interval, trend.close, f.period, trend.sma), nil
+	}
+	return "", nil
+}
+
+// trend returns the symbol's higher timeframe trend, fetching it once per candle
+func (f *trendFilter) trend(ctx context.Context, symbol string, now time.Time) (higherTrend, error) {
+	f.mu.Lock()
+	cached, ok := f.trends[symbol]
+	f.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached, nil
+	}
+
+	// One extra for the forming candle
+	klines, err := f.client.GetKlines(ctx, symbol, f.interval, f.period+1)
+	if err != nil {
+		return higherTrend{}, fmt.Errorf("failed to get %s klines: %w", f.interval, err)
+	}
+	closed := closedKlines(klines)
+	if len(closed) < f.period {
+		return higherTrend{}, fmt.Errorf("only %d closed %s klines, need %d", len(closed), f.interval, f.period)
+	}
+
+	closes := make([]float64, len(closed))
+	for i, kline := range closed {
+		closes[i] = kline.Close
+	}
+	trend := higherTrend{
+		close:   closes[len(closes)-1],
+		sma:     utils.CalculateMovingAverage(closes, f.period),
+		expires: time.UnixMilli(klines[len(klines)-1].CloseTime + 1),
+	}
+
+	f.mu.Lock()
+	f.trends[symbol] = trend
+	f.mu.Unlock()
+	return trend, nil
+}
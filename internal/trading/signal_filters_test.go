@@ -0,0 +1,114 @@
+package trading
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/exchange"
+)
+
+// filterClient serves fixed klines and order books; other calls panic
+type filterClient struct {
+	exchange.Client
+	klines     []*exchange.KlineData
+	book       *exchange.OrderBook
+	klineCalls int
+}
+
+func (c *filterClient) GetKlines(ctx context.Context, symbol, interval string, limit int) ([]*exchange.KlineData, error) {
+	c.klineCalls++
+	return c.klines, nil
+}
+
+func (c *filterClient) GetOrderBook(ctx context.Context, symbol string, limit int) (*exchange.OrderBook, error) {
+	return c.book, nil
+}
+
+func TestSignalFilterChain(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 5, 0, 0, time.UTC)
+	// Four closed hourly candles rising to 13 and the forming one
+	hourly := make([]*exchange.KlineData, 5)
+	for i := range hourly {
+		open := now.Add(time.Duration(i-4) * time.Hour).Truncate(time.Hour)
+		hourly[i] = &exchange.KlineData{OpenTime: open.UnixMilli(), Close: float64(10 + i), CloseTime: open.Add(time.Hour).UnixMilli() - 1}
+	}
+	client := &filterClient{
+		klines: hourly,
+		book: &exchange.OrderBook{
+			Bids: []exchange.PriceLevel{{Price: 99.99, Quantity: 1}},
+			Asks: []exchange.PriceLevel{{Price: 100.01, Quantity: 1}},
+		},
+	}
+
+	chain, err := newSignalFilterChain(config.SignalFilterConfig{
+		MinQuoteVolume24h: 1e6,
+		MaxSpreadBps:      5,
+		TrendInterval:     "1h",
+		TrendPeriod:       3,
+		AllowedHours:      []string{"08:00-16:00"},
+		Timezone:          "UTC",
+		CooldownMinutes:   30,
+	}, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := &MarketData{Symbol: "BTCUSDT", Price: 100, QuoteVolume24h: 2e6}
+	long := &Signal{Action: "BUY", PositionSide: "LONG"}
+
+	if filter, reason := chain.check(context.Background(), data, long, now); filter != "" {
+		t.Fatalf("signal rejected by %s: %s", filter, reason)
+	}
+
+	tests := []struct {
+		name   string
+		data   *MarketData
+		signal *Signal
+		at     time.Time
+		filter string
+	}{
+		{"cooling down", data, long, now.Add(10 * time.Minute), "cooldown"},
+		{"outside hours", data, long, now.Add(8 * time.Hour), "time_of_day"},
+		{"thin market", &MarketData{Symbol: "ETHUSDT", QuoteVolume24h: 5e5}, long, now, "min_volume"},
+		{"counter trend", &MarketData{Symbol: "ETHUSDT", QuoteVolume24h: 2e6}, &Signal{Action: "BUY", PositionSide: "SHORT"}, now, "trend_alignment"},
+	}
+	for _, tt := range tests {
+		filter, reason := chain.check(context.Background(), tt.data, tt.signal, tt.at)
+		if filter != tt.filter || reason == "" {
+			t.Errorf("%s: rejected by %q (%s), want %s", tt.name, filter, reason, tt.filter)
+		}
+	}
+
+	// The higher timeframe trend is fetched once per symbol and candle
+	if _, reason := chain.check(context.Background(), data, long, now.Add(40*time.Minute)); reason != "" {
+		t.Fatalf("signal after the cooldown rejected: %s", reason)
+	}
+	if client.klineCalls != 2 {
+		t.Errorf("fetched hourly klines %d times, want 2", client.klineCalls)
+	}
+
+	client.book.Asks[0].Price = 100.2
+	filter, reason := chain.check(context.Background(), &MarketData{Symbol: "SOLUSDT", QuoteVolume24h: 2e6}, long, now)
+	if filter != "max_spread" || !strings.Contains(reason, "spread") {
+		t.Errorf("wide spread: rejected by %q (%s)", filter, reason)
+	}
+}
+
+func TestSignalFilterConfigFor(t *testing.T) {
+	cfg := config.SignalFilterConfig{
+		MaxSpreadBps: 5,
+		TrendPeriod:  50,
+		Strategies: map[string]config.SignalFilterConfig{
+			"breakout": {TrendInterval: "4h", MaxSpreadBps: 2},
+		},
+	}
+	breakout := cfg.For("breakout")
+	if breakout.MaxSpreadBps != 2 || breakout.TrendInterval != "4h" || breakout.TrendPeriod != 50 || breakout.Strategies != nil {
+		t.Fatalf("breakout filters: %+v", breakout)
+	}
+	if rsi := cfg.For("rsi"); rsi.MaxSpreadBps != 5 || rsi.TrendInterval != "" {
+		t.Fatalf("rsi filters: %+v", rsi)
+	}
+}
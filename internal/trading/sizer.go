@@ -0,0 +1,235 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/exchange"
+)
+
+// Sizer decides entry quantities from live account equity, so position sizes scale with
+// the account instead of being fixed inside strategies
+type Sizer struct {
+	config         config.SizingConfig
+	strategyName   string // Strategy recorded on trades, for Kelly statistics
+	riskManager    *RiskManager
+	exchangeClient exchange.Client
+	repository     database.Repository
+	volTarget      *VolTargeter    // Scales every size toward the portfolio volatility target; nil disables
+	drawdown       *DrawdownScaler // Scales every size down while equity is below its high-water mark; nil disables
+	profitLock     *ProfitLock     // Scales every size down once the daily profit target locks with the reduce action; nil disables
+	contracts      *contractSpecs
+}
+
+// NewSizer creates a position sizer with the sizing already resolved for the strategy
+func NewSizer(cfg config.SizingConfig, strategyName string, riskManager *RiskManager, exchangeClient exchange.Client, repository database.Repository, volTarget *VolTargeter, drawdown *DrawdownScaler, profitLock *ProfitLock, contracts *contractSpecs) *Sizer {
+	return &Sizer{
+		config:         cfg,
+		strategyName:   strategyName,
+		riskManager:    riskManager,
+		exchangeClient: exchangeClient,
+		repository:     repository,
+		volTarget:      volTarget,
+		drawdown:       drawdown,
+		profitLock:     profitLock,
+		contracts:      contracts,
+	}
+}
+
+// kellyStats summarizes the strategy's recent closed trades
+type kellyStats struct {
+	trades  int
+	winRate float64
+	payoff  float64 // Average win over average loss
+}
+
+// fraction returns the full Kelly fraction W - (1-W)/R
+func (k *kellyStats) fraction() float64 {
+	if k.payoff <= 0 {
+		return 0
+	}
+	return k.winRate - (1-k.winRate)/k.payoff
+}
+
+// Size sets the quantity of an entry signal according to the sizing mode and the portfolio
+// volatility scale. Every mode except strategy sizing is capped at max_position_size.
+// Safety orders are scaled with the entry so the ladder keeps its shape. Sizes are worked out
+// in the base asset and converted to contracts for inverse symbols.
+func (s *Sizer) Size(ctx context.Context, signal *Signal, data *MarketData) error {
+	if err := s.size(ctx, signal, data); err != nil {
+		return err
+	}
+	if data == nil || !s.contracts.get(data.Symbol).Inverse() {
+		return nil
+	}
+
+	signal.Quantity = s.contracts.contractQuantity(data.Symbol, signal.Quantity, signal.Price)
+	for i := range signal.SafetyOrders {
+		order := &signal.SafetyOrders[i]
+		order.Quantity = s.contracts.contractQuantity(data.Symbol, order.Quantity, order.Price)
+	}
+	return nil
+}
+
+// size sets the base asset quantity of the signal
+func (s *Sizer) size(ctx context.Context, signal *Signal, data *MarketData) error {
+	if s.config.Mode == config.SizingModeStrategy && signal.Quantity > 0 {
+		if scale := s.sizeScale(); scale != 1 {
+			signal.Quantity *= scale
+			for i := range signal.SafetyOrders {
+				signal.SafetyOrders[i].Quantity *= scale
+			}
+		}
+		return nil
+	}
+	if signal.Price <= 0 {
+		return fmt.Errorf("signal has no price to size from")
+	}
+
+	var quantity float64
+	if s.config.Mode == config.SizingModeFixedNotional {
+		quantity = s.config.Notional / signal.Price
+	} else {
+		equity, err := s.equity(ctx)
+		if err != nil {
+			return err
+		}
+		if quantity, err = s.sizeFromEquity(equity, signal, data); err != nil {
+			return err
+		}
+	}
+
+	quantity *= s.sizeScale()
+	quantity = math.Min(quantity, s.riskManager.Limits().MaxPositionSize/signal.Price)
+	if quantity <= 0 {
+		return fmt.Errorf("sized quantity is zero")
+	}
+
+	if signal.Quantity > 0 {
+		scale := quantity / signal.Quantity
+		for i := range signal.SafetyOrders {
+			signal.SafetyOrders[i].Quantity *= scale
+		}
+	}
+	signal.Quantity = quantity
+	return nil
+}
+
+// sizeScale returns the product of the portfolio volatility targeting, drawdown and profit
+// lock factors, 1 when all are disabled
+func (s *Sizer) sizeScale() float64 {
+	scale := 1.0
+	if s.volTarget != nil {
+		scale *= s.volTarget.Scale()
+	}
+	if s.drawdown != nil {
+		scale *= s.drawdown.Scale()
+	}
+	if s.profitLock != nil {
+		scale *= s.profitLock.Scale()
+	}
+	return scale
+}
+
+func (s *Sizer) sizeFromEquity(equity float64, signal *Signal, data *MarketData) (float64, error) {
+	switch s.config.Mode {
+	case config.SizingModeFixedFractional:
+		return equity * s.config.FractionPercent / 100 / signal.Price, nil
+
+	case config.SizingModeVolatilityTarget:
+		if data == nil || data.ATR <= 0 {
+			return 0, fmt.Errorf("no ATR available for volatility targeting")
+		}
+		return equity * s.config.TargetVolatilityPercent / 100 / data.ATR, nil
+
+	case config.SizingModeKelly:
+		stats, err := s.kellyStats()
+		if err != nil {
+			return 0, err
+		}
+		if stats.trades < s.config.KellyMinTrades {
+			// Too few trades for a meaningful estimate; size by risk until there are enough
+			return s.riskSize(equity, signal), nil
+		}
+		fraction := stats.fraction() * s.config.KellyFraction
+		if fraction <= 0 {
+			return 0, fmt.Errorf("no edge over the last %d trades (win rate %.2f, payoff %.2f)", stats.trades, stats.winRate, stats.payoff)
+		}
+		riskAmount := equity * math.Min(fraction, s.config.KellyMaxPercent/100)
+		return riskAmount / (signal.Price - s.stopPrice(signal)), nil
+
+	default:
+		return s.riskSize(equity, signal), nil
+	}
+}
+
+// riskSize risks RiskPerTrade percent of equity between the entry and the stop
+func (s *Sizer) riskSize(equity float64, signal *Signal) float64 {
+	return s.riskManager.CalculatePositionSize(equity, signal.Price, s.stopPrice(signal))
+}
+
+// stopPrice is the signal's stop when it sets one below the entry, otherwise the
+// configured stop-loss percent
+func (s *Sizer) stopPrice(signal *Signal) float64 {
+	if signal.StopLoss > 0 && signal.StopLoss < signal.Price {
+		return signal.StopLoss
+	}
+	return s.riskManager.CalculateStopLoss(signal.Price, "LONG")
+}
+
+func (s *Sizer) equity(ctx context.Context) (float64, error) {
+	account, err := s.exchangeClient.GetAccountInfo(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get account info: %w", err)
+	}
+	if account.TotalMarginBalance <= 0 {
+		return 0, fmt.Errorf("account equity %.2f is not positive", account.TotalMarginBalance)
+	}
+	return account.TotalMarginBalance, nil
+}
+
+// kellyStats computes win rate and payoff over the strategy's last closed trades. Fills of
+// the same order are one trade.
+func (s *Sizer) kellyStats() (*kellyStats, error) {
+	fills, err := s.repository.GetRealizedTrades(s.strategyName, s.config.KellyLookback*4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get realized trades: %w", err)
+	}
+
+	pnlByOrder := make(map[uint]float64)
+	var orders []uint
+	for _, fill := range fills {
+		if _, ok := pnlByOrder[fill.OrderID]; !ok {
+			if len(orders) == s.config.KellyLookback {
+				continue
+			}
+			orders = append(orders, fill.OrderID)
+		}
+		pnlByOrder[fill.OrderID] += fill.RealizedPnL
+	}
+
+	stats := &kellyStats{trades: len(orders)}
+	var wins, losses, totalWin, totalLoss float64
+	for _, id := range orders {
+		if pnl := pnlByOrder[id]; pnl > 0 {
+			wins++
+			totalWin += pnl
+		} else if pnl < 0 {
+			losses++
+			totalLoss -= pnl
+		}
+	}
+	if stats.trades > 0 {
+		stats.winRate = wins / float64(stats.trades)
+	}
+	switch {
+	case wins > 0 && losses > 0:
+		stats.payoff = (totalWin / wins) / (totalLoss / losses)
+	case wins > 0:
+		stats.payoff = math.Inf(1) // No losses yet; the fraction reduces to the win rate
+	}
+	return stats, nil
+}
@@ -0,0 +1,135 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/pkg/utils"
+)
+
+// slippageBps returns how much worse than the reference a fill price is, in basis points
+func slippageBps(side string, reference, fillPrice float64) float64 {
+	if reference <= 0 {
+		return 0
+	}
+
+	bps := (fillPrice - reference) / reference * 10000
+	if side == "SELL" {
+		bps = -bps
+	}
+	return bps
+}
+
+// guardSlippage checks a MARKET entry against the order book before it is sent. It returns
+// the order to place, converted to an IOC limit order at the slippage bound when configured,
+// or an error when the order is rejected.
+func (e *Engine) guardSlippage(ctx context.Context, order *exchange.OrderRequest, referencePrice float64) (*exchange.OrderRequest, error) {
+	cfg := e.config.Slippage
+	if !cfg.Enabled || order.Type != "MARKET" {
+		return order, nil
+	}
+
+	book, err := e.orderBook(ctx, order.Symbol, cfg.BookDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check slippage: %w", err)
+	}
+
+	if spread := book.SpreadBps(); cfg.MaxSpreadBps > 0 && spread > cfg.MaxSpreadBps {
+		return nil, fmt.Errorf("spread %.1f bps exceeds limit %.1f bps", spread, cfg.MaxSpreadBps)
+	}
+
+	if referencePrice <= 0 {
+		referencePrice = book.MidPrice()
+	}
+
+	expected, ok := book.ExpectedFillPrice(order.Side, order.Quantity)
+	if ok {
+		slippage := slippageBps(order.Side, referencePrice, expected)
+		if slippage <= cfg.MaxSlippageBps {
+			return order, nil
+		}
+		e.logger.Warnf("Expected slippage for %s %s is %.1f bps (limit %.1f bps)",
+			order.Side, order.Symbol, slippage, cfg.MaxSlippageBps)
+	} else {
+		e.logger.Warnf("Order book for %s too thin to fill %.6f within %d levels", order.Symbol, order.Quantity, cfg.BookDepth)
+	}
+
+	if cfg.Action != "limit" {
+		return nil, fmt.Errorf("expected slippage exceeds %.1f bps", cfg.MaxSlippageBps)
+	}
+
+	bound := cfg.MaxSlippageBps / 10000
+	limitPrice := referencePrice * (1 + bound)
+	if order.Side == "SELL" {
+		limitPrice = referencePrice * (1 - bound)
+	}
+
+	if info, err := e.exchangeClient.GetSymbolInfo(ctx, order.Symbol); err == nil {
+		limitPrice = utils.NormalizePrice(limitPrice, info.TickSize)
+	} else {
+		e.logger.Warnf("Failed to get tick size for %s: %v", order.Symbol, err)
+	}
+
+	limited := *order
+	limited.Type = "LIMIT"
+	limited.TimeInForce = "IOC"
+	limited.Price = limitPrice
+	e.logger.Infof("Converted %s %s market order to IOC limit at %.8f", order.Side, order.Symbol, limitPrice)
+
+	return &limited, nil
+}
+
+// recordFills stores the fills of an order with their slippage against the reference price,
+// and the time from submission to the first fill on the order
+func (e *Engine) recordFills(ctx context.Context, order *models.Order, response *exchange.OrderResponse, referencePrice float64) {
+	if response.ExecutedQty <= 0 {
+		return
+	}
+
+	fills, err := e.exchangeClient.GetOrderTrades(ctx, response.Symbol, response.OrderID)
+	if err != nil {
+		e.logger.Errorf("Failed to get fills for order %d: %v", response.OrderID, err)
+		return
+	}
+
+	var firstFill int64
+	for _, fill := range fills {
+		if firstFill == 0 || fill.Time < firstFill {
+			firstFill = fill.Time
+		}
+		trade := &models.Trade{
+			ExchangeTradeID: fmt.Sprintf("%d", fill.ID),
+			OrderID:         order.ID,
+			Symbol:          fill.Symbol,
+			Side:            fill.Side,
+			Quantity:        fill.Quantity,
+			Price:           fill.Price,
+			QuoteQty:        e.contracts.get(fill.Symbol).Notional(fill.Quantity, fill.Price),
+			Commission:      fill.Commission,
+			CommissionAsset: fill.CommissionAsset,
+			RealizedPnL:     fill.RealizedPnL,
+			IsMaker:         fill.IsMaker,
+			ExpectedPrice:   referencePrice,
+			SlippageBps:     slippageBps(fill.Side, referencePrice, fill.Price),
+			PositionSide:    response.PositionSide,
+			Strategy:        order.Strategy,
+			TradeTime:       time.UnixMilli(fill.Time),
+		}
+
+		if err := e.repository.CreateTrade(trade); err != nil {
+			e.logger.Errorf("Failed to save trade %s: %v", trade.ExchangeTradeID, err)
+		}
+	}
+
+	if order.ID == 0 || order.SubmittedAt == nil || firstFill == 0 {
+		return
+	}
+	// Exchange and local clocks differ slightly; a fill can appear to precede submission
+	order.FillLatencyMs = max(firstFill-order.SubmittedAt.UnixMilli(), 0)
+	if err := e.repository.UpdateOrder(order); err != nil {
+		e.logger.Errorf("Failed to save fill latency of order %s: %v", order.ExchangeOrderID, err)
+	}
+}
@@ -0,0 +1,89 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"contract_playground/internal/events"
+	"contract_playground/internal/notify"
+)
+
+// dataAge returns how long ago the market data of a symbol was last refreshed. Symbols that
+// were never refreshed count from the start of the engine.
+func (e *Engine) dataAge(symbol string) time.Duration {
+	e.marketDataMu.RLock()
+	updated, ok := e.dataTimes[symbol]
+	e.marketDataMu.RUnlock()
+
+	if !ok {
+		updated = e.startedAt
+	}
+	return time.Since(updated)
+}
+
+// isStale reports whether the market data of a symbol is too old to act on
+func (e *Engine) isStale(symbol string) bool {
+	maxAge := time.Duration(e.config.StaleData.MaxAgeSeconds) * time.Second
+	return e.config.StaleData.Enabled && e.dataAge(symbol) > maxAge
+}
+
+// staleFilter returns a reason when stale market data blocks new entries
+func (e *Engine) staleFilter(symbol string) string {
+	if !e.isStale(symbol) {
+		return ""
+	}
+	return fmt.Sprintf("market data is %s old", e.dataAge(symbol).Truncate(time.Second))
+}
+
+// monitorStaleData alerts once when a symbol's market data goes stale and again when it
+// recovers. Entries are blocked and exits are reduce-only while it is stale.
+func (e *Engine) monitorStaleData(ctx context.Context) {
+	interval := time.Duration(e.config.StaleData.MaxAgeSeconds) * time.Second / 4
+	if interval > 15*time.Second {
+		interval = 15 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	stale := make(map[string]bool, len(e.config.Symbols))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, symbol := range e.config.Symbols {
+				if now := e.isStale(symbol); now != stale[symbol] {
+					stale[symbol] = now
+					e.staleDataChanged(ctx, symbol, now)
+				}
+			}
+		}
+	}
+}
+
+func (e *Engine) staleDataChanged(ctx context.Context, symbol string, stale bool) {
+	age := e.dataAge(symbol).Truncate(time.Second)
+	msg := &notify.Message{
+		Title:  fmt.Sprintf("%s market data recovered", symbol),
+		Body:   "Market data is updating again; new entries are allowed.",
+		Level:  notify.LevelInfo,
+		Symbol: symbol,
+		Time:   time.Now(),
+	}
+	action := "stale_data_recovered"
+	if stale {
+		msg.Title = fmt.Sprintf("%s market data stale", symbol)
+		msg.Body = fmt.Sprintf("No market data update for %s. New entries are blocked and exits are reduce-only until data resumes.", age)
+		msg.Level = notify.LevelWarning
+		action = "stale_data"
+		e.logger.Warnf("Market data for %s is stale (%s old)", symbol, age)
+	} else {
+		e.logger.Infof("Market data for %s recovered", symbol)
+	}
+
+	e.publishEvent(events.TypeRisk, action, symbol, map[string]interface{}{"age_seconds": age.Seconds()})
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver stale data notification: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"contract_playground/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// StatefulStrategy is implemented by strategies whose in-memory state should survive a
+// restart. SaveState returns nil when there is nothing to save for the symbol.
+type StatefulStrategy interface {
+	SaveState(symbol string) (json.RawMessage, error)
+	LoadState(symbol string, state json.RawMessage) error
+}
+
+// loadStrategyState restores the saved state of every symbol, skipping state older than
+// max_age_minutes since the market moved on while the engine was down
+func (e *Engine) loadStrategyState() {
+	cfg := e.config.Strategy.State
+	for _, symbol := range e.config.Symbols {
+		saved, err := e.repository.GetStrategyState(e.strategy.Name(), symbol)
+		if err == gorm.ErrRecordNotFound {
+			continue
+		}
+		if err != nil {
+			e.logger.Errorf("Failed to load strategy state for %s: %v", symbol, err)
+			continue
+		}
+
+		age := time.Since(saved.UpdatedAt)
+		if cfg.MaxAgeMinutes > 0 && age > time.Duration(cfg.MaxAgeMinutes)*time.Minute {
+			e.logger.Infof("Ignoring strategy state for %s saved %s ago", symbol, age.Truncate(time.Second))
+			continue
+		}
+		if err := e.statefulStrategy.LoadState(symbol, json.RawMessage(saved.State)); err != nil {
+			e.logger.Errorf("Failed to restore strategy state for %s: %v", symbol, err)
+			continue
+		}
+		e.logger.Infof("Restored strategy state for %s saved %s ago", symbol, age.Truncate(time.Second))
+	}
+}
+
+// persistStrategyState saves the strategy state periodically
+func (e *Engine) persistStrategyState(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(e.config.Strategy.State.SaveIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.saveStrategyState()
+		}
+	}
+}
+
+// saveStrategyState stores the strategy state of every symbol
+func (e *Engine) saveStrategyState() {
+	for _, symbol := range e.config.Symbols {
+		state, err := e.statefulStrategy.SaveState(symbol)
+		if err != nil {
+			e.logger.Errorf("Failed to serialize strategy state for %s: %v", symbol, err)
+			continue
+		}
+		if state == nil {
+			continue
+		}
+		if err := e.repository.SaveStrategyState(&models.StrategyState{
+			Strategy: e.strategy.Name(),
+			Symbol:   symbol,
+			State:    string(state),
+		}); err != nil {
+			e.logger.Errorf("Failed to save strategy state for %s: %v", symbol, err)
+		}
+	}
+}
+
+// savePriceHistory serializes a strategy's price history of the symbol
+func savePriceHistory(history []float64) (json.RawMessage, error) {
+	if len(history) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(history)
+}
@@ -0,0 +1,207 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"contract_playground/internal/models"
+	"contract_playground/pkg/utils"
+)
+
+// StochasticStrategy buys a bullish %K/%D crossover in the oversold zone when Williams %R
+// confirms the oversold reading and RSI is not elevated, and sells the bearish crossover in
+// the overbought zone
+type StochasticStrategy struct {
+	name             string
+	orderValue       float64 // USDT per entry
+	kPeriod          int
+	smoothK          int
+	dPeriod          int
+	oversold         float64 // %K/%D level below which crossovers are bought
+	overbought       float64 // %K/%D level above which crossovers are sold
+	williamsPeriod   int
+	williamsBuy      float64 // Williams %R must have been at or below this within the lookback
+	williamsLookback int     // Candles to look back for the Williams %R confirmation
+	rsiPeriod        int
+	rsiMax           float64 // RSI must be below this to buy (100 = no filter)
+	stopLossPercent  float64 // Percent below entry (0 = no stop)
+	stopWorkingType  string
+}
+
+// NewStochasticStrategy creates a new stochastic crossover strategy
+func NewStochasticStrategy() Strategy {
+	return &StochasticStrategy{
+		name:             "Stochastic Strategy",
+		orderValue:       100,
+		kPeriod:          14,
+		smoothK:          3,
+		dPeriod:          3,
+		oversold:         20,
+		overbought:       80,
+		williamsPeriod:   14,
+		williamsBuy:      -80,
+		williamsLookback: 3,
+		rsiPeriod:        14,
+		rsiMax:           45,
+		stopLossPercent:  2.0,
+		stopWorkingType:  WorkingTypeContractPrice,
+	}
+}
+
+// Name returns the strategy name
+func (s *StochasticStrategy) Name() string {
+	return s.name
+}
+
+// Initialize initializes the stochastic strategy with parameters
+func (s *StochasticStrategy) Initialize(config map[string]interface{}) error {
+	if val, ok := getFloatParam(config, "order_usdt"); ok {
+		s.orderValue = val
+	}
+	if val, ok := getFloatParam(config, "k_period"); ok {
+		s.kPeriod = int(val)
+	}
+	if val, ok := getFloatParam(config, "smooth_k"); ok {
+		s.smoothK = int(val)
+	}
+	if val, ok := getFloatParam(config, "d_period"); ok {
+		s.dPeriod = int(val)
+	}
+	if val, ok := getFloatParam(config, "oversold"); ok {
+		s.oversold = val
+	}
+	if val, ok := getFloatParam(config, "overbought"); ok {
+		s.overbought = val
+	}
+	if val, ok := getFloatParam(config, "williams_period"); ok {
+		s.williamsPeriod = int(val)
+	}
+	if val, ok := getFloatParam(config, "williams_buy"); ok {
+		s.williamsBuy = val
+	}
+	if val, ok := getFloatParam(config, "williams_lookback"); ok {
+		s.williamsLookback = int(val)
+	}
+	if val, ok := getFloatParam(config, "rsi_period"); ok {
+		s.rsiPeriod = int(val)
+	}
+	if val, ok := getFloatParam(config, "rsi_max"); ok {
+		s.rsiMax = val
+	}
+	if val, ok := getFloatParam(config, "stop_loss"); ok {
+		s.stopLossPercent = val
+	}
+	if workingType, ok := getStringParam(config, "stop_working_type"); ok && workingType != "" {
+		s.stopWorkingType = workingType
+	}
+
+	if s.orderValue <= 0 {
+		return fmt.Errorf("order value must be positive")
+	}
+	if s.kPeriod < 1 || s.smoothK < 1 || s.dPeriod < 1 || s.williamsPeriod < 1 || s.rsiPeriod < 1 {
+		return fmt.Errorf("stochastic, Williams %%R and RSI periods must be positive")
+	}
+	if s.williamsLookback < 1 {
+		return fmt.Errorf("williams lookback must be positive")
+	}
+	if s.oversold <= 0 || s.overbought >= 100 || s.oversold >= s.overbought {
+		return fmt.Errorf("oversold must be below overbought, both between 0 and 100")
+	}
+	if s.williamsBuy < -100 || s.williamsBuy > 0 {
+		return fmt.Errorf("williams buy level must be between -100 and 0")
+	}
+	if s.stopLossPercent < 0 {
+		return fmt.Errorf("stop loss must not be negative")
+	}
+
+	return nil
+}
+
+// ShouldBuy buys a bullish crossover in the oversold zone confirmed by Williams %R and RSI
+func (s *StochasticStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
+	k, d, williams, closes := s.calculate(data)
+	i := len(closes) - 1
+	if i < 1 || math.IsNaN(d[i-1]) || math.IsNaN(williams[i]) {
+		return &Signal{Action: "HOLD", Reason: "Insufficient data for stochastic"}, nil
+	}
+
+	crossUp := k[i-1] <= d[i-1] && k[i] > d[i]
+	if !crossUp || d[i-1] >= s.oversold {
+		return &Signal{Action: "HOLD", Reason: fmt.Sprintf("Stochastic %%K %.1f %%D %.1f", k[i], d[i])}, nil
+	}
+
+	confirmed := false
+	for j := i; j > i-s.williamsLookback && j >= 0; j-- {
+		if !math.IsNaN(williams[j]) && williams[j] <= s.williamsBuy {
+			confirmed = true
+			break
+		}
+	}
+	if !confirmed {
+		return &Signal{Action: "HOLD", Reason: fmt.Sprintf("Williams %%R %.1f not oversold", williams[i])}, nil
+	}
+
+	rsi := utils.CalculateRSI(closes, s.rsiPeriod)
+	if rsi >= s.rsiMax {
+		return &Signal{Action: "HOLD", Reason: fmt.Sprintf("RSI filter: %.2f >= %.2f", rsi, s.rsiMax)}, nil
+	}
+
+	return &Signal{
+		Action:       "BUY",
+		Quantity:     s.orderValue / data.Price,
+		Price:        data.Price,
+		Confidence:   math.Min(1.0, (s.oversold-d[i-1])/s.oversold+0.5),
+		Reason:       fmt.Sprintf("Stochastic cross up %%K %.1f %%D %.1f, Williams %%R %.1f, RSI %.2f", k[i], d[i], williams[i], rsi),
+		PositionSide: "LONG",
+	}, nil
+}
+
+// ShouldSell sells a bearish crossover in the overbought zone, or at the stop
+func (s *StochasticStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
+	if s.stopLossPercent > 0 {
+		stop := position.EntryPrice * (1 - s.stopLossPercent/100)
+		if data.WorkingPrice(s.stopWorkingType) <= stop {
+			return &Signal{
+				Action:     "SELL",
+				Quantity:   position.Size,
+				Price:      data.Price,
+				Confidence: 1.0,
+				Reason:     fmt.Sprintf("Stop loss: %.6f", stop),
+			}, nil
+		}
+	}
+
+	k, d, williams, closes := s.calculate(data)
+	i := len(closes) - 1
+	if i < 1 || math.IsNaN(d[i-1]) {
+		return &Signal{Action: "HOLD", Reason: "Insufficient data for stochastic"}, nil
+	}
+
+	if k[i-1] >= d[i-1] && k[i] < d[i] && d[i-1] > s.overbought {
+		return &Signal{
+			Action:     "SELL",
+			Quantity:   position.Size,
+			Price:      data.Price,
+			Confidence: math.Min(1.0, (d[i-1]-s.overbought)/(100-s.overbought)+0.5),
+			Reason:     fmt.Sprintf("Stochastic cross down %%K %.1f %%D %.1f, Williams %%R %.1f", k[i], d[i], williams[i]),
+		}, nil
+	}
+
+	return &Signal{Action: "HOLD", Reason: fmt.Sprintf("Stochastic %%K %.1f %%D %.1f", k[i], d[i])}, nil
+}
+
+// calculate computes the oscillator series over the closed candles
+func (s *StochasticStrategy) calculate(data *MarketData) (k, d, williams, closes []float64) {
+	closed := closedKlines(data.Klines)
+	highs := make([]float64, len(closed))
+	lows := make([]float64, len(closed))
+	closes = make([]float64, len(closed))
+	for i, kline := range closed {
+		highs[i], lows[i], closes[i] = kline.High, kline.Low, kline.Close
+	}
+
+	k, d = utils.CalculateStochastic(highs, lows, closes, s.kPeriod, s.smoothK, s.dPeriod)
+	williams = utils.CalculateWilliamsR(highs, lows, closes, s.williamsPeriod)
+	return k, d, williams, closes
+}
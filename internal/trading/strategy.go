@@ -2,9 +2,14 @@ package trading
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
+	"sync"
+	"time"
 
+	"contract_playground/internal/database"
+	"contract_playground/internal/exchange"
 	"contract_playground/internal/models"
 )
 
@@ -14,7 +19,9 @@ type SMAStrategy struct {
 	shortPeriod     int
 	longPeriod      int
 	minConfidence   float64
-	priceHistory    map[string][]float64
+	stopWorkingType string
+	priceHistory    map[string]*rollingPrices
+	historyMu       sync.Mutex // Symbols are evaluated concurrently
 }
 
 // NewSMAStrategy creates a new SMA strategy
@@ -24,7 +31,8 @@ func NewSMAStrategy() Strategy {
 		shortPeriod:   10,
 		longPeriod:    20,
 		minConfidence: 0.7,
-		priceHistory:  make(map[string][]float64),
+		stopWorkingType: WorkingTypeContractPrice,
+		priceHistory:  make(map[string]*rollingPrices),
 	}
 }
 
@@ -35,43 +43,43 @@ func (s *SMAStrategy) Name() string {
 
 // Initialize initializes the strategy with parameters
 func (s *SMAStrategy) Initialize(config map[string]interface{}) error {
-	if val, ok := config["short_period"]; ok {
-		if period, ok := val.(float64); ok {
-			s.shortPeriod = int(period)
-		}
+	if period, ok := getFloatParam(config, "short_period"); ok {
+		s.shortPeriod = int(period)
 	}
 	
-	if val, ok := config["long_period"]; ok {
-		if period, ok := val.(float64); ok {
-			s.longPeriod = int(period)
-		}
+	if period, ok := getFloatParam(config, "long_period"); ok {
+		s.longPeriod = int(period)
 	}
 	
-	if val, ok := config["min_confidence"]; ok {
-		if conf, ok := val.(float64); ok {
-			s.minConfidence = conf
-		}
+	if conf, ok := getFloatParam(config, "min_confidence"); ok {
+		s.minConfidence = conf
+	}
+	
+	if workingType, ok := getStringParam(config, "stop_working_type"); ok && workingType != "" {
+		s.stopWorkingType = workingType
 	}
 	
 	if s.shortPeriod >= s.longPeriod {
 		return fmt.Errorf("short period must be less than long period")
 	}
 	
+	// Re-window the kept histories for the new periods
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+	for symbol, history := range s.priceHistory {
+		s.priceHistory[symbol] = s.newHistory(history.values())
+	}
+	
 	return nil
 }
 
 // ShouldBuy determines if we should buy
 func (s *SMAStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
-	s.updatePriceHistory(symbol, data.Price)
-	
-	prices := s.priceHistory[symbol]
-	if len(prices) < s.longPeriod {
+	shortSMA, longSMA, ok := s.updatePriceHistory(symbol, data.Price)
+	if !ok {
 		return &Signal{Action: "HOLD", Reason: "Insufficient data"}, nil
 	}
 	
-	shortSMA := s.calculateSMA(prices, s.shortPeriod)
-	longSMA := s.calculateSMA(prices, s.longPeriod)
-	
 	// Buy signal: short SMA crosses above long SMA
 	if shortSMA > longSMA {
 		// Calculate crossover strength for confidence
@@ -79,11 +87,8 @@ func (s *SMAStrategy) ShouldBuy(ctx context.Context, symbol string, data *Market
 		confidence := math.Min(crossoverStrength*10, 1.0) // Scale to 0-1
 		
 		if confidence >= s.minConfidence {
-			quantity := s.calculateQuantity(data.Price, 1000) // $1000 position
-			
 			return &Signal{
 				Action:       "BUY",
-				Quantity:     quantity,
 				Price:        data.Price,
 				Confidence:   confidence,
 				Reason:       fmt.Sprintf("SMA crossover: short=%.2f, long=%.2f", shortSMA, longSMA),
@@ -97,16 +102,11 @@ func (s *SMAStrategy) ShouldBuy(ctx context.Context, symbol string, data *Market
 
 // ShouldSell determines if we should sell
 func (s *SMAStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
-	s.updatePriceHistory(symbol, data.Price)
-	
-	prices := s.priceHistory[symbol]
-	if len(prices) < s.longPeriod {
+	shortSMA, longSMA, ok := s.updatePriceHistory(symbol, data.Price)
+	if !ok {
 		return &Signal{Action: "HOLD", Reason: "Insufficient data"}, nil
 	}
 	
-	shortSMA := s.calculateSMA(prices, s.shortPeriod)
-	longSMA := s.calculateSMA(prices, s.longPeriod)
-	
 	// Sell signal: short SMA crosses below long SMA
 	if shortSMA < longSMA {
 		crossoverStrength := (longSMA - shortSMA) / longSMA
@@ -123,8 +123,8 @@ func (s *SMAStrategy) ShouldSell(ctx context.Context, symbol string, data *Marke
 		}
 	}
 	
-	// Also check for stop loss or take profit
-	pnlPercent := (data.Price - position.EntryPrice) / position.EntryPrice * 100
+	// Also check for stop loss or take profit against the configured working price
+	pnlPercent := (data.WorkingPrice(s.stopWorkingType) - position.EntryPrice) / position.EntryPrice * 100
 	
 	if pnlPercent <= -2.0 { // 2% stop loss
 		return &Signal{
@@ -146,41 +146,98 @@ func (s *SMAStrategy) ShouldSell(ctx context.Context, symbol string, data *Marke
 		}, nil
 	}
 	
+	// Trend still intact: ask to scale in (only acted on when pyramiding is enabled)
+	if shortSMA > longSMA {
+		confidence := math.Min((shortSMA-longSMA)/longSMA*10, 1.0)
+		if confidence >= s.minConfidence {
+			return &Signal{
+				Action:       "BUY",
+				Price:        data.Price,
+				Confidence:   confidence,
+				Reason:       fmt.Sprintf("SMA trend continuation: short=%.2f, long=%.2f", shortSMA, longSMA),
+				PositionSide: "LONG",
+			}, nil
+		}
+	}
+	
 	return &Signal{Action: "HOLD", Reason: "No sell signal"}, nil
 }
 
-// updatePriceHistory updates the price history for a symbol
-func (s *SMAStrategy) updatePriceHistory(symbol string, price float64) {
-	if s.priceHistory[symbol] == nil {
-		s.priceHistory[symbol] = make([]float64, 0)
+// updatePriceHistory adds a price to the symbol's history and returns the short and long
+// SMA; ok is false until the history covers the long period
+func (s *SMAStrategy) updatePriceHistory(symbol string, price float64) (shortSMA, longSMA float64, ok bool) {
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+
+	history := s.priceHistory[symbol]
+	if history == nil {
+		history = s.newHistory(nil)
+		s.priceHistory[symbol] = history
 	}
+	history.push(price)
 	
-	s.priceHistory[symbol] = append(s.priceHistory[symbol], price)
-	
-	// Keep only the data we need
-	maxLength := s.longPeriod + 10
-	if len(s.priceHistory[symbol]) > maxLength {
-		s.priceHistory[symbol] = s.priceHistory[symbol][len(s.priceHistory[symbol])-maxLength:]
+	if history.len() < s.longPeriod {
+		return 0, 0, false
 	}
+	return history.average(s.shortPeriod), history.average(s.longPeriod), true
+}
+
+// newHistory creates a price history keeping the data the SMAs need. Callers hold historyMu.
+func (s *SMAStrategy) newHistory(prices []float64) *rollingPrices {
+	return newRollingPrices(s.longPeriod+10, []int{s.shortPeriod, s.longPeriod}, 0, prices)
+}
+
+// RequiredCandles returns the prices needed for the long SMA
+func (s *SMAStrategy) RequiredCandles() int {
+	return s.longPeriod
 }
 
-// calculateSMA calculates Simple Moving Average
-func (s *SMAStrategy) calculateSMA(prices []float64, period int) float64 {
-	if len(prices) < period {
-		return 0
+// Warmup seeds the price history with the closes of historical candles
+func (s *SMAStrategy) Warmup(symbol string, klines []*exchange.KlineData) {
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+
+	s.priceHistory[symbol] = s.newHistory(closingPrices(klines, s.longPeriod+10))
+}
+
+// SaveState returns the price history of the symbol
+func (s *SMAStrategy) SaveState(symbol string) (json.RawMessage, error) {
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+
+	if history := s.priceHistory[symbol]; history != nil {
+		return savePriceHistory(history.values())
 	}
-	
-	sum := 0.0
-	for i := len(prices) - period; i < len(prices); i++ {
-		sum += prices[i]
+	return nil, nil
+}
+
+// LoadState restores the price history of the symbol
+func (s *SMAStrategy) LoadState(symbol string, state json.RawMessage) error {
+	var history []float64
+	if err := json.Unmarshal(state, &history); err != nil {
+		return fmt.Errorf("failed to decode price history: %w", err)
 	}
-	
-	return sum / float64(period)
+
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+
+	s.priceHistory[symbol] = s.newHistory(history)
+	return nil
 }
 
-// calculateQuantity calculates position quantity based on position value
-func (s *SMAStrategy) calculateQuantity(price, positionValue float64) float64 {
-	return positionValue / price
+// Indicators returns the current short and long SMA of the symbol
+func (s *SMAStrategy) Indicators(symbol string) map[string]float64 {
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+
+	history := s.priceHistory[symbol]
+	if history == nil {
+		history = s.newHistory(nil)
+	}
+	return map[string]float64{
+		"short_sma": history.average(s.shortPeriod),
+		"long_sma":  history.average(s.longPeriod),
+	}
 }
 
 // RSIStrategy implements RSI strategy
@@ -190,7 +247,9 @@ type RSIStrategy struct {
 	oversold      float64
 	overbought    float64
 	minConfidence float64
-	priceHistory  map[string][]float64
+	stopWorkingType string
+	priceHistory  map[string]*rollingPrices
+	historyMu     sync.Mutex // Symbols are evaluated concurrently
 }
 
 // NewRSIStrategy creates a new RSI strategy
@@ -201,7 +260,8 @@ func NewRSIStrategy() Strategy {
 		oversold:      30,
 		overbought:    70,
 		minConfidence: 0.6,
-		priceHistory:  make(map[string][]float64),
+		stopWorkingType: WorkingTypeContractPrice,
+		priceHistory:  make(map[string]*rollingPrices),
 	}
 }
 
@@ -212,28 +272,31 @@ func (r *RSIStrategy) Name() string {
 
 // Initialize initializes the strategy with parameters
 func (r *RSIStrategy) Initialize(config map[string]interface{}) error {
-	if val, ok := config["period"]; ok {
-		if period, ok := val.(float64); ok {
-			r.period = int(period)
-		}
+	if period, ok := getFloatParam(config, "period"); ok {
+		r.period = int(period)
 	}
 	
-	if val, ok := config["oversold"]; ok {
-		if oversold, ok := val.(float64); ok {
-			r.oversold = oversold
-		}
+	if oversold, ok := getFloatParam(config, "oversold"); ok {
+		r.oversold = oversold
 	}
 	
-	if val, ok := config["overbought"]; ok {
-		if overbought, ok := val.(float64); ok {
-			r.overbought = overbought
-		}
+	if overbought, ok := getFloatParam(config, "overbought"); ok {
+		r.overbought = overbought
 	}
 	
-	if val, ok := config["min_confidence"]; ok {
-		if conf, ok := val.(float64); ok {
-			r.minConfidence = conf
-		}
+	if conf, ok := getFloatParam(config, "min_confidence"); ok {
+		r.minConfidence = conf
+	}
+	
+	if workingType, ok := getStringParam(config, "stop_working_type"); ok && workingType != "" {
+		r.stopWorkingType = workingType
+	}
+	
+	// Re-window the kept histories for the new period
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+	for symbol, history := range r.priceHistory {
+		r.priceHistory[symbol] = r.newHistory(history.values())
 	}
 	
 	return nil
@@ -241,25 +304,18 @@ func (r *RSIStrategy) Initialize(config map[string]interface{}) error {
 
 // ShouldBuy determines if we should buy based on RSI
 func (r *RSIStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
-	r.updatePriceHistory(symbol, data.Price)
-	
-	prices := r.priceHistory[symbol]
-	if len(prices) < r.period+1 {
+	rsi, ok := r.updatePriceHistory(symbol, data.Price)
+	if !ok {
 		return &Signal{Action: "HOLD", Reason: "Insufficient data for RSI"}, nil
 	}
 	
-	rsi := r.calculateRSI(prices)
-	
 	// Buy signal: RSI is oversold
 	if rsi < r.oversold {
 		confidence := (r.oversold - rsi) / r.oversold
 		
 		if confidence >= r.minConfidence {
-			quantity := r.calculateQuantity(data.Price, 1000)
-			
 			return &Signal{
 				Action:       "BUY",
-				Quantity:     quantity,
 				Price:        data.Price,
 				Confidence:   confidence,
 				Reason:       fmt.Sprintf("RSI oversold: %.2f", rsi),
@@ -273,15 +329,11 @@ func (r *RSIStrategy) ShouldBuy(ctx context.Context, symbol string, data *Market
 
 // ShouldSell determines if we should sell based on RSI
 func (r *RSIStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
-	r.updatePriceHistory(symbol, data.Price)
-	
-	prices := r.priceHistory[symbol]
-	if len(prices) < r.period+1 {
+	rsi, ok := r.updatePriceHistory(symbol, data.Price)
+	if !ok {
 		return &Signal{Action: "HOLD", Reason: "Insufficient data for RSI"}, nil
 	}
 	
-	rsi := r.calculateRSI(prices)
-	
 	// Sell signal: RSI is overbought
 	if rsi > r.overbought {
 		confidence := (rsi - r.overbought) / (100 - r.overbought)
@@ -297,8 +349,8 @@ func (r *RSIStrategy) ShouldSell(ctx context.Context, symbol string, data *Marke
 		}
 	}
 	
-	// Check stop loss and take profit
-	pnlPercent := (data.Price - position.EntryPrice) / position.EntryPrice * 100
+	// Check stop loss and take profit against the configured working price
+	pnlPercent := (data.WorkingPrice(r.stopWorkingType) - position.EntryPrice) / position.EntryPrice * 100
 	
 	if pnlPercent <= -2.0 {
 		return &Signal{
@@ -323,99 +375,116 @@ func (r *RSIStrategy) ShouldSell(ctx context.Context, symbol string, data *Marke
 	return &Signal{Action: "HOLD", Reason: fmt.Sprintf("RSI: %.2f", rsi)}, nil
 }
 
-// updatePriceHistory updates the price history for RSI calculation
-func (r *RSIStrategy) updatePriceHistory(symbol string, price float64) {
-	if r.priceHistory[symbol] == nil {
-		r.priceHistory[symbol] = make([]float64, 0)
+// updatePriceHistory adds a price to the symbol's history and returns the RSI; ok is false
+// until the history covers period price changes
+func (r *RSIStrategy) updatePriceHistory(symbol string, price float64) (rsi float64, ok bool) {
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+
+	history := r.priceHistory[symbol]
+	if history == nil {
+		history = r.newHistory(nil)
+		r.priceHistory[symbol] = history
 	}
+	history.push(price)
 	
-	r.priceHistory[symbol] = append(r.priceHistory[symbol], price)
-	
-	// Keep only the data we need
-	maxLength := r.period + 20
-	if len(r.priceHistory[symbol]) > maxLength {
-		r.priceHistory[symbol] = r.priceHistory[symbol][len(r.priceHistory[symbol])-maxLength:]
+	if history.len() < r.period+1 {
+		return 50, false
 	}
+	return history.rsi(), true
 }
 
-// calculateRSI calculates the Relative Strength Index
-func (r *RSIStrategy) calculateRSI(prices []float64) float64 {
-	if len(prices) < r.period+1 {
-		return 50 // Neutral RSI
-	}
-	
-	gains := make([]float64, 0)
-	losses := make([]float64, 0)
-	
-	// Calculate price changes
-	for i := 1; i < len(prices); i++ {
-		change := prices[i] - prices[i-1]
-		if change > 0 {
-			gains = append(gains, change)
-			losses = append(losses, 0)
-		} else {
-			gains = append(gains, 0)
-			losses = append(losses, -change)
-		}
-	}
-	
-	if len(gains) < r.period {
-		return 50
-	}
-	
-	// Calculate average gain and loss over the period
-	avgGain := 0.0
-	avgLoss := 0.0
-	
-	// Initial averages
-	for i := 0; i < r.period; i++ {
-		avgGain += gains[len(gains)-r.period+i]
-		avgLoss += losses[len(losses)-r.period+i]
-	}
-	
-	avgGain /= float64(r.period)
-	avgLoss /= float64(r.period)
-	
-	if avgLoss == 0 {
-		return 100
+// newHistory creates a price history keeping the data the RSI needs. Callers hold historyMu.
+func (r *RSIStrategy) newHistory(prices []float64) *rollingPrices {
+	return newRollingPrices(r.period+20, nil, r.period, prices)
+}
+
+// RequiredCandles returns the prices needed for the RSI
+func (r *RSIStrategy) RequiredCandles() int {
+	return r.period + 1
+}
+
+// Warmup seeds the price history with the closes of historical candles
+func (r *RSIStrategy) Warmup(symbol string, klines []*exchange.KlineData) {
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+
+	r.priceHistory[symbol] = r.newHistory(closingPrices(klines, r.period+20))
+}
+
+// SaveState returns the price history of the symbol
+func (r *RSIStrategy) SaveState(symbol string) (json.RawMessage, error) {
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+
+	if history := r.priceHistory[symbol]; history != nil {
+		return savePriceHistory(history.values())
 	}
-	
-	rs := avgGain / avgLoss
-	rsi := 100 - (100 / (1 + rs))
-	
-	return rsi
+	return nil, nil
 }
 
-// calculateQuantity calculates position quantity for RSI strategy
-func (r *RSIStrategy) calculateQuantity(price, positionValue float64) float64 {
-	return positionValue / price
+// LoadState restores the price history of the symbol
+func (r *RSIStrategy) LoadState(symbol string, state json.RawMessage) error {
+	var history []float64
+	if err := json.Unmarshal(state, &history); err != nil {
+		return fmt.Errorf("failed to decode price history: %w", err)
+	}
+
+	r.historyMu.Lock()
+	defer r.historyMu.Unlock()
+
+	r.priceHistory[symbol] = r.newHistory(history)
+	return nil
 }
 
-// GridStrategy implements a grid trading strategy
-type GridStrategy struct {
-	name          string
-	gridSize      float64
-	numGrids      int
-	basePrice     float64
-	positions     map[string][]GridPosition
-	minConfidence float64
+// Indicators returns the current RSI of the symbol and its thresholds
+func (r *RSIStrategy) Indicators(symbol string) map[string]float64 {
+	r.historyMu.Lock()
+	rsi := 50.0
+	if history := r.priceHistory[symbol]; history != nil {
+		rsi = history.rsi()
+	}
+	r.historyMu.Unlock()
+
+	return map[string]float64{
+		"rsi":        rsi,
+		"oversold":   r.oversold,
+		"overbought": r.overbought,
+	}
 }
 
-// GridPosition represents a position in the grid
-type GridPosition struct {
-	Price    float64
-	Quantity float64
-	Active   bool
+// Grid modes
+const (
+	GridModeLong    = "long"    // Buy at levels below the base price, sell one grid above entry
+	GridModeNeutral = "neutral" // Keep resting buy and sell limit orders on every level
+)
+
+// GridStrategy implements a grid trading strategy. Grid levels and filled legs are
+// persisted in grid_states when a repository is set, so the grid survives restarts.
+type GridStrategy struct {
+	name            string
+	gridSize        float64
+	numGrids        int
+	orderValue      float64
+	mode            string
+	levels          map[string][]*models.GridState
+	repository      database.Repository
+	minConfidence   float64
+	stopWorkingType string
+	mu              sync.Mutex
 }
 
 // NewGridStrategy creates a new grid strategy
 func NewGridStrategy() Strategy {
 	return &GridStrategy{
-		name:          "Grid Strategy",
-		gridSize:      0.01, // 1% grid size
-		numGrids:      10,
-		positions:     make(map[string][]GridPosition),
-		minConfidence: 0.8,
+		name:            "Grid Strategy",
+		gridSize:        0.01, // 1% grid size
+		numGrids:        10,
+		orderValue:      100, // $100 per grid level
+		mode:            GridModeLong,
+		levels:          make(map[string][]*models.GridState),
+		minConfidence:   0.8,
+		stopWorkingType: WorkingTypeContractPrice,
 	}
 }
 
@@ -424,46 +493,98 @@ func (g *GridStrategy) Name() string {
 	return g.name
 }
 
+// SetRepository enables persistence of grid state
+func (g *GridStrategy) SetRepository(repository database.Repository) {
+	g.repository = repository
+}
+
+// Neutral reports whether the grid runs on resting orders instead of signals
+func (g *GridStrategy) Neutral() bool {
+	return g.mode == GridModeNeutral
+}
+
+// OrderValue returns the quote value of each grid order
+func (g *GridStrategy) OrderValue() float64 {
+	return g.orderValue
+}
+
 // Initialize initializes the grid strategy
 func (g *GridStrategy) Initialize(config map[string]interface{}) error {
-	if val, ok := config["grid_size"]; ok {
-		if size, ok := val.(float64); ok {
-			g.gridSize = size
-		}
+	if size, ok := getFloatParam(config, "grid_size"); ok {
+		g.gridSize = size
 	}
-	
-	if val, ok := config["num_grids"]; ok {
-		if num, ok := val.(float64); ok {
-			g.numGrids = int(num)
-		}
+
+	if num, ok := getFloatParam(config, "num_grids"); ok {
+		g.numGrids = int(num)
 	}
-	
-	if val, ok := config["min_confidence"]; ok {
-		if conf, ok := val.(float64); ok {
-			g.minConfidence = conf
-		}
+
+	if value, ok := getFloatParam(config, "order_usdt"); ok {
+		g.orderValue = value
 	}
-	
+
+	if mode, ok := getStringParam(config, "mode"); ok && mode != "" {
+		g.mode = mode
+	}
+
+	if conf, ok := getFloatParam(config, "min_confidence"); ok {
+		g.minConfidence = conf
+	}
+
+	if workingType, ok := getStringParam(config, "stop_working_type"); ok && workingType != "" {
+		g.stopWorkingType = workingType
+	}
+
+	if g.mode != GridModeLong && g.mode != GridModeNeutral {
+		return fmt.Errorf("invalid grid mode: %s", g.mode)
+	}
+
+	if g.gridSize <= 0 || g.numGrids < 2 || g.orderValue <= 0 {
+		return fmt.Errorf("grid size, order value and at least 2 grids are required")
+	}
+
+	// The lowest level must stay above zero
+	if float64(g.numGrids/2)*g.gridSize >= 1 {
+		return fmt.Errorf("grid of %d levels at %.4f spacing reaches zero price", g.numGrids, g.gridSize)
+	}
+
 	return nil
 }
 
 // ShouldBuy determines if we should buy based on grid strategy
 func (g *GridStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
-	if g.basePrice == 0 {
-		g.basePrice = data.Price
-		g.initializeGrid(symbol, data.Price)
+	if g.Neutral() {
+		return &Signal{Action: "HOLD", Reason: "Neutral grid runs on resting orders"}, nil
 	}
-	
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	levels, err := g.levelsLocked(symbol, data.Price)
+	if err != nil {
+		return nil, err
+	}
+
 	// Find the appropriate grid level
-	gridLevel := g.findGridLevel(data.Price)
-	if gridLevel < 0 || gridLevel >= len(g.positions[symbol]) {
+	gridLevel := g.findGridLevel(levels[0].BasePrice, data.Price)
+	if gridLevel < 0 || gridLevel >= len(levels) {
 		return &Signal{Action: "HOLD", Reason: "Price outside grid range"}, nil
 	}
-	
-	// Buy at support levels (lower grid levels)
-	if data.Price <= g.positions[symbol][gridLevel].Price && !g.positions[symbol][gridLevel].Active {
-		quantity := g.calculateGridQuantity(data.Price)
-		
+
+	// Buy at support levels (lower grid levels); the leg is recorded when signaled
+	level := levels[gridLevel]
+	if data.Price <= level.Price && !level.Active {
+		quantity := g.orderValue / data.Price
+
+		now := time.Now()
+		level.Active = true
+		level.Quantity = quantity
+		level.FilledLegs++
+		level.LastFillPrice = data.Price
+		level.LastFillTime = &now
+		if err := g.saveLevel(level); err != nil {
+			return nil, err
+		}
+
 		return &Signal{
 			Action:       "BUY",
 			Quantity:     quantity,
@@ -473,73 +594,196 @@ func (g *GridStrategy) ShouldBuy(ctx context.Context, symbol string, data *Marke
 			PositionSide: "LONG",
 		}, nil
 	}
-	
+
 	return &Signal{Action: "HOLD", Reason: "No grid buy signal"}, nil
 }
 
 // ShouldSell determines if we should sell based on grid strategy
 func (g *GridStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
-	if g.basePrice == 0 {
-		return &Signal{Action: "HOLD", Reason: "Grid not initialized"}, nil
+	if g.Neutral() {
+		return &Signal{Action: "HOLD", Reason: "Neutral grid runs on resting orders"}, nil
 	}
-	
+
+	var signal *Signal
+
 	// Sell at resistance levels (higher grid levels)
 	profitTarget := position.EntryPrice * (1 + g.gridSize)
-	
+	stopLoss := position.EntryPrice * (1 - g.gridSize*2)
+
 	if data.Price >= profitTarget {
-		return &Signal{
+		signal = &Signal{
 			Action:     "SELL",
 			Quantity:   position.Size,
 			Price:      data.Price,
 			Confidence: g.minConfidence,
 			Reason:     fmt.Sprintf("Grid sell target reached: %.2f", profitTarget),
-		}, nil
-	}
-	
-	// Stop loss
-	stopLoss := position.EntryPrice * (1 - g.gridSize*2)
-	if data.Price <= stopLoss {
-		return &Signal{
+		}
+	} else if data.WorkingPrice(g.stopWorkingType) <= stopLoss {
+		signal = &Signal{
 			Action:     "SELL",
 			Quantity:   position.Size,
 			Price:      data.Price,
 			Confidence: 1.0,
 			Reason:     fmt.Sprintf("Grid stop loss: %.2f", stopLoss),
-		}, nil
+		}
+	} else {
+		return &Signal{Action: "HOLD", Reason: "No grid sell signal"}, nil
 	}
-	
-	return &Signal{Action: "HOLD", Reason: "No grid sell signal"}, nil
+
+	// The exit closes every held leg
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	levels, err := g.levelsLocked(symbol, data.Price)
+	if err != nil {
+		return nil, err
+	}
+	for _, level := range levels {
+		if !level.Active {
+			continue
+		}
+		level.Active = false
+		level.Quantity = 0
+		if err := g.saveLevel(level); err != nil {
+			return nil, err
+		}
+	}
+
+	return signal, nil
 }
 
-// initializeGrid initializes the trading grid
-func (g *GridStrategy) initializeGrid(symbol string, basePrice float64) {
-	g.positions[symbol] = make([]GridPosition, g.numGrids)
-	
+// Levels returns the grid for the symbol, restoring it from the database or building a new
+// one around price
+func (g *GridStrategy) Levels(symbol string, price float64) ([]*models.GridState, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.levelsLocked(symbol, price)
+}
+
+// SaveLevel persists a grid level
+func (g *GridStrategy) SaveLevel(level *models.GridState) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.saveLevel(level)
+}
+
+// levelsLocked returns the grid for the symbol. Callers hold mu.
+func (g *GridStrategy) levelsLocked(symbol string, price float64) ([]*models.GridState, error) {
+	if levels, ok := g.levels[symbol]; ok {
+		return levels, nil
+	}
+
+	if g.repository != nil {
+		levels, err := g.repository.GetGridStates(symbol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load grid state for %s: %w", symbol, err)
+		}
+		if len(levels) == g.numGrids {
+			g.levels[symbol] = levels
+			return levels, nil
+		}
+		// A grid saved with other settings is rebuilt around the current price
+		if len(levels) > 0 {
+			if err := g.repository.DeleteGridStates(symbol); err != nil {
+				return nil, fmt.Errorf("failed to reset grid state for %s: %w", symbol, err)
+			}
+		}
+	}
+
+	levels := g.initializeGrid(symbol, price)
+	for _, level := range levels {
+		if err := g.saveLevel(level); err != nil {
+			return nil, err
+		}
+	}
+	g.levels[symbol] = levels
+
+	return levels, nil
+}
+
+// saveLevel persists a grid level when a repository is set
+func (g *GridStrategy) saveLevel(level *models.GridState) error {
+	if g.repository == nil {
+		return nil
+	}
+
+	if err := g.repository.SaveGridState(level); err != nil {
+		return fmt.Errorf("failed to save grid level %d for %s: %w", level.Level, level.Symbol, err)
+	}
+	return nil
+}
+
+// initializeGrid builds the trading grid around basePrice
+func (g *GridStrategy) initializeGrid(symbol string, basePrice float64) []*models.GridState {
+	levels := make([]*models.GridState, g.numGrids)
+
 	for i := 0; i < g.numGrids; i++ {
 		offset := float64(i-g.numGrids/2) * g.gridSize
-		price := basePrice * (1 + offset)
-		
-		g.positions[symbol][i] = GridPosition{
-			Price:    price,
-			Quantity: 0,
-			Active:   false,
+
+		levels[i] = &models.GridState{
+			Symbol:    symbol,
+			Level:     i,
+			BasePrice: basePrice,
+			Price:     basePrice * (1 + offset),
 		}
 	}
+
+	return levels
 }
 
 // findGridLevel finds the grid level for a given price
-func (g *GridStrategy) findGridLevel(price float64) int {
-	if g.basePrice == 0 {
+func (g *GridStrategy) findGridLevel(basePrice, price float64) int {
+	if basePrice == 0 {
 		return -1
 	}
-	
-	offset := (price - g.basePrice) / g.basePrice / g.gridSize
+
+	offset := (price - basePrice) / basePrice / g.gridSize
 	level := int(offset) + g.numGrids/2
-	
+
 	return level
 }
 
-// calculateGridQuantity calculates quantity for grid trading
-func (g *GridStrategy) calculateGridQuantity(price float64) float64 {
-	return 100 / price // Fixed $100 per grid level
+// getFloatParam reads a numeric strategy parameter, accepting both YAML integers and floats
+func getFloatParam(config map[string]interface{}, key string) (float64, bool) {
+	val, ok := config[key]
+	if !ok {
+		return 0, false
+	}
+
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// getStringParam reads a string strategy parameter
+func getStringParam(config map[string]interface{}, key string) (string, bool) {
+	val, ok := config[key]
+	if !ok {
+		return "", false
+	}
+
+	s, ok := val.(string)
+	return s, ok
+}
+
+// getBoolParam reads a boolean strategy parameter
+func getBoolParam(config map[string]interface{}, key string) (bool, bool) {
+	val, ok := config[key]
+	if !ok {
+		return false, false
+	}
+
+	b, ok := val.(bool)
+	return b, ok
 }
@@ -0,0 +1,242 @@
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/models"
+	"contract_playground/internal/notify"
+
+	"gorm.io/gorm"
+)
+
+// StrategyPause records why the strategy's new entries are paused. Like a symbol pause it
+// leaves exits, stops and child orders of open positions alone.
+type StrategyPause struct {
+	Strategy string         `json:"strategy"`
+	Reason   string         `json:"reason"`
+	Stats    *StrategyStats `json:"stats"`
+	Since    time.Time      `json:"since"`
+}
+
+// StrategyStats summarizes the strategy's closed trades in the guard's rolling window
+type StrategyStats struct {
+	Trades      int     `json:"trades"`
+	WinRate     float64 `json:"win_rate"`
+	Expectancy  float64 `json:"expectancy"` // Mean realized PnL per trade in USDT
+	NetPnL      float64 `json:"net_pnl"`
+	MaxDrawdown float64 `json:"max_drawdown"` // Largest peak-to-trough fall of cumulative PnL
+}
+
+// computeStrategyStats summarizes per-trade PnLs given oldest first
+func computeStrategyStats(pnls []float64) *StrategyStats {
+	stats := &StrategyStats{Trades: len(pnls)}
+	if len(pnls) == 0 {
+		return stats
+	}
+
+	var wins int
+	var peak float64
+	for _, pnl := range pnls {
+		if pnl > 0 {
+			wins++
+		}
+		stats.NetPnL += pnl
+		if stats.NetPnL > peak {
+			peak = stats.NetPnL
+		}
+		if drawdown := peak - stats.NetPnL; drawdown > stats.MaxDrawdown {
+			stats.MaxDrawdown = drawdown
+		}
+	}
+	stats.WinRate = float64(wins) / float64(len(pnls))
+	stats.Expectancy = stats.NetPnL / float64(len(pnls))
+	return stats
+}
+
+// breaches returns the thresholds of the guard the stats violate; windows with fewer than
+// min_trades trades are never judged
+func (s *StrategyStats) breaches(cfg config.StrategyGuardConfig) []string {
+	if s.Trades < cfg.MinTrades {
+		return nil
+	}
+
+	var reasons []string
+	if s.Expectancy < cfg.MinExpectancy {
+		reasons = append(reasons, fmt.Sprintf("expectancy %.2f USDT below %.2f over %d trades", s.Expectancy, cfg.MinExpectancy, s.Trades))
+	}
+	if cfg.MaxDrawdown > 0 && s.MaxDrawdown >= cfg.MaxDrawdown {
+		reasons = append(reasons, fmt.Sprintf("drawdown %.2f USDT reached the %.2f limit", s.MaxDrawdown, cfg.MaxDrawdown))
+	}
+	return reasons
+}
+
+// guardTradePnLs sums fills (newest first) per order and returns the PnL of the last
+// lookback orders filled after since, oldest first
+func guardTradePnLs(fills []*models.Trade, since time.Time, lookback int) []float64 {
+	pnlByOrder := make(map[uint]float64)
+	var orders []uint
+	for _, fill := range fills {
+		if !fill.TradeTime.After(since) {
+			continue
+		}
+		if _, ok := pnlByOrder[fill.OrderID]; !ok {
+			if len(orders) == lookback {
+				continue
+			}
+			orders = append(orders, fill.OrderID)
+		}
+		pnlByOrder[fill.OrderID] += fill.RealizedPnL
+	}
+
+	pnls := make([]float64, len(orders))
+	for i, id := range orders {
+		pnls[len(orders)-1-i] = pnlByOrder[id]
+	}
+	return pnls
+}
+
+// checkStrategyGuard pauses the strategy's entries when its rolling window breaches a
+// threshold. Only trades after the last resume count, so a resumed strategy starts fresh.
+func (e *Engine) checkStrategyGuard(ctx context.Context) error {
+	e.pauseMu.Lock()
+	paused := e.strategyPause != nil
+	since := e.strategyResumedAt
+	e.pauseMu.Unlock()
+	if paused {
+		return nil
+	}
+
+	cfg := e.config.StrategyGuard.For(e.config.Strategy.Type)
+	name := e.strategy.Name()
+	fills, err := e.repository.GetRealizedTrades(name, cfg.Lookback*4)
+	if err != nil {
+		return fmt.Errorf("failed to get realized trades: %w", err)
+	}
+	stats := computeStrategyStats(guardTradePnLs(fills, since, cfg.Lookback))
+	reasons := stats.breaches(cfg)
+	if len(reasons) == 0 {
+		e.logger.Debugf("Strategy guard: %d trades, expectancy %.2f, max drawdown %.2f", stats.Trades, stats.Expectancy, stats.MaxDrawdown)
+		return nil
+	}
+
+	pause := &StrategyPause{Strategy: name, Reason: strings.Join(reasons, "; "), Stats: stats, Since: time.Now()}
+	e.pauseMu.Lock()
+	e.strategyPause = pause
+	e.pauseMu.Unlock()
+
+	e.logger.Warnf("Paused entries for strategy %s: %s", name, pause.Reason)
+	e.saveStrategyPause(pause, nil)
+	e.recordAudit("strategy", "auto_pause", "", pause)
+
+	msg := &notify.Message{
+		Title: fmt.Sprintf("Strategy %s entries paused", name),
+		Body: fmt.Sprintf("%s.\nLast %d trades: expectancy %.2f USDT, win rate %.0f%%, net %.2f USDT, max drawdown %.2f USDT.\n"+
+			"Open positions are still managed; resume the strategy once it has been reviewed.",
+			pause.Reason, stats.Trades, stats.Expectancy, stats.WinRate*100, stats.NetPnL, stats.MaxDrawdown),
+		Level: notify.LevelCritical,
+		Time:  pause.Since,
+	}
+	if err := e.notifier.Notify(ctx, msg); err != nil {
+		e.logger.Errorf("Failed to deliver strategy pause notification: %v", err)
+	}
+	return nil
+}
+
+// ResumeStrategy allows new entries after a guard pause. The guard's window restarts, so
+// the trades that triggered the pause cannot pause the strategy again.
+func (e *Engine) ResumeStrategy() error {
+	now := time.Now()
+	e.pauseMu.Lock()
+	pause := e.strategyPause
+	if pause != nil {
+		e.strategyPause = nil
+		e.strategyResumedAt = now
+	}
+	e.pauseMu.Unlock()
+
+	if pause == nil {
+		return fmt.Errorf("strategy %s is not paused", e.strategy.Name())
+	}
+
+	e.logger.Infof("Resumed entries for strategy %s", pause.Strategy)
+	e.saveStrategyPause(&StrategyPause{Strategy: pause.Strategy}, &now)
+	e.recordAudit("strategy", "resume", "", map[string]string{"strategy": pause.Strategy})
+	return nil
+}
+
+// PausedStrategy returns the strategy's guard pause, or nil when it may enter
+func (e *Engine) PausedStrategy() *StrategyPause {
+	e.pauseMu.Lock()
+	defer e.pauseMu.Unlock()
+	if e.strategyPause == nil {
+		return nil
+	}
+	copied := *e.strategyPause
+	return &copied
+}
+
+// loadStrategyPause restores a guard pause and the last resume so a restart neither lifts
+// the pause nor judges trades from before the resume again
+func (e *Engine) loadStrategyPause() {
+	saved, err := e.repository.GetStrategyPause(e.strategy.Name())
+	if err == gorm.ErrRecordNotFound {
+		return
+	}
+	if err != nil {
+		e.logger.Warnf("Failed to load strategy pause: %v", err)
+		return
+	}
+
+	e.pauseMu.Lock()
+	defer e.pauseMu.Unlock()
+	if saved.ResumedAt != nil {
+		e.strategyResumedAt = *saved.ResumedAt
+	}
+	if !saved.Paused {
+		return
+	}
+	pause := &StrategyPause{Strategy: saved.Strategy, Reason: saved.Reason}
+	if saved.PausedAt != nil {
+		pause.Since = *saved.PausedAt
+	}
+	if saved.Stats != "" {
+		if err := json.Unmarshal([]byte(saved.Stats), &pause.Stats); err != nil {
+			e.logger.Warnf("Failed to decode strategy pause stats: %v", err)
+		}
+	}
+	e.strategyPause = pause
+	e.logger.Warnf("Entries for strategy %s remain paused since %s: %s", pause.Strategy, pause.Since.Format(time.RFC3339), pause.Reason)
+}
+
+// saveStrategyPause persists a pause, or a resume when resumedAt is set; failures only lose
+// the pause across restarts
+func (e *Engine) saveStrategyPause(pause *StrategyPause, resumedAt *time.Time) {
+	row := &models.StrategyPause{Strategy: pause.Strategy, ResumedAt: resumedAt, UpdatedAt: time.Now()}
+	if resumedAt == nil {
+		since := pause.Since
+		row.Paused = true
+		row.Reason = pause.Reason
+		row.PausedAt = &since
+		stats, err := json.Marshal(pause.Stats)
+		if err != nil {
+			e.logger.Errorf("Failed to encode strategy pause stats: %v", err)
+		}
+		row.Stats = string(stats)
+
+		// Keep the last resume, which bounds the window after a restart
+		e.pauseMu.Lock()
+		if !e.strategyResumedAt.IsZero() {
+			resumed := e.strategyResumedAt
+			row.ResumedAt = &resumed
+		}
+		e.pauseMu.Unlock()
+	}
+	if err := e.repository.SaveStrategyPause(row); err != nil {
+		e.logger.Errorf("Failed to save strategy pause for %s: %v", pause.Strategy, err)
+	}
+}
@@ -0,0 +1,74 @@
+package trading
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/models"
+)
+
+func TestComputeStrategyStats(t *testing.T) {
+	stats := computeStrategyStats([]float64{10, -5, 20, -15, -10, 5})
+	if stats.Trades != 6 {
+		t.Fatalf("trades = %d, want 6", stats.Trades)
+	}
+	if math.Abs(stats.WinRate-0.5) > 1e-9 {
+		t.Errorf("win rate = %v, want 0.5", stats.WinRate)
+	}
+	if math.Abs(stats.NetPnL-5) > 1e-9 || math.Abs(stats.Expectancy-5.0/6) > 1e-9 {
+		t.Errorf("net = %v, expectancy = %v, want 5 and %v", stats.NetPnL, stats.Expectancy, 5.0/6)
+	}
+	// Cumulative 10, 5, 25, 10, 0, 5: the peak of 25 falls to 0
+	if math.Abs(stats.MaxDrawdown-25) > 1e-9 {
+		t.Errorf("max drawdown = %v, want 25", stats.MaxDrawdown)
+	}
+}
+
+func TestStrategyStatsBreaches(t *testing.T) {
+	cfg := config.StrategyGuardConfig{MinTrades: 5, MinExpectancy: 0, MaxDrawdown: 20}
+
+	few := computeStrategyStats([]float64{-50, -50})
+	if reasons := few.breaches(cfg); len(reasons) != 0 {
+		t.Errorf("judged a window below min_trades: %v", reasons)
+	}
+	healthy := computeStrategyStats([]float64{10, -5, 10, -5, 10})
+	if reasons := healthy.breaches(cfg); len(reasons) != 0 {
+		t.Errorf("healthy window breached: %v", reasons)
+	}
+	losing := computeStrategyStats([]float64{10, -15, -10, 5, -5})
+	if reasons := losing.breaches(cfg); len(reasons) != 2 {
+		t.Errorf("breaches = %v, want expectancy and drawdown", reasons)
+	}
+}
+
+func TestGuardTradePnLs(t *testing.T) {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	fill := func(order uint, minute int, pnl float64) *models.Trade {
+		return &models.Trade{OrderID: order, TradeTime: base.Add(time.Duration(minute) * time.Minute), RealizedPnL: pnl}
+	}
+	// Newest first, as the repository returns them; order 3 filled in two parts
+	fills := []*models.Trade{
+		fill(4, 40, -4),
+		fill(3, 31, 2),
+		fill(3, 30, 1),
+		fill(2, 20, -2),
+		fill(1, 10, 1),
+	}
+
+	got := guardTradePnLs(fills, base.Add(15*time.Minute), 10)
+	want := []float64{-2, 3, -4}
+	if len(got) != len(want) {
+		t.Fatalf("pnls = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("pnls = %v, want %v", got, want)
+		}
+	}
+
+	if got := guardTradePnLs(fills, time.Time{}, 2); len(got) != 2 || got[0] != 3 || got[1] != -4 {
+		t.Errorf("lookback 2 = %v, want [3 -4]", got)
+	}
+}
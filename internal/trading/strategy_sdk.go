@@ -0,0 +1,103 @@
+package trading
+
+import (
+	"fmt"
+
+	"contract_playground/internal/models"
+	"contract_playground/pkg/strategysdk"
+)
+
+// Parameters consumed by the external and plugin strategy loaders; the rest are passed on
+var sdkLoaderParams = map[string]bool{"command": true, "args": true, "dir": true, "path": true, "name": true}
+
+// sdkParameters returns the parameters meant for the strategy itself
+func sdkParameters(config map[string]interface{}) map[string]interface{} {
+	params := make(map[string]interface{}, len(config))
+	for key, value := range config {
+		if !sdkLoaderParams[key] {
+			params[key] = value
+		}
+	}
+	return params
+}
+
+func toSDKMarketData(data *MarketData) *strategysdk.MarketData {
+	klines := make([]strategysdk.Kline, len(data.Klines))
+	for i, kline := range data.Klines {
+		klines[i] = strategysdk.Kline{
+			OpenTime:  kline.OpenTime,
+			Open:      kline.Open,
+			High:      kline.High,
+			Low:       kline.Low,
+			Close:     kline.Close,
+			Volume:    kline.Volume,
+			CloseTime: kline.CloseTime,
+		}
+	}
+
+	return &strategysdk.MarketData{
+		Symbol:            data.Symbol,
+		Price:             data.Price,
+		MarkPrice:         data.MarkPrice,
+		Volume:            data.Volume,
+		ChangePercent:     data.ChangePercent,
+		High24h:           data.High24h,
+		Low24h:            data.Low24h,
+		QuoteVolume24h:    data.QuoteVolume24h,
+		FundingRate:       data.FundingRate,
+		Timestamp:         data.Timestamp.UnixMilli(),
+		Klines:            klines,
+		ATR:               data.ATR,
+		Sentiment:         data.Sentiment,
+		OpenInterest:      data.OpenInterest,
+		LongShortRatio:    data.LongShortRatio,
+		ImpliedVolatility: data.ImpliedVolatility,
+		VolumeDelta:       data.VolumeDelta,
+		LongLiquidations:  data.LongLiquidations,
+		ShortLiquidations: data.ShortLiquidations,
+	}
+}
+
+func toSDKPosition(position *models.Position) *strategysdk.Position {
+	return &strategysdk.Position{
+		Symbol:        position.Symbol,
+		PositionSide:  position.PositionSide,
+		Size:          position.Size,
+		EntryPrice:    position.EntryPrice,
+		MarkPrice:     position.MarkPrice,
+		UnrealizedPnL: position.UnrealizedPnL,
+		Leverage:      position.Leverage,
+		OpenTime:      position.OpenTime.UnixMilli(),
+	}
+}
+
+// fromSDKSignal validates an external strategy's signal; no signal means HOLD
+func fromSDKSignal(signal *strategysdk.Signal) (*Signal, error) {
+	if signal == nil {
+		return holdSignal("no signal"), nil
+	}
+	switch signal.Action {
+	case strategysdk.ActionBuy, strategysdk.ActionSell, strategysdk.ActionHold:
+	default:
+		return nil, fmt.Errorf("invalid signal action %q", signal.Action)
+	}
+	if signal.Confidence < 0 || signal.Confidence > 1 {
+		return nil, fmt.Errorf("signal confidence %v is outside [0, 1]", signal.Confidence)
+	}
+	switch signal.PositionSide {
+	case "", "LONG", "SHORT":
+	default:
+		return nil, fmt.Errorf("invalid signal position side %q", signal.PositionSide)
+	}
+
+	return &Signal{
+		Action:              signal.Action,
+		Price:               signal.Price,
+		StopLoss:            signal.StopLoss,
+		TakeProfit:          signal.TakeProfit,
+		Confidence:          signal.Confidence,
+		Reason:              signal.Reason,
+		PositionSide:        signal.PositionSide,
+		TrailingStopPercent: signal.TrailingStopPercent,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package trading
+
+import (
+	"context"
+	"time"
+
+	"contract_playground/internal/auditlog"
+	"contract_playground/internal/exchange"
+)
+
+// A streamed order book older than this is not trusted; the depth stream may be re-seeding
+const orderBookMaxAge = 5 * time.Second
+
+// streamedBook is the latest local order book of a symbol and when it arrived
+type streamedBook struct {
+	book     *exchange.OrderBook
+	received time.Time
+}
+
+// depthHandler keeps the latest local order book of each symbol
+type depthHandler struct {
+	engine *Engine
+}
+
+// OnDepthUpdate stores the book
+func (h *depthHandler) OnDepthUpdate(symbol string, book *exchange.OrderBook) {
+	h.engine.orderBooksMu.Lock()
+	h.engine.orderBooks[symbol] = &streamedBook{book: book, received: time.Now()}
+	h.engine.orderBooksMu.Unlock()
+}
+
+// OnError logs stream errors; the stream reconnects and re-seeds on its own
+func (h *depthHandler) OnError(err error) {
+	h.engine.logger.Warnf("Depth stream error: %v", err)
+}
+
+// orderBook returns the top depth levels of the symbol's book: the streamed book while it is
+// fresh and deep enough, a REST snapshot otherwise
+func (e *Engine) orderBook(ctx context.Context, symbol string, depth int) (*exchange.OrderBook, error) {
+	e.orderBooksMu.RLock()
+	streamed := e.orderBooks[symbol]
+	e.orderBooksMu.RUnlock()
+
+	if streamed != nil && time.Since(streamed.received) < orderBookMaxAge &&
+		len(streamed.book.Bids) >= depth && len(streamed.book.Asks) >= depth {
+		book := *streamed.book
+		book.Bids = book.Bids[:depth]
+		book.Asks = book.Asks[:depth]
+		return &book, nil
+	}
+	return e.exchangeClient.GetOrderBook(ctx, symbol, depth)
+}
+
+// userDataHandler reconciles the engine with the exchange whenever the user data stream
+// (re)connects. Fills are applied by the trading loop, which polls the orders it placed;
+// the stream's snapshot catches changes missed while it was disconnected.
+type userDataHandler struct {
+	engine *Engine
+	ctx    context.Context
+}
+
+// OnAccountUpdate is not used; balances are read when needed
+func (h *userDataHandler) OnAccountUpdate(account *exchange.AccountInfo) {}
+
+// OnOrderUpdate logs the update; the trading loop applies fills under the symbol lock
+func (h *userDataHandler) OnOrderUpdate(order *exchange.OrderInfo) {
+	h.engine.logger.Debugf("Order %d for %s is %s: executed %.6f", order.OrderID, order.Symbol, order.Status, order.ExecutedQty)
+}
+
+// OnPositionUpdate is not used; the position sync corrects DB positions
+func (h *userDataHandler) OnPositionUpdate(position *exchange.PositionInfo) {}
+
+// OnTradeUpdate is not used; fills are recorded with the order that carries them
+func (h *userDataHandler) OnTradeUpdate(trade *exchange.TradeInfo) {}
+
+// OnResync hands the snapshot's positions to the position sync and reconciles open orders
+func (h *userDataHandler) OnResync(snapshot *exchange.UserDataSnapshot) {
+	e := h.engine
+	ctx := auditlog.WithSource(h.ctx, auditlog.TriggerSystem, "user_data_resync")
+
+	// A newer snapshot replaces one the position sync has not applied yet
+	select {
+	case <-e.positionSnapshots:
+	default:
+	}
+	e.positionSnapshots <- snapshot.Positions
+
+	report := &RecoveryReport{}
+	if err := e.reconcileOpenOrders(ctx, snapshot.OpenOrders, report); err != nil {
+		e.logger.Errorf("Failed to reconcile open orders after user data re-sync: %v", err)
+		return
+	}
+	if len(report.UpdatedOrders) == 0 && len(report.UnknownOrders) == 0 {
+		return
+	}
+	e.logger.Warnf("User data re-sync: %d orders updated, %d unknown orders", len(report.UpdatedOrders), len(report.UnknownOrders))
+	e.recordAudit("recovery", "resync", "", report)
+}
+
+// OnError logs stream errors; the stream reconnects and re-syncs on its own
+func (h *userDataHandler) OnError(err error) {
+	h.engine.logger.Warnf("User data stream error: %v", err)
+}
@@ -0,0 +1,389 @@
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"contract_playground/internal/database"
+	"contract_playground/internal/events"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/pkg/utils"
+)
+
+// TakeProfitLevel closes part of a position once price moves Percent from entry
+type TakeProfitLevel struct {
+	Percent  float64 // Price move from entry, in percent
+	Fraction float64 // Share of the initial position size, 0-1
+}
+
+// Child order roles for a position
+const (
+	trailingStopRole = "TRAIL"
+)
+
+// takeProfitPlan returns the ladder for a new position: the signal's own levels, or the
+// configured default ladder when the signal has none
+func (e *Engine) takeProfitPlan(signal *Signal) ([]TakeProfitLevel, float64) {
+	if len(signal.TakeProfitLevels) > 0 || signal.TrailingStopPercent > 0 {
+		return signal.TakeProfitLevels, signal.TrailingStopPercent
+	}
+
+	ladder := e.config.TakeProfitLadder
+	if !ladder.Enabled {
+		return nil, 0
+	}
+
+	levels := make([]TakeProfitLevel, 0, len(ladder.Levels))
+	for _, level := range ladder.Levels {
+		levels = append(levels, TakeProfitLevel{Percent: level.Percent, Fraction: level.Fraction})
+	}
+	return levels, ladder.TrailingCallbackPercent
+}
+
+// takeProfitLadder is the ladder a position was opened with. It is stored on the position,
+// so the orders can be rebuilt for a new size or entry without the entry signal and without
+// re-placing levels that already filled.
+type takeProfitLadder struct {
+	Levels   []takeProfitStep `json:"levels"`
+	Trailing float64          `json:"trailing_percent"`
+}
+
+// takeProfitStep is a ladder level and how much of it has filled
+type takeProfitStep struct {
+	Percent  float64 `json:"percent"`
+	Fraction float64 `json:"fraction"`
+	Filled   float64 `json:"filled"` // Quantity closed at this level so far
+	Done     bool    `json:"done"`   // The level's order filled completely
+}
+
+// newTakeProfitLadder returns the ladder for a position opened on the signal
+func (e *Engine) newTakeProfitLadder(signal *Signal) *takeProfitLadder {
+	levels, trailing := e.takeProfitPlan(signal)
+	ladder := &takeProfitLadder{Trailing: trailing}
+	for _, level := range levels {
+		ladder.Levels = append(ladder.Levels, takeProfitStep{Percent: level.Percent, Fraction: level.Fraction})
+	}
+	return ladder
+}
+
+// positionLadder returns the position's stored ladder; positions saved before ladders were
+// stored use the configured default ladder
+func (e *Engine) positionLadder(position *models.Position) *takeProfitLadder {
+	if position.TakeProfitPlan != "" {
+		var ladder takeProfitLadder
+		if err := json.Unmarshal([]byte(position.TakeProfitPlan), &ladder); err == nil {
+			return &ladder
+		}
+		e.logger.Warnf("Invalid take-profit ladder on position %d, using the default ladder", position.ID)
+	}
+	return e.newTakeProfitLadder(&Signal{})
+}
+
+func (l *takeProfitLadder) encode() string {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// recordFill counts a fill of a TP<n> child order against its level
+func (l *takeProfitLadder) recordFill(role string, quantity float64, done bool) bool {
+	level, err := strconv.Atoi(strings.TrimPrefix(role, "TP"))
+	if !strings.HasPrefix(role, "TP") || err != nil || level < 1 || level > len(l.Levels) {
+		return false
+	}
+	l.Levels[level-1].Filled += quantity
+	l.Levels[level-1].Done = l.Levels[level-1].Done || done
+	return true
+}
+
+// placeTakeProfitOrders places reduce-only limit orders for each unfilled level of the
+// position's ladder and a trailing stop for the runner, and records them as child orders of
+// the position. Levels are sized on the position's size before any level closed part of it.
+func (e *Engine) placeTakeProfitOrders(ctx context.Context, position *models.Position) {
+	ladder := e.positionLadder(position)
+	if len(ladder.Levels) == 0 && ladder.Trailing <= 0 {
+		return
+	}
+
+	info, err := e.exchangeClient.GetSymbolInfo(ctx, position.Symbol)
+	if err != nil {
+		e.logger.Errorf("Failed to get symbol info for %s take-profit orders: %v", position.Symbol, err)
+		return
+	}
+
+	base := position.Size
+	for _, level := range ladder.Levels {
+		base += level.Filled
+	}
+
+	remaining := position.Size
+	lastPrice := 0.0
+	for i, level := range ladder.Levels {
+		price := utils.NormalizePrice(position.EntryPrice*(1+level.Percent/100), info.TickSize)
+		if level.Done {
+			lastPrice = price
+			continue
+		}
+
+		quantity := utils.NormalizeQuantity(base*level.Fraction-level.Filled, info.StepSize)
+		if quantity <= 0 || quantity > remaining {
+			continue
+		}
+
+		role := fmt.Sprintf("TP%d", i+1)
+		if e.placeChildOrder(ctx, position, role, &exchange.OrderRequest{
+			Symbol:           position.Symbol,
+			Side:             "SELL",
+			Type:             "LIMIT",
+			Quantity:         quantity,
+			Price:            price,
+			TimeInForce:      "GTC",
+			ReduceOnly:       true,
+			PositionSide:     "BOTH",
+			NewClientOrderID: fmt.Sprintf("tp_%d_%d", position.ID, i+1),
+		}) {
+			remaining -= quantity
+			lastPrice = price
+		}
+	}
+
+	runner := utils.NormalizeQuantity(remaining, info.StepSize)
+	if ladder.Trailing <= 0 || runner <= 0 {
+		return
+	}
+
+	// The trailing stop activates once the last ladder level is reached
+	e.placeChildOrder(ctx, position, trailingStopRole, &exchange.OrderRequest{
+		Symbol:           position.Symbol,
+		Side:             "SELL",
+		Type:             "TRAILING_STOP_MARKET",
+		Quantity:         runner,
+		ActivationPrice:  lastPrice,
+		CallbackRate:     ladder.Trailing,
+		ReduceOnly:       true,
+		PositionSide:     "BOTH",
+		WorkingType:      e.config.StopWorkingType,
+		NewClientOrderID: fmt.Sprintf("trail_%d", position.ID),
+	})
+}
+
+// placeChildOrder sends an order for the position and stores it with its role
+func (e *Engine) placeChildOrder(ctx context.Context, position *models.Position, role string, request *exchange.OrderRequest) bool {
+	response, err := e.placeOrder(ctx, request)
+	if err != nil {
+		e.logger.Errorf("Failed to place %s order for %s: %v", role, position.Symbol, err)
+		return false
+	}
+
+	positionID := position.ID
+	order := &models.Order{
+		ExchangeOrderID: fmt.Sprintf("%d", response.OrderID),
+		ClientOrderID:   response.ClientOrderID,
+		Symbol:          response.Symbol,
+		Side:            response.Side,
+		Type:            response.Type,
+		Status:          response.Status,
+		Quantity:        response.OrigQty,
+		Price:           response.Price,
+		StopPrice:       response.StopPrice,
+		ExecutedQty:     response.ExecutedQty,
+		CumulativeQuote: response.CumQuote,
+		TimeInForce:     response.TimeInForce,
+		ReduceOnly:      request.ReduceOnly,
+		PositionSide:    response.PositionSide,
+		PositionID:      &positionID,
+		Role:            role,
+		Strategy:        e.strategy.Name(),
+	}
+	if err := e.repository.CreateOrder(order); err != nil {
+		e.logger.Errorf("Failed to save %s order for %s: %v", role, position.Symbol, err)
+	}
+
+	e.logger.Infof("Placed %s order for %s: quantity=%.6f, price=%.6f", role, position.Symbol, request.Quantity, request.Price)
+	return true
+}
+
+// reconcileChildOrders applies child order fills to the position: take-profit fills reduce
+// it, safety order fills add to it at a volume-weighted average entry. It returns the
+// updated position, or nil when the child orders closed it completely.
+func (e *Engine) reconcileChildOrders(ctx context.Context, position *models.Position) (*models.Position, error) {
+	children, err := e.repository.GetPositionOrders(position.ID)
+	if err != nil {
+		return position, fmt.Errorf("failed to get child orders: %w", err)
+	}
+
+	ladder := e.positionLadder(position)
+	changed := false
+	added := false
+	exitPrice := 0.0
+	var updated []*models.Order
+	for _, child := range children {
+		if isFinalOrderStatus(child.Status) {
+			continue
+		}
+
+		orderID, err := strconv.ParseInt(child.ExchangeOrderID, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		info, err := e.exchangeClient.GetOrder(ctx, child.Symbol, orderID)
+		if err != nil {
+			e.logger.Errorf("Failed to get %s order for %s: %v", child.Role, child.Symbol, err)
+			continue
+		}
+
+		if filled := info.ExecutedQty - child.ExecutedQty; filled > 0 && addsToPosition(child) {
+			size := position.Size + filled
+			position.EntryPrice = e.averageEntry(position.Symbol, position.EntryPrice, position.Size, info.AvgPrice, filled)
+			position.Size = size
+			position.AddOns++
+			position.LastAddPrice = info.AvgPrice
+			changed = true
+			added = true
+			e.logger.Infof("%s order for %s filled %.6f at %.6f: size=%.6f, avg entry=%.6f",
+				child.Role, child.Symbol, filled, info.AvgPrice, position.Size, position.EntryPrice)
+		} else if filled > 0 {
+			pnl := e.positionPnL(position.Symbol, position.EntryPrice, info.AvgPrice, filled)
+			position.Size -= filled
+			position.ClosedPnL += pnl
+			exitPrice = info.AvgPrice
+			changed = true
+			if ladder.recordFill(child.Role, filled, info.Status == "FILLED") {
+				position.TakeProfitPlan = ladder.encode()
+			}
+			e.logger.Infof("%s order for %s filled %.6f at %.6f (pnl %.2f)", child.Role, child.Symbol, filled, info.AvgPrice, pnl)
+		}
+
+		if info.Status != child.Status || info.ExecutedQty != child.ExecutedQty {
+			child.Status = info.Status
+			child.ExecutedQty = info.ExecutedQty
+			child.CumulativeQuote = info.CumQuote
+			updated = append(updated, child)
+		}
+	}
+
+	// Treat dust below the smallest tradable step as closed
+	closed := changed && position.Size < math.Max(e.minQuantity(ctx, position.Symbol), 1e-9)
+
+	// Fills are counted against the executed quantity stored on the child orders, so the
+	// orders and the position are saved together; otherwise a fill could be applied twice
+	// or lost
+	err = e.repository.WithTx(func(tx database.Repository) error {
+		for _, child := range updated {
+			if err := tx.UpdateOrder(child); err != nil {
+				return fmt.Errorf("failed to update %s order: %w", child.Role, err)
+			}
+		}
+		if closed {
+			if err := tx.ClosePosition(position.ID, exitPrice, position.ClosedPnL); err != nil {
+				return fmt.Errorf("failed to close position: %w", err)
+			}
+			return e.enqueueEvent(tx, events.TypePosition, "closed", position.Symbol, map[string]interface{}{
+				"position_id": position.ID,
+				"exit_price":  exitPrice,
+				"pnl":         position.ClosedPnL,
+			})
+		} else if changed {
+			if err := tx.UpdatePosition(position); err != nil {
+				return fmt.Errorf("failed to update position: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return position, err
+	}
+	if !changed {
+		return position, nil
+	}
+
+	if closed {
+		e.cancelChildOrders(ctx, position)
+		netPnL := e.recordClosedPosition(ctx, position, position.ClosedPnL)
+		if e.commentary != nil {
+			go e.annotateClosedPosition(position, &Signal{Action: "SELL", Reason: "take-profit ladder completed"}, exitPrice, netPnL)
+		}
+		return nil, nil
+	}
+
+	// Take-profit orders were sized and priced for the previous entry; levels that filled
+	// stay filled
+	if added {
+		e.cancelTakeProfitOrders(ctx, position)
+		e.placeTakeProfitOrders(ctx, position)
+	}
+
+	e.refreshPnLAlerts()
+	return position, nil
+}
+
+// cancelChildOrders cancels the position's open child orders, e.g. before a full exit
+func (e *Engine) cancelChildOrders(ctx context.Context, position *models.Position) {
+	e.cancelChildOrdersWhere(ctx, position, func(*models.Order) bool { return true })
+}
+
+// cancelTakeProfitOrders cancels the position's open reduce-only child orders, keeping
+// safety orders and manual orders in place
+func (e *Engine) cancelTakeProfitOrders(ctx context.Context, position *models.Position) {
+	e.cancelChildOrdersWhere(ctx, position, func(order *models.Order) bool {
+		return order.ReduceOnly && order.Role != manualSellRole
+	})
+}
+
+// cancelChildOrdersWhere cancels the position's open child orders that match
+func (e *Engine) cancelChildOrdersWhere(ctx context.Context, position *models.Position, match func(*models.Order) bool) {
+	children, err := e.repository.GetPositionOrders(position.ID)
+	if err != nil {
+		e.logger.Errorf("Failed to get child orders for position %d: %v", position.ID, err)
+		return
+	}
+
+	for _, child := range children {
+		if isFinalOrderStatus(child.Status) || !match(child) {
+			continue
+		}
+
+		orderID, err := strconv.ParseInt(child.ExchangeOrderID, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if err := e.cancelOrder(ctx, child.Symbol, orderID); err != nil {
+			e.logger.Warnf("Failed to cancel %s order for %s: %v", child.Role, child.Symbol, err)
+			continue
+		}
+
+		child.Status = "CANCELED"
+		child.UpdatedAt = time.Now()
+		if err := e.repository.UpdateOrder(child); err != nil {
+			e.logger.Errorf("Failed to update %s order for %s: %v", child.Role, child.Symbol, err)
+		}
+	}
+}
+
+// minQuantity returns the minimum order quantity for the symbol, or 0 if unknown
+func (e *Engine) minQuantity(ctx context.Context, symbol string) float64 {
+	info, err := e.exchangeClient.GetSymbolInfo(ctx, symbol)
+	if err != nil {
+		return 0
+	}
+	return info.MinQty
+}
+
+// isFinalOrderStatus reports whether an order can no longer fill
+func isFinalOrderStatus(status string) bool {
+	switch status {
+	case "FILLED", "CANCELED", "EXPIRED", "REJECTED":
+		return true
+	}
+	return false
+}
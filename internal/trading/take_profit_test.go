@@ -0,0 +1,218 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/events"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ladderRepo keeps a position's child orders in memory; other calls panic
+type ladderRepo struct {
+	database.Repository
+	orders   []*models.Order
+	closed   bool
+	closePnL float64
+}
+
+func (r *ladderRepo) WithTx(fn func(tx database.Repository) error) error { return fn(r) }
+func (r *ladderRepo) UpdateOrder(order *models.Order) error              { return nil }
+func (r *ladderRepo) UpdatePosition(position *models.Position) error     { return nil }
+func (r *ladderRepo) UpdatePositionCosts(id uint, commission, fundingFee, netPnL float64) error {
+	return nil
+}
+func (r *ladderRepo) SaveSymbolCooldown(cooldown *models.SymbolCooldown) error { return nil }
+func (r *ladderRepo) GetClosedPositions(start, end time.Time) ([]*models.Position, error) {
+	return nil, nil
+}
+
+func (r *ladderRepo) CreateOrder(order *models.Order) error {
+	r.orders = append(r.orders, order)
+	return nil
+}
+
+func (r *ladderRepo) GetPositionOrders(positionID uint) ([]*models.Order, error) {
+	var orders []*models.Order
+	for _, order := range r.orders {
+		if order.PositionID != nil && *order.PositionID == positionID {
+			orders = append(orders, order)
+		}
+	}
+	return orders, nil
+}
+
+func (r *ladderRepo) ClosePosition(id uint, closePrice float64, closedPnL float64) error {
+	r.closed = true
+	r.closePnL = closedPnL
+	return nil
+}
+
+// ladderClient rests placed orders until the test fills them; other calls panic
+type ladderClient struct {
+	exchange.Client
+	orders   map[int64]*exchange.OrderInfo
+	requests map[int64]*exchange.OrderRequest
+	nextID   int64
+}
+
+func (c *ladderClient) GetSymbolInfo(ctx context.Context, symbol string) (*exchange.SymbolInfo, error) {
+	return &exchange.SymbolInfo{Symbol: symbol, StepSize: 0.001, TickSize: 0.01, MinQty: 0.001}, nil
+}
+
+func (c *ladderClient) PlaceOrder(ctx context.Context, request *exchange.OrderRequest) (*exchange.OrderResponse, error) {
+	c.nextID++
+	c.requests[c.nextID] = request
+	c.orders[c.nextID] = &exchange.OrderInfo{OrderID: c.nextID, Symbol: request.Symbol, Status: "NEW"}
+	return &exchange.OrderResponse{
+		OrderID:       c.nextID,
+		Symbol:        request.Symbol,
+		Status:        "NEW",
+		ClientOrderID: request.NewClientOrderID,
+		Price:         request.Price,
+		OrigQty:       request.Quantity,
+		Type:          request.Type,
+		Side:          request.Side,
+	}, nil
+}
+
+func (c *ladderClient) GetOrder(ctx context.Context, symbol string, orderID int64) (*exchange.OrderInfo, error) {
+	info := *c.orders[orderID]
+	return &info, nil
+}
+
+func (c *ladderClient) CancelOrder(ctx context.Context, symbol string, orderID int64) error {
+	c.orders[orderID].Status = "CANCELED"
+	return nil
+}
+
+func (c *ladderClient) GetIncomeHistory(ctx context.Context, symbol string, incomeType string, startTime, endTime int64, limit int) ([]*exchange.IncomeInfo, error) {
+	return nil, errors.New("no income history")
+}
+
+// fill fills the open order of the role completely at price
+func (c *ladderClient) fill(t *testing.T, repo *ladderRepo, role string, price float64) {
+	t.Helper()
+	for _, order := range repo.orders {
+		if order.Role != role || isFinalOrderStatus(order.Status) {
+			continue
+		}
+		for id, request := range c.requests {
+			if request.NewClientOrderID == order.ClientOrderID && c.orders[id].Status == "NEW" {
+				c.orders[id] = &exchange.OrderInfo{OrderID: id, Symbol: request.Symbol, Status: "FILLED", AvgPrice: price, ExecutedQty: request.Quantity}
+				return
+			}
+		}
+	}
+	t.Fatalf("no open %s order", role)
+}
+
+// openOrders returns the requests of the position's open child orders by role
+func (c *ladderClient) openOrders(repo *ladderRepo) map[string]*exchange.OrderRequest {
+	open := make(map[string]*exchange.OrderRequest)
+	for _, order := range repo.orders {
+		for id, request := range c.requests {
+			if request.NewClientOrderID == order.ClientOrderID && c.orders[id].Status == "NEW" {
+				open[order.Role] = request
+			}
+		}
+	}
+	return open
+}
+
+func TestTakeProfitLadderAccounting(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	repo := &ladderRepo{}
+	client := &ladderClient{orders: make(map[int64]*exchange.OrderInfo), requests: make(map[int64]*exchange.OrderRequest)}
+	rm := NewRiskManager(&RiskConfig{})
+	rm.logger.SetOutput(io.Discard)
+	e := &Engine{
+		config: config.TradingConfig{TakeProfitLadder: config.TakeProfitLadderConfig{
+			Enabled:                 true,
+			Levels:                  []config.TakeProfitLevelConfig{{Percent: 1, Fraction: 0.5}},
+			TrailingCallbackPercent: 1,
+		}},
+		repository:     repo,
+		exchangeClient: client,
+		riskManager:    rm,
+		contracts:      newContractSpecs(),
+		dayLocation:    time.UTC,
+		logger:         logger,
+		events:         events.NewBus(),
+		strategy:       &bandStrategy{},
+	}
+	ctx := context.Background()
+
+	// The signal's ladder replaces the configured one for the life of the position
+	signal := &Signal{
+		TakeProfitLevels:    []TakeProfitLevel{{Percent: 2, Fraction: 0.3}, {Percent: 4, Fraction: 0.3}},
+		TrailingStopPercent: 1.5,
+	}
+	position := &models.Position{ID: 1, Symbol: "BTCUSDT", Size: 1, EntryPrice: 100, Status: "OPEN"}
+	position.TakeProfitPlan = e.newTakeProfitLadder(signal).encode()
+	e.placeTakeProfitOrders(ctx, position)
+
+	open := client.openOrders(repo)
+	if open["TP1"] == nil || open["TP1"].Quantity != 0.3 || open["TP1"].Price != 102 {
+		t.Fatalf("TP1 = %+v, want 0.3 at 102", open["TP1"])
+	}
+	if open[trailingStopRole] == nil || open[trailingStopRole].Quantity != 0.4 || open[trailingStopRole].CallbackRate != 1.5 {
+		t.Fatalf("trailing stop = %+v, want 0.4 with callback 1.5", open[trailingStopRole])
+	}
+
+	// TP1 fills and reduces the position
+	client.fill(t, repo, "TP1", 102)
+	position, err := e.reconcileChildOrders(ctx, position)
+	if err != nil || position == nil {
+		t.Fatalf("reconcile after TP1 = %v, %v", position, err)
+	}
+	if math.Abs(position.Size-0.7) > 1e-9 || math.Abs(position.ClosedPnL-0.6) > 1e-9 {
+		t.Fatalf("after TP1 size = %.6f, pnl = %.6f, want 0.7 and 0.6", position.Size, position.ClosedPnL)
+	}
+
+	// A safety order fill grows the position; only the unfilled level is rebuilt
+	e.placeChildOrder(ctx, position, "SO1", &exchange.OrderRequest{Symbol: "BTCUSDT", Side: "BUY", Type: "LIMIT", Quantity: 0.5, Price: 90, NewClientOrderID: "so_1_1"})
+	client.fill(t, repo, "SO1", 90)
+	position, err = e.reconcileChildOrders(ctx, position)
+	if err != nil || position == nil {
+		t.Fatalf("reconcile after SO1 = %v, %v", position, err)
+	}
+	entry := (0.7*100 + 0.5*90) / 1.2
+	if math.Abs(position.Size-1.2) > 1e-9 || math.Abs(position.EntryPrice-entry) > 1e-9 {
+		t.Fatalf("after SO1 size = %.6f, entry = %.6f, want 1.2 at %.6f", position.Size, position.EntryPrice, entry)
+	}
+
+	open = client.openOrders(repo)
+	if open["TP1"] != nil {
+		t.Errorf("filled TP1 re-placed: %+v", open["TP1"])
+	}
+	// 30% of the 1.5 entered, at 4% above the new entry
+	if open["TP2"] == nil || open["TP2"].Quantity != 0.45 || open["TP2"].Price != 99.67 {
+		t.Fatalf("TP2 = %+v, want 0.45 at 99.67", open["TP2"])
+	}
+	if open[trailingStopRole] == nil || open[trailingStopRole].Quantity != 0.75 || open[trailingStopRole].CallbackRate != 1.5 {
+		t.Fatalf("trailing stop = %+v, want 0.75 with callback 1.5", open[trailingStopRole])
+	}
+
+	// The rest of the ladder fills and closes the position
+	client.fill(t, repo, "TP2", 99.67)
+	client.fill(t, repo, trailingStopRole, 97)
+	position, err = e.reconcileChildOrders(ctx, position)
+	if err != nil || position != nil {
+		t.Fatalf("reconcile after the last fills = %v, %v, want the position closed", position, err)
+	}
+	pnl := 0.6 + 0.45*(99.67-entry) + 0.75*(97-entry)
+	if !repo.closed || math.Abs(repo.closePnL-pnl) > 1e-9 {
+		t.Errorf("closed = %v with pnl %.6f, want %.6f", repo.closed, repo.closePnL, pnl)
+	}
+}
@@ -0,0 +1,259 @@
+//go:build integration
+
+package trading
+
+import (
+	"context"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/internal/exchange"
+	"contract_playground/internal/models"
+	"contract_playground/migrations"
+	"contract_playground/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// The testnet suite runs the full engine against Binance futures testnet and a dedicated
+// MySQL database. It is excluded from normal builds; run it with
+//
+//	TRADER_EXCHANGE_API_KEY=... TRADER_EXCHANGE_SECRET_KEY=... \
+//	TRADER_DATABASE_MYSQL_DSN=... go test -tags integration -v ./internal/trading -run Testnet
+//
+// Configuration comes from the defaults and TRADER_* variables only. INTEGRATION_SYMBOL
+// selects the traded symbol (BTCUSDT by default).
+
+const testnetTimeout = 45 * time.Second
+
+// testnetEngine is a started engine wired to the testnet and the test database
+type testnetEngine struct {
+	*Engine
+	symbol string
+	info   *exchange.SymbolInfo
+}
+
+func newTestnetEngine(t *testing.T, ctx context.Context) *testnetEngine {
+	t.Helper()
+
+	if os.Getenv("TRADER_EXCHANGE_API_KEY") == "" || os.Getenv("TRADER_DATABASE_MYSQL_DSN") == "" {
+		t.Skip("set TRADER_EXCHANGE_API_KEY, TRADER_EXCHANGE_SECRET_KEY and TRADER_DATABASE_MYSQL_DSN to run the testnet suite")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if !cfg.Exchange.Testnet {
+		t.Fatal("refusing to run the integration suite with exchange.testnet disabled")
+	}
+
+	symbol := os.Getenv("INTEGRATION_SYMBOL")
+	if symbol == "" {
+		symbol = "BTCUSDT"
+	}
+	cfg.Trading.Symbols = []string{symbol}
+	cfg.Trading.EnablePaperTrading = false
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	db, err := database.InitMySQL(cfg.Database.MySQL)
+	if err != nil {
+		t.Fatalf("Failed to connect to MySQL: %v", err)
+	}
+	if err := database.ApplyMigrations(db, config.MigrationsConfig{AutoApply: true}, migrations.Files, logger); err != nil {
+		t.Fatalf("Failed to migrate the test database: %v", err)
+	}
+	// Stop closes every open DB position, so the database must not be shared with a bot
+	if open, err := database.NewMySQLRepository(db).GetAllPositions(); err != nil {
+		t.Fatalf("Failed to get positions: %v", err)
+	} else if len(open) > 0 {
+		t.Fatalf("the test database has %d open positions; use a dedicated database", len(open))
+	}
+
+	client, err := exchange.NewClient(cfg.Exchange, logger)
+	if err != nil {
+		t.Fatalf("Failed to create exchange client: %v", err)
+	}
+	positions, err := client.GetPositions(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get testnet positions: %v", err)
+	}
+	for _, position := range positions {
+		if position.Symbol == symbol && position.PositionAmt != 0 {
+			t.Fatalf("the testnet account already holds %.6f %s; close it first", position.PositionAmt, symbol)
+		}
+	}
+	info, err := client.GetSymbolInfo(ctx, symbol)
+	if err != nil {
+		t.Fatalf("Failed to get symbol info: %v", err)
+	}
+
+	engine := NewEngine(&EngineConfig{
+		DB:             db,
+		ExchangeClient: client,
+		Config:         cfg.Trading,
+		Logger:         logger,
+	})
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("Failed to start engine: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := engine.Stop(context.Background()); err != nil {
+			t.Errorf("Failed to stop engine: %v", err)
+		}
+	})
+
+	// Keep the strategy from entering on its own while the test trades manually
+	if err := engine.PauseSymbol(symbol, "integration test"); err != nil {
+		t.Fatalf("Failed to pause %s: %v", symbol, err)
+	}
+
+	return &testnetEngine{Engine: engine, symbol: symbol, info: info}
+}
+
+// minQuantity returns the smallest quantity the exchange accepts at the current price
+func (e *testnetEngine) minQuantity(t *testing.T, ctx context.Context) float64 {
+	t.Helper()
+
+	price, err := e.exchangeClient.GetSymbolPrice(ctx, e.symbol)
+	if err != nil {
+		t.Fatalf("Failed to get price: %v", err)
+	}
+	quantity := math.Max(e.info.MinQty, e.info.MinNotional*1.2/price)
+	return utils.NormalizeQuantity(quantity+e.info.StepSize, e.info.StepSize)
+}
+
+// openPosition waits for the manual buy to be reconciled into an open DB position
+func (e *testnetEngine) openPosition(t *testing.T, ctx context.Context) *models.Position {
+	t.Helper()
+
+	deadline := time.Now().Add(testnetTimeout)
+	for time.Now().Before(deadline) {
+		unlock := e.lockSymbol(e.symbol)
+		e.reconcileManualFills(ctx, e.symbol, nil)
+		unlock()
+
+		position, err := e.repository.GetPosition(e.symbol, "LONG")
+		if err == nil && position.Status == "OPEN" {
+			return position
+		}
+		time.Sleep(time.Second)
+	}
+	t.Fatalf("no open %s position within %s", e.symbol, testnetTimeout)
+	return nil
+}
+
+func TestTestnetOrderLifecycle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	e := newTestnetEngine(t, ctx)
+	t.Cleanup(func() {
+		// Leave the testnet account flat even when an assertion failed midway
+		if _, err := e.CloseSymbol(context.Background(), e.symbol); err != nil {
+			t.Errorf("Failed to close %s: %v", e.symbol, err)
+		}
+	})
+
+	before, err := e.repository.GetLatestRiskMetric()
+	if err != nil {
+		before = &models.RiskMetric{}
+	}
+
+	// Place and fill
+	quantity := e.minQuantity(t, ctx)
+	order, err := e.PlaceManualOrder(ctx, &ManualOrderRequest{
+		Symbol:   e.symbol,
+		Side:     "BUY",
+		Type:     "MARKET",
+		Quantity: quantity,
+	})
+	if err != nil {
+		t.Fatalf("Failed to place order: %v", err)
+	}
+	if order.ExchangeOrderID == "" {
+		t.Fatalf("order %d has no exchange order ID", order.ID)
+	}
+
+	// Reconcile: the DB position follows the fill and matches the exchange
+	position := e.openPosition(t, ctx)
+	if math.Abs(position.Size-quantity) > e.info.StepSize/2 {
+		t.Errorf("position size %.8f, want %.8f", position.Size, quantity)
+	}
+	if position.EntryPrice <= 0 {
+		t.Errorf("position entry price %.8f, want positive", position.EntryPrice)
+	}
+	stored, err := e.repository.GetOrder(order.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload order: %v", err)
+	}
+	if stored.Status != "FILLED" {
+		t.Errorf("order status %s, want FILLED", stored.Status)
+	}
+	if stored.PositionID == nil || *stored.PositionID != position.ID {
+		t.Errorf("order is not linked to position %d", position.ID)
+	}
+
+	if err := e.syncPositions(ctx, make(map[string]float64)); err != nil {
+		t.Fatalf("Failed to sync positions: %v", err)
+	}
+	live, err := e.livePosition(ctx, e.symbol)
+	if err != nil {
+		t.Fatalf("Failed to get exchange position: %v", err)
+	}
+	synced, err := e.repository.GetPosition(e.symbol, "LONG")
+	if err != nil {
+		t.Fatalf("Failed to reload position: %v", err)
+	}
+	if positionDiffers(synced, live) {
+		t.Errorf("DB position %.8f differs from exchange position %+v", synced.Size, live)
+	}
+
+	// Close and verify the trade records
+	result, err := e.ClosePosition(ctx, position.ID, "MARKET", 0)
+	if err != nil {
+		t.Fatalf("Failed to close position: %v", err)
+	}
+	if !result.Closed {
+		t.Fatalf("position %d not closed: %s", position.ID, result.Error)
+	}
+	closed, err := e.repository.GetPosition(e.symbol, "LONG")
+	if err != nil {
+		t.Fatalf("Failed to reload position: %v", err)
+	}
+	if closed.Status != "CLOSED" {
+		t.Errorf("position status %s, want CLOSED", closed.Status)
+	}
+	if live, err := e.livePosition(ctx, e.symbol); err != nil {
+		t.Fatalf("Failed to get exchange position: %v", err)
+	} else if live != nil {
+		t.Errorf("exchange still holds %.8f %s", live.PositionAmt, e.symbol)
+	}
+	trades, err := e.repository.GetTradeHistory(e.symbol, 10)
+	if err != nil {
+		t.Fatalf("Failed to get trades: %v", err)
+	}
+	if len(trades) == 0 || trades[0].Side != "SELL" {
+		t.Errorf("the exit fills of %s were not recorded as trades", e.symbol)
+	}
+
+	// Risk metrics count the round trip
+	if err := e.updateRiskMetrics(ctx); err != nil {
+		t.Fatalf("Failed to update risk metrics: %v", err)
+	}
+	after, err := e.repository.GetLatestRiskMetric()
+	if err != nil {
+		t.Fatalf("Failed to get risk metric: %v", err)
+	}
+	if after.ID == before.ID {
+		t.Error("no new risk metric was saved")
+	}
+	if closedTrades := after.WinningTrades + after.LosingTrades; closedTrades <= before.WinningTrades+before.LosingTrades {
+		t.Errorf("risk metric counts %d closed trades, want more than %d", closedTrades, before.WinningTrades+before.LosingTrades)
+	}
+}
@@ -0,0 +1,99 @@
+package trading
+
+import (
+	"math"
+
+	"contract_playground/pkg/utils"
+)
+
+// VectorizedStrategy is implemented by strategies whose indicators can be precomputed over a
+// whole candle series. Vectorized backtests then decide each candle by index, without
+// per-candle calls that build market data and copy price windows.
+type VectorizedStrategy interface {
+	Vectorize(series *CandleSeries) VectorSignals
+}
+
+// VectorSignals are a strategy's decisions over a precomputed series. Each must match what
+// the strategy's ShouldBuy/ShouldSell would return at the close of candle i.
+type VectorSignals interface {
+	Enter(i int) bool                    // Open a long position at candle i
+	Exit(i int, entryPrice float64) bool // Close the long position opened at entryPrice
+}
+
+// Vectorize precomputes the short and long SMA over the closes
+func (s *SMAStrategy) Vectorize(series *CandleSeries) VectorSignals {
+	return &smaSignals{
+		strategy: s,
+		closes:   series.Close,
+		short:    utils.MovingAverageSeries(series.Close, s.shortPeriod),
+		long:     utils.MovingAverageSeries(series.Close, s.longPeriod),
+	}
+}
+
+type smaSignals struct {
+	strategy    *SMAStrategy
+	closes      []float64
+	short, long []float64
+}
+
+func (v *smaSignals) Enter(i int) bool {
+	if i+1 < v.strategy.longPeriod || v.short[i] <= v.long[i] {
+		return false
+	}
+	confidence := math.Min((v.short[i]-v.long[i])/v.long[i]*10, 1.0)
+	return confidence >= v.strategy.minConfidence
+}
+
+func (v *smaSignals) Exit(i int, entryPrice float64) bool {
+	if i+1 < v.strategy.longPeriod {
+		return false
+	}
+	if v.short[i] < v.long[i] {
+		confidence := math.Min((v.long[i]-v.short[i])/v.long[i]*10, 1.0)
+		if confidence >= v.strategy.minConfidence {
+			return true
+		}
+	}
+	return stopOrTarget(v.closes[i], entryPrice)
+}
+
+// Vectorize precomputes the RSI over the closes
+func (r *RSIStrategy) Vectorize(series *CandleSeries) VectorSignals {
+	return &rsiSignals{
+		strategy: r,
+		closes:   series.Close,
+		rsi:      utils.RSISeries(series.Close, r.period),
+	}
+}
+
+type rsiSignals struct {
+	strategy *RSIStrategy
+	closes   []float64
+	rsi      []float64
+}
+
+func (v *rsiSignals) Enter(i int) bool {
+	rsi := v.rsi[i]
+	if i < v.strategy.period || rsi >= v.strategy.oversold {
+		return false
+	}
+	return (v.strategy.oversold-rsi)/v.strategy.oversold >= v.strategy.minConfidence
+}
+
+func (v *rsiSignals) Exit(i int, entryPrice float64) bool {
+	if i < v.strategy.period {
+		return false
+	}
+	if rsi := v.rsi[i]; rsi > v.strategy.overbought &&
+		(rsi-v.strategy.overbought)/(100-v.strategy.overbought) >= v.strategy.minConfidence {
+		return true
+	}
+	return stopOrTarget(v.closes[i], entryPrice)
+}
+
+// stopOrTarget reports whether the price hit the 2% stop loss or 5% take profit the SMA and
+// RSI strategies apply to long positions
+func stopOrTarget(price, entryPrice float64) bool {
+	pnlPercent := (price - entryPrice) / entryPrice * 100
+	return pnlPercent <= -2.0 || pnlPercent >= 5.0
+}
@@ -0,0 +1,137 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"contract_playground/internal/config"
+	"contract_playground/internal/database"
+	"contract_playground/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// VolTargetStatus is the latest portfolio volatility estimate and the size scale derived from it
+type VolTargetStatus struct {
+	RealizedPercent float64   `json:"realized_percent"` // Annualized; 0 until enough samples exist
+	TargetPercent   float64   `json:"target_percent"`
+	Scale           float64   `json:"scale"`
+	Samples         int       `json:"samples"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// VolTargeter estimates realized portfolio volatility from account equity snapshots and
+// derives the factor new position sizes are multiplied by to reach the target volatility
+type VolTargeter struct {
+	config     config.VolTargetConfig
+	repository database.Repository
+	logger     *logrus.Logger
+
+	mu     sync.RWMutex
+	status *VolTargetStatus
+}
+
+// NewVolTargeter creates a volatility targeter; sizes are unscaled until the first refresh
+func NewVolTargeter(cfg config.VolTargetConfig, repository database.Repository, logger *logrus.Logger) *VolTargeter {
+	return &VolTargeter{
+		config:     cfg,
+		repository: repository,
+		logger:     logger,
+		status:     &VolTargetStatus{TargetPercent: cfg.TargetPercent, Scale: 1},
+	}
+}
+
+// run refreshes the estimate until ctx is cancelled
+func (v *VolTargeter) run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(v.config.RefreshMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		if err := v.Refresh(); err != nil {
+			v.logger.Errorf("Failed to refresh portfolio volatility: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh recomputes realized volatility from the equity at the end of each bucket in the
+// window. Deposits and withdrawals show up as returns, so transfers inflate the estimate
+// until they leave the window.
+func (v *VolTargeter) Refresh() error {
+	bucket := time.Duration(v.config.BucketMinutes) * time.Minute
+	accounts, err := v.repository.GetAccountsSince(time.Now().Add(-time.Duration(v.config.WindowHours) * time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to get account snapshots: %w", err)
+	}
+
+	// Keep the last snapshot of each bucket
+	var equity []float64
+	var lastBucket int64 = -1
+	for _, account := range accounts {
+		if account.TotalMarginBalance <= 0 {
+			continue
+		}
+		b := account.CreatedAt.UnixNano() / int64(bucket)
+		if b == lastBucket {
+			equity[len(equity)-1] = account.TotalMarginBalance
+			continue
+		}
+		equity = append(equity, account.TotalMarginBalance)
+		lastBucket = b
+	}
+
+	returns := make([]float64, 0, len(equity))
+	for i := 1; i < len(equity); i++ {
+		returns = append(returns, math.Log(equity[i]/equity[i-1]))
+	}
+
+	status := &VolTargetStatus{
+		TargetPercent: v.config.TargetPercent,
+		Scale:         1,
+		Samples:       len(returns),
+		UpdatedAt:     time.Now(),
+	}
+	if len(returns) >= v.config.MinSamples {
+		periodsPerYear := float64(365*24*time.Hour) / float64(bucket)
+		status.RealizedPercent = utils.CalculateStandardDeviation(returns) * math.Sqrt(periodsPerYear) * 100
+		if status.RealizedPercent > 0 {
+			status.Scale = math.Max(v.config.MinScale, math.Min(v.config.MaxScale, v.config.TargetPercent/status.RealizedPercent))
+		} else {
+			status.Scale = v.config.MaxScale
+		}
+	}
+
+	v.mu.Lock()
+	previous := v.status.Scale
+	v.status = status
+	v.mu.Unlock()
+
+	if math.Abs(status.Scale-previous) >= 0.05 {
+		v.logger.Infof("Portfolio volatility %.1f%% against target %.1f%%, position sizes scaled by %.2f",
+			status.RealizedPercent, status.TargetPercent, status.Scale)
+	}
+	return nil
+}
+
+// Scale returns the factor applied to new position sizes
+func (v *VolTargeter) Scale() float64 {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.status.Scale
+}
+
+// Status returns the latest estimate
+func (v *VolTargeter) Status() *VolTargetStatus {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	status := *v.status
+	return &status
+}
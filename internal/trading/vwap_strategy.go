@@ -0,0 +1,191 @@
+package trading
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"contract_playground/internal/models"
+	"contract_playground/pkg/utils"
+)
+
+// VWAPReversionStrategy fades downside extensions: it buys when price falls more than N
+// standard deviations below the session VWAP and exits once price reverts towards it
+type VWAPReversionStrategy struct {
+	name            string
+	orderValue      float64 // USDT per entry
+	anchor          string  // Session anchor: day or funding
+	bandStd         float64 // Entry band below VWAP in standard deviations
+	exitStd         float64 // Exit once price is within this many deviations below VWAP (0 = at VWAP)
+	stopStd         float64 // Stop below VWAP in standard deviations (0 = no stop)
+	minSamples      int     // Closed candles required in the session before trading
+	stopWorkingType string
+
+	mu       sync.Mutex
+	sessions map[string]*vwapSession
+}
+
+// vwapSession is the running VWAP of one symbol and the last candle folded into it
+type vwapSession struct {
+	vwap         *utils.VWAP
+	lastOpenTime int64
+}
+
+// NewVWAPReversionStrategy creates a new VWAP reversion strategy
+func NewVWAPReversionStrategy() Strategy {
+	return &VWAPReversionStrategy{
+		name:            "VWAP Reversion Strategy",
+		orderValue:      100,
+		anchor:          utils.VWAPAnchorDay,
+		bandStd:         2.0,
+		stopStd:         3.5,
+		minSamples:      30,
+		stopWorkingType: WorkingTypeContractPrice,
+		sessions:        make(map[string]*vwapSession),
+	}
+}
+
+// Name returns the strategy name
+func (v *VWAPReversionStrategy) Name() string {
+	return v.name
+}
+
+// Initialize initializes the VWAP reversion strategy with parameters
+func (v *VWAPReversionStrategy) Initialize(config map[string]interface{}) error {
+	if val, ok := getFloatParam(config, "order_usdt"); ok {
+		v.orderValue = val
+	}
+	if anchor, ok := getStringParam(config, "anchor"); ok && anchor != "" {
+		v.anchor = anchor
+	}
+	if val, ok := getFloatParam(config, "band_std"); ok {
+		v.bandStd = val
+	}
+	if val, ok := getFloatParam(config, "exit_std"); ok {
+		v.exitStd = val
+	}
+	if val, ok := getFloatParam(config, "stop_std"); ok {
+		v.stopStd = val
+	}
+	if val, ok := getFloatParam(config, "min_samples"); ok {
+		v.minSamples = int(val)
+	}
+	if workingType, ok := getStringParam(config, "stop_working_type"); ok && workingType != "" {
+		v.stopWorkingType = workingType
+	}
+
+	if v.orderValue <= 0 {
+		return fmt.Errorf("order value must be positive")
+	}
+	if v.anchor != utils.VWAPAnchorDay && v.anchor != utils.VWAPAnchorFunding {
+		return fmt.Errorf("invalid VWAP anchor: %s", v.anchor)
+	}
+	if v.bandStd <= 0 {
+		return fmt.Errorf("band standard deviations must be positive")
+	}
+	if v.exitStd < 0 || v.exitStd >= v.bandStd {
+		return fmt.Errorf("exit standard deviations must be between 0 and the entry band")
+	}
+	if v.stopStd != 0 && v.stopStd <= v.bandStd {
+		return fmt.Errorf("stop standard deviations must be beyond the entry band")
+	}
+	if v.minSamples < 1 {
+		return fmt.Errorf("min samples must be positive")
+	}
+
+	return nil
+}
+
+// ShouldBuy buys when price is stretched below the lower VWAP band
+func (v *VWAPReversionStrategy) ShouldBuy(ctx context.Context, symbol string, data *MarketData) (*Signal, error) {
+	vwap, std, samples := v.update(symbol, data)
+	if samples < v.minSamples || std <= 0 {
+		return &Signal{Action: "HOLD", Reason: fmt.Sprintf("VWAP session has %d/%d candles", samples, v.minSamples)}, nil
+	}
+
+	lower := vwap - v.bandStd*std
+	if data.Price >= lower {
+		return &Signal{Action: "HOLD", Reason: fmt.Sprintf("Price %.6f above lower band %.6f (VWAP %.6f)", data.Price, lower, vwap)}, nil
+	}
+
+	// A move already past the stop band is a trend, not an extension
+	if v.stopStd > 0 && data.Price <= vwap-v.stopStd*std {
+		return &Signal{Action: "HOLD", Reason: fmt.Sprintf("Price %.6f beyond stop band", data.Price)}, nil
+	}
+
+	deviation := (vwap - data.Price) / std
+	signal := &Signal{
+		Action:       "BUY",
+		Quantity:     v.orderValue / data.Price,
+		Price:        data.Price,
+		TakeProfit:   vwap - v.exitStd*std,
+		Confidence:   utils.Min(1.0, deviation/v.bandStd/2+0.5),
+		Reason:       fmt.Sprintf("Price %.2f std below VWAP %.6f", deviation, vwap),
+		PositionSide: "LONG",
+	}
+	if v.stopStd > 0 {
+		signal.StopLoss = vwap - v.stopStd*std
+	}
+
+	return signal, nil
+}
+
+// ShouldSell exits when price reverts to the exit band or breaks through the stop band
+func (v *VWAPReversionStrategy) ShouldSell(ctx context.Context, symbol string, data *MarketData, position *models.Position) (*Signal, error) {
+	vwap, std, samples := v.update(symbol, data)
+	if samples == 0 || std <= 0 {
+		return &Signal{Action: "HOLD", Reason: "VWAP not available"}, nil
+	}
+
+	if target := vwap - v.exitStd*std; data.Price >= target {
+		return &Signal{
+			Action:     "SELL",
+			Quantity:   position.Size,
+			Price:      data.Price,
+			Confidence: 1.0,
+			Reason:     fmt.Sprintf("Reverted to VWAP: %.6f >= %.6f", data.Price, target),
+		}, nil
+	}
+
+	if v.stopStd > 0 {
+		if stop := vwap - v.stopStd*std; data.WorkingPrice(v.stopWorkingType) <= stop {
+			return &Signal{
+				Action:     "SELL",
+				Quantity:   position.Size,
+				Price:      data.Price,
+				Confidence: 1.0,
+				Reason:     fmt.Sprintf("VWAP stop band broken: %.6f", stop),
+			}, nil
+		}
+	}
+
+	return &Signal{Action: "HOLD", Reason: fmt.Sprintf("VWAP %.6f std %.6f", vwap, std)}, nil
+}
+
+// update folds closed candles not seen yet into the symbol's session VWAP
+func (v *VWAPReversionStrategy) update(symbol string, data *MarketData) (vwap, std float64, samples int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	session, ok := v.sessions[symbol]
+	if !ok {
+		session = &vwapSession{vwap: utils.NewVWAP(v.anchor)}
+		v.sessions[symbol] = session
+	}
+
+	for _, k := range closedKlines(data.Klines) {
+		if k.OpenTime <= session.lastOpenTime {
+			continue
+		}
+		session.vwap.Update(time.UnixMilli(k.OpenTime), utils.TypicalPrice(k.High, k.Low, k.Close), k.Volume)
+		session.lastOpenTime = k.OpenTime
+	}
+
+	// Don't carry the previous session into a new one before its first candle closes
+	if now := data.Timestamp; !now.IsZero() && utils.VWAPSessionStart(now, v.anchor).After(session.vwap.SessionStart()) {
+		return 0, 0, 0
+	}
+
+	return session.vwap.Value(), session.vwap.StdDev(), session.vwap.Samples()
+}
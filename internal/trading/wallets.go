@@ -0,0 +1,233 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"contract_playground/internal/auditlog"
+	"contract_playground/internal/models"
+)
+
+// ErrWalletsDisabled is returned when trading.wallets is disabled
+var ErrWalletsDisabled = errors.New("strategy wallets are disabled")
+
+// ErrInsufficientWalletFunds is returned when a withdrawal exceeds a wallet's free capital
+var ErrInsufficientWalletFunds = errors.New("insufficient free capital in the wallet")
+
+// WalletStatus is the virtual capital of one strategy within the shared exchange account
+type WalletStatus struct {
+	Strategy      string    `json:"strategy"`
+	Since         time.Time `json:"since"` // First transfer; positions closed before it do not count
+	Deposits      float64   `json:"deposits"`
+	RealizedPnL   float64   `json:"realized_pnl"` // Net of commissions and funding
+	UnrealizedPnL float64   `json:"unrealized_pnl"`
+	Equity        float64   `json:"equity"`
+	MarginUsed    float64   `json:"margin_used"` // Initial margin of open positions at their entry
+	Free          float64   `json:"free"`        // Equity less margin used, available to new entries
+	OpenPositions int       `json:"open_positions"`
+}
+
+// walletStatuses computes every wallet from its transfers and the positions of its strategy.
+// Like the daily figures they are read from the database, so they survive restarts.
+func (e *Engine) walletStatuses() (map[string]*WalletStatus, error) {
+	transfers, err := e.repository.GetWalletTransfers("", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet transfers: %w", err)
+	}
+	wallets := openWallets(transfers)
+	if len(wallets) == 0 {
+		return wallets, nil
+	}
+
+	for _, wallet := range wallets {
+		if wallet.RealizedPnL, err = e.repository.SumClosedNetPnL(wallet.Strategy, wallet.Since); err != nil {
+			return nil, fmt.Errorf("failed to sum realized PnL of %s: %w", wallet.Strategy, err)
+		}
+	}
+	positions, err := e.repository.GetAllPositions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open positions: %w", err)
+	}
+	for _, position := range positions {
+		wallet, ok := wallets[position.Strategy]
+		if !ok {
+			continue
+		}
+		wallet.UnrealizedPnL += position.UnrealizedPnL
+		wallet.MarginUsed += e.contracts.get(position.Symbol).Notional(position.Size, position.EntryPrice) / math.Max(float64(position.Leverage), 1)
+		wallet.OpenPositions++
+	}
+	for _, wallet := range wallets {
+		wallet.settle()
+	}
+	return wallets, nil
+}
+
+// openWallets sums the transfers of every strategy; a wallet opens with its first transfer
+func openWallets(transfers []*models.WalletTransfer) map[string]*WalletStatus {
+	wallets := make(map[string]*WalletStatus)
+	for _, transfer := range transfers {
+		wallet, ok := wallets[transfer.Strategy]
+		if !ok {
+			wallet = &WalletStatus{Strategy: transfer.Strategy, Since: transfer.CreatedAt}
+			wallets[transfer.Strategy] = wallet
+		}
+		wallet.Deposits += transfer.Amount
+		if transfer.CreatedAt.Before(wallet.Since) {
+			wallet.Since = transfer.CreatedAt
+		}
+	}
+	return wallets
+}
+
+// settle derives the equity and free capital from the deposits, PnL and margin
+func (w *WalletStatus) settle() {
+	w.Equity = w.Deposits + w.RealizedPnL + w.UnrealizedPnL
+	w.Free = w.Equity - w.MarginUsed
+}
+
+// walletFilter rejects an entry whose initial margin does not fit the free capital of the
+// strategy's wallet; a strategy without a wallet has no budget
+func (e *Engine) walletFilter(strategy string, order *OrderInfo) string {
+	if !e.config.Wallets.Enabled {
+		return ""
+	}
+	wallets, err := e.walletStatuses()
+	if err != nil {
+		e.logger.Errorf("Failed to load strategy wallets: %v", err)
+		return "strategy wallets unavailable"
+	}
+	wallet, ok := wallets[strategy]
+	if !ok {
+		return fmt.Sprintf("strategy %q has no wallet", strategy)
+	}
+	margin := order.Value() / float64(e.symbolLeverage(order.Symbol))
+	if margin > wallet.Free {
+		return fmt.Sprintf("margin %.2f exceeds the free capital %.2f of the %s wallet", margin, wallet.Free, strategy)
+	}
+	return ""
+}
+
+// seedWallets records the configured opening deposit of every wallet without transfers
+func (e *Engine) seedWallets() error {
+	transfers, err := e.repository.GetWalletTransfers("", 0)
+	if err != nil {
+		return fmt.Errorf("failed to get wallet transfers: %w", err)
+	}
+	funded := make(map[string]bool, len(transfers))
+	for _, transfer := range transfers {
+		funded[transfer.Strategy] = true
+	}
+	for _, allocation := range e.config.Wallets.Allocations {
+		if funded[allocation.Strategy] {
+			continue
+		}
+		transfer := &models.WalletTransfer{Strategy: allocation.Strategy, Amount: allocation.Amount, Note: "opening allocation", Actor: "config"}
+		if err := e.repository.CreateWalletTransfer(transfer); err != nil {
+			return fmt.Errorf("failed to fund the %s wallet: %w", allocation.Strategy, err)
+		}
+		e.logger.Infof("Opened the %s wallet with %.2f", allocation.Strategy, allocation.Amount)
+	}
+	return nil
+}
+
+// snapshotWallets stores a point of every wallet's equity curve and prunes old points
+func (e *Engine) snapshotWallets(ctx context.Context) error {
+	wallets, err := e.walletStatuses()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, wallet := range wallets {
+		snapshot := &models.WalletSnapshot{
+			Strategy:      wallet.Strategy,
+			Time:          now,
+			Deposits:      wallet.Deposits,
+			RealizedPnL:   wallet.RealizedPnL,
+			UnrealizedPnL: wallet.UnrealizedPnL,
+			Equity:        wallet.Equity,
+			MarginUsed:    wallet.MarginUsed,
+		}
+		if err := e.repository.SaveWalletSnapshot(snapshot); err != nil {
+			return fmt.Errorf("failed to save %s wallet snapshot: %w", wallet.Strategy, err)
+		}
+	}
+
+	if days := e.config.Wallets.RetentionDays; days > 0 {
+		if _, err := e.repository.DeleteWalletSnapshotsBefore(now.AddDate(0, 0, -days)); err != nil {
+			return fmt.Errorf("failed to prune wallet snapshots: %w", err)
+		}
+	}
+	return nil
+}
+
+// Wallets returns every strategy wallet, ordered by strategy
+func (e *Engine) Wallets() ([]*WalletStatus, error) {
+	if !e.config.Wallets.Enabled {
+		return nil, ErrWalletsDisabled
+	}
+	wallets, err := e.walletStatuses()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*WalletStatus, 0, len(wallets))
+	for _, wallet := range wallets {
+		list = append(list, wallet)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Strategy < list[j].Strategy })
+	return list, nil
+}
+
+// WalletEquity returns the equity curve of a strategy's wallet since the given time
+func (e *Engine) WalletEquity(strategy string, since time.Time) ([]*models.WalletSnapshot, error) {
+	if !e.config.Wallets.Enabled {
+		return nil, ErrWalletsDisabled
+	}
+	return e.repository.GetWalletSnapshots(strategy, since)
+}
+
+// WalletTransfers returns the newest transfers of a strategy's wallet
+func (e *Engine) WalletTransfers(strategy string, limit int) ([]*models.WalletTransfer, error) {
+	if !e.config.Wallets.Enabled {
+		return nil, ErrWalletsDisabled
+	}
+	return e.repository.GetWalletTransfers(strategy, limit)
+}
+
+// TransferWallet deposits a positive amount into a strategy's wallet, opening it if needed,
+// or withdraws a negative amount from its free capital. Entries are serialized with the
+// transfer so a withdrawal cannot take capital an entry is about to commit.
+func (e *Engine) TransferWallet(ctx context.Context, strategy string, amount float64, note string) (*WalletStatus, error) {
+	if !e.config.Wallets.Enabled {
+		return nil, ErrWalletsDisabled
+	}
+
+	e.entryMu.Lock()
+	defer e.entryMu.Unlock()
+
+	wallets, err := e.walletStatuses()
+	if err != nil {
+		return nil, err
+	}
+	if amount < 0 {
+		wallet, ok := wallets[strategy]
+		if !ok || -amount > wallet.Free {
+			return nil, ErrInsufficientWalletFunds
+		}
+	}
+
+	transfer := &models.WalletTransfer{Strategy: strategy, Amount: amount, Note: note, Actor: auditlog.SourceFrom(ctx).Actor}
+	if err := e.repository.CreateWalletTransfer(transfer); err != nil {
+		return nil, fmt.Errorf("failed to save wallet transfer: %w", err)
+	}
+	e.logger.Infof("Transferred %.2f to the %s wallet (%s)", amount, strategy, transfer.Actor)
+
+	if wallets, err = e.walletStatuses(); err != nil {
+		return nil, err
+	}
+	return wallets[strategy], nil
+}
@@ -0,0 +1,34 @@
+package trading
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"contract_playground/internal/models"
+)
+
+func TestOpenWallets(t *testing.T) {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	// Newest first, as the repository returns them
+	transfers := []*models.WalletTransfer{
+		{Strategy: "RSI", Amount: -200, CreatedAt: base.Add(48 * time.Hour)},
+		{Strategy: "manual", Amount: 300, CreatedAt: base.Add(24 * time.Hour)},
+		{Strategy: "RSI", Amount: 1000, CreatedAt: base},
+	}
+
+	wallets := openWallets(transfers)
+	if len(wallets) != 2 {
+		t.Fatalf("wallets = %v, want RSI and manual", wallets)
+	}
+	rsi := wallets["RSI"]
+	if rsi.Deposits != 800 || !rsi.Since.Equal(base) {
+		t.Errorf("RSI deposits = %v since %v, want 800 since %v", rsi.Deposits, rsi.Since, base)
+	}
+
+	rsi.RealizedPnL, rsi.UnrealizedPnL, rsi.MarginUsed = 150, -50, 400
+	rsi.settle()
+	if math.Abs(rsi.Equity-900) > 1e-9 || math.Abs(rsi.Free-500) > 1e-9 {
+		t.Errorf("equity = %v, free = %v, want 900 and 500", rsi.Equity, rsi.Free)
+	}
+}
@@ -0,0 +1,83 @@
+package trading
+
+import (
+	"context"
+	"time"
+
+	"contract_playground/internal/exchange"
+)
+
+// Candles fetched per refresh without warmup, and the most a single request returns
+const (
+	defaultKlineLimit = 100
+	maxKlineLimit     = 1500
+)
+
+// WarmupStrategy is implemented by strategies that need a number of closed candles before
+// they can decide. Strategies keeping their own price history seed it in Warmup.
+type WarmupStrategy interface {
+	RequiredCandles() int
+	Warmup(symbol string, klines []*exchange.KlineData)
+}
+
+// klineLimit returns the candles fetched per refresh: enough for the strategy's longest
+// period plus the warmup buffer and the forming candle
+func (e *Engine) klineLimit() int {
+	if e.warmupStrategy == nil || !e.config.Warmup.Enabled {
+		return defaultKlineLimit
+	}
+	limit := e.warmupStrategy.RequiredCandles() + e.config.Warmup.BufferCandles + 1
+	if limit < defaultKlineLimit {
+		return defaultKlineLimit
+	}
+	if limit > maxKlineLimit {
+		return maxKlineLimit
+	}
+	return limit
+}
+
+// klineWindow returns the klines kept per symbol: trading.retention.kline_window, but never
+// fewer than are fetched for the strategy
+func (e *Engine) klineWindow() int {
+	if window := e.config.Retention.KlineWindow; window > e.klineLimit() {
+		return window
+	}
+	return e.klineLimit()
+}
+
+// warmup loads market data for every symbol and seeds the strategy with the closed candles
+// before the first trading decision, so indicators are complete right after a restart
+func (e *Engine) warmup(ctx context.Context) {
+	started := time.Now()
+	for _, symbol := range e.config.Symbols {
+		if err := e.updateMarketData(ctx, symbol); err != nil {
+			e.logger.Warnf("Failed to load warmup data for %s: %v", symbol, err)
+			continue
+		}
+		if e.warmupStrategy == nil {
+			continue
+		}
+
+		klines := closedKlines(e.klines.window(symbol))
+
+		if required := e.warmupStrategy.RequiredCandles(); len(klines) < required {
+			e.logger.Warnf("Only %d closed %s candles available for %s, strategy needs %d",
+				len(klines), e.config.KlineInterval, symbol, required)
+		}
+		e.warmupStrategy.Warmup(symbol, klines)
+	}
+	e.logger.Infof("Warmed up %d symbols with %d %s candles in %s",
+		len(e.config.Symbols), e.klineLimit(), e.config.KlineInterval, time.Since(started).Truncate(time.Millisecond))
+}
+
+// closingPrices returns the closes of the last count candles, oldest first
+func closingPrices(klines []*exchange.KlineData, count int) []float64 {
+	if len(klines) > count {
+		klines = klines[len(klines)-count:]
+	}
+	closes := make([]float64, len(klines))
+	for i, kline := range klines {
+		closes[i] = kline.Close
+	}
+	return closes
+}
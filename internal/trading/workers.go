@@ -0,0 +1,72 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// processSymbols runs process for every symbol on at most MaxConcurrentSymbols workers.
+// Each symbol gets its own timeout so one slow exchange call cannot stall the cycle, and
+// the errors of all failed symbols are returned together.
+func (e *Engine) processSymbols(ctx context.Context, symbols []string, process func(context.Context, string) error) error {
+	workers := e.config.MaxConcurrentSymbols
+	if workers <= 0 || workers > len(symbols) {
+		workers = len(symbols)
+	}
+
+	jobs := make(chan string)
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []error
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for symbol := range jobs {
+				if err := e.processSymbol(ctx, symbol, process); err != nil {
+					mu.Lock()
+					failed = append(failed, fmt.Errorf("%s: %w", symbol, err))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, symbol := range symbols {
+		select {
+		case jobs <- symbol:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d symbols failed: %w", len(failed), len(symbols), errors.Join(failed...))
+	}
+	return nil
+}
+
+// processSymbol runs process for one symbol under the per-symbol timeout and records the
+// result for automatic pausing
+func (e *Engine) processSymbol(ctx context.Context, symbol string, process func(context.Context, string) error) error {
+	symbolCtx := ctx
+	if timeout := time.Duration(e.config.SymbolTimeoutSeconds) * time.Second; timeout > 0 {
+		var cancel context.CancelFunc
+		symbolCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := process(symbolCtx, symbol)
+	if err != nil && errors.Is(symbolCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %ds: %w", e.config.SymbolTimeoutSeconds, err)
+	}
+	e.recordSymbolResult(ctx, symbol, err)
+	return err
+}
@@ -0,0 +1,16 @@
+-- 新闻/情绪数据表
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS sentiment_items (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(50) NOT NULL,
+    source VARCHAR(50) NOT NULL,
+    external_id VARCHAR(191) NOT NULL,
+    title TEXT,
+    url TEXT,
+    score DOUBLE NOT NULL,
+    published_at DATETIME(3) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_sentiment_symbol_external (symbol, external_id),
+    INDEX idx_published_at (published_at)
+);
@@ -0,0 +1,16 @@
+-- 持仓量与多空比数据表
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS positioning_data (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(50) NOT NULL,
+    open_interest DECIMAL(30,8) NOT NULL,
+    open_interest_value DECIMAL(30,8),
+    long_short_ratio DECIMAL(20,8),
+    long_account DECIMAL(10,6),
+    short_account DECIMAL(10,6),
+    timestamp BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_symbol (symbol),
+    INDEX idx_timestamp (timestamp)
+);
@@ -0,0 +1,15 @@
+-- 审计日志表
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    category VARCHAR(50) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    symbol VARCHAR(50),
+    actor VARCHAR(100),
+    details JSON,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_category (category),
+    INDEX idx_symbol (symbol),
+    INDEX idx_created_at (created_at)
+);
@@ -0,0 +1,4 @@
+-- 持仓表增加最新成交价（与标记价格分别记录）
+USE trading_bot;
+
+ALTER TABLE positions ADD COLUMN last_price DECIMAL(20,8) DEFAULT 0 AFTER mark_price;
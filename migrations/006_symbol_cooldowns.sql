@@ -0,0 +1,12 @@
+-- 交易品种冷却状态表（连续亏损冷却、最小再入场间隔，重启后保留）
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS symbol_cooldowns (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(50) NOT NULL UNIQUE,
+    consecutive_losses INT DEFAULT 0,
+    last_entry_time TIMESTAMP NULL,
+    last_exit_time TIMESTAMP NULL,
+    cooldown_until TIMESTAMP NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
@@ -0,0 +1,6 @@
+-- 成交记录增加预期价格和滑点（基点，正数表示成交价差于预期）
+USE trading_bot;
+
+ALTER TABLE trades
+    ADD COLUMN expected_price DECIMAL(20,8) DEFAULT 0 AFTER is_maker,
+    ADD COLUMN slippage_bps DECIMAL(12,4) DEFAULT 0 AFTER expected_price;
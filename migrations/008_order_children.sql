@@ -0,0 +1,7 @@
+-- 订单表增加所属持仓和子订单角色（分批止盈、移动止损）
+USE trading_bot;
+
+ALTER TABLE orders
+    ADD COLUMN position_id BIGINT UNSIGNED NULL AFTER position_side,
+    ADD COLUMN role VARCHAR(20) AFTER position_id,
+    ADD INDEX idx_position_id (position_id);
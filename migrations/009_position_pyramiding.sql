@@ -0,0 +1,6 @@
+-- 持仓表增加加仓次数和最近一次开/加仓价格（开仓价为加权平均价）
+USE trading_bot;
+
+ALTER TABLE positions
+    ADD COLUMN add_ons INT DEFAULT 0 AFTER closed_pnl,
+    ADD COLUMN last_add_price DECIMAL(20,8) DEFAULT 0 AFTER add_ons;
@@ -0,0 +1,20 @@
+-- 网格策略状态表（网格档位、挂单及成交记录，重启后恢复网格）
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS grid_states (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(50) NOT NULL,
+    level INT NOT NULL,
+    base_price DECIMAL(20,8) NOT NULL,
+    price DECIMAL(20,8) NOT NULL,
+    quantity DECIMAL(20,8) DEFAULT 0,
+    active BOOLEAN DEFAULT FALSE,
+    side VARCHAR(10),
+    exchange_order_id VARCHAR(50),
+    filled_legs INT DEFAULT 0,
+    last_fill_price DECIMAL(20,8) DEFAULT 0,
+    last_fill_time TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY idx_grid_symbol_level (symbol, level)
+);
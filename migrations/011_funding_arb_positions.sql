@@ -0,0 +1,24 @@
+-- 资金费率套利持仓表（现货多头 + 永续空头）
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS funding_arb_positions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN',
+    spot_quantity DECIMAL(20,8) NOT NULL,
+    perp_quantity DECIMAL(20,8) NOT NULL,
+    spot_entry_price DECIMAL(20,8) NOT NULL,
+    perp_entry_price DECIMAL(20,8) NOT NULL,
+    spot_exit_price DECIMAL(20,8) DEFAULT 0,
+    perp_exit_price DECIMAL(20,8) DEFAULT 0,
+    entry_funding_rate DECIMAL(12,8) DEFAULT 0,
+    exit_funding_rate DECIMAL(12,8) DEFAULT 0,
+    closed_pnl DECIMAL(20,8) DEFAULT 0,
+    open_time TIMESTAMP NOT NULL,
+    close_time TIMESTAMP NULL,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_symbol (symbol),
+    INDEX idx_status (status)
+);
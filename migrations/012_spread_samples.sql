@@ -0,0 +1,14 @@
+-- 跨交易所价差采样表（其他交易所相对 Binance 的价差）
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS spread_samples (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(50) NOT NULL,
+    venue VARCHAR(20) NOT NULL,
+    reference_price DECIMAL(20,8) NOT NULL,
+    venue_price DECIMAL(20,8) NOT NULL,
+    spread_bps DECIMAL(12,4) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_spread_symbol_venue (symbol, venue),
+    INDEX idx_created_at (created_at)
+);
@@ -0,0 +1,6 @@
+-- 订单表增加客户端订单ID（策略信号使用确定性ID，用于跳过重复下单）
+USE trading_bot;
+
+ALTER TABLE orders
+    ADD COLUMN client_order_id VARCHAR(64) AFTER exchange_order_id,
+    ADD INDEX idx_client_order_id (client_order_id);
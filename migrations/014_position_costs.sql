@@ -0,0 +1,11 @@
+-- 持仓表增加手续费、资金费和净盈亏，风险指标表增加每日手续费和资金费
+USE trading_bot;
+
+ALTER TABLE positions
+    ADD COLUMN commission DECIMAL(20,8) DEFAULT 0 AFTER closed_pnl,
+    ADD COLUMN funding_fee DECIMAL(20,8) DEFAULT 0 AFTER commission,
+    ADD COLUMN net_pnl DECIMAL(20,8) DEFAULT 0 AFTER funding_fee;
+
+ALTER TABLE risk_metrics
+    ADD COLUMN daily_fees DECIMAL(20,8) DEFAULT 0 AFTER daily_pnl,
+    ADD COLUMN daily_funding DECIMAL(20,8) DEFAULT 0 AFTER daily_fees;
@@ -0,0 +1,17 @@
+-- 交易所收益历史表（已实现盈亏、手续费、资金费等），用于与持仓盈亏对账
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS incomes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    tran_id BIGINT NOT NULL,
+    income_type VARCHAR(32) NOT NULL,
+    symbol VARCHAR(50),
+    income DECIMAL(20,8) NOT NULL,
+    asset VARCHAR(20),
+    info VARCHAR(191),
+    trade_id VARCHAR(64),
+    time TIMESTAMP(3) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_income_tran_type (tran_id, income_type),
+    INDEX idx_income_symbol_time (symbol, time)
+);
@@ -0,0 +1,18 @@
+-- 订单流分钟样本表（按主动成交方向统计的成交额与大单）
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS order_flow_samples (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(50) NOT NULL,
+    buy_volume DECIMAL(30,8) NOT NULL,
+    sell_volume DECIMAL(30,8) NOT NULL,
+    delta DECIMAL(30,8) NOT NULL,
+    trade_count INT NOT NULL,
+    large_buys INT DEFAULT 0,
+    large_sells INT DEFAULT 0,
+    large_buy_volume DECIMAL(30,8) DEFAULT 0,
+    large_sell_volume DECIMAL(30,8) DEFAULT 0,
+    timestamp BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_order_flow_symbol_time (symbol, timestamp)
+);
@@ -0,0 +1,14 @@
+-- 强平订单表（来自交易所强平订单流）
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS liquidations (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(50) NOT NULL,
+    side VARCHAR(10) NOT NULL,
+    price DECIMAL(20,8) NOT NULL,
+    quantity DECIMAL(20,8) NOT NULL,
+    notional DECIMAL(30,8) NOT NULL,
+    time DATETIME(3) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_liquidation_symbol_time (symbol, time)
+);
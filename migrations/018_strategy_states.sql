@@ -0,0 +1,12 @@
+-- 策略状态表（策略内存状态按策略和品种以JSON保存，重启后恢复）
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS strategy_states (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    strategy VARCHAR(100) NOT NULL,
+    symbol VARCHAR(50) NOT NULL,
+    state MEDIUMTEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY idx_strategy_state (strategy, symbol)
+);
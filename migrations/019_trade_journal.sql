@@ -0,0 +1,22 @@
+-- 交易日志表（开仓、加仓、平仓时的行情特征、策略指标、策略状态和K线窗口快照）
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS trade_journals (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    position_id BIGINT UNSIGNED,
+    symbol VARCHAR(50) NOT NULL,
+    event VARCHAR(20) NOT NULL,
+    strategy VARCHAR(100),
+    reason TEXT,
+    confidence DECIMAL(10,4),
+    price DECIMAL(20,8),
+    quantity DECIMAL(20,8),
+    features TEXT,
+    indicators TEXT,
+    strategy_state MEDIUMTEXT,
+    klines MEDIUMTEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_trade_journals_position_id (position_id),
+    INDEX idx_trade_journals_symbol (symbol),
+    INDEX idx_trade_journals_created_at (created_at)
+);
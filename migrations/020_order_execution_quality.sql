@@ -0,0 +1,9 @@
+-- 订单表增加执行质量字段（信号预期价格、提交价格、成交均价、提交时间和首笔成交延迟）
+USE trading_bot;
+
+ALTER TABLE orders
+    ADD COLUMN expected_price DECIMAL(20,8) DEFAULT 0 AFTER price,
+    ADD COLUMN submitted_price DECIMAL(20,8) DEFAULT 0 AFTER expected_price,
+    ADD COLUMN avg_fill_price DECIMAL(20,8) DEFAULT 0 AFTER submitted_price,
+    ADD COLUMN submitted_at TIMESTAMP NULL AFTER avg_fill_price,
+    ADD COLUMN fill_latency_ms BIGINT DEFAULT 0 AFTER submitted_at;
@@ -0,0 +1,18 @@
+-- 期权隐含波动率、偏度与看跌/看涨持仓比样本表
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS options_volatility_samples (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    underlying VARCHAR(20) NOT NULL,
+    venue VARCHAR(20) NOT NULL,
+    underlying_price DECIMAL(30,8),
+    expiry DATETIME,
+    atm_iv DECIMAL(12,4),
+    put_iv DECIMAL(12,4),
+    call_iv DECIMAL(12,4),
+    skew DECIMAL(12,4),
+    put_call_ratio DECIMAL(12,6),
+    timestamp BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_options_underlying_time (underlying, timestamp)
+);
@@ -0,0 +1,31 @@
+-- 订单审计日志：只追加、哈希链式，订单请求和交易所结果加密存储
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS order_audit_log (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    seq BIGINT UNSIGNED NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    market VARCHAR(10) NOT NULL,
+    symbol VARCHAR(50) NOT NULL,
+    exchange_order_id VARCHAR(50),
+    client_order_id VARCHAR(64),
+    triggered_by VARCHAR(20) NOT NULL,
+    actor VARCHAR(100),
+    success BOOLEAN,
+    payload TEXT,
+    prev_hash VARCHAR(64),
+    hash VARCHAR(64) NOT NULL,
+    timestamp BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_order_audit_seq (seq),
+    INDEX idx_order_audit_symbol (symbol),
+    INDEX idx_order_audit_timestamp (timestamp)
+);
+
+-- 禁止修改和删除已写入的审计记录
+DELIMITER //
+CREATE TRIGGER order_audit_log_no_update BEFORE UPDATE ON order_audit_log
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'order_audit_log is append-only'//
+CREATE TRIGGER order_audit_log_no_delete BEFORE DELETE ON order_audit_log
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'order_audit_log is append-only'//
+DELIMITER ;
@@ -0,0 +1,22 @@
+-- 远程配置：风险限额和策略参数可通过 API 修改，带版本号并记录每次变更
+USE trading_bot;
+
+ALTER TABLE trading_configs
+    ADD COLUMN max_daily_loss DECIMAL(20,8) NOT NULL DEFAULT 0 AFTER risk_percent,
+    ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER max_daily_loss;
+
+ALTER TABLE strategies
+    ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER performance;
+
+-- 配置变更历史：谁在何时把哪个字段从什么改成了什么
+CREATE TABLE IF NOT EXISTS config_changes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    entity VARCHAR(20) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    version INT UNSIGNED NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    changes JSON,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_config_change_entity (entity, name),
+    INDEX idx_created_at (created_at)
+);
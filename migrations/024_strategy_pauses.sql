@@ -0,0 +1,13 @@
+-- 策略自动暂停：滚动期望收益或回撤超过阈值时暂停新开仓，恢复时间之前的交易不再计入统计
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS strategy_pauses (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    strategy VARCHAR(100) NOT NULL UNIQUE,
+    paused BOOLEAN DEFAULT FALSE,
+    reason TEXT,
+    stats TEXT,
+    paused_at TIMESTAMP NULL,
+    resumed_at TIMESTAMP NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
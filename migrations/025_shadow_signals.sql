@@ -0,0 +1,19 @@
+-- 影子A/B测试：候选策略与实盘策略的对照副本在相同行情上各自模拟交易，记录双方的非HOLD信号和模拟盈亏
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS shadow_signals (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    variant VARCHAR(100) NOT NULL,
+    strategy VARCHAR(100),
+    symbol VARCHAR(50) NOT NULL,
+    action VARCHAR(10) NOT NULL,
+    price DECIMAL(20,8),
+    confidence DECIMAL(10,4),
+    reason TEXT,
+    filled BOOLEAN DEFAULT FALSE,
+    pnl DECIMAL(20,8) DEFAULT 0,
+    signal_time TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_shadow_variant_time (variant, signal_time),
+    INDEX idx_symbol (symbol)
+);
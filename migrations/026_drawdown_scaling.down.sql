@@ -0,0 +1,6 @@
+-- 回滚 026：删除风险指标中的回撤比例和缩放倍数列
+USE trading_bot;
+
+ALTER TABLE risk_metrics
+    DROP COLUMN drawdown_scale,
+    DROP COLUMN equity_drawdown_percent;
@@ -0,0 +1,6 @@
+-- 回撤缩仓：风险指标记录权益相对高水位的回撤比例和当前的开仓缩放倍数
+USE trading_bot;
+
+ALTER TABLE risk_metrics
+    ADD COLUMN equity_drawdown_percent DECIMAL(10,4) DEFAULT 0 AFTER total_exposure,
+    ADD COLUMN drawdown_scale DECIMAL(10,4) DEFAULT 1 AFTER equity_drawdown_percent;
@@ -0,0 +1,4 @@
+-- 回滚 027：删除日内风险指标序列
+USE trading_bot;
+
+DROP TABLE IF EXISTS risk_metric_points;
@@ -0,0 +1,18 @@
+-- 风险指标按日期每天一行（覆盖更新），日内变化另存到 risk_metric_points 供绘图使用（trading.risk_metrics.intraday）
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS risk_metric_points (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    time TIMESTAMP NOT NULL,
+    daily_pnl DECIMAL(20,8) DEFAULT 0,
+    daily_fees DECIMAL(20,8) DEFAULT 0,
+    daily_funding DECIMAL(20,8) DEFAULT 0,
+    total_trades INT DEFAULT 0,
+    winning_trades INT DEFAULT 0,
+    losing_trades INT DEFAULT 0,
+    win_rate DECIMAL(5,2) DEFAULT 0,
+    equity_drawdown_percent DECIMAL(10,4) DEFAULT 0,
+    drawdown_scale DECIMAL(10,4) DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_time (time)
+);
@@ -0,0 +1,5 @@
+-- 回滚 028：删除订单和持仓的版本号
+USE trading_bot;
+
+ALTER TABLE orders DROP COLUMN version;
+ALTER TABLE positions DROP COLUMN version;
@@ -0,0 +1,5 @@
+-- 乐观锁：订单和持仓增加版本号，每次更新加一；写入时版本号已被其他流程改变则更新失败，避免并发覆盖
+USE trading_bot;
+
+ALTER TABLE orders ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER notes;
+ALTER TABLE positions ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER notes;
@@ -0,0 +1,4 @@
+-- 回滚 029：删除发件箱
+USE trading_bot;
+
+DROP TABLE IF EXISTS outbox_messages;
@@ -0,0 +1,16 @@
+-- 发件箱：通知和持仓事件与交易状态在同一事务中写入，由后台异步投递，失败后按退避重试，超过次数后标记为 DEAD
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL,
+    payload MEDIUMTEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT,
+    delivered_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_outbox_due (status, next_attempt_at)
+);
@@ -0,0 +1,5 @@
+-- 回滚 030：删除策略虚拟钱包
+USE trading_bot;
+
+DROP TABLE IF EXISTS wallet_snapshots;
+DROP TABLE IF EXISTS wallet_transfers;
@@ -0,0 +1,25 @@
+-- 按策略划分的虚拟钱包：资金划转记录和权益曲线（trading.wallets）
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS wallet_transfers (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    strategy VARCHAR(100) NOT NULL,
+    amount DECIMAL(20,8) NOT NULL,
+    note VARCHAR(255),
+    actor VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_strategy (strategy)
+);
+
+CREATE TABLE IF NOT EXISTS wallet_snapshots (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    strategy VARCHAR(100) NOT NULL,
+    time TIMESTAMP NOT NULL,
+    deposits DECIMAL(20,8) DEFAULT 0,
+    realized_pnl DECIMAL(20,8) DEFAULT 0,
+    unrealized_pnl DECIMAL(20,8) DEFAULT 0,
+    equity DECIMAL(20,8) DEFAULT 0,
+    margin_used DECIMAL(20,8) DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_wallet_time (strategy, time)
+);
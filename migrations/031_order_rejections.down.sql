@@ -0,0 +1,7 @@
+-- 回滚 031：删除订单的拒绝分类
+USE trading_bot;
+
+DROP INDEX idx_orders_reject_reason ON orders;
+ALTER TABLE orders DROP COLUMN remediations;
+ALTER TABLE orders DROP COLUMN reject_message;
+ALTER TABLE orders DROP COLUMN reject_reason;
@@ -0,0 +1,7 @@
+-- 下单拒绝分析：被交易所拒绝的订单也写入 orders（状态 REJECTED），记录拒绝分类、交易所返回信息和自动修正次数
+USE trading_bot;
+
+ALTER TABLE orders ADD COLUMN reject_reason VARCHAR(32) NOT NULL DEFAULT '' AFTER notes;
+ALTER TABLE orders ADD COLUMN reject_message VARCHAR(255) NOT NULL DEFAULT '' AFTER reject_reason;
+ALTER TABLE orders ADD COLUMN remediations INT NOT NULL DEFAULT 0 AFTER reject_message;
+CREATE INDEX idx_orders_reject_reason ON orders (reject_reason, created_at);
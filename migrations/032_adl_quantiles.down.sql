@@ -0,0 +1,4 @@
+-- 回滚 032：删除 ADL 分位记录
+USE trading_bot;
+
+DROP TABLE IF EXISTS adl_quantiles;
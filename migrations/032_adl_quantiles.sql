@@ -0,0 +1,11 @@
+-- 自动减仓（ADL）队列：记录每个持仓方向 ADL 分位的变化（0 最靠后，4 最先被减仓）
+USE trading_bot;
+
+CREATE TABLE IF NOT EXISTS adl_quantiles (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    side VARCHAR(10) NOT NULL,
+    quantile TINYINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_adl_symbol_time (symbol, created_at)
+);
@@ -0,0 +1,5 @@
+-- 回滚 033：恢复按 (品种, 外部ID) 去重；不同来源存在相同外部ID时需先删除重复条目
+USE trading_bot;
+
+CREATE UNIQUE INDEX idx_sentiment_symbol_external ON sentiment_items (symbol, external_id);
+DROP INDEX idx_sentiment_symbol_source_external ON sentiment_items;
@@ -0,0 +1,5 @@
+-- 新闻条目按 (品种, 来源, 外部ID) 去重，与内存窗口的去重键一致；不同来源的相同外部ID不再被丢弃
+USE trading_bot;
+
+CREATE UNIQUE INDEX idx_sentiment_symbol_source_external ON sentiment_items (symbol, source, external_id);
+DROP INDEX idx_sentiment_symbol_external ON sentiment_items;
@@ -0,0 +1,4 @@
+-- 回滚 034：删除持仓保存的止盈阶梯
+USE trading_bot;
+
+ALTER TABLE positions DROP COLUMN take_profit_plan;
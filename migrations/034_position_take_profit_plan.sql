@@ -0,0 +1,4 @@
+-- 止盈阶梯随持仓保存（各档位、追踪回调比例及已成交档位），加仓后只按原阶梯重挂未成交的档位
+USE trading_bot;
+
+ALTER TABLE positions ADD COLUMN take_profit_plan TEXT AFTER notes;
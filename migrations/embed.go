@@ -0,0 +1,10 @@
+// Package migrations embeds the versioned SQL schema migrations. NNN_name.sql applies a
+// change and the optional NNN_name.down.sql reverts it.
+package migrations
+
+import "embed"
+
+// Files holds every migration script of this directory
+//
+//go:embed *.sql
+var Files embed.FS
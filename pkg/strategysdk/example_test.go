@@ -0,0 +1,44 @@
+package strategysdk_test
+
+import (
+	"context"
+	"log"
+
+	"contract_playground/pkg/strategysdk"
+)
+
+// breakout buys when the price clears the highest high of the previous klines
+type breakout struct{}
+
+func (breakout) Initialize(parameters map[string]interface{}) error { return nil }
+
+func (breakout) ShouldBuy(ctx context.Context, data *strategysdk.MarketData) (*strategysdk.Signal, error) {
+	if len(data.Klines) < 2 {
+		return &strategysdk.Signal{Action: strategysdk.ActionHold, Reason: "insufficient data"}, nil
+	}
+	high := 0.0
+	for _, kline := range data.Klines[:len(data.Klines)-1] {
+		if kline.High > high {
+			high = kline.High
+		}
+	}
+	if data.Price > high {
+		return &strategysdk.Signal{Action: strategysdk.ActionBuy, Confidence: 0.7, Reason: "breakout"}, nil
+	}
+	return &strategysdk.Signal{Action: strategysdk.ActionHold}, nil
+}
+
+func (breakout) ShouldSell(ctx context.Context, data *strategysdk.MarketData, position *strategysdk.Position) (*strategysdk.Signal, error) {
+	if data.Price < position.EntryPrice*0.98 {
+		return &strategysdk.Signal{Action: strategysdk.ActionSell, Confidence: 1, Reason: "stop"}, nil
+	}
+	return &strategysdk.Signal{Action: strategysdk.ActionHold}, nil
+}
+
+// The main function of an external strategy, configured with strategy.type "external" and
+// parameters.command pointing at the built binary
+func ExampleServe() {
+	if err := strategysdk.Serve(breakout{}); err != nil {
+		log.Fatal(err)
+	}
+}
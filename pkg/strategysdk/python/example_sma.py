@@ -0,0 +1,51 @@
+"""Moving average crossover over the kline closes, as an example Python strategy.
+
+strategy:
+  type: "python"
+  parameters:
+    script: "pkg/strategysdk/python/example_sma.py"
+    short_period: 10
+    long_period: 30
+"""
+
+import sys
+
+from strategysdk import BUY, HOLD, SELL, Strategy, serve, signal
+
+
+class MovingAverageCrossover(Strategy):
+    name = "Python SMA"
+
+    def initialize(self, parameters):
+        self.short_period = int(parameters.get("short_period", 10))
+        self.long_period = int(parameters.get("long_period", 30))
+        if self.short_period >= self.long_period:
+            raise ValueError("short_period must be below long_period")
+        print("initialized with %d/%d" % (self.short_period, self.long_period), file=sys.stderr)
+
+    def averages(self, market_data):
+        closes = [kline["close"] for kline in market_data.get("klines") or []]
+        if len(closes) < self.long_period:
+            return None
+        short = sum(closes[-self.short_period:]) / self.short_period
+        long = sum(closes[-self.long_period:]) / self.long_period
+        return short, long
+
+    def should_buy(self, market_data):
+        averages = self.averages(market_data)
+        if averages is None:
+            return signal(HOLD, reason="insufficient data")
+        short, long = averages
+        if short > long:
+            return signal(BUY, confidence=min((short - long) / long * 10, 1.0), reason="short SMA above long")
+        return None
+
+    def should_sell(self, market_data, position):
+        averages = self.averages(market_data)
+        if averages is not None and averages[0] < averages[1]:
+            return signal(SELL, confidence=1.0, reason="short SMA below long")
+        return None
+
+
+if __name__ == "__main__":
+    serve(MovingAverageCrossover())
@@ -0,0 +1,81 @@
+"""Python side of the contract_playground strategy bridge.
+
+Subclass Strategy, override the methods you need and call serve(); configure the bot with
+strategy.type "python" and parameters.script pointing at your script. The bot exchanges one
+JSON object per line over stdin/stdout (the protocol of pkg/strategysdk), so stdout belongs
+to the protocol: log to stderr, which the bot forwards to its own log.
+
+Market data and positions arrive as dicts with the field names of pkg/strategysdk
+(market_data["price"], market_data["klines"][-1]["close"], position["entry_price"], ...).
+Methods return a dict built with signal(), or None to hold.
+"""
+
+import json
+import sys
+import traceback
+
+PROTOCOL_VERSION = 1
+
+BUY = "BUY"
+SELL = "SELL"
+HOLD = "HOLD"
+
+
+def signal(action, confidence=0.0, reason="", **fields):
+    """Build a signal; optional fields are price, stop_loss, take_profit, position_side
+    and trailing_stop_percent."""
+    return dict(fields, action=action, confidence=confidence, reason=reason)
+
+
+class Strategy:
+    """Base strategy that always holds."""
+
+    name = None  # Shown in the bot's logs and trades when set
+
+    def initialize(self, parameters):
+        """Receive trading.strategy.parameters; called again when they change."""
+
+    def should_buy(self, market_data):
+        return signal(HOLD)
+
+    def should_sell(self, market_data, position):
+        return signal(HOLD)
+
+
+def handle(strategy, request):
+    """Answer one request; exceptions become the response's error."""
+    response = {"id": request.get("id", 0)}
+    try:
+        method = request.get("method")
+        if method == "initialize":
+            version = request.get("version", 0)
+            if version > PROTOCOL_VERSION:
+                raise ValueError(
+                    "protocol version %d is newer than the supported %d" % (version, PROTOCOL_VERSION))
+            strategy.initialize(request.get("parameters") or {})
+            if strategy.name:
+                response["name"] = strategy.name
+        elif method == "should_buy":
+            response["signal"] = strategy.should_buy(request["market_data"])
+        elif method == "should_sell":
+            response["signal"] = strategy.should_sell(request["market_data"], request["position"])
+        else:
+            raise ValueError("unknown method %r" % method)
+    except Exception as exc:  # Report to the bot instead of dying
+        traceback.print_exc(file=sys.stderr)
+        response.pop("signal", None)
+        response["error"] = "%s: %s" % (type(exc).__name__, exc)
+    return response
+
+
+def serve(strategy, stdin=None, stdout=None):
+    """Answer the bot's requests until stdin closes."""
+    stdin = stdin or sys.stdin
+    stdout = stdout or sys.stdout
+    for line in stdin:
+        line = line.strip()
+        if not line:
+            continue
+        response = handle(strategy, json.loads(line))
+        stdout.write(json.dumps(response) + "\n")
+        stdout.flush()
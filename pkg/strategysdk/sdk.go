@@ -0,0 +1,119 @@
+// Package strategysdk lets trading strategies live outside this repository. A strategy
+// implements Strategy and either runs as its own process, calling Serve to speak the
+// JSON-over-stdio protocol (strategy type "external"), or is built as a Go plugin exporting
+// NewStrategy (strategy type "plugin").
+package strategysdk
+
+import "context"
+
+// ProtocolVersion is sent with every initialize request
+const ProtocolVersion = 1
+
+// Protocol methods
+const (
+	MethodInitialize = "initialize"
+	MethodShouldBuy  = "should_buy"
+	MethodShouldSell = "should_sell"
+)
+
+// Signal actions
+const (
+	ActionBuy  = "BUY"
+	ActionSell = "SELL"
+	ActionHold = "HOLD"
+)
+
+// Strategy decides entries and exits from market data. Calls for different symbols may come
+// from the same Strategy; over the stdio protocol they arrive one at a time.
+type Strategy interface {
+	// Initialize receives trading.strategy.parameters, without the loader's own keys. It is
+	// called again with new parameters when the configuration changes.
+	Initialize(parameters map[string]interface{}) error
+	// ShouldBuy returns BUY to open a position (LONG unless PositionSide says otherwise)
+	ShouldBuy(ctx context.Context, data *MarketData) (*Signal, error)
+	// ShouldSell returns SELL to close the position or BUY to add to it
+	ShouldSell(ctx context.Context, data *MarketData, position *Position) (*Signal, error)
+}
+
+// Namer is optionally implemented by strategies to name themselves in logs and trades
+type Namer interface {
+	Name() string
+}
+
+// Kline is one candle; times are Unix milliseconds
+type Kline struct {
+	OpenTime  int64   `json:"open_time"`
+	Open      float64 `json:"open"`
+	High      float64 `json:"high"`
+	Low       float64 `json:"low"`
+	Close     float64 `json:"close"`
+	Volume    float64 `json:"volume"`
+	CloseTime int64   `json:"close_time"`
+}
+
+// MarketData is the engine's view of a symbol at evaluation time. Optional feeds are 0 when
+// disabled, as in the built-in strategies.
+type MarketData struct {
+	Symbol         string  `json:"symbol"`
+	Price          float64 `json:"price"`
+	MarkPrice      float64 `json:"mark_price"`
+	Volume         float64 `json:"volume"`
+	ChangePercent  float64 `json:"change_percent"`
+	High24h        float64 `json:"high_24h"`
+	Low24h         float64 `json:"low_24h"`
+	QuoteVolume24h float64 `json:"quote_volume_24h"`
+	FundingRate    float64 `json:"funding_rate"`
+	Timestamp      int64   `json:"timestamp"` // Unix milliseconds
+	Klines         []Kline `json:"klines"`    // Oldest first; the last one may still be forming
+	ATR            float64 `json:"atr"`
+
+	Sentiment         float64 `json:"sentiment"`
+	OpenInterest      float64 `json:"open_interest"`
+	LongShortRatio    float64 `json:"long_short_ratio"`
+	ImpliedVolatility float64 `json:"implied_volatility"`
+	VolumeDelta       float64 `json:"volume_delta"`
+	LongLiquidations  float64 `json:"long_liquidations"`
+	ShortLiquidations float64 `json:"short_liquidations"`
+}
+
+// Position is the open position a ShouldSell call is about
+type Position struct {
+	Symbol        string  `json:"symbol"`
+	PositionSide  string  `json:"position_side"` // LONG, SHORT
+	Size          float64 `json:"size"`
+	EntryPrice    float64 `json:"entry_price"`
+	MarkPrice     float64 `json:"mark_price"`
+	UnrealizedPnL float64 `json:"unrealized_pnl"`
+	Leverage      int     `json:"leverage"`
+	OpenTime      int64   `json:"open_time"` // Unix milliseconds
+}
+
+// Signal is a strategy's decision. The engine sizes orders itself, so there is no quantity.
+type Signal struct {
+	Action              string  `json:"action"` // BUY, SELL, HOLD
+	Price               float64 `json:"price,omitempty"`
+	StopLoss            float64 `json:"stop_loss,omitempty"`
+	TakeProfit          float64 `json:"take_profit,omitempty"`
+	Confidence          float64 `json:"confidence"` // 0.0 to 1.0
+	Reason              string  `json:"reason,omitempty"`
+	PositionSide        string  `json:"position_side,omitempty"`
+	TrailingStopPercent float64 `json:"trailing_stop_percent,omitempty"`
+}
+
+// Request is one line the engine writes to the strategy process's stdin
+type Request struct {
+	ID         uint64                 `json:"id"`
+	Method     string                 `json:"method"`
+	Version    int                    `json:"version,omitempty"`    // initialize
+	Parameters map[string]interface{} `json:"parameters,omitempty"` // initialize
+	MarketData *MarketData            `json:"market_data,omitempty"`
+	Position   *Position              `json:"position,omitempty"` // should_sell
+}
+
+// Response is the line the strategy process writes to stdout for the request with the same ID
+type Response struct {
+	ID     uint64  `json:"id"`
+	Name   string  `json:"name,omitempty"` // initialize; optional
+	Signal *Signal `json:"signal,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
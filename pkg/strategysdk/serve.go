@@ -0,0 +1,81 @@
+package strategysdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Serve answers the engine's requests on stdin/stdout until stdin closes. Anything the
+// strategy wants to log must go to stderr, which the engine forwards to its log.
+func Serve(strategy Strategy) error {
+	return ServeIO(context.Background(), strategy, os.Stdin, os.Stdout)
+}
+
+// ServeIO answers newline-delimited JSON requests from r on w, one at a time, until r is
+// exhausted or ctx is cancelled
+func ServeIO(ctx context.Context, strategy Strategy, r io.Reader, w io.Writer) error {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	writer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(writer)
+
+	for ctx.Err() == nil {
+		var request Request
+		if err := decoder.Decode(&request); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read request: %w", err)
+		}
+
+		response := handle(ctx, strategy, &request)
+		if err := encoder.Encode(response); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+		if err := writer.Flush(); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+	return ctx.Err()
+}
+
+// handle dispatches a request; strategy errors are returned in the response
+func handle(ctx context.Context, strategy Strategy, request *Request) *Response {
+	response := &Response{ID: request.ID}
+	var err error
+	switch request.Method {
+	case MethodInitialize:
+		if request.Version > ProtocolVersion {
+			err = fmt.Errorf("protocol version %d is newer than the supported %d", request.Version, ProtocolVersion)
+			break
+		}
+		if err = strategy.Initialize(request.Parameters); err == nil {
+			if namer, ok := strategy.(Namer); ok {
+				response.Name = namer.Name()
+			}
+		}
+	case MethodShouldBuy:
+		if request.MarketData == nil {
+			err = fmt.Errorf("should_buy without market data")
+			break
+		}
+		response.Signal, err = strategy.ShouldBuy(ctx, request.MarketData)
+	case MethodShouldSell:
+		if request.MarketData == nil || request.Position == nil {
+			err = fmt.Errorf("should_sell without market data or position")
+			break
+		}
+		response.Signal, err = strategy.ShouldSell(ctx, request.MarketData, request.Position)
+	default:
+		err = fmt.Errorf("unknown method %q", request.Method)
+	}
+
+	if err != nil {
+		response.Signal = nil
+		response.Error = err.Error()
+	}
+	return response
+}
@@ -0,0 +1,88 @@
+package strategysdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type thresholdStrategy struct {
+	threshold float64
+}
+
+func (s *thresholdStrategy) Name() string { return "Threshold" }
+
+func (s *thresholdStrategy) Initialize(parameters map[string]interface{}) error {
+	threshold, ok := parameters["threshold"].(float64)
+	if !ok {
+		return fmt.Errorf("threshold is required")
+	}
+	s.threshold = threshold
+	return nil
+}
+
+func (s *thresholdStrategy) ShouldBuy(ctx context.Context, data *MarketData) (*Signal, error) {
+	if data.Price > s.threshold {
+		return &Signal{Action: ActionBuy, Confidence: 1}, nil
+	}
+	return &Signal{Action: ActionHold}, nil
+}
+
+func (s *thresholdStrategy) ShouldSell(ctx context.Context, data *MarketData, position *Position) (*Signal, error) {
+	if data.Price < position.EntryPrice {
+		return &Signal{Action: ActionSell, Confidence: 1}, nil
+	}
+	return &Signal{Action: ActionHold}, nil
+}
+
+func TestServeIO(t *testing.T) {
+	requests := []Request{
+		{ID: 1, Method: MethodShouldBuy},
+		{ID: 2, Method: MethodInitialize, Version: ProtocolVersion, Parameters: map[string]interface{}{}},
+		{ID: 3, Method: MethodInitialize, Version: ProtocolVersion, Parameters: map[string]interface{}{"threshold": 100.0}},
+		{ID: 4, Method: MethodShouldBuy, MarketData: &MarketData{Symbol: "BTCUSDT", Price: 101}},
+		{ID: 5, Method: MethodShouldSell, MarketData: &MarketData{Symbol: "BTCUSDT", Price: 99}, Position: &Position{EntryPrice: 100}},
+		{ID: 6, Method: "unknown"},
+	}
+	var in bytes.Buffer
+	for _, request := range requests {
+		if err := json.NewEncoder(&in).Encode(request); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := ServeIO(context.Background(), &thresholdStrategy{}, &in, &out); err != nil {
+		t.Fatalf("ServeIO: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != len(requests) {
+		t.Fatalf("got %d responses, want %d", len(lines), len(requests))
+	}
+	responses := make([]Response, len(lines))
+	for i, line := range lines {
+		if err := json.Unmarshal([]byte(line), &responses[i]); err != nil {
+			t.Fatalf("response %d: %v", i, err)
+		}
+		if responses[i].ID != requests[i].ID {
+			t.Fatalf("response %d has ID %d, want %d", i, responses[i].ID, requests[i].ID)
+		}
+	}
+
+	if responses[0].Error == "" || responses[1].Error == "" || responses[5].Error == "" {
+		t.Fatalf("invalid requests did not fail: %+v", responses)
+	}
+	if responses[2].Error != "" || responses[2].Name != "Threshold" {
+		t.Fatalf("initialize: %+v", responses[2])
+	}
+	if responses[3].Signal == nil || responses[3].Signal.Action != ActionBuy {
+		t.Fatalf("should_buy: %+v", responses[3])
+	}
+	if responses[4].Signal == nil || responses[4].Signal.Action != ActionSell {
+		t.Fatalf("should_sell: %+v", responses[4])
+	}
+}
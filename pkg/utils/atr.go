@@ -0,0 +1,39 @@
+package utils
+
+import "math"
+
+// TrueRange returns the true range of a candle: the largest of high - low and the distances
+// from the previous close to the high and the low. Pass prevClose <= 0 for the first candle.
+func TrueRange(high, low, prevClose float64) float64 {
+	tr := high - low
+	if prevClose > 0 {
+		tr = math.Max(tr, math.Max(math.Abs(high-prevClose), math.Abs(low-prevClose)))
+	}
+	return tr
+}
+
+// CalculateATR calculates the Average True Range of the candles with Wilder's smoothing: the
+// first value is the simple average of the first period true ranges. It returns 0 when fewer
+// than period candles are given.
+func CalculateATR(highs, lows, closes []float64, period int) float64 {
+	n := len(closes)
+	if period <= 0 || n < period || len(highs) != n || len(lows) != n {
+		return 0
+	}
+
+	atr := 0.0
+	for i := 0; i < n; i++ {
+		prevClose := 0.0
+		if i > 0 {
+			prevClose = closes[i-1]
+		}
+		tr := TrueRange(highs[i], lows[i], prevClose)
+
+		if i < period {
+			atr += tr / float64(period)
+		} else {
+			atr = (atr*float64(period-1) + tr) / float64(period)
+		}
+	}
+	return atr
+}
@@ -0,0 +1,70 @@
+package utils
+
+// IchimokuSeries holds the Ichimoku lines aligned to the input candles. Senkou spans are the
+// cloud values plotted at each index (computed displacement candles earlier) so they compare
+// directly with the close at the same index. Values are 0 where history is insufficient.
+type IchimokuSeries struct {
+	Tenkan  []float64 // Conversion line
+	Kijun   []float64 // Base line
+	SenkouA []float64 // Leading span A
+	SenkouB []float64 // Leading span B
+	Chikou  []float64 // Lagging span: the close displacement candles later, 0 for the latest candles
+}
+
+// CloudTop returns the upper edge of the cloud at index i
+func (s *IchimokuSeries) CloudTop(i int) float64 {
+	return Max(s.SenkouA[i], s.SenkouB[i])
+}
+
+// CloudBottom returns the lower edge of the cloud at index i
+func (s *IchimokuSeries) CloudBottom(i int) float64 {
+	return Min(s.SenkouA[i], s.SenkouB[i])
+}
+
+// CalculateIchimoku calculates the Ichimoku lines for the candles described by highs, lows
+// and closes (typical periods are 9, 26, 52 and a displacement of 26)
+func CalculateIchimoku(highs, lows, closes []float64, tenkanPeriod, kijunPeriod, senkouBPeriod, displacement int) *IchimokuSeries {
+	n := len(closes)
+	series := &IchimokuSeries{
+		Tenkan:  make([]float64, n),
+		Kijun:   make([]float64, n),
+		SenkouA: make([]float64, n),
+		SenkouB: make([]float64, n),
+		Chikou:  make([]float64, n),
+	}
+	if len(highs) != n || len(lows) != n {
+		return series
+	}
+
+	// Span B before displacement; span A is derived from the already computed lines
+	series.Tenkan = midpoints(highs, lows, tenkanPeriod)
+	series.Kijun = midpoints(highs, lows, kijunPeriod)
+	spanB := midpoints(highs, lows, senkouBPeriod)
+	for i := 0; i < n; i++ {
+
+		if i+displacement < n {
+			series.Chikou[i] = closes[i+displacement]
+		}
+
+		j := i - displacement
+		if j < 0 {
+			continue
+		}
+		if series.Tenkan[j] > 0 && series.Kijun[j] > 0 {
+			series.SenkouA[i] = (series.Tenkan[j] + series.Kijun[j]) / 2
+		}
+		series.SenkouB[i] = spanB[j]
+	}
+
+	return series
+}
+
+// midpoints returns (highest high + lowest low) / 2 over the period ending at each index, or
+// 0 without a full period of history
+func midpoints(highs, lows []float64, period int) []float64 {
+	high, low := slidingHighLow(highs, lows, period)
+	for i := range high {
+		high[i] = (high[i] + low[i]) / 2
+	}
+	return high
+}
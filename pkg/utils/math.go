@@ -2,7 +2,16 @@ package utils
 
 import (
 	"math"
+	"sort"
 	"strconv"
+	"strings"
+)
+
+// Float error tolerated in quantity/step divisions, so 0.3/0.1 counts 3 steps: absolute for
+// small step counts, a few ulps of the count for large ones
+const (
+	stepEpsilon         = 1e-9
+	relativeStepEpsilon = 1e-15
 )
 
 // RoundToDecimal rounds a float64 to the specified number of decimal places
@@ -64,8 +73,9 @@ func NormalizeQuantity(quantity float64, stepSize float64) float64 {
 		return quantity
 	}
 	
-	steps := math.Floor(quantity / stepSize)
-	return steps * stepSize
+	steps := quantity / stepSize
+	steps = math.Floor(steps + math.Max(stepEpsilon, math.Abs(steps)*relativeStepEpsilon))
+	return RoundToDecimal(steps*stepSize, stepDecimals(stepSize))
 }
 
 // NormalizePrice normalizes price to exchange precision
@@ -75,7 +85,17 @@ func NormalizePrice(price float64, tickSize float64) float64 {
 	}
 	
 	ticks := math.Round(price / tickSize)
-	return ticks * tickSize
+	return RoundToDecimal(ticks*tickSize, stepDecimals(tickSize))
+}
+
+// stepDecimals returns the decimal places of a step or tick size such as 0.001 (3), so
+// multiples of it can be rounded back to exact exchange precision
+func stepDecimals(step float64) int {
+	formatted := strconv.FormatFloat(step, 'f', -1, 64)
+	if dot := strings.IndexByte(formatted, '.'); dot >= 0 {
+		return len(formatted) - dot - 1
+	}
+	return 0
 }
 
 // CalculateStandardDeviation calculates standard deviation of a slice of float64
@@ -125,6 +145,11 @@ func CalculateEMA(values []float64, period int) float64 {
 		return values[0]
 	}
 	
+	// A period below one would extrapolate past the latest value
+	if period < 1 {
+		period = 1
+	}
+	
 	multiplier := 2.0 / (float64(period) + 1.0)
 	ema := values[0]
 	
@@ -137,32 +162,23 @@ func CalculateEMA(values []float64, period int) float64 {
 
 // CalculateRSI calculates Relative Strength Index
 func CalculateRSI(prices []float64, period int) float64 {
-	if len(prices) < period+1 {
+	if period < 1 || len(prices) < period+1 {
 		return 50 // Neutral RSI
 	}
 	
-	gains := make([]float64, 0)
-	losses := make([]float64, 0)
-	
-	// Calculate price changes
-	for i := 1; i < len(prices); i++ {
+	// Only the last period price changes count, so there is no need to build the full series
+	avgGain := 0.0
+	avgLoss := 0.0
+	for i := len(prices) - period; i < len(prices); i++ {
 		change := prices[i] - prices[i-1]
 		if change > 0 {
-			gains = append(gains, change)
-			losses = append(losses, 0)
+			avgGain += change
 		} else {
-			gains = append(gains, 0)
-			losses = append(losses, -change)
+			avgLoss -= change
 		}
 	}
-	
-	if len(gains) < period {
-		return 50
-	}
-	
-	// Calculate average gain and loss
-	avgGain := CalculateMovingAverage(gains, period)
-	avgLoss := CalculateMovingAverage(losses, period)
+	avgGain /= float64(period)
+	avgLoss /= float64(period)
 	
 	if avgLoss == 0 {
 		return 100
@@ -223,6 +239,10 @@ func CalculateMaxDrawdown(cumulativeReturns []float64) float64 {
 		if value > peak {
 			peak = value
 		}
+		// A drawdown relative to a zero or negative peak is undefined
+		if peak <= 0 {
+			continue
+		}
 		
 		drawdown := (peak - value) / peak
 		if drawdown > maxDrawdown {
@@ -242,20 +262,16 @@ func CalculateVaR(returns []float64, confidenceLevel float64) float64 {
 	// Sort returns in ascending order
 	sortedReturns := make([]float64, len(returns))
 	copy(sortedReturns, returns)
-	
-	for i := 0; i < len(sortedReturns); i++ {
-		for j := i + 1; j < len(sortedReturns); j++ {
-			if sortedReturns[i] > sortedReturns[j] {
-				sortedReturns[i], sortedReturns[j] = sortedReturns[j], sortedReturns[i]
-			}
-		}
-	}
+	sort.Float64s(sortedReturns)
 	
 	// Calculate index for the confidence level
 	index := int(float64(len(sortedReturns)) * (1 - confidenceLevel))
 	if index >= len(sortedReturns) {
 		index = len(sortedReturns) - 1
 	}
+	if index < 0 {
+		index = 0
+	}
 	
 	return math.Abs(sortedReturns[index])
 }
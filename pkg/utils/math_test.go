@@ -0,0 +1,319 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// Largest step count fuzzed; beyond it float64 cannot tell neighbouring steps apart reliably
+const maxSteps = 1e12
+
+// Step and tick sizes as the exchange reports them
+var exchangeSteps = []float64{1, 0.1, 0.01, 0.001, 0.0001, 0.00001, 0.000001, 0.00000001, 0.5, 0.25, 5, 10}
+
+// onStep reports whether value is a whole multiple of step, allowing float error
+func onStep(value, step float64) bool {
+	steps := value / step
+	return math.Abs(steps-math.Round(steps)) <= math.Max(1e-6, math.Abs(steps)*1e-15)
+}
+
+func TestNormalizeQuantityFloatError(t *testing.T) {
+	tests := []struct {
+		quantity, step, want float64
+	}{
+		{0.3, 0.1, 0.3},
+		{0.7, 0.1, 0.7},
+		{1.001, 0.001, 1.001},
+		{0.029, 0.001, 0.029},
+		{4.35, 0.01, 4.35},
+		{0.30000000000000004, 0.1, 0.3},
+		{0.2999, 0.1, 0.2},
+		{123.456789, 0.001, 123.456},
+		{7, 5, 5},
+		{0.5, 0, 0.5},
+	}
+	for _, tt := range tests {
+		if got := NormalizeQuantity(tt.quantity, tt.step); got != tt.want {
+			t.Errorf("NormalizeQuantity(%v, %v) = %v, want %v", tt.quantity, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizePriceFloatError(t *testing.T) {
+	tests := []struct {
+		price, tick, want float64
+	}{
+		{0.3, 0.1, 0.3},
+		{60000.06, 0.1, 60000.1},
+		{1.23456, 0.0001, 1.2346},
+		{0.1 + 0.2, 0.01, 0.3},
+		{99.99, 0.5, 100},
+	}
+	for _, tt := range tests {
+		if got := NormalizePrice(tt.price, tt.tick); got != tt.want {
+			t.Errorf("NormalizePrice(%v, %v) = %v, want %v", tt.price, tt.tick, got, tt.want)
+		}
+	}
+}
+
+func FuzzNormalizeQuantity(f *testing.F) {
+	f.Add(0.3, uint8(1))
+	f.Add(1.001, uint8(3))
+	f.Add(123456.789, uint8(7))
+	f.Add(0.0, uint8(0))
+	f.Add(1e9, uint8(2))
+
+	f.Fuzz(func(t *testing.T, quantity float64, stepIndex uint8) {
+		step := exchangeSteps[int(stepIndex)%len(exchangeSteps)]
+		if math.IsNaN(quantity) || quantity < 0 || quantity/step > maxSteps {
+			t.Skip()
+		}
+
+		// Within float error of a step boundary the quantity rounds up to it
+		got := NormalizeQuantity(quantity, step)
+		if got < 0 || got > quantity+step*stepEpsilon+quantity*relativeStepEpsilon {
+			t.Fatalf("NormalizeQuantity(%v, %v) = %v, outside [0, quantity]", quantity, step, got)
+		}
+		if quantity-got >= step*(1+1e-6) {
+			t.Fatalf("NormalizeQuantity(%v, %v) = %v, dropped a whole step", quantity, step, got)
+		}
+		if !onStep(got, step) {
+			t.Fatalf("NormalizeQuantity(%v, %v) = %v, not a multiple of the step", quantity, step, got)
+		}
+		if again := NormalizeQuantity(got, step); again != got {
+			t.Fatalf("NormalizeQuantity is not idempotent: %v -> %v -> %v (step %v)", quantity, got, again, step)
+		}
+	})
+}
+
+func FuzzNormalizePrice(f *testing.F) {
+	f.Add(0.3, uint8(1))
+	f.Add(60000.06, uint8(1))
+	f.Add(1.23456, uint8(4))
+	f.Add(99.99, uint8(8))
+
+	f.Fuzz(func(t *testing.T, price float64, tickIndex uint8) {
+		tick := exchangeSteps[int(tickIndex)%len(exchangeSteps)]
+		if math.IsNaN(price) || price < 0 || price/tick > maxSteps {
+			t.Skip()
+		}
+
+		got := NormalizePrice(price, tick)
+		// Large prices cannot hold every decimal of a tiny tick
+		if math.Abs(got-price) > tick/2*(1+1e-6)+price*1e-15 {
+			t.Fatalf("NormalizePrice(%v, %v) = %v, more than half a tick away", price, tick, got)
+		}
+		if !onStep(got, tick) {
+			t.Fatalf("NormalizePrice(%v, %v) = %v, not a multiple of the tick", price, tick, got)
+		}
+		if again := NormalizePrice(got, tick); again != got {
+			t.Fatalf("NormalizePrice is not idempotent: %v -> %v -> %v (tick %v)", price, got, again, tick)
+		}
+	})
+}
+
+func TestCalculateRSIEdgeCases(t *testing.T) {
+	rising := []float64{1, 2, 3, 4, 5, 6}
+	tests := []struct {
+		name   string
+		prices []float64
+		period int
+		want   float64
+	}{
+		{"too few prices", []float64{1, 2}, 14, 50},
+		{"zero period", rising, 0, 50},
+		{"negative period", rising, -3, 50},
+		{"only gains", rising, 5, 100},
+		{"only losses", []float64{6, 5, 4, 3, 2, 1}, 5, 0},
+		{"equal gains and losses", []float64{1, 2, 1, 2, 1}, 4, 50},
+	}
+	for _, tt := range tests {
+		if got := CalculateRSI(tt.prices, tt.period); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: CalculateRSI = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func FuzzCalculateRSI(f *testing.F) {
+	f.Add(100.0, 101.0, 99.0, 102.0, 98.0, 3)
+	f.Add(1e-8, 2e-8, 1e-8, 3e-8, 1e-8, 2)
+	f.Add(5.0, 5.0, 5.0, 5.0, 5.0, 4)
+	f.Add(1e12, -1e12, 1e12, -1e12, 1e12, 0)
+
+	f.Fuzz(func(t *testing.T, a, b, c, d, e float64, period int) {
+		prices := []float64{a, b, c, d, e}
+		for _, price := range prices {
+			if math.IsNaN(price) || math.Abs(price) > 1e12 {
+				t.Skip()
+			}
+		}
+
+		got := CalculateRSI(prices, period)
+		if math.IsNaN(got) || got < 0 || got > 100 {
+			t.Fatalf("CalculateRSI(%v, %d) = %v, outside [0, 100]", prices, period, got)
+		}
+	})
+}
+
+func FuzzCalculateEMA(f *testing.F) {
+	f.Add(1.0, 2.0, 3.0, 4.0, 10)
+	f.Add(100.0, 50.0, 25.0, 12.5, 1)
+	f.Add(1.0, 1.0, 1.0, 1.0, 0)
+	f.Add(-5.0, 5.0, -5.0, 5.0, -2)
+
+	f.Fuzz(func(t *testing.T, a, b, c, d float64, period int) {
+		values := []float64{a, b, c, d}
+		low, high := math.Inf(1), math.Inf(-1)
+		for _, value := range values {
+			if math.IsNaN(value) || math.Abs(value) > 1e12 {
+				t.Skip()
+			}
+			low, high = math.Min(low, value), math.Max(high, value)
+		}
+
+		// An EMA is a weighted average, so it stays within the values' range
+		got := CalculateEMA(values, period)
+		slack := (high - low) * 1e-9
+		if math.IsNaN(got) || got < low-slack || got > high+slack {
+			t.Fatalf("CalculateEMA(%v, %d) = %v, outside [%v, %v]", values, period, got, low, high)
+		}
+	})
+}
+
+func TestCalculateVaRExtremeInputs(t *testing.T) {
+	tests := []struct {
+		name       string
+		returns    []float64
+		confidence float64
+		want       float64
+	}{
+		{"empty", nil, 0.95, 0},
+		{"single", []float64{-0.2}, 0.95, 0.2},
+		{"confidence above one", []float64{0.1, -0.3, 0.2}, 1.5, 0.3},
+		{"confidence below zero", []float64{0.1, -0.3, 0.2}, -1, 0.2},
+		{"infinite loss", []float64{0.1, math.Inf(-1), 0.2}, 0.95, math.Inf(1)},
+		{"huge values", []float64{1e300, -1e300, 0}, 0.95, 1e300},
+	}
+	for _, tt := range tests {
+		if got := CalculateVaR(tt.returns, tt.confidence); got != tt.want {
+			t.Errorf("%s: CalculateVaR = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// The input is left unsorted
+	returns := []float64{0.3, -0.1, 0.2}
+	CalculateVaR(returns, 0.95)
+	if returns[0] != 0.3 || returns[1] != -0.1 || returns[2] != 0.2 {
+		t.Errorf("CalculateVaR reordered its input: %v", returns)
+	}
+}
+
+// bubbleVaR is the exchange-sort VaR that CalculateVaR replaced
+func bubbleVaR(returns []float64, confidenceLevel float64) float64 {
+	sorted := append([]float64(nil), returns...)
+	for i := 0; i < len(sorted); i++ {
+		for j := i + 1; j < len(sorted); j++ {
+			if sorted[i] > sorted[j] {
+				sorted[i], sorted[j] = sorted[j], sorted[i]
+			}
+		}
+	}
+	index := int(float64(len(sorted)) * (1 - confidenceLevel))
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return math.Abs(sorted[index])
+}
+
+// BenchmarkCalculateVaR compares the VaR of 100 position returns (one per symbol, as in the
+// portfolio risk check) and of a year of daily returns with the former bubble sort
+func BenchmarkCalculateVaR(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{100, 365} {
+		returns := make([]float64, n)
+		for i := range returns {
+			returns[i] = rng.NormFloat64() * 0.02
+		}
+		if CalculateVaR(returns, 0.95) != bubbleVaR(returns, 0.95) {
+			b.Fatalf("CalculateVaR differs from the bubble sort for %d returns", n)
+		}
+
+		b.Run(fmt.Sprintf("sort/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				CalculateVaR(returns, 0.95)
+			}
+		})
+		b.Run(fmt.Sprintf("bubble/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bubbleVaR(returns, 0.95)
+			}
+		})
+	}
+}
+
+func FuzzCalculateVaR(f *testing.F) {
+	f.Add(0.1, -0.2, 0.05, -0.5, 0.95)
+	f.Add(0.0, 0.0, 0.0, 0.0, 0.99)
+	f.Add(1e300, -1e300, 1e-300, -1e-300, 2.0)
+	f.Add(-1.0, -1.0, -1.0, -1.0, -3.0)
+
+	f.Fuzz(func(t *testing.T, a, b, c, d, confidence float64) {
+		returns := []float64{a, b, c, d}
+		for _, value := range append(returns, confidence) {
+			if math.IsNaN(value) {
+				t.Skip()
+			}
+		}
+
+		got := CalculateVaR(returns, confidence)
+		found := false
+		for _, value := range returns {
+			found = found || got == math.Abs(value)
+		}
+		if got < 0 || !found {
+			t.Fatalf("CalculateVaR(%v, %v) = %v, not the magnitude of one of the returns", returns, confidence, got)
+		}
+	})
+}
+
+func TestCalculateMaxDrawdownExtremeInputs(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"rising", []float64{1, 2, 3}, 0},
+		{"halved", []float64{100, 50, 75}, 0.5},
+		{"starts at zero", []float64{0, 0, 1, 0.5}, 0.5},
+		{"negative peak", []float64{-2, -1, -3}, 0},
+		{"wiped out", []float64{1e300, 0}, 1},
+	}
+	for _, tt := range tests {
+		if got := CalculateMaxDrawdown(tt.values); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s: CalculateMaxDrawdown = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func FuzzCalculateMaxDrawdown(f *testing.F) {
+	f.Add(1.0, 0.5, 2.0, 1.0)
+	f.Add(0.0, -1.0, 1.0, 0.0)
+	f.Add(1e300, 1e-300, -1e300, 1e300)
+
+	f.Fuzz(func(t *testing.T, a, b, c, d float64) {
+		values := []float64{a, b, c, d}
+		for _, value := range values {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				t.Skip()
+			}
+		}
+
+		got := CalculateMaxDrawdown(values)
+		if math.IsNaN(got) || math.IsInf(got, 0) || got < 0 {
+			t.Fatalf("CalculateMaxDrawdown(%v) = %v, want a finite non-negative drawdown", values, got)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package utils
+
+import "math"
+
+// CalculateStochastic calculates the stochastic oscillator series aligned to the input candles.
+// %K is the close's position in the kPeriod high-low range smoothed over smoothK candles (1 for
+// the fast stochastic) and %D is the dPeriod average of %K. Both range from 0 to 100; entries
+// without enough history are NaN.
+func CalculateStochastic(highs, lows, closes []float64, kPeriod, smoothK, dPeriod int) (k, d []float64) {
+	n := len(closes)
+	k = nanSeries(n)
+	d = nanSeries(n)
+	if len(highs) != n || len(lows) != n || kPeriod <= 0 || smoothK <= 0 || dPeriod <= 0 {
+		return k, d
+	}
+
+	raw := nanSeries(n)
+	highest, lowest := slidingHighLow(highs, lows, kPeriod)
+	for i := kPeriod - 1; i < n; i++ {
+		high, low := highest[i], lowest[i]
+		if high > low {
+			raw[i] = (closes[i] - low) / (high - low) * 100
+		} else {
+			raw[i] = 50
+		}
+	}
+
+	k = smoothSeries(raw, smoothK)
+	d = smoothSeries(k, dPeriod)
+	return k, d
+}
+
+// CalculateWilliamsR calculates the Williams %R series aligned to the input candles. Values
+// range from -100 (close at the period low) to 0 (close at the period high); entries without
+// enough history are NaN.
+func CalculateWilliamsR(highs, lows, closes []float64, period int) []float64 {
+	n := len(closes)
+	result := nanSeries(n)
+	if len(highs) != n || len(lows) != n || period <= 0 {
+		return result
+	}
+
+	highest, lowest := slidingHighLow(highs, lows, period)
+	for i := period - 1; i < n; i++ {
+		high, low := highest[i], lowest[i]
+		if high > low {
+			result[i] = (high - closes[i]) / (high - low) * -100
+		} else {
+			result[i] = -50
+		}
+	}
+	return result
+}
+
+// smoothSeries returns the simple moving average of a series, skipping its leading NaNs
+func smoothSeries(values []float64, period int) []float64 {
+	result := nanSeries(len(values))
+	sum, count := 0.0, 0
+	for i, v := range values {
+		if math.IsNaN(v) {
+			continue
+		}
+		sum += v
+		count++
+		if count > period {
+			sum -= values[i-period]
+		}
+		if count >= period {
+			result[i] = sum / float64(period)
+		}
+	}
+	return result
+}
+
+func nanSeries(n int) []float64 {
+	series := make([]float64, n)
+	for i := range series {
+		series[i] = math.NaN()
+	}
+	return series
+}
@@ -0,0 +1,66 @@
+package utils
+
+// MovingAverageSeries returns the simple moving average at every index in one pass: entry i
+// equals CalculateMovingAverage(values[:i+1], period), 0 until period values are available
+func MovingAverageSeries(values []float64, period int) []float64 {
+	result := make([]float64, len(values))
+	if period <= 0 {
+		return result
+	}
+
+	sum := 0.0
+	for i, value := range values {
+		sum += value
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i+1 >= period {
+			result[i] = sum / float64(period)
+		}
+	}
+	return result
+}
+
+// RSISeries returns the RSI at every index in one pass: entry i equals
+// CalculateRSI(prices[:i+1], period), 50 until period price changes are available
+func RSISeries(prices []float64, period int) []float64 {
+	result := make([]float64, len(prices))
+	for i := range result {
+		result[i] = 50
+	}
+	if period < 1 {
+		return result
+	}
+
+	// Rolling sums over the last period changes; losses counts the falling ones so a window
+	// of gains only reports exactly 100 despite float residue
+	gainSum, lossSum, losses := 0.0, 0.0, 0
+	change := func(i int) float64 { return prices[i] - prices[i-1] }
+	for i := 1; i < len(prices); i++ {
+		if c := change(i); c > 0 {
+			gainSum += c
+		} else if c < 0 {
+			lossSum -= c
+			losses++
+		}
+		if i > period {
+			if c := change(i - period); c > 0 {
+				gainSum -= c
+			} else if c < 0 {
+				lossSum += c
+				losses--
+			}
+		}
+		if i < period {
+			continue
+		}
+
+		if losses == 0 {
+			result[i] = 100
+			continue
+		}
+		rs := Max(0, gainSum) / lossSum
+		result[i] = 100 - (100 / (1 + rs))
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"math"
+	"time"
+)
+
+// VWAP session anchors
+const (
+	VWAPAnchorDay     = "day"     // Resets at 00:00 UTC
+	VWAPAnchorFunding = "funding" // Resets at every 8-hour funding time (00:00, 08:00, 16:00 UTC)
+)
+
+// VWAPSessionStart returns the start of the session containing t for the given anchor
+func VWAPSessionStart(t time.Time, anchor string) time.Time {
+	day := t.UTC().Truncate(24 * time.Hour)
+	if anchor != VWAPAnchorFunding {
+		return day
+	}
+	return day.Add(time.Duration(t.UTC().Hour()/8) * 8 * time.Hour)
+}
+
+// CalculateVWAP calculates the volume-weighted average price and the volume-weighted
+// standard deviation of prices around it
+func CalculateVWAP(prices, volumes []float64) (vwap, stdDev float64) {
+	if len(prices) == 0 || len(prices) != len(volumes) {
+		return 0, 0
+	}
+
+	v := &VWAP{}
+	for i := range prices {
+		v.add(prices[i], volumes[i])
+	}
+	return v.Value(), v.StdDev()
+}
+
+// TypicalPrice returns the (high + low + close) / 3 price of a candle
+func TypicalPrice(high, low, close float64) float64 {
+	return (high + low + close) / 3
+}
+
+// VWAP accumulates a session VWAP incrementally, resetting when a sample falls in a new session
+type VWAP struct {
+	anchor       string
+	sessionStart time.Time
+	sumVolume    float64
+	sumPV        float64 // Sum of price * volume
+	sumP2V       float64 // Sum of price^2 * volume
+	samples      int
+}
+
+// NewVWAP creates a session VWAP for the given anchor (VWAPAnchorDay or VWAPAnchorFunding)
+func NewVWAP(anchor string) *VWAP {
+	return &VWAP{anchor: anchor}
+}
+
+// Update adds a sample taken at t, starting a new session when t is past the current one
+func (v *VWAP) Update(t time.Time, price, volume float64) {
+	if start := VWAPSessionStart(t, v.anchor); !start.Equal(v.sessionStart) {
+		*v = VWAP{anchor: v.anchor, sessionStart: start}
+	}
+	v.add(price, volume)
+}
+
+func (v *VWAP) add(price, volume float64) {
+	if volume <= 0 {
+		return
+	}
+	v.sumVolume += volume
+	v.sumPV += price * volume
+	v.sumP2V += price * price * volume
+	v.samples++
+}
+
+// Value returns the session VWAP, or 0 before any volume was traded
+func (v *VWAP) Value() float64 {
+	if v.sumVolume == 0 {
+		return 0
+	}
+	return v.sumPV / v.sumVolume
+}
+
+// StdDev returns the volume-weighted standard deviation of prices around the VWAP
+func (v *VWAP) StdDev() float64 {
+	if v.sumVolume == 0 {
+		return 0
+	}
+	mean := v.sumPV / v.sumVolume
+	return math.Sqrt(math.Max(0, v.sumP2V/v.sumVolume-mean*mean))
+}
+
+// Samples returns the number of samples with volume in the current session
+func (v *VWAP) Samples() int {
+	return v.samples
+}
+
+// SessionStart returns the start of the current session
+func (v *VWAP) SessionStart() time.Time {
+	return v.sessionStart
+}
@@ -0,0 +1,42 @@
+package utils
+
+// slidingHighLow returns the highest high and lowest low over the period ending at each index,
+// for indexes with a full period of history (earlier entries are 0). Monotonic deques keep it
+// O(n) whatever the period.
+func slidingHighLow(highs, lows []float64, period int) (high, low []float64) {
+	n := len(highs)
+	high = make([]float64, n)
+	low = make([]float64, n)
+	if period <= 0 || len(lows) != n {
+		return high, low
+	}
+
+	// Indexes whose values are still candidates, best first from the head
+	maxQueue := make([]int, 0, n)
+	minQueue := make([]int, 0, n)
+	maxHead, minHead := 0, 0
+	for i := 0; i < n; i++ {
+		for len(maxQueue) > maxHead && highs[maxQueue[len(maxQueue)-1]] <= highs[i] {
+			maxQueue = maxQueue[:len(maxQueue)-1]
+		}
+		maxQueue = append(maxQueue, i)
+		for len(minQueue) > minHead && lows[minQueue[len(minQueue)-1]] >= lows[i] {
+			minQueue = minQueue[:len(minQueue)-1]
+		}
+		minQueue = append(minQueue, i)
+
+		// Drop the index that left the window
+		if maxQueue[maxHead] <= i-period {
+			maxHead++
+		}
+		if minQueue[minHead] <= i-period {
+			minHead++
+		}
+
+		if i+1 >= period {
+			high[i] = highs[maxQueue[maxHead]]
+			low[i] = lows[minQueue[minHead]]
+		}
+	}
+	return high, low
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// rescanHighLow is the per-index window scan slidingHighLow replaces
+func rescanHighLow(highs, lows []float64, i, period int) (float64, float64) {
+	high, low := highs[i], lows[i]
+	for j := i - period + 1; j < i; j++ {
+		high = math.Max(high, highs[j])
+		low = math.Min(low, lows[j])
+	}
+	return high, low
+}
+
+// randomCandles returns the highs, lows and closes of n random candles around 100
+func randomCandles(rng *rand.Rand, n int) (highs, lows, closes []float64) {
+	price := 100.0
+	for i := 0; i < n; i++ {
+		price *= 1 + rng.NormFloat64()*0.01
+		spread := math.Abs(rng.NormFloat64()) * price * 0.005
+		// Round so equal highs and lows occur
+		highs = append(highs, math.Round((price+spread)*10)/10)
+		lows = append(lows, math.Round((price-spread)*10)/10)
+		closes = append(closes, price)
+	}
+	return highs, lows, closes
+}
+
+func TestSlidingHighLowMatchesRescan(t *testing.T) {
+	highs, lows, _ := randomCandles(rand.New(rand.NewSource(1)), 2000)
+	for _, period := range []int{1, 2, 9, 26, 52, 2000} {
+		high, low := slidingHighLow(highs, lows, period)
+		for i := range highs {
+			wantHigh, wantLow := 0.0, 0.0
+			if i+1 >= period {
+				wantHigh, wantLow = rescanHighLow(highs, lows, i, period)
+			}
+			if high[i] != wantHigh || low[i] != wantLow {
+				t.Fatalf("period %d, index %d: got %v/%v, want %v/%v", period, i, high[i], low[i], wantHigh, wantLow)
+			}
+		}
+	}
+
+	if high, _ := slidingHighLow(highs, lows, 0); high[len(high)-1] != 0 {
+		t.Fatalf("period 0 produced values")
+	}
+}
+
+func BenchmarkSlidingHighLow(b *testing.B) {
+	highs, lows, _ := randomCandles(rand.New(rand.NewSource(2)), 500)
+	const period = 52
+
+	b.Run("deque", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			slidingHighLow(highs, lows, period)
+		}
+	})
+
+	b.Run("rescan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := period - 1; j < len(highs); j++ {
+				rescanHighLow(highs, lows, j, period)
+			}
+		}
+	})
+}
+
+// One op is an Ichimoku recalculation over a 500-candle window for 100 symbols, as the
+// Ichimoku strategy does each cycle
+func BenchmarkCalculateIchimoku(b *testing.B) {
+	rng := rand.New(rand.NewSource(3))
+	type candles struct{ highs, lows, closes []float64 }
+	symbols := make([]candles, 100)
+	for i := range symbols {
+		symbols[i].highs, symbols[i].lows, symbols[i].closes = randomCandles(rng, 500)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, c := range symbols {
+			CalculateIchimoku(c.highs, c.lows, c.closes, 9, 26, 52, 26)
+		}
+	}
+}